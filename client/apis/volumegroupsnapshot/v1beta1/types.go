@@ -109,6 +109,27 @@ type VolumeGroupSnapshotStatus struct {
 	// group snapshot creation. Upon success, this error field will be cleared.
 	// +optional
 	Error *snapshotv1.VolumeSnapshotError `json:"error,omitempty" protobuf:"bytes,4,opt,name=error,casttype=VolumeSnapshotError"`
+
+	// Emulated is true if the group snapshot was not taken by the CSI driver as
+	// a single operation, but emulated by the csi-snapshotter sidecar with one
+	// snapshot per volume, all cut within a bounded time window. Such a group
+	// snapshot is crash consistent only if the workload tolerates writes
+	// landing within that window.
+	// This field is updated based on the Emulated field in VolumeGroupSnapshotContentStatus
+	// +optional
+	Emulated *bool `json:"emulated,omitempty" protobuf:"varint,5,opt,name=emulated"`
+
+	// Conditions are the latest observations of the state of the group
+	// snapshot. The snapshot controller maintains the Bound, Created and
+	// Ready conditions, and the DeletionBlocked condition while the deletion
+	// of the group snapshot waits for other objects. The condition types and
+	// reasons are those of VolumeSnapshots.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,6,rep,name=conditions"`
 }
 
 //+genclient
@@ -123,6 +144,7 @@ type VolumeGroupSnapshotStatus struct {
 // +kubebuilder:printcolumn:name="VolumeGroupSnapshotClass",type=string,JSONPath=`.spec.volumeGroupSnapshotClassName`,description="The name of the VolumeGroupSnapshotClass requested by the VolumeGroupSnapshot."
 // +kubebuilder:printcolumn:name="VolumeGroupSnapshotContent",type=string,JSONPath=`.status.boundVolumeGroupSnapshotContentName`,description="Name of the VolumeGroupSnapshotContent object to which the VolumeGroupSnapshot object intends to bind to. Please note that verification of binding actually requires checking both VolumeGroupSnapshot and VolumeGroupSnapshotContent to ensure both are pointing at each other. Binding MUST be verified prior to usage of this object."
 // +kubebuilder:printcolumn:name="CreationTime",type=date,JSONPath=`.status.creationTime`,description="Timestamp when the point-in-time group snapshot was taken by the underlying storage system."
+// +kubebuilder:printcolumn:name="Emulated",type=boolean,JSONPath=`.status.emulated`,description="Indicates if the group snapshot was emulated with individual snapshots.",priority=1
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 type VolumeGroupSnapshot struct {
 	metav1.TypeMeta `json:",inline"`
@@ -225,6 +247,7 @@ type VolumeGroupSnapshotClassList struct {
 // +kubebuilder:printcolumn:name="VolumeGroupSnapshotClass",type=string,JSONPath=`.spec.volumeGroupSnapshotClassName`,description="Name of the VolumeGroupSnapshotClass from which this group snapshot was (or will be) created."
 // +kubebuilder:printcolumn:name="VolumeGroupSnapshotNamespace",type=string,JSONPath=`.spec.volumeGroupSnapshotRef.namespace`,description="Namespace of the VolumeGroupSnapshot object to which this VolumeGroupSnapshotContent object is bound."
 // +kubebuilder:printcolumn:name="VolumeGroupSnapshot",type=string,JSONPath=`.spec.volumeGroupSnapshotRef.name`,description="Name of the VolumeGroupSnapshot object to which this VolumeGroupSnapshotContent object is bound."
+// +kubebuilder:printcolumn:name="Emulated",type=boolean,JSONPath=`.status.emulated`,description="Indicates if the group snapshot was emulated with individual snapshots.",priority=1
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 type VolumeGroupSnapshotContent struct {
 	metav1.TypeMeta `json:",inline"`
@@ -345,6 +368,24 @@ type VolumeGroupSnapshotContentStatus struct {
 	// on the storage system.
 	// +optional
 	VolumeSnapshotHandlePairList []VolumeSnapshotHandlePair `json:"volumeSnapshotHandlePairList,omitempty" protobuf:"bytes,6,opt,name=volumeSnapshotHandlePairList"`
+
+	// Emulated is true if the group snapshot was emulated by the csi-snapshotter
+	// sidecar with one CreateSnapshot call per volume, issued in parallel within
+	// a bounded time window, because the CSI driver does not support the
+	// GroupController service.
+	// This field is the source for the Emulated field in VolumeGroupSnapshotStatus
+	// +optional
+	Emulated *bool `json:"emulated,omitempty" protobuf:"varint,7,opt,name=emulated"`
+
+	// Conditions are the latest observations of the state of the group
+	// snapshot on the storage system. The CSI snapshotter sidecar maintains
+	// the Created and Ready conditions.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,8,rep,name=conditions"`
 }
 
 // VolumeGroupSnapshotContentSource represents the CSI source of a group snapshot.
@@ -194,6 +194,82 @@ type VolumeSnapshotStatus struct {
 	// VolumeSnapshot is a part of.
 	// +optional
 	VolumeGroupSnapshotName *string `json:"volumeGroupSnapshotName,omitempty" protobuf:"bytes,6,opt,name=volumeGroupSnapshotName"`
+
+	// hooks are the results of the commands of the VolumeSnapshotHook that
+	// the snapshot controller ran in the pods using the source
+	// PersistentVolumeClaim before and after the snapshot was taken.
+	// +optional
+	// +listType=atomic
+	Hooks []VolumeSnapshotHookResult `json:"hooks,omitempty" protobuf:"bytes,7,rep,name=hooks"`
+
+	// conditions are the latest observations of the state of the snapshot.
+	// The snapshot controller maintains the Bound, Created and Ready
+	// conditions, and the DeletionBlocked condition while the deletion of the
+	// snapshot waits for other objects. The observedGeneration of the
+	// conditions is the generation of the VolumeSnapshot they were computed
+	// for.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,8,rep,name=conditions"`
+}
+
+// VolumeSnapshotHookPhase is the phase of a snapshot in which a hook runs.
+type VolumeSnapshotHookPhase string
+
+const (
+	// VolumeSnapshotHookPhasePre hooks run before the snapshot is taken.
+	VolumeSnapshotHookPhasePre VolumeSnapshotHookPhase = "Pre"
+	// VolumeSnapshotHookPhasePost hooks run after the snapshot is taken.
+	VolumeSnapshotHookPhasePost VolumeSnapshotHookPhase = "Post"
+)
+
+// VolumeSnapshotHookOutcome is the outcome of a hook command.
+type VolumeSnapshotHookOutcome string
+
+const (
+	// VolumeSnapshotHookSucceeded means that the command exited with status 0.
+	VolumeSnapshotHookSucceeded VolumeSnapshotHookOutcome = "Succeeded"
+	// VolumeSnapshotHookFailed means that the command could not be run or
+	// exited with a non-zero status.
+	VolumeSnapshotHookFailed VolumeSnapshotHookOutcome = "Failed"
+	// VolumeSnapshotHookTimedOut means that the command did not finish within
+	// its timeout.
+	VolumeSnapshotHookTimedOut VolumeSnapshotHookOutcome = "TimedOut"
+)
+
+// VolumeSnapshotHookResult describes the result of a hook command in one pod.
+type VolumeSnapshotHookResult struct {
+	// phase is the phase of the snapshot in which the command ran, "Pre" or
+	// "Post".
+	Phase VolumeSnapshotHookPhase `json:"phase" protobuf:"bytes,1,opt,name=phase,casttype=VolumeSnapshotHookPhase"`
+
+	// pod is the name of the pod in which the command ran.
+	Pod string `json:"pod" protobuf:"bytes,2,opt,name=pod"`
+
+	// container is the name of the container in which the command ran.
+	Container string `json:"container" protobuf:"bytes,3,opt,name=container"`
+
+	// outcome is "Succeeded", "Failed" or "TimedOut".
+	Outcome VolumeSnapshotHookOutcome `json:"outcome" protobuf:"bytes,4,opt,name=outcome,casttype=VolumeSnapshotHookOutcome"`
+
+	// startTime is the time at which the command was started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty" protobuf:"bytes,5,opt,name=startTime"`
+
+	// completionTime is the time at which the command finished or was
+	// aborted.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty" protobuf:"bytes,6,opt,name=completionTime"`
+
+	// message is the error of a failed command, including the end of its
+	// output.
+	// NOTE: message may be logged, and it should not contain sensitive
+	// information.
+	// +optional
+	Message *string `json:"message,omitempty" protobuf:"bytes,7,opt,name=message"`
 }
 
 // +genclient
@@ -440,6 +516,17 @@ type VolumeSnapshotContentStatus struct {
 	// on the underlying storage system.
 	// +optional
 	VolumeGroupSnapshotHandle *string `json:"volumeGroupSnapshotHandle,omitempty" protobuf:"bytes,6,opt,name=volumeGroupSnapshotHandle"`
+
+	// conditions are the latest observations of the state of the snapshot on
+	// the storage system. The CSI snapshotter sidecar maintains the Created
+	// and Ready conditions. The observedGeneration of the conditions is the
+	// generation of the VolumeSnapshotContent they were computed for.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,7,rep,name=conditions"`
 }
 
 // DeletionPolicy describes a policy for end-of-life maintenance of volume snapshot contents
@@ -468,4 +555,105 @@ type VolumeSnapshotError struct {
 	// information.
 	// +optional
 	Message *string `json:"message,omitempty" protobuf:"bytes,2,opt,name=message"`
+
+	// reason is a machine-readable, CamelCase identifier of the cause of the
+	// error. Errors returned by the CSI driver have the reason CSIDriverError,
+	// failed requests to the Kubernetes API server APIServerError, missing or
+	// unreadable secrets SecretError and missing or invalid classes
+	// ClassError. Other errors have the reason of the event reported along
+	// with them, e.g. SnapshotContentMissing.
+	// +optional
+	Reason *string `json:"reason,omitempty" protobuf:"bytes,3,opt,name=reason"`
+
+	// code is the gRPC status code returned by the CSI driver, e.g.
+	// ResourceExhausted, if reason is CSIDriverError.
+	// +optional
+	Code *string `json:"code,omitempty" protobuf:"bytes,4,opt,name=code"`
+
+	// final indicates that the operation failed for sure and is not in
+	// progress on the storage system, because the CSI driver returned an error
+	// with a final gRPC status code. Errors that are not final, e.g. timeouts,
+	// may leave the operation in progress.
+	// +optional
+	Final *bool `json:"final,omitempty" protobuf:"varint,5,opt,name=final"`
 }
+
+// Types of the conditions of (group) snapshots and their contents.
+const (
+	// VolumeSnapshotConditionBound means that the snapshot is bound to its
+	// content.
+	VolumeSnapshotConditionBound = "Bound"
+
+	// VolumeSnapshotConditionCreated means that the snapshot was cut on the
+	// storage system.
+	VolumeSnapshotConditionCreated = "Created"
+
+	// VolumeSnapshotConditionReady means that the snapshot is ready to be
+	// used to restore a volume.
+	VolumeSnapshotConditionReady = "Ready"
+
+	// VolumeSnapshotConditionDeletionBlocked means that the deletion of the
+	// snapshot waits for other objects, e.g. a volume being restored from it.
+	VolumeSnapshotConditionDeletionBlocked = "DeletionBlocked"
+)
+
+// Reasons of the conditions of (group) snapshots and their contents. The
+// conditions that are False because of an error have the reason of the error.
+const (
+	// VolumeSnapshotReasonContentBound means that the snapshot is bound to
+	// its content.
+	VolumeSnapshotReasonContentBound = "ContentBound"
+
+	// VolumeSnapshotReasonWaitingForContent means that the content of the
+	// snapshot was not created or bound yet.
+	VolumeSnapshotReasonWaitingForContent = "WaitingForContent"
+
+	// VolumeSnapshotReasonCreating means that the snapshot is being cut by
+	// the CSI driver.
+	VolumeSnapshotReasonCreating = "SnapshotCreating"
+
+	// VolumeSnapshotReasonCreated means that the snapshot was cut.
+	VolumeSnapshotReasonCreated = "SnapshotCreated"
+
+	// VolumeSnapshotReasonProcessing means that the snapshot was cut, but is
+	// still being processed by the storage system, e.g. uploaded.
+	VolumeSnapshotReasonProcessing = "SnapshotProcessing"
+
+	// VolumeSnapshotReasonReady means that the snapshot is ready to use.
+	VolumeSnapshotReasonReady = "SnapshotReady"
+
+	// VolumeSnapshotReasonDeleted means that the snapshot was deleted from
+	// the storage system.
+	VolumeSnapshotReasonDeleted = "SnapshotDeleted"
+
+	// VolumeSnapshotReasonError is the reason of the conditions that are
+	// False because of an error without a reason.
+	VolumeSnapshotReasonError = "Error"
+
+	// VolumeSnapshotReasonUsedAsSource means that the deletion of the
+	// snapshot waits for a volume being restored from it.
+	VolumeSnapshotReasonUsedAsSource = "SnapshotUsedAsSource"
+
+	// VolumeSnapshotReasonInGroup means that the snapshot can only be deleted
+	// along with its group snapshot.
+	VolumeSnapshotReasonInGroup = "SnapshotInGroup"
+)
+
+// Reasons of a VolumeSnapshotError.
+const (
+	// VolumeSnapshotErrorReasonCSIDriverError means that the CSI driver
+	// returned an error, whose gRPC status code is in the error.
+	VolumeSnapshotErrorReasonCSIDriverError = "CSIDriverError"
+
+	// VolumeSnapshotErrorReasonAPIServerError means that a request to the
+	// Kubernetes API server failed.
+	VolumeSnapshotErrorReasonAPIServerError = "APIServerError"
+
+	// VolumeSnapshotErrorReasonSecretError means that a secret passed to the
+	// CSI driver is missing or cannot be read.
+	VolumeSnapshotErrorReasonSecretError = "SecretError"
+
+	// VolumeSnapshotErrorReasonClassError means that the class of the object
+	// is missing or invalid.
+	VolumeSnapshotErrorReasonClassError = "ClassError"
+)
@@ -0,0 +1,584 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// +kubebuilder:object:generate=true
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VolumeSnapshotSchedule is a user's request to periodically create
+// VolumeSnapshots of one or more PersistentVolumeClaims in its namespace.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Namespaced,shortName=vss
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Schedule",type=string,JSONPath=`.spec.schedule`,description="The cron expression that determines when snapshots are taken."
+// +kubebuilder:printcolumn:name="SnapshotClass",type=string,JSONPath=`.spec.volumeSnapshotClassName`,description="The name of the VolumeSnapshotClass used for the created VolumeSnapshots."
+// +kubebuilder:printcolumn:name="Suspend",type=boolean,JSONPath=`.spec.suspend`,description="Indicates if the schedule is suspended."
+// +kubebuilder:printcolumn:name="LastSchedule",type=date,JSONPath=`.status.lastScheduleTime`,description="The last time a run of this schedule was processed."
+// +kubebuilder:printcolumn:name="LastSuccessfulSnapshot",type=string,JSONPath=`.status.lastSuccessfulSnapshot`,description="The name of the most recent VolumeSnapshot created by this schedule that is ready to use."
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type VolumeSnapshotSchedule struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// spec defines when and from which PersistentVolumeClaims VolumeSnapshots
+	// are created.
+	// Required.
+	Spec VolumeSnapshotScheduleSpec `json:"spec" protobuf:"bytes,2,opt,name=spec"`
+
+	// status represents the most recently observed state of the schedule.
+	// +optional
+	Status *VolumeSnapshotScheduleStatus `json:"status,omitempty" protobuf:"bytes,3,opt,name=status"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VolumeSnapshotScheduleList is a list of VolumeSnapshotSchedule objects
+// +kubebuilder:object:root=true
+type VolumeSnapshotScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// List of VolumeSnapshotSchedules
+	Items []VolumeSnapshotSchedule `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
+
+// VolumeSnapshotScheduleSpec describes the common attributes of a volume
+// snapshot schedule.
+type VolumeSnapshotScheduleSpec struct {
+	// schedule is a cron expression in the standard five field format
+	// (minute, hour, day of month, month, day of week) that determines when
+	// VolumeSnapshots are created. The predefined "@hourly", "@daily",
+	// "@weekly", "@monthly" and "@yearly" expressions are also accepted.
+	// All times are interpreted in UTC.
+	// Required.
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule" protobuf:"bytes,1,opt,name=schedule"`
+
+	// source specifies the PersistentVolumeClaims that are snapshotted on
+	// every run of the schedule.
+	// Required.
+	Source VolumeSnapshotScheduleSource `json:"source" protobuf:"bytes,2,opt,name=source"`
+
+	// volumeSnapshotClassName is the name of the VolumeSnapshotClass set on
+	// the created VolumeSnapshots. If unset, the default VolumeSnapshotClass
+	// of the CSI driver of each PersistentVolumeClaim is used.
+	// Empty string is not allowed for this field.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="size(self) > 0",message="volumeSnapshotClassName must not be the empty string when set"
+	VolumeSnapshotClassName *string `json:"volumeSnapshotClassName,omitempty" protobuf:"bytes,3,opt,name=volumeSnapshotClassName"`
+
+	// snapshotNameTemplate is used to generate the names of the created
+	// VolumeSnapshots. The following tokens are substituted:
+	// ${schedule.name} - the name of the VolumeSnapshotSchedule,
+	// ${pvc.name} - the name of the source PersistentVolumeClaim,
+	// ${timestamp} - the scheduled time of the run in the UTC "20060102150405" format.
+	// The template must include ${pvc.name} if the source is a selector, and
+	// must always include ${timestamp}, so that every run of the schedule
+	// produces unique names.
+	// Defaults to "${schedule.name}-${pvc.name}-${timestamp}".
+	// +optional
+	SnapshotNameTemplate *string `json:"snapshotNameTemplate,omitempty" protobuf:"bytes,4,opt,name=snapshotNameTemplate"`
+
+	// snapshotLabels are added to every VolumeSnapshot created by this schedule
+	// in addition to the label that identifies the schedule.
+	// +optional
+	SnapshotLabels map[string]string `json:"snapshotLabels,omitempty" protobuf:"bytes,5,rep,name=snapshotLabels"`
+
+	// startingDeadlineSeconds is the deadline in seconds for starting a run
+	// that was missed, for example because the snapshot controller was not
+	// running at the scheduled time. When several runs were missed, only
+	// the most recent one is started, and only if it is not older than the
+	// deadline. If unset, the most recent missed run is always started.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	StartingDeadlineSeconds *int64 `json:"startingDeadlineSeconds,omitempty" protobuf:"varint,6,opt,name=startingDeadlineSeconds"`
+
+	// suspend tells the controller to stop creating VolumeSnapshots for this
+	// schedule. Runs that are due while the schedule is suspended are treated
+	// as missed runs once it is resumed.
+	// Defaults to false.
+	// +optional
+	Suspend *bool `json:"suspend,omitempty" protobuf:"varint,7,opt,name=suspend"`
+}
+
+// VolumeSnapshotScheduleSource specifies the PersistentVolumeClaims a
+// schedule takes snapshots of.
+// Exactly one of its members must be set.
+// +kubebuilder:validation:XValidation:rule="(has(self.persistentVolumeClaimName) && !has(self.selector)) || (!has(self.persistentVolumeClaimName) && has(self.selector))", message="exactly one of persistentVolumeClaimName and selector must be set"
+type VolumeSnapshotScheduleSource struct {
+	// persistentVolumeClaimName specifies the name of a single
+	// PersistentVolumeClaim in the same namespace as the schedule.
+	// +optional
+	PersistentVolumeClaimName *string `json:"persistentVolumeClaimName,omitempty" protobuf:"bytes,1,opt,name=persistentVolumeClaimName"`
+
+	// selector is a label query over PersistentVolumeClaims in the same
+	// namespace as the schedule. It is evaluated on every run.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty" protobuf:"bytes,2,opt,name=selector"`
+}
+
+// VolumeSnapshotScheduleStatus is the status of a VolumeSnapshotSchedule.
+type VolumeSnapshotScheduleStatus struct {
+	// lastScheduleTime is the scheduled time of the last run that was
+	// processed, either because its VolumeSnapshots were created or because
+	// it was skipped after missing its starting deadline.
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty" protobuf:"bytes,1,opt,name=lastScheduleTime"`
+
+	// lastSuccessfulSnapshot is the name of the most recently created
+	// VolumeSnapshot of this schedule that is ready to use.
+	// +optional
+	LastSuccessfulSnapshot *string `json:"lastSuccessfulSnapshot,omitempty" protobuf:"bytes,2,opt,name=lastSuccessfulSnapshot"`
+
+	// error is the last observed error while running the schedule, if any.
+	// It is cleared once a run completes successfully.
+	// +optional
+	Error *snapshotv1.VolumeSnapshotError `json:"error,omitempty" protobuf:"bytes,3,opt,name=error,casttype=VolumeSnapshotError"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VolumeSnapshotRetentionPolicy makes the snapshot controller delete old
+// VolumeSnapshots in its namespace. The policy is applied separately to the
+// snapshots of every source PersistentVolumeClaim.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Namespaced,shortName=vsrp
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="KeepLast",type=integer,JSONPath=`.spec.keepLast`,description="The number of most recent snapshots that are kept."
+// +kubebuilder:printcolumn:name="KeepWithin",type=string,JSONPath=`.spec.keepWithin`,description="Snapshots newer than this duration are kept."
+// +kubebuilder:printcolumn:name="LastPrune",type=date,JSONPath=`.status.lastPruneTime`,description="The last time snapshots were deleted by this policy."
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type VolumeSnapshotRetentionPolicy struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// spec defines which VolumeSnapshots the policy applies to and which of
+	// them are kept.
+	// Required.
+	Spec VolumeSnapshotRetentionPolicySpec `json:"spec" protobuf:"bytes,2,opt,name=spec"`
+
+	// status represents the most recently observed state of the policy.
+	// +optional
+	Status *VolumeSnapshotRetentionPolicyStatus `json:"status,omitempty" protobuf:"bytes,3,opt,name=status"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VolumeSnapshotRetentionPolicyList is a list of VolumeSnapshotRetentionPolicy objects
+// +kubebuilder:object:root=true
+type VolumeSnapshotRetentionPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// List of VolumeSnapshotRetentionPolicies
+	Items []VolumeSnapshotRetentionPolicy `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
+
+// VolumeSnapshotRetentionPolicySpec describes the common attributes of a
+// volume snapshot retention policy.
+//
+// The selected VolumeSnapshots are grouped by their source
+// PersistentVolumeClaim. Within each group only snapshots that are ready to
+// use are taken into account. A snapshot is kept if at least one of the
+// keep rules keeps it, all other ready snapshots of the group are deleted.
+// Snapshots that are not ready to use, that are part of a
+// VolumeGroupSnapshot, that are being used as the data source of a
+// PersistentVolumeClaim that is being provisioned, and pre-provisioned
+// snapshots without a source PersistentVolumeClaim are never deleted.
+// If several policies select the same snapshot, each of them may delete it.
+// +kubebuilder:validation:XValidation:rule="has(self.keepLast) || has(self.keepWithin) || has(self.keepHourly) || has(self.keepDaily) || has(self.keepWeekly)", message="at least one of keepLast, keepWithin, keepHourly, keepDaily and keepWeekly must be set"
+// +kubebuilder:validation:XValidation:rule="(has(self.selector.matchLabels) && size(self.selector.matchLabels) > 0) || (has(self.selector.matchExpressions) && size(self.selector.matchExpressions) > 0)", message="selector must not be empty"
+type VolumeSnapshotRetentionPolicySpec struct {
+	// selector is a label query over VolumeSnapshots in the same namespace
+	// as the policy. An empty selector, which would select all the
+	// snapshots of the namespace, is rejected.
+	// Required.
+	Selector metav1.LabelSelector `json:"selector" protobuf:"bytes,1,opt,name=selector"`
+
+	// keepLast is the number of most recent snapshots that are kept.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	KeepLast *int32 `json:"keepLast,omitempty" protobuf:"varint,2,opt,name=keepLast"`
+
+	// keepWithin keeps all snapshots that were taken within this duration
+	// before now, e.g. "72h".
+	// +optional
+	KeepWithin *metav1.Duration `json:"keepWithin,omitempty" protobuf:"bytes,3,opt,name=keepWithin"`
+
+	// keepHourly is the number of hours for which the most recent snapshot
+	// of the hour is kept. Hours without snapshots are not counted.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	KeepHourly *int32 `json:"keepHourly,omitempty" protobuf:"varint,4,opt,name=keepHourly"`
+
+	// keepDaily is the number of days for which the most recent snapshot
+	// of the day is kept. Days without snapshots are not counted.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	KeepDaily *int32 `json:"keepDaily,omitempty" protobuf:"varint,5,opt,name=keepDaily"`
+
+	// keepWeekly is the number of ISO 8601 weeks for which the most recent
+	// snapshot of the week is kept. Weeks without snapshots are not counted.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	KeepWeekly *int32 `json:"keepWeekly,omitempty" protobuf:"varint,6,opt,name=keepWeekly"`
+}
+
+// VolumeSnapshotRetentionPolicyStatus is the status of a
+// VolumeSnapshotRetentionPolicy.
+type VolumeSnapshotRetentionPolicyStatus struct {
+	// lastPruneTime is the last time VolumeSnapshots were deleted by this
+	// policy.
+	// +optional
+	LastPruneTime *metav1.Time `json:"lastPruneTime,omitempty" protobuf:"bytes,1,opt,name=lastPruneTime"`
+
+	// lastPrunedCount is the number of VolumeSnapshots that were deleted at
+	// lastPruneTime.
+	// +optional
+	LastPrunedCount *int32 `json:"lastPrunedCount,omitempty" protobuf:"varint,2,opt,name=lastPrunedCount"`
+
+	// error is the last observed error while applying the policy, if any.
+	// It is cleared once the policy is applied successfully.
+	// +optional
+	Error *snapshotv1.VolumeSnapshotError `json:"error,omitempty" protobuf:"bytes,3,opt,name=error,casttype=VolumeSnapshotError"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SnapshotMetadataService advertises the endpoint of the snapshot metadata
+// service of a CSI driver, which returns the allocated and changed blocks of
+// VolumeSnapshots. The object has the name of the CSI driver and is created
+// by the csi-snapshot-metadata sidecar.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName=sms
+// +kubebuilder:printcolumn:name="Address",type=string,JSONPath=`.spec.address`,description="The address of the snapshot metadata service."
+// +kubebuilder:printcolumn:name="Audience",type=string,JSONPath=`.spec.audience`,description="The audience of the tokens accepted by the service."
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type SnapshotMetadataService struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// spec describes how to connect to the snapshot metadata service.
+	// Required.
+	Spec SnapshotMetadataServiceSpec `json:"spec" protobuf:"bytes,2,opt,name=spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SnapshotMetadataServiceList is a list of SnapshotMetadataService objects
+// +kubebuilder:object:root=true
+type SnapshotMetadataServiceList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// List of SnapshotMetadataServices
+	Items []SnapshotMetadataService `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
+
+// SnapshotMetadataServiceSpec describes the endpoint of a snapshot metadata
+// service.
+type SnapshotMetadataServiceSpec struct {
+	// address is the TCP address (host:port) of the gRPC service, usually
+	// the DNS name of a Kubernetes Service.
+	// Required.
+	// +kubebuilder:validation:MinLength=1
+	Address string `json:"address" protobuf:"bytes,1,opt,name=address"`
+
+	// caCert is the PEM encoded CA certificate that signed the TLS
+	// certificate of the service.
+	// Required.
+	// +kubebuilder:validation:MinLength=1
+	CACert []byte `json:"caCert" protobuf:"bytes,2,opt,name=caCert"`
+
+	// audience is the audience that the service account tokens sent by
+	// clients must be issued for.
+	// Required.
+	// +kubebuilder:validation:MinLength=1
+	Audience string `json:"audience" protobuf:"bytes,3,opt,name=audience"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VolumeSnapshotHook describes commands that the snapshot controller runs in
+// the pods using a PersistentVolumeClaim before and after a VolumeSnapshot of
+// the claim is taken, e.g. to freeze and thaw a file system or to put a
+// database into backup mode. A VolumeSnapshot uses the hook named by the
+// snapshot.storage.kubernetes.io/hook annotation of the snapshot or, if not
+// set, of its VolumeSnapshotClass. Hooks are cluster scoped: the commands run
+// with the permissions of the snapshot controller, so only cluster
+// administrators should be allowed to create them.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName=vsh
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type VolumeSnapshotHook struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// spec defines the commands of the hook.
+	// Required.
+	Spec VolumeSnapshotHookSpec `json:"spec" protobuf:"bytes,2,opt,name=spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VolumeSnapshotHookList is a list of VolumeSnapshotHook objects
+// +kubebuilder:object:root=true
+type VolumeSnapshotHookList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// List of VolumeSnapshotHooks
+	Items []VolumeSnapshotHook `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
+
+// VolumeSnapshotHookSpec describes the commands run before and after a
+// snapshot is taken.
+// +kubebuilder:validation:XValidation:rule="has(self.pre) || has(self.post)",message="at least one of pre and post must be set"
+type VolumeSnapshotHookSpec struct {
+	// pre is run in every running pod using the source
+	// PersistentVolumeClaim before the snapshot is taken. If it fails or
+	// times out in any pod, the snapshot is not taken and the post command
+	// is run in the pods in which pre succeeded.
+	// +optional
+	Pre *VolumeSnapshotHookAction `json:"pre,omitempty" protobuf:"bytes,1,opt,name=pre"`
+
+	// post is run in the same pods after the CSI driver has cut the
+	// snapshot. It is also run if the snapshot fails, is deleted or is
+	// not cut within postDeadline after pre ran, so that the application
+	// is never left e.g. frozen. Its failures are recorded but do not
+	// affect the snapshot.
+	// +optional
+	Post *VolumeSnapshotHookAction `json:"post,omitempty" protobuf:"bytes,2,opt,name=post"`
+
+	// postDeadline is how long after pre succeeded the post command runs at
+	// the latest, even if the snapshot has not been cut yet. Defaults to 5m.
+	// +optional
+	PostDeadline *metav1.Duration `json:"postDeadline,omitempty" protobuf:"bytes,3,opt,name=postDeadline"`
+}
+
+// VolumeSnapshotHookAction is a command executed in a container.
+type VolumeSnapshotHookAction struct {
+	// container is the name of the container in which the command is run.
+	// Defaults to the first container of the pod.
+	// +optional
+	Container string `json:"container,omitempty" protobuf:"bytes,1,opt,name=container"`
+
+	// command is the command line to run. It is not run in a shell.
+	// Required.
+	// +kubebuilder:validation:MinItems=1
+	Command []string `json:"command" protobuf:"bytes,2,rep,name=command"`
+
+	// timeout is how long the command may run. Defaults to 30s.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty" protobuf:"bytes,3,opt,name=timeout"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VolumeSnapshotTransferRequest offers a VolumeSnapshot in the namespace of
+// the request to another namespace. The transfer takes place once a
+// VolumeSnapshotTransferAccept in the target namespace accepts the request:
+// the VolumeSnapshotContent of the snapshot is then bound to a new
+// VolumeSnapshot in the target namespace and the source VolumeSnapshot is
+// deleted without deleting the content.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Namespaced,shortName=vstr
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="SourceSnapshot",type=string,JSONPath=`.spec.volumeSnapshotName`,description="The name of the VolumeSnapshot that is transferred."
+// +kubebuilder:printcolumn:name="TargetNamespace",type=string,JSONPath=`.spec.targetNamespace`,description="The namespace the VolumeSnapshot is transferred to."
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`,description="The phase of the transfer."
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type VolumeSnapshotTransferRequest struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// spec defines the VolumeSnapshot that is offered and the namespace it
+	// is offered to.
+	// Required.
+	Spec VolumeSnapshotTransferRequestSpec `json:"spec" protobuf:"bytes,2,opt,name=spec"`
+
+	// status represents the most recently observed state of the transfer.
+	// +optional
+	Status *VolumeSnapshotTransferStatus `json:"status,omitempty" protobuf:"bytes,3,opt,name=status"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VolumeSnapshotTransferRequestList is a list of VolumeSnapshotTransferRequest objects
+// +kubebuilder:object:root=true
+type VolumeSnapshotTransferRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// List of VolumeSnapshotTransferRequests
+	Items []VolumeSnapshotTransferRequest `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
+
+// VolumeSnapshotTransferRequestSpec describes the VolumeSnapshot offered by
+// a VolumeSnapshotTransferRequest.
+// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="spec is immutable"
+type VolumeSnapshotTransferRequestSpec struct {
+	// volumeSnapshotName is the name of the VolumeSnapshot in the namespace
+	// of the request that is transferred. The snapshot must be ready to use
+	// and must not be a member of a VolumeGroupSnapshot.
+	// Required.
+	// +kubebuilder:validation:MinLength=1
+	VolumeSnapshotName string `json:"volumeSnapshotName" protobuf:"bytes,1,opt,name=volumeSnapshotName"`
+
+	// targetNamespace is the namespace the VolumeSnapshot is transferred
+	// to.
+	// Required.
+	// +kubebuilder:validation:MinLength=1
+	TargetNamespace string `json:"targetNamespace" protobuf:"bytes,2,opt,name=targetNamespace"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VolumeSnapshotTransferAccept accepts a VolumeSnapshotTransferRequest of
+// another namespace. It is created in the target namespace of the request.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Namespaced,shortName=vsta
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="SourceNamespace",type=string,JSONPath=`.spec.sourceNamespace`,description="The namespace of the accepted VolumeSnapshotTransferRequest."
+// +kubebuilder:printcolumn:name="Request",type=string,JSONPath=`.spec.transferRequestName`,description="The name of the accepted VolumeSnapshotTransferRequest."
+// +kubebuilder:printcolumn:name="Snapshot",type=string,JSONPath=`.spec.volumeSnapshotName`,description="The name of the VolumeSnapshot created in this namespace."
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`,description="The phase of the transfer."
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type VolumeSnapshotTransferAccept struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// spec defines the accepted request and the VolumeSnapshot created for
+	// the transferred content.
+	// Required.
+	Spec VolumeSnapshotTransferAcceptSpec `json:"spec" protobuf:"bytes,2,opt,name=spec"`
+
+	// status represents the most recently observed state of the transfer.
+	// +optional
+	Status *VolumeSnapshotTransferStatus `json:"status,omitempty" protobuf:"bytes,3,opt,name=status"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VolumeSnapshotTransferAcceptList is a list of VolumeSnapshotTransferAccept objects
+// +kubebuilder:object:root=true
+type VolumeSnapshotTransferAcceptList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// List of VolumeSnapshotTransferAccepts
+	Items []VolumeSnapshotTransferAccept `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
+
+// VolumeSnapshotTransferAcceptSpec describes the request accepted by a
+// VolumeSnapshotTransferAccept.
+// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="spec is immutable"
+type VolumeSnapshotTransferAcceptSpec struct {
+	// sourceNamespace is the namespace of the accepted
+	// VolumeSnapshotTransferRequest.
+	// Required.
+	// +kubebuilder:validation:MinLength=1
+	SourceNamespace string `json:"sourceNamespace" protobuf:"bytes,1,opt,name=sourceNamespace"`
+
+	// transferRequestName is the name of the accepted
+	// VolumeSnapshotTransferRequest.
+	// Required.
+	// +kubebuilder:validation:MinLength=1
+	TransferRequestName string `json:"transferRequestName" protobuf:"bytes,2,opt,name=transferRequestName"`
+
+	// volumeSnapshotName is the name of the VolumeSnapshot that is created
+	// in this namespace and bound to the transferred VolumeSnapshotContent.
+	// No VolumeSnapshot of this name may exist in the namespace.
+	// Required.
+	// +kubebuilder:validation:MinLength=1
+	VolumeSnapshotName string `json:"volumeSnapshotName" protobuf:"bytes,3,opt,name=volumeSnapshotName"`
+}
+
+// VolumeSnapshotTransferPhase is the phase of a VolumeSnapshot transfer.
+type VolumeSnapshotTransferPhase string
+
+const (
+	// VolumeSnapshotTransferPending means that the transfer waits for the
+	// other party or for the source VolumeSnapshot to become ready.
+	VolumeSnapshotTransferPending VolumeSnapshotTransferPhase = "Pending"
+	// VolumeSnapshotTransferCompleted means that the VolumeSnapshotContent
+	// is bound to the VolumeSnapshot in the target namespace.
+	VolumeSnapshotTransferCompleted VolumeSnapshotTransferPhase = "Completed"
+	// VolumeSnapshotTransferFailed means that the transfer cannot take
+	// place, e.g. because the source VolumeSnapshot was deleted.
+	VolumeSnapshotTransferFailed VolumeSnapshotTransferPhase = "Failed"
+)
+
+// VolumeSnapshotTransferStatus is the status of both sides of a
+// VolumeSnapshot transfer.
+type VolumeSnapshotTransferStatus struct {
+	// phase is the phase of the transfer.
+	// +optional
+	Phase VolumeSnapshotTransferPhase `json:"phase,omitempty" protobuf:"bytes,1,opt,name=phase,casttype=VolumeSnapshotTransferPhase"`
+
+	// volumeSnapshotContentName is the name of the transferred
+	// VolumeSnapshotContent.
+	// +optional
+	VolumeSnapshotContentName *string `json:"volumeSnapshotContentName,omitempty" protobuf:"bytes,2,opt,name=volumeSnapshotContentName"`
+
+	// completionTime is the time the VolumeSnapshotContent was bound to the
+	// target namespace.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty" protobuf:"bytes,3,opt,name=completionTime"`
+
+	// message explains why the transfer is pending or has failed.
+	// +optional
+	Message *string `json:"message,omitempty" protobuf:"bytes,4,opt,name=message"`
+}
@@ -24,6 +24,7 @@ import (
 
 	groupsnapshotv1beta1 "github.com/kubernetes-csi/external-snapshotter/client/v8/clientset/versioned/typed/volumegroupsnapshot/v1beta1"
 	snapshotv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/clientset/versioned/typed/volumesnapshot/v1"
+	snapshotv1alpha1 "github.com/kubernetes-csi/external-snapshotter/client/v8/clientset/versioned/typed/volumesnapshot/v1alpha1"
 	discovery "k8s.io/client-go/discovery"
 	rest "k8s.io/client-go/rest"
 	flowcontrol "k8s.io/client-go/util/flowcontrol"
@@ -33,6 +34,7 @@ type Interface interface {
 	Discovery() discovery.DiscoveryInterface
 	GroupsnapshotV1beta1() groupsnapshotv1beta1.GroupsnapshotV1beta1Interface
 	SnapshotV1() snapshotv1.SnapshotV1Interface
+	SnapshotV1alpha1() snapshotv1alpha1.SnapshotV1alpha1Interface
 }
 
 // Clientset contains the clients for groups.
@@ -40,6 +42,7 @@ type Clientset struct {
 	*discovery.DiscoveryClient
 	groupsnapshotV1beta1 *groupsnapshotv1beta1.GroupsnapshotV1beta1Client
 	snapshotV1           *snapshotv1.SnapshotV1Client
+	snapshotV1alpha1     *snapshotv1alpha1.SnapshotV1alpha1Client
 }
 
 // GroupsnapshotV1beta1 retrieves the GroupsnapshotV1beta1Client
@@ -52,6 +55,11 @@ func (c *Clientset) SnapshotV1() snapshotv1.SnapshotV1Interface {
 	return c.snapshotV1
 }
 
+// SnapshotV1alpha1 retrieves the SnapshotV1alpha1Client
+func (c *Clientset) SnapshotV1alpha1() snapshotv1alpha1.SnapshotV1alpha1Interface {
+	return c.snapshotV1alpha1
+}
+
 // Discovery retrieves the DiscoveryClient
 func (c *Clientset) Discovery() discovery.DiscoveryInterface {
 	if c == nil {
@@ -104,6 +112,10 @@ func NewForConfigAndClient(c *rest.Config, httpClient *http.Client) (*Clientset,
 	if err != nil {
 		return nil, err
 	}
+	cs.snapshotV1alpha1, err = snapshotv1alpha1.NewForConfigAndClient(&configShallowCopy, httpClient)
+	if err != nil {
+		return nil, err
+	}
 
 	cs.DiscoveryClient, err = discovery.NewDiscoveryClientForConfigAndClient(&configShallowCopy, httpClient)
 	if err != nil {
@@ -127,6 +139,7 @@ func New(c rest.Interface) *Clientset {
 	var cs Clientset
 	cs.groupsnapshotV1beta1 = groupsnapshotv1beta1.New(c)
 	cs.snapshotV1 = snapshotv1.New(c)
+	cs.snapshotV1alpha1 = snapshotv1alpha1.New(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClient(c)
 	return &cs
@@ -23,6 +23,7 @@ import (
 
 	v1beta1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumegroupsnapshot/v1beta1"
 	v1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	v1alpha1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1alpha1"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	cache "k8s.io/client-go/tools/cache"
 )
@@ -69,6 +70,20 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	case v1.SchemeGroupVersion.WithResource("volumesnapshotcontents"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Snapshot().V1().VolumeSnapshotContents().Informer()}, nil
 
+		// Group=snapshot.storage.k8s.io, Version=v1alpha1
+	case v1alpha1.SchemeGroupVersion.WithResource("snapshotmetadataservices"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Snapshot().V1alpha1().SnapshotMetadataServices().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("volumesnapshothooks"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Snapshot().V1alpha1().VolumeSnapshotHooks().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("volumesnapshotretentionpolicies"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Snapshot().V1alpha1().VolumeSnapshotRetentionPolicies().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("volumesnapshotschedules"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Snapshot().V1alpha1().VolumeSnapshotSchedules().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("volumesnapshottransferaccepts"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Snapshot().V1alpha1().VolumeSnapshotTransferAccepts().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("volumesnapshottransferrequests"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Snapshot().V1alpha1().VolumeSnapshotTransferRequests().Informer()}, nil
+
 	}
 
 	return nil, fmt.Errorf("no informer found for %v", resource)
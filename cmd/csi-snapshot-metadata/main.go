@@ -0,0 +1,232 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	klog "k8s.io/klog/v2"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/kubernetes-csi/csi-lib-utils/connection"
+	"github.com/kubernetes-csi/csi-lib-utils/metrics"
+	csirpc "github.com/kubernetes-csi/csi-lib-utils/rpc"
+	crdv1alpha1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1alpha1"
+	clientset "github.com/kubernetes-csi/external-snapshotter/client/v8/clientset/versioned"
+	informers "github.com/kubernetes-csi/external-snapshotter/client/v8/informers/externalversions"
+	snapshotcredentials "github.com/kubernetes-csi/external-snapshotter/v8/pkg/credentials"
+	snapshotmetadata "github.com/kubernetes-csi/external-snapshotter/v8/pkg/snapshot-metadata"
+	webhook "github.com/kubernetes-csi/external-snapshotter/v8/pkg/validation-webhook"
+	"k8s.io/component-base/featuregate"
+	"k8s.io/component-base/logs"
+	logsapi "k8s.io/component-base/logs/api/v1"
+	_ "k8s.io/component-base/logs/json/register"
+)
+
+const (
+	// Default timeout of short CSI calls like GetPluginInfo
+	defaultCSITimeout = time.Minute
+)
+
+// Command line flags
+var (
+	kubeconfig     = flag.String("kubeconfig", "", "Absolute path to the kubeconfig file. Required only when running out of cluster.")
+	csiAddress     = flag.String("csi-address", "/run/csi/socket", "Address of the CSI driver socket.")
+	resyncPeriod   = flag.Duration("resync-period", 15*time.Minute, "Resync interval of the informers. Default is 15 minutes")
+	showVersion    = flag.Bool("version", false, "Show version.")
+	csiTimeout     = flag.Duration("timeout", defaultCSITimeout, "The timeout for short RPCs to the CSI driver like GetPluginInfo. Default is 1 minute.")
+	port           = flag.Int("port", 50051, "TCP port that the snapshot metadata service listens on.")
+	certFile       = flag.String("tls-cert-file", "", "File containing the x509 certificate of the service. Required.")
+	keyFile        = flag.String("tls-private-key-file", "", "File containing the x509 private key matching --tls-cert-file. Required.")
+	caCertFile     = flag.String("ca-cert-file", "", "File containing the CA certificate that signed --tls-cert-file. It is published in the SnapshotMetadataService. Required.")
+	serviceAddress = flag.String("service-address", "", "The address (host:port) at which clients reach the service, usually the DNS name of its Kubernetes Service. Required.")
+	audience       = flag.String("audience", "", "The audience that the tokens sent by clients must be issued for. Required.")
+
+	kubeAPIQPS   = flag.Float64("kube-api-qps", 5, "QPS to use while communicating with the kubernetes apiserver. Defaults to 5.0.")
+	kubeAPIBurst = flag.Int("kube-api-burst", 10, "Burst to use while communicating with the kubernetes apiserver. Defaults to 10.")
+
+	credentialsDir           = flag.String("credentials-dir", "", "Directory holding the credential directories named by the csi.storage.k8s.io/snapshotter-credentials-file parameter of the snapshot classes. The default is empty, which disables file credentials.")
+	credentialsExecPluginDir = flag.String("credentials-exec-plugin-dir", "", "Directory holding the exec plugins named by the csi.storage.k8s.io/snapshotter-credentials-exec parameter of the snapshot classes. The default is empty, which disables exec plugins.")
+	credentialsExecTimeout   = flag.Duration("credentials-exec-timeout", 10*time.Second, "Maximum run time of a credentials exec plugin. Default is 10 seconds.")
+	credentialsCacheTTL      = flag.Duration("credentials-cache-ttl", time.Minute, "Time the credentials passed to the CSI driver are cached for before they are read again. 0 disables the cache. Default is 1 minute.")
+)
+
+var version = "unknown"
+
+func main() {
+	fg := featuregate.NewFeatureGate()
+	logsapi.AddFeatureGates(fg)
+	c := logsapi.NewLoggingConfiguration()
+	logsapi.AddGoFlags(c, flag.CommandLine)
+	logs.InitLogs()
+	flag.Parse()
+	if err := logsapi.ValidateAndApply(c, fg); err != nil {
+		klog.ErrorS(err, "LoggingConfiguration is invalid")
+		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+	}
+
+	if *showVersion {
+		fmt.Println(os.Args[0], version)
+		os.Exit(0)
+	}
+	klog.InfoS("Version", "version", version)
+
+	if *certFile == "" || *keyFile == "" || *caCertFile == "" || *serviceAddress == "" || *audience == "" {
+		klog.Error("--tls-cert-file, --tls-private-key-file, --ca-cert-file, --service-address and --audience are required")
+		os.Exit(1)
+	}
+	caCert, err := os.ReadFile(*caCertFile)
+	if err != nil {
+		klog.Errorf("Error reading CA certificate: %v", err)
+		os.Exit(1)
+	}
+	certWatcher, err := webhook.NewCertWatcher(*certFile, *keyFile, webhook.DefaultCertReloadInterval)
+	if err != nil {
+		klog.Errorf("Error loading certificate: %v", err)
+		os.Exit(1)
+	}
+
+	// Create the client config. Use kubeconfig if given, otherwise assume in-cluster.
+	config, err := buildConfig(*kubeconfig)
+	if err != nil {
+		klog.Error(err.Error())
+		os.Exit(1)
+	}
+	config.QPS = (float32)(*kubeAPIQPS)
+	config.Burst = *kubeAPIBurst
+
+	kubeClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		klog.Error(err.Error())
+		os.Exit(1)
+	}
+	snapClient, err := clientset.NewForConfig(config)
+	if err != nil {
+		klog.Errorf("Error building snapshot clientset: %s", err.Error())
+		os.Exit(1)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	// Connect to CSI.
+	metricsManager := metrics.NewCSIMetricsManager("" /* driverName */)
+	csiConn, err := connection.Connect(
+		ctx,
+		*csiAddress,
+		metricsManager,
+		connection.OnConnectionLoss(connection.ExitOnConnectionLoss()))
+	if err != nil {
+		klog.Errorf("error connecting to CSI driver: %v", err)
+		os.Exit(1)
+	}
+
+	tctx, tcancel := context.WithTimeout(ctx, *csiTimeout)
+	defer tcancel()
+	driverName, err := csirpc.GetDriverName(tctx, csiConn)
+	if err != nil {
+		klog.Errorf("error getting CSI driver name: %v", err)
+		os.Exit(1)
+	}
+	klog.V(2).Infof("CSI driver name: %q", driverName)
+
+	if err = csirpc.ProbeForever(ctx, csiConn, *csiTimeout); err != nil {
+		klog.Errorf("error waiting for CSI driver to be ready: %v", err)
+		os.Exit(1)
+	}
+	tctx, tcancel = context.WithTimeout(ctx, *csiTimeout)
+	defer tcancel()
+	capabilities, err := csirpc.GetPluginCapabilities(tctx, csiConn)
+	if err != nil {
+		klog.Errorf("error getting CSI plugin capabilities: %v", err)
+		os.Exit(1)
+	}
+	if !capabilities[csi.PluginCapability_Service_SNAPSHOT_METADATA_SERVICE] {
+		klog.Errorf("CSI driver %s does not support the SnapshotMetadata service", driverName)
+		os.Exit(1)
+	}
+
+	factory := informers.NewSharedInformerFactory(snapClient, *resyncPeriod)
+	server := snapshotmetadata.NewServer(kubeClient, csiConn, driverName, *audience,
+		factory.Snapshot().V1().VolumeSnapshots(),
+		factory.Snapshot().V1().VolumeSnapshotContents(),
+		factory.Snapshot().V1().VolumeSnapshotClasses(),
+		snapshotcredentials.Options{
+			Dir:           *credentialsDir,
+			ExecPluginDir: *credentialsExecPluginDir,
+			ExecTimeout:   *credentialsExecTimeout,
+			CacheTTL:      *credentialsCacheTTL,
+		})
+	factory.Start(ctx.Done())
+	if !server.WaitForCacheSync(ctx.Done()) {
+		klog.Error("Cannot sync caches")
+		os.Exit(1)
+	}
+
+	if err := snapshotmetadata.RegisterService(ctx, snapClient, driverName, crdv1alpha1.SnapshotMetadataServiceSpec{
+		Address:  *serviceAddress,
+		CACert:   caCert,
+		Audience: *audience,
+	}); err != nil {
+		klog.Error(err.Error())
+		os.Exit(1)
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", *port))
+	if err != nil {
+		klog.Errorf("Failed to listen on port %d: %v", *port, err)
+		os.Exit(1)
+	}
+	grpcServer := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: certWatcher.GetCertificate,
+	})))
+	csi.RegisterSnapshotMetadataServer(grpcServer, server)
+
+	go certWatcher.Start(ctx)
+	go func() {
+		<-ctx.Done()
+		grpcServer.GracefulStop()
+	}()
+
+	klog.Infof("Starting snapshot metadata service on port %d", *port)
+	if err := grpcServer.Serve(listener); err != nil {
+		klog.Errorf("Snapshot metadata service failed: %v", err)
+		os.Exit(1)
+	}
+}
+
+func buildConfig(kubeconfig string) (*rest.Config, error) {
+	if kubeconfig != "" {
+		return clientcmd.BuildConfigFromFlags("", kubeconfig)
+	}
+	return rest.InClusterConfig()
+}
@@ -28,6 +28,7 @@ import (
 
 	"google.golang.org/grpc"
 
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -36,6 +37,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/util/workqueue"
 	klog "k8s.io/klog/v2"
@@ -45,9 +47,12 @@ import (
 	"github.com/kubernetes-csi/csi-lib-utils/leaderelection"
 	"github.com/kubernetes-csi/csi-lib-utils/metrics"
 	csirpc "github.com/kubernetes-csi/csi-lib-utils/rpc"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/credentials"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/debug"
 	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/features"
 	controller "github.com/kubernetes-csi/external-snapshotter/v8/pkg/sidecar-controller"
 	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/snapshotter"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/tracing"
 	utilflag "k8s.io/component-base/cli/flag"
 	"k8s.io/component-base/featuregate"
 	"k8s.io/component-base/logs"
@@ -99,6 +104,40 @@ var (
 	groupSnapshotNamePrefix     = flag.String("groupsnapshot-name-prefix", "groupsnapshot", "Prefix to apply to the name of a created group snapshot")
 	groupSnapshotNameUUIDLength = flag.Int("groupsnapshot-name-uuid-length", -1, "Length in characters for the generated uuid of a created group snapshot. Defaults behavior is to NOT truncate.")
 	featureGates                map[string]bool
+
+	emulateGroupSnapshots        = flag.Bool("emulate-volume-group-snapshots", false, "If set and the CSI driver does not support CREATE_DELETE_GET_VOLUME_GROUP_SNAPSHOT, VolumeGroupSnapshots are emulated with one CreateSnapshot call per volume, issued in parallel under the create-snapshot limits. Workloads are not quiesced. Requires --feature-gates=CSIVolumeGroupSnapshot=true.")
+	groupSnapshotEmulationWindow = flag.Duration("volume-group-snapshot-emulation-window", 10*time.Second, "Time within which all the snapshots of an emulated group snapshot must be cut, otherwise they are deleted and the group snapshot is retried. Default is 10 seconds.")
+
+	snapshotStatusCacheTTL = flag.Duration("snapshot-status-cache-ttl", 0, "If set, the status of the snapshots of pre-provisioned and group snapshot member VolumeSnapshotContents is read from a listing of all the snapshots of the driver, which is refreshed after this duration. The default is 0, which calls ListSnapshots for every snapshot. Requires ListSnapshots support in the driver.")
+	listSnapshotsPageSize  = flag.Int("list-snapshots-page-size", 100, "Maximum number of snapshots requested per ListSnapshots call by --snapshot-status-cache-ttl. 0 lets the driver choose.")
+
+	csiRPCMaxInFlight = flag.Int("csi-rpc-max-inflight", 0, "Maximum number of concurrent CreateSnapshot, DeleteSnapshot, ListSnapshots, CreateVolumeGroupSnapshot, DeleteVolumeGroupSnapshot or GetVolumeGroupSnapshot calls to the CSI driver, per RPC type. The default is 0, which does not limit the calls.")
+	csiRPCQPS         = flag.Float64("csi-rpc-qps", 0, "Maximum rate of the calls to the CSI driver per RPC type, enforced with a token bucket. The default is 0, which does not limit the calls.")
+	csiRPCBurst       = flag.Int("csi-rpc-burst", 0, "Size of the token bucket of --csi-rpc-qps. Defaults to --csi-rpc-qps, rounded up.")
+	csiRPCLimits      = flag.String("csi-rpc-limits", "", "Comma-separated list of key=value pairs overriding --csi-rpc-max-inflight, --csi-rpc-qps and --csi-rpc-burst for one RPC type, e.g. `create-snapshot-max-inflight=2,create-snapshot-qps=1`. The RPC types are create-snapshot, delete-snapshot, list-snapshots, create-group-snapshot, delete-group-snapshot and get-group-snapshot.")
+
+	credentialsDir           = flag.String("credentials-dir", "", "Directory holding the credential directories named by the csi.storage.k8s.io/*-credentials-file parameters of the snapshot classes, e.g. volumes of the Secrets Store CSI driver or projected volumes. The default is empty, which disables file credentials.")
+	credentialsExecPluginDir = flag.String("credentials-exec-plugin-dir", "", "Directory holding the exec plugins named by the csi.storage.k8s.io/*-credentials-exec parameters of the snapshot classes. The default is empty, which disables exec plugins.")
+	credentialsExecTimeout   = flag.Duration("credentials-exec-timeout", 10*time.Second, "Maximum run time of a credentials exec plugin. Default is 10 seconds.")
+	credentialsCacheTTL      = flag.Duration("credentials-cache-ttl", time.Minute, "Time the credentials passed to the CSI driver are cached for before they are read again. 0 disables the cache. Default is 1 minute.")
+
+	importSnapshots             = flag.Bool("import-snapshots", false, "If set, the existing snapshots of the driver that are not referenced by any VolumeSnapshotContent are imported as pre-provisioned VolumeSnapshotContents with the Retain deletion policy each time the sidecar becomes the leader.")
+	importSourceVolumeIDs       = flag.String("import-source-volume-ids", "", "Comma-separated list of the IDs of the volumes whose snapshots are imported by --import-snapshots. All volumes by default.")
+	importSnapshotIDPrefix      = flag.String("import-snapshot-id-prefix", "", "Only the snapshots whose ID starts with this prefix are imported by --import-snapshots.")
+	importNamespace             = flag.String("import-namespace", "", "Namespace of the VolumeSnapshots of imported snapshots whose source volume has no bound PersistentVolume. These snapshots are skipped if not set.")
+	importSnapshotClass         = flag.String("import-snapshot-class", "", "VolumeSnapshotClass set on the imported VolumeSnapshotContents and VolumeSnapshots.")
+	importCreateVolumeSnapshots = flag.Bool("import-create-volume-snapshots", false, "If set, --import-snapshots also creates the VolumeSnapshots bound to the imported VolumeSnapshotContents.")
+	importSecret                = flag.String("import-secret", "", "Namespace and name (<namespace>/<name>) of the secret passed to ListSnapshots by --import-snapshots.")
+	importDryRun                = flag.Bool("import-dry-run", false, "If set, --import-snapshots only reports the snapshots that would be imported.")
+
+	orphanDetectionInterval = flag.Duration("orphan-detection-interval", 0, "Interval at which the snapshots of the driver are compared with the VolumeSnapshotContents to detect orphaned snapshots. The default is 0, which disables the detection. Requires ListSnapshots support in the driver.")
+	orphanDetectionPageSize = flag.Int("orphan-detection-page-size", 100, "Maximum number of snapshots requested per ListSnapshots call by the orphan detection. 0 lets the driver choose.")
+	orphanDetectionSecret   = flag.String("orphan-detection-secret", "", "Namespace and name (<namespace>/<name>) of the secret passed to ListSnapshots and DeleteSnapshot by the orphan detection.")
+	deleteOrphanedSnapshots = flag.Bool("delete-orphaned-snapshots", false, "If set, orphaned snapshots older than --orphaned-snapshot-min-age are deleted from the storage backend.")
+	orphanedSnapshotMinAge  = flag.Duration("orphaned-snapshot-min-age", 24*time.Hour, "Minimum age of an orphaned snapshot before it is deleted. Default is 24 hours.")
+
+	enableDebugEndpoints = flag.Bool("enable-debug-endpoints", false, "Enables read-only JSON endpoints under /debug/state/ on --http-endpoint, exposing the work queues, the result of the last sync of every object and the leader election state of the sidecar.")
+	enableOtelTracing    = flag.Bool("enable-otel-tracing", false, "Enables the export of OpenTelemetry traces of the snapshots with OTLP over gRPC. The exporter is configured with the standard OTEL_EXPORTER_OTLP_* environment variables. The traces started by the snapshot controller are continued into the CSI calls.")
 )
 
 var (
@@ -189,14 +228,29 @@ func main() {
 		addr = *httpEndpoint
 	}
 
+	ctx := context.Background()
+	connectOptions := []connection.Option{connection.OnConnectionLoss(connection.ExitOnConnectionLoss())}
+	if *enableOtelTracing {
+		shutdownTracing, err := tracing.Setup(ctx, "csi-snapshotter")
+		if err != nil {
+			klog.Errorf("failed to set up tracing: %v", err)
+			os.Exit(1)
+		}
+		defer func() {
+			if err := shutdownTracing(context.Background()); err != nil {
+				klog.Errorf("failed to shut down tracing: %v", err)
+			}
+		}()
+		connectOptions = append(connectOptions, connection.WithOtelTracing())
+	}
+
 	// Connect to CSI.
 	metricsManager := metrics.NewCSIMetricsManager("" /* driverName */)
-	ctx := context.Background()
 	csiConn, err := connection.Connect(
 		ctx,
 		*csiAddress,
 		metricsManager,
-		connection.OnConnectionLoss(connection.ExitOnConnectionLoss()))
+		connectOptions...)
 	if err != nil {
 		klog.Errorf("error connecting to CSI driver: %v", err)
 		os.Exit(1)
@@ -257,6 +311,45 @@ func main() {
 		os.Exit(1)
 	}
 
+	if *snapshotStatusCacheTTL < 0 || *listSnapshotsPageSize < 0 {
+		klog.Error("--snapshot-status-cache-ttl and --list-snapshots-page-size must not be negative")
+		os.Exit(1)
+	}
+
+	if *csiRPCMaxInFlight < 0 || *csiRPCQPS < 0 || *csiRPCBurst < 0 {
+		klog.Error("--csi-rpc-max-inflight, --csi-rpc-qps and --csi-rpc-burst must not be negative")
+		os.Exit(1)
+	}
+	rpcLimits, err := controller.ParseRPCLimitsFlag(controller.RPCLimit{
+		MaxInFlight: *csiRPCMaxInFlight,
+		QPS:         *csiRPCQPS,
+		Burst:       *csiRPCBurst,
+	}, *csiRPCLimits)
+	if err != nil {
+		klog.Errorf("invalid --csi-rpc-limits: %v", err)
+		os.Exit(1)
+	}
+
+	var orphanDetectorOptions *controller.OrphanDetectorOptions
+	if *orphanDetectionInterval > 0 {
+		orphanDetectorOptions, err = buildOrphanDetectorOptions()
+		if err != nil {
+			klog.Error(err.Error())
+			os.Exit(1)
+		}
+		tctx, cancel = context.WithTimeout(ctx, *csiTimeout)
+		defer cancel()
+		supportsListSnapshots, err := supportsControllerListSnapshots(tctx, csiConn)
+		if err != nil {
+			klog.Errorf("error determining if driver supports list snapshots operations: %v", err)
+			os.Exit(1)
+		}
+		if !supportsListSnapshots {
+			klog.Errorf("CSI driver %s does not support ListSnapshots, which is required by --orphan-detection-interval", driverName)
+			os.Exit(1)
+		}
+	}
+
 	klog.V(2).Infof("Start NewCSISnapshotSideCarController with snapshotter [%s] kubeconfig [%s] csiTimeout [%+v] csiAddress [%s] resyncPeriod [%+v] snapshotNamePrefix [%s] snapshotNameUUIDLength [%d]", driverName, *kubeconfig, *csiTimeout, *csiAddress, *resyncPeriod, *snapshotNamePrefix, snapshotNameUUIDLength)
 
 	snapShotter := snapshotter.NewSnapshotter(csiConn)
@@ -267,16 +360,30 @@ func main() {
 		supportsCreateVolumeGroupSnapshot, err := supportsGroupControllerCreateVolumeGroupSnapshot(tctx, csiConn)
 		if err != nil {
 			klog.Errorf("error determining if driver supports create/delete group snapshot operations: %v", err)
-		} else if !supportsCreateVolumeGroupSnapshot {
+		} else if !supportsCreateVolumeGroupSnapshot && !*emulateGroupSnapshots {
 			klog.Warningf("CSI driver %s does not support GroupControllerCreateVolumeGroupSnapshot when the --feature-gates=CSIVolumeGroupSnapshot=true flag is set", driverName)
 		}
-		groupSnapshotter = group_snapshotter.NewGroupSnapshotter(csiConn)
+		if err == nil && !supportsCreateVolumeGroupSnapshot && *emulateGroupSnapshots {
+			klog.Infof("CSI driver %s does not support GroupControllerCreateVolumeGroupSnapshot, emulating group snapshots with individual snapshots cut within %v", driverName, *groupSnapshotEmulationWindow)
+			groupSnapshotter = group_snapshotter.NewEmulatedGroupSnapshotter(snapShotter, *groupSnapshotEmulationWindow)
+		} else {
+			groupSnapshotter = group_snapshotter.NewGroupSnapshotter(csiConn)
+		}
 		if len(*groupSnapshotNamePrefix) == 0 {
 			klog.Error("group snapshot name prefix cannot be of length 0")
 			os.Exit(1)
 		}
 	}
 
+	var importer snapshotImporter
+	if *importSnapshots {
+		importer, err = newSnapshotImporter(snapClient, kubeClient, driverName, factory, coreFactory, snapShotter)
+		if err != nil {
+			klog.Error(err.Error())
+			os.Exit(1)
+		}
+	}
+
 	ctrl := controller.NewCSISnapshotSideCarController(
 		snapClient,
 		kubeClient,
@@ -297,15 +404,56 @@ func main() {
 		snapshotContentfactory.Groupsnapshot().V1beta1().VolumeGroupSnapshotContents(),
 		snapshotContentfactory.Groupsnapshot().V1beta1().VolumeGroupSnapshotClasses(),
 		workqueue.NewTypedItemExponentialFailureRateLimiter[string](*retryIntervalStart, *retryIntervalMax),
+		*snapshotStatusCacheTTL,
+		int32(*listSnapshotsPageSize),
+		rpcLimits,
+		credentials.Options{
+			Dir:           *credentialsDir,
+			ExecPluginDir: *credentialsExecPluginDir,
+			ExecTimeout:   *credentialsExecTimeout,
+			CacheTTL:      *credentialsCacheTTL,
+		},
 	)
 
+	var orphanDetector interface{ Run(<-chan struct{}) }
+	if orphanDetectorOptions != nil {
+		orphanDetector = controller.NewOrphanDetector(
+			snapClient,
+			kubeClient,
+			driverName,
+			snapshotContentfactory.Snapshot().V1().VolumeSnapshotContents(),
+			snapShotter,
+			*orphanDetectorOptions,
+		)
+	}
+
+	lockName := fmt.Sprintf("%s-%s", prefix, strings.Replace(driverName, "/", "-", -1))
+	leader := debug.NewLeader(*leaderElection, lockName)
+	if *enableDebugEndpoints {
+		if addr == "" {
+			klog.Error("--enable-debug-endpoints requires --http-endpoint")
+			os.Exit(1)
+		}
+		debugServer := debug.NewServer(mux)
+		ctrl.RegisterDebugEndpoints(debugServer)
+		debugServer.Register("leader", func() interface{} { return leader.State() })
+		klog.Infof("Debug endpoints successfully registered at %s", debug.PathPrefix)
+	}
+
 	run := func(context.Context) {
+		leader.Started()
 		// run...
 		stopCh := make(chan struct{})
 		snapshotContentfactory.Start(stopCh)
 		factory.Start(stopCh)
 		coreFactory.Start(stopCh)
 		go ctrl.Run(*threads, stopCh)
+		if orphanDetector != nil {
+			go orphanDetector.Run(stopCh)
+		}
+		if importer != nil {
+			go importBackendSnapshots(importer, stopCh)
+		}
 
 		// ...until SIGINT
 		c := make(chan os.Signal, 1)
@@ -317,7 +465,6 @@ func main() {
 	if !*leaderElection {
 		run(context.TODO())
 	} else {
-		lockName := fmt.Sprintf("%s-%s", prefix, strings.Replace(driverName, "/", "-", -1))
 		// Create a new clientset for leader election to prevent throttling
 		// due to snapshot sidecar
 		leClientset, err := kubernetes.NewForConfig(config)
@@ -359,6 +506,112 @@ func supportsControllerCreateSnapshot(ctx context.Context, conn *grpc.ClientConn
 	return capabilities[csi.ControllerServiceCapability_RPC_CREATE_DELETE_SNAPSHOT], nil
 }
 
+func supportsControllerListSnapshots(ctx context.Context, conn *grpc.ClientConn) (bool, error) {
+	capabilities, err := csirpc.GetControllerCapabilities(ctx, conn)
+	if err != nil {
+		return false, err
+	}
+
+	return capabilities[csi.ControllerServiceCapability_RPC_LIST_SNAPSHOTS], nil
+}
+
+// buildOrphanDetectorOptions validates the orphan detection flags.
+func buildOrphanDetectorOptions() (*controller.OrphanDetectorOptions, error) {
+	if *enableNodeDeployment {
+		return nil, fmt.Errorf("--orphan-detection-interval cannot be used together with --node-deployment")
+	}
+	if *orphanDetectionPageSize < 0 {
+		return nil, fmt.Errorf("--orphan-detection-page-size must not be negative")
+	}
+	if *orphanedSnapshotMinAge < 0 {
+		return nil, fmt.Errorf("--orphaned-snapshot-min-age must not be negative")
+	}
+	options := &controller.OrphanDetectorOptions{
+		Interval: *orphanDetectionInterval,
+		PageSize: int32(*orphanDetectionPageSize),
+		Timeout:  *csiTimeout,
+		Delete:   *deleteOrphanedSnapshots,
+		MinAge:   *orphanedSnapshotMinAge,
+	}
+	if *orphanDetectionSecret != "" {
+		secret, err := parseSecretReference(*orphanDetectionSecret)
+		if err != nil {
+			return nil, fmt.Errorf("--orphan-detection-secret: %v", err)
+		}
+		options.Secret = secret
+	}
+	return options, nil
+}
+
+type snapshotImporter interface {
+	Import(stopCh <-chan struct{}) ([]controller.ImportResult, error)
+}
+
+// newSnapshotImporter validates the --import-* flags and returns the importer
+// of the existing snapshots of the driver. It must be called before the
+// informers are started.
+func newSnapshotImporter(snapClient clientset.Interface, kubeClient kubernetes.Interface, driverName string, factory informers.SharedInformerFactory, coreFactory coreinformers.SharedInformerFactory, snapShotter snapshotter.Snapshotter) (snapshotImporter, error) {
+	if *enableNodeDeployment {
+		return nil, fmt.Errorf("--import-snapshots cannot be used together with --node-deployment")
+	}
+	options := controller.ImportOptions{
+		SnapshotIDPrefix:      *importSnapshotIDPrefix,
+		Namespace:             *importNamespace,
+		SnapshotClassName:     *importSnapshotClass,
+		CreateVolumeSnapshots: *importCreateVolumeSnapshots,
+		DryRun:                *importDryRun,
+		PageSize:              int32(*listSnapshotsPageSize),
+		Timeout:               *csiTimeout,
+	}
+	if *importSourceVolumeIDs != "" {
+		options.SourceVolumeIDs = strings.Split(*importSourceVolumeIDs, ",")
+	}
+	if *importSecret != "" {
+		secret, err := parseSecretReference(*importSecret)
+		if err != nil {
+			return nil, fmt.Errorf("--import-secret: %v", err)
+		}
+		options.Secret = secret
+	}
+
+	pvInformer := coreFactory.Core().V1().PersistentVolumes()
+	if err := pvInformer.Informer().AddIndexers(cache.Indexers{
+		utils.CSIDriverHandleIndexName: utils.PersistentVolumeByCSIDriverHandleIndexFunc,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to index PersistentVolumes: %v", err)
+	}
+	return controller.NewSnapshotImporter(snapClient, kubeClient, driverName, factory.Snapshot().V1().VolumeSnapshotContents(), pvInformer, snapShotter, options), nil
+}
+
+// importBackendSnapshots imports the existing snapshots of the driver once
+// the informers are synced and logs the result. It runs in the leader next
+// to the controller: the import is idempotent, so a new leader simply
+// imports the snapshots that are not imported yet.
+func importBackendSnapshots(importer snapshotImporter, stopCh <-chan struct{}) {
+	results, err := importer.Import(stopCh)
+	imported := 0
+	for _, result := range results {
+		if result.Skipped != "" {
+			klog.Infof("snapshot %s of volume %s: skipped: %s", result.SnapshotID, result.SourceVolumeID, result.Skipped)
+			continue
+		}
+		imported++
+		klog.Infof("snapshot %s of volume %s: VolumeSnapshotContent %s, VolumeSnapshot %s/%s", result.SnapshotID, result.SourceVolumeID, result.ContentName, result.SnapshotNamespace, result.SnapshotName)
+	}
+	klog.Infof("imported %d of %d matching snapshots (dry run: %t)", imported, len(results), *importDryRun)
+	if err != nil {
+		klog.Errorf("failed to import snapshots: %v", err)
+	}
+}
+
+func parseSecretReference(value string) (*corev1.SecretReference, error) {
+	namespace, name, found := strings.Cut(value, "/")
+	if !found || namespace == "" || name == "" {
+		return nil, fmt.Errorf("must be in the <namespace>/<name> format, got %q", value)
+	}
+	return &corev1.SecretReference{Namespace: namespace, Name: name}, nil
+}
+
 func supportsGroupControllerCreateVolumeGroupSnapshot(ctx context.Context, conn *grpc.ClientConn) (bool, error) {
 	capabilities, err := csirpc.GetGroupControllerCapabilities(ctx, conn)
 	if err != nil {
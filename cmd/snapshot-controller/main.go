@@ -44,12 +44,16 @@ import (
 
 	"github.com/kubernetes-csi/csi-lib-utils/leaderelection"
 	controller "github.com/kubernetes-csi/external-snapshotter/v8/pkg/common-controller"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/debug"
 	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/features"
 	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/metrics"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/tracing"
 
 	clientset "github.com/kubernetes-csi/external-snapshotter/client/v8/clientset/versioned"
 	snapshotscheme "github.com/kubernetes-csi/external-snapshotter/client/v8/clientset/versioned/scheme"
 	informers "github.com/kubernetes-csi/external-snapshotter/client/v8/informers/externalversions"
+	groupsnapshotinformers "github.com/kubernetes-csi/external-snapshotter/client/v8/informers/externalversions/volumegroupsnapshot/v1beta1"
+	snapshotalphainformers "github.com/kubernetes-csi/external-snapshotter/client/v8/informers/externalversions/volumesnapshot/v1alpha1"
 	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	coreinformers "k8s.io/client-go/informers"
 	utilflag "k8s.io/component-base/cli/flag"
@@ -83,13 +87,26 @@ var (
 	preventVolumeModeConversion   = flag.Bool("prevent-volume-mode-conversion", true, "Prevents an unauthorised user from modifying the volume mode when creating a PVC from an existing VolumeSnapshot.")
 
 	retryCRDIntervalMax = flag.Duration("retry-crd-interval-max", 30*time.Second, "Maximum time to wait for CRDs to appear. The default is 30 seconds.")
-	featureGates        map[string]bool
+	inventoryMaxSeries  = flag.Int("inventory-metrics-max-series", 1000, "Maximum number of series of each inventory metric, e.g. of the number of VolumeSnapshots by namespace, class, driver and ready state. The objects beyond are aggregated into a series whose labels are all `_other`. 0 does not limit the series, a negative value disables the inventory metrics. Default is 1000.")
+	enableOtelTracing   = flag.Bool("enable-otel-tracing", false, "Enables the export of OpenTelemetry traces of the snapshots with OTLP over gRPC. The exporter is configured with the standard OTEL_EXPORTER_OTLP_* environment variables.")
+
+	stuckOperationCheckInterval = flag.Duration("stuck-operation-check-interval", 0, "Interval at which the operations in flight are checked for stuck ones, which are reported with Warning events and metrics. 0 disables the check. Default is 0.")
+	stuckOperationThreshold     = flag.Duration("stuck-operation-threshold", 30*time.Minute, "Age beyond which an operation in flight is stuck. Default is 30 minutes.")
+	stuckOperationThresholds    = flag.String("stuck-operation-thresholds", "", "Comma separated list of operation=duration pairs overriding --stuck-operation-threshold for some operations, e.g. `CreateSnapshot=10m,DeleteSnapshot=1h`. The operations are CreateSnapshot, CreateSnapshotAndReady, DeleteSnapshot, CreateGroupSnapshot, CreateGroupSnapshotAndReady and DeleteGroupSnapshot. A duration of 0 disables the check of an operation.")
+	enableDebugEndpoints        = flag.Bool("enable-debug-endpoints", false, "Enables read-only JSON endpoints under /debug/state/ on --http-endpoint, exposing the work queues, the result of the last sync of every object, the operations in flight and the leader election state of the controller.")
+	featureGates                map[string]bool
 )
 
 var version = "unknown"
 
+// controllerRunner is implemented by the controllers started by the
+// snapshot-controller.
+type controllerRunner interface {
+	Run(workers int, stopCh <-chan struct{})
+}
+
 // Checks that the VolumeSnapshot v1 CRDs exist. It will wait at most the duration specified by retryCRDIntervalMax
-func ensureCustomResourceDefinitionsExist(client *clientset.Clientset, enableVolumeGroupSnapshots bool) error {
+func ensureCustomResourceDefinitionsExist(client *clientset.Clientset, enableVolumeGroupSnapshots, enableVolumeSnapshotSchedules, enableVolumeSnapshotRetentionPolicies, enableVolumeSnapshotHooks, enableVolumeSnapshotTransfer bool) error {
 	condition := func(ctx context.Context) (bool, error) {
 		var err error
 		// List calls should return faster with a limit of 1.
@@ -131,6 +148,39 @@ func ensureCustomResourceDefinitionsExist(client *clientset.Clientset, enableVol
 				return false, nil
 			}
 		}
+		if enableVolumeSnapshotSchedules {
+			_, err = client.SnapshotV1alpha1().VolumeSnapshotSchedules("").List(ctx, listOptions)
+			if err != nil {
+				klog.Errorf("Failed to list v1alpha1 volumesnapshotschedules with error=%+v", err)
+				return false, nil
+			}
+		}
+		if enableVolumeSnapshotRetentionPolicies {
+			_, err = client.SnapshotV1alpha1().VolumeSnapshotRetentionPolicies("").List(ctx, listOptions)
+			if err != nil {
+				klog.Errorf("Failed to list v1alpha1 volumesnapshotretentionpolicies with error=%+v", err)
+				return false, nil
+			}
+		}
+		if enableVolumeSnapshotHooks {
+			_, err = client.SnapshotV1alpha1().VolumeSnapshotHooks().List(ctx, listOptions)
+			if err != nil {
+				klog.Errorf("Failed to list v1alpha1 volumesnapshothooks with error=%+v", err)
+				return false, nil
+			}
+		}
+		if enableVolumeSnapshotTransfer {
+			_, err = client.SnapshotV1alpha1().VolumeSnapshotTransferRequests("").List(ctx, listOptions)
+			if err != nil {
+				klog.Errorf("Failed to list v1alpha1 volumesnapshottransferrequests with error=%+v", err)
+				return false, nil
+			}
+			_, err = client.SnapshotV1alpha1().VolumeSnapshotTransferAccepts("").List(ctx, listOptions)
+			if err != nil {
+				klog.Errorf("Failed to list v1alpha1 volumesnapshottransferaccepts with error=%+v", err)
+				return false, nil
+			}
+		}
 
 		return true, nil
 	}
@@ -178,6 +228,19 @@ func main() {
 	}
 	klog.InfoS("Version", "version", version)
 
+	if *enableOtelTracing {
+		shutdownTracing, err := tracing.Setup(context.Background(), "snapshot-controller")
+		if err != nil {
+			klog.Errorf("failed to set up tracing: %v", err)
+			os.Exit(1)
+		}
+		defer func() {
+			if err := shutdownTracing(context.Background()); err != nil {
+				klog.Errorf("failed to shut down tracing: %v", err)
+			}
+		}()
+	}
+
 	// Create the client config. Use kubeconfig if given, otherwise assume in-cluster.
 	config, err := buildConfig(*kubeconfig)
 	if err != nil {
@@ -210,11 +273,21 @@ func main() {
 		nodeInformer = coreFactory.Core().V1().Nodes()
 	}
 
+	var hookInformer snapshotalphainformers.VolumeSnapshotHookInformer
+	var podInformer v1.PodInformer
+	var hookExecutor controller.HookExecutor
+	if utilfeature.DefaultFeatureGate.Enabled(features.VolumeSnapshotHooks) {
+		hookInformer = factory.Snapshot().V1alpha1().VolumeSnapshotHooks()
+		podInformer = coreFactory.Core().V1().Pods()
+		hookExecutor = controller.NewPodExecutor(config, kubeClient)
+	}
+
 	// Create and register metrics manager
 	metricsManager := metrics.NewMetricsManager()
 	wg := &sync.WaitGroup{}
 
 	mux := http.NewServeMux()
+	var inventoryListers *metrics.InventoryListers
 	if *httpEndpoint != "" {
 		err := metricsManager.PrepareMetricsPath(mux, *metricsPath, promklog{})
 		if err != nil {
@@ -222,6 +295,21 @@ func main() {
 			os.Exit(1)
 		}
 		klog.Infof("Metrics path successfully registered at %s", *metricsPath)
+
+		if *inventoryMaxSeries >= 0 {
+			// The inventory is registered by the leader once its caches
+			// are synced, see run.
+			inventoryListers = &metrics.InventoryListers{
+				Snapshots: factory.Snapshot().V1().VolumeSnapshots().Lister(),
+				Contents:  factory.Snapshot().V1().VolumeSnapshotContents().Lister(),
+				Classes:   factory.Snapshot().V1().VolumeSnapshotClasses().Lister(),
+			}
+			if utilfeature.DefaultFeatureGate.Enabled(features.VolumeGroupSnapshot) {
+				inventoryListers.GroupSnapshots = factory.Groupsnapshot().V1beta1().VolumeGroupSnapshots().Lister()
+				inventoryListers.GroupSnapshotContents = factory.Groupsnapshot().V1beta1().VolumeGroupSnapshotContents().Lister()
+				inventoryListers.GroupSnapshotClasses = factory.Groupsnapshot().V1beta1().VolumeGroupSnapshotClasses().Lister()
+			}
+		}
 	}
 
 	// Add Snapshot types to the default Kubernetes so events can be logged for them
@@ -241,6 +329,8 @@ func main() {
 		coreFactory.Core().V1().PersistentVolumeClaims(),
 		coreFactory.Core().V1().PersistentVolumes(),
 		nodeInformer,
+		hookInformer,
+		podInformer,
 		metricsManager,
 		*resyncPeriod,
 		workqueue.NewTypedItemExponentialFailureRateLimiter[string](*retryIntervalStart, *retryIntervalMax),
@@ -250,19 +340,115 @@ func main() {
 		*enableDistributedSnapshotting,
 		*preventVolumeModeConversion,
 		utilfeature.DefaultFeatureGate.Enabled(features.VolumeGroupSnapshot),
+		hookExecutor,
 	)
 
-	if err := ensureCustomResourceDefinitionsExist(snapClient, utilfeature.DefaultFeatureGate.Enabled(features.VolumeGroupSnapshot)); err != nil {
+	// Optional controllers run next to the common snapshot controller and
+	// share its informers.
+	var optionalControllers []controllerRunner
+	if utilfeature.DefaultFeatureGate.Enabled(features.VolumeSnapshotSchedule) {
+		optionalControllers = append(optionalControllers, controller.NewSnapshotScheduleController(
+			snapClient,
+			kubeClient,
+			factory.Snapshot().V1alpha1().VolumeSnapshotSchedules(),
+			factory.Snapshot().V1().VolumeSnapshots(),
+			coreFactory.Core().V1().PersistentVolumeClaims(),
+			*resyncPeriod,
+			workqueue.NewTypedItemExponentialFailureRateLimiter[string](*retryIntervalStart, *retryIntervalMax),
+		))
+	}
+	if utilfeature.DefaultFeatureGate.Enabled(features.VolumeSnapshotRetentionPolicy) {
+		optionalControllers = append(optionalControllers, controller.NewSnapshotRetentionController(
+			snapClient,
+			kubeClient,
+			factory.Snapshot().V1alpha1().VolumeSnapshotRetentionPolicies(),
+			factory.Snapshot().V1().VolumeSnapshots(),
+			coreFactory.Core().V1().PersistentVolumeClaims(),
+			*resyncPeriod,
+			workqueue.NewTypedItemExponentialFailureRateLimiter[string](*retryIntervalStart, *retryIntervalMax),
+		))
+	}
+	if utilfeature.DefaultFeatureGate.Enabled(features.VolumeSnapshotTransfer) {
+		optionalControllers = append(optionalControllers, controller.NewSnapshotTransferController(
+			snapClient,
+			kubeClient,
+			factory.Snapshot().V1alpha1().VolumeSnapshotTransferRequests(),
+			factory.Snapshot().V1alpha1().VolumeSnapshotTransferAccepts(),
+			factory.Snapshot().V1().VolumeSnapshots(),
+			factory.Snapshot().V1().VolumeSnapshotContents(),
+			coreFactory.Core().V1().PersistentVolumeClaims(),
+			*resyncPeriod,
+			workqueue.NewTypedItemExponentialFailureRateLimiter[string](*retryIntervalStart, *retryIntervalMax),
+		))
+	}
+
+	if *stuckOperationCheckInterval > 0 {
+		thresholds, err := controller.ParseStuckOperationThresholdsFlag(*stuckOperationThreshold, *stuckOperationThresholds)
+		if err != nil {
+			klog.Errorf("invalid --stuck-operation-thresholds: %v", err)
+			os.Exit(1)
+		}
+		var groupSnapshotInformer groupsnapshotinformers.VolumeGroupSnapshotInformer
+		var groupSnapshotContentInformer groupsnapshotinformers.VolumeGroupSnapshotContentInformer
+		if utilfeature.DefaultFeatureGate.Enabled(features.VolumeGroupSnapshot) {
+			groupSnapshotInformer = factory.Groupsnapshot().V1beta1().VolumeGroupSnapshots()
+			groupSnapshotContentInformer = factory.Groupsnapshot().V1beta1().VolumeGroupSnapshotContents()
+		}
+		optionalControllers = append(optionalControllers, controller.NewStuckOperationWatchdog(
+			kubeClient,
+			ctrl.EventRecorder(),
+			factory.Snapshot().V1().VolumeSnapshots(),
+			factory.Snapshot().V1().VolumeSnapshotContents(),
+			groupSnapshotInformer,
+			groupSnapshotContentInformer,
+			coreFactory.Core().V1().PersistentVolumeClaims(),
+			metricsManager,
+			*stuckOperationCheckInterval,
+			thresholds,
+		))
+	}
+
+	if err := ensureCustomResourceDefinitionsExist(snapClient,
+		utilfeature.DefaultFeatureGate.Enabled(features.VolumeGroupSnapshot),
+		utilfeature.DefaultFeatureGate.Enabled(features.VolumeSnapshotSchedule),
+		utilfeature.DefaultFeatureGate.Enabled(features.VolumeSnapshotRetentionPolicy),
+		utilfeature.DefaultFeatureGate.Enabled(features.VolumeSnapshotHooks),
+		utilfeature.DefaultFeatureGate.Enabled(features.VolumeSnapshotTransfer)); err != nil {
 		klog.Errorf("Exiting due to failure to ensure CRDs exist during startup: %+v", err)
 		os.Exit(1)
 	}
 
+	lockName := "snapshot-controller-leader"
+	leader := debug.NewLeader(*leaderElection, lockName)
+	if *enableDebugEndpoints {
+		if *httpEndpoint == "" {
+			klog.Error("--enable-debug-endpoints requires --http-endpoint")
+			os.Exit(1)
+		}
+		debugServer := debug.NewServer(mux)
+		ctrl.RegisterDebugEndpoints(debugServer)
+		debugServer.Register("leader", func() interface{} { return leader.State() })
+		klog.Infof("Debug endpoints successfully registered at %s", debug.PathPrefix)
+	}
+
 	run := func(context.Context) {
+		leader.Started()
 		// run...
 		stopCh := make(chan struct{})
 		factory.Start(stopCh)
 		coreFactory.Start(stopCh)
 		go ctrl.Run(*threads, stopCh)
+		for _, c := range optionalControllers {
+			go c.Run(*threads, stopCh)
+		}
+		if inventoryListers != nil {
+			// Only the leader runs the informers, export the inventory
+			// once they are synced rather than zeros.
+			go func() {
+				factory.WaitForCacheSync(stopCh)
+				metricsManager.RegisterInventory(*inventoryListers, *inventoryMaxSeries)
+			}()
+		}
 
 		// ...until SIGINT
 		c := make(chan os.Signal, 1)
@@ -300,7 +486,6 @@ func main() {
 	if !*leaderElection {
 		run(context.TODO())
 	} else {
-		lockName := "snapshot-controller-leader"
 		// Create a new clientset for leader election to prevent throttling
 		// due to snapshot controller
 		leClientset, err := kubernetes.NewForConfig(config)
@@ -0,0 +1,139 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	klog "k8s.io/klog/v2"
+
+	clientset "github.com/kubernetes-csi/external-snapshotter/client/v8/clientset/versioned"
+	informers "github.com/kubernetes-csi/external-snapshotter/client/v8/informers/externalversions"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/features"
+	webhook "github.com/kubernetes-csi/external-snapshotter/v8/pkg/validation-webhook"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	utilflag "k8s.io/component-base/cli/flag"
+	"k8s.io/component-base/featuregate"
+	"k8s.io/component-base/logs"
+	logsapi "k8s.io/component-base/logs/api/v1"
+	_ "k8s.io/component-base/logs/json/register"
+)
+
+// Command line flags
+var (
+	certFile           = flag.String("tls-cert-file", "", "File containing the x509 certificate for HTTPS. (CA cert, if any, concatenated after server cert). Required.")
+	keyFile            = flag.String("tls-private-key-file", "", "File containing the x509 private key matching --tls-cert-file. Required.")
+	certReloadInterval = flag.Duration("tls-reload-interval", webhook.DefaultCertReloadInterval, "How often the certificate and key files are checked for changes.")
+	port               = flag.Int("port", 443, "Secure port that the webhook listens on.")
+	kubeconfig         = flag.String("kubeconfig", "", "Absolute path to the kubeconfig file. Required only when running out of cluster.")
+	resyncPeriod       = flag.Duration("resync-period", 15*time.Minute, "Resync interval of the informers.")
+	showVersion        = flag.Bool("version", false, "Show version.")
+
+	kubeAPIQPS   = flag.Float64("kube-api-qps", 5, "QPS to use while communicating with the kubernetes apiserver. Defaults to 5.0.")
+	kubeAPIBurst = flag.Int("kube-api-burst", 10, "Burst to use while communicating with the kubernetes apiserver. Defaults to 10.")
+
+	featureGates map[string]bool
+)
+
+var version = "unknown"
+
+func main() {
+	flag.Var(utilflag.NewMapStringBool(&featureGates), "feature-gates", "Comma-seprated list of key=value pairs that describe feature gates for alpha/experimental features. "+
+		"Options are:\n"+strings.Join(utilfeature.DefaultFeatureGate.KnownFeatures(), "\n"))
+
+	fg := featuregate.NewFeatureGate()
+	logsapi.AddFeatureGates(fg)
+	c := logsapi.NewLoggingConfiguration()
+	logsapi.AddGoFlags(c, flag.CommandLine)
+	logs.InitLogs()
+	flag.Parse()
+	if err := logsapi.ValidateAndApply(c, fg); err != nil {
+		klog.ErrorS(err, "LoggingConfiguration is invalid")
+		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+	}
+	if err := utilfeature.DefaultMutableFeatureGate.SetFromMap(featureGates); err != nil {
+		klog.Fatal("Error while parsing feature gates: ", err)
+	}
+
+	if *showVersion {
+		fmt.Println(os.Args[0], version)
+		os.Exit(0)
+	}
+	klog.InfoS("Version", "version", version)
+
+	if *certFile == "" || *keyFile == "" {
+		klog.Error("--tls-cert-file and --tls-private-key-file are required")
+		os.Exit(1)
+	}
+	certWatcher, err := webhook.NewCertWatcher(*certFile, *keyFile, *certReloadInterval)
+	if err != nil {
+		klog.Errorf("Error loading webhook certificate: %v", err)
+		os.Exit(1)
+	}
+
+	// Create the client config. Use kubeconfig if given, otherwise assume in-cluster.
+	config, err := buildConfig(*kubeconfig)
+	if err != nil {
+		klog.Error(err.Error())
+		os.Exit(1)
+	}
+	config.QPS = (float32)(*kubeAPIQPS)
+	config.Burst = *kubeAPIBurst
+
+	snapClient, err := clientset.NewForConfig(config)
+	if err != nil {
+		klog.Errorf("Error building snapshot clientset: %s", err.Error())
+		os.Exit(1)
+	}
+
+	factory := informers.NewSharedInformerFactory(snapClient, *resyncPeriod)
+	admitter, err := webhook.NewAdmitter(factory, utilfeature.DefaultFeatureGate.Enabled(features.VolumeGroupSnapshot))
+	if err != nil {
+		klog.Error(err.Error())
+		os.Exit(1)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	factory.Start(ctx.Done())
+	if !admitter.WaitForCacheSync(ctx.Done()) {
+		klog.Error("Cannot sync caches")
+		os.Exit(1)
+	}
+
+	if err := webhook.StartServer(ctx, *port, certWatcher, admitter); err != nil {
+		klog.Errorf("Webhook server failed: %v", err)
+		os.Exit(1)
+	}
+}
+
+func buildConfig(kubeconfig string) (*rest.Config, error) {
+	if kubeconfig != "" {
+		return clientcmd.BuildConfigFromFlags("", kubeconfig)
+	}
+	return rest.InClusterConfig()
+}
@@ -215,6 +215,13 @@ func withClaimLabels(pvcs []*v1.PersistentVolumeClaim, labels map[string]string)
 	return pvcs
 }
 
+func withSnapshotDeletionBlocked(snapshots []*crdv1.VolumeSnapshot, reason, message string) []*crdv1.VolumeSnapshot {
+	for i := range snapshots {
+		utils.SetDeletionBlockedCondition(&snapshots[i].Status.Conditions, snapshots[i].Generation, reason, message)
+	}
+	return snapshots
+}
+
 func withVolumesCSIDriverName(pvs []*v1.PersistentVolume, driverName string) []*v1.PersistentVolume {
 	for i := range pvs {
 		if pvs[i].Spec.CSI == nil {
@@ -771,6 +778,7 @@ func (r *snapshotReactor) checkContents(expectedContents []*crdv1.VolumeSnapshot
 		v.Spec.VolumeSnapshotRef.ResourceVersion = ""
 		if v.Status != nil {
 			v.Status.CreationTime = nil
+			clearConditionTimes(v.Status.Conditions)
 		}
 		expectedMap[v.Name] = v
 	}
@@ -782,6 +790,7 @@ func (r *snapshotReactor) checkContents(expectedContents []*crdv1.VolumeSnapshot
 		v.Spec.VolumeSnapshotRef.ResourceVersion = ""
 		if v.Status != nil {
 			v.Status.CreationTime = nil
+			clearConditionTimes(v.Status.Conditions)
 		}
 		gotMap[v.Name] = v
 	}
@@ -810,6 +819,7 @@ func (r *snapshotReactor) checkGroupContents(expectedGroupContents []*crdv1beta1
 		sort.Strings(v.Spec.Source.VolumeHandles)
 		if v.Status != nil {
 			v.Status.CreationTime = nil
+			clearConditionTimes(v.Status.Conditions)
 		}
 		expectedMap[v.Name] = v
 	}
@@ -821,6 +831,7 @@ func (r *snapshotReactor) checkGroupContents(expectedGroupContents []*crdv1beta1
 		sort.Strings(v.Spec.Source.VolumeHandles)
 		if v.Status != nil {
 			v.Status.CreationTime = nil
+			clearConditionTimes(v.Status.Conditions)
 		}
 		gotMap[v.Name] = v
 	}
@@ -833,6 +844,14 @@ func (r *snapshotReactor) checkGroupContents(expectedGroupContents []*crdv1beta1
 	return nil
 }
 
+// clearConditionTimes clears the transition times of conditions, which depend
+// on the time the test runs.
+func clearConditionTimes(conditions []metav1.Condition) {
+	for i := range conditions {
+		conditions[i].LastTransitionTime = metav1.Time{}
+	}
+}
+
 // checkSnapshots compares all expectedSnapshots with set of snapshots at the end of the
 // test and reports differences.
 func (r *snapshotReactor) checkSnapshots(expectedSnapshots []*crdv1.VolumeSnapshot) error {
@@ -848,6 +867,9 @@ func (r *snapshotReactor) checkSnapshots(expectedSnapshots []*crdv1.VolumeSnapsh
 		if c.Status != nil && c.Status.Error != nil {
 			c.Status.Error.Time = &metav1.Time{}
 		}
+		if c.Status != nil {
+			clearConditionTimes(c.Status.Conditions)
+		}
 		expectedMap[c.Name] = c
 	}
 	for _, c := range r.snapshots {
@@ -858,6 +880,9 @@ func (r *snapshotReactor) checkSnapshots(expectedSnapshots []*crdv1.VolumeSnapsh
 		if c.Status != nil && c.Status.Error != nil {
 			c.Status.Error.Time = &metav1.Time{}
 		}
+		if c.Status != nil {
+			clearConditionTimes(c.Status.Conditions)
+		}
 		gotMap[c.Name] = c
 	}
 	if !reflect.DeepEqual(expectedMap, gotMap) {
@@ -883,6 +908,9 @@ func (r *snapshotReactor) checkGroupSnapshots(expectedGroupSnapshots []*crdv1bet
 		if c.Status != nil && c.Status.Error != nil {
 			c.Status.Error.Time = &metav1.Time{}
 		}
+		if c.Status != nil {
+			clearConditionTimes(c.Status.Conditions)
+		}
 		expectedMap[c.Name] = c
 	}
 	for _, c := range r.groupSnapshots {
@@ -893,6 +921,9 @@ func (r *snapshotReactor) checkGroupSnapshots(expectedGroupSnapshots []*crdv1bet
 		if c.Status != nil && c.Status.Error != nil {
 			c.Status.Error.Time = &metav1.Time{}
 		}
+		if c.Status != nil {
+			clearConditionTimes(c.Status.Conditions)
+		}
 		gotMap[c.Name] = c
 	}
 	if !reflect.DeepEqual(expectedMap, gotMap) {
@@ -1203,6 +1234,8 @@ func newTestController(kubeClient kubernetes.Interface, clientset clientset.Inte
 		coreFactory.Core().V1().PersistentVolumeClaims(),
 		coreFactory.Core().V1().PersistentVolumes(),
 		nil,
+		nil,
+		nil,
 		metricsManager,
 		60*time.Second,
 		workqueue.NewTypedItemExponentialFailureRateLimiter[string](1*time.Millisecond, 1*time.Minute),
@@ -1212,6 +1245,7 @@ func newTestController(kubeClient kubernetes.Interface, clientset clientset.Inte
 		false,
 		false,
 		true,
+		nil,
 	)
 
 	ctrl.eventRecorder = record.NewFakeRecorder(1000)
@@ -1476,6 +1510,7 @@ func newSnapshot(
 	if targetContentName != "" {
 		snapshot.Spec.Source.VolumeSnapshotContentName = &targetContentName
 	}
+	utils.SetVolumeSnapshotConditions(&snapshot)
 	if withAllFinalizers {
 		return withSnapshotFinalizers([]*crdv1.VolumeSnapshot{&snapshot}, utils.VolumeSnapshotAsSourceFinalizer, utils.VolumeSnapshotBoundFinalizer)[0]
 	}
@@ -1525,6 +1560,7 @@ func newGroupSnapshot(
 	if targetContentName != "" {
 		groupSnapshot.Spec.Source.VolumeGroupSnapshotContentName = &targetContentName
 	}
+	utils.SetVolumeGroupSnapshotConditions(&groupSnapshot)
 	if withAllFinalizers {
 		return withGroupSnapshotFinalizers([]*crdv1beta1.VolumeGroupSnapshot{&groupSnapshot}, utils.VolumeGroupSnapshotContentFinalizer, utils.VolumeGroupSnapshotBoundFinalizer)[0]
 	}
@@ -1720,10 +1756,13 @@ func newVolumeCoupleArray(name, volumeUID, volumeHandle, capacity, boundToClaimU
 	}
 }
 
-func newVolumeError(message string) *crdv1.VolumeSnapshotError {
+func newVolumeError(message, reason string) *crdv1.VolumeSnapshotError {
+	final := false
 	return &crdv1.VolumeSnapshotError{
 		Time:    &metav1.Time{},
 		Message: &message,
+		Reason:  &reason,
+		Final:   &final,
 	}
 }
 
@@ -1809,7 +1848,7 @@ func testUpdateSnapshotClass(ctrl *csiSnapshotCommonController, reactor *snapsho
 }
 
 func testNewSnapshotContentCreation(ctrl *csiSnapshotCommonController, reactor *snapshotReactor, test controllerTest) error {
-	if err := ctrl.syncUnreadySnapshot(test.initialSnapshots[0]); err != nil {
+	if err := ctrl.syncUnreadySnapshot(context.TODO(), test.initialSnapshots[0]); err != nil {
 		return fmt.Errorf("syncUnreadySnapshot failed: %v", err)
 	}
 
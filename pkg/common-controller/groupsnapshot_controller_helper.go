@@ -72,7 +72,8 @@ func (ctrl *csiSnapshotCommonController) getGroupSnapshotClass(className string)
 //     if true, ReadyToUse will be set to false;
 //     otherwise, ReadyToUse will not be changed.
 //   - eventtype, reason, message - event to send, see EventRecorder.Event()
-func (ctrl *csiSnapshotCommonController) updateGroupSnapshotErrorStatusWithEvent(groupSnapshot *crdv1beta1.VolumeGroupSnapshot, setReadyToFalse bool, eventtype, reason, message string) error {
+//   - err - the error being reported, used to set the reason of the error status
+func (ctrl *csiSnapshotCommonController) updateGroupSnapshotErrorStatusWithEvent(groupSnapshot *crdv1beta1.VolumeGroupSnapshot, setReadyToFalse bool, eventtype, reason, message string, err error) error {
 	klog.V(5).Infof("updateGroupSnapshotErrorStatusWithEvent[%s]", utils.GroupSnapshotKey(groupSnapshot))
 
 	if groupSnapshot.Status != nil && groupSnapshot.Status.Error != nil && *groupSnapshot.Status.Error.Message == message {
@@ -83,18 +84,14 @@ func (ctrl *csiSnapshotCommonController) updateGroupSnapshotErrorStatusWithEvent
 	if groupSnapshotClone.Status == nil {
 		groupSnapshotClone.Status = &crdv1beta1.VolumeGroupSnapshotStatus{}
 	}
-	statusError := &crdv1.VolumeSnapshotError{
-		Time: &metav1.Time{
-			Time: time.Now(),
-		},
-		Message: &message,
-	}
+	statusError := utils.NewVolumeSnapshotError(message, snapshotErrorReason(reason), err)
 	groupSnapshotClone.Status.Error = statusError
 	// Only update ReadyToUse in VolumeGroupSnapshot's Status to false if setReadyToFalse is true.
 	if setReadyToFalse {
 		ready := false
 		groupSnapshotClone.Status.ReadyToUse = &ready
 	}
+	utils.SetVolumeGroupSnapshotConditions(groupSnapshotClone)
 	newSnapshot, err := ctrl.clientset.GroupsnapshotV1beta1().VolumeGroupSnapshots(groupSnapshotClone.Namespace).UpdateStatus(context.TODO(), groupSnapshotClone, metav1.UpdateOptions{})
 
 	// Emit the event even if the status update fails so that user can see the error
@@ -149,11 +146,11 @@ func (ctrl *csiSnapshotCommonController) SetDefaultGroupSnapshotClass(groupSnaps
 		}
 	}
 	if len(defaultClasses) == 0 {
-		return nil, groupSnapshot, fmt.Errorf("cannot find default group snapshot class")
+		return nil, groupSnapshot, utils.NewStatusError(crdv1.VolumeSnapshotErrorReasonClassError, fmt.Errorf("cannot find default group snapshot class"))
 	}
 	if len(defaultClasses) > 1 {
 		klog.V(4).Infof("get DefaultGroupSnapshotClass %d defaults found", len(defaultClasses))
-		return nil, groupSnapshot, fmt.Errorf("%d default snapshot classes were found", len(defaultClasses))
+		return nil, groupSnapshot, utils.NewStatusError(crdv1.VolumeSnapshotErrorReasonClassError, fmt.Errorf("%d default snapshot classes were found", len(defaultClasses)))
 	}
 	klog.V(5).Infof("setDefaultGroupSnapshotClass [%s]: default VolumeGroupSnapshotClassName [%s]", groupSnapshot.Name, defaultClasses[0].Name)
 	groupSnapshotClone := groupSnapshot.DeepCopy()
@@ -310,7 +307,7 @@ func (ctrl *csiSnapshotCommonController) syncGroupSnapshot(ctx context.Context,
 		(groupSnapshot.Spec.Source.Selector != nil && groupSnapshot.Spec.Source.VolumeGroupSnapshotContentName != nil) {
 		err := fmt.Errorf("Exactly one of Selector and VolumeGroupSnapshotContentName should be specified")
 		klog.Errorf("syncGroupSnapshot[%s]: validation error, %s", utils.GroupSnapshotKey(groupSnapshot), err.Error())
-		ctrl.updateGroupSnapshotErrorStatusWithEvent(groupSnapshot, true, v1.EventTypeWarning, "GroupSnapshotValidationError", err.Error())
+		ctrl.updateGroupSnapshotErrorStatusWithEvent(groupSnapshot, true, v1.EventTypeWarning, "GroupSnapshotValidationError", err.Error(), nil)
 		return err
 	}
 
@@ -347,13 +344,13 @@ func (ctrl *csiSnapshotCommonController) syncReadyGroupSnapshot(groupSnapshot *c
 	if groupSnapshotContent == nil {
 		// this meant there is no matching group snapshot content in cache found
 		// update status of the group snapshot and return
-		return ctrl.updateGroupSnapshotErrorStatusWithEvent(groupSnapshot, true, v1.EventTypeWarning, "GroupSnapshotContentMissing", "VolumeGroupSnapshotContent is missing")
+		return ctrl.updateGroupSnapshotErrorStatusWithEvent(groupSnapshot, true, v1.EventTypeWarning, "GroupSnapshotContentMissing", "VolumeGroupSnapshotContent is missing", nil)
 	}
 	klog.V(5).Infof("syncReadyGroupSnapshot[%s]: VolumeGroupSnapshotContent %q found", utils.GroupSnapshotKey(groupSnapshot), groupSnapshotContent.Name)
 	// check binding from group snapshot content side to make sure the binding is still valid
 	if !utils.IsVolumeGroupSnapshotRefSet(groupSnapshot, groupSnapshotContent) {
 		// group snapshot is bound but group snapshot content is not pointing to the group snapshot
-		return ctrl.updateGroupSnapshotErrorStatusWithEvent(groupSnapshot, true, v1.EventTypeWarning, "GroupSnapshotMisbound", "VolumeGroupSnapshotContent is not bound to the VolumeGroupSnapshot correctly")
+		return ctrl.updateGroupSnapshotErrorStatusWithEvent(groupSnapshot, true, v1.EventTypeWarning, "GroupSnapshotMisbound", "VolumeGroupSnapshotContent is not bound to the VolumeGroupSnapshot correctly", nil)
 	}
 
 	// everything is verified, return
@@ -420,7 +417,7 @@ func (ctrl *csiSnapshotCommonController) syncUnreadyGroupSnapshot(ctx context.Co
 		// if no group snapshot content found yet, update status and return
 		if groupSnapshotContent == nil {
 			// can not find the desired VolumeGroupSnapshotContent from cache store
-			ctrl.updateGroupSnapshotErrorStatusWithEvent(groupSnapshot, true, v1.EventTypeWarning, "GroupSnapshotContentMissing", "VolumeGroupSnapshotContent is missing")
+			ctrl.updateGroupSnapshotErrorStatusWithEvent(groupSnapshot, true, v1.EventTypeWarning, "GroupSnapshotContentMissing", "VolumeGroupSnapshotContent is missing", nil)
 			klog.V(4).Infof("syncUnreadyGroupSnapshot[%s]: group snapshot content %q requested but not found, will try again", utils.GroupSnapshotKey(groupSnapshot), *groupSnapshot.Spec.Source.VolumeGroupSnapshotContentName)
 
 			return fmt.Errorf("group snapshot %s requests an non-existing group snapshot content %s", utils.GroupSnapshotKey(groupSnapshot), *groupSnapshot.Spec.Source.VolumeGroupSnapshotContentName)
@@ -430,7 +427,7 @@ func (ctrl *csiSnapshotCommonController) syncUnreadyGroupSnapshot(ctx context.Co
 		newGroupSnapshotContent, err := ctrl.checkAndBindGroupSnapshotContent(groupSnapshot, groupSnapshotContent)
 		if err != nil {
 			// group snapshot is bound but group snapshot content is not bound to group snapshot correctly
-			ctrl.updateGroupSnapshotErrorStatusWithEvent(groupSnapshot, true, v1.EventTypeWarning, "GroupSnapshotBindFailed", fmt.Sprintf("GroupSnapshot failed to bind VolumeGroupSnapshotContent, %v", err))
+			ctrl.updateGroupSnapshotErrorStatusWithEvent(groupSnapshot, true, v1.EventTypeWarning, "GroupSnapshotBindFailed", fmt.Sprintf("GroupSnapshot failed to bind VolumeGroupSnapshotContent, %v", err), nil)
 			return fmt.Errorf("group snapshot %s is bound, but VolumeGroupSnapshotContent %s is not bound to the VolumeGroupSnapshot correctly, %v", uniqueGroupSnapshotName, groupSnapshotContent.Name, err)
 		}
 
@@ -439,7 +436,7 @@ func (ctrl *csiSnapshotCommonController) syncUnreadyGroupSnapshot(ctx context.Co
 		if _, err = ctrl.updateGroupSnapshotStatus(groupSnapshot, newGroupSnapshotContent); err != nil {
 			// update group snapshot status failed
 			klog.V(4).Infof("failed to update group snapshot %s status: %v", utils.GroupSnapshotKey(groupSnapshot), err)
-			ctrl.updateGroupSnapshotErrorStatusWithEvent(groupSnapshot, false, v1.EventTypeWarning, "GroupSnapshotStatusUpdateFailed", fmt.Sprintf("GroupSnapshot status update failed, %v", err))
+			ctrl.updateGroupSnapshotErrorStatusWithEvent(groupSnapshot, false, v1.EventTypeWarning, "GroupSnapshotStatusUpdateFailed", fmt.Sprintf("GroupSnapshot status update failed, %v", err), nil)
 			return err
 		}
 
@@ -457,7 +454,7 @@ func (ctrl *csiSnapshotCommonController) syncUnreadyGroupSnapshot(ctx context.Co
 	if contentObj != nil {
 		klog.V(5).Infof("Found VolumeGroupSnapshotContent object %s for group snapshot %s", contentObj.Name, uniqueGroupSnapshotName)
 		if contentObj.Spec.Source.GroupSnapshotHandles != nil {
-			ctrl.updateGroupSnapshotErrorStatusWithEvent(groupSnapshot, true, v1.EventTypeWarning, "GroupSnapshotHandleSet", fmt.Sprintf("GroupSnapshot handle should not be set in group snapshot content %s for dynamic provisioning", uniqueGroupSnapshotName))
+			ctrl.updateGroupSnapshotErrorStatusWithEvent(groupSnapshot, true, v1.EventTypeWarning, "GroupSnapshotHandleSet", fmt.Sprintf("GroupSnapshot handle should not be set in group snapshot content %s for dynamic provisioning", uniqueGroupSnapshotName), nil)
 			return fmt.Errorf("VolumeGroupSnapshotHandle should not be set in the group snapshot content for dynamic provisioning for group snapshot %s", uniqueGroupSnapshotName)
 		}
 
@@ -480,7 +477,7 @@ func (ctrl *csiSnapshotCommonController) syncUnreadyGroupSnapshot(ctx context.Co
 	// If reach here, it is a dynamically provisioned group snapshot, and the VolumeGroupSnapshotContent object is not yet created.
 	var groupSnapshotContent *crdv1beta1.VolumeGroupSnapshotContent
 	if groupSnapshotContent, err = ctrl.createGroupSnapshotContent(groupSnapshot); err != nil {
-		ctrl.updateGroupSnapshotErrorStatusWithEvent(groupSnapshot, true, v1.EventTypeWarning, "GroupSnapshotContentCreationFailed", fmt.Sprintf("failed to create group snapshot content with error %v", err))
+		ctrl.updateGroupSnapshotErrorStatusWithEvent(groupSnapshot, true, v1.EventTypeWarning, "GroupSnapshotContentCreationFailed", fmt.Sprintf("failed to create group snapshot content with error %v", err), err)
 		return err
 	}
 
@@ -488,7 +485,7 @@ func (ctrl *csiSnapshotCommonController) syncUnreadyGroupSnapshot(ctx context.Co
 	klog.V(5).Infof("syncUnreadyGroupSnapshot [%s]: trying to update group snapshot status", utils.GroupSnapshotKey(groupSnapshot))
 	if _, err = ctrl.updateGroupSnapshotStatus(groupSnapshot, groupSnapshotContent); err != nil {
 		// update group snapshot status failed
-		ctrl.updateGroupSnapshotErrorStatusWithEvent(groupSnapshot, false, v1.EventTypeWarning, "GroupSnapshotStatusUpdateFailed", fmt.Sprintf("GroupSnapshot status update failed, %v", err))
+		ctrl.updateGroupSnapshotErrorStatusWithEvent(groupSnapshot, false, v1.EventTypeWarning, "GroupSnapshotStatusUpdateFailed", fmt.Sprintf("GroupSnapshot status update failed, %v", err), nil)
 		return err
 	}
 	return nil
@@ -525,7 +522,7 @@ func (ctrl *csiSnapshotCommonController) createSnapshotsForGroupSnapshotContent(
 			*groupSnapshot.Spec.VolumeGroupSnapshotClassName, err)
 	}
 
-	groupSnapshotSecret, err := utils.GetGroupSnapshotSecretReference(
+	groupSnapshotCredentials, err := utils.GetGroupSnapshotCredentialsReference(
 		utils.GroupSnapshotterSecretParams,
 		groupSnapshotClass.Parameters,
 		groupSnapshotContent.GetObjectMeta().GetName(), nil)
@@ -588,12 +585,9 @@ func (ctrl *csiSnapshotCommonController) createSnapshotsForGroupSnapshotContent(
 			volumeSnapshotContent.Spec.SourceVolumeMode = pv.Spec.VolumeMode
 		}
 
-		if groupSnapshotSecret != nil {
-			klog.V(5).Infof("createSnapshotsForGroupSnapshotContent: set annotation [%s] on volume snapshot content [%s].", utils.AnnDeletionSecretRefName, volumeSnapshotContent.Name)
-			metav1.SetMetaDataAnnotation(&volumeSnapshotContent.ObjectMeta, utils.AnnDeletionSecretRefName, groupSnapshotSecret.Name)
-
-			klog.V(5).Infof("createSnapshotsForGroupSnapshotContent: set annotation [%s] on volume snapshot content [%s].", utils.AnnDeletionSecretRefNamespace, volumeSnapshotContent.Name)
-			metav1.SetMetaDataAnnotation(&volumeSnapshotContent.ObjectMeta, utils.AnnDeletionSecretRefNamespace, groupSnapshotSecret.Namespace)
+		if groupSnapshotCredentials != nil {
+			klog.V(5).Infof("createSnapshotsForGroupSnapshotContent: set credentials annotations %s on volume snapshot content [%s].", groupSnapshotCredentials, volumeSnapshotContent.Name)
+			utils.SetCredentialsAnnotations(&volumeSnapshotContent.ObjectMeta, groupSnapshotCredentials, utils.AnnDeletionSecretRefName, utils.AnnDeletionSecretRefNamespace, utils.AnnDeletionCredentials)
 		}
 
 		volumeSnapshot := &crdv1.VolumeSnapshot{
@@ -646,6 +640,11 @@ func (ctrl *csiSnapshotCommonController) createSnapshotsForGroupSnapshotContent(
 
 		// bind the volume snapshot to the volume snapshot content
 		// like a dynamically provisioned snapshot would do
+		boundVolumeSnapshot := createdVolumeSnapshot.DeepCopy()
+		boundVolumeSnapshot.Status = &crdv1.VolumeSnapshotStatus{
+			BoundVolumeSnapshotContentName: &volumeSnapshotContentName,
+		}
+		utils.SetVolumeSnapshotConditions(boundVolumeSnapshot)
 		_, err = utils.PatchVolumeSnapshot(createdVolumeSnapshot, []utils.PatchOp{
 			{
 				Op:    "replace",
@@ -657,6 +656,11 @@ func (ctrl *csiSnapshotCommonController) createSnapshotsForGroupSnapshotContent(
 				Path:  "/status/boundVolumeSnapshotContentName",
 				Value: volumeSnapshotContentName,
 			},
+			{
+				Op:    "add",
+				Path:  "/status/conditions",
+				Value: boundVolumeSnapshot.Status.Conditions,
+			},
 		}, ctrl.clientset, "status")
 		if err != nil {
 			return groupSnapshotContent, fmt.Errorf(
@@ -766,7 +770,7 @@ func (ctrl *csiSnapshotCommonController) getPreprovisionedGroupSnapshotContentFr
 	if groupSnapshotContent.Spec.Source.GroupSnapshotHandles == nil {
 		// found a group snapshot content which represents a dynamically provisioned group snapshot
 		// update the group snapshot and return an error
-		ctrl.updateGroupSnapshotErrorStatusWithEvent(groupSnapshot, true, v1.EventTypeWarning, "GroupSnapshotContentMismatch", "VolumeGroupSnapshotContent is dynamically provisioned while expecting a pre-provisioned one")
+		ctrl.updateGroupSnapshotErrorStatusWithEvent(groupSnapshot, true, v1.EventTypeWarning, "GroupSnapshotContentMismatch", "VolumeGroupSnapshotContent is dynamically provisioned while expecting a pre-provisioned one", nil)
 		klog.V(4).Infof("sync group snapshot[%s]: group snapshot content %q is dynamically provisioned while expecting a pre-provisioned one", utils.GroupSnapshotKey(groupSnapshot), contentName)
 		return nil, fmt.Errorf("group snapshot %s expects a pre-provisioned VolumeGroupSnapshotContent %s but gets a dynamically provisioned one", utils.GroupSnapshotKey(groupSnapshot), contentName)
 	}
@@ -775,7 +779,7 @@ func (ctrl *csiSnapshotCommonController) getPreprovisionedGroupSnapshotContentFr
 	if ref.Name != groupSnapshot.Name || ref.Namespace != groupSnapshot.Namespace || (ref.UID != "" && ref.UID != groupSnapshot.UID) {
 		klog.V(4).Infof("sync group snapshot[%s]: VolumeGroupSnapshotContent %s is bound to another group snapshot %v", utils.GroupSnapshotKey(groupSnapshot), contentName, ref)
 		msg := fmt.Sprintf("VolumeGroupSnapshotContent [%s] is bound to a different group snapshot", contentName)
-		ctrl.updateGroupSnapshotErrorStatusWithEvent(groupSnapshot, true, v1.EventTypeWarning, "GroupSnapshotContentMisbound", msg)
+		ctrl.updateGroupSnapshotErrorStatusWithEvent(groupSnapshot, true, v1.EventTypeWarning, "GroupSnapshotContentMisbound", msg, nil)
 		return nil, fmt.Errorf(msg)
 	}
 	return groupSnapshotContent, nil
@@ -842,6 +846,11 @@ func (ctrl *csiSnapshotCommonController) updateGroupSnapshotStatus(groupSnapshot
 	if groupSnapshotContent.Status != nil && groupSnapshotContent.Status.Error != nil {
 		volumeSnapshotErr = groupSnapshotContent.Status.Error.DeepCopy()
 	}
+	var emulated *bool
+	if groupSnapshotContent.Status != nil && groupSnapshotContent.Status.Emulated != nil {
+		value := *groupSnapshotContent.Status.Emulated
+		emulated = &value
+	}
 
 	klog.V(5).Infof("updateGroupSnapshotStatus: updating VolumeGroupSnapshot [%+v] based on VolumeGroupSnapshotContentStatus [%+v]", groupSnapshot, groupSnapshotContent.Status)
 
@@ -863,6 +872,7 @@ func (ctrl *csiSnapshotCommonController) updateGroupSnapshotStatus(groupSnapshot
 		if volumeSnapshotErr != nil {
 			newStatus.Error = volumeSnapshotErr
 		}
+		newStatus.Emulated = emulated
 
 		updated = true
 	} else {
@@ -882,16 +892,23 @@ func (ctrl *csiSnapshotCommonController) updateGroupSnapshotStatus(groupSnapshot
 				newStatus.Error = nil
 			}
 		}
+		if newStatus.Emulated == nil && emulated != nil {
+			newStatus.Emulated = emulated
+			updated = true
+		}
 		if (newStatus.Error == nil && volumeSnapshotErr != nil) || (newStatus.Error != nil && volumeSnapshotErr != nil && newStatus.Error.Time != nil && volumeSnapshotErr.Time != nil && &newStatus.Error.Time != &volumeSnapshotErr.Time) || (newStatus.Error != nil && volumeSnapshotErr == nil) {
 			newStatus.Error = volumeSnapshotErr
 			updated = true
 		}
 	}
 
-	if updated {
-		groupSnapshotClone := groupSnapshotObj.DeepCopy()
-		groupSnapshotClone.Status = newStatus
+	groupSnapshotClone := groupSnapshotObj.DeepCopy()
+	groupSnapshotClone.Status = newStatus
+	if utils.SetVolumeGroupSnapshotConditions(groupSnapshotClone) {
+		updated = true
+	}
 
+	if updated {
 		// We need to record metrics before updating the status due to a bug causing cache entries after a failed UpdateStatus call.
 		// Must meet the following criteria to emit a successful CreateGroupSnapshot status
 		// 1. Previous status was nil OR Previous status had a nil CreationTime
@@ -954,7 +971,7 @@ func (ctrl *csiSnapshotCommonController) getDynamicallyProvisionedGroupContentFr
 	}
 	// check whether the group snapshot content represents a dynamically provisioned snapshot
 	if groupSnapshotContent.Spec.Source.GroupSnapshotHandles != nil {
-		ctrl.updateGroupSnapshotErrorStatusWithEvent(groupSnapshot, true, v1.EventTypeWarning, "GroupSnapshotContentMismatch", "VolumeGroupSnapshotContent "+contentName+" is pre-provisioned while expecting a dynamically provisioned one")
+		ctrl.updateGroupSnapshotErrorStatusWithEvent(groupSnapshot, true, v1.EventTypeWarning, "GroupSnapshotContentMismatch", "VolumeGroupSnapshotContent "+contentName+" is pre-provisioned while expecting a dynamically provisioned one", nil)
 		klog.V(4).Infof("sync group snapshot[%s]: group snapshot content %s is pre-provisioned while expecting a dynamically provisioned one", utils.GroupSnapshotKey(groupSnapshot), contentName)
 		return nil, fmt.Errorf("group snapshot %s expects a dynamically provisioned VolumeGroupSnapshotContent %s but gets a pre-provisioned one", utils.GroupSnapshotKey(groupSnapshot), contentName)
 	}
@@ -967,7 +984,7 @@ func (ctrl *csiSnapshotCommonController) getDynamicallyProvisionedGroupContentFr
 	if ref.Name != groupSnapshot.Name || ref.Namespace != groupSnapshot.Namespace || ref.UID != groupSnapshot.UID {
 		klog.V(4).Infof("sync group snapshot[%s]: VolumeGroupSnapshotContent %s is bound to another group snapshot %v", utils.GroupSnapshotKey(groupSnapshot), contentName, ref)
 		msg := fmt.Sprintf("VolumeGroupSnapshotContent [%s] is bound to a different group snapshot", contentName)
-		ctrl.updateGroupSnapshotErrorStatusWithEvent(groupSnapshot, true, v1.EventTypeWarning, "GroupSnapshotContentMisbound", msg)
+		ctrl.updateGroupSnapshotErrorStatusWithEvent(groupSnapshot, true, v1.EventTypeWarning, "GroupSnapshotContentMisbound", msg, nil)
 		return nil, fmt.Errorf(msg)
 	}
 	return groupSnapshotContent, nil
@@ -993,7 +1010,7 @@ func (ctrl *csiSnapshotCommonController) bindandUpdateVolumeGroupSnapshot(groupS
 	if err != nil {
 		// update group snapshot status failed
 		klog.V(4).Infof("failed to update group snapshot %s status: %v", utils.GroupSnapshotKey(groupSnapshot), err)
-		ctrl.updateGroupSnapshotErrorStatusWithEvent(groupSnapshotCopy, true, v1.EventTypeWarning, "GroupSnapshotStatusUpdateFailed", fmt.Sprintf("GroupSnapshot status update failed, %v", err))
+		ctrl.updateGroupSnapshotErrorStatusWithEvent(groupSnapshotCopy, true, v1.EventTypeWarning, "GroupSnapshotStatusUpdateFailed", fmt.Sprintf("GroupSnapshot status update failed, %v", err), nil)
 		return nil, err
 	}
 
@@ -1014,9 +1031,9 @@ func (ctrl *csiSnapshotCommonController) createGroupSnapshotContent(groupSnapsho
 		TODO: Add PVC finalizer
 	*/
 
-	groupSnapshotClass, volumes, contentName, snapshotterSecretRef, err := ctrl.getCreateGroupSnapshotInput(groupSnapshot)
+	groupSnapshotClass, volumes, contentName, snapshotterCredentialsRef, err := ctrl.getCreateGroupSnapshotInput(groupSnapshot)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get input parameters to create group snapshot %s: %q", groupSnapshot.Name, err)
+		return nil, utils.WrapStatusError(err, fmt.Errorf("failed to get input parameters to create group snapshot %s: %q", groupSnapshot.Name, err))
 	}
 
 	snapshotRef, err := ref.GetReference(scheme.Scheme, groupSnapshot)
@@ -1042,17 +1059,17 @@ func (ctrl *csiSnapshotCommonController) createGroupSnapshotContent(groupSnapsho
 		volumeCSIDriver := pv.Spec.CSI.Driver
 		classCSIDriver := groupSnapshotClass.Driver
 		if volumeCSIDriver != classCSIDriver {
-			strErr := fmt.Sprintf(
+			err := fmt.Errorf(
 				"Volume CSI driver (%s) mismatch with VolumeGroupSnapshotClass (%s) %s: %s",
 				volumeCSIDriver, classCSIDriver, utils.GroupSnapshotKey(groupSnapshot), pv.Name)
-			klog.Error(strErr)
+			klog.Error(err.Error())
 			ctrl.eventRecorder.Event(
 				groupSnapshot,
 				v1.EventTypeWarning,
 				"CreateGroupSnapshotContentFailed",
-				strErr,
+				err.Error(),
 			)
-			return nil, newControllerUpdateError(utils.GroupSnapshotKey(groupSnapshot), strErr)
+			return nil, utils.NewStatusError(crdv1.VolumeSnapshotErrorReasonClassError, err)
 
 		}
 		volumeHandles = append(volumeHandles, pv.Spec.CSI.VolumeHandle)
@@ -1076,12 +1093,9 @@ func (ctrl *csiSnapshotCommonController) createGroupSnapshotContent(groupSnapsho
 	/*
 		Add secret reference details
 	*/
-	if snapshotterSecretRef != nil {
-		klog.V(5).Infof("createGroupSnapshotContent: set annotation [%s] on volume group snapshot content [%s].", utils.AnnDeletionGroupSecretRefName, groupSnapshotContent.Name)
-		metav1.SetMetaDataAnnotation(&groupSnapshotContent.ObjectMeta, utils.AnnDeletionGroupSecretRefName, snapshotterSecretRef.Name)
-
-		klog.V(5).Infof("creategroupSnapshotContent: set annotation [%s] on volume group snapshot content [%s].", utils.AnnDeletionGroupSecretRefNamespace, groupSnapshotContent.Name)
-		metav1.SetMetaDataAnnotation(&groupSnapshotContent.ObjectMeta, utils.AnnDeletionGroupSecretRefNamespace, snapshotterSecretRef.Namespace)
+	if snapshotterCredentialsRef != nil {
+		klog.V(5).Infof("createGroupSnapshotContent: set credentials annotations %s on volume group snapshot content [%s].", snapshotterCredentialsRef, groupSnapshotContent.Name)
+		utils.SetCredentialsAnnotations(&groupSnapshotContent.ObjectMeta, snapshotterCredentialsRef, utils.AnnDeletionGroupSecretRefName, utils.AnnDeletionGroupSecretRefNamespace, utils.AnnDeletionGroupCredentials)
 	}
 
 	var updateGroupSnapshotContent *crdv1beta1.VolumeGroupSnapshotContent
@@ -1118,7 +1132,7 @@ func (ctrl *csiSnapshotCommonController) createGroupSnapshotContent(groupSnapsho
 	return updateGroupSnapshotContent, nil
 }
 
-func (ctrl *csiSnapshotCommonController) getCreateGroupSnapshotInput(groupSnapshot *crdv1beta1.VolumeGroupSnapshot) (*crdv1beta1.VolumeGroupSnapshotClass, []*v1.PersistentVolume, string, *v1.SecretReference, error) {
+func (ctrl *csiSnapshotCommonController) getCreateGroupSnapshotInput(groupSnapshot *crdv1beta1.VolumeGroupSnapshot) (*crdv1beta1.VolumeGroupSnapshotClass, []*v1.PersistentVolume, string, *utils.CredentialsReference, error) {
 	className := groupSnapshot.Spec.VolumeGroupSnapshotClassName
 	klog.V(5).Infof("getCreateGroupSnapshotInput [%s]", groupSnapshot.Name)
 	var groupSnapshotClass *crdv1beta1.VolumeGroupSnapshotClass
@@ -1127,11 +1141,11 @@ func (ctrl *csiSnapshotCommonController) getCreateGroupSnapshotInput(groupSnapsh
 		groupSnapshotClass, err = ctrl.getGroupSnapshotClass(*className)
 		if err != nil {
 			klog.Errorf("getCreateGroupSnapshotInput failed to getClassFromVolumeGroupSnapshot %s", err)
-			return nil, nil, "", nil, err
+			return nil, nil, "", nil, utils.NewStatusError(crdv1.VolumeSnapshotErrorReasonClassError, err)
 		}
 	} else {
 		klog.Errorf("failed to getCreateGroupSnapshotInput %s without a group snapshot class", groupSnapshot.Name)
-		return nil, nil, "", nil, fmt.Errorf("failed to take group snapshot %s without a group snapshot class", groupSnapshot.Name)
+		return nil, nil, "", nil, utils.NewStatusError(crdv1.VolumeSnapshotErrorReasonClassError, fmt.Errorf("failed to take group snapshot %s without a group snapshot class", groupSnapshot.Name))
 	}
 
 	volumes, err := ctrl.getVolumesFromVolumeGroupSnapshot(groupSnapshot)
@@ -1144,12 +1158,12 @@ func (ctrl *csiSnapshotCommonController) getCreateGroupSnapshotInput(groupSnapsh
 	contentName := utils.GetDynamicSnapshotContentNameForGroupSnapshot(groupSnapshot)
 
 	// Get the secret reference
-	snapshotterSecretRef, err := utils.GetGroupSnapshotSecretReference(utils.GroupSnapshotterSecretParams, groupSnapshotClass.Parameters, contentName, groupSnapshot)
+	snapshotterCredentialsRef, err := utils.GetGroupSnapshotCredentialsReference(utils.GroupSnapshotterSecretParams, groupSnapshotClass.Parameters, contentName, groupSnapshot)
 	if err != nil {
-		return nil, nil, "", nil, err
+		return nil, nil, "", nil, utils.NewStatusError(crdv1.VolumeSnapshotErrorReasonSecretError, err)
 	}
 
-	return groupSnapshotClass, volumes, contentName, snapshotterSecretRef, nil
+	return groupSnapshotClass, volumes, contentName, snapshotterCredentialsRef, nil
 }
 
 // syncGroupSnapshotContent deals with one key off the queue
@@ -1368,6 +1382,24 @@ func (ctrl *csiSnapshotCommonController) addGroupSnapshotFinalizer(groupSnapshot
 	return nil
 }
 
+// setGroupSnapshotDeletionBlocked sets the DeletionBlocked condition of a group
+// snapshot whose deletion waits for other objects.
+func (ctrl *csiSnapshotCommonController) setGroupSnapshotDeletionBlocked(groupSnapshot *crdv1beta1.VolumeGroupSnapshot, reason, message string) error {
+	groupSnapshotClone := groupSnapshot.DeepCopy()
+	if groupSnapshotClone.Status == nil {
+		groupSnapshotClone.Status = &crdv1beta1.VolumeGroupSnapshotStatus{}
+	}
+	if !utils.SetDeletionBlockedCondition(&groupSnapshotClone.Status.Conditions, groupSnapshot.Generation, reason, message) {
+		return nil
+	}
+	newGroupSnapshot, err := ctrl.clientset.GroupsnapshotV1beta1().VolumeGroupSnapshots(groupSnapshotClone.Namespace).UpdateStatus(context.TODO(), groupSnapshotClone, metav1.UpdateOptions{})
+	if err != nil {
+		return newControllerUpdateError(utils.GroupSnapshotKey(groupSnapshot), err.Error())
+	}
+	_, err = ctrl.storeGroupSnapshotUpdate(newGroupSnapshot)
+	return err
+}
+
 // processGroupSnapshotWithDeletionTimestamp processes finalizers and deletes the
 // group snapshot content when appropriate. It has the following steps:
 // 1. Get the VolumeGroupSnapshotContent which the to-be-deleted VolumeGroupSnapshot
@@ -1466,7 +1498,7 @@ func (ctrl *csiSnapshotCommonController) processGroupSnapshotWithDeletionTimesta
 			klog.V(4).Info(msg)
 			ctrl.eventRecorder.Event(groupSnapshot, v1.EventTypeWarning, "SnapshotDeletePending", msg)
 			// TODO(@xiangqian): should requeue this?
-			return nil
+			return ctrl.setGroupSnapshotDeletionBlocked(groupSnapshot, crdv1.VolumeSnapshotReasonUsedAsSource, msg)
 		}
 
 	}
@@ -110,7 +110,7 @@ func TestCreateGroupSnapshotSync(t *testing.T) {
 					"app.kubernetes.io/name": "postgresql",
 				},
 				"", classNonExisting, "", &False, nil,
-				newVolumeError(`failed to create group snapshot content with error failed to get input parameters to create group snapshot group-snap-1-1: "volumegroupsnapshotclass.groupsnapshot.storage.k8s.io \"non-existing\" not found"`),
+				newVolumeError(`failed to create group snapshot content with error failed to get input parameters to create group snapshot group-snap-1-1: "volumegroupsnapshotclass.groupsnapshot.storage.k8s.io \"non-existing\" not found"`, crdv1.VolumeSnapshotErrorReasonClassError),
 				false, false, nil,
 			),
 			initialGroupContents:  nogroupcontents,
@@ -138,7 +138,7 @@ func TestCreateGroupSnapshotSync(t *testing.T) {
 					"app.kubernetes.io/name": "postgresql",
 				},
 				"", "", "", &False, nil,
-				newVolumeError(`failed to create group snapshot content with error failed to get input parameters to create group snapshot group-snap-1-1: "failed to take group snapshot group-snap-1-1 without a group snapshot class"`),
+				newVolumeError(`failed to create group snapshot content with error failed to get input parameters to create group snapshot group-snap-1-1: "failed to take group snapshot group-snap-1-1 without a group snapshot class"`, crdv1.VolumeSnapshotErrorReasonClassError),
 				false, false, nil,
 			),
 			initialGroupContents:  nogroupcontents,
@@ -166,7 +166,7 @@ func TestCreateGroupSnapshotSync(t *testing.T) {
 					"app.kubernetes.io/name": "postgresql",
 				},
 				"", classGold, "", &False, nil,
-				newVolumeError(`failed to create group snapshot content with error failed to get input parameters to create group snapshot group-snap-1-1: "label selector app.kubernetes.io/name=postgresql for group snapshot not applied to any PVC"`),
+				newVolumeError(`failed to create group snapshot content with error failed to get input parameters to create group snapshot group-snap-1-1: "label selector app.kubernetes.io/name=postgresql for group snapshot not applied to any PVC"`, "GroupSnapshotContentCreationFailed"),
 				false, false, nil,
 			),
 			initialGroupContents:  nogroupcontents,
@@ -190,7 +190,7 @@ func TestCreateGroupSnapshotSync(t *testing.T) {
 					"app.kubernetes.io/name": "postgresql",
 				},
 				"", classGold, "", &False, nil,
-				newVolumeError(`failed to create group snapshot content with error failed to get input parameters to create group snapshot group-snap-1-1: "the PVC claim1-1 is not yet bound to a PV, will not attempt to take a group snapshot"`),
+				newVolumeError(`failed to create group snapshot content with error failed to get input parameters to create group snapshot group-snap-1-1: "the PVC claim1-1 is not yet bound to a PV, will not attempt to take a group snapshot"`, "GroupSnapshotContentCreationFailed"),
 				false, false, nil,
 			),
 			initialGroupContents:  nogroupcontents,
@@ -218,7 +218,7 @@ func TestCreateGroupSnapshotSync(t *testing.T) {
 					"app.kubernetes.io/name": "postgresql",
 				},
 				"", classGold, "", &False, nil,
-				newVolumeError("failed to create group snapshot content with error cannot snapshot a non-CSI volume for group snapshot default/group-snap-1-1: volume6-1"),
+				newVolumeError("failed to create group snapshot content with error cannot snapshot a non-CSI volume for group snapshot default/group-snap-1-1: volume6-1", "GroupSnapshotContentCreationFailed"),
 				false, false, nil,
 			),
 			initialGroupContents:  nogroupcontents,
@@ -249,7 +249,7 @@ func TestCreateGroupSnapshotSync(t *testing.T) {
 					"app.kubernetes.io/name": "postgresql",
 				},
 				"", classGold, "", &False, nil,
-				newVolumeError("failed to create group snapshot content with error snapshot controller failed to update default/group-snap-1-1 on API server: Volume CSI driver (test.csi.driver.name) mismatch with VolumeGroupSnapshotClass (csi-mock-plugin) default/group-snap-1-1: volume6-1"),
+				newVolumeError("failed to create group snapshot content with error Volume CSI driver (test.csi.driver.name) mismatch with VolumeGroupSnapshotClass (csi-mock-plugin) default/group-snap-1-1: volume6-1", crdv1.VolumeSnapshotErrorReasonClassError),
 				false, false, nil,
 			),
 			initialGroupContents:  nogroupcontents,
@@ -304,7 +304,7 @@ func TestCreateGroupSnapshotSync(t *testing.T) {
 			expectedGroupSnapshots: newGroupSnapshotArray(
 				"group-snap-1-1", "group-snapuid1-1", nil,
 				"groupsnapcontent-snapuid1-1", classGold, "", &False, nil,
-				newVolumeError(`VolumeGroupSnapshotContent is missing`),
+				newVolumeError(`VolumeGroupSnapshotContent is missing`, "GroupSnapshotContentMissing"),
 				false, false, nil,
 			),
 			initialGroupContents:  nogroupcontents,
@@ -327,7 +327,7 @@ func TestCreateGroupSnapshotSync(t *testing.T) {
 			),
 			expectedGroupSnapshots: newGroupSnapshotArray(
 				"group-snap-1-1", "group-snapuid1-1", nil,
-				"groupsnapcontent-snapuid1-1", classGold, "", &False, nil, newVolumeError(`VolumeGroupSnapshotContent [groupsnapcontent-snapuid1-1] is bound to a different group snapshot`), false, false, nil,
+				"groupsnapcontent-snapuid1-1", classGold, "", &False, nil, newVolumeError(`VolumeGroupSnapshotContent [groupsnapcontent-snapuid1-1] is bound to a different group snapshot`, "GroupSnapshotContentMisbound"), false, false, nil,
 			),
 			initialGroupContents: newGroupSnapshotContentArray(
 				"groupsnapcontent-snapuid1-1", "group-snapuid1-1", "group-wrong-snap-1-1", "", classGold, nil,
@@ -355,7 +355,7 @@ func TestCreateGroupSnapshotSync(t *testing.T) {
 			),
 			expectedGroupSnapshots: newGroupSnapshotArray(
 				"group-snap-1-1", "group-snapuid1-1", nil,
-				"groupsnapcontent-snapuid1-1", classGold, "", &False, nil, newVolumeError(`VolumeGroupSnapshotContent is dynamically provisioned while expecting a pre-provisioned one`), false, false, nil,
+				"groupsnapcontent-snapuid1-1", classGold, "", &False, nil, newVolumeError(`VolumeGroupSnapshotContent is dynamically provisioned while expecting a pre-provisioned one`, "GroupSnapshotContentMismatch"), false, false, nil,
 			),
 			initialGroupContents: newGroupSnapshotContentArray(
 				"groupsnapcontent-snapuid1-1", "group-snapuid1-1", "group-snap-1-1", "", classGold, nil,
@@ -0,0 +1,322 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common_controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	crdv1alpha1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1alpha1"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	klog "k8s.io/klog/v2"
+)
+
+// Design:
+//
+// A VolumeSnapshotRetentionPolicy is evaluated separately for the snapshots
+// of every source PVC selected by spec.selector. Only snapshots that are
+// ready to use take part in the evaluation: a snapshot that is still being
+// cut must neither be deleted nor take the place of an older, usable one.
+// Snapshots of a VolumeGroupSnapshot belong to their group and are ignored.
+//
+// The keep rules work like the ones of common backup tools. The snapshots
+// of a PVC are ordered newest first, every rule marks the snapshots it keeps
+// and whatever is not marked by any rule is deleted:
+//   - keepLast keeps the newest N snapshots.
+//   - keepWithin keeps the snapshots taken within the duration before now.
+//   - keepHourly, keepDaily and keepWeekly keep the newest snapshot of each
+//     of the N most recent hours, days and ISO weeks that have a snapshot.
+//     All buckets are computed in UTC.
+//
+// A snapshot that is the data source of a PVC being provisioned is kept,
+// and the policy is synced again with a backoff. The deletion itself is done by removing the
+// VolumeSnapshot object, the common controller then deletes or retains the
+// content according to its deletion policy.
+
+// syncPolicy deletes the VolumeSnapshots that are not kept by a retention
+// policy and updates the policy status. It returns the duration after which
+// the policy must be synced again because a snapshot kept by keepWithin
+// falls out of the window; zero means the policy does not need to be
+// requeued until it or one of its snapshots is changed.
+func (ctrl *snapshotRetentionController) syncPolicy(policy *crdv1alpha1.VolumeSnapshotRetentionPolicy) (time.Duration, error) {
+	klog.V(5).Infof("synchronizing VolumeSnapshotRetentionPolicy[%s/%s]", policy.Namespace, policy.Name)
+
+	now := ctrl.now()
+	if !hasKeepRule(&policy.Spec) {
+		// Deleting all the selected snapshots is never what the user
+		// wants, so a policy that keeps nothing is rejected.
+		ctrl.updatePolicyErrorStatusWithEvent(policy, now, "InvalidRetentionPolicy", "at least one of keepLast, keepWithin, keepHourly, keepDaily and keepWeekly must keep snapshots")
+		return 0, nil
+	}
+	groups, err := ctrl.getRetentionCandidates(policy)
+	if err != nil {
+		// Nothing will change until the user fixes the spec, which
+		// enqueues the policy again.
+		ctrl.updatePolicyErrorStatusWithEvent(policy, now, "InvalidRetentionPolicy", err.Error())
+		return 0, nil
+	}
+
+	var requeueAfter time.Duration
+	var pruned, inUse int32
+	var errs []error
+	pvcNames := make([]string, 0, len(groups))
+	for pvcName := range groups {
+		pvcNames = append(pvcNames, pvcName)
+	}
+	sort.Strings(pvcNames)
+	for _, pvcName := range pvcNames {
+		toDelete, groupRequeue := applyRetentionPolicy(&policy.Spec, groups[pvcName], now)
+		if groupRequeue > 0 && (requeueAfter == 0 || groupRequeue < requeueAfter) {
+			requeueAfter = groupRequeue
+		}
+		for _, snapshot := range toDelete {
+			deleted, err := ctrl.pruneSnapshot(policy, snapshot)
+			if errors.Is(err, errSnapshotInUse) {
+				inUse++
+				continue
+			}
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if deleted {
+				pruned++
+			}
+		}
+	}
+
+	if err := utilerrors.NewAggregate(errs); err != nil {
+		ctrl.updatePolicyErrorStatusWithEvent(policy, now, "SnapshotPruneFailed", fmt.Sprintf("Failed to delete snapshots: %v", err))
+		return 0, err
+	}
+
+	newStatus := &crdv1alpha1.VolumeSnapshotRetentionPolicyStatus{}
+	if policy.Status != nil {
+		newStatus = policy.Status.DeepCopy()
+	}
+	if pruned > 0 {
+		newStatus.LastPruneTime = &metav1.Time{Time: now}
+		newStatus.LastPrunedCount = &pruned
+	}
+	newStatus.Error = nil
+	if err := ctrl.updatePolicyStatus(policy, newStatus); err != nil {
+		return 0, err
+	}
+	if inUse > 0 {
+		// Retry with a backoff, there is no event when the volumes are
+		// done being created from the snapshots.
+		return 0, fmt.Errorf("%d VolumeSnapshots are used as data source of volumes that are being created, retrying later", inUse)
+	}
+	return requeueAfter, nil
+}
+
+// hasKeepRule checks if at least one of the keep rules of a retention policy
+// keeps snapshots.
+func hasKeepRule(spec *crdv1alpha1.VolumeSnapshotRetentionPolicySpec) bool {
+	positive := func(count *int32) bool { return count != nil && *count > 0 }
+	return positive(spec.KeepLast) || positive(spec.KeepHourly) || positive(spec.KeepDaily) || positive(spec.KeepWeekly) ||
+		(spec.KeepWithin != nil && spec.KeepWithin.Duration > 0)
+}
+
+// getRetentionCandidates returns the snapshots selected by the policy that
+// take part in its evaluation, grouped by the name of their source PVC.
+func (ctrl *snapshotRetentionController) getRetentionCandidates(policy *crdv1alpha1.VolumeSnapshotRetentionPolicy) (map[string][]*crdv1.VolumeSnapshot, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&policy.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VolumeSnapshot selector: %v", err)
+	}
+	if selector.Empty() {
+		// The CRD rejects an empty selector, which would select all the
+		// snapshots of the namespace. Nothing is deleted for policies
+		// created before the validation.
+		return nil, errors.New("VolumeSnapshot selector must not be empty")
+	}
+	snapshots, err := ctrl.snapshotLister.VolumeSnapshots(policy.Namespace).List(selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list VolumeSnapshots: %v", err)
+	}
+
+	groups := map[string][]*crdv1.VolumeSnapshot{}
+	for _, snapshot := range snapshots {
+		if snapshot.Spec.Source.PersistentVolumeClaimName == nil {
+			// Pre-provisioned snapshots have no source PVC.
+			continue
+		}
+		if snapshot.DeletionTimestamp != nil || !utils.IsSnapshotReady(snapshot) {
+			continue
+		}
+		if snapshot.Status.VolumeGroupSnapshotName != nil && *snapshot.Status.VolumeGroupSnapshotName != "" {
+			continue
+		}
+		pvcName := *snapshot.Spec.Source.PersistentVolumeClaimName
+		groups[pvcName] = append(groups[pvcName], snapshot)
+	}
+	return groups, nil
+}
+
+// applyRetentionPolicy returns the snapshots of a single PVC that are not
+// kept by the policy, together with the duration after which the oldest
+// snapshot kept by keepWithin falls out of the window. If no keep rule keeps
+// snapshots, nothing is deleted.
+func applyRetentionPolicy(spec *crdv1alpha1.VolumeSnapshotRetentionPolicySpec, snapshots []*crdv1.VolumeSnapshot, now time.Time) ([]*crdv1.VolumeSnapshot, time.Duration) {
+	if !hasKeepRule(spec) {
+		return nil, 0
+	}
+
+	sorted := make([]*crdv1.VolumeSnapshot, len(snapshots))
+	copy(sorted, snapshots)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ti, tj := snapshotCreationTime(sorted[i]), snapshotCreationTime(sorted[j])
+		if !ti.Equal(tj) {
+			return ti.After(tj)
+		}
+		return sorted[i].Name > sorted[j].Name
+	})
+
+	keep := make([]bool, len(sorted))
+	if spec.KeepLast != nil {
+		for i := 0; i < len(sorted) && i < int(*spec.KeepLast); i++ {
+			keep[i] = true
+		}
+	}
+
+	var requeueAfter time.Duration
+	if spec.KeepWithin != nil {
+		for i, snapshot := range sorted {
+			expiry := snapshotCreationTime(snapshot).Add(spec.KeepWithin.Duration)
+			if !expiry.After(now) {
+				break
+			}
+			keep[i] = true
+			requeueAfter = expiry.Sub(now)
+		}
+	}
+
+	keepBuckets(sorted, keep, spec.KeepHourly, func(t time.Time) string { return t.Format("2006-01-02T15") })
+	keepBuckets(sorted, keep, spec.KeepDaily, func(t time.Time) string { return t.Format("2006-01-02") })
+	keepBuckets(sorted, keep, spec.KeepWeekly, func(t time.Time) string {
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	})
+
+	var toDelete []*crdv1.VolumeSnapshot
+	for i, snapshot := range sorted {
+		if !keep[i] {
+			toDelete = append(toDelete, snapshot)
+		}
+	}
+	return toDelete, requeueAfter
+}
+
+// keepBuckets marks the newest snapshot of each of the count most recent
+// buckets as kept. sorted must be ordered newest first.
+func keepBuckets(sorted []*crdv1.VolumeSnapshot, keep []bool, count *int32, bucketOf func(time.Time) string) {
+	if count == nil || *count <= 0 {
+		return
+	}
+	lastBucket := ""
+	buckets := int32(0)
+	for i, snapshot := range sorted {
+		bucket := bucketOf(snapshotCreationTime(snapshot).UTC())
+		if bucket == lastBucket {
+			continue
+		}
+		lastBucket = bucket
+		keep[i] = true
+		buckets++
+		if buckets == *count {
+			return
+		}
+	}
+}
+
+// errSnapshotInUse is returned by pruneSnapshot for a snapshot that is the
+// data source of a volume being created.
+var errSnapshotInUse = errors.New("VolumeSnapshot is used as data source of a volume that is being created")
+
+// pruneSnapshot deletes a snapshot that is not kept by the policy. It
+// returns errSnapshotInUse if the snapshot must not be deleted yet, and
+// false without an error if the snapshot is already gone.
+func (ctrl *snapshotRetentionController) pruneSnapshot(policy *crdv1alpha1.VolumeSnapshotRetentionPolicy, snapshot *crdv1.VolumeSnapshot) (bool, error) {
+	if volumeBeingCreatedFromSnapshot(ctrl.pvcLister, snapshot) {
+		klog.V(4).Infof("pruneSnapshot: VolumeSnapshot %s is used as data source of a volume that is being created, retrying later", utils.SnapshotKey(snapshot))
+		return false, errSnapshotInUse
+	}
+
+	klog.V(5).Infof("pruneSnapshot: deleting VolumeSnapshot %s for retention policy %s", utils.SnapshotKey(snapshot), policy.Name)
+	uid := snapshot.UID
+	err := ctrl.clientset.SnapshotV1().VolumeSnapshots(snapshot.Namespace).Delete(context.TODO(), snapshot.Name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{UID: &uid},
+	})
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to delete VolumeSnapshot %s: %v", snapshot.Name, err)
+	}
+
+	ctrl.eventRecorder.Event(policy, v1.EventTypeNormal, "VolumeSnapshotPruned",
+		fmt.Sprintf("Deleted VolumeSnapshot %s of PersistentVolumeClaim %s", snapshot.Name, *snapshot.Spec.Source.PersistentVolumeClaimName))
+	return true, nil
+}
+
+// updatePolicyStatus saves the new status of a retention policy to the API
+// server if it differs from the current one.
+func (ctrl *snapshotRetentionController) updatePolicyStatus(policy *crdv1alpha1.VolumeSnapshotRetentionPolicy, newStatus *crdv1alpha1.VolumeSnapshotRetentionPolicyStatus) error {
+	if equality.Semantic.DeepEqual(policy.Status, newStatus) {
+		return nil
+	}
+	policyClone := policy.DeepCopy()
+	policyClone.Status = newStatus
+	_, err := ctrl.clientset.SnapshotV1alpha1().VolumeSnapshotRetentionPolicies(policy.Namespace).UpdateStatus(context.TODO(), policyClone, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to update status of VolumeSnapshotRetentionPolicy %s/%s: %v", policy.Namespace, policy.Name, err)
+	}
+	return nil
+}
+
+// updatePolicyErrorStatusWithEvent saves the given error message in the
+// policy status and emits a warning event with the same message.
+func (ctrl *snapshotRetentionController) updatePolicyErrorStatusWithEvent(policy *crdv1alpha1.VolumeSnapshotRetentionPolicy, now time.Time, reason, message string) {
+	klog.V(4).Infof("VolumeSnapshotRetentionPolicy[%s/%s]: %s", policy.Namespace, policy.Name, message)
+	ctrl.eventRecorder.Event(policy, v1.EventTypeWarning, reason, message)
+
+	if policy.Status != nil && policy.Status.Error != nil && policy.Status.Error.Message != nil && *policy.Status.Error.Message == message {
+		return
+	}
+	newStatus := &crdv1alpha1.VolumeSnapshotRetentionPolicyStatus{}
+	if policy.Status != nil {
+		newStatus = policy.Status.DeepCopy()
+	}
+	newStatus.Error = &crdv1.VolumeSnapshotError{
+		Time:    &metav1.Time{Time: now},
+		Message: &message,
+	}
+	if err := ctrl.updatePolicyStatus(policy, newStatus); err != nil {
+		klog.V(4).Infof("updating VolumeSnapshotRetentionPolicy[%s/%s] error status failed %v", policy.Namespace, policy.Name, err)
+	}
+}
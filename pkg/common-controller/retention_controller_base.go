@@ -0,0 +1,221 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common_controller
+
+import (
+	"time"
+
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	crdv1alpha1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1alpha1"
+	clientset "github.com/kubernetes-csi/external-snapshotter/client/v8/clientset/versioned"
+	snapshotinformers "github.com/kubernetes-csi/external-snapshotter/client/v8/informers/externalversions/volumesnapshot/v1"
+	retentioninformers "github.com/kubernetes-csi/external-snapshotter/client/v8/informers/externalversions/volumesnapshot/v1alpha1"
+	snapshotlisters "github.com/kubernetes-csi/external-snapshotter/client/v8/listers/volumesnapshot/v1"
+	retentionlisters "github.com/kubernetes-csi/external-snapshotter/client/v8/listers/volumesnapshot/v1alpha1"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	klog "k8s.io/klog/v2"
+)
+
+// snapshotRetentionController deletes VolumeSnapshots that are no longer
+// kept by a VolumeSnapshotRetentionPolicy. Deleted snapshots are finalized
+// by csiSnapshotCommonController like any other VolumeSnapshot.
+type snapshotRetentionController struct {
+	clientset      clientset.Interface
+	client         kubernetes.Interface
+	eventRecorder  record.EventRecorder
+	retentionQueue workqueue.TypedRateLimitingInterface[string]
+
+	policyLister         retentionlisters.VolumeSnapshotRetentionPolicyLister
+	policyListerSynced   cache.InformerSynced
+	snapshotLister       snapshotlisters.VolumeSnapshotLister
+	snapshotListerSynced cache.InformerSynced
+	pvcLister            corelisters.PersistentVolumeClaimLister
+	pvcListerSynced      cache.InformerSynced
+
+	resyncPeriod time.Duration
+
+	// now returns the current time. It is replaced in unit tests.
+	now func() time.Time
+}
+
+// NewSnapshotRetentionController returns a new *snapshotRetentionController
+func NewSnapshotRetentionController(
+	clientset clientset.Interface,
+	client kubernetes.Interface,
+	volumeSnapshotRetentionPolicyInformer retentioninformers.VolumeSnapshotRetentionPolicyInformer,
+	volumeSnapshotInformer snapshotinformers.VolumeSnapshotInformer,
+	pvcInformer coreinformers.PersistentVolumeClaimInformer,
+	resyncPeriod time.Duration,
+	retentionRateLimiter workqueue.TypedRateLimiter[string],
+) *snapshotRetentionController {
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartLogging(klog.Infof)
+	broadcaster.StartRecordingToSink(&corev1.EventSinkImpl{Interface: client.CoreV1().Events(v1.NamespaceAll)})
+	eventRecorder := broadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: "snapshot-controller"})
+
+	ctrl := &snapshotRetentionController{
+		clientset:     clientset,
+		client:        client,
+		eventRecorder: eventRecorder,
+		resyncPeriod:  resyncPeriod,
+		retentionQueue: workqueue.NewTypedRateLimitingQueueWithConfig(retentionRateLimiter,
+			workqueue.TypedRateLimitingQueueConfig[string]{
+				Name: "snapshot-controller-retention"}),
+		now: time.Now,
+	}
+
+	// Policies are resynced periodically to catch snapshots that are kept
+	// back temporarily, e.g. because a volume is being restored from them.
+	volumeSnapshotRetentionPolicyInformer.Informer().AddEventHandlerWithResyncPeriod(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { ctrl.enqueuePolicyWork(obj) },
+			UpdateFunc: func(oldObj, newObj interface{}) { ctrl.enqueuePolicyWork(newObj) },
+		},
+		ctrl.resyncPeriod,
+	)
+	ctrl.policyLister = volumeSnapshotRetentionPolicyInformer.Lister()
+	ctrl.policyListerSynced = volumeSnapshotRetentionPolicyInformer.Informer().HasSynced
+
+	volumeSnapshotInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { ctrl.enqueuePoliciesForSnapshot(obj) },
+			UpdateFunc: func(oldObj, newObj interface{}) { ctrl.enqueuePoliciesForSnapshot(newObj) },
+		},
+	)
+	ctrl.snapshotLister = volumeSnapshotInformer.Lister()
+	ctrl.snapshotListerSynced = volumeSnapshotInformer.Informer().HasSynced
+
+	ctrl.pvcLister = pvcInformer.Lister()
+	ctrl.pvcListerSynced = pvcInformer.Informer().HasSynced
+
+	return ctrl
+}
+
+func (ctrl *snapshotRetentionController) Run(workers int, stopCh <-chan struct{}) {
+	defer ctrl.retentionQueue.ShutDown()
+
+	klog.Infof("Starting snapshot retention controller")
+	defer klog.Infof("Shutting snapshot retention controller")
+
+	if !cache.WaitForCacheSync(stopCh, ctrl.policyListerSynced, ctrl.snapshotListerSynced, ctrl.pvcListerSynced) {
+		klog.Errorf("Cannot sync caches")
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.Until(ctrl.retentionWorker, 0, stopCh)
+	}
+
+	<-stopCh
+}
+
+// enqueuePolicyWork adds a retention policy to the work queue.
+func (ctrl *snapshotRetentionController) enqueuePolicyWork(obj interface{}) {
+	if policy, ok := obj.(*crdv1alpha1.VolumeSnapshotRetentionPolicy); ok {
+		objName, err := cache.MetaNamespaceKeyFunc(policy)
+		if err != nil {
+			klog.Errorf("failed to get key from object: %v, %v", err, policy)
+			return
+		}
+		klog.V(5).Infof("enqueued %q for sync", objName)
+		ctrl.retentionQueue.Add(objName)
+	}
+}
+
+// enqueuePoliciesForSnapshot adds all retention policies that select the
+// given snapshot to the work queue.
+func (ctrl *snapshotRetentionController) enqueuePoliciesForSnapshot(obj interface{}) {
+	snapshot, ok := obj.(*crdv1.VolumeSnapshot)
+	if !ok {
+		return
+	}
+	policies, err := ctrl.policyLister.VolumeSnapshotRetentionPolicies(snapshot.Namespace).List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list retention policies in namespace %s: %v", snapshot.Namespace, err)
+		return
+	}
+	for _, policy := range policies {
+		selector, err := metav1.LabelSelectorAsSelector(&policy.Spec.Selector)
+		if err != nil || !selector.Matches(labels.Set(snapshot.Labels)) {
+			continue
+		}
+		key := policy.Namespace + "/" + policy.Name
+		klog.V(5).Infof("enqueued %q for sync on change of snapshot %s", key, utils.SnapshotKey(snapshot))
+		ctrl.retentionQueue.Add(key)
+	}
+}
+
+// retentionWorker is the main worker for VolumeSnapshotRetentionPolicies.
+func (ctrl *snapshotRetentionController) retentionWorker() {
+	key, quit := ctrl.retentionQueue.Get()
+	if quit {
+		return
+	}
+	defer ctrl.retentionQueue.Done(key)
+
+	requeueAfter, err := ctrl.syncPolicyByKey(key)
+	if err != nil {
+		// Rather than wait for a full resync, re-add the key to the
+		// queue to be processed.
+		ctrl.retentionQueue.AddRateLimited(key)
+		klog.V(4).Infof("Failed to sync retention policy %q, will retry again: %v", key, err)
+		return
+	}
+	// Finally, if no error occurs we Forget this item so it does not
+	// get queued again until another change happens or a kept snapshot
+	// falls out of the keepWithin window.
+	ctrl.retentionQueue.Forget(key)
+	if requeueAfter > 0 {
+		ctrl.retentionQueue.AddAfter(key, requeueAfter)
+	}
+}
+
+// syncPolicyByKey processes a VolumeSnapshotRetentionPolicy. It returns the
+// duration after which the policy must be synced again.
+func (ctrl *snapshotRetentionController) syncPolicyByKey(key string) (time.Duration, error) {
+	klog.V(5).Infof("syncPolicyByKey[%s]", key)
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		klog.Errorf("error getting namespace & name of retention policy %q to get policy from informer: %v", key, err)
+		return 0, nil
+	}
+	policy, err := ctrl.policyLister.VolumeSnapshotRetentionPolicies(namespace).Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			klog.V(4).Infof("retention policy %q has been deleted", key)
+			return 0, nil
+		}
+		klog.V(2).Infof("error getting retention policy %q from informer: %v", key, err)
+		return 0, err
+	}
+	return ctrl.syncPolicy(policy.DeepCopy())
+}
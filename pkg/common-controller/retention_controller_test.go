@@ -0,0 +1,412 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common_controller
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	crdv1alpha1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1alpha1"
+	"github.com/kubernetes-csi/external-snapshotter/client/v8/clientset/versioned/fake"
+	informers "github.com/kubernetes-csi/external-snapshotter/client/v8/informers/externalversions"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	coreinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+)
+
+type retentionTest struct {
+	name   string
+	policy *crdv1alpha1.VolumeSnapshotRetentionPolicy
+	pvcs   []*v1.PersistentVolumeClaim
+	// snapshots initially present in the API server and informer
+	snapshots []*crdv1.VolumeSnapshot
+	now       string
+
+	expectedSnapshots   []string
+	expectedRequeue     time.Duration
+	expectedPrunedCount int32
+	expectedStatusError bool
+	expectedEvents      []string
+	expectError         bool
+}
+
+func newRetentionPolicy(name string, selector map[string]string) *crdv1alpha1.VolumeSnapshotRetentionPolicy {
+	return &crdv1alpha1.VolumeSnapshotRetentionPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testScheduleNamespace,
+		},
+		Spec: crdv1alpha1.VolumeSnapshotRetentionPolicySpec{
+			Selector: metav1.LabelSelector{MatchLabels: selector},
+		},
+	}
+}
+
+func withKeepLast(policy *crdv1alpha1.VolumeSnapshotRetentionPolicy, count int32) *crdv1alpha1.VolumeSnapshotRetentionPolicy {
+	policy.Spec.KeepLast = &count
+	return policy
+}
+
+func withKeepWithin(policy *crdv1alpha1.VolumeSnapshotRetentionPolicy, duration time.Duration) *crdv1alpha1.VolumeSnapshotRetentionPolicy {
+	policy.Spec.KeepWithin = &metav1.Duration{Duration: duration}
+	return policy
+}
+
+func withKeepBuckets(policy *crdv1alpha1.VolumeSnapshotRetentionPolicy, hourly, daily, weekly int32) *crdv1alpha1.VolumeSnapshotRetentionPolicy {
+	if hourly > 0 {
+		policy.Spec.KeepHourly = &hourly
+	}
+	if daily > 0 {
+		policy.Spec.KeepDaily = &daily
+	}
+	if weekly > 0 {
+		policy.Spec.KeepWeekly = &weekly
+	}
+	return policy
+}
+
+func newRetainedSnapshot(name, pvcName string, ready bool, created string) *crdv1.VolumeSnapshot {
+	snapshot := newScheduledSnapshot(name, "hourly", ready, created)
+	snapshot.Labels["app"] = "db"
+	snapshot.Spec.Source.PersistentVolumeClaimName = &pvcName
+	return snapshot
+}
+
+func withGroupSnapshotName(snapshot *crdv1.VolumeSnapshot, groupName string) *crdv1.VolumeSnapshot {
+	snapshot.Status.VolumeGroupSnapshotName = &groupName
+	return snapshot
+}
+
+func newRestoringClaim(name, snapshotName string) *v1.PersistentVolumeClaim {
+	pvc := newScheduleClaim(name, nil)
+	apiGroup := snapshotAPIGroup
+	pvc.Spec.DataSource = &v1.TypedLocalObjectReference{
+		APIGroup: &apiGroup,
+		Kind:     snapshotKind,
+		Name:     snapshotName,
+	}
+	pvc.Status.Phase = v1.ClaimPending
+	return pvc
+}
+
+func TestApplyRetentionPolicy(t *testing.T) {
+	hourlySnapshots := []*crdv1.VolumeSnapshot{
+		newRetainedSnapshot("s0800", "claim1", true, "2024-01-01T08:00:00Z"),
+		newRetainedSnapshot("s0900", "claim1", true, "2024-01-01T09:00:00Z"),
+		newRetainedSnapshot("s0930", "claim1", true, "2024-01-01T09:30:00Z"),
+		newRetainedSnapshot("s1000", "claim1", true, "2024-01-01T10:00:00Z"),
+	}
+	dailySnapshots := []*crdv1.VolumeSnapshot{
+		newRetainedSnapshot("d0101a", "claim1", true, "2024-01-01T01:00:00Z"),
+		newRetainedSnapshot("d0101b", "claim1", true, "2024-01-01T13:00:00Z"),
+		newRetainedSnapshot("d0102", "claim1", true, "2024-01-02T13:00:00Z"),
+		newRetainedSnapshot("d0108", "claim1", true, "2024-01-08T13:00:00Z"),
+		newRetainedSnapshot("d0115", "claim1", true, "2024-01-15T13:00:00Z"),
+		newRetainedSnapshot("d0116", "claim1", true, "2024-01-16T13:00:00Z"),
+	}
+
+	tests := []struct {
+		name            string
+		spec            crdv1alpha1.VolumeSnapshotRetentionPolicySpec
+		snapshots       []*crdv1.VolumeSnapshot
+		now             string
+		expectedDeleted []string
+		expectedRequeue time.Duration
+	}{
+		{
+			name:      "no rules keeps everything",
+			spec:      newRetentionPolicy("p", nil).Spec,
+			snapshots: hourlySnapshots,
+			now:       "2024-01-01T10:30:00Z",
+		},
+		{
+			name:            "keepLast",
+			spec:            withKeepLast(newRetentionPolicy("p", nil), 2).Spec,
+			snapshots:       hourlySnapshots,
+			now:             "2024-01-01T10:30:00Z",
+			expectedDeleted: []string{"s0900", "s0800"},
+		},
+		{
+			name:      "keepLast zero keeps everything",
+			spec:      withKeepLast(newRetentionPolicy("p", nil), 0).Spec,
+			snapshots: hourlySnapshots,
+			now:       "2024-01-01T10:30:00Z",
+		},
+		{
+			name:            "keepWithin",
+			spec:            withKeepWithin(newRetentionPolicy("p", nil), time.Hour).Spec,
+			snapshots:       hourlySnapshots,
+			now:             "2024-01-01T10:15:00Z",
+			expectedDeleted: []string{"s0900", "s0800"},
+			expectedRequeue: 15 * time.Minute,
+		},
+		{
+			name:            "keepWithin expires exactly at the window",
+			spec:            withKeepWithin(newRetentionPolicy("p", nil), time.Hour).Spec,
+			snapshots:       hourlySnapshots,
+			now:             "2024-01-01T10:30:00Z",
+			expectedDeleted: []string{"s0930", "s0900", "s0800"},
+			expectedRequeue: 30 * time.Minute,
+		},
+		{
+			name:            "keepHourly keeps the newest snapshot of each hour",
+			spec:            withKeepBuckets(newRetentionPolicy("p", nil), 3, 0, 0).Spec,
+			snapshots:       hourlySnapshots,
+			now:             "2024-01-01T10:30:00Z",
+			expectedDeleted: []string{"s0900"},
+		},
+		{
+			name:            "keepDaily and keepWeekly",
+			spec:            withKeepBuckets(newRetentionPolicy("p", nil), 0, 2, 3).Spec,
+			snapshots:       dailySnapshots,
+			now:             "2024-01-17T00:00:00Z",
+			expectedDeleted: []string{"d0101b", "d0101a"},
+		},
+		{
+			name:            "rules are combined",
+			spec:            withKeepBuckets(withKeepLast(newRetentionPolicy("p", nil), 1), 0, 3, 0).Spec,
+			snapshots:       dailySnapshots,
+			now:             "2024-01-17T00:00:00Z",
+			expectedDeleted: []string{"d0102", "d0101b", "d0101a"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			deleted, requeue := applyRetentionPolicy(&test.spec, test.snapshots, mustParseTime(test.now))
+			names := []string{}
+			for _, snapshot := range deleted {
+				names = append(names, snapshot.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(test.expectedDeleted, ",") {
+				t.Errorf("expected deleted snapshots %v, got %v", test.expectedDeleted, names)
+			}
+			if requeue != test.expectedRequeue {
+				t.Errorf("expected requeue after %v, got %v", test.expectedRequeue, requeue)
+			}
+		})
+	}
+}
+
+func TestSyncRetentionPolicy(t *testing.T) {
+	tests := []retentionTest{
+		{
+			name:   "2-1 - old snapshots are deleted per PVC",
+			policy: withKeepLast(newRetentionPolicy("keep1", map[string]string{"app": "db"}), 1),
+			snapshots: []*crdv1.VolumeSnapshot{
+				newRetainedSnapshot("a1", "data", true, "2024-01-01T08:00:00Z"),
+				newRetainedSnapshot("a2", "data", true, "2024-01-01T09:00:00Z"),
+				newRetainedSnapshot("b1", "logs", true, "2024-01-01T08:00:00Z"),
+			},
+			now:                 "2024-01-01T10:00:00Z",
+			expectedSnapshots:   []string{"a2", "b1"},
+			expectedPrunedCount: 1,
+			expectedEvents:      []string{"Normal VolumeSnapshotPruned"},
+		},
+		{
+			name:   "2-2 - snapshots that are not ready are neither deleted nor counted",
+			policy: withKeepLast(newRetentionPolicy("keep1", map[string]string{"app": "db"}), 1),
+			snapshots: []*crdv1.VolumeSnapshot{
+				newRetainedSnapshot("a1", "data", true, "2024-01-01T08:00:00Z"),
+				newRetainedSnapshot("a2", "data", false, "2024-01-01T09:00:00Z"),
+			},
+			now:               "2024-01-01T10:00:00Z",
+			expectedSnapshots: []string{"a1", "a2"},
+		},
+		{
+			name:   "2-3 - snapshots used as data source are kept",
+			policy: withKeepLast(newRetentionPolicy("keep1", map[string]string{"app": "db"}), 1),
+			pvcs:   []*v1.PersistentVolumeClaim{newRestoringClaim("restore", "a1")},
+			snapshots: []*crdv1.VolumeSnapshot{
+				newRetainedSnapshot("a1", "data", true, "2024-01-01T08:00:00Z"),
+				newRetainedSnapshot("a2", "data", true, "2024-01-01T09:00:00Z"),
+			},
+			now:               "2024-01-01T10:00:00Z",
+			expectedSnapshots: []string{"a1", "a2"},
+			expectError:       true,
+		},
+		{
+			name:   "2-4 - group snapshot members and unselected snapshots are ignored",
+			policy: withKeepLast(newRetentionPolicy("keep1", map[string]string{"app": "db"}), 1),
+			snapshots: []*crdv1.VolumeSnapshot{
+				withGroupSnapshotName(newRetainedSnapshot("a1", "data", true, "2024-01-01T08:00:00Z"), "group1"),
+				newRetainedSnapshot("a2", "data", true, "2024-01-01T09:00:00Z"),
+				newScheduledSnapshot("other", "hourly", true, "2024-01-01T07:00:00Z"),
+			},
+			now:               "2024-01-01T10:00:00Z",
+			expectedSnapshots: []string{"a1", "a2", "other"},
+		},
+		{
+			name:   "2-5 - requeue when a snapshot leaves the keepWithin window",
+			policy: withKeepWithin(newRetentionPolicy("recent", map[string]string{"app": "db"}), 2*time.Hour),
+			snapshots: []*crdv1.VolumeSnapshot{
+				newRetainedSnapshot("a1", "data", true, "2024-01-01T07:00:00Z"),
+				newRetainedSnapshot("a2", "data", true, "2024-01-01T09:00:00Z"),
+			},
+			now:                 "2024-01-01T10:00:00Z",
+			expectedSnapshots:   []string{"a2"},
+			expectedRequeue:     time.Hour,
+			expectedPrunedCount: 1,
+			expectedEvents:      []string{"Normal VolumeSnapshotPruned"},
+		},
+		{
+			name: "2-6 - invalid selector",
+			policy: func() *crdv1alpha1.VolumeSnapshotRetentionPolicy {
+				policy := withKeepLast(newRetentionPolicy("invalid", nil), 1)
+				policy.Spec.Selector.MatchExpressions = []metav1.LabelSelectorRequirement{{Key: "app", Operator: "Bogus"}}
+				return policy
+			}(),
+			snapshots: []*crdv1.VolumeSnapshot{
+				newRetainedSnapshot("a1", "data", true, "2024-01-01T08:00:00Z"),
+			},
+			now:                 "2024-01-01T10:00:00Z",
+			expectedSnapshots:   []string{"a1"},
+			expectedStatusError: true,
+			expectedEvents:      []string{"Warning InvalidRetentionPolicy"},
+		},
+		{
+			name:   "2-7 - policy keeping no snapshot is rejected",
+			policy: withKeepLast(newRetentionPolicy("keep0", nil), 0),
+			snapshots: []*crdv1.VolumeSnapshot{
+				newRetainedSnapshot("a1", "data", true, "2024-01-01T08:00:00Z"),
+				newRetainedSnapshot("a2", "data", true, "2024-01-01T09:00:00Z"),
+			},
+			now:                 "2024-01-01T10:00:00Z",
+			expectedSnapshots:   []string{"a1", "a2"},
+			expectedStatusError: true,
+			expectedEvents:      []string{"Warning InvalidRetentionPolicy"},
+		},
+		{
+			name:   "2-8 - policy with an empty selector is rejected",
+			policy: withKeepLast(newRetentionPolicy("all", nil), 1),
+			snapshots: []*crdv1.VolumeSnapshot{
+				newRetainedSnapshot("a1", "data", true, "2024-01-01T08:00:00Z"),
+				newRetainedSnapshot("a2", "data", true, "2024-01-01T09:00:00Z"),
+			},
+			now:                 "2024-01-01T10:00:00Z",
+			expectedSnapshots:   []string{"a1", "a2"},
+			expectedStatusError: true,
+			expectedEvents:      []string{"Warning InvalidRetentionPolicy"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			runRetentionTest(t, test)
+		})
+	}
+}
+
+func runRetentionTest(t *testing.T, test retentionTest) {
+	objs := []runtime.Object{test.policy}
+	for _, snapshot := range test.snapshots {
+		objs = append(objs, snapshot)
+	}
+	client := fake.NewSimpleClientset(objs...)
+	kubeObjs := []runtime.Object{}
+	for _, pvc := range test.pvcs {
+		kubeObjs = append(kubeObjs, pvc)
+	}
+	kubeClient := kubefake.NewSimpleClientset(kubeObjs...)
+
+	factory := informers.NewSharedInformerFactory(client, 0)
+	coreFactory := coreinformers.NewSharedInformerFactory(kubeClient, 0)
+	ctrl := NewSnapshotRetentionController(
+		client,
+		kubeClient,
+		factory.Snapshot().V1alpha1().VolumeSnapshotRetentionPolicies(),
+		factory.Snapshot().V1().VolumeSnapshots(),
+		coreFactory.Core().V1().PersistentVolumeClaims(),
+		0,
+		workqueue.DefaultTypedControllerRateLimiter[string](),
+	)
+	fakeRecorder := record.NewFakeRecorder(100)
+	ctrl.eventRecorder = fakeRecorder
+	ctrl.now = func() time.Time { return mustParseTime(test.now) }
+
+	for _, pvc := range test.pvcs {
+		coreFactory.Core().V1().PersistentVolumeClaims().Informer().GetIndexer().Add(pvc)
+	}
+	for _, snapshot := range test.snapshots {
+		factory.Snapshot().V1().VolumeSnapshots().Informer().GetIndexer().Add(snapshot)
+	}
+
+	requeue, err := ctrl.syncPolicy(test.policy.DeepCopy())
+	if test.expectError && err == nil {
+		t.Errorf("expected an error")
+	}
+	if !test.expectError && err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if requeue != test.expectedRequeue {
+		t.Errorf("expected requeue after %v, got %v", test.expectedRequeue, requeue)
+	}
+
+	snapshots, err := client.SnapshotV1().VolumeSnapshots(testScheduleNamespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to list snapshots: %v", err)
+	}
+	names := []string{}
+	for _, snapshot := range snapshots.Items {
+		names = append(names, snapshot.Name)
+	}
+	sort.Strings(names)
+	if strings.Join(names, ",") != strings.Join(test.expectedSnapshots, ",") {
+		t.Errorf("expected snapshots %v, got %v", test.expectedSnapshots, names)
+	}
+
+	policy, err := client.SnapshotV1alpha1().VolumeSnapshotRetentionPolicies(testScheduleNamespace).Get(context.TODO(), test.policy.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get policy: %v", err)
+	}
+	prunedCount := int32(0)
+	statusError := false
+	if policy.Status != nil {
+		if policy.Status.LastPrunedCount != nil {
+			prunedCount = *policy.Status.LastPrunedCount
+		}
+		statusError = policy.Status.Error != nil
+	}
+	if prunedCount != test.expectedPrunedCount {
+		t.Errorf("expected lastPrunedCount %d, got %d", test.expectedPrunedCount, prunedCount)
+	}
+	if statusError != test.expectedStatusError {
+		t.Errorf("expected status error %v, got %v", test.expectedStatusError, statusError)
+	}
+
+	close(fakeRecorder.Events)
+	events := []string{}
+	for event := range fakeRecorder.Events {
+		events = append(events, event)
+	}
+	if len(events) != len(test.expectedEvents) {
+		t.Fatalf("expected events %v, got %v", test.expectedEvents, events)
+	}
+	for i, expected := range test.expectedEvents {
+		if !strings.HasPrefix(events[i], expected) {
+			t.Errorf("expected event %d to start with %q, got %q", i, expected, events[i])
+		}
+	}
+}
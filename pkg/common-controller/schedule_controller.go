@@ -0,0 +1,398 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common_controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	crdv1alpha1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1alpha1"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/cron"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	klog "k8s.io/klog/v2"
+)
+
+// Design:
+//
+// A VolumeSnapshotSchedule creates one VolumeSnapshot per source PVC each
+// time its cron expression fires. The controller does not poll: after each
+// sync the schedule is re-enqueued for the next activation time.
+//
+// The scheduled time of a run is recorded in status.lastScheduleTime only
+// after all snapshots of the run were created. Snapshot names are derived
+// from the scheduled time, so a run that failed halfway is retried without
+// creating duplicates.
+//
+// Missed runs, e.g. while the snapshot controller was not running or the
+// schedule was suspended, are not backfilled. When the controller finds one
+// or more runs that are due, it only starts the most recent one, and only
+// if it is not older than spec.startingDeadlineSeconds. Older runs are
+// reported with a MissedSchedule event.
+
+const (
+	defaultSnapshotNameTemplate = "${schedule.name}-${pvc.name}-${timestamp}"
+	scheduledTimestampFormat    = "20060102150405"
+
+	scheduleNameToken = "schedule.name"
+	pvcNameToken      = "pvc.name"
+	timestampToken    = "timestamp"
+)
+
+// syncSchedule creates the VolumeSnapshots of a schedule run that is due and
+// updates the schedule status. It returns the duration after which the
+// schedule must be synced again; zero means the schedule does not need to be
+// requeued until it is changed.
+func (ctrl *snapshotScheduleController) syncSchedule(schedule *crdv1alpha1.VolumeSnapshotSchedule) (time.Duration, error) {
+	klog.V(5).Infof("synchronizing VolumeSnapshotSchedule[%s/%s]", schedule.Namespace, schedule.Name)
+
+	now := ctrl.now()
+	cronSchedule, err := validateSchedule(schedule)
+	if err != nil {
+		// Nothing will change until the user fixes the spec, which
+		// enqueues the schedule again.
+		ctrl.updateScheduleErrorStatusWithEvent(schedule, now, "InvalidSchedule", err.Error())
+		return 0, nil
+	}
+
+	newStatus := &crdv1alpha1.VolumeSnapshotScheduleStatus{}
+	if schedule.Status != nil {
+		newStatus = schedule.Status.DeepCopy()
+	}
+	if lastSuccessful := ctrl.getLastSuccessfulSnapshot(schedule); lastSuccessful != nil {
+		newStatus.LastSuccessfulSnapshot = lastSuccessful
+	}
+
+	if schedule.Spec.Suspend != nil && *schedule.Spec.Suspend {
+		klog.V(4).Infof("VolumeSnapshotSchedule[%s/%s] is suspended", schedule.Namespace, schedule.Name)
+		return 0, ctrl.updateScheduleStatus(schedule, newStatus)
+	}
+
+	scheduledTime, missedRuns := getMostRecentScheduledTime(schedule, cronSchedule, now)
+	if !scheduledTime.IsZero() {
+		if tooLate(schedule, scheduledTime, now) {
+			ctrl.eventRecorder.Event(schedule, v1.EventTypeWarning, "MissedSchedule",
+				fmt.Sprintf("Skipped %d missed run(s) up to the run scheduled at %s because its starting deadline was exceeded", missedRuns+1, scheduledTime.Format(time.RFC3339)))
+		} else {
+			if err := ctrl.createScheduledSnapshots(schedule, scheduledTime); err != nil {
+				message := fmt.Sprintf("Failed to create snapshots for the run scheduled at %s: %v", scheduledTime.Format(time.RFC3339), err)
+				ctrl.updateScheduleErrorStatusWithEvent(schedule, now, "SnapshotScheduleFailed", message)
+				return 0, err
+			}
+			if missedRuns > 0 {
+				ctrl.eventRecorder.Event(schedule, v1.EventTypeWarning, "MissedSchedule",
+					fmt.Sprintf("Skipped %d missed run(s) before the run scheduled at %s", missedRuns, scheduledTime.Format(time.RFC3339)))
+			}
+		}
+		newStatus.LastScheduleTime = &metav1.Time{Time: scheduledTime}
+		newStatus.Error = nil
+	}
+
+	if err := ctrl.updateScheduleStatus(schedule, newStatus); err != nil {
+		return 0, err
+	}
+
+	next := cronSchedule.Next(now)
+	if next.IsZero() {
+		klog.V(2).Infof("VolumeSnapshotSchedule[%s/%s]: schedule %q has no further runs", schedule.Namespace, schedule.Name, schedule.Spec.Schedule)
+		return 0, nil
+	}
+	return next.Sub(now), nil
+}
+
+// validateSchedule parses the cron expression of a schedule and checks its
+// snapshot name template.
+func validateSchedule(schedule *crdv1alpha1.VolumeSnapshotSchedule) (*cron.Schedule, error) {
+	cronSchedule, err := cron.Parse(schedule.Spec.Schedule)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse schedule %q: %v", schedule.Spec.Schedule, err)
+	}
+	if errs := validation.IsValidLabelValue(schedule.Name); len(errs) > 0 {
+		return nil, fmt.Errorf("schedule name %q cannot be used as a label value: %s", schedule.Name, strings.Join(errs, ", "))
+	}
+	template := getSnapshotNameTemplate(schedule)
+	if !strings.Contains(template, "${"+timestampToken+"}") {
+		return nil, fmt.Errorf("snapshot name template %q must contain ${%s}", template, timestampToken)
+	}
+	if schedule.Spec.Source.Selector != nil && !strings.Contains(template, "${"+pvcNameToken+"}") {
+		return nil, fmt.Errorf("snapshot name template %q must contain ${%s} when a selector is used", template, pvcNameToken)
+	}
+	return cronSchedule, nil
+}
+
+func getSnapshotNameTemplate(schedule *crdv1alpha1.VolumeSnapshotSchedule) string {
+	if schedule.Spec.SnapshotNameTemplate != nil && *schedule.Spec.SnapshotNameTemplate != "" {
+		return *schedule.Spec.SnapshotNameTemplate
+	}
+	return defaultSnapshotNameTemplate
+}
+
+// getMostRecentScheduledTime returns the most recent activation time of the
+// schedule that is due and has not been processed yet, together with the
+// number of earlier runs that were missed. It returns the zero time if no
+// run is due.
+func getMostRecentScheduledTime(schedule *crdv1alpha1.VolumeSnapshotSchedule, cronSchedule *cron.Schedule, now time.Time) (time.Time, int) {
+	earliest := schedule.CreationTimestamp.Time
+	if schedule.Status != nil && schedule.Status.LastScheduleTime != nil {
+		earliest = schedule.Status.LastScheduleTime.Time
+	}
+
+	scheduledTime := cronSchedule.Next(earliest)
+	if scheduledTime.IsZero() || scheduledTime.After(now) {
+		return time.Time{}, 0
+	}
+	missedRuns := 0
+	for {
+		next := cronSchedule.Next(scheduledTime)
+		if next.IsZero() || next.After(now) {
+			break
+		}
+		scheduledTime = next
+		missedRuns++
+	}
+	return scheduledTime, missedRuns
+}
+
+// tooLate returns true if a run scheduled at scheduledTime can no longer be
+// started because its starting deadline has passed.
+func tooLate(schedule *crdv1alpha1.VolumeSnapshotSchedule, scheduledTime, now time.Time) bool {
+	if schedule.Spec.StartingDeadlineSeconds == nil {
+		return false
+	}
+	deadline := time.Duration(*schedule.Spec.StartingDeadlineSeconds) * time.Second
+	return now.Sub(scheduledTime) > deadline
+}
+
+// createScheduledSnapshots creates one VolumeSnapshot per source PVC of the
+// schedule for the run scheduled at scheduledTime. Snapshots that already
+// exist are not recreated.
+func (ctrl *snapshotScheduleController) createScheduledSnapshots(schedule *crdv1alpha1.VolumeSnapshotSchedule, scheduledTime time.Time) error {
+	pvcNames, err := ctrl.getScheduleClaimNames(schedule)
+	if err != nil {
+		return err
+	}
+	if len(pvcNames) == 0 {
+		ctrl.eventRecorder.Event(schedule, v1.EventTypeWarning, "NoPersistentVolumeClaims",
+			fmt.Sprintf("No PersistentVolumeClaims matched for the run scheduled at %s", scheduledTime.Format(time.RFC3339)))
+		return nil
+	}
+
+	var errs []error
+	for _, pvcName := range pvcNames {
+		if err := ctrl.createScheduledSnapshot(schedule, pvcName, scheduledTime); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func (ctrl *snapshotScheduleController) createScheduledSnapshot(schedule *crdv1alpha1.VolumeSnapshotSchedule, pvcName string, scheduledTime time.Time) error {
+	snapshotName, err := makeScheduledSnapshotName(schedule, pvcName, scheduledTime)
+	if err != nil {
+		return err
+	}
+
+	snapshotLabels := map[string]string{}
+	for k, v := range schedule.Spec.SnapshotLabels {
+		snapshotLabels[k] = v
+	}
+	snapshotLabels[utils.VolumeSnapshotScheduleLabel] = schedule.Name
+
+	snapshot := &crdv1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      snapshotName,
+			Namespace: schedule.Namespace,
+			Labels:    snapshotLabels,
+			Annotations: map[string]string{
+				utils.AnnVolumeSnapshotScheduledTime: scheduledTime.UTC().Format(time.RFC3339),
+			},
+		},
+		Spec: crdv1.VolumeSnapshotSpec{
+			Source: crdv1.VolumeSnapshotSource{
+				PersistentVolumeClaimName: &pvcName,
+			},
+			VolumeSnapshotClassName: schedule.Spec.VolumeSnapshotClassName,
+		},
+	}
+
+	klog.V(5).Infof("createScheduledSnapshot: creating VolumeSnapshot %s/%s for schedule %s", snapshot.Namespace, snapshot.Name, schedule.Name)
+	_, err = ctrl.clientset.SnapshotV1().VolumeSnapshots(schedule.Namespace).Create(context.TODO(), snapshot, metav1.CreateOptions{})
+	if err != nil {
+		if !apierrs.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create VolumeSnapshot %s for PersistentVolumeClaim %s: %v", snapshotName, pvcName, err)
+		}
+		existing, err := ctrl.clientset.SnapshotV1().VolumeSnapshots(schedule.Namespace).Get(context.TODO(), snapshotName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get VolumeSnapshot %s: %v", snapshotName, err)
+		}
+		if existing.Labels[utils.VolumeSnapshotScheduleLabel] != schedule.Name {
+			return fmt.Errorf("VolumeSnapshot %s already exists and was not created by this schedule", snapshotName)
+		}
+		klog.V(4).Infof("createScheduledSnapshot: VolumeSnapshot %s/%s already exists", snapshot.Namespace, snapshot.Name)
+		return nil
+	}
+
+	ctrl.eventRecorder.Event(schedule, v1.EventTypeNormal, "VolumeSnapshotCreated",
+		fmt.Sprintf("Created VolumeSnapshot %s for PersistentVolumeClaim %s", snapshotName, pvcName))
+	return nil
+}
+
+// getScheduleClaimNames returns the sorted names of the PVCs that are
+// snapshotted by a run of the schedule. PVCs that are being deleted are
+// skipped.
+func (ctrl *snapshotScheduleController) getScheduleClaimNames(schedule *crdv1alpha1.VolumeSnapshotSchedule) ([]string, error) {
+	source := schedule.Spec.Source
+	if source.PersistentVolumeClaimName != nil {
+		pvc, err := ctrl.pvcLister.PersistentVolumeClaims(schedule.Namespace).Get(*source.PersistentVolumeClaimName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve PersistentVolumeClaim %s: %v", *source.PersistentVolumeClaimName, err)
+		}
+		if pvc.DeletionTimestamp != nil {
+			return nil, nil
+		}
+		return []string{pvc.Name}, nil
+	}
+
+	if source.Selector == nil {
+		return nil, fmt.Errorf("neither persistentVolumeClaimName nor selector is set")
+	}
+	selector, err := metav1.LabelSelectorAsSelector(source.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PersistentVolumeClaim selector: %v", err)
+	}
+	pvcs, err := ctrl.pvcLister.PersistentVolumeClaims(schedule.Namespace).List(selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list PersistentVolumeClaims: %v", err)
+	}
+	names := []string{}
+	for _, pvc := range pvcs {
+		if pvc.DeletionTimestamp == nil {
+			names = append(names, pvc.Name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// makeScheduledSnapshotName resolves the snapshot name template of a schedule.
+func makeScheduledSnapshotName(schedule *crdv1alpha1.VolumeSnapshotSchedule, pvcName string, scheduledTime time.Time) (string, error) {
+	params := map[string]string{
+		scheduleNameToken: schedule.Name,
+		pvcNameToken:      pvcName,
+		timestampToken:    scheduledTime.UTC().Format(scheduledTimestampFormat),
+	}
+	missingParams := sets.NewString()
+	name := os.Expand(getSnapshotNameTemplate(schedule), func(k string) string {
+		v, ok := params[k]
+		if !ok {
+			missingParams.Insert(k)
+		}
+		return v
+	})
+	if missingParams.Len() > 0 {
+		return "", fmt.Errorf("invalid tokens in snapshot name template: %q", missingParams.List())
+	}
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return "", fmt.Errorf("snapshot name %q is invalid: %s", name, strings.Join(errs, ", "))
+	}
+	return name, nil
+}
+
+// getLastSuccessfulSnapshot returns the name of the most recently created
+// snapshot of the schedule that is ready to use, or nil if there is none.
+func (ctrl *snapshotScheduleController) getLastSuccessfulSnapshot(schedule *crdv1alpha1.VolumeSnapshotSchedule) *string {
+	selector := labels.SelectorFromSet(labels.Set{utils.VolumeSnapshotScheduleLabel: schedule.Name})
+	snapshots, err := ctrl.snapshotLister.VolumeSnapshots(schedule.Namespace).List(selector)
+	if err != nil {
+		klog.Errorf("failed to list snapshots of schedule %s/%s: %v", schedule.Namespace, schedule.Name, err)
+		return nil
+	}
+
+	var last *crdv1.VolumeSnapshot
+	for _, snapshot := range snapshots {
+		if !utils.IsSnapshotReady(snapshot) {
+			continue
+		}
+		if last == nil || snapshotCreationTime(snapshot).After(snapshotCreationTime(last)) {
+			last = snapshot
+		}
+	}
+	if last == nil {
+		return nil
+	}
+	name := last.Name
+	return &name
+}
+
+// snapshotCreationTime returns the time the point-in-time snapshot was taken,
+// falling back to the creation time of the VolumeSnapshot object.
+func snapshotCreationTime(snapshot *crdv1.VolumeSnapshot) time.Time {
+	if snapshot.Status != nil && snapshot.Status.CreationTime != nil {
+		return snapshot.Status.CreationTime.Time
+	}
+	return snapshot.CreationTimestamp.Time
+}
+
+// updateScheduleStatus saves the new status of a schedule to the API server
+// if it differs from the current one.
+func (ctrl *snapshotScheduleController) updateScheduleStatus(schedule *crdv1alpha1.VolumeSnapshotSchedule, newStatus *crdv1alpha1.VolumeSnapshotScheduleStatus) error {
+	if equality.Semantic.DeepEqual(schedule.Status, newStatus) {
+		return nil
+	}
+	scheduleClone := schedule.DeepCopy()
+	scheduleClone.Status = newStatus
+	_, err := ctrl.clientset.SnapshotV1alpha1().VolumeSnapshotSchedules(schedule.Namespace).UpdateStatus(context.TODO(), scheduleClone, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to update status of VolumeSnapshotSchedule %s/%s: %v", schedule.Namespace, schedule.Name, err)
+	}
+	return nil
+}
+
+// updateScheduleErrorStatusWithEvent saves the given error message in the
+// schedule status and emits a warning event with the same message.
+func (ctrl *snapshotScheduleController) updateScheduleErrorStatusWithEvent(schedule *crdv1alpha1.VolumeSnapshotSchedule, now time.Time, reason, message string) {
+	klog.V(4).Infof("VolumeSnapshotSchedule[%s/%s]: %s", schedule.Namespace, schedule.Name, message)
+	ctrl.eventRecorder.Event(schedule, v1.EventTypeWarning, reason, message)
+
+	if schedule.Status != nil && schedule.Status.Error != nil && schedule.Status.Error.Message != nil && *schedule.Status.Error.Message == message {
+		return
+	}
+	newStatus := &crdv1alpha1.VolumeSnapshotScheduleStatus{}
+	if schedule.Status != nil {
+		newStatus = schedule.Status.DeepCopy()
+	}
+	newStatus.Error = &crdv1.VolumeSnapshotError{
+		Time:    &metav1.Time{Time: now},
+		Message: &message,
+	}
+	if err := ctrl.updateScheduleStatus(schedule, newStatus); err != nil {
+		klog.V(4).Infof("updating VolumeSnapshotSchedule[%s/%s] error status failed %v", schedule.Namespace, schedule.Name, err)
+	}
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common_controller
+
+import (
+	"time"
+
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	crdv1alpha1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1alpha1"
+	clientset "github.com/kubernetes-csi/external-snapshotter/client/v8/clientset/versioned"
+	snapshotinformers "github.com/kubernetes-csi/external-snapshotter/client/v8/informers/externalversions/volumesnapshot/v1"
+	scheduleinformers "github.com/kubernetes-csi/external-snapshotter/client/v8/informers/externalversions/volumesnapshot/v1alpha1"
+	snapshotlisters "github.com/kubernetes-csi/external-snapshotter/client/v8/listers/volumesnapshot/v1"
+	schedulelisters "github.com/kubernetes-csi/external-snapshotter/client/v8/listers/volumesnapshot/v1alpha1"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	klog "k8s.io/klog/v2"
+)
+
+// snapshotScheduleController creates VolumeSnapshots on behalf of
+// VolumeSnapshotSchedule objects. It runs next to csiSnapshotCommonController
+// in the snapshot-controller, which takes care of the created snapshots like
+// of any other VolumeSnapshot.
+type snapshotScheduleController struct {
+	clientset     clientset.Interface
+	client        kubernetes.Interface
+	eventRecorder record.EventRecorder
+	scheduleQueue workqueue.TypedRateLimitingInterface[string]
+
+	scheduleLister       schedulelisters.VolumeSnapshotScheduleLister
+	scheduleListerSynced cache.InformerSynced
+	snapshotLister       snapshotlisters.VolumeSnapshotLister
+	snapshotListerSynced cache.InformerSynced
+	pvcLister            corelisters.PersistentVolumeClaimLister
+	pvcListerSynced      cache.InformerSynced
+
+	resyncPeriod time.Duration
+
+	// now returns the current time. It is replaced in unit tests.
+	now func() time.Time
+}
+
+// NewSnapshotScheduleController returns a new *snapshotScheduleController
+func NewSnapshotScheduleController(
+	clientset clientset.Interface,
+	client kubernetes.Interface,
+	volumeSnapshotScheduleInformer scheduleinformers.VolumeSnapshotScheduleInformer,
+	volumeSnapshotInformer snapshotinformers.VolumeSnapshotInformer,
+	pvcInformer coreinformers.PersistentVolumeClaimInformer,
+	resyncPeriod time.Duration,
+	scheduleRateLimiter workqueue.TypedRateLimiter[string],
+) *snapshotScheduleController {
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartLogging(klog.Infof)
+	broadcaster.StartRecordingToSink(&corev1.EventSinkImpl{Interface: client.CoreV1().Events(v1.NamespaceAll)})
+	eventRecorder := broadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: "snapshot-controller"})
+
+	ctrl := &snapshotScheduleController{
+		clientset:     clientset,
+		client:        client,
+		eventRecorder: eventRecorder,
+		resyncPeriod:  resyncPeriod,
+		scheduleQueue: workqueue.NewTypedRateLimitingQueueWithConfig(scheduleRateLimiter,
+			workqueue.TypedRateLimitingQueueConfig[string]{
+				Name: "snapshot-controller-schedule"}),
+		now: time.Now,
+	}
+
+	volumeSnapshotScheduleInformer.Informer().AddEventHandlerWithResyncPeriod(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { ctrl.enqueueScheduleWork(obj) },
+			UpdateFunc: func(oldObj, newObj interface{}) { ctrl.enqueueScheduleWork(newObj) },
+			DeleteFunc: func(obj interface{}) { ctrl.enqueueScheduleWork(obj) },
+		},
+		ctrl.resyncPeriod,
+	)
+	ctrl.scheduleLister = volumeSnapshotScheduleInformer.Lister()
+	ctrl.scheduleListerSynced = volumeSnapshotScheduleInformer.Informer().HasSynced
+
+	// Snapshots created by a schedule are watched to keep
+	// status.lastSuccessfulSnapshot up to date.
+	volumeSnapshotInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { ctrl.enqueueScheduleForSnapshot(obj) },
+			UpdateFunc: func(oldObj, newObj interface{}) { ctrl.enqueueScheduleForSnapshot(newObj) },
+			DeleteFunc: func(obj interface{}) { ctrl.enqueueScheduleForSnapshot(obj) },
+		},
+	)
+	ctrl.snapshotLister = volumeSnapshotInformer.Lister()
+	ctrl.snapshotListerSynced = volumeSnapshotInformer.Informer().HasSynced
+
+	ctrl.pvcLister = pvcInformer.Lister()
+	ctrl.pvcListerSynced = pvcInformer.Informer().HasSynced
+
+	return ctrl
+}
+
+func (ctrl *snapshotScheduleController) Run(workers int, stopCh <-chan struct{}) {
+	defer ctrl.scheduleQueue.ShutDown()
+
+	klog.Infof("Starting snapshot schedule controller")
+	defer klog.Infof("Shutting snapshot schedule controller")
+
+	if !cache.WaitForCacheSync(stopCh, ctrl.scheduleListerSynced, ctrl.snapshotListerSynced, ctrl.pvcListerSynced) {
+		klog.Errorf("Cannot sync caches")
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.Until(ctrl.scheduleWorker, 0, stopCh)
+	}
+
+	<-stopCh
+}
+
+// enqueueScheduleWork adds a schedule to the work queue.
+func (ctrl *snapshotScheduleController) enqueueScheduleWork(obj interface{}) {
+	// Beware of "xxx deleted" events
+	if unknown, ok := obj.(cache.DeletedFinalStateUnknown); ok && unknown.Obj != nil {
+		obj = unknown.Obj
+	}
+	if schedule, ok := obj.(*crdv1alpha1.VolumeSnapshotSchedule); ok {
+		objName, err := cache.DeletionHandlingMetaNamespaceKeyFunc(schedule)
+		if err != nil {
+			klog.Errorf("failed to get key from object: %v, %v", err, schedule)
+			return
+		}
+		klog.V(5).Infof("enqueued %q for sync", objName)
+		ctrl.scheduleQueue.Add(objName)
+	}
+}
+
+// enqueueScheduleForSnapshot adds the schedule that created the given
+// snapshot, if any, to the work queue.
+func (ctrl *snapshotScheduleController) enqueueScheduleForSnapshot(obj interface{}) {
+	// Beware of "xxx deleted" events
+	if unknown, ok := obj.(cache.DeletedFinalStateUnknown); ok && unknown.Obj != nil {
+		obj = unknown.Obj
+	}
+	snapshot, ok := obj.(*crdv1.VolumeSnapshot)
+	if !ok {
+		return
+	}
+	scheduleName, ok := snapshot.Labels[utils.VolumeSnapshotScheduleLabel]
+	if !ok || scheduleName == "" {
+		return
+	}
+	key := snapshot.Namespace + "/" + scheduleName
+	klog.V(5).Infof("enqueued %q for sync on change of snapshot %s", key, utils.SnapshotKey(snapshot))
+	ctrl.scheduleQueue.Add(key)
+}
+
+// scheduleWorker is the main worker for VolumeSnapshotSchedules.
+func (ctrl *snapshotScheduleController) scheduleWorker() {
+	key, quit := ctrl.scheduleQueue.Get()
+	if quit {
+		return
+	}
+	defer ctrl.scheduleQueue.Done(key)
+
+	requeueAfter, err := ctrl.syncScheduleByKey(key)
+	if err != nil {
+		// Rather than wait for a full resync, re-add the key to the
+		// queue to be processed.
+		ctrl.scheduleQueue.AddRateLimited(key)
+		klog.V(4).Infof("Failed to sync schedule %q, will retry again: %v", key, err)
+		return
+	}
+	// Finally, if no error occurs we Forget this item so it does not
+	// get queued again until another change happens or the next run
+	// of the schedule is due.
+	ctrl.scheduleQueue.Forget(key)
+	if requeueAfter > 0 {
+		ctrl.scheduleQueue.AddAfter(key, requeueAfter)
+	}
+}
+
+// syncScheduleByKey processes a VolumeSnapshotSchedule. It returns the
+// duration after which the schedule must be synced again.
+func (ctrl *snapshotScheduleController) syncScheduleByKey(key string) (time.Duration, error) {
+	klog.V(5).Infof("syncScheduleByKey[%s]", key)
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		klog.Errorf("error getting namespace & name of schedule %q to get schedule from informer: %v", key, err)
+		return 0, nil
+	}
+	schedule, err := ctrl.scheduleLister.VolumeSnapshotSchedules(namespace).Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Snapshots created by a deleted schedule are left alone.
+			klog.V(4).Infof("schedule %q has been deleted", key)
+			return 0, nil
+		}
+		klog.V(2).Infof("error getting schedule %q from informer: %v", key, err)
+		return 0, err
+	}
+	return ctrl.syncSchedule(schedule.DeepCopy())
+}
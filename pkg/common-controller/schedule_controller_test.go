@@ -0,0 +1,378 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common_controller
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	crdv1alpha1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1alpha1"
+	"github.com/kubernetes-csi/external-snapshotter/client/v8/clientset/versioned/fake"
+	informers "github.com/kubernetes-csi/external-snapshotter/client/v8/informers/externalversions"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	coreinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const testScheduleNamespace = "default"
+
+type scheduleTest struct {
+	name     string
+	schedule *crdv1alpha1.VolumeSnapshotSchedule
+	pvcs     []*v1.PersistentVolumeClaim
+	// snapshots initially present in the API server and informer
+	snapshots []*crdv1.VolumeSnapshot
+	now       string
+
+	expectedSnapshots        []string
+	expectedRequeue          time.Duration
+	expectError              bool
+	expectedLastScheduleTime string
+	expectedLastSuccessful   string
+	expectedStatusError      bool
+	expectedEvents           []string
+}
+
+func newSchedule(name, cronSpec, created string, lastScheduled string) *crdv1alpha1.VolumeSnapshotSchedule {
+	pvcName := "claim1"
+	schedule := &crdv1alpha1.VolumeSnapshotSchedule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         testScheduleNamespace,
+			CreationTimestamp: metav1.Time{Time: mustParseTime(created)},
+		},
+		Spec: crdv1alpha1.VolumeSnapshotScheduleSpec{
+			Schedule: cronSpec,
+			Source: crdv1alpha1.VolumeSnapshotScheduleSource{
+				PersistentVolumeClaimName: &pvcName,
+			},
+		},
+	}
+	if lastScheduled != "" {
+		schedule.Status = &crdv1alpha1.VolumeSnapshotScheduleStatus{
+			LastScheduleTime: &metav1.Time{Time: mustParseTime(lastScheduled)},
+		}
+	}
+	return schedule
+}
+
+func withScheduleSelector(schedule *crdv1alpha1.VolumeSnapshotSchedule, selector map[string]string) *crdv1alpha1.VolumeSnapshotSchedule {
+	schedule.Spec.Source.PersistentVolumeClaimName = nil
+	schedule.Spec.Source.Selector = &metav1.LabelSelector{MatchLabels: selector}
+	return schedule
+}
+
+func withStartingDeadline(schedule *crdv1alpha1.VolumeSnapshotSchedule, seconds int64) *crdv1alpha1.VolumeSnapshotSchedule {
+	schedule.Spec.StartingDeadlineSeconds = &seconds
+	return schedule
+}
+
+func withSuspend(schedule *crdv1alpha1.VolumeSnapshotSchedule) *crdv1alpha1.VolumeSnapshotSchedule {
+	suspend := true
+	schedule.Spec.Suspend = &suspend
+	return schedule
+}
+
+func withNameTemplate(schedule *crdv1alpha1.VolumeSnapshotSchedule, template string) *crdv1alpha1.VolumeSnapshotSchedule {
+	schedule.Spec.SnapshotNameTemplate = &template
+	return schedule
+}
+
+func newScheduleClaim(name string, claimLabels map[string]string) *v1.PersistentVolumeClaim {
+	return &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testScheduleNamespace,
+			Labels:    claimLabels,
+		},
+	}
+}
+
+func newScheduledSnapshot(name, scheduleName string, ready bool, created string) *crdv1.VolumeSnapshot {
+	creationTime := metav1.Time{Time: mustParseTime(created)}
+	return &crdv1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testScheduleNamespace,
+			Labels:    map[string]string{utils.VolumeSnapshotScheduleLabel: scheduleName},
+		},
+		Status: &crdv1.VolumeSnapshotStatus{
+			ReadyToUse:   &ready,
+			CreationTime: &creationTime,
+		},
+	}
+}
+
+func mustParseTime(value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestSyncSchedule(t *testing.T) {
+	tests := []scheduleTest{
+		{
+			name:              "1-1 - run not due yet",
+			schedule:          newSchedule("hourly", "0 * * * *", "2024-01-01T09:30:00Z", ""),
+			pvcs:              []*v1.PersistentVolumeClaim{newScheduleClaim("claim1", nil)},
+			now:               "2024-01-01T09:45:00Z",
+			expectedSnapshots: []string{},
+			expectedRequeue:   15 * time.Minute,
+		},
+		{
+			name:                     "1-2 - first run creates a snapshot",
+			schedule:                 newSchedule("hourly", "0 * * * *", "2024-01-01T09:30:00Z", ""),
+			pvcs:                     []*v1.PersistentVolumeClaim{newScheduleClaim("claim1", nil)},
+			now:                      "2024-01-01T10:00:10Z",
+			expectedSnapshots:        []string{"hourly-claim1-20240101100000"},
+			expectedRequeue:          59*time.Minute + 50*time.Second,
+			expectedLastScheduleTime: "2024-01-01T10:00:00Z",
+			expectedEvents:           []string{"Normal VolumeSnapshotCreated"},
+		},
+		{
+			name:                     "1-3 - only the most recent missed run is started",
+			schedule:                 withScheduleSelector(newSchedule("hourly", "0 * * * *", "2024-01-01T00:00:00Z", "2024-01-01T07:00:00Z"), map[string]string{"app": "db"}),
+			pvcs:                     []*v1.PersistentVolumeClaim{newScheduleClaim("data", map[string]string{"app": "db"}), newScheduleClaim("logs", map[string]string{"app": "db"}), newScheduleClaim("other", nil)},
+			now:                      "2024-01-01T10:30:00Z",
+			expectedSnapshots:        []string{"hourly-data-20240101100000", "hourly-logs-20240101100000"},
+			expectedRequeue:          30 * time.Minute,
+			expectedLastScheduleTime: "2024-01-01T10:00:00Z",
+			expectedEvents:           []string{"Normal VolumeSnapshotCreated", "Normal VolumeSnapshotCreated", "Warning MissedSchedule"},
+		},
+		{
+			name:                     "1-4 - missed run beyond the starting deadline is skipped",
+			schedule:                 withStartingDeadline(newSchedule("hourly", "0 * * * *", "2024-01-01T00:00:00Z", "2024-01-01T07:00:00Z"), 300),
+			pvcs:                     []*v1.PersistentVolumeClaim{newScheduleClaim("claim1", nil)},
+			now:                      "2024-01-01T10:30:00Z",
+			expectedSnapshots:        []string{},
+			expectedRequeue:          30 * time.Minute,
+			expectedLastScheduleTime: "2024-01-01T10:00:00Z",
+			expectedEvents:           []string{"Warning MissedSchedule"},
+		},
+		{
+			name:                     "1-5 - missed run within the starting deadline is started",
+			schedule:                 withStartingDeadline(newSchedule("hourly", "0 * * * *", "2024-01-01T00:00:00Z", "2024-01-01T09:00:00Z"), 3600),
+			pvcs:                     []*v1.PersistentVolumeClaim{newScheduleClaim("claim1", nil)},
+			now:                      "2024-01-01T10:30:00Z",
+			expectedSnapshots:        []string{"hourly-claim1-20240101100000"},
+			expectedRequeue:          30 * time.Minute,
+			expectedLastScheduleTime: "2024-01-01T10:00:00Z",
+			expectedEvents:           []string{"Normal VolumeSnapshotCreated"},
+		},
+		{
+			name:                     "1-6 - suspended schedule does not create snapshots",
+			schedule:                 withSuspend(newSchedule("hourly", "0 * * * *", "2024-01-01T00:00:00Z", "2024-01-01T07:00:00Z")),
+			pvcs:                     []*v1.PersistentVolumeClaim{newScheduleClaim("claim1", nil)},
+			now:                      "2024-01-01T10:30:00Z",
+			expectedSnapshots:        []string{},
+			expectedLastScheduleTime: "2024-01-01T07:00:00Z",
+		},
+		{
+			name:                "1-7 - invalid cron expression",
+			schedule:            newSchedule("hourly", "0 * * *", "2024-01-01T00:00:00Z", ""),
+			now:                 "2024-01-01T10:30:00Z",
+			expectedSnapshots:   []string{},
+			expectedStatusError: true,
+			expectedEvents:      []string{"Warning InvalidSchedule"},
+		},
+		{
+			name:                "1-8 - name template without timestamp is rejected",
+			schedule:            withNameTemplate(newSchedule("hourly", "0 * * * *", "2024-01-01T00:00:00Z", ""), "${pvc.name}-snap"),
+			now:                 "2024-01-01T10:30:00Z",
+			expectedSnapshots:   []string{},
+			expectedStatusError: true,
+			expectedEvents:      []string{"Warning InvalidSchedule"},
+		},
+		{
+			name:                     "1-9 - custom name template",
+			schedule:                 withNameTemplate(newSchedule("nightly", "@daily", "2024-01-01T12:00:00Z", ""), "backup-${timestamp}"),
+			pvcs:                     []*v1.PersistentVolumeClaim{newScheduleClaim("claim1", nil)},
+			now:                      "2024-01-02T00:00:00Z",
+			expectedSnapshots:        []string{"backup-20240102000000"},
+			expectedRequeue:          24 * time.Hour,
+			expectedLastScheduleTime: "2024-01-02T00:00:00Z",
+			expectedEvents:           []string{"Normal VolumeSnapshotCreated"},
+		},
+		{
+			name:                     "1-10 - existing snapshot of a retried run is not recreated",
+			schedule:                 newSchedule("hourly", "0 * * * *", "2024-01-01T09:30:00Z", ""),
+			pvcs:                     []*v1.PersistentVolumeClaim{newScheduleClaim("claim1", nil)},
+			snapshots:                []*crdv1.VolumeSnapshot{newScheduledSnapshot("hourly-claim1-20240101100000", "hourly", false, "2024-01-01T10:00:00Z")},
+			now:                      "2024-01-01T10:00:10Z",
+			expectedSnapshots:        []string{"hourly-claim1-20240101100000"},
+			expectedRequeue:          59*time.Minute + 50*time.Second,
+			expectedLastScheduleTime: "2024-01-01T10:00:00Z",
+		},
+		{
+			name:                "1-11 - name conflict with a foreign snapshot",
+			schedule:            newSchedule("hourly", "0 * * * *", "2024-01-01T09:30:00Z", ""),
+			pvcs:                []*v1.PersistentVolumeClaim{newScheduleClaim("claim1", nil)},
+			snapshots:           []*crdv1.VolumeSnapshot{newScheduledSnapshot("hourly-claim1-20240101100000", "other", false, "2024-01-01T10:00:00Z")},
+			now:                 "2024-01-01T10:00:10Z",
+			expectedSnapshots:   []string{"hourly-claim1-20240101100000"},
+			expectError:         true,
+			expectedStatusError: true,
+			expectedEvents:      []string{"Warning SnapshotScheduleFailed"},
+		},
+		{
+			name:                "1-12 - missing PVC",
+			schedule:            newSchedule("hourly", "0 * * * *", "2024-01-01T09:30:00Z", ""),
+			now:                 "2024-01-01T10:00:10Z",
+			expectedSnapshots:   []string{},
+			expectError:         true,
+			expectedStatusError: true,
+			expectedEvents:      []string{"Warning SnapshotScheduleFailed"},
+		},
+		{
+			name:     "1-13 - last successful snapshot is the newest ready one",
+			schedule: newSchedule("hourly", "0 * * * *", "2024-01-01T07:30:00Z", "2024-01-01T10:00:00Z"),
+			pvcs:     []*v1.PersistentVolumeClaim{newScheduleClaim("claim1", nil)},
+			snapshots: []*crdv1.VolumeSnapshot{
+				newScheduledSnapshot("hourly-claim1-20240101080000", "hourly", true, "2024-01-01T08:00:00Z"),
+				newScheduledSnapshot("hourly-claim1-20240101090000", "hourly", true, "2024-01-01T09:00:00Z"),
+				newScheduledSnapshot("hourly-claim1-20240101100000", "hourly", false, "2024-01-01T10:00:00Z"),
+			},
+			now:                      "2024-01-01T10:30:00Z",
+			expectedSnapshots:        []string{"hourly-claim1-20240101080000", "hourly-claim1-20240101090000", "hourly-claim1-20240101100000"},
+			expectedRequeue:          30 * time.Minute,
+			expectedLastScheduleTime: "2024-01-01T10:00:00Z",
+			expectedLastSuccessful:   "hourly-claim1-20240101090000",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			runScheduleTest(t, test)
+		})
+	}
+}
+
+func runScheduleTest(t *testing.T, test scheduleTest) {
+	objs := []runtime.Object{test.schedule}
+	for _, snapshot := range test.snapshots {
+		objs = append(objs, snapshot)
+	}
+	client := fake.NewSimpleClientset(objs...)
+	kubeObjs := []runtime.Object{}
+	for _, pvc := range test.pvcs {
+		kubeObjs = append(kubeObjs, pvc)
+	}
+	kubeClient := kubefake.NewSimpleClientset(kubeObjs...)
+
+	factory := informers.NewSharedInformerFactory(client, 0)
+	coreFactory := coreinformers.NewSharedInformerFactory(kubeClient, 0)
+	ctrl := NewSnapshotScheduleController(
+		client,
+		kubeClient,
+		factory.Snapshot().V1alpha1().VolumeSnapshotSchedules(),
+		factory.Snapshot().V1().VolumeSnapshots(),
+		coreFactory.Core().V1().PersistentVolumeClaims(),
+		0,
+		workqueue.DefaultTypedControllerRateLimiter[string](),
+	)
+	fakeRecorder := record.NewFakeRecorder(100)
+	ctrl.eventRecorder = fakeRecorder
+	ctrl.now = func() time.Time { return mustParseTime(test.now) }
+
+	for _, pvc := range test.pvcs {
+		coreFactory.Core().V1().PersistentVolumeClaims().Informer().GetIndexer().Add(pvc)
+	}
+	for _, snapshot := range test.snapshots {
+		factory.Snapshot().V1().VolumeSnapshots().Informer().GetIndexer().Add(snapshot)
+	}
+
+	requeue, err := ctrl.syncSchedule(test.schedule.DeepCopy())
+	if test.expectError && err == nil {
+		t.Errorf("expected error, got none")
+	}
+	if !test.expectError && err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if requeue != test.expectedRequeue {
+		t.Errorf("expected requeue after %v, got %v", test.expectedRequeue, requeue)
+	}
+
+	snapshots, err := client.SnapshotV1().VolumeSnapshots(testScheduleNamespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to list snapshots: %v", err)
+	}
+	names := []string{}
+	for _, snapshot := range snapshots.Items {
+		names = append(names, snapshot.Name)
+		if snapshot.Spec.Source.PersistentVolumeClaimName == nil {
+			continue
+		}
+		if snapshot.Labels[utils.VolumeSnapshotScheduleLabel] != test.schedule.Name && len(test.snapshots) == 0 {
+			t.Errorf("snapshot %s is missing the schedule label", snapshot.Name)
+		}
+	}
+	sort.Strings(names)
+	if strings.Join(names, ",") != strings.Join(test.expectedSnapshots, ",") {
+		t.Errorf("expected snapshots %v, got %v", test.expectedSnapshots, names)
+	}
+
+	schedule, err := client.SnapshotV1alpha1().VolumeSnapshotSchedules(testScheduleNamespace).Get(context.TODO(), test.schedule.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get schedule: %v", err)
+	}
+	lastScheduleTime := ""
+	lastSuccessful := ""
+	statusError := false
+	if schedule.Status != nil {
+		if schedule.Status.LastScheduleTime != nil {
+			lastScheduleTime = schedule.Status.LastScheduleTime.UTC().Format(time.RFC3339)
+		}
+		if schedule.Status.LastSuccessfulSnapshot != nil {
+			lastSuccessful = *schedule.Status.LastSuccessfulSnapshot
+		}
+		statusError = schedule.Status.Error != nil
+	}
+	if lastScheduleTime != test.expectedLastScheduleTime {
+		t.Errorf("expected lastScheduleTime %q, got %q", test.expectedLastScheduleTime, lastScheduleTime)
+	}
+	if lastSuccessful != test.expectedLastSuccessful {
+		t.Errorf("expected lastSuccessfulSnapshot %q, got %q", test.expectedLastSuccessful, lastSuccessful)
+	}
+	if statusError != test.expectedStatusError {
+		t.Errorf("expected status error %v, got %v", test.expectedStatusError, statusError)
+	}
+
+	close(fakeRecorder.Events)
+	events := []string{}
+	for event := range fakeRecorder.Events {
+		events = append(events, event)
+	}
+	if len(events) != len(test.expectedEvents) {
+		t.Fatalf("expected events %v, got %v", test.expectedEvents, events)
+	}
+	for i, expected := range test.expectedEvents {
+		if !strings.HasPrefix(events[i], expected) {
+			t.Errorf("expected event %d to start with %q, got %q", i, expected, events[i])
+		}
+	}
+}
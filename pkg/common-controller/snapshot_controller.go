@@ -18,6 +18,7 @@ package common_controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"slices"
 	"strings"
@@ -29,13 +30,19 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes/scheme"
+	corelisters "k8s.io/client-go/listers/core/v1"
 	ref "k8s.io/client-go/tools/reference"
 	"k8s.io/client-go/util/retry"
 	corev1helpers "k8s.io/component-helpers/scheduling/corev1"
 	klog "k8s.io/klog/v2"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
 	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
 	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/metrics"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/tracing"
 	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
 )
 
@@ -90,6 +97,12 @@ func (ctrl *csiSnapshotCommonController) syncContent(content *crdv1.VolumeSnapsh
 	snapshotName := utils.SnapshotRefKey(&content.Spec.VolumeSnapshotRef)
 	klog.V(4).Infof("synchronizing VolumeSnapshotContent[%s]: content is bound to snapshot %s", content.Name, snapshotName)
 
+	if utils.IsContentAbandoned(content) {
+		// The content is being deleted and the administrator asked not to
+		// wait for the sidecar to delete the snapshot on the storage system.
+		return ctrl.abandonContent(content)
+	}
+
 	klog.V(5).Infof("syncContent[%s]: check if we should add invalid label on content", content.Name)
 
 	if (content.Spec.Source.VolumeHandle == nil && content.Spec.Source.SnapshotHandle == nil) ||
@@ -173,9 +186,38 @@ func (ctrl *csiSnapshotCommonController) syncContent(content *crdv1.VolumeSnapsh
 // created, updated or periodically synced. We do not differentiate between
 // these events.
 // For easier readability, it is split into syncUnreadySnapshot and syncReadySnapshot
-func (ctrl *csiSnapshotCommonController) syncSnapshot(ctx context.Context, snapshot *crdv1.VolumeSnapshot) error {
+func (ctrl *csiSnapshotCommonController) syncSnapshot(ctx context.Context, snapshot *crdv1.VolumeSnapshot) (err error) {
 	klog.V(5).Infof("synchronizing VolumeSnapshot[%s]: %s", utils.SnapshotKey(snapshot), utils.GetSnapshotStatusForLogging(snapshot))
 
+	// Trace the snapshot until it is bound to a content. The root span is
+	// started by the first sync and its trace context is stored on the
+	// snapshot, so that later syncs continue the same trace. The trace
+	// context is passed to the sidecar with an annotation on the content.
+	if snapshot.ObjectMeta.DeletionTimestamp == nil && !utils.IsBoundVolumeSnapshotContentNameSet(snapshot) {
+		attributes := trace.WithAttributes(
+			attribute.String("volumesnapshot.namespace", snapshot.Namespace),
+			attribute.String("volumesnapshot.name", snapshot.Name),
+		)
+		var span trace.Span
+		if metav1.HasAnnotation(snapshot.ObjectMeta, utils.AnnTraceParent) {
+			ctx, span = tracing.Start(tracing.ExtractAnnotation(ctx, snapshot.Annotations, utils.AnnTraceParent), "syncSnapshot", attributes)
+		} else {
+			ctx, span = tracing.Start(ctx, "VolumeSnapshot", attributes)
+			if snapshot, err = ctrl.setAnnTraceParent(ctx, snapshot); err != nil {
+				// Tracing does not hold the snapshot up, the next
+				// sync starts another trace.
+				klog.V(4).Infof("syncSnapshot[%s]: failed to store the trace context: %v", utils.SnapshotKey(snapshot), err)
+			}
+		}
+		defer func() {
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+			span.End()
+		}()
+	}
+
 	klog.V(5).Infof("syncSnapshot [%s]: check if we should remove finalizer on snapshot PVC source and remove it if we can", utils.SnapshotKey(snapshot))
 
 	// Check if we should remove finalizer on PVC and remove it if we can.
@@ -189,6 +231,12 @@ func (ctrl *csiSnapshotCommonController) syncSnapshot(ctx context.Context, snaps
 
 	// Proceed with snapshot deletion and remove finalizers when needed
 	if snapshot.ObjectMeta.DeletionTimestamp != nil {
+		// Run the post-snapshot hook, if any, so that the application is
+		// not left e.g. frozen by a snapshot that will never be cut.
+		if snapshot, err = ctrl.checkandRunPostSnapshotHooks(snapshot); err != nil {
+			klog.Errorf("error running post-snapshot hook for snapshot [%s]: %v", snapshot.Name, err)
+			return err
+		}
 		return ctrl.processSnapshotWithDeletionTimestamp(snapshot)
 	}
 
@@ -197,7 +245,7 @@ func (ctrl *csiSnapshotCommonController) syncSnapshot(ctx context.Context, snaps
 		(snapshot.Spec.Source.PersistentVolumeClaimName != nil && snapshot.Spec.Source.VolumeSnapshotContentName != nil) {
 		err := fmt.Errorf("Exactly one of PersistentVolumeClaimName and VolumeSnapshotContentName should be specified")
 		klog.Errorf("syncSnapshot[%s]: validation error, %s", utils.SnapshotKey(snapshot), err.Error())
-		ctrl.updateSnapshotErrorStatusWithEvent(snapshot, true, v1.EventTypeWarning, "SnapshotValidationError", err.Error())
+		ctrl.updateSnapshotErrorStatusWithEvent(snapshot, true, v1.EventTypeWarning, "SnapshotValidationError", err.Error(), nil)
 		return err
 	}
 
@@ -207,14 +255,79 @@ func (ctrl *csiSnapshotCommonController) syncSnapshot(ctx context.Context, snaps
 		ctrl.eventRecorder.Event(snapshot, v1.EventTypeWarning, "SnapshotFinalizerError", fmt.Sprintf("Failed to check and update snapshot: %s", err.Error()))
 		return err
 	}
+	klog.V(5).Infof("syncSnapshot[%s]: check if the snapshot has expired", utils.SnapshotKey(snapshot))
+	if deleted, err := ctrl.checkandDeleteExpiredSnapshot(ctx, snapshot); err != nil || deleted {
+		return err
+	}
+	klog.V(5).Infof("syncSnapshot[%s]: check if the post-snapshot hook should run", utils.SnapshotKey(snapshot))
+	snapshot, err = ctrl.checkandRunPostSnapshotHooks(snapshot)
+	if err != nil {
+		klog.Errorf("error running post-snapshot hook for snapshot [%s]: %v", snapshot.Name, err)
+		// A failed post command is retried with the backoff of the
+		// queue. It does not hold up the status of a snapshot that has
+		// been cut.
+		if !errors.Is(err, errPostSnapshotHookFailed) || !utils.IsSnapshotCreated(snapshot) {
+			return err
+		}
+	}
+	postHookErr := err
 	// Need to build or update snapshot.Status in following cases:
 	// 1) snapshot.Status is nil
 	// 2) snapshot.Status.ReadyToUse is false
 	// 3) snapshot.Status.BoundVolumeSnapshotContentName is not set
 	if !utils.IsSnapshotReady(snapshot) || !utils.IsBoundVolumeSnapshotContentNameSet(snapshot) {
-		return ctrl.syncUnreadySnapshot(snapshot)
+		err = ctrl.syncUnreadySnapshot(ctx, snapshot)
+	} else {
+		err = ctrl.syncReadySnapshot(ctx, snapshot)
 	}
-	return ctrl.syncReadySnapshot(ctx, snapshot)
+	if err != nil {
+		return err
+	}
+	return postHookErr
+}
+
+// checkandDeleteExpiredSnapshot deletes the snapshot once the expiry time requested by its
+// annotations has passed. A snapshot that has not expired yet is enqueued again at its expiry
+// time. Snapshots that belong to a group snapshot do not expire, as they can only be deleted
+// together with the group. It returns true if the snapshot has been deleted.
+func (ctrl *csiSnapshotCommonController) checkandDeleteExpiredSnapshot(ctx context.Context, snapshot *crdv1.VolumeSnapshot) (bool, error) {
+	expiry, expires, err := utils.GetSnapshotExpiryTime(snapshot)
+	if err != nil {
+		// Do not retry, the annotation must be fixed by the user which triggers a new sync.
+		klog.Errorf("checkandDeleteExpiredSnapshot[%s]: %v", utils.SnapshotKey(snapshot), err)
+		ctrl.eventRecorder.Event(snapshot, v1.EventTypeWarning, "InvalidSnapshotExpiry", err.Error())
+		ctrl.metricsManager.DropSnapshotExpiry(snapshot.UID)
+		return false, nil
+	}
+	if !expires {
+		ctrl.metricsManager.DropSnapshotExpiry(snapshot.UID)
+		return false, nil
+	}
+	if snapshot.Status != nil && snapshot.Status.VolumeGroupSnapshotName != nil {
+		klog.V(4).Infof("checkandDeleteExpiredSnapshot[%s]: ignoring expiry of snapshot that belongs to group snapshot %s", utils.SnapshotKey(snapshot), *snapshot.Status.VolumeGroupSnapshotName)
+		ctrl.metricsManager.DropSnapshotExpiry(snapshot.UID)
+		return false, nil
+	}
+
+	ctrl.metricsManager.SetSnapshotExpiry(snapshot.UID, expiry)
+	if remaining := time.Until(expiry); remaining > 0 {
+		klog.V(5).Infof("checkandDeleteExpiredSnapshot[%s]: snapshot expires at %s, requeueing in %v", utils.SnapshotKey(snapshot), expiry.Format(time.RFC3339), remaining)
+		ctrl.snapshotQueue.AddAfter(utils.SnapshotKey(snapshot), remaining)
+		return false, nil
+	}
+
+	klog.V(2).Infof("checkandDeleteExpiredSnapshot[%s]: snapshot expired at %s, deleting it", utils.SnapshotKey(snapshot), expiry.Format(time.RFC3339))
+	uid := snapshot.UID
+	err = ctrl.clientset.SnapshotV1().VolumeSnapshots(snapshot.Namespace).Delete(ctx, snapshot.Name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{UID: &uid},
+	})
+	if err != nil && !apierrs.IsNotFound(err) {
+		klog.Errorf("checkandDeleteExpiredSnapshot[%s]: failed to delete expired snapshot: %v", utils.SnapshotKey(snapshot), err)
+		ctrl.eventRecorder.Event(snapshot, v1.EventTypeWarning, "SnapshotExpiryFailed", fmt.Sprintf("Failed to delete expired snapshot: %v", err))
+		return false, err
+	}
+	ctrl.eventRecorder.Event(snapshot, v1.EventTypeNormal, "SnapshotExpired", fmt.Sprintf("Deleted snapshot that expired at %s", expiry.Format(time.RFC3339)))
+	return true, nil
 }
 
 // processSnapshotWithDeletionTimestamp processes finalizers and deletes the content when appropriate. It has the following steps:
@@ -222,6 +335,7 @@ func (ctrl *csiSnapshotCommonController) syncSnapshot(ctx context.Context, snaps
 // 2. Call checkandRemoveSnapshotFinalizersAndCheckandDeleteContent() with information obtained from step 1. This function name is very long but the name suggests what it does. It determines whether to remove finalizers on snapshot and whether to delete content.
 func (ctrl *csiSnapshotCommonController) processSnapshotWithDeletionTimestamp(snapshot *crdv1.VolumeSnapshot) error {
 	klog.V(5).Infof("processSnapshotWithDeletionTimestamp VolumeSnapshot[%s]: %s", utils.SnapshotKey(snapshot), utils.GetSnapshotStatusForLogging(snapshot))
+	ctrl.metricsManager.DropSnapshotExpiry(snapshot.UID)
 	driverName, err := ctrl.getSnapshotDriverName(snapshot)
 	if err != nil {
 		klog.Errorf("failed to getSnapshotDriverName while recording metrics for snapshot %q: %v", utils.SnapshotKey(snapshot), err)
@@ -288,7 +402,7 @@ func (ctrl *csiSnapshotCommonController) checkandRemoveSnapshotFinalizersAndChec
 		klog.V(4).Infof("checkandRemoveSnapshotFinalizersAndCheckandDeleteContent[%s]: snapshot is being used to restore a PVC", utils.SnapshotKey(snapshot))
 		ctrl.eventRecorder.Event(snapshot, v1.EventTypeWarning, "SnapshotDeletePending", "Snapshot is being used to restore a PVC")
 		// TODO(@xiangqian): should requeue this?
-		return nil
+		return ctrl.setSnapshotDeletionBlocked(snapshot, crdv1.VolumeSnapshotReasonUsedAsSource, "Snapshot is being used to restore a PVC")
 	}
 
 	removeGroupFinalizer := false
@@ -299,6 +413,9 @@ func (ctrl *csiSnapshotCommonController) checkandRemoveSnapshotFinalizersAndChec
 			msg := fmt.Sprintf("deletion of the individual volume snapshot %s is not allowed as it belongs to group snapshot %s. Deleting the group snapshot will trigger the deletion of all the individual volume snapshots that are part of the group.", utils.SnapshotKey(snapshot), utils.GroupSnapshotKey(groupSnapshot))
 			klog.Error(msg)
 			ctrl.eventRecorder.Event(snapshot, v1.EventTypeWarning, "SnapshotDeletePending", msg)
+			if err := ctrl.setSnapshotDeletionBlocked(snapshot, crdv1.VolumeSnapshotReasonInGroup, msg); err != nil {
+				return err
+			}
 			return fmt.Errorf(msg)
 		}
 		if !apierrs.IsNotFound(err) {
@@ -353,6 +470,24 @@ func (ctrl *csiSnapshotCommonController) checkandRemoveSnapshotFinalizersAndChec
 	return ctrl.removeSnapshotFinalizer(snapshot, true, removeBoundFinalizer, removeGroupFinalizer)
 }
 
+// setSnapshotDeletionBlocked sets the DeletionBlocked condition of a snapshot
+// whose deletion waits for other objects.
+func (ctrl *csiSnapshotCommonController) setSnapshotDeletionBlocked(snapshot *crdv1.VolumeSnapshot, reason, message string) error {
+	snapshotClone := snapshot.DeepCopy()
+	if snapshotClone.Status == nil {
+		snapshotClone.Status = &crdv1.VolumeSnapshotStatus{}
+	}
+	if !utils.SetDeletionBlockedCondition(&snapshotClone.Status.Conditions, snapshot.Generation, reason, message) {
+		return nil
+	}
+	newSnapshot, err := ctrl.clientset.SnapshotV1().VolumeSnapshots(snapshotClone.Namespace).UpdateStatus(context.TODO(), snapshotClone, metav1.UpdateOptions{})
+	if err != nil {
+		return newControllerUpdateError(utils.SnapshotKey(snapshot), err.Error())
+	}
+	_, err = ctrl.storeSnapshotUpdate(newSnapshot)
+	return err
+}
+
 // checkandAddSnapshotFinalizers checks and adds snapshot finailzers when needed
 func (ctrl *csiSnapshotCommonController) checkandAddSnapshotFinalizers(snapshot *crdv1.VolumeSnapshot) error {
 	// get the content for this Snapshot
@@ -406,13 +541,13 @@ func (ctrl *csiSnapshotCommonController) syncReadySnapshot(ctx context.Context,
 	if content == nil {
 		// this meant there is no matching content in cache found
 		// update status of the snapshot and return
-		return ctrl.updateSnapshotErrorStatusWithEvent(snapshot, true, v1.EventTypeWarning, "SnapshotContentMissing", "VolumeSnapshotContent is missing")
+		return ctrl.updateSnapshotErrorStatusWithEvent(snapshot, true, v1.EventTypeWarning, "SnapshotContentMissing", "VolumeSnapshotContent is missing", nil)
 	}
 	klog.V(5).Infof("syncReadySnapshot[%s]: VolumeSnapshotContent %q found", utils.SnapshotKey(snapshot), content.Name)
 	// check binding from content side to make sure the binding is still valid
 	if !utils.IsVolumeSnapshotRefSet(snapshot, content) {
 		// snapshot is bound but content is not pointing to the snapshot
-		return ctrl.updateSnapshotErrorStatusWithEvent(snapshot, true, v1.EventTypeWarning, "SnapshotMisbound", "VolumeSnapshotContent is not bound to the VolumeSnapshot correctly")
+		return ctrl.updateSnapshotErrorStatusWithEvent(snapshot, true, v1.EventTypeWarning, "SnapshotMisbound", "VolumeSnapshotContent is not bound to the VolumeSnapshot correctly", nil)
 	}
 
 	// If this snapshot is a member of a volume group snapshot, ensure we have
@@ -466,7 +601,7 @@ func (ctrl *csiSnapshotCommonController) addVolumeGroupSnapshotOwnership(ctx con
 }
 
 // syncUnreadySnapshot is the main controller method to decide what to do with a snapshot which is not set to ready.
-func (ctrl *csiSnapshotCommonController) syncUnreadySnapshot(snapshot *crdv1.VolumeSnapshot) error {
+func (ctrl *csiSnapshotCommonController) syncUnreadySnapshot(ctx context.Context, snapshot *crdv1.VolumeSnapshot) error {
 	uniqueSnapshotName := utils.SnapshotKey(snapshot)
 	klog.V(5).Infof("syncUnreadySnapshot %s", uniqueSnapshotName)
 	driverName, err := ctrl.getSnapshotDriverName(snapshot)
@@ -502,7 +637,7 @@ func (ctrl *csiSnapshotCommonController) syncUnreadySnapshot(snapshot *crdv1.Vol
 		// if no content found yet, update status and return
 		if content == nil {
 			// can not find the desired VolumeSnapshotContent from cache store
-			ctrl.updateSnapshotErrorStatusWithEvent(snapshot, true, v1.EventTypeWarning, "SnapshotContentMissing", "VolumeSnapshotContent is missing")
+			ctrl.updateSnapshotErrorStatusWithEvent(snapshot, true, v1.EventTypeWarning, "SnapshotContentMissing", "VolumeSnapshotContent is missing", nil)
 			klog.V(4).Infof("syncUnreadySnapshot[%s]: snapshot content %q requested but not found, will try again", utils.SnapshotKey(snapshot), *snapshot.Spec.Source.VolumeSnapshotContentName)
 
 			return fmt.Errorf("snapshot %s requests an non-existing content %s", utils.SnapshotKey(snapshot), *snapshot.Spec.Source.VolumeSnapshotContentName)
@@ -512,7 +647,7 @@ func (ctrl *csiSnapshotCommonController) syncUnreadySnapshot(snapshot *crdv1.Vol
 		newContent, err := ctrl.checkandBindSnapshotContent(snapshot, content)
 		if err != nil {
 			// snapshot is bound but content is not bound to snapshot correctly
-			ctrl.updateSnapshotErrorStatusWithEvent(snapshot, true, v1.EventTypeWarning, "SnapshotBindFailed", fmt.Sprintf("Snapshot failed to bind VolumeSnapshotContent, %v", err))
+			ctrl.updateSnapshotErrorStatusWithEvent(snapshot, true, v1.EventTypeWarning, "SnapshotBindFailed", fmt.Sprintf("Snapshot failed to bind VolumeSnapshotContent, %v", err), nil)
 			return fmt.Errorf("snapshot %s is bound, but VolumeSnapshotContent %s is not bound to the VolumeSnapshot correctly, %v", uniqueSnapshotName, content.Name, err)
 		}
 
@@ -521,7 +656,7 @@ func (ctrl *csiSnapshotCommonController) syncUnreadySnapshot(snapshot *crdv1.Vol
 		if _, err = ctrl.updateSnapshotStatus(snapshot, newContent); err != nil {
 			// update snapshot status failed
 			klog.V(4).Infof("failed to update snapshot %s status: %v", utils.SnapshotKey(snapshot), err)
-			ctrl.updateSnapshotErrorStatusWithEvent(snapshot, false, v1.EventTypeWarning, "SnapshotStatusUpdateFailed", fmt.Sprintf("Snapshot status update failed, %v", err))
+			ctrl.updateSnapshotErrorStatusWithEvent(snapshot, false, v1.EventTypeWarning, "SnapshotStatusUpdateFailed", fmt.Sprintf("Snapshot status update failed, %v", err), nil)
 			return err
 		}
 
@@ -555,7 +690,7 @@ func (ctrl *csiSnapshotCommonController) syncUnreadySnapshot(snapshot *crdv1.Vol
 		if _, err = ctrl.updateSnapshotStatus(snapshot, content); err != nil {
 			// update snapshot status failed
 			klog.V(4).Infof("failed to update group snapshot member %s status: %v", utils.SnapshotKey(snapshot), err)
-			ctrl.updateSnapshotErrorStatusWithEvent(snapshot, false, v1.EventTypeWarning, "SnapshotStatusUpdateFailed", fmt.Sprintf("Snapshot status update failed, %v", err))
+			ctrl.updateSnapshotErrorStatusWithEvent(snapshot, false, v1.EventTypeWarning, "SnapshotStatusUpdateFailed", fmt.Sprintf("Snapshot status update failed, %v", err), nil)
 			return err
 		}
 
@@ -573,7 +708,7 @@ func (ctrl *csiSnapshotCommonController) syncUnreadySnapshot(snapshot *crdv1.Vol
 	if contentObj != nil {
 		klog.V(5).Infof("Found VolumeSnapshotContent object %s for snapshot %s", contentObj.Name, uniqueSnapshotName)
 		if contentObj.Spec.Source.SnapshotHandle != nil {
-			ctrl.updateSnapshotErrorStatusWithEvent(snapshot, true, v1.EventTypeWarning, "SnapshotHandleSet", fmt.Sprintf("Snapshot handle should not be set in content %s for dynamic provisioning", uniqueSnapshotName))
+			ctrl.updateSnapshotErrorStatusWithEvent(snapshot, true, v1.EventTypeWarning, "SnapshotHandleSet", fmt.Sprintf("Snapshot handle should not be set in content %s for dynamic provisioning", uniqueSnapshotName), nil)
 			return fmt.Errorf("snapshotHandle should not be set in the content for dynamic provisioning for snapshot %s", uniqueSnapshotName)
 		}
 		newSnapshot, err := ctrl.bindandUpdateVolumeSnapshot(contentObj, snapshot)
@@ -587,12 +722,22 @@ func (ctrl *csiSnapshotCommonController) syncUnreadySnapshot(snapshot *crdv1.Vol
 
 	// If we reach here, it is a dynamically provisioned snapshot, and the volumeSnapshotContent object is not yet created.
 	if snapshot.Spec.Source.PersistentVolumeClaimName == nil {
-		ctrl.updateSnapshotErrorStatusWithEvent(snapshot, true, v1.EventTypeWarning, "SnapshotPVCSourceMissing", fmt.Sprintf("PVC source for snapshot %s is missing", uniqueSnapshotName))
+		ctrl.updateSnapshotErrorStatusWithEvent(snapshot, true, v1.EventTypeWarning, "SnapshotPVCSourceMissing", fmt.Sprintf("PVC source for snapshot %s is missing", uniqueSnapshotName), nil)
 		return fmt.Errorf("expected PVC source for snapshot %s but got nil", uniqueSnapshotName)
 	}
+	// Run the pre-snapshot hook, if any, before the snapshot is cut.
+	snapshot, proceed, err := ctrl.runPreSnapshotHooks(snapshot)
+	if err != nil || !proceed {
+		return err
+	}
 	var content *crdv1.VolumeSnapshotContent
-	if content, err = ctrl.createSnapshotContent(snapshot); err != nil {
-		ctrl.updateSnapshotErrorStatusWithEvent(snapshot, true, v1.EventTypeWarning, "SnapshotContentCreationFailed", fmt.Sprintf("Failed to create snapshot content with error %v", err))
+	if content, err = ctrl.createSnapshotContent(ctx, snapshot); err != nil {
+		if newSnapshot, hookErr := ctrl.abortSnapshotHooks(snapshot); hookErr != nil {
+			klog.Errorf("failed to abort hooks of snapshot %s: %v", uniqueSnapshotName, hookErr)
+		} else {
+			snapshot = newSnapshot
+		}
+		ctrl.updateSnapshotErrorStatusWithEvent(snapshot, true, v1.EventTypeWarning, "SnapshotContentCreationFailed", fmt.Sprintf("Failed to create snapshot content with error %v", err), err)
 		return err
 	}
 
@@ -600,7 +745,7 @@ func (ctrl *csiSnapshotCommonController) syncUnreadySnapshot(snapshot *crdv1.Vol
 	klog.V(5).Infof("syncUnreadySnapshot [%s]: trying to update snapshot status", utils.SnapshotKey(snapshot))
 	if _, err = ctrl.updateSnapshotStatus(snapshot, content); err != nil {
 		// update snapshot status failed
-		ctrl.updateSnapshotErrorStatusWithEvent(snapshot, false, v1.EventTypeWarning, "SnapshotStatusUpdateFailed", fmt.Sprintf("Snapshot status update failed, %v", err))
+		ctrl.updateSnapshotErrorStatusWithEvent(snapshot, false, v1.EventTypeWarning, "SnapshotStatusUpdateFailed", fmt.Sprintf("Snapshot status update failed, %v", err), nil)
 		return err
 	}
 	return nil
@@ -631,11 +776,13 @@ func (ctrl *csiSnapshotCommonController) getPreprovisionedContentFromStore(snaps
 		// can not find the desired VolumeSnapshotContent from cache store
 		return nil, nil
 	}
-	// check whether the content is a pre-provisioned VolumeSnapshotContent
-	if content.Spec.Source.SnapshotHandle == nil {
+	// check whether the content is a pre-provisioned VolumeSnapshotContent,
+	// a dynamically provisioned content transferred from another namespace
+	// is bound the same way
+	if content.Spec.Source.SnapshotHandle == nil && !metav1.HasAnnotation(content.ObjectMeta, utils.AnnVolumeSnapshotTransferredFrom) {
 		// found a content which represents a dynamically provisioned snapshot
 		// update the snapshot and return an error
-		ctrl.updateSnapshotErrorStatusWithEvent(snapshot, true, v1.EventTypeWarning, "SnapshotContentMismatch", "VolumeSnapshotContent is dynamically provisioned while expecting a pre-provisioned one")
+		ctrl.updateSnapshotErrorStatusWithEvent(snapshot, true, v1.EventTypeWarning, "SnapshotContentMismatch", "VolumeSnapshotContent is dynamically provisioned while expecting a pre-provisioned one", nil)
 		klog.V(4).Infof("sync snapshot[%s]: snapshot content %q is dynamically provisioned while expecting a pre-provisioned one", utils.SnapshotKey(snapshot), contentName)
 		return nil, fmt.Errorf("snapshot %s expects a pre-provisioned VolumeSnapshotContent %s but gets a dynamically provisioned one", utils.SnapshotKey(snapshot), contentName)
 	}
@@ -644,7 +791,7 @@ func (ctrl *csiSnapshotCommonController) getPreprovisionedContentFromStore(snaps
 	if ref.Name != snapshot.Name || ref.Namespace != snapshot.Namespace || (ref.UID != "" && ref.UID != snapshot.UID) {
 		klog.V(4).Infof("sync snapshot[%s]: VolumeSnapshotContent %s is bound to another snapshot %v", utils.SnapshotKey(snapshot), contentName, ref)
 		msg := fmt.Sprintf("VolumeSnapshotContent [%s] is bound to a different snapshot", contentName)
-		ctrl.updateSnapshotErrorStatusWithEvent(snapshot, true, v1.EventTypeWarning, "SnapshotContentMisbound", msg)
+		ctrl.updateSnapshotErrorStatusWithEvent(snapshot, true, v1.EventTypeWarning, "SnapshotContentMisbound", msg, nil)
 		return nil, fmt.Errorf(msg)
 	}
 	return content, nil
@@ -677,7 +824,7 @@ func (ctrl *csiSnapshotCommonController) getDynamicallyProvisionedContentFromSto
 	}
 	// check whether the content represents a dynamically provisioned snapshot
 	if content.Spec.Source.VolumeHandle == nil {
-		ctrl.updateSnapshotErrorStatusWithEvent(snapshot, true, v1.EventTypeWarning, "SnapshotContentMismatch", "VolumeSnapshotContent "+contentName+" is pre-provisioned while expecting a dynamically provisioned one")
+		ctrl.updateSnapshotErrorStatusWithEvent(snapshot, true, v1.EventTypeWarning, "SnapshotContentMismatch", "VolumeSnapshotContent "+contentName+" is pre-provisioned while expecting a dynamically provisioned one", nil)
 		klog.V(4).Infof("sync snapshot[%s]: snapshot content %s is pre-provisioned while expecting a dynamically provisioned one", utils.SnapshotKey(snapshot), contentName)
 		return nil, fmt.Errorf("snapshot %s expects a dynamically provisioned VolumeSnapshotContent %s but gets a pre-provisioned one", utils.SnapshotKey(snapshot), contentName)
 	}
@@ -690,7 +837,7 @@ func (ctrl *csiSnapshotCommonController) getDynamicallyProvisionedContentFromSto
 	if ref.Name != snapshot.Name || ref.Namespace != snapshot.Namespace || ref.UID != snapshot.UID {
 		klog.V(4).Infof("sync snapshot[%s]: VolumeSnapshotContent %s is bound to another snapshot %v", utils.SnapshotKey(snapshot), contentName, ref)
 		msg := fmt.Sprintf("VolumeSnapshotContent [%s] is bound to a different snapshot", contentName)
-		ctrl.updateSnapshotErrorStatusWithEvent(snapshot, true, v1.EventTypeWarning, "SnapshotContentMisbound", msg)
+		ctrl.updateSnapshotErrorStatusWithEvent(snapshot, true, v1.EventTypeWarning, "SnapshotContentMisbound", msg, nil)
 		return nil, fmt.Errorf(msg)
 	}
 	return content, nil
@@ -719,7 +866,7 @@ func (ctrl *csiSnapshotCommonController) getContentFromStore(contentName string)
 }
 
 // createSnapshotContent will only be called for dynamic provisioning
-func (ctrl *csiSnapshotCommonController) createSnapshotContent(snapshot *crdv1.VolumeSnapshot) (*crdv1.VolumeSnapshotContent, error) {
+func (ctrl *csiSnapshotCommonController) createSnapshotContent(ctx context.Context, snapshot *crdv1.VolumeSnapshot) (*crdv1.VolumeSnapshotContent, error) {
 	klog.Infof("createSnapshotContent: Creating content for snapshot %s through the plugin ...", utils.SnapshotKey(snapshot))
 
 	// If PVC is not being deleted and finalizer is not added yet, a finalizer should be added to PVC until snapshot is created
@@ -730,9 +877,9 @@ func (ctrl *csiSnapshotCommonController) createSnapshotContent(snapshot *crdv1.V
 		return nil, err
 	}
 
-	class, volume, contentName, snapshotterSecretRef, err := ctrl.getCreateSnapshotInput(snapshot)
+	class, volume, contentName, snapshotterCredentialsRef, err := ctrl.getCreateSnapshotInput(snapshot)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get input parameters to create snapshot %s: %q", snapshot.Name, err)
+		return nil, utils.WrapStatusError(err, fmt.Errorf("failed to get input parameters to create snapshot %s: %q", snapshot.Name, err))
 	}
 
 	// Create VolumeSnapshotContent in the database
@@ -778,20 +925,20 @@ func (ctrl *csiSnapshotCommonController) createSnapshotContent(snapshot *crdv1.V
 		}
 	}
 
-	// Set AnnDeletionSecretRefName and AnnDeletionSecretRefNamespace
-	if snapshotterSecretRef != nil {
-		klog.V(5).Infof("createSnapshotContent: set annotation [%s] on content [%s].", utils.AnnDeletionSecretRefName, snapshotContent.Name)
-		metav1.SetMetaDataAnnotation(&snapshotContent.ObjectMeta, utils.AnnDeletionSecretRefName, snapshotterSecretRef.Name)
-
-		klog.V(5).Infof("createSnapshotContent: set annotation [%s] on content [%s].", utils.AnnDeletionSecretRefNamespace, snapshotContent.Name)
-		metav1.SetMetaDataAnnotation(&snapshotContent.ObjectMeta, utils.AnnDeletionSecretRefNamespace, snapshotterSecretRef.Namespace)
+	// Set AnnDeletionSecretRefName and AnnDeletionSecretRefNamespace, or AnnDeletionCredentials
+	if snapshotterCredentialsRef != nil {
+		klog.V(5).Infof("createSnapshotContent: set credentials annotations %s on content [%s].", snapshotterCredentialsRef, snapshotContent.Name)
+		utils.SetCredentialsAnnotations(&snapshotContent.ObjectMeta, snapshotterCredentialsRef, utils.AnnDeletionSecretRefName, utils.AnnDeletionSecretRefNamespace, utils.AnnDeletionCredentials)
 	}
 
+	// Set AnnTraceParent, so that the sidecar continues the trace of the snapshot
+	tracing.InjectAnnotation(ctx, &snapshotContent.Annotations, utils.AnnTraceParent)
+
 	var updateContent *crdv1.VolumeSnapshotContent
 	klog.V(5).Infof("volume snapshot content %#v", snapshotContent)
 	// Try to create the VolumeSnapshotContent object
 	klog.V(5).Infof("createSnapshotContent [%s]: trying to save volume snapshot content %s", utils.SnapshotKey(snapshot), snapshotContent.Name)
-	if updateContent, err = ctrl.clientset.SnapshotV1().VolumeSnapshotContents().Create(context.TODO(), snapshotContent, metav1.CreateOptions{}); err == nil || apierrs.IsAlreadyExists(err) {
+	if updateContent, err = ctrl.clientset.SnapshotV1().VolumeSnapshotContents().Create(ctx, snapshotContent, metav1.CreateOptions{}); err == nil || apierrs.IsAlreadyExists(err) {
 		// Save succeeded.
 		if err != nil {
 			klog.V(3).Infof("volume snapshot content %q for snapshot %q already exists, reusing", snapshotContent.Name, utils.SnapshotKey(snapshot))
@@ -821,7 +968,7 @@ func (ctrl *csiSnapshotCommonController) createSnapshotContent(snapshot *crdv1.V
 	return updateContent, nil
 }
 
-func (ctrl *csiSnapshotCommonController) getCreateSnapshotInput(snapshot *crdv1.VolumeSnapshot) (*crdv1.VolumeSnapshotClass, *v1.PersistentVolume, string, *v1.SecretReference, error) {
+func (ctrl *csiSnapshotCommonController) getCreateSnapshotInput(snapshot *crdv1.VolumeSnapshot) (*crdv1.VolumeSnapshotClass, *v1.PersistentVolume, string, *utils.CredentialsReference, error) {
 	className := snapshot.Spec.VolumeSnapshotClassName
 	klog.V(5).Infof("getCreateSnapshotInput [%s]", snapshot.Name)
 	var class *crdv1.VolumeSnapshotClass
@@ -830,11 +977,11 @@ func (ctrl *csiSnapshotCommonController) getCreateSnapshotInput(snapshot *crdv1.
 		class, err = ctrl.getSnapshotClass(*className)
 		if err != nil {
 			klog.Errorf("getCreateSnapshotInput failed to getClassFromVolumeSnapshot %s", err)
-			return nil, nil, "", nil, err
+			return nil, nil, "", nil, utils.NewStatusError(crdv1.VolumeSnapshotErrorReasonClassError, err)
 		}
 	} else {
 		klog.Errorf("failed to getCreateSnapshotInput %s without a snapshot class", snapshot.Name)
-		return nil, nil, "", nil, fmt.Errorf("failed to take snapshot %s without a snapshot class", snapshot.Name)
+		return nil, nil, "", nil, utils.NewStatusError(crdv1.VolumeSnapshotErrorReasonClassError, fmt.Errorf("failed to take snapshot %s without a snapshot class", snapshot.Name))
 	}
 
 	volume, err := ctrl.getVolumeFromVolumeSnapshot(snapshot)
@@ -847,12 +994,12 @@ func (ctrl *csiSnapshotCommonController) getCreateSnapshotInput(snapshot *crdv1.
 	contentName := utils.GetDynamicSnapshotContentNameForSnapshot(snapshot)
 
 	// Resolve snapshotting secret credentials.
-	snapshotterSecretRef, err := utils.GetSecretReference(utils.SnapshotterSecretParams, class.Parameters, contentName, snapshot)
+	snapshotterCredentialsRef, err := utils.GetCredentialsReference(utils.SnapshotterSecretParams, class.Parameters, contentName, snapshot)
 	if err != nil {
-		return nil, nil, "", nil, err
+		return nil, nil, "", nil, utils.NewStatusError(crdv1.VolumeSnapshotErrorReasonSecretError, err)
 	}
 
-	return class, volume, contentName, snapshotterSecretRef, nil
+	return class, volume, contentName, snapshotterCredentialsRef, nil
 }
 
 func (ctrl *csiSnapshotCommonController) storeSnapshotUpdate(snapshot interface{}) (bool, error) {
@@ -873,7 +1020,8 @@ func (ctrl *csiSnapshotCommonController) storeContentUpdate(content interface{})
 //     if true, ReadyToUse will be set to false;
 //     otherwise, ReadyToUse will not be changed.
 //   - eventtype, reason, message - event to send, see EventRecorder.Event()
-func (ctrl *csiSnapshotCommonController) updateSnapshotErrorStatusWithEvent(snapshot *crdv1.VolumeSnapshot, setReadyToFalse bool, eventtype, reason, message string) error {
+//   - err - the error being reported, used to set the reason of the error status
+func (ctrl *csiSnapshotCommonController) updateSnapshotErrorStatusWithEvent(snapshot *crdv1.VolumeSnapshot, setReadyToFalse bool, eventtype, reason, message string, err error) error {
 	klog.V(5).Infof("updateSnapshotErrorStatusWithEvent[%s]", utils.SnapshotKey(snapshot))
 
 	if snapshot.Status != nil && snapshot.Status.Error != nil && *snapshot.Status.Error.Message == message {
@@ -884,18 +1032,14 @@ func (ctrl *csiSnapshotCommonController) updateSnapshotErrorStatusWithEvent(snap
 	if snapshotClone.Status == nil {
 		snapshotClone.Status = &crdv1.VolumeSnapshotStatus{}
 	}
-	statusError := &crdv1.VolumeSnapshotError{
-		Time: &metav1.Time{
-			Time: time.Now(),
-		},
-		Message: &message,
-	}
+	statusError := utils.NewVolumeSnapshotError(message, snapshotErrorReason(reason), err)
 	snapshotClone.Status.Error = statusError
 	// Only update ReadyToUse in VolumeSnapshot's Status to false if setReadyToFalse is true.
 	if setReadyToFalse {
 		ready := false
 		snapshotClone.Status.ReadyToUse = &ready
 	}
+	utils.SetVolumeSnapshotConditions(snapshotClone)
 	newSnapshot, err := ctrl.clientset.SnapshotV1().VolumeSnapshots(snapshotClone.Namespace).UpdateStatus(context.TODO(), snapshotClone, metav1.UpdateOptions{})
 
 	// Emit the event even if the status update fails so that user can see the error
@@ -915,6 +1059,27 @@ func (ctrl *csiSnapshotCommonController) updateSnapshotErrorStatusWithEvent(snap
 	return nil
 }
 
+// snapshotErrorReasons maps the reasons of the events reported along with the
+// errors of (group) snapshots to the reasons of the errors, when the events
+// report class lookups or failed requests to the API server.
+var snapshotErrorReasons = map[string]string{
+	"GetSnapshotClassFailed":          crdv1.VolumeSnapshotErrorReasonClassError,
+	"GetGroupSnapshotClassFailed":     crdv1.VolumeSnapshotErrorReasonClassError,
+	"SnapshotBindFailed":              crdv1.VolumeSnapshotErrorReasonAPIServerError,
+	"SnapshotStatusUpdateFailed":      crdv1.VolumeSnapshotErrorReasonAPIServerError,
+	"GroupSnapshotBindFailed":         crdv1.VolumeSnapshotErrorReasonAPIServerError,
+	"GroupSnapshotStatusUpdateFailed": crdv1.VolumeSnapshotErrorReasonAPIServerError,
+}
+
+// snapshotErrorReason returns the reason of an error reported along with an
+// event with the given reason.
+func snapshotErrorReason(eventReason string) string {
+	if reason, ok := snapshotErrorReasons[eventReason]; ok {
+		return reason
+	}
+	return eventReason
+}
+
 // addContentFinalizer adds a Finalizer for VolumeSnapshotContent.
 func (ctrl *csiSnapshotCommonController) addContentFinalizer(content *crdv1.VolumeSnapshotContent) error {
 	var patches []utils.PatchOp
@@ -947,9 +1112,53 @@ func (ctrl *csiSnapshotCommonController) addContentFinalizer(content *crdv1.Volu
 	return nil
 }
 
+// abandonContent removes the finalizer of a content with the abandon
+// annotation without the snapshot being deleted on the storage system, so
+// that the content and the snapshot bound to it can be deleted when the CSI
+// driver is no longer installed.
+func (ctrl *csiSnapshotCommonController) abandonContent(content *crdv1.VolumeSnapshotContent) error {
+	snapshotHandle := ""
+	if content.Status != nil && content.Status.SnapshotHandle != nil {
+		snapshotHandle = *content.Status.SnapshotHandle
+	} else if content.Spec.Source.SnapshotHandle != nil {
+		snapshotHandle = *content.Spec.Source.SnapshotHandle
+	}
+	reason := content.Annotations[utils.AnnVolumeSnapshotContentAbandon]
+
+	contentClone := content.DeepCopy()
+	contentClone.ObjectMeta.Finalizers = utils.RemoveString(contentClone.ObjectMeta.Finalizers, utils.VolumeSnapshotContentFinalizer)
+	newContent, err := ctrl.clientset.SnapshotV1().VolumeSnapshotContents().Update(context.TODO(), contentClone, metav1.UpdateOptions{})
+	if err != nil {
+		return newControllerUpdateError(content.Name, err.Error())
+	}
+
+	_, err = ctrl.storeContentUpdate(newContent)
+	if err != nil {
+		klog.Errorf("failed to update content store %v", err)
+	}
+
+	msg := fmt.Sprintf("Snapshot %q of driver %s was abandoned without being deleted, it may leak on the storage system: %s", snapshotHandle, content.Spec.Driver, reason)
+	klog.Warningf("abandonContent [%s]: %s", content.Name, msg)
+	ctrl.eventRecorder.Event(content, v1.EventTypeWarning, "SnapshotAbandoned", msg)
+	ctrl.metricsManager.RecordAbandonedSnapshot(content.Spec.Driver)
+
+	snapshotName := utils.SnapshotRefKey(&content.Spec.VolumeSnapshotRef)
+	snapshot, err := ctrl.getSnapshotFromStore(snapshotName)
+	if err == nil && snapshot != nil && snapshot.UID == content.Spec.VolumeSnapshotRef.UID {
+		ctrl.eventRecorder.Event(snapshot, v1.EventTypeWarning, "SnapshotAbandoned", msg)
+	}
+	return nil
+}
+
 // isVolumeBeingCreatedFromSnapshot checks if an volume is being created from the snapshot.
 func (ctrl *csiSnapshotCommonController) isVolumeBeingCreatedFromSnapshot(snapshot *crdv1.VolumeSnapshot) bool {
-	pvcList, err := ctrl.pvcLister.PersistentVolumeClaims(snapshot.Namespace).List(labels.Everything())
+	return volumeBeingCreatedFromSnapshot(ctrl.pvcLister, snapshot)
+}
+
+// volumeBeingCreatedFromSnapshot checks if a PVC known to pvcLister is being
+// provisioned with the snapshot as its data source.
+func volumeBeingCreatedFromSnapshot(pvcLister corelisters.PersistentVolumeClaimLister, snapshot *crdv1.VolumeSnapshot) bool {
+	pvcList, err := pvcLister.PersistentVolumeClaims(snapshot.Namespace).List(labels.Everything())
 	if err != nil {
 		klog.Errorf("Failed to retrieve PVCs from the lister to check if volume snapshot %s is being used by a volume: %q", utils.SnapshotKey(snapshot), err)
 		return false
@@ -1158,7 +1367,7 @@ func (ctrl *csiSnapshotCommonController) bindandUpdateVolumeSnapshot(snapshotCon
 	if err != nil {
 		// update snapshot status failed
 		klog.V(4).Infof("failed to update snapshot %s status: %v", utils.SnapshotKey(snapshot), err)
-		ctrl.updateSnapshotErrorStatusWithEvent(snapshotCopy, true, v1.EventTypeWarning, "SnapshotStatusUpdateFailed", fmt.Sprintf("Snapshot status update failed, %v", err))
+		ctrl.updateSnapshotErrorStatusWithEvent(snapshotCopy, true, v1.EventTypeWarning, "SnapshotStatusUpdateFailed", fmt.Sprintf("Snapshot status update failed, %v", err), nil)
 		return nil, err
 	}
 
@@ -1306,10 +1515,13 @@ func (ctrl *csiSnapshotCommonController) updateSnapshotStatus(snapshot *crdv1.Vo
 		}
 	}
 
-	if updated {
-		snapshotClone := snapshotObj.DeepCopy()
-		snapshotClone.Status = newStatus
+	snapshotClone := snapshotObj.DeepCopy()
+	snapshotClone.Status = newStatus
+	if utils.SetVolumeSnapshotConditions(snapshotClone) {
+		updated = true
+	}
 
+	if updated {
 		// We need to record metrics before updating the status due to a bug causing cache entries after a failed UpdateStatus call.
 		// Must meet the following criteria to emit a successful CreateSnapshot status
 		// 1. Previous status was nil OR Previous status had a nil CreationTime
@@ -1494,11 +1706,11 @@ func (ctrl *csiSnapshotCommonController) SetDefaultSnapshotClass(snapshot *crdv1
 		}
 	}
 	if len(defaultClasses) == 0 {
-		return nil, snapshot, fmt.Errorf("cannot find default snapshot class")
+		return nil, snapshot, utils.NewStatusError(crdv1.VolumeSnapshotErrorReasonClassError, fmt.Errorf("cannot find default snapshot class"))
 	}
 	if len(defaultClasses) > 1 {
 		klog.V(4).Infof("get DefaultClass %d defaults found", len(defaultClasses))
-		return nil, snapshot, fmt.Errorf("%d default snapshot classes were found", len(defaultClasses))
+		return nil, snapshot, utils.NewStatusError(crdv1.VolumeSnapshotErrorReasonClassError, fmt.Errorf("%d default snapshot classes were found", len(defaultClasses)))
 	}
 	klog.V(5).Infof("setDefaultSnapshotClass [%s]: default VolumeSnapshotClassName [%s]", snapshot.Name, defaultClasses[0].Name)
 	snapshotClone := snapshot.DeepCopy()
@@ -1548,9 +1760,9 @@ type controllerUpdateError struct {
 }
 
 func newControllerUpdateError(name, message string) error {
-	return controllerUpdateError{
+	return utils.NewStatusError(crdv1.VolumeSnapshotErrorReasonAPIServerError, controllerUpdateError{
 		message: fmt.Sprintf("%s %s on API server: %s", controllerUpdateFailMsg, name, message),
-	}
+	})
 }
 
 func (e controllerUpdateError) Error() string {
@@ -1725,6 +1937,35 @@ func (ctrl *csiSnapshotCommonController) setAnnVolumeSnapshotBeingDeleted(conten
 	return content, nil
 }
 
+// setAnnTraceParent stores the trace context of ctx in AnnTraceParent on the
+// snapshot. Nothing is stored if ctx has no sampled span.
+func (ctrl *csiSnapshotCommonController) setAnnTraceParent(ctx context.Context, snapshot *crdv1.VolumeSnapshot) (*crdv1.VolumeSnapshot, error) {
+	annotations := map[string]string{}
+	for key, value := range snapshot.Annotations {
+		annotations[key] = value
+	}
+	tracing.InjectAnnotation(ctx, &annotations, utils.AnnTraceParent)
+	if _, ok := annotations[utils.AnnTraceParent]; !ok {
+		return snapshot, nil
+	}
+	klog.V(5).Infof("setAnnTraceParent: set annotation [%s] on snapshot [%s].", utils.AnnTraceParent, utils.SnapshotKey(snapshot))
+	patches := []utils.PatchOp{
+		{
+			Op:    "replace",
+			Path:  "/metadata/annotations",
+			Value: annotations,
+		},
+	}
+	patchedSnapshot, err := utils.PatchVolumeSnapshot(snapshot, patches, ctrl.clientset)
+	if err != nil {
+		return snapshot, newControllerUpdateError(utils.SnapshotKey(snapshot), err.Error())
+	}
+	if _, err = ctrl.storeSnapshotUpdate(patchedSnapshot); err != nil {
+		klog.V(4).Infof("setAnnTraceParent for snapshot [%s]: cannot update internal cache %v", utils.SnapshotKey(patchedSnapshot), err)
+	}
+	return patchedSnapshot, nil
+}
+
 func (ctrl *csiSnapshotCommonController) getManagedByNode(pv *v1.PersistentVolume) (string, error) {
 	if pv.Spec.NodeAffinity == nil {
 		klog.V(5).Infof("NodeAffinity not set for pv %s", pv.Name)
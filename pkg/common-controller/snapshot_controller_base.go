@@ -19,6 +19,7 @@ package common_controller
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	crdv1beta1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumegroupsnapshot/v1beta1"
@@ -26,8 +27,11 @@ import (
 	clientset "github.com/kubernetes-csi/external-snapshotter/client/v8/clientset/versioned"
 	groupsnapshotinformers "github.com/kubernetes-csi/external-snapshotter/client/v8/informers/externalversions/volumegroupsnapshot/v1beta1"
 	snapshotinformers "github.com/kubernetes-csi/external-snapshotter/client/v8/informers/externalversions/volumesnapshot/v1"
+	snapshotalphainformers "github.com/kubernetes-csi/external-snapshotter/client/v8/informers/externalversions/volumesnapshot/v1alpha1"
 	groupsnapshotlisters "github.com/kubernetes-csi/external-snapshotter/client/v8/listers/volumegroupsnapshot/v1beta1"
 	snapshotlisters "github.com/kubernetes-csi/external-snapshotter/client/v8/listers/volumesnapshot/v1"
+	snapshotalphalisters "github.com/kubernetes-csi/external-snapshotter/client/v8/listers/volumesnapshot/v1alpha1"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/debug"
 	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/metrics"
 	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
 
@@ -73,12 +77,25 @@ type csiSnapshotCommonController struct {
 	groupSnapshotContentListerSynced cache.InformerSynced
 	groupSnapshotClassLister         groupsnapshotlisters.VolumeGroupSnapshotClassLister
 	groupSnapshotClassListerSynced   cache.InformerSynced
+	hookLister                       snapshotalphalisters.VolumeSnapshotHookLister
+	hookListerSynced                 cache.InformerSynced
+	podLister                        corelisters.PodLister
+	podListerSynced                  cache.InformerSynced
+
+	hookExecutor HookExecutor
 
 	snapshotStore             cache.Store
 	contentStore              cache.Store
 	groupSnapshotStore        cache.Store
 	groupSnapshotContentStore cache.Store
 
+	// The results of the last sync of the objects of each queue, exposed by
+	// the debug endpoints.
+	snapshotSyncResults             *debug.SyncResults
+	contentSyncResults              *debug.SyncResults
+	groupSnapshotSyncResults        *debug.SyncResults
+	groupSnapshotContentSyncResults *debug.SyncResults
+
 	metricsManager metrics.MetricsManager
 
 	resyncPeriod time.Duration
@@ -104,6 +121,8 @@ func NewCSISnapshotCommonController(
 	pvcInformer coreinformers.PersistentVolumeClaimInformer,
 	pvInformer coreinformers.PersistentVolumeInformer,
 	nodeInformer coreinformers.NodeInformer,
+	volumeSnapshotHookInformer snapshotalphainformers.VolumeSnapshotHookInformer,
+	podInformer coreinformers.PodInformer,
 	metricsManager metrics.MetricsManager,
 	resyncPeriod time.Duration,
 	snapshotRateLimiter workqueue.TypedRateLimiter[string],
@@ -113,6 +132,7 @@ func NewCSISnapshotCommonController(
 	enableDistributedSnapshotting bool,
 	preventVolumeModeConversion bool,
 	enableVolumeGroupSnapshots bool,
+	hookExecutor HookExecutor,
 ) *csiSnapshotCommonController {
 	broadcaster := record.NewBroadcaster()
 	broadcaster.StartLogging(klog.Infof)
@@ -143,15 +163,7 @@ func NewCSISnapshotCommonController(
 	ctrl.pvListerSynced = pvInformer.Informer().HasSynced
 
 	pvInformer.Informer().AddIndexers(map[string]cache.IndexFunc{
-		utils.CSIDriverHandleIndexName: func(obj interface{}) ([]string, error) {
-			if pv, ok := obj.(*v1.PersistentVolume); ok {
-				if key := utils.PersistentVolumeKeyFunc(pv); key != "" {
-					return []string{key}, nil
-				}
-			}
-
-			return nil, nil
-		},
+		utils.CSIDriverHandleIndexName: utils.PersistentVolumeByCSIDriverHandleIndexFunc,
 	})
 	ctrl.pvIndexer = pvInformer.Informer().GetIndexer()
 
@@ -177,6 +189,7 @@ func NewCSISnapshotCommonController(
 	ctrl.snapshotLister = volumeSnapshotInformer.Lister()
 	ctrl.snapshotListerSynced = volumeSnapshotInformer.Informer().HasSynced
 	ctrl.snapshotIndexer = volumeSnapshotInformer.Informer().GetIndexer()
+	ctrl.snapshotSyncResults = debug.NewSyncResults("snapshot-controller-snapshot", volumeSnapshotInformer.Informer().GetStore())
 
 	volumeSnapshotContentInformer.Informer().AddEventHandlerWithResyncPeriod(
 		cache.ResourceEventHandlerFuncs{
@@ -188,6 +201,7 @@ func NewCSISnapshotCommonController(
 	)
 	ctrl.contentLister = volumeSnapshotContentInformer.Lister()
 	ctrl.contentListerSynced = volumeSnapshotContentInformer.Informer().HasSynced
+	ctrl.contentSyncResults = debug.NewSyncResults("snapshot-controller-content", volumeSnapshotContentInformer.Informer().GetStore())
 
 	ctrl.classLister = volumeSnapshotClassInformer.Lister()
 	ctrl.classListerSynced = volumeSnapshotClassInformer.Informer().HasSynced
@@ -203,6 +217,14 @@ func NewCSISnapshotCommonController(
 
 	ctrl.enableVolumeGroupSnapshots = enableVolumeGroupSnapshots
 
+	if volumeSnapshotHookInformer != nil {
+		ctrl.hookLister = volumeSnapshotHookInformer.Lister()
+		ctrl.hookListerSynced = volumeSnapshotHookInformer.Informer().HasSynced
+		ctrl.podLister = podInformer.Lister()
+		ctrl.podListerSynced = podInformer.Informer().HasSynced
+		ctrl.hookExecutor = hookExecutor
+	}
+
 	if enableVolumeGroupSnapshots {
 		ctrl.groupSnapshotStore = cache.NewStore(cache.DeletionHandlingMetaNamespaceKeyFunc)
 		ctrl.groupSnapshotContentStore = cache.NewStore(cache.DeletionHandlingMetaNamespaceKeyFunc)
@@ -224,6 +246,7 @@ func NewCSISnapshotCommonController(
 		)
 		ctrl.groupSnapshotLister = volumeGroupSnapshotInformer.Lister()
 		ctrl.groupSnapshotListerSynced = volumeGroupSnapshotInformer.Informer().HasSynced
+		ctrl.groupSnapshotSyncResults = debug.NewSyncResults("snapshot-controller-group-snapshot", volumeGroupSnapshotInformer.Informer().GetStore())
 
 		volumeGroupSnapshotContentInformer.Informer().AddEventHandlerWithResyncPeriod(
 			cache.ResourceEventHandlerFuncs{
@@ -235,6 +258,7 @@ func NewCSISnapshotCommonController(
 		)
 		ctrl.groupSnapshotContentLister = volumeGroupSnapshotContentInformer.Lister()
 		ctrl.groupSnapshotContentListerSynced = volumeGroupSnapshotContentInformer.Informer().HasSynced
+		ctrl.groupSnapshotContentSyncResults = debug.NewSyncResults("snapshot-controller-group-content", volumeGroupSnapshotContentInformer.Informer().GetStore())
 
 		ctrl.groupSnapshotClassLister = volumeGroupSnapshotClassInformer.Lister()
 		ctrl.groupSnapshotClassListerSynced = volumeGroupSnapshotClassInformer.Informer().HasSynced
@@ -244,6 +268,12 @@ func NewCSISnapshotCommonController(
 	return ctrl
 }
 
+// EventRecorder returns the recorder of the events of the controller, for
+// the controllers that report on the same objects.
+func (ctrl *csiSnapshotCommonController) EventRecorder() record.EventRecorder {
+	return ctrl.eventRecorder
+}
+
 func (ctrl *csiSnapshotCommonController) Run(workers int, stopCh <-chan struct{}) {
 	defer ctrl.snapshotQueue.ShutDown()
 	defer ctrl.contentQueue.ShutDown()
@@ -268,6 +298,9 @@ func (ctrl *csiSnapshotCommonController) Run(workers int, stopCh <-chan struct{}
 	if ctrl.enableVolumeGroupSnapshots {
 		informersSynced = append(informersSynced, []cache.InformerSynced{ctrl.groupSnapshotListerSynced, ctrl.groupSnapshotContentListerSynced, ctrl.groupSnapshotClassListerSynced}...)
 	}
+	if ctrl.hookListerSynced != nil {
+		informersSynced = append(informersSynced, ctrl.hookListerSynced, ctrl.podListerSynced)
+	}
 
 	if !cache.WaitForCacheSync(stopCh, informersSynced...) {
 		klog.Errorf("Cannot sync caches")
@@ -288,6 +321,42 @@ func (ctrl *csiSnapshotCommonController) Run(workers int, stopCh <-chan struct{}
 	<-stopCh
 }
 
+// RegisterDebugEndpoints registers the endpoints exposing the state of the
+// work queues of the controller, the result of the last sync of their
+// objects and the operations in flight.
+func (ctrl *csiSnapshotCommonController) RegisterDebugEndpoints(server *debug.Server) {
+	server.Register("queues", func() interface{} {
+		queues := []debug.QueueState{
+			ctrl.snapshotSyncResults.QueueState(ctrl.snapshotQueue),
+			ctrl.contentSyncResults.QueueState(ctrl.contentQueue),
+		}
+		if ctrl.enableVolumeGroupSnapshots {
+			queues = append(queues,
+				ctrl.groupSnapshotSyncResults.QueueState(ctrl.groupSnapshotQueue),
+				ctrl.groupSnapshotContentSyncResults.QueueState(ctrl.groupSnapshotContentQueue))
+		}
+		return queues
+	})
+	server.Register("syncs", func() interface{} {
+		syncs := map[string][]debug.SyncResult{
+			"snapshots": ctrl.snapshotSyncResults.List(),
+			"contents":  ctrl.contentSyncResults.List(),
+		}
+		if ctrl.enableVolumeGroupSnapshots {
+			syncs["groupSnapshots"] = ctrl.groupSnapshotSyncResults.List()
+			syncs["groupSnapshotContents"] = ctrl.groupSnapshotContentSyncResults.List()
+		}
+		return syncs
+	})
+	server.Register("operations", func() interface{} {
+		operations := ctrl.metricsManager.InFlightOperations()
+		sort.Slice(operations, func(i, j int) bool {
+			return operations[i].StartTime.Before(operations[j].StartTime)
+		})
+		return operations
+	})
+}
+
 // enqueueSnapshotWork adds snapshot to given work queue.
 func (ctrl *csiSnapshotCommonController) enqueueSnapshotWork(obj interface{}) {
 	// Beware of "xxx deleted" events
@@ -330,7 +399,9 @@ func (ctrl *csiSnapshotCommonController) snapshotWorker() {
 	}
 	defer ctrl.snapshotQueue.Done(key)
 
-	if err := ctrl.syncSnapshotByKey(key); err != nil {
+	err := ctrl.syncSnapshotByKey(key)
+	ctrl.snapshotSyncResults.Record(key, err)
+	if err != nil {
 		// Rather than wait for a full resync, re-add the key to the
 		// queue to be processed.
 		ctrl.snapshotQueue.AddRateLimited(key)
@@ -406,7 +477,9 @@ func (ctrl *csiSnapshotCommonController) contentWorker() {
 	}
 	defer ctrl.contentQueue.Done(key)
 
-	if err := ctrl.syncContentByKey(key); err != nil {
+	err := ctrl.syncContentByKey(key)
+	ctrl.contentSyncResults.Record(key, err)
+	if err != nil {
 		// Rather than wait for a full resync, re-add the key to the
 		// queue to be processed.
 		ctrl.contentQueue.AddRateLimited(key)
@@ -475,7 +548,7 @@ func (ctrl *csiSnapshotCommonController) checkAndUpdateSnapshotClass(snapshot *c
 		class, err = ctrl.getSnapshotClass(*className)
 		if err != nil {
 			klog.Errorf("checkAndUpdateSnapshotClass failed to getSnapshotClass %v", err)
-			ctrl.updateSnapshotErrorStatusWithEvent(snapshot, false, v1.EventTypeWarning, "GetSnapshotClassFailed", fmt.Sprintf("Failed to get snapshot class with error %v", err))
+			ctrl.updateSnapshotErrorStatusWithEvent(snapshot, false, v1.EventTypeWarning, "GetSnapshotClassFailed", fmt.Sprintf("Failed to get snapshot class with error %v", err), nil)
 			// we need to return the original snapshot even if the class isn't found, as it may need to be deleted
 			return newSnapshot, err
 		}
@@ -484,7 +557,7 @@ func (ctrl *csiSnapshotCommonController) checkAndUpdateSnapshotClass(snapshot *c
 		class, newSnapshot, err = ctrl.SetDefaultSnapshotClass(snapshot)
 		if err != nil {
 			klog.Errorf("checkAndUpdateSnapshotClass failed to setDefaultClass %v", err)
-			ctrl.updateSnapshotErrorStatusWithEvent(snapshot, false, v1.EventTypeWarning, "SetDefaultSnapshotClassFailed", fmt.Sprintf("Failed to set default snapshot class with error %v", err))
+			ctrl.updateSnapshotErrorStatusWithEvent(snapshot, false, v1.EventTypeWarning, "SetDefaultSnapshotClassFailed", fmt.Sprintf("Failed to set default snapshot class with error %v", err), err)
 			return snapshot, err
 		}
 	}
@@ -554,6 +627,7 @@ func (ctrl *csiSnapshotCommonController) updateContent(content *crdv1.VolumeSnap
 func (ctrl *csiSnapshotCommonController) deleteSnapshot(snapshot *crdv1.VolumeSnapshot) {
 	_ = ctrl.snapshotStore.Delete(snapshot)
 	klog.V(4).Infof("snapshot %q deleted", utils.SnapshotKey(snapshot))
+	ctrl.metricsManager.DropSnapshotExpiry(snapshot.UID)
 	driverName, err := ctrl.getSnapshotDriverName(snapshot)
 	if err != nil {
 		klog.Errorf("failed to getSnapshotDriverName while recording metrics for snapshot %q: %s", utils.SnapshotKey(snapshot), err)
@@ -694,7 +768,9 @@ func (ctrl *csiSnapshotCommonController) groupSnapshotWorker() {
 	}
 	defer ctrl.groupSnapshotQueue.Done(key)
 
-	if err := ctrl.syncGroupSnapshotByKey(context.Background(), key); err != nil {
+	err := ctrl.syncGroupSnapshotByKey(context.Background(), key)
+	ctrl.groupSnapshotSyncResults.Record(key, err)
+	if err != nil {
 		// Rather than wait for a full resync, re-add the key to the
 		// queue to be processed.
 		ctrl.groupSnapshotQueue.AddRateLimited(key)
@@ -714,7 +790,9 @@ func (ctrl *csiSnapshotCommonController) groupSnapshotContentWorker() {
 	}
 	defer ctrl.groupSnapshotContentQueue.Done(key)
 
-	if err := ctrl.syncGroupSnapshotContentByKey(key); err != nil {
+	err := ctrl.syncGroupSnapshotContentByKey(key)
+	ctrl.groupSnapshotContentSyncResults.Record(key, err)
+	if err != nil {
 		// Rather than wait for a full resync, re-add the key to the
 		// queue to be processed.
 		ctrl.groupSnapshotContentQueue.AddRateLimited(key)
@@ -795,7 +873,7 @@ func (ctrl *csiSnapshotCommonController) checkAndUpdateGroupSnapshotClass(groupS
 		class, err = ctrl.getGroupSnapshotClass(*className)
 		if err != nil {
 			klog.Errorf("checkAndUpdateGroupSnapshotClass failed to getGroupSnapshotClass %v", err)
-			ctrl.updateGroupSnapshotErrorStatusWithEvent(groupSnapshot, false, v1.EventTypeWarning, "GetGroupSnapshotClassFailed", fmt.Sprintf("failed to get group snapshot class with error %v", err))
+			ctrl.updateGroupSnapshotErrorStatusWithEvent(groupSnapshot, false, v1.EventTypeWarning, "GetGroupSnapshotClassFailed", fmt.Sprintf("failed to get group snapshot class with error %v", err), nil)
 			// we need to return the original group snapshot even if the class isn't found, as it may need to be deleted
 			return newGroupSnapshot, err
 		}
@@ -804,7 +882,7 @@ func (ctrl *csiSnapshotCommonController) checkAndUpdateGroupSnapshotClass(groupS
 		class, newGroupSnapshot, err = ctrl.SetDefaultGroupSnapshotClass(groupSnapshot)
 		if err != nil {
 			klog.Errorf("checkAndUpdateGroupSnapshotClass failed to setDefaultClass %v", err)
-			ctrl.updateGroupSnapshotErrorStatusWithEvent(groupSnapshot, false, v1.EventTypeWarning, "SetDefaultGroupSnapshotClassFailed", fmt.Sprintf("Failed to set default group snapshot class with error %v", err))
+			ctrl.updateGroupSnapshotErrorStatusWithEvent(groupSnapshot, false, v1.EventTypeWarning, "SetDefaultGroupSnapshotClassFailed", fmt.Sprintf("Failed to set default group snapshot class with error %v", err), err)
 			return groupSnapshot, err
 		}
 	}
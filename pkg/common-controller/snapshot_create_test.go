@@ -17,11 +17,19 @@ limitations under the License.
 package common_controller
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
 
 	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -81,7 +89,7 @@ func TestCreateSnapshotSync(t *testing.T) {
 			initialContents:   nocontents,
 			expectedContents:  nocontents,
 			initialSnapshots:  newSnapshotArray("snap7-1", "snapuid7-1", "claim7-1", "", classNonExisting, "", &False, nil, nil, nil, false, true, nil),
-			expectedSnapshots: newSnapshotArray("snap7-1", "snapuid7-1", "claim7-1", "", classNonExisting, "", &False, nil, nil, newVolumeError("Failed to create snapshot content with error failed to get input parameters to create snapshot snap7-1: \"volumesnapshotclass.snapshot.storage.k8s.io \\\"non-existing\\\" not found\""), false, true, nil),
+			expectedSnapshots: newSnapshotArray("snap7-1", "snapuid7-1", "claim7-1", "", classNonExisting, "", &False, nil, nil, newVolumeError("Failed to create snapshot content with error failed to get input parameters to create snapshot snap7-1: \"volumesnapshotclass.snapshot.storage.k8s.io \\\"non-existing\\\" not found\"", crdv1.VolumeSnapshotErrorReasonClassError), false, true, nil),
 			initialClaims:     newClaimArray("claim7-1", "pvc-uid7-1", "1Gi", "volume7-1", v1.ClaimBound, &classEmpty),
 			initialVolumes:    newVolumeArray("volume7-1", "pv-uid7-1", "pv-handle7-1", "1Gi", "pvc-uid7-1", "claim7-1", v1.VolumeBound, v1.PersistentVolumeReclaimDelete, classEmpty),
 			expectedEvents:    []string{"Warning SnapshotContentCreationFailed"},
@@ -94,7 +102,7 @@ func TestCreateSnapshotSync(t *testing.T) {
 			initialContents:   nocontents,
 			expectedContents:  nocontents,
 			initialSnapshots:  newSnapshotArray("snap7-3", "snapuid7-3", "claim7-3", "", "", "", &False, nil, nil, nil, false, true, nil),
-			expectedSnapshots: newSnapshotArray("snap7-3", "snapuid7-3", "claim7-3", "", "", "", &False, nil, nil, newVolumeError("Failed to create snapshot content with error failed to get input parameters to create snapshot snap7-3: \"failed to take snapshot snap7-3 without a snapshot class\""), false, true, nil),
+			expectedSnapshots: newSnapshotArray("snap7-3", "snapuid7-3", "claim7-3", "", "", "", &False, nil, nil, newVolumeError("Failed to create snapshot content with error failed to get input parameters to create snapshot snap7-3: \"failed to take snapshot snap7-3 without a snapshot class\"", crdv1.VolumeSnapshotErrorReasonClassError), false, true, nil),
 			initialClaims:     newClaimArray("claim7-3", "pvc-uid7-3", "1Gi", "volume7-3", v1.ClaimBound, &classEmpty),
 			initialVolumes:    newVolumeArray("volume7-3", "pv-uid7-3", "pv-handle7-3", "1Gi", "pvc-uid7-3", "claim7-3", v1.VolumeBound, v1.PersistentVolumeReclaimDelete, classEmpty),
 			expectedEvents:    []string{"Warning SnapshotContentCreationFailed"},
@@ -107,7 +115,7 @@ func TestCreateSnapshotSync(t *testing.T) {
 			initialContents:   nocontents,
 			expectedContents:  nocontents,
 			initialSnapshots:  newSnapshotArray("snap7-4", "snapuid7-4", "claim7-4", "", classGold, "", &False, nil, nil, nil, false, true, nil),
-			expectedSnapshots: newSnapshotArray("snap7-4", "snapuid7-4", "claim7-4", "", classGold, "", &False, nil, nil, newVolumeError("Failed to create snapshot content with error snapshot controller failed to update snap7-4 on API server: cannot get claim from snapshot"), false, true, nil),
+			expectedSnapshots: newSnapshotArray("snap7-4", "snapuid7-4", "claim7-4", "", classGold, "", &False, nil, nil, newVolumeError("Failed to create snapshot content with error snapshot controller failed to update snap7-4 on API server: cannot get claim from snapshot", crdv1.VolumeSnapshotErrorReasonAPIServerError), false, true, nil),
 			initialVolumes:    newVolumeArray("volume7-4", "pv-uid7-4", "pv-handle7-4", "1Gi", "pvc-uid7-4", "claim7-4", v1.VolumeBound, v1.PersistentVolumeReclaimDelete, classGold),
 			expectedEvents:    []string{"Warning SnapshotContentCreationFailed"},
 			errors:            noerrors,
@@ -119,7 +127,7 @@ func TestCreateSnapshotSync(t *testing.T) {
 			initialContents:   nocontents,
 			expectedContents:  nocontents,
 			initialSnapshots:  newSnapshotArray("snap7-5", "snapuid7-5", "claim7-5", "", classGold, "", &False, nil, nil, nil, false, true, nil),
-			expectedSnapshots: newSnapshotArray("snap7-5", "snapuid7-5", "claim7-5", "", classGold, "", &False, nil, nil, newVolumeError("Failed to create snapshot content with error failed to get input parameters to create snapshot snap7-5: \"failed to retrieve PV volume7-5 from the API server: \\\"cannot find volume volume7-5\\\"\""), false, true, nil),
+			expectedSnapshots: newSnapshotArray("snap7-5", "snapuid7-5", "claim7-5", "", classGold, "", &False, nil, nil, newVolumeError("Failed to create snapshot content with error failed to get input parameters to create snapshot snap7-5: \"failed to retrieve PV volume7-5 from the API server: \\\"cannot find volume volume7-5\\\"\"", "SnapshotContentCreationFailed"), false, true, nil),
 			initialClaims:     newClaimArray("claim7-5", "pvc-uid7-5", "1Gi", "volume7-5", v1.ClaimBound, &classGold),
 			expectedEvents:    []string{"Warning SnapshotContentCreationFailed"},
 			errors:            noerrors,
@@ -132,7 +140,7 @@ func TestCreateSnapshotSync(t *testing.T) {
 			initialContents:   nocontents,
 			expectedContents:  nocontents,
 			initialSnapshots:  newSnapshotArray("snap7-6", "snapuid7-6", "claim7-6", "", classGold, "", &False, nil, nil, nil, false, true, nil),
-			expectedSnapshots: newSnapshotArray("snap7-6", "snapuid7-6", "claim7-6", "", classGold, "", &False, nil, nil, newVolumeError("Failed to create snapshot content with error failed to get input parameters to create snapshot snap7-6: \"the PVC claim7-6 is not yet bound to a PV, will not attempt to take a snapshot\""), false, true, nil),
+			expectedSnapshots: newSnapshotArray("snap7-6", "snapuid7-6", "claim7-6", "", classGold, "", &False, nil, nil, newVolumeError("Failed to create snapshot content with error failed to get input parameters to create snapshot snap7-6: \"the PVC claim7-6 is not yet bound to a PV, will not attempt to take a snapshot\"", "SnapshotContentCreationFailed"), false, true, nil),
 			initialClaims:     newClaimArray("claim7-6", "pvc-uid7-6", "1Gi", "", v1.ClaimPending, &classGold),
 			expectedEvents:    []string{"Warning SnapshotContentCreationFailed"},
 			errors:            noerrors,
@@ -178,7 +186,7 @@ func TestCreateSnapshotSync(t *testing.T) {
 			initialContents:   nocontents,
 			expectedContents:  nocontents,
 			initialSnapshots:  newSnapshotArray("snap7-10", "snapuid7-10", "claim7-10", "", invalidSecretClass, "", &False, nil, nil, nil, false, true, nil),
-			expectedSnapshots: newSnapshotArray("snap7-10", "snapuid7-10", "claim7-10", "", invalidSecretClass, "", &False, nil, nil, newVolumeError("Failed to create snapshot content with error failed to get input parameters to create snapshot snap7-10: \"failed to get name and namespace template from params: either name and namespace for Snapshotter secrets specified, Both must be specified\""), false, true, nil),
+			expectedSnapshots: newSnapshotArray("snap7-10", "snapuid7-10", "claim7-10", "", invalidSecretClass, "", &False, nil, nil, newVolumeError("Failed to create snapshot content with error failed to get input parameters to create snapshot snap7-10: \"failed to get name and namespace template from params: either name and namespace for Snapshotter secrets specified, Both must be specified\"", crdv1.VolumeSnapshotErrorReasonSecretError), false, true, nil),
 			initialClaims:     newClaimArray("claim7-10", "pvc-uid7-10", "1Gi", "volume7-10", v1.ClaimBound, &classEmpty),
 			initialVolumes:    newVolumeArray("volume7-10", "pv-uid7-10", "pv-handle7-10", "1Gi", "pvc-uid7-10", "claim7-10", v1.VolumeBound, v1.PersistentVolumeReclaimDelete, classEmpty),
 			initialSecrets:    []*v1.Secret{}, // no initial secret created
@@ -189,7 +197,7 @@ func TestCreateSnapshotSync(t *testing.T) {
 			initialContents:   nocontents,
 			expectedContents:  nocontents,
 			initialSnapshots:  newSnapshotArray("snap7-11", "snapuid7-11", "claim7-11", "", classGold, "", &False, nil, nil, nil, false, true, nil),
-			expectedSnapshots: newSnapshotArray("snap7-11", "snapuid7-11", "claim7-11", "", classGold, "", &False, nil, nil, newVolumeError("Failed to create snapshot content with error snapshot controller failed to update default/snap7-11 on API server: mock create error"), false, true, nil),
+			expectedSnapshots: newSnapshotArray("snap7-11", "snapuid7-11", "claim7-11", "", classGold, "", &False, nil, nil, newVolumeError("Failed to create snapshot content with error snapshot controller failed to update default/snap7-11 on API server: mock create error", crdv1.VolumeSnapshotErrorReasonAPIServerError), false, true, nil),
 			initialClaims:     newClaimArray("claim7-11", "pvc-uid7-11", "1Gi", "volume7-11", v1.ClaimBound, &classGold),
 			initialVolumes:    newVolumeArray("volume7-11", "pv-uid7-11", "pv-handle7-11", "1Gi", "pvc-uid7-11", "claim7-11", v1.VolumeBound, v1.PersistentVolumeReclaimDelete, classGold),
 			errors: []reactorError{
@@ -203,3 +211,90 @@ func TestCreateSnapshotSync(t *testing.T) {
 	}
 	runSyncTests(t, tests, snapshotClasses, nil)
 }
+
+// fixedIDGenerator generates the same trace and span IDs for every span.
+type fixedIDGenerator struct{}
+
+func (fixedIDGenerator) NewIDs(ctx context.Context) (trace.TraceID, trace.SpanID) {
+	return trace.TraceID{0x4b, 0xf9, 0x2f, 0x35}, trace.SpanID{0x00, 0xf0, 0x67, 0xaa}
+}
+
+func (fixedIDGenerator) NewSpanID(ctx context.Context, traceID trace.TraceID) trace.SpanID {
+	return trace.SpanID{0x00, 0xf0, 0x67, 0xaa}
+}
+
+// Test that the trace started for a snapshot is stored on it, continued by
+// later syncs and passed to its content.
+func TestCreateSnapshotSyncTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter), sdktrace.WithIDGenerator(fixedIDGenerator{})))
+	// The default global provider cannot be restored, drop the spans of the
+	// following tests instead.
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	tests := []controllerTest{
+		{
+			name:            "6-3 - create snapshot passes the trace context to the content",
+			initialContents: nocontents,
+			expectedContents: withContentAnnotations(newContentArrayNoStatus("snapcontent-snapuid6-3", "snapuid6-3", "snap6-3", "sid6-3", classGold, "", "pv-handle6-3", deletionPolicy, nil, nil, false, false),
+				map[string]string{
+					utils.AnnTraceParent: "00-4bf92f35000000000000000000000000-00f067aa00000000-01",
+				}),
+			initialSnapshots: newSnapshotArray("snap6-3", "snapuid6-3", "claim6-3", "", classGold, "", &False, nil, nil, nil, false, true, nil),
+			expectedSnapshots: withSnapshotAnnotations(newSnapshotArray("snap6-3", "snapuid6-3", "claim6-3", "", classGold, "snapcontent-snapuid6-3", &False, nil, nil, nil, false, true, nil),
+				map[string]string{
+					utils.AnnTraceParent: "00-4bf92f35000000000000000000000000-00f067aa00000000-01",
+				}),
+			initialClaims:  newClaimArray("claim6-3", "pvc-uid6-3", "1Gi", "volume6-3", v1.ClaimBound, &classGold),
+			initialVolumes: newVolumeArray("volume6-3", "pv-uid6-3", "pv-handle6-3", "1Gi", "pvc-uid6-3", "claim6-3", v1.VolumeBound, v1.PersistentVolumeReclaimDelete, classGold),
+			errors:         noerrors,
+			test:           testSyncSnapshot,
+		},
+		{
+			name:            "6-4 - a later sync continues the trace stored on the snapshot",
+			initialContents: nocontents,
+			expectedContents: withContentAnnotations(newContentArrayNoStatus("snapcontent-snapuid6-4", "snapuid6-4", "snap6-4", "sid6-4", classGold, "", "pv-handle6-4", deletionPolicy, nil, nil, false, false),
+				map[string]string{
+					utils.AnnTraceParent: "00-0af76519000000000000000000000000-00f067aa00000000-01",
+				}),
+			initialSnapshots: withSnapshotAnnotations(newSnapshotArray("snap6-4", "snapuid6-4", "claim6-4", "", classGold, "", &False, nil, nil, nil, false, true, nil),
+				map[string]string{
+					utils.AnnTraceParent: "00-0af76519000000000000000000000000-b7ad6b7100000000-01",
+				}),
+			expectedSnapshots: withSnapshotAnnotations(newSnapshotArray("snap6-4", "snapuid6-4", "claim6-4", "", classGold, "snapcontent-snapuid6-4", &False, nil, nil, nil, false, true, nil),
+				map[string]string{
+					utils.AnnTraceParent: "00-0af76519000000000000000000000000-b7ad6b7100000000-01",
+				}),
+			initialClaims:  newClaimArray("claim6-4", "pvc-uid6-4", "1Gi", "volume6-4", v1.ClaimBound, &classGold),
+			initialVolumes: newVolumeArray("volume6-4", "pv-uid6-4", "pv-handle6-4", "1Gi", "pvc-uid6-4", "claim6-4", v1.VolumeBound, v1.PersistentVolumeReclaimDelete, classGold),
+			errors:         noerrors,
+			test:           testSyncSnapshot,
+		},
+	}
+	runSyncTests(t, tests, snapshotClasses, nil)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	for i, expected := range []struct {
+		name     string
+		parent   string
+		snapshot string
+	}{
+		{name: "VolumeSnapshot", snapshot: "snap6-3"},
+		{name: "syncSnapshot", parent: "0af76519000000000000000000000000", snapshot: "snap6-4"},
+	} {
+		expectedAttributes := attribute.NewSet(
+			attribute.String("volumesnapshot.namespace", "default"),
+			attribute.String("volumesnapshot.name", expected.snapshot),
+		)
+		attributes := attribute.NewSet(spans[i].Attributes...)
+		if spans[i].Name != expected.name || !attributes.Equals(&expectedAttributes) {
+			t.Errorf("expected span %s with attributes %v, got span %s with attributes %v", expected.name, expectedAttributes.ToSlice(), spans[i].Name, spans[i].Attributes)
+		}
+		if parent := spans[i].Parent; parent.IsValid() != (expected.parent != "") || (parent.IsValid() && parent.TraceID().String() != expected.parent) {
+			t.Errorf("expected span %s to continue trace %q, got parent %v", expected.name, expected.parent, parent)
+		}
+	}
+}
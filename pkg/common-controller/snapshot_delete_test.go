@@ -334,6 +334,40 @@ func TestDeleteSync(t *testing.T) {
 			errors:            noerrors,
 			test:              testSyncSnapshot,
 		},
+		{
+			name:              "3-13 - (static) snapshot should have its deletion blocked while a PVC is being restored from it",
+			initialContents:   newContentArray("content-3-13", "snapuid3-13", "snap3-13", "sid3-13", validSecretClass, "sid3-13", "", deletePolicy, nil, nil, true),
+			expectedContents:  newContentArray("content-3-13", "snapuid3-13", "snap3-13", "sid3-13", validSecretClass, "sid3-13", "", deletePolicy, nil, nil, true),
+			initialSnapshots:  newSnapshotArray("snap3-13", "snapuid3-13", "", "content-3-13", validSecretClass, "content-3-13", &True, nil, nil, nil, false, true, &timeNowMetav1),
+			expectedSnapshots: withSnapshotDeletionBlocked(newSnapshotArray("snap3-13", "snapuid3-13", "", "content-3-13", validSecretClass, "content-3-13", &True, nil, nil, nil, false, true, &timeNowMetav1), crdv1.VolumeSnapshotReasonUsedAsSource, "Snapshot is being used to restore a PVC"),
+			initialClaims:     []*v1.PersistentVolumeClaim{newRestoringClaim("claim3-13", "snap3-13")},
+			expectedEvents:    []string{"Warning SnapshotDeletePending"},
+			initialSecrets:    []*v1.Secret{secret()},
+			errors:            noerrors,
+			test:              testSyncSnapshot,
+		},
+		{
+			name:              "4-1 - abandoned content has its finalizer removed without deleting the snapshot",
+			initialContents:   withContentDeletionTimestamp(withContentAnnotations(newContentArray("content4-1", "snapuid4-1", "snap4-1", "sid4-1", validSecretClass, "", "volume4-1", deletePolicy, nil, nil, true), map[string]string{utils.AnnVolumeSnapshotContentAbandon: "driver uninstalled"}), &timeNowMetav1),
+			expectedContents:  withContentDeletionTimestamp(withContentAnnotations(newContentArray("content4-1", "snapuid4-1", "snap4-1", "sid4-1", validSecretClass, "", "volume4-1", deletePolicy, nil, nil, false), map[string]string{utils.AnnVolumeSnapshotContentAbandon: "driver uninstalled"}), &timeNowMetav1),
+			initialSnapshots:  newSnapshotArray("snap4-1", "snapuid4-1", "claim4-1", "", validSecretClass, "content4-1", &True, nil, nil, nil, false, true, &timeNowMetav1),
+			expectedSnapshots: newSnapshotArray("snap4-1", "snapuid4-1", "claim4-1", "", validSecretClass, "content4-1", &True, nil, nil, nil, false, true, &timeNowMetav1),
+			expectedEvents:    []string{"Warning SnapshotAbandoned", "Warning SnapshotAbandoned"},
+			initialSecrets:    []*v1.Secret{secret()},
+			errors:            noerrors,
+			test:              testSyncContent,
+		},
+		{
+			name:              "4-2 - abandon annotation is ignored on a content that is not being deleted",
+			initialContents:   withContentAnnotations(newContentArray("content4-2", "snapuid4-2", "snap4-2", "sid4-2", validSecretClass, "", "volume4-2", deletePolicy, nil, nil, true), map[string]string{utils.AnnVolumeSnapshotContentAbandon: "driver uninstalled"}),
+			expectedContents:  withContentAnnotations(newContentArray("content4-2", "snapuid4-2", "snap4-2", "sid4-2", validSecretClass, "", "volume4-2", deletePolicy, nil, nil, true), map[string]string{utils.AnnVolumeSnapshotContentAbandon: "driver uninstalled"}),
+			initialSnapshots:  newSnapshotArray("snap4-2", "snapuid4-2", "claim4-2", "", validSecretClass, "content4-2", &True, nil, nil, nil, false, true, nil),
+			expectedSnapshots: newSnapshotArray("snap4-2", "snapuid4-2", "claim4-2", "", validSecretClass, "content4-2", &True, nil, nil, nil, false, true, nil),
+			expectedEvents:    noevents,
+			initialSecrets:    []*v1.Secret{secret()},
+			errors:            noerrors,
+			test:              testSyncContent,
+		},
 	}
 	runSyncTests(t, tests, snapshotClasses, nil)
 }
@@ -0,0 +1,76 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common_controller
+
+import (
+	"testing"
+
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+)
+
+func withSnapshotAnnotations(snapshots []*crdv1.VolumeSnapshot, annotations map[string]string) []*crdv1.VolumeSnapshot {
+	for i := range snapshots {
+		if snapshots[i].ObjectMeta.Annotations == nil {
+			snapshots[i].ObjectMeta.Annotations = make(map[string]string)
+		}
+		for k, v := range annotations {
+			snapshots[i].ObjectMeta.Annotations[k] = v
+		}
+	}
+	return snapshots
+}
+
+// Test single call to syncSnapshot for snapshots with expiry annotations.
+func TestSnapshotExpiry(t *testing.T) {
+	expired := map[string]string{utils.AnnVolumeSnapshotExpiresAt: "2020-01-01T00:00:00Z"}
+	notExpired := map[string]string{utils.AnnVolumeSnapshotTTL: "24h"}
+	invalid := map[string]string{utils.AnnVolumeSnapshotTTL: "one day"}
+	tests := []controllerTest{
+		{
+			name:              "11-1 - expired snapshot is deleted",
+			initialContents:   newContentArray("content11-1", "snapuid11-1", "snap11-1", "sid11-1", validSecretClass, "sid11-1", "", deletionPolicy, nil, nil, false),
+			expectedContents:  newContentArray("content11-1", "snapuid11-1", "snap11-1", "sid11-1", validSecretClass, "sid11-1", "", deletionPolicy, nil, nil, false),
+			initialSnapshots:  withSnapshotAnnotations(newSnapshotArray("snap11-1", "snapuid11-1", "", "content11-1", validSecretClass, "content11-1", &True, metaTimeNow, nil, nil, false, true, nil), expired),
+			expectedSnapshots: nosnapshots,
+			expectedEvents:    []string{"Normal SnapshotExpired"},
+			errors:            noerrors,
+			test:              testSyncSnapshot,
+		},
+		{
+			name:              "11-2 - snapshot within its ttl is kept",
+			initialContents:   newContentArray("content11-2", "snapuid11-2", "snap11-2", "sid11-2", validSecretClass, "sid11-2", "", deletionPolicy, nil, nil, false),
+			expectedContents:  newContentArray("content11-2", "snapuid11-2", "snap11-2", "sid11-2", validSecretClass, "sid11-2", "", deletionPolicy, nil, nil, false),
+			initialSnapshots:  withSnapshotAnnotations(newSnapshotArray("snap11-2", "snapuid11-2", "", "content11-2", validSecretClass, "content11-2", &True, metaTimeNow, nil, nil, false, true, nil), notExpired),
+			expectedSnapshots: withSnapshotAnnotations(newSnapshotArray("snap11-2", "snapuid11-2", "", "content11-2", validSecretClass, "content11-2", &True, metaTimeNow, nil, nil, false, true, nil), notExpired),
+			errors:            noerrors,
+			test:              testSyncSnapshot,
+		},
+		{
+			name:              "11-3 - invalid expiry annotation is reported and ignored",
+			initialContents:   newContentArray("content11-3", "snapuid11-3", "snap11-3", "sid11-3", validSecretClass, "sid11-3", "", deletionPolicy, nil, nil, false),
+			expectedContents:  newContentArray("content11-3", "snapuid11-3", "snap11-3", "sid11-3", validSecretClass, "sid11-3", "", deletionPolicy, nil, nil, false),
+			initialSnapshots:  withSnapshotAnnotations(newSnapshotArray("snap11-3", "snapuid11-3", "", "content11-3", validSecretClass, "content11-3", &True, metaTimeNow, nil, nil, false, true, nil), invalid),
+			expectedSnapshots: withSnapshotAnnotations(newSnapshotArray("snap11-3", "snapuid11-3", "", "content11-3", validSecretClass, "content11-3", &True, metaTimeNow, nil, nil, false, true, nil), invalid),
+			expectedEvents:    []string{"Warning InvalidSnapshotExpiry"},
+			errors:            noerrors,
+			test:              testSyncSnapshot,
+		},
+	}
+
+	runSyncTests(t, tests, snapshotClasses, nil)
+}
@@ -0,0 +1,530 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common_controller
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	crdv1alpha1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1alpha1"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	klog "k8s.io/klog/v2"
+)
+
+// Design:
+//
+// A VolumeSnapshotHook referenced by a dynamically provisioned VolumeSnapshot,
+// or by its class, makes the snapshot application-consistent:
+//
+// 1. Before the VolumeSnapshotContent is created, the pre command is run in
+//    every running pod that mounts the source PVC. The results are saved in
+//    status.hooks. If the command fails in any pod, the post command is run
+//    in the pods in which it succeeded, e.g. to thaw a frozen file system,
+//    and the snapshot is aborted: no content is created for it and it has
+//    to be deleted by the user. If the results cannot be saved, post is run
+//    in the pods in which pre succeeded before the sync is retried.
+// 2. Once the CSI driver has cut the snapshot, i.e. status.creationTime is
+//    set, the post command is run in the pods in which pre succeeded.
+// 3. If the content cannot be created after pre succeeded, post is run and
+//    the results are cleared, so that the next attempt runs pre again.
+// 4. post also runs before the snapshot is cut if the snapshot is deleted,
+//    if the CSI driver failed to cut it with a final error, or once the
+//    postDeadline of the hook has passed since pre ran. The application is
+//    never left e.g. frozen, at the cost of a possibly inconsistent
+//    snapshot, which is reported with a SnapshotPostHookForced event.
+// 5. A failed post command is run again, with the backoff of the snapshot
+//    queue, in the pods in which it did not succeed until it succeeds or the
+//    pods are gone. The results of an aborted attempt are only cleared then.
+//
+// Hooks are cluster scoped, so that only cluster administrators decide which
+// commands the controller runs with its pods/exec permission. The commands
+// run synchronously in the snapshot workers, so their timeouts should be
+// short.
+
+// errPostSnapshotHookFailed is returned when the post command failed in a
+// pod, so that the snapshot is synced again to retry it.
+var errPostSnapshotHookFailed = errors.New("post-snapshot hook failed")
+
+const (
+	// defaultHookTimeout is the timeout of hook commands without a timeout.
+	defaultHookTimeout = 30 * time.Second
+	// defaultHookPostDeadline is the postDeadline of hooks without one.
+	defaultHookPostDeadline = 5 * time.Minute
+	// maxHookOutput is the number of bytes of the output of a failed
+	// command that is kept in its result.
+	maxHookOutput = 512
+)
+
+// HookExecutor runs a command in a container of a pod.
+type HookExecutor interface {
+	// Exec runs the command and returns its combined stdout and stderr.
+	// It returns an error if the command could not be run, exited with a
+	// non-zero status or ctx was done before the command finished.
+	Exec(ctx context.Context, namespace, pod, container string, command []string) (string, error)
+}
+
+type podExecutor struct {
+	config *rest.Config
+	client kubernetes.Interface
+}
+
+// NewPodExecutor returns a HookExecutor that runs commands through the exec
+// subresource of pods.
+func NewPodExecutor(config *rest.Config, client kubernetes.Interface) HookExecutor {
+	return &podExecutor{config: config, client: client}
+}
+
+func (e *podExecutor) Exec(ctx context.Context, namespace, pod, container string, command []string) (string, error) {
+	req := e.client.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod).
+		SubResource("exec").
+		VersionedParams(&v1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+	executor, err := remotecommand.NewSPDYExecutor(e.config, "POST", req.URL())
+	if err != nil {
+		return "", err
+	}
+	var output bytes.Buffer
+	err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdout: &output,
+		Stderr: &output,
+	})
+	return output.String(), err
+}
+
+// getSnapshotHook returns the VolumeSnapshotHook referenced by the snapshot
+// or its class, or nil if hooks are disabled or none is referenced.
+func (ctrl *csiSnapshotCommonController) getSnapshotHook(snapshot *crdv1.VolumeSnapshot) (*crdv1alpha1.VolumeSnapshotHook, error) {
+	if ctrl.hookLister == nil || ctrl.hookExecutor == nil {
+		return nil, nil
+	}
+	if snapshot.Spec.Source.PersistentVolumeClaimName == nil || utils.IsVolumeGroupSnapshotMember(snapshot) {
+		return nil, nil
+	}
+	hookName := snapshot.Annotations[utils.AnnVolumeSnapshotHook]
+	if hookName == "" && snapshot.Spec.VolumeSnapshotClassName != nil {
+		class, err := ctrl.getSnapshotClass(*snapshot.Spec.VolumeSnapshotClassName)
+		if err != nil {
+			return nil, err
+		}
+		hookName = class.Annotations[utils.AnnVolumeSnapshotHook]
+	}
+	if hookName == "" {
+		return nil, nil
+	}
+	hook, err := ctrl.hookLister.Get(hookName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get VolumeSnapshotHook %s: %v", hookName, err)
+	}
+	return hook, nil
+}
+
+// runPreSnapshotHooks runs the pre command of the hook of the snapshot, if
+// any, before its content is created. It returns the updated snapshot and
+// false if the snapshot must not be taken.
+func (ctrl *csiSnapshotCommonController) runPreSnapshotHooks(snapshot *crdv1.VolumeSnapshot) (*crdv1.VolumeSnapshot, bool, error) {
+	hook, err := ctrl.getSnapshotHook(snapshot)
+	if err != nil {
+		ctrl.updateSnapshotErrorStatusWithEvent(snapshot, true, v1.EventTypeWarning, "SnapshotHookMissing", err.Error(), nil)
+		return snapshot, false, err
+	}
+	if hook == nil || hook.Spec.Pre == nil {
+		return snapshot, true, nil
+	}
+
+	preResults := hookResults(snapshot, crdv1.VolumeSnapshotHookPhasePre)
+	if len(preResults) > 0 {
+		if !allHooksSucceeded(preResults) {
+			// A failed pre hook aborts the snapshot for good, once
+			// the pods in which it succeeded have been thawed.
+			if hook.Spec.Post != nil {
+				snapshot, err = ctrl.runPendingPostSnapshotHooks(snapshot, hook)
+			}
+			return snapshot, false, err
+		}
+		if len(hookResults(snapshot, crdv1.VolumeSnapshotHookPhasePost)) == 0 {
+			// The hook has already run, the content is created next.
+			return snapshot, true, nil
+		}
+		// A previous attempt was aborted. Its post command must succeed
+		// before pre runs again.
+		if snapshot, err = ctrl.abortSnapshotHooks(snapshot); err != nil {
+			return snapshot, false, err
+		}
+	}
+
+	pods, err := ctrl.getHookPods(snapshot)
+	if err != nil {
+		return snapshot, false, err
+	}
+	if len(pods) == 0 {
+		klog.V(4).Infof("no running pod uses the source of snapshot %s, skipping the pre-snapshot hook", utils.SnapshotKey(snapshot))
+		return snapshot, true, nil
+	}
+	results := ctrl.runHookAction(snapshot, crdv1.VolumeSnapshotHookPhasePre, hook.Spec.Pre, pods)
+	succeeded := allHooksSucceeded(results)
+	newSnapshot, err := ctrl.updateSnapshotHookResults(snapshot, results)
+	if err != nil {
+		// Nothing remembers the pods in which pre succeeded, thaw them
+		// before the next attempt runs pre again.
+		if hook.Spec.Post != nil {
+			postResults := ctrl.runHookAction(snapshot, crdv1.VolumeSnapshotHookPhasePost, hook.Spec.Post, succeededHookPods(results, pods))
+			if !allHooksSucceeded(postResults) {
+				ctrl.eventRecorder.Event(snapshot, v1.EventTypeWarning, "SnapshotPostHookFailed", fmt.Sprintf("Post-snapshot hook %s failed: %s", hook.Name, hookFailureMessage(postResults)))
+			}
+		}
+		return snapshot, false, err
+	}
+	if !succeeded {
+		var postErr error
+		if hook.Spec.Post != nil {
+			newSnapshot, postErr = ctrl.runPendingPostSnapshotHooks(newSnapshot, hook)
+		}
+		msg := fmt.Sprintf("Pre-snapshot hook %s failed, the snapshot is not taken: %s", hook.Name, hookFailureMessage(results))
+		ctrl.updateSnapshotErrorStatusWithEvent(newSnapshot, true, v1.EventTypeWarning, "SnapshotPreHookFailed", msg, nil)
+		return newSnapshot, false, postErr
+	}
+	ctrl.eventRecorder.Event(newSnapshot, v1.EventTypeNormal, "SnapshotPreHookSucceeded", fmt.Sprintf("Pre-snapshot hook %s succeeded in %d pods", hook.Name, len(results)))
+	return newSnapshot, true, nil
+}
+
+// checkandRunPostSnapshotHooks runs the post command of the hook of the
+// snapshot once the snapshot has been cut and returns the updated snapshot.
+// If pre ran, post also runs before the snapshot is cut when
+// forcedPostHookMessage says so. Once post ran, it runs again in the pods in
+// which it failed.
+func (ctrl *csiSnapshotCommonController) checkandRunPostSnapshotHooks(snapshot *crdv1.VolumeSnapshot) (*crdv1.VolumeSnapshot, error) {
+	preResults := hookResults(snapshot, crdv1.VolumeSnapshotHookPhasePre)
+	retry := len(hookResults(snapshot, crdv1.VolumeSnapshotHookPhasePost)) > 0
+	created := utils.IsSnapshotCreated(snapshot)
+	if !retry && !created && len(preResults) == 0 {
+		return snapshot, nil
+	}
+	hook, err := ctrl.getSnapshotHook(snapshot)
+	if err != nil || hook == nil || hook.Spec.Post == nil {
+		// There is nothing to run for a missing hook.
+		return snapshot, nil
+	}
+	if hook.Spec.Pre != nil && len(preResults) == 0 {
+		// The hook was added after the content was created.
+		return snapshot, nil
+	}
+	if !retry && !created {
+		msg := ctrl.forcedPostHookMessage(snapshot, hook, preResults)
+		if msg == "" {
+			return snapshot, nil
+		}
+		ctrl.eventRecorder.Event(snapshot, v1.EventTypeWarning, "SnapshotPostHookForced", fmt.Sprintf("Running post-snapshot hook %s before the snapshot is cut: %s", hook.Name, msg))
+	}
+	return ctrl.runPendingPostSnapshotHooks(snapshot, hook)
+}
+
+// runPendingPostSnapshotHooks runs the post command of the hook in the
+// running pods in which pre succeeded, or in which post ran for hooks
+// without pre, and post did not succeed yet. It returns an error wrapping
+// errPostSnapshotHookFailed if post failed in any of them.
+func (ctrl *csiSnapshotCommonController) runPendingPostSnapshotHooks(snapshot *crdv1.VolumeSnapshot, hook *crdv1alpha1.VolumeSnapshotHook) (*crdv1.VolumeSnapshot, error) {
+	preResults := hookResults(snapshot, crdv1.VolumeSnapshotHookPhasePre)
+	postResults := hookResults(snapshot, crdv1.VolumeSnapshotHookPhasePost)
+	pods, err := ctrl.getHookPods(snapshot)
+	if err != nil {
+		return snapshot, err
+	}
+	switch {
+	case hook.Spec.Pre != nil:
+		pods = succeededHookPods(preResults, pods)
+	case len(postResults) > 0:
+		pods = hookResultPods(postResults, pods)
+	}
+	succeeded := succeededHookPods(postResults, pods)
+	var pending []*v1.Pod
+	for _, pod := range pods {
+		if !slices.Contains(succeeded, pod) {
+			pending = append(pending, pod)
+		}
+	}
+	if len(pending) == 0 {
+		return snapshot, nil
+	}
+
+	results := ctrl.runHookAction(snapshot, crdv1.VolumeSnapshotHookPhasePost, hook.Spec.Post, pending)
+	// The new results replace the failed ones of the same pods.
+	allResults := []crdv1.VolumeSnapshotHookResult{}
+	for _, result := range snapshot.Status.Hooks {
+		if result.Phase != crdv1.VolumeSnapshotHookPhasePost || len(hookResultPods([]crdv1.VolumeSnapshotHookResult{result}, pending)) == 0 {
+			allResults = append(allResults, result)
+		}
+	}
+	newSnapshot, err := ctrl.updateSnapshotHookResults(snapshot, append(allResults, results...))
+	if err != nil {
+		return snapshot, err
+	}
+	if !allHooksSucceeded(results) {
+		msg := fmt.Sprintf("Post-snapshot hook %s failed: %s", hook.Name, hookFailureMessage(results))
+		ctrl.eventRecorder.Event(newSnapshot, v1.EventTypeWarning, "SnapshotPostHookFailed", msg)
+		return newSnapshot, fmt.Errorf("%w: %s", errPostSnapshotHookFailed, msg)
+	}
+	ctrl.eventRecorder.Event(newSnapshot, v1.EventTypeNormal, "SnapshotPostHookSucceeded", fmt.Sprintf("Post-snapshot hook %s succeeded in %d pods", hook.Name, len(results)))
+	return newSnapshot, nil
+}
+
+// forcedPostHookMessage returns why the post command must run although the
+// snapshot has not been cut yet, or "" if it can wait. It enqueues the
+// snapshot again for the postDeadline of the hook.
+func (ctrl *csiSnapshotCommonController) forcedPostHookMessage(snapshot *crdv1.VolumeSnapshot, hook *crdv1alpha1.VolumeSnapshotHook, preResults []crdv1.VolumeSnapshotHookResult) string {
+	if snapshot.ObjectMeta.DeletionTimestamp != nil {
+		return "the snapshot is being deleted"
+	}
+
+	contentName := utils.GetDynamicSnapshotContentNameForSnapshot(snapshot)
+	if utils.IsBoundVolumeSnapshotContentNameSet(snapshot) {
+		contentName = *snapshot.Status.BoundVolumeSnapshotContentName
+	}
+	content, err := ctrl.getContentFromStore(contentName)
+	if err != nil {
+		klog.Errorf("forcedPostHookMessage[%s]: %v", utils.SnapshotKey(snapshot), err)
+	}
+	// The sidecar removes the being created annotation when the CSI driver
+	// returns a final error: the driver is no longer cutting the snapshot.
+	if content != nil && content.Status != nil && content.Status.Error != nil && !metav1.HasAnnotation(content.ObjectMeta, utils.AnnVolumeSnapshotBeingCreated) {
+		msg := "creating the snapshot failed"
+		if content.Status.Error.Message != nil {
+			msg = fmt.Sprintf("%s: %s", msg, *content.Status.Error.Message)
+		}
+		return msg
+	}
+
+	postDeadline := defaultHookPostDeadline
+	if hook.Spec.PostDeadline != nil {
+		postDeadline = hook.Spec.PostDeadline.Duration
+	}
+	var preCompletion time.Time
+	for _, result := range preResults {
+		if result.CompletionTime != nil && result.CompletionTime.After(preCompletion) {
+			preCompletion = result.CompletionTime.Time
+		}
+	}
+	if remaining := time.Until(preCompletion.Add(postDeadline)); remaining > 0 {
+		ctrl.snapshotQueue.AddAfter(utils.SnapshotKey(snapshot), remaining)
+		return ""
+	}
+	return fmt.Sprintf("the snapshot was not cut within %v", postDeadline)
+}
+
+// abortSnapshotHooks runs the post command in the pods in which the pre
+// command succeeded when the content of the snapshot could not be created,
+// and clears the results so that the next attempt runs the pre command
+// again. The results are kept until post has succeeded in all the pods.
+func (ctrl *csiSnapshotCommonController) abortSnapshotHooks(snapshot *crdv1.VolumeSnapshot) (*crdv1.VolumeSnapshot, error) {
+	preResults := hookResults(snapshot, crdv1.VolumeSnapshotHookPhasePre)
+	if len(preResults) == 0 {
+		return snapshot, nil
+	}
+	hook, err := ctrl.getSnapshotHook(snapshot)
+	if err != nil {
+		return snapshot, err
+	}
+	if hook != nil && hook.Spec.Post != nil {
+		if snapshot, err = ctrl.runPendingPostSnapshotHooks(snapshot, hook); err != nil {
+			return snapshot, err
+		}
+	}
+	return ctrl.updateSnapshotHookResults(snapshot, nil)
+}
+
+// getHookPods returns the running pods that mount the source PVC of the
+// snapshot, sorted by name.
+func (ctrl *csiSnapshotCommonController) getHookPods(snapshot *crdv1.VolumeSnapshot) ([]*v1.Pod, error) {
+	pvcName := *snapshot.Spec.Source.PersistentVolumeClaimName
+	allPods, err := ctrl.podLister.Pods(snapshot.Namespace).List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods using PVC %s/%s: %v", snapshot.Namespace, pvcName, err)
+	}
+	var pods []*v1.Pod
+	for _, pod := range allPods {
+		if pod.DeletionTimestamp != nil || pod.Status.Phase != v1.PodRunning {
+			continue
+		}
+		for _, volume := range pod.Spec.Volumes {
+			if volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName == pvcName {
+				pods = append(pods, pod)
+				break
+			}
+		}
+	}
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+	return pods, nil
+}
+
+// runHookAction runs the command of a hook in each pod and returns the
+// results.
+func (ctrl *csiSnapshotCommonController) runHookAction(snapshot *crdv1.VolumeSnapshot, phase crdv1.VolumeSnapshotHookPhase, action *crdv1alpha1.VolumeSnapshotHookAction, pods []*v1.Pod) []crdv1.VolumeSnapshotHookResult {
+	timeout := defaultHookTimeout
+	if action.Timeout != nil {
+		timeout = action.Timeout.Duration
+	}
+	results := make([]crdv1.VolumeSnapshotHookResult, 0, len(pods))
+	for _, pod := range pods {
+		container := action.Container
+		if container == "" && len(pod.Spec.Containers) > 0 {
+			container = pod.Spec.Containers[0].Name
+		}
+		result := crdv1.VolumeSnapshotHookResult{
+			Phase:     phase,
+			Pod:       pod.Name,
+			Container: container,
+			StartTime: &metav1.Time{Time: time.Now()},
+		}
+
+		klog.V(4).Infof("running %s hook of snapshot %s in container %s of pod %s", phase, utils.SnapshotKey(snapshot), container, pod.Name)
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		output, err := ctrl.hookExecutor.Exec(ctx, pod.Namespace, pod.Name, container, action.Command)
+		timedOut := ctx.Err() == context.DeadlineExceeded
+		cancel()
+
+		result.CompletionTime = &metav1.Time{Time: time.Now()}
+		switch {
+		case timedOut:
+			result.Outcome = crdv1.VolumeSnapshotHookTimedOut
+			msg := fmt.Sprintf("command did not finish within %v", timeout)
+			result.Message = &msg
+		case err != nil:
+			result.Outcome = crdv1.VolumeSnapshotHookFailed
+			msg := err.Error()
+			if output = strings.TrimSpace(output); output != "" {
+				if len(output) > maxHookOutput {
+					output = output[len(output)-maxHookOutput:]
+				}
+				msg = fmt.Sprintf("%s: %s", msg, output)
+			}
+			result.Message = &msg
+		default:
+			result.Outcome = crdv1.VolumeSnapshotHookSucceeded
+		}
+		klog.V(4).Infof("%s hook of snapshot %s in pod %s: %s", phase, utils.SnapshotKey(snapshot), pod.Name, result.Outcome)
+		results = append(results, result)
+	}
+	return results
+}
+
+// updateSnapshotHookResults saves the hook results in the snapshot status.
+func (ctrl *csiSnapshotCommonController) updateSnapshotHookResults(snapshot *crdv1.VolumeSnapshot, results []crdv1.VolumeSnapshotHookResult) (*crdv1.VolumeSnapshot, error) {
+	snapshotClone := snapshot.DeepCopy()
+	if snapshotClone.Status == nil {
+		snapshotClone.Status = &crdv1.VolumeSnapshotStatus{}
+	}
+	snapshotClone.Status.Hooks = results
+	newSnapshot, err := ctrl.clientset.SnapshotV1().VolumeSnapshots(snapshotClone.Namespace).UpdateStatus(context.TODO(), snapshotClone, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, newControllerUpdateError(utils.SnapshotKey(snapshot), err.Error())
+	}
+	if _, err := ctrl.storeSnapshotUpdate(newSnapshot); err != nil {
+		klog.Errorf("failed to update snapshot store %v", err)
+	}
+	return newSnapshot, nil
+}
+
+// hookResults returns the hook results of the given phase.
+func hookResults(snapshot *crdv1.VolumeSnapshot, phase crdv1.VolumeSnapshotHookPhase) []crdv1.VolumeSnapshotHookResult {
+	if snapshot.Status == nil {
+		return nil
+	}
+	var results []crdv1.VolumeSnapshotHookResult
+	for _, result := range snapshot.Status.Hooks {
+		if result.Phase == phase {
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+func allHooksSucceeded(results []crdv1.VolumeSnapshotHookResult) bool {
+	for _, result := range results {
+		if result.Outcome != crdv1.VolumeSnapshotHookSucceeded {
+			return false
+		}
+	}
+	return true
+}
+
+// succeededHookPods returns the pods in which a hook succeeded.
+func succeededHookPods(results []crdv1.VolumeSnapshotHookResult, pods []*v1.Pod) []*v1.Pod {
+	succeeded := map[string]bool{}
+	for _, result := range results {
+		if result.Outcome == crdv1.VolumeSnapshotHookSucceeded {
+			succeeded[result.Pod] = true
+		}
+	}
+	var matching []*v1.Pod
+	for _, pod := range pods {
+		if succeeded[pod.Name] {
+			matching = append(matching, pod)
+		}
+	}
+	return matching
+}
+
+// hookResultPods returns the pods that have a result.
+func hookResultPods(results []crdv1.VolumeSnapshotHookResult, pods []*v1.Pod) []*v1.Pod {
+	var matching []*v1.Pod
+	for _, pod := range pods {
+		for _, result := range results {
+			if result.Pod == pod.Name {
+				matching = append(matching, pod)
+				break
+			}
+		}
+	}
+	return matching
+}
+
+// hookFailureMessage describes the failed hook results.
+func hookFailureMessage(results []crdv1.VolumeSnapshotHookResult) string {
+	var failures []string
+	for _, result := range results {
+		if result.Outcome == crdv1.VolumeSnapshotHookSucceeded {
+			continue
+		}
+		failure := fmt.Sprintf("%s in pod %s: %s", result.Phase, result.Pod, result.Outcome)
+		if result.Message != nil {
+			failure += " (" + *result.Message + ")"
+		}
+		failures = append(failures, failure)
+	}
+	return strings.Join(failures, "; ")
+}
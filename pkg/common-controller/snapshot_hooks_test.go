@@ -0,0 +1,634 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common_controller
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	crdv1alpha1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1alpha1"
+	"github.com/kubernetes-csi/external-snapshotter/client/v8/clientset/versioned/fake"
+	informers "github.com/kubernetes-csi/external-snapshotter/client/v8/informers/externalversions"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	coreinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const testHookNamespace = "hooks"
+
+// fakeHookExecutor records the commands it runs. Commands fail in the pods
+// listed in failures and block until the context is done in the pods listed
+// in hangs.
+type fakeHookExecutor struct {
+	lock     sync.Mutex
+	calls    []string
+	failures map[string]bool
+	hangs    map[string]bool
+}
+
+func (e *fakeHookExecutor) Exec(ctx context.Context, namespace, pod, container string, command []string) (string, error) {
+	e.lock.Lock()
+	e.calls = append(e.calls, pod+"/"+container+":"+strings.Join(command, " "))
+	e.lock.Unlock()
+	if e.hangs[pod] {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}
+	if e.failures[pod] {
+		return "fsfreeze: device busy", errors.New("command terminated with exit code 1")
+	}
+	return "", nil
+}
+
+// delayRecordingQueue records the items added with a delay.
+type delayRecordingQueue struct {
+	workqueue.TypedRateLimitingInterface[string]
+	delayed []string
+}
+
+func (q *delayRecordingQueue) AddAfter(item string, duration time.Duration) {
+	q.delayed = append(q.delayed, item)
+}
+
+func newHookPod(name, pvcName string, phase v1.PodPhase) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testHookNamespace},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{Name: "app"}, {Name: "sidecar"}},
+			Volumes: []v1.Volume{{
+				Name: "data",
+				VolumeSource: v1.VolumeSource{
+					PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: pvcName},
+				},
+			}},
+		},
+		Status: v1.PodStatus{Phase: phase},
+	}
+}
+
+func newSnapshotHook(name string, pre, post []string) *crdv1alpha1.VolumeSnapshotHook {
+	hook := &crdv1alpha1.VolumeSnapshotHook{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+	}
+	if pre != nil {
+		hook.Spec.Pre = &crdv1alpha1.VolumeSnapshotHookAction{Command: pre}
+	}
+	if post != nil {
+		hook.Spec.Post = &crdv1alpha1.VolumeSnapshotHookAction{Command: post}
+	}
+	return hook
+}
+
+func withHookTimeout(hook *crdv1alpha1.VolumeSnapshotHook, timeout time.Duration) *crdv1alpha1.VolumeSnapshotHook {
+	for _, action := range []*crdv1alpha1.VolumeSnapshotHookAction{hook.Spec.Pre, hook.Spec.Post} {
+		if action != nil {
+			action.Timeout = &metav1.Duration{Duration: timeout}
+		}
+	}
+	return hook
+}
+
+func newHookSnapshot(name, hookName, className string) *crdv1.VolumeSnapshot {
+	pvcName := "data"
+	snapshot := &crdv1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testHookNamespace, UID: types.UID("uid-" + name)},
+		Spec: crdv1.VolumeSnapshotSpec{
+			Source: crdv1.VolumeSnapshotSource{PersistentVolumeClaimName: &pvcName},
+		},
+	}
+	if hookName != "" {
+		snapshot.Annotations = map[string]string{utils.AnnVolumeSnapshotHook: hookName}
+	}
+	if className != "" {
+		snapshot.Spec.VolumeSnapshotClassName = &className
+	}
+	return snapshot
+}
+
+func withPostDeadline(hook *crdv1alpha1.VolumeSnapshotHook, deadline time.Duration) *crdv1alpha1.VolumeSnapshotHook {
+	hook.Spec.PostDeadline = &metav1.Duration{Duration: deadline}
+	return hook
+}
+
+func newHookTestController(t *testing.T, snapshot *crdv1.VolumeSnapshot, hooks []*crdv1alpha1.VolumeSnapshotHook, classes []*crdv1.VolumeSnapshotClass, pods []*v1.Pod, executor HookExecutor) *csiSnapshotCommonController {
+	client := fake.NewSimpleClientset(snapshot)
+	kubeClient := kubefake.NewSimpleClientset()
+	coreFactory := coreinformers.NewSharedInformerFactory(kubeClient, 0)
+	podInformer := coreFactory.Core().V1().Pods()
+	for _, pod := range pods {
+		podInformer.Informer().GetIndexer().Add(pod)
+	}
+	snapshotQueue := &delayRecordingQueue{TypedRateLimitingInterface: workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[string]())}
+	t.Cleanup(snapshotQueue.ShutDown)
+	factory := informers.NewSharedInformerFactory(client, 0)
+	hookInformer := factory.Snapshot().V1alpha1().VolumeSnapshotHooks()
+	for _, hook := range hooks {
+		hookInformer.Informer().GetIndexer().Add(hook)
+	}
+	classInformer := factory.Snapshot().V1().VolumeSnapshotClasses()
+	for _, class := range classes {
+		classInformer.Informer().GetIndexer().Add(class)
+	}
+	return &csiSnapshotCommonController{
+		clientset:     client,
+		client:        kubeClient,
+		eventRecorder: record.NewFakeRecorder(100),
+		snapshotStore: cache.NewStore(cache.DeletionHandlingMetaNamespaceKeyFunc),
+		contentStore:  cache.NewStore(cache.DeletionHandlingMetaNamespaceKeyFunc),
+		snapshotQueue: snapshotQueue,
+		classLister:   classInformer.Lister(),
+		hookLister:    hookInformer.Lister(),
+		podLister:     podInformer.Lister(),
+		hookExecutor:  executor,
+	}
+}
+
+func hookOutcomes(snapshot *crdv1.VolumeSnapshot) []string {
+	var outcomes []string
+	if snapshot.Status == nil {
+		return outcomes
+	}
+	for _, result := range snapshot.Status.Hooks {
+		outcomes = append(outcomes, string(result.Phase)+" "+result.Pod+" "+string(result.Outcome))
+	}
+	sort.Strings(outcomes)
+	return outcomes
+}
+
+func collectEvents(ctrl *csiSnapshotCommonController) []string {
+	var events []string
+	recorder := ctrl.eventRecorder.(*record.FakeRecorder)
+	for {
+		select {
+		case event := <-recorder.Events:
+			fields := strings.SplitN(event, " ", 3)
+			events = append(events, fields[0]+" "+fields[1])
+		default:
+			return events
+		}
+	}
+}
+
+func TestRunPreSnapshotHooks(t *testing.T) {
+	freeze := []string{"fsfreeze", "-f", "/data"}
+	thaw := []string{"fsfreeze", "-u", "/data"}
+	pods := []*v1.Pod{
+		newHookPod("pod-a", "data", v1.PodRunning),
+		newHookPod("pod-b", "data", v1.PodRunning),
+		newHookPod("pod-pending", "data", v1.PodPending),
+		newHookPod("pod-other", "other", v1.PodRunning),
+	}
+	classWithHook := &crdv1.VolumeSnapshotClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "class-with-hook",
+			Annotations: map[string]string{utils.AnnVolumeSnapshotHook: "freeze"},
+		},
+	}
+	// The content of a previous attempt could not be created and post
+	// failed in pod-b.
+	abortedResults := []crdv1.VolumeSnapshotHookResult{
+		{Phase: crdv1.VolumeSnapshotHookPhasePre, Pod: "pod-a", Container: "app", Outcome: crdv1.VolumeSnapshotHookSucceeded},
+		{Phase: crdv1.VolumeSnapshotHookPhasePre, Pod: "pod-b", Container: "app", Outcome: crdv1.VolumeSnapshotHookSucceeded},
+		{Phase: crdv1.VolumeSnapshotHookPhasePost, Pod: "pod-a", Container: "app", Outcome: crdv1.VolumeSnapshotHookSucceeded},
+		{Phase: crdv1.VolumeSnapshotHookPhasePost, Pod: "pod-b", Container: "app", Outcome: crdv1.VolumeSnapshotHookFailed},
+	}
+	withHookResults := func(snapshot *crdv1.VolumeSnapshot, results []crdv1.VolumeSnapshotHookResult) *crdv1.VolumeSnapshot {
+		snapshot.Status = &crdv1.VolumeSnapshotStatus{Hooks: results}
+		return snapshot
+	}
+
+	tests := []struct {
+		name                string
+		snapshot            *crdv1.VolumeSnapshot
+		hooks               []*crdv1alpha1.VolumeSnapshotHook
+		failures            map[string]bool
+		hangs               map[string]bool
+		expectedProceed     bool
+		expectedErr         bool
+		expectedCalls       []string
+		expectedOutcomes    []string
+		expectedEvents      []string
+		expectedStatusError bool
+	}{
+		{
+			name:            "6-1 - snapshot without hook proceeds",
+			snapshot:        newHookSnapshot("snap6-1", "", ""),
+			hooks:           []*crdv1alpha1.VolumeSnapshotHook{newSnapshotHook("freeze", freeze, thaw)},
+			expectedProceed: true,
+		},
+		{
+			name:            "6-2 - pre hook runs in running pods that use the PVC",
+			snapshot:        newHookSnapshot("snap6-2", "freeze", ""),
+			hooks:           []*crdv1alpha1.VolumeSnapshotHook{newSnapshotHook("freeze", freeze, thaw)},
+			expectedProceed: true,
+			expectedCalls:   []string{"pod-a/app:fsfreeze -f /data", "pod-b/app:fsfreeze -f /data"},
+			expectedOutcomes: []string{
+				"Pre pod-a Succeeded",
+				"Pre pod-b Succeeded",
+			},
+			expectedEvents: []string{"Normal SnapshotPreHookSucceeded"},
+		},
+		{
+			name:            "6-3 - hook referenced by the class",
+			snapshot:        newHookSnapshot("snap6-3", "", "class-with-hook"),
+			hooks:           []*crdv1alpha1.VolumeSnapshotHook{newSnapshotHook("freeze", freeze, nil)},
+			expectedProceed: true,
+			expectedCalls:   []string{"pod-a/app:fsfreeze -f /data", "pod-b/app:fsfreeze -f /data"},
+			expectedOutcomes: []string{
+				"Pre pod-a Succeeded",
+				"Pre pod-b Succeeded",
+			},
+			expectedEvents: []string{"Normal SnapshotPreHookSucceeded"},
+		},
+		{
+			name:            "6-4 - failed pre hook aborts the snapshot and thaws the other pods",
+			snapshot:        newHookSnapshot("snap6-4", "freeze", ""),
+			hooks:           []*crdv1alpha1.VolumeSnapshotHook{newSnapshotHook("freeze", freeze, thaw)},
+			failures:        map[string]bool{"pod-b": true},
+			expectedProceed: false,
+			expectedCalls: []string{
+				"pod-a/app:fsfreeze -f /data",
+				"pod-b/app:fsfreeze -f /data",
+				"pod-a/app:fsfreeze -u /data",
+			},
+			expectedOutcomes: []string{
+				"Post pod-a Succeeded",
+				"Pre pod-a Succeeded",
+				"Pre pod-b Failed",
+			},
+			expectedEvents:      []string{"Normal SnapshotPostHookSucceeded", "Warning SnapshotPreHookFailed"},
+			expectedStatusError: true,
+		},
+		{
+			name:            "6-5 - pre hook timing out aborts the snapshot",
+			snapshot:        newHookSnapshot("snap6-5", "freeze", ""),
+			hooks:           []*crdv1alpha1.VolumeSnapshotHook{withHookTimeout(newSnapshotHook("freeze", freeze, nil), 10*time.Millisecond)},
+			hangs:           map[string]bool{"pod-a": true, "pod-b": true},
+			expectedProceed: false,
+			expectedCalls:   []string{"pod-a/app:fsfreeze -f /data", "pod-b/app:fsfreeze -f /data"},
+			expectedOutcomes: []string{
+				"Pre pod-a TimedOut",
+				"Pre pod-b TimedOut",
+			},
+			expectedEvents:      []string{"Warning SnapshotPreHookFailed"},
+			expectedStatusError: true,
+		},
+		{
+			name:                "6-6 - missing hook is reported",
+			snapshot:            newHookSnapshot("snap6-6", "missing", ""),
+			expectedProceed:     false,
+			expectedErr:         true,
+			expectedEvents:      []string{"Warning SnapshotHookMissing"},
+			expectedStatusError: true,
+		},
+		{
+			name:            "6-16 - pre hook runs again once the aborted attempt has been thawed",
+			snapshot:        withHookResults(newHookSnapshot("snap6-16", "freeze", ""), abortedResults),
+			hooks:           []*crdv1alpha1.VolumeSnapshotHook{newSnapshotHook("freeze", freeze, thaw)},
+			expectedProceed: true,
+			expectedCalls: []string{
+				"pod-b/app:fsfreeze -u /data",
+				"pod-a/app:fsfreeze -f /data",
+				"pod-b/app:fsfreeze -f /data",
+			},
+			expectedOutcomes: []string{
+				"Pre pod-a Succeeded",
+				"Pre pod-b Succeeded",
+			},
+			expectedEvents: []string{"Normal SnapshotPostHookSucceeded", "Normal SnapshotPreHookSucceeded"},
+		},
+		{
+			name:            "6-17 - aborted attempt is kept while its post hook fails",
+			snapshot:        withHookResults(newHookSnapshot("snap6-17", "freeze", ""), abortedResults),
+			hooks:           []*crdv1alpha1.VolumeSnapshotHook{newSnapshotHook("freeze", freeze, thaw)},
+			failures:        map[string]bool{"pod-b": true},
+			expectedProceed: false,
+			expectedErr:     true,
+			expectedCalls:   []string{"pod-b/app:fsfreeze -u /data"},
+			expectedOutcomes: []string{
+				"Post pod-a Succeeded",
+				"Post pod-b Failed",
+				"Pre pod-a Succeeded",
+				"Pre pod-b Succeeded",
+			},
+			expectedEvents: []string{"Warning SnapshotPostHookFailed"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			executor := &fakeHookExecutor{failures: test.failures, hangs: test.hangs}
+			ctrl := newHookTestController(t, test.snapshot, test.hooks, []*crdv1.VolumeSnapshotClass{classWithHook}, pods, executor)
+
+			_, proceed, err := ctrl.runPreSnapshotHooks(test.snapshot)
+			if (err != nil) != test.expectedErr {
+				t.Fatalf("expected error %v, got %v", test.expectedErr, err)
+			}
+			if proceed != test.expectedProceed {
+				t.Errorf("expected proceed %v, got %v", test.expectedProceed, proceed)
+			}
+			if strings.Join(executor.calls, ",") != strings.Join(test.expectedCalls, ",") {
+				t.Errorf("expected calls %v, got %v", test.expectedCalls, executor.calls)
+			}
+
+			snapshot, err := ctrl.clientset.SnapshotV1().VolumeSnapshots(testHookNamespace).Get(context.TODO(), test.snapshot.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("failed to get snapshot: %v", err)
+			}
+			if outcomes := hookOutcomes(snapshot); strings.Join(outcomes, ",") != strings.Join(test.expectedOutcomes, ",") {
+				t.Errorf("expected hook outcomes %v, got %v", test.expectedOutcomes, outcomes)
+			}
+			hasError := snapshot.Status != nil && snapshot.Status.Error != nil
+			if hasError != test.expectedStatusError {
+				t.Errorf("expected status error %v, got %v", test.expectedStatusError, snapshot.Status)
+			}
+			if events := collectEvents(ctrl); strings.Join(events, ",") != strings.Join(test.expectedEvents, ",") {
+				t.Errorf("expected events %v, got %v", test.expectedEvents, events)
+			}
+
+			// A second pass must not run the hook again.
+			executor.calls = nil
+			_, proceed, err = ctrl.runPreSnapshotHooks(snapshot)
+			if err == nil && len(executor.calls) != 0 {
+				t.Errorf("expected hook not to run again, got calls %v", executor.calls)
+			}
+			if err == nil && proceed != test.expectedProceed {
+				t.Errorf("expected proceed %v on second pass, got %v", test.expectedProceed, proceed)
+			}
+		})
+	}
+}
+
+func TestRunPreSnapshotHooksUpdateFailure(t *testing.T) {
+	freeze := []string{"fsfreeze", "-f", "/data"}
+	thaw := []string{"fsfreeze", "-u", "/data"}
+	pods := []*v1.Pod{
+		newHookPod("pod-a", "data", v1.PodRunning),
+		newHookPod("pod-b", "data", v1.PodRunning),
+	}
+	snapshot := newHookSnapshot("snap6-20", "freeze", "")
+	executor := &fakeHookExecutor{failures: map[string]bool{"pod-b": true}}
+	ctrl := newHookTestController(t, snapshot, []*crdv1alpha1.VolumeSnapshotHook{newSnapshotHook("freeze", freeze, thaw)}, nil, pods, executor)
+	ctrl.clientset.(*fake.Clientset).PrependReactor("update", "volumesnapshots", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("etcd unavailable")
+	})
+
+	// The pods in which pre succeeded are thawed, as nothing remembers
+	// them.
+	if _, proceed, err := ctrl.runPreSnapshotHooks(snapshot); err == nil || proceed {
+		t.Fatalf("expected the snapshot not to proceed with an error, got %v, %v", proceed, err)
+	}
+	expectedCalls := []string{
+		"pod-a/app:fsfreeze -f /data",
+		"pod-b/app:fsfreeze -f /data",
+		"pod-a/app:fsfreeze -u /data",
+	}
+	if strings.Join(executor.calls, ",") != strings.Join(expectedCalls, ",") {
+		t.Errorf("expected calls %v, got %v", expectedCalls, executor.calls)
+	}
+}
+
+func TestRunPostSnapshotHooks(t *testing.T) {
+	freeze := []string{"fsfreeze", "-f", "/data"}
+	thaw := []string{"fsfreeze", "-u", "/data"}
+	pods := []*v1.Pod{
+		newHookPod("pod-a", "data", v1.PodRunning),
+		newHookPod("pod-b", "data", v1.PodRunning),
+	}
+	newPreResults := func(completion time.Time) []crdv1.VolumeSnapshotHookResult {
+		return []crdv1.VolumeSnapshotHookResult{
+			{Phase: crdv1.VolumeSnapshotHookPhasePre, Pod: "pod-a", Container: "app", Outcome: crdv1.VolumeSnapshotHookSucceeded, CompletionTime: &metav1.Time{Time: completion}},
+		}
+	}
+	preResults := newPreResults(time.Now())
+	stalePreResults := newPreResults(time.Now().Add(-10 * time.Minute))
+	withStatus := func(snapshot *crdv1.VolumeSnapshot, created bool, results []crdv1.VolumeSnapshotHookResult) *crdv1.VolumeSnapshot {
+		snapshot.Status = &crdv1.VolumeSnapshotStatus{Hooks: results}
+		if created {
+			snapshot.Status.CreationTime = &metav1.Time{Time: time.Now()}
+		}
+		return snapshot
+	}
+	withDeletionTimestamp := func(snapshot *crdv1.VolumeSnapshot) *crdv1.VolumeSnapshot {
+		snapshot.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		return snapshot
+	}
+	newFailedContent := func(snapshotName string, beingCreated bool) *crdv1.VolumeSnapshotContent {
+		message := "failed to take snapshot of the volume: rpc error: code = InvalidArgument"
+		content := &crdv1.VolumeSnapshotContent{
+			ObjectMeta: metav1.ObjectMeta{Name: utils.GetDynamicSnapshotContentNameForSnapshot(newHookSnapshot(snapshotName, "", ""))},
+			Status:     &crdv1.VolumeSnapshotContentStatus{Error: &crdv1.VolumeSnapshotError{Message: &message}},
+		}
+		if beingCreated {
+			content.Annotations = map[string]string{utils.AnnVolumeSnapshotBeingCreated: "yes"}
+		}
+		return content
+	}
+	failedPostResults := []crdv1.VolumeSnapshotHookResult{
+		{Phase: crdv1.VolumeSnapshotHookPhasePre, Pod: "pod-a", Container: "app", Outcome: crdv1.VolumeSnapshotHookSucceeded},
+		{Phase: crdv1.VolumeSnapshotHookPhasePre, Pod: "pod-b", Container: "app", Outcome: crdv1.VolumeSnapshotHookSucceeded},
+		{Phase: crdv1.VolumeSnapshotHookPhasePost, Pod: "pod-a", Container: "app", Outcome: crdv1.VolumeSnapshotHookSucceeded},
+		{Phase: crdv1.VolumeSnapshotHookPhasePost, Pod: "pod-b", Container: "app", Outcome: crdv1.VolumeSnapshotHookFailed},
+	}
+
+	tests := []struct {
+		name             string
+		snapshot         *crdv1.VolumeSnapshot
+		hook             *crdv1alpha1.VolumeSnapshotHook
+		content          *crdv1.VolumeSnapshotContent
+		failures         map[string]bool
+		expectedErr      bool
+		expectedCalls    []string
+		expectedOutcomes []string
+		expectedEvents   []string
+		expectedRequeue  bool
+		// expectedRetryCalls are the calls of the next sync.
+		expectedRetryCalls []string
+	}{
+		{
+			name:             "6-7 - post hook waits for the snapshot to be cut",
+			snapshot:         withStatus(newHookSnapshot("snap6-7", "freeze", ""), false, preResults),
+			hook:             newSnapshotHook("freeze", freeze, thaw),
+			expectedOutcomes: []string{"Pre pod-a Succeeded"},
+			expectedRequeue:  true,
+		},
+		{
+			name:          "6-8 - post hook runs in the pods in which pre succeeded",
+			snapshot:      withStatus(newHookSnapshot("snap6-8", "freeze", ""), true, preResults),
+			hook:          newSnapshotHook("freeze", freeze, thaw),
+			expectedCalls: []string{"pod-a/app:fsfreeze -u /data"},
+			expectedOutcomes: []string{
+				"Post pod-a Succeeded",
+				"Pre pod-a Succeeded",
+			},
+			expectedEvents: []string{"Normal SnapshotPostHookSucceeded"},
+		},
+		{
+			name:          "6-9 - post-only hook runs in all pods using the PVC",
+			snapshot:      withStatus(newHookSnapshot("snap6-9", "resume", ""), true, nil),
+			hook:          newSnapshotHook("resume", nil, thaw),
+			failures:      map[string]bool{"pod-b": true},
+			expectedCalls: []string{"pod-a/app:fsfreeze -u /data", "pod-b/app:fsfreeze -u /data"},
+			expectedErr:   true,
+			expectedOutcomes: []string{
+				"Post pod-a Succeeded",
+				"Post pod-b Failed",
+			},
+			expectedEvents:     []string{"Warning SnapshotPostHookFailed"},
+			expectedRetryCalls: []string{"pod-b/app:fsfreeze -u /data"},
+		},
+		{
+			name:             "6-10 - post hook is skipped when pre did not run",
+			snapshot:         withStatus(newHookSnapshot("snap6-10", "freeze", ""), true, nil),
+			hook:             newSnapshotHook("freeze", freeze, thaw),
+			expectedOutcomes: nil,
+		},
+		{
+			name:          "6-11 - post hook runs once the post deadline has passed",
+			snapshot:      withStatus(newHookSnapshot("snap6-11", "freeze", ""), false, stalePreResults),
+			hook:          newSnapshotHook("freeze", freeze, thaw),
+			expectedCalls: []string{"pod-a/app:fsfreeze -u /data"},
+			expectedOutcomes: []string{
+				"Post pod-a Succeeded",
+				"Pre pod-a Succeeded",
+			},
+			expectedEvents: []string{"Warning SnapshotPostHookForced", "Normal SnapshotPostHookSucceeded"},
+		},
+		{
+			name:          "6-12 - post hook runs when the snapshot is deleted before it is cut",
+			snapshot:      withDeletionTimestamp(withStatus(newHookSnapshot("snap6-12", "freeze", ""), false, preResults)),
+			hook:          newSnapshotHook("freeze", freeze, thaw),
+			expectedCalls: []string{"pod-a/app:fsfreeze -u /data"},
+			expectedOutcomes: []string{
+				"Post pod-a Succeeded",
+				"Pre pod-a Succeeded",
+			},
+			expectedEvents: []string{"Warning SnapshotPostHookForced", "Normal SnapshotPostHookSucceeded"},
+		},
+		{
+			name:          "6-13 - post hook runs on a final error of the CSI driver",
+			snapshot:      withStatus(newHookSnapshot("snap6-13", "freeze", ""), false, preResults),
+			hook:          newSnapshotHook("freeze", freeze, thaw),
+			content:       newFailedContent("snap6-13", false),
+			expectedCalls: []string{"pod-a/app:fsfreeze -u /data"},
+			expectedOutcomes: []string{
+				"Post pod-a Succeeded",
+				"Pre pod-a Succeeded",
+			},
+			expectedEvents: []string{"Warning SnapshotPostHookForced", "Normal SnapshotPostHookSucceeded"},
+		},
+		{
+			name:             "6-14 - post hook waits while the CSI driver retries a non-final error",
+			snapshot:         withStatus(newHookSnapshot("snap6-14", "freeze", ""), false, preResults),
+			hook:             newSnapshotHook("freeze", freeze, thaw),
+			content:          newFailedContent("snap6-14", true),
+			expectedOutcomes: []string{"Pre pod-a Succeeded"},
+			expectedRequeue:  true,
+		},
+		{
+			name:             "6-15 - post deadline of the hook",
+			snapshot:         withStatus(newHookSnapshot("snap6-15", "freeze", ""), false, stalePreResults),
+			hook:             withPostDeadline(newSnapshotHook("freeze", freeze, thaw), time.Hour),
+			expectedOutcomes: []string{"Pre pod-a Succeeded"},
+			expectedRequeue:  true,
+		},
+		{
+			name:          "6-18 - failed post hook runs again only in the pods in which it failed",
+			snapshot:      withStatus(newHookSnapshot("snap6-18", "freeze", ""), true, failedPostResults),
+			hook:          newSnapshotHook("freeze", freeze, thaw),
+			expectedCalls: []string{"pod-b/app:fsfreeze -u /data"},
+			expectedOutcomes: []string{
+				"Post pod-a Succeeded",
+				"Post pod-b Succeeded",
+				"Pre pod-a Succeeded",
+				"Pre pod-b Succeeded",
+			},
+			expectedEvents: []string{"Normal SnapshotPostHookSucceeded"},
+		},
+		{
+			name:          "6-19 - failed post hook runs again before the snapshot is cut",
+			snapshot:      withStatus(newHookSnapshot("snap6-19", "freeze", ""), false, failedPostResults),
+			hook:          newSnapshotHook("freeze", freeze, thaw),
+			failures:      map[string]bool{"pod-b": true},
+			expectedErr:   true,
+			expectedCalls: []string{"pod-b/app:fsfreeze -u /data"},
+			expectedOutcomes: []string{
+				"Post pod-a Succeeded",
+				"Post pod-b Failed",
+				"Pre pod-a Succeeded",
+				"Pre pod-b Succeeded",
+			},
+			expectedEvents:     []string{"Warning SnapshotPostHookFailed"},
+			expectedRetryCalls: []string{"pod-b/app:fsfreeze -u /data"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			executor := &fakeHookExecutor{failures: test.failures}
+			ctrl := newHookTestController(t, test.snapshot, []*crdv1alpha1.VolumeSnapshotHook{test.hook}, nil, pods, executor)
+			if test.content != nil {
+				ctrl.contentStore.Add(test.content)
+			}
+
+			newSnapshot, err := ctrl.checkandRunPostSnapshotHooks(test.snapshot)
+			if (err != nil) != test.expectedErr {
+				t.Fatalf("expected error %v, got %v", test.expectedErr, err)
+			}
+			if err != nil && !errors.Is(err, errPostSnapshotHookFailed) {
+				t.Errorf("expected error to wrap %v, got %v", errPostSnapshotHookFailed, err)
+			}
+			if strings.Join(executor.calls, ",") != strings.Join(test.expectedCalls, ",") {
+				t.Errorf("expected calls %v, got %v", test.expectedCalls, executor.calls)
+			}
+			if outcomes := hookOutcomes(newSnapshot); strings.Join(outcomes, ",") != strings.Join(test.expectedOutcomes, ",") {
+				t.Errorf("expected hook outcomes %v, got %v", test.expectedOutcomes, outcomes)
+			}
+			if events := collectEvents(ctrl); strings.Join(events, ",") != strings.Join(test.expectedEvents, ",") {
+				t.Errorf("expected events %v, got %v", test.expectedEvents, events)
+			}
+			// The snapshot is enqueued again for the post deadline.
+			queue := ctrl.snapshotQueue.(*delayRecordingQueue)
+			if requeued := len(queue.delayed) > 0; requeued != test.expectedRequeue {
+				t.Errorf("expected requeue %v, got %v", test.expectedRequeue, queue.delayed)
+			}
+
+			// The post hook runs again only in the pods in which it failed.
+			executor.calls = nil
+			if _, err := ctrl.checkandRunPostSnapshotHooks(newSnapshot); (err != nil) != test.expectedErr {
+				t.Fatalf("expected error %v on the next sync, got %v", test.expectedErr, err)
+			}
+			if len(test.expectedCalls) > 0 && strings.Join(executor.calls, ",") != strings.Join(test.expectedRetryCalls, ",") {
+				t.Errorf("expected calls %v on the next sync, got %v", test.expectedRetryCalls, executor.calls)
+			}
+		})
+	}
+}
@@ -17,8 +17,10 @@ limitations under the License.
 package common_controller
 
 import (
-	v1 "k8s.io/api/core/v1"
 	"testing"
+
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	v1 "k8s.io/api/core/v1"
 )
 
 // Test single call to checkAndUpdateSnapshotClass.
@@ -57,7 +59,7 @@ func TestUpdateSnapshotClass(t *testing.T) {
 			name:              "1-3 - snapshot class name not found",
 			initialContents:   nocontents,
 			initialSnapshots:  newSnapshotArray("snap1-3", "snapuid1-3", "claim1-3", "", "missing-class", "", &True, nil, nil, nil, false, true, nil),
-			expectedSnapshots: newSnapshotArray("snap1-3", "snapuid1-3", "claim1-3", "", "missing-class", "", &True, nil, nil, newVolumeError("Failed to get snapshot class with error volumesnapshotclass.snapshot.storage.k8s.io \"missing-class\" not found"), false, true, nil),
+			expectedSnapshots: newSnapshotArray("snap1-3", "snapuid1-3", "claim1-3", "", "missing-class", "", &True, nil, nil, newVolumeError("Failed to get snapshot class with error volumesnapshotclass.snapshot.storage.k8s.io \"missing-class\" not found", crdv1.VolumeSnapshotErrorReasonClassError), false, true, nil),
 			initialClaims:     newClaimArray("claim1-3", "pvc-uid1-3", "1Gi", "volume1-3", v1.ClaimBound, &sameDriver),
 			initialVolumes:    newVolumeArray("volume1-3", "pv-uid1-3", "pv-handle1-3", "1Gi", "pvc-uid1-3", "claim1-3", v1.VolumeBound, v1.PersistentVolumeReclaimDelete, sameDriver),
 			expectedEvents:    []string{"Warning GetSnapshotClassFailed"},
@@ -69,13 +71,29 @@ func TestUpdateSnapshotClass(t *testing.T) {
 			name:              "1-5 - snapshot update with default class name failed because PVC was not found",
 			initialContents:   nocontents,
 			initialSnapshots:  newSnapshotArray("snap1-5", "snapuid1-5", "claim1-5", "", "", "", &True, nil, nil, nil, false, true, nil),
-			expectedSnapshots: newSnapshotArray("snap1-5", "snapuid1-5", "claim1-5", "", "", "", &True, nil, nil, newVolumeError("Failed to set default snapshot class with error failed to retrieve PVC claim1-5 from the lister: \"persistentvolumeclaim \\\"claim1-5\\\" not found\""), false, true, nil),
+			expectedSnapshots: newSnapshotArray("snap1-5", "snapuid1-5", "claim1-5", "", "", "", &True, nil, nil, newVolumeError("Failed to set default snapshot class with error failed to retrieve PVC claim1-5 from the lister: \"persistentvolumeclaim \\\"claim1-5\\\" not found\"", "SetDefaultSnapshotClassFailed"), false, true, nil),
 			initialClaims:     nil,
 			initialVolumes:    nil,
 			expectedEvents:    []string{"Warning SetDefaultSnapshotClassFailed"},
 			errors:            noerrors,
 			test:              testUpdateSnapshotClass,
 		},
+		{
+			// no default snapshot class for the driver of the volume
+			name:              "1-6 - snapshot update with default class name failed because there is no default class",
+			initialContents:   nocontents,
+			initialSnapshots:  newSnapshotArray("snap1-6", "snapuid1-6", "claim1-6", "", "", "", &True, nil, nil, nil, false, true, nil),
+			expectedSnapshots: newSnapshotArray("snap1-6", "snapuid1-6", "claim1-6", "", "", "", &True, nil, nil, newVolumeError("Failed to set default snapshot class with error cannot find default snapshot class", crdv1.VolumeSnapshotErrorReasonClassError), false, true, nil),
+			initialClaims:     newClaimArray("claim1-6", "pvc-uid1-6", "1Gi", "volume1-6", v1.ClaimBound, &sameDriver),
+			initialVolumes: func() []*v1.PersistentVolume {
+				volumes := newVolumeArray("volume1-6", "pv-uid1-6", "pv-handle1-6", "1Gi", "pvc-uid1-6", "claim1-6", v1.VolumeBound, v1.PersistentVolumeReclaimDelete, sameDriver)
+				volumes[0].Spec.CSI.Driver = "other.csi.driver"
+				return volumes
+			}(),
+			expectedEvents: []string{"Warning SetDefaultSnapshotClassFailed"},
+			errors:         noerrors,
+			test:           testUpdateSnapshotClass,
+		},
 	}
 
 	runUpdateSnapshotClassTests(t, tests, snapshotClasses)
@@ -0,0 +1,495 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common_controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	crdv1beta1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumegroupsnapshot/v1beta1"
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	groupsnapshotinformers "github.com/kubernetes-csi/external-snapshotter/client/v8/informers/externalversions/volumegroupsnapshot/v1beta1"
+	snapshotinformers "github.com/kubernetes-csi/external-snapshotter/client/v8/informers/externalversions/volumesnapshot/v1"
+	groupsnapshotlisters "github.com/kubernetes-csi/external-snapshotter/client/v8/listers/volumegroupsnapshot/v1beta1"
+	snapshotlisters "github.com/kubernetes-csi/external-snapshotter/client/v8/listers/volumesnapshot/v1"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/metrics"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	klog "k8s.io/klog/v2"
+)
+
+// Reasons why a stuck operation is blocked. They are reported in the
+// stuck_operations metric and in the OperationStuck events.
+const (
+	stuckReasonError                           = crdv1.VolumeSnapshotReasonError
+	stuckReasonWaitingForContent               = crdv1.VolumeSnapshotReasonWaitingForContent
+	stuckReasonUsedAsSource                    = crdv1.VolumeSnapshotReasonUsedAsSource
+	stuckReasonInGroup                         = crdv1.VolumeSnapshotReasonInGroup
+	stuckReasonPVCNotFound                     = "PVCNotFound"
+	stuckReasonPVCBeingDeleted                 = "PVCBeingDeleted"
+	stuckReasonSecretNotFound                  = "SecretNotFound"
+	stuckReasonVolumeSnapshotBeingCreated      = "VolumeSnapshotBeingCreated"
+	stuckReasonVolumeGroupSnapshotBeingCreated = "VolumeGroupSnapshotBeingCreated"
+	stuckReasonWaitingForReadyToUse            = "WaitingForReadyToUse"
+	stuckReasonWaitingForContentDeletion       = "WaitingForContentDeletion"
+	stuckReasonUnknown                         = "Unknown"
+
+	stuckThresholdsSeparator  = ","
+	stuckThresholdsAssignment = "="
+)
+
+// stuckOperationNames are the operations checked by the watchdog.
+var stuckOperationNames = []string{
+	metrics.CreateSnapshotOperationName,
+	metrics.CreateSnapshotAndReadyOperationName,
+	metrics.DeleteSnapshotOperationName,
+	metrics.CreateGroupSnapshotOperationName,
+	metrics.CreateGroupSnapshotAndReadyOperationName,
+	metrics.DeleteGroupSnapshotOperationName,
+}
+
+// StuckOperationThresholds are the ages beyond which operations in flight
+// are stuck, by operation name. Operations without a positive threshold are
+// not checked.
+type StuckOperationThresholds map[string]time.Duration
+
+// ParseStuckOperationThresholdsFlag returns the thresholds set by a comma
+// separated list of operation=duration pairs, e.g.
+// "CreateSnapshot=10m,DeleteGroupSnapshot=1h". Operations without a
+// threshold in the list get the default one.
+func ParseStuckOperationThresholdsFlag(defaultThreshold time.Duration, flag string) (StuckOperationThresholds, error) {
+	thresholds := StuckOperationThresholds{}
+	for _, name := range stuckOperationNames {
+		thresholds[name] = defaultThreshold
+	}
+	for _, pair := range strings.Split(flag, stuckThresholdsSeparator) {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, value, found := strings.Cut(pair, stuckThresholdsAssignment)
+		if !found {
+			return nil, fmt.Errorf("invalid stuck operation threshold %q: must be an operation=duration pair", pair)
+		}
+		name = strings.TrimSpace(name)
+		if !slices.Contains(stuckOperationNames, name) {
+			return nil, fmt.Errorf("unknown operation %q, must be one of %s", name, strings.Join(stuckOperationNames, ", "))
+		}
+		threshold, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || threshold < 0 {
+			return nil, fmt.Errorf("invalid threshold %q of operation %q: must be a non-negative duration", value, name)
+		}
+		thresholds[name] = threshold
+	}
+	return thresholds, nil
+}
+
+// stuckOperationWatchdog periodically checks the operations in flight of
+// the metrics manager. Operations older than their threshold are reported
+// in the stuck_operations metric, and with a Warning event on their
+// VolumeSnapshot or VolumeGroupSnapshot when they cross their threshold and
+// then twice the threshold of the previous event, together with the reason
+// they are blocked.
+type stuckOperationWatchdog struct {
+	client         kubernetes.Interface
+	eventRecorder  record.EventRecorder
+	metricsManager metrics.MetricsManager
+
+	snapshotLister                   snapshotlisters.VolumeSnapshotLister
+	snapshotListerSynced             cache.InformerSynced
+	contentLister                    snapshotlisters.VolumeSnapshotContentLister
+	contentListerSynced              cache.InformerSynced
+	groupSnapshotLister              groupsnapshotlisters.VolumeGroupSnapshotLister
+	groupSnapshotListerSynced        cache.InformerSynced
+	groupSnapshotContentLister       groupsnapshotlisters.VolumeGroupSnapshotContentLister
+	groupSnapshotContentListerSynced cache.InformerSynced
+	pvcLister                        corelisters.PersistentVolumeClaimLister
+	pvcListerSynced                  cache.InformerSynced
+
+	interval   time.Duration
+	thresholds StuckOperationThresholds
+
+	// reported are the thresholds the operations in flight were last
+	// reported at with an event. It is only used by the single worker.
+	reported map[metrics.OperationKey]stuckReport
+	// secretsForbidden is set once the missing permission to get secrets
+	// has been logged.
+	secretsForbidden bool
+
+	// now returns the current time. It is replaced in unit tests.
+	now func() time.Time
+}
+
+// stuckReport is the threshold an operation in flight was last reported at.
+type stuckReport struct {
+	startTime time.Time
+	threshold time.Duration
+}
+
+// NewStuckOperationWatchdog returns a new *stuckOperationWatchdog. The events
+// are emitted with the recorder of the snapshot controller. The group
+// snapshot informers are nil when VolumeGroupSnapshots are disabled, in which
+// case the group snapshot operations are not checked.
+func NewStuckOperationWatchdog(
+	client kubernetes.Interface,
+	eventRecorder record.EventRecorder,
+	volumeSnapshotInformer snapshotinformers.VolumeSnapshotInformer,
+	volumeSnapshotContentInformer snapshotinformers.VolumeSnapshotContentInformer,
+	volumeGroupSnapshotInformer groupsnapshotinformers.VolumeGroupSnapshotInformer,
+	volumeGroupSnapshotContentInformer groupsnapshotinformers.VolumeGroupSnapshotContentInformer,
+	pvcInformer coreinformers.PersistentVolumeClaimInformer,
+	metricsManager metrics.MetricsManager,
+	interval time.Duration,
+	thresholds StuckOperationThresholds,
+) *stuckOperationWatchdog {
+	ctrl := &stuckOperationWatchdog{
+		client:         client,
+		eventRecorder:  eventRecorder,
+		metricsManager: metricsManager,
+		interval:       interval,
+		thresholds:     thresholds,
+		reported:       map[metrics.OperationKey]stuckReport{},
+		now:            time.Now,
+	}
+
+	ctrl.snapshotLister = volumeSnapshotInformer.Lister()
+	ctrl.snapshotListerSynced = volumeSnapshotInformer.Informer().HasSynced
+	ctrl.contentLister = volumeSnapshotContentInformer.Lister()
+	ctrl.contentListerSynced = volumeSnapshotContentInformer.Informer().HasSynced
+	ctrl.pvcLister = pvcInformer.Lister()
+	ctrl.pvcListerSynced = pvcInformer.Informer().HasSynced
+	if volumeGroupSnapshotInformer != nil && volumeGroupSnapshotContentInformer != nil {
+		ctrl.groupSnapshotLister = volumeGroupSnapshotInformer.Lister()
+		ctrl.groupSnapshotListerSynced = volumeGroupSnapshotInformer.Informer().HasSynced
+		ctrl.groupSnapshotContentLister = volumeGroupSnapshotContentInformer.Lister()
+		ctrl.groupSnapshotContentListerSynced = volumeGroupSnapshotContentInformer.Informer().HasSynced
+	}
+
+	return ctrl
+}
+
+// Run checks the operations in flight every interval until stopCh is
+// closed. A single worker is used whatever the number of workers.
+func (ctrl *stuckOperationWatchdog) Run(workers int, stopCh <-chan struct{}) {
+	klog.Infof("Starting stuck operation watchdog")
+	defer klog.Infof("Shutting stuck operation watchdog")
+
+	informersSynced := []cache.InformerSynced{ctrl.snapshotListerSynced, ctrl.contentListerSynced, ctrl.pvcListerSynced}
+	if ctrl.groupSnapshotLister != nil {
+		informersSynced = append(informersSynced, ctrl.groupSnapshotListerSynced, ctrl.groupSnapshotContentListerSynced)
+	}
+	if !cache.WaitForCacheSync(stopCh, informersSynced...) {
+		klog.Errorf("Cannot sync caches")
+		return
+	}
+
+	wait.Until(ctrl.checkOperations, ctrl.interval, stopCh)
+}
+
+// checkOperations reports the operations in flight that are older than
+// their threshold, and records the age of all of them.
+func (ctrl *stuckOperationWatchdog) checkOperations() {
+	now := ctrl.now()
+	inFlight := ctrl.metricsManager.InFlightOperations()
+
+	// Forget the operations that are no longer in flight.
+	inFlightKeys := make(map[metrics.OperationKey]bool, len(inFlight))
+	for _, op := range inFlight {
+		inFlightKeys[op.Key] = true
+	}
+	for key := range ctrl.reported {
+		if !inFlightKeys[key] {
+			delete(ctrl.reported, key)
+		}
+	}
+
+	// The objects are looked up by UID, so they are only indexed when an
+	// operation is stuck.
+	var snapshots map[types.UID]*crdv1.VolumeSnapshot
+	var groupSnapshots map[types.UID]*crdv1beta1.VolumeGroupSnapshot
+	stuck := []metrics.StuckOperation{}
+	for _, op := range inFlight {
+		threshold := ctrl.thresholds[op.Key.Name]
+		age := now.Sub(op.StartTime)
+		if threshold <= 0 || age < threshold {
+			continue
+		}
+
+		var object runtime.Object
+		var reason, message string
+		switch op.Key.Name {
+		case metrics.CreateSnapshotOperationName, metrics.CreateSnapshotAndReadyOperationName, metrics.DeleteSnapshotOperationName:
+			if snapshots == nil {
+				snapshots = ctrl.snapshotsByUID()
+			}
+			snapshot, found := snapshots[op.Key.ResourceID]
+			if !found {
+				continue
+			}
+			object = snapshot
+			reason, message = ctrl.snapshotBlockedReason(op.Key.Name, snapshot)
+		case metrics.CreateGroupSnapshotOperationName, metrics.CreateGroupSnapshotAndReadyOperationName, metrics.DeleteGroupSnapshotOperationName:
+			if ctrl.groupSnapshotLister == nil {
+				continue
+			}
+			if groupSnapshots == nil {
+				groupSnapshots = ctrl.groupSnapshotsByUID()
+			}
+			groupSnapshot, found := groupSnapshots[op.Key.ResourceID]
+			if !found {
+				continue
+			}
+			object = groupSnapshot
+			reason, message = ctrl.groupSnapshotBlockedReason(op.Key.Name, groupSnapshot)
+		default:
+			continue
+		}
+
+		klog.V(4).Infof("%s operation of %s stuck for %v: %s: %s", op.Key.Name, op.Key.ResourceID, age, reason, message)
+		if ctrl.crossedThreshold(op, threshold, age) {
+			ctrl.eventRecorder.Eventf(object, v1.EventTypeWarning, "OperationStuck",
+				"%s operation in flight for %v, blocked by %s: %s", op.Key.Name, age.Round(time.Second), reason, message)
+		}
+		stuck = append(stuck, metrics.StuckOperation{InFlightOperation: op, Reason: reason})
+	}
+
+	ctrl.metricsManager.RecordStuckOperations(now, inFlight, stuck)
+}
+
+// crossedThreshold returns true if a stuck operation has crossed its
+// threshold, or twice the threshold it was last reported at, since the
+// previous check. A stuck operation is thus reported at its threshold, and
+// then at 2, 4, 8... times its threshold, instead of at every check.
+func (ctrl *stuckOperationWatchdog) crossedThreshold(op metrics.InFlightOperation, threshold, age time.Duration) bool {
+	next := threshold
+	if last, found := ctrl.reported[op.Key]; found && last.startTime.Equal(op.StartTime) {
+		next = 2 * last.threshold
+	}
+	if age < next {
+		return false
+	}
+	for 2*next <= age {
+		next *= 2
+	}
+	ctrl.reported[op.Key] = stuckReport{startTime: op.StartTime, threshold: next}
+	return true
+}
+
+func (ctrl *stuckOperationWatchdog) snapshotsByUID() map[types.UID]*crdv1.VolumeSnapshot {
+	snapshots := map[types.UID]*crdv1.VolumeSnapshot{}
+	list, err := ctrl.snapshotLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list snapshots: %v", err)
+		return snapshots
+	}
+	for _, snapshot := range list {
+		snapshots[snapshot.UID] = snapshot
+	}
+	return snapshots
+}
+
+func (ctrl *stuckOperationWatchdog) groupSnapshotsByUID() map[types.UID]*crdv1beta1.VolumeGroupSnapshot {
+	groupSnapshots := map[types.UID]*crdv1beta1.VolumeGroupSnapshot{}
+	list, err := ctrl.groupSnapshotLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list group snapshots: %v", err)
+		return groupSnapshots
+	}
+	for _, groupSnapshot := range list {
+		groupSnapshots[groupSnapshot.UID] = groupSnapshot
+	}
+	return groupSnapshots
+}
+
+// snapshotBlockedReason returns the reason why an operation of a snapshot
+// is blocked, and a message describing it.
+func (ctrl *stuckOperationWatchdog) snapshotBlockedReason(operation string, snapshot *crdv1.VolumeSnapshot) (string, string) {
+	if snapshot.Status != nil && snapshot.Status.Error != nil && snapshot.Status.Error.Message != nil {
+		return stuckReasonError, *snapshot.Status.Error.Message
+	}
+	content := ctrl.getSnapshotContent(snapshot)
+
+	if operation == metrics.DeleteSnapshotOperationName {
+		if volumeBeingCreatedFromSnapshot(ctrl.pvcLister, snapshot) {
+			return stuckReasonUsedAsSource, "the snapshot is being used to restore a PVC"
+		}
+		if snapshot.Status != nil && snapshot.Status.VolumeGroupSnapshotName != nil && ctrl.groupSnapshotLister != nil {
+			if _, err := ctrl.groupSnapshotLister.VolumeGroupSnapshots(snapshot.Namespace).Get(*snapshot.Status.VolumeGroupSnapshotName); err == nil {
+				return stuckReasonInGroup, fmt.Sprintf("the snapshot belongs to group snapshot %s/%s", snapshot.Namespace, *snapshot.Status.VolumeGroupSnapshotName)
+			}
+		}
+		if content == nil {
+			return stuckReasonUnknown, "no VolumeSnapshotContent is bound to the snapshot"
+		}
+		if reason, message := ctrl.secretBlockedReason(content.Annotations, utils.AnnDeletionSecretRefName, utils.AnnDeletionSecretRefNamespace); reason != "" {
+			return reason, message
+		}
+		if metav1.HasAnnotation(content.ObjectMeta, utils.AnnVolumeSnapshotBeingCreated) {
+			return stuckReasonVolumeSnapshotBeingCreated, fmt.Sprintf("VolumeSnapshotContent %s is still being created by the CSI driver", content.Name)
+		}
+		if content.DeletionTimestamp != nil {
+			return stuckReasonWaitingForContentDeletion, fmt.Sprintf("waiting for VolumeSnapshotContent %s to be deleted", content.Name)
+		}
+		return stuckReasonUnknown, fmt.Sprintf("VolumeSnapshotContent %s is not being deleted", content.Name)
+	}
+
+	if content == nil {
+		if snapshot.Spec.Source.PersistentVolumeClaimName != nil {
+			pvcName := *snapshot.Spec.Source.PersistentVolumeClaimName
+			pvc, err := ctrl.pvcLister.PersistentVolumeClaims(snapshot.Namespace).Get(pvcName)
+			if errors.IsNotFound(err) {
+				return stuckReasonPVCNotFound, fmt.Sprintf("source PVC %s/%s not found", snapshot.Namespace, pvcName)
+			}
+			if err == nil && pvc.DeletionTimestamp != nil && !slices.Contains(pvc.Finalizers, utils.PVCFinalizer) {
+				return stuckReasonPVCBeingDeleted, fmt.Sprintf("cannot add finalizer %s on source PVC %s/%s because it is being deleted", utils.PVCFinalizer, snapshot.Namespace, pvcName)
+			}
+		}
+		return stuckReasonWaitingForContent, "waiting for a VolumeSnapshotContent to be bound to the snapshot"
+	}
+	if reason, message := ctrl.secretBlockedReason(content.Annotations, utils.AnnDeletionSecretRefName, utils.AnnDeletionSecretRefNamespace); reason != "" {
+		return reason, message
+	}
+	if content.Status != nil && content.Status.Error != nil && content.Status.Error.Message != nil {
+		return stuckReasonError, *content.Status.Error.Message
+	}
+	if metav1.HasAnnotation(content.ObjectMeta, utils.AnnVolumeSnapshotBeingCreated) {
+		return stuckReasonVolumeSnapshotBeingCreated, fmt.Sprintf("VolumeSnapshotContent %s is still being created by the CSI driver", content.Name)
+	}
+	if content.Status == nil || content.Status.ReadyToUse == nil || !*content.Status.ReadyToUse {
+		return stuckReasonWaitingForReadyToUse, fmt.Sprintf("waiting for VolumeSnapshotContent %s to be ready to use", content.Name)
+	}
+	return stuckReasonUnknown, fmt.Sprintf("VolumeSnapshotContent %s is ready to use", content.Name)
+}
+
+// groupSnapshotBlockedReason returns the reason why an operation of a group
+// snapshot is blocked, and a message describing it.
+func (ctrl *stuckOperationWatchdog) groupSnapshotBlockedReason(operation string, groupSnapshot *crdv1beta1.VolumeGroupSnapshot) (string, string) {
+	if groupSnapshot.Status != nil && groupSnapshot.Status.Error != nil && groupSnapshot.Status.Error.Message != nil {
+		return stuckReasonError, *groupSnapshot.Status.Error.Message
+	}
+	content := ctrl.getGroupSnapshotContent(groupSnapshot)
+
+	if operation == metrics.DeleteGroupSnapshotOperationName {
+		if content == nil {
+			return stuckReasonUnknown, "no VolumeGroupSnapshotContent is bound to the group snapshot"
+		}
+		if reason, message := ctrl.secretBlockedReason(content.Annotations, utils.AnnDeletionGroupSecretRefName, utils.AnnDeletionGroupSecretRefNamespace); reason != "" {
+			return reason, message
+		}
+		if metav1.HasAnnotation(content.ObjectMeta, utils.AnnVolumeGroupSnapshotBeingCreated) {
+			return stuckReasonVolumeGroupSnapshotBeingCreated, fmt.Sprintf("VolumeGroupSnapshotContent %s is still being created by the CSI driver", content.Name)
+		}
+		if content.DeletionTimestamp != nil {
+			return stuckReasonWaitingForContentDeletion, fmt.Sprintf("waiting for VolumeGroupSnapshotContent %s to be deleted", content.Name)
+		}
+		return stuckReasonUnknown, fmt.Sprintf("VolumeGroupSnapshotContent %s is not being deleted", content.Name)
+	}
+
+	if content == nil {
+		return stuckReasonWaitingForContent, "waiting for a VolumeGroupSnapshotContent to be bound to the group snapshot"
+	}
+	if reason, message := ctrl.secretBlockedReason(content.Annotations, utils.AnnDeletionGroupSecretRefName, utils.AnnDeletionGroupSecretRefNamespace); reason != "" {
+		return reason, message
+	}
+	if content.Status != nil && content.Status.Error != nil && content.Status.Error.Message != nil {
+		return stuckReasonError, *content.Status.Error.Message
+	}
+	if metav1.HasAnnotation(content.ObjectMeta, utils.AnnVolumeGroupSnapshotBeingCreated) {
+		return stuckReasonVolumeGroupSnapshotBeingCreated, fmt.Sprintf("VolumeGroupSnapshotContent %s is still being created by the CSI driver", content.Name)
+	}
+	if content.Status == nil || content.Status.ReadyToUse == nil || !*content.Status.ReadyToUse {
+		return stuckReasonWaitingForReadyToUse, fmt.Sprintf("waiting for VolumeGroupSnapshotContent %s to be ready to use", content.Name)
+	}
+	return stuckReasonUnknown, fmt.Sprintf("VolumeGroupSnapshotContent %s is ready to use", content.Name)
+}
+
+// secretBlockedReason returns SecretNotFound when the secret referenced by
+// the annotations of a content does not exist, and an empty reason
+// otherwise. The first Forbidden error is logged as a warning, since the
+// permission to get secrets is optional.
+func (ctrl *stuckOperationWatchdog) secretBlockedReason(annotations map[string]string, nameKey, namespaceKey string) (string, string) {
+	name, namespace := annotations[nameKey], annotations[namespaceKey]
+	if name == "" {
+		return "", ""
+	}
+	_, err := ctrl.client.CoreV1().Secrets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return stuckReasonSecretNotFound, fmt.Sprintf("secret %s/%s not found", namespace, name)
+	}
+	if errors.IsForbidden(err) && !ctrl.secretsForbidden {
+		ctrl.secretsForbidden = true
+		klog.Warningf("Cannot check whether the secrets of stuck operations exist, grant the get permission on secrets to the snapshot controller to report %s: %v", stuckReasonSecretNotFound, err)
+		return "", ""
+	}
+	if err != nil {
+		klog.V(4).Infof("failed to get secret %s/%s: %v", namespace, name, err)
+	}
+	return "", ""
+}
+
+// getSnapshotContent returns the content of a snapshot, or nil if it does
+// not exist yet.
+func (ctrl *stuckOperationWatchdog) getSnapshotContent(snapshot *crdv1.VolumeSnapshot) *crdv1.VolumeSnapshotContent {
+	var contentName string
+	switch {
+	case snapshot.Status != nil && snapshot.Status.BoundVolumeSnapshotContentName != nil:
+		contentName = *snapshot.Status.BoundVolumeSnapshotContentName
+	case snapshot.Spec.Source.VolumeSnapshotContentName != nil:
+		contentName = *snapshot.Spec.Source.VolumeSnapshotContentName
+	default:
+		contentName = utils.GetDynamicSnapshotContentNameForSnapshot(snapshot)
+	}
+	content, err := ctrl.contentLister.Get(contentName)
+	if err != nil {
+		return nil
+	}
+	return content
+}
+
+// getGroupSnapshotContent returns the content of a group snapshot, or nil if
+// it does not exist yet.
+func (ctrl *stuckOperationWatchdog) getGroupSnapshotContent(groupSnapshot *crdv1beta1.VolumeGroupSnapshot) *crdv1beta1.VolumeGroupSnapshotContent {
+	var contentName string
+	switch {
+	case groupSnapshot.Status != nil && groupSnapshot.Status.BoundVolumeGroupSnapshotContentName != nil:
+		contentName = *groupSnapshot.Status.BoundVolumeGroupSnapshotContentName
+	case groupSnapshot.Spec.Source.VolumeGroupSnapshotContentName != nil:
+		contentName = *groupSnapshot.Spec.Source.VolumeGroupSnapshotContentName
+	default:
+		contentName = utils.GetDynamicSnapshotContentNameForGroupSnapshot(groupSnapshot)
+	}
+	content, err := ctrl.groupSnapshotContentLister.Get(contentName)
+	if err != nil {
+		return nil
+	}
+	return content
+}
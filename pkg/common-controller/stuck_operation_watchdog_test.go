@@ -0,0 +1,436 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common_controller
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	crdv1beta1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumegroupsnapshot/v1beta1"
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	"github.com/kubernetes-csi/external-snapshotter/client/v8/clientset/versioned/fake"
+	informers "github.com/kubernetes-csi/external-snapshotter/client/v8/informers/externalversions"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/metrics"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	coreinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+)
+
+// fakeStuckMetricsManager returns fixed operations in flight and keeps the
+// stuck operations recorded by the watchdog.
+type fakeStuckMetricsManager struct {
+	metrics.MetricsManager
+
+	inFlight []metrics.InFlightOperation
+	recorded []metrics.InFlightOperation
+	stuck    []metrics.StuckOperation
+}
+
+func (m *fakeStuckMetricsManager) InFlightOperations() []metrics.InFlightOperation {
+	return m.inFlight
+}
+
+func (m *fakeStuckMetricsManager) RecordStuckOperations(now time.Time, inFlight []metrics.InFlightOperation, stuck []metrics.StuckOperation) {
+	m.recorded = inFlight
+	m.stuck = stuck
+}
+
+type stuckOperationTest struct {
+	name           string
+	operation      string
+	uid            string
+	age            time.Duration
+	snapshots      []*crdv1.VolumeSnapshot
+	contents       []*crdv1.VolumeSnapshotContent
+	groupSnapshots []*crdv1beta1.VolumeGroupSnapshot
+	groupContents  []*crdv1beta1.VolumeGroupSnapshotContent
+	pvcs           []*v1.PersistentVolumeClaim
+	secrets        []*v1.Secret
+
+	expectedReason string
+	expectedEvents []string
+}
+
+func newStuckTestSecret(name string) *v1.Secret {
+	return &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace}}
+}
+
+func TestCheckStuckOperations(t *testing.T) {
+	deletionTimestamp := &metav1.Time{Time: mustParseTime("2024-01-01T09:00:00Z")}
+	secretAnnotations := map[string]string{
+		utils.AnnDeletionSecretRefName:      "creds",
+		utils.AnnDeletionSecretRefNamespace: testNamespace,
+	}
+	groupSecretAnnotations := map[string]string{
+		utils.AnnDeletionGroupSecretRefName:      "creds",
+		utils.AnnDeletionGroupSecretRefNamespace: testNamespace,
+	}
+	snapshotError := &crdv1.VolumeSnapshotError{Message: &[]string{"failed to take snapshot"}[0]}
+	restoringClaim := newClaim("restore", "pvc-uid2", "1Gi", "", v1.ClaimPending, nil, false)
+	restoringClaim.Spec.DataSource = &v1.TypedLocalObjectReference{APIGroup: &[]string{snapshotAPIGroup}[0], Kind: snapshotKind, Name: "snap12-1"}
+	deletedClaim := newClaim("claim12-1", "pvc-uid1", "1Gi", "volume12-1", v1.ClaimBound, nil, false)
+	deletedClaim.DeletionTimestamp = deletionTimestamp
+
+	tests := []stuckOperationTest{
+		{
+			name:           "12-1 - operation younger than its threshold is not stuck",
+			operation:      metrics.CreateSnapshotOperationName,
+			uid:            "snapuid12-1",
+			age:            5 * time.Minute,
+			snapshots:      newSnapshotArray("snap12-1", "snapuid12-1", "claim12-1", "", classGold, "", nil, nil, nil, nil, false, true, nil),
+			expectedReason: "",
+		},
+		{
+			name:           "12-2 - creation waits for a missing source PVC",
+			operation:      metrics.CreateSnapshotOperationName,
+			uid:            "snapuid12-1",
+			age:            time.Hour,
+			snapshots:      newSnapshotArray("snap12-1", "snapuid12-1", "claim12-1", "", classGold, "", nil, nil, nil, nil, false, true, nil),
+			expectedReason: stuckReasonPVCNotFound,
+			expectedEvents: []string{"Warning OperationStuck CreateSnapshot operation in flight for 1h0m0s, blocked by PVCNotFound"},
+		},
+		{
+			name:           "12-3 - creation cannot add the finalizer of a PVC being deleted",
+			operation:      metrics.CreateSnapshotOperationName,
+			uid:            "snapuid12-1",
+			age:            time.Hour,
+			snapshots:      newSnapshotArray("snap12-1", "snapuid12-1", "claim12-1", "", classGold, "", nil, nil, nil, nil, false, true, nil),
+			pvcs:           []*v1.PersistentVolumeClaim{deletedClaim},
+			expectedReason: stuckReasonPVCBeingDeleted,
+			expectedEvents: []string{"Warning OperationStuck CreateSnapshot operation in flight for 1h0m0s, blocked by PVCBeingDeleted"},
+		},
+		{
+			name:           "12-4 - creation waits for the CSI driver",
+			operation:      metrics.CreateSnapshotAndReadyOperationName,
+			uid:            "snapuid12-1",
+			age:            time.Hour,
+			snapshots:      newSnapshotArray("snap12-1", "snapuid12-1", "claim12-1", "", classGold, "content12-1", nil, nil, nil, nil, false, true, nil),
+			contents:       withContentAnnotations(newContentArray("content12-1", "snapuid12-1", "snap12-1", "", classGold, "", "volume12-1", deletionPolicy, nil, nil, false), map[string]string{utils.AnnVolumeSnapshotBeingCreated: "yes"}),
+			expectedReason: stuckReasonVolumeSnapshotBeingCreated,
+			expectedEvents: []string{"Warning OperationStuck CreateSnapshotAndReady operation in flight for 1h0m0s, blocked by VolumeSnapshotBeingCreated"},
+		},
+		{
+			name:           "12-5 - creation with a missing secret",
+			operation:      metrics.CreateSnapshotOperationName,
+			uid:            "snapuid12-1",
+			age:            time.Hour,
+			snapshots:      newSnapshotArray("snap12-1", "snapuid12-1", "claim12-1", "", classGold, "content12-1", nil, nil, nil, nil, false, true, nil),
+			contents:       withContentAnnotations(newContentArray("content12-1", "snapuid12-1", "snap12-1", "", classGold, "", "volume12-1", deletionPolicy, nil, nil, false), secretAnnotations),
+			expectedReason: stuckReasonSecretNotFound,
+			expectedEvents: []string{"Warning OperationStuck CreateSnapshot operation in flight for 1h0m0s, blocked by SecretNotFound: secret default/creds not found"},
+		},
+		{
+			name:           "12-6 - creation with an existing secret waits for the content to be ready",
+			operation:      metrics.CreateSnapshotAndReadyOperationName,
+			uid:            "snapuid12-1",
+			age:            time.Hour,
+			snapshots:      newSnapshotArray("snap12-1", "snapuid12-1", "claim12-1", "", classGold, "content12-1", nil, nil, nil, nil, false, true, nil),
+			contents:       withContentAnnotations(newContentArrayNoStatus("content12-1", "snapuid12-1", "snap12-1", "", classGold, "", "volume12-1", deletionPolicy, nil, nil, false, false), secretAnnotations),
+			secrets:        []*v1.Secret{newStuckTestSecret("creds")},
+			expectedReason: stuckReasonWaitingForReadyToUse,
+			expectedEvents: []string{"Warning OperationStuck CreateSnapshotAndReady operation in flight for 1h0m0s, blocked by WaitingForReadyToUse"},
+		},
+		{
+			name:           "12-7 - snapshot error",
+			operation:      metrics.CreateSnapshotOperationName,
+			uid:            "snapuid12-1",
+			age:            time.Hour,
+			snapshots:      newSnapshotArray("snap12-1", "snapuid12-1", "claim12-1", "", classGold, "", nil, nil, nil, snapshotError, false, true, nil),
+			expectedReason: stuckReasonError,
+			expectedEvents: []string{"Warning OperationStuck CreateSnapshot operation in flight for 1h0m0s, blocked by Error: failed to take snapshot"},
+		},
+		{
+			name:           "12-8 - deletion of a snapshot used to restore a PVC",
+			operation:      metrics.DeleteSnapshotOperationName,
+			uid:            "snapuid12-1",
+			age:            time.Hour,
+			snapshots:      newSnapshotArray("snap12-1", "snapuid12-1", "claim12-1", "", classGold, "content12-1", &True, nil, nil, nil, false, true, deletionTimestamp),
+			contents:       newContentArray("content12-1", "snapuid12-1", "snap12-1", "sid12-1", classGold, "", "volume12-1", deletionPolicy, nil, nil, true),
+			pvcs:           []*v1.PersistentVolumeClaim{restoringClaim},
+			expectedReason: stuckReasonUsedAsSource,
+			expectedEvents: []string{"Warning OperationStuck DeleteSnapshot operation in flight for 1h0m0s, blocked by SnapshotUsedAsSource"},
+		},
+		{
+			name:           "12-9 - deletion waits for the content to be deleted",
+			operation:      metrics.DeleteSnapshotOperationName,
+			uid:            "snapuid12-1",
+			age:            2 * time.Hour,
+			snapshots:      newSnapshotArray("snap12-1", "snapuid12-1", "claim12-1", "", classGold, "content12-1", &True, nil, nil, nil, false, true, deletionTimestamp),
+			contents:       withContentDeletionTimestamp(newContentArray("content12-1", "snapuid12-1", "snap12-1", "sid12-1", classGold, "", "volume12-1", deletionPolicy, nil, nil, true), deletionTimestamp),
+			expectedReason: stuckReasonWaitingForContentDeletion,
+			expectedEvents: []string{"Warning OperationStuck DeleteSnapshot operation in flight for 2h0m0s, blocked by WaitingForContentDeletion"},
+		},
+		{
+			name:           "12-10 - operations of unknown objects are ignored",
+			operation:      metrics.CreateSnapshotOperationName,
+			uid:            "snapuid12-unknown",
+			age:            time.Hour,
+			snapshots:      newSnapshotArray("snap12-1", "snapuid12-1", "claim12-1", "", classGold, "", nil, nil, nil, nil, false, true, nil),
+			expectedReason: "",
+		},
+		{
+			name:           "12-11 - group snapshot creation waits for the CSI driver",
+			operation:      metrics.CreateGroupSnapshotOperationName,
+			uid:            "groupuid12-1",
+			age:            time.Hour,
+			groupSnapshots: []*crdv1beta1.VolumeGroupSnapshot{newGroupSnapshot("group12-1", "groupuid12-1", map[string]string{"app": "db"}, "", classGold, "groupcontent12-1", nil, nil, nil, false, true, nil)},
+			groupContents:  withGroupContentAnnotations([]*crdv1beta1.VolumeGroupSnapshotContent{newGroupSnapshotContent("groupcontent12-1", "groupuid12-1", "group12-1", "", classGold, []string{"volume12-1"}, "", deletionPolicy, nil, false, false)}, map[string]string{utils.AnnVolumeGroupSnapshotBeingCreated: "yes"}),
+			expectedReason: stuckReasonVolumeGroupSnapshotBeingCreated,
+			expectedEvents: []string{"Warning OperationStuck CreateGroupSnapshot operation in flight for 1h0m0s, blocked by VolumeGroupSnapshotBeingCreated"},
+		},
+		{
+			name:           "12-12 - group snapshot deletion with a missing secret",
+			operation:      metrics.DeleteGroupSnapshotOperationName,
+			uid:            "groupuid12-1",
+			age:            time.Hour,
+			groupSnapshots: []*crdv1beta1.VolumeGroupSnapshot{newGroupSnapshot("group12-1", "groupuid12-1", map[string]string{"app": "db"}, "", classGold, "groupcontent12-1", &True, nil, nil, false, true, deletionTimestamp)},
+			groupContents:  withGroupContentAnnotations([]*crdv1beta1.VolumeGroupSnapshotContent{newGroupSnapshotContent("groupcontent12-1", "groupuid12-1", "group12-1", "gid12-1", classGold, []string{"volume12-1"}, "", deletionPolicy, nil, false, true)}, groupSecretAnnotations),
+			expectedReason: stuckReasonSecretNotFound,
+			expectedEvents: []string{"Warning OperationStuck DeleteGroupSnapshot operation in flight for 1h0m0s, blocked by SecretNotFound"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			runStuckOperationTest(t, test)
+		})
+	}
+}
+
+func withContentDeletionTimestamp(contents []*crdv1.VolumeSnapshotContent, deletionTimestamp *metav1.Time) []*crdv1.VolumeSnapshotContent {
+	for i := range contents {
+		contents[i].ObjectMeta.DeletionTimestamp = deletionTimestamp
+	}
+	return contents
+}
+
+func runStuckOperationTest(t *testing.T, test stuckOperationTest) {
+	now := mustParseTime("2024-01-01T10:00:00Z")
+	client := fake.NewSimpleClientset()
+	kubeObjs := []runtime.Object{}
+	for _, secret := range test.secrets {
+		kubeObjs = append(kubeObjs, secret)
+	}
+	kubeClient := kubefake.NewSimpleClientset(kubeObjs...)
+
+	factory := informers.NewSharedInformerFactory(client, 0)
+	coreFactory := coreinformers.NewSharedInformerFactory(kubeClient, 0)
+	op := metrics.InFlightOperation{
+		Key:          metrics.NewOperationKey(test.operation, types.UID(test.uid)),
+		Driver:       mockDriverName,
+		SnapshotType: string(metrics.DynamicSnapshotType),
+		StartTime:    now.Add(-test.age),
+	}
+	metricsManager := &fakeStuckMetricsManager{inFlight: []metrics.InFlightOperation{op}}
+	thresholds, err := ParseStuckOperationThresholdsFlag(30*time.Minute, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fakeRecorder := record.NewFakeRecorder(100)
+	ctrl := NewStuckOperationWatchdog(
+		kubeClient,
+		fakeRecorder,
+		factory.Snapshot().V1().VolumeSnapshots(),
+		factory.Snapshot().V1().VolumeSnapshotContents(),
+		factory.Groupsnapshot().V1beta1().VolumeGroupSnapshots(),
+		factory.Groupsnapshot().V1beta1().VolumeGroupSnapshotContents(),
+		coreFactory.Core().V1().PersistentVolumeClaims(),
+		metricsManager,
+		time.Minute,
+		thresholds,
+	)
+	ctrl.now = func() time.Time { return now }
+
+	for _, snapshot := range test.snapshots {
+		factory.Snapshot().V1().VolumeSnapshots().Informer().GetIndexer().Add(snapshot)
+	}
+	for _, content := range test.contents {
+		factory.Snapshot().V1().VolumeSnapshotContents().Informer().GetIndexer().Add(content)
+	}
+	for _, groupSnapshot := range test.groupSnapshots {
+		factory.Groupsnapshot().V1beta1().VolumeGroupSnapshots().Informer().GetIndexer().Add(groupSnapshot)
+	}
+	for _, groupContent := range test.groupContents {
+		factory.Groupsnapshot().V1beta1().VolumeGroupSnapshotContents().Informer().GetIndexer().Add(groupContent)
+	}
+	for _, pvc := range test.pvcs {
+		coreFactory.Core().V1().PersistentVolumeClaims().Informer().GetIndexer().Add(pvc)
+	}
+
+	ctrl.checkOperations()
+
+	if !reflect.DeepEqual(metricsManager.recorded, []metrics.InFlightOperation{op}) {
+		t.Errorf("expected the age of %+v to be recorded, got %+v", op, metricsManager.recorded)
+	}
+	reasons := []string{}
+	for _, stuck := range metricsManager.stuck {
+		reasons = append(reasons, stuck.Reason)
+	}
+	expectedReasons := []string{}
+	if test.expectedReason != "" {
+		expectedReasons = append(expectedReasons, test.expectedReason)
+	}
+	if !reflect.DeepEqual(reasons, expectedReasons) {
+		t.Errorf("expected stuck operations with reasons %v, got %v", expectedReasons, reasons)
+	}
+
+	events := []string{}
+	close(fakeRecorder.Events)
+	for event := range fakeRecorder.Events {
+		events = append(events, event)
+	}
+	sort.Strings(events)
+	if len(events) != len(test.expectedEvents) {
+		t.Fatalf("expected events %v, got %v", test.expectedEvents, events)
+	}
+	for i, expected := range test.expectedEvents {
+		if !strings.HasPrefix(events[i], expected) {
+			t.Errorf("expected event %q, got %q", expected, events[i])
+		}
+	}
+}
+
+func TestStuckOperationEventsAtThresholdCrossings(t *testing.T) {
+	start := mustParseTime("2024-01-01T10:00:00Z")
+	factory := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+	kubeClient := kubefake.NewSimpleClientset()
+	coreFactory := coreinformers.NewSharedInformerFactory(kubeClient, 0)
+	op := metrics.InFlightOperation{
+		Key:       metrics.NewOperationKey(metrics.CreateSnapshotOperationName, "snapuid12-1"),
+		Driver:    mockDriverName,
+		StartTime: start,
+	}
+	metricsManager := &fakeStuckMetricsManager{inFlight: []metrics.InFlightOperation{op}}
+	thresholds, err := ParseStuckOperationThresholdsFlag(30*time.Minute, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fakeRecorder := record.NewFakeRecorder(100)
+	ctrl := NewStuckOperationWatchdog(
+		kubeClient,
+		fakeRecorder,
+		factory.Snapshot().V1().VolumeSnapshots(),
+		factory.Snapshot().V1().VolumeSnapshotContents(),
+		nil,
+		nil,
+		coreFactory.Core().V1().PersistentVolumeClaims(),
+		metricsManager,
+		time.Minute,
+		thresholds,
+	)
+	for _, snapshot := range newSnapshotArray("snap12-1", "snapuid12-1", "claim12-1", "", classGold, "", nil, nil, nil, nil, false, true, nil) {
+		factory.Snapshot().V1().VolumeSnapshots().Informer().GetIndexer().Add(snapshot)
+	}
+
+	// The operation is reported when it crosses 30m, 1h and 4h, but it
+	// stays stuck in the metric at every check.
+	for _, check := range []struct {
+		age         time.Duration
+		expectEvent bool
+	}{
+		{20 * time.Minute, false},
+		{35 * time.Minute, true},
+		{40 * time.Minute, false},
+		{61 * time.Minute, true},
+		{90 * time.Minute, false},
+		{5 * time.Hour, true},
+		{6 * time.Hour, false},
+	} {
+		ctrl.now = func() time.Time { return start.Add(check.age) }
+		ctrl.checkOperations()
+		if stuck := len(metricsManager.stuck) == 1; stuck != (check.age >= 30*time.Minute) {
+			t.Errorf("at %v: unexpected stuck operations %+v", check.age, metricsManager.stuck)
+		}
+		if event := len(fakeRecorder.Events) == 1; event != check.expectEvent {
+			t.Errorf("at %v: expected an event %v, got %d events", check.age, check.expectEvent, len(fakeRecorder.Events))
+		}
+		for len(fakeRecorder.Events) > 0 {
+			<-fakeRecorder.Events
+		}
+	}
+
+	// A new operation of the same snapshot is reported at its threshold.
+	metricsManager.inFlight = nil
+	ctrl.checkOperations()
+	op.StartTime = start.Add(6 * time.Hour)
+	metricsManager.inFlight = []metrics.InFlightOperation{op}
+	ctrl.now = func() time.Time { return op.StartTime.Add(31 * time.Minute) }
+	ctrl.checkOperations()
+	if len(fakeRecorder.Events) != 1 {
+		t.Errorf("expected an event for the new operation, got %d events", len(fakeRecorder.Events))
+	}
+}
+
+func TestStuckOperationSecretsForbidden(t *testing.T) {
+	kubeClient := kubefake.NewSimpleClientset()
+	kubeClient.PrependReactor("get", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(v1.Resource("secrets"), "creds", errors.New("no RBAC rule"))
+	})
+	ctrl := &stuckOperationWatchdog{client: kubeClient}
+	annotations := map[string]string{
+		utils.AnnDeletionSecretRefName:      "creds",
+		utils.AnnDeletionSecretRefNamespace: testNamespace,
+	}
+	for i := 0; i < 2; i++ {
+		if reason, message := ctrl.secretBlockedReason(annotations, utils.AnnDeletionSecretRefName, utils.AnnDeletionSecretRefNamespace); reason != "" {
+			t.Errorf("expected no reason when secrets are forbidden, got %s: %s", reason, message)
+		}
+	}
+	if !ctrl.secretsForbidden {
+		t.Errorf("expected the forbidden secrets to be logged")
+	}
+}
+
+func TestParseStuckOperationThresholdsFlag(t *testing.T) {
+	thresholds, err := ParseStuckOperationThresholdsFlag(time.Hour, " CreateSnapshot=10m, DeleteGroupSnapshot=0,")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := StuckOperationThresholds{
+		metrics.CreateSnapshotOperationName:              10 * time.Minute,
+		metrics.CreateSnapshotAndReadyOperationName:      time.Hour,
+		metrics.DeleteSnapshotOperationName:              time.Hour,
+		metrics.CreateGroupSnapshotOperationName:         time.Hour,
+		metrics.CreateGroupSnapshotAndReadyOperationName: time.Hour,
+		metrics.DeleteGroupSnapshotOperationName:         0,
+	}
+	if !reflect.DeepEqual(thresholds, expected) {
+		t.Errorf("expected %+v, got %+v", expected, thresholds)
+	}
+
+	for _, flag := range []string{
+		"CreateSnapshot",
+		"CreateSnapshot=-1m",
+		"CreateSnapshot=soon",
+		"RestoreSnapshot=1h",
+	} {
+		if _, err := ParseStuckOperationThresholdsFlag(time.Hour, flag); err == nil {
+			t.Errorf("expected an error for %q", flag)
+		}
+	}
+}
@@ -0,0 +1,349 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common_controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	crdv1alpha1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1alpha1"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	klog "k8s.io/klog/v2"
+)
+
+// Design:
+//
+// A transfer needs the consent of both namespaces: a
+// VolumeSnapshotTransferRequest in the source namespace offers a
+// VolumeSnapshot to a target namespace, and a VolumeSnapshotTransferAccept in
+// the target namespace accepts the request and names the VolumeSnapshot to
+// create there. Once both exist and the source snapshot is ready, the
+// transfer is carried out in steps that can be resumed after any failure:
+//
+// 1. The name of the VolumeSnapshotContent is recorded in the request
+//    status, so that the transfer can be resumed after the source snapshot
+//    is gone.
+// 2. The content is rebound to the target namespace with a single patch of
+//    spec.volumeSnapshotRef. The patch tests the UID of the source snapshot,
+//    so it fails if the content was rebound concurrently. This is the point
+//    at which the source snapshot is released: from then on the common
+//    controller treats the content as pre-bound to the target snapshot and
+//    never deletes it on behalf of the source snapshot.
+// 3. The target VolumeSnapshot is created with the content as its
+//    volumeSnapshotContentName source. The common controller binds them in
+//    checkandBindSnapshotContent, which also sets the UID of the reference.
+// 4. The source VolumeSnapshot is deleted. Its content no longer points back
+//    to it, so only its finalizers are removed.
+// 5. The request and the accept are marked as completed.
+
+// syncRequest carries out the transfer of a VolumeSnapshotTransferRequest
+// once it has been accepted.
+func (ctrl *snapshotTransferController) syncRequest(request *crdv1alpha1.VolumeSnapshotTransferRequest) error {
+	klog.V(5).Infof("synchronizing VolumeSnapshotTransferRequest[%s/%s]", request.Namespace, request.Name)
+
+	if request.Status != nil && (request.Status.Phase == crdv1alpha1.VolumeSnapshotTransferCompleted || request.Status.Phase == crdv1alpha1.VolumeSnapshotTransferFailed) {
+		return nil
+	}
+	if request.Spec.TargetNamespace == request.Namespace {
+		return ctrl.failTransfer(request, nil, "the target namespace must differ from the namespace of the request")
+	}
+
+	accept, err := ctrl.getAccept(request)
+	if err != nil {
+		return err
+	}
+	if accept == nil {
+		return ctrl.updateTransferStatus(request, nil, crdv1alpha1.VolumeSnapshotTransferPending, "",
+			fmt.Sprintf("Waiting for a VolumeSnapshotTransferAccept in namespace %s", request.Spec.TargetNamespace))
+	}
+
+	source, err := ctrl.snapshotLister.VolumeSnapshots(request.Namespace).Get(request.Spec.VolumeSnapshotName)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return err
+	}
+	if apierrs.IsNotFound(err) {
+		source = nil
+	}
+
+	var contentName string
+	if request.Status != nil && request.Status.VolumeSnapshotContentName != nil {
+		contentName = *request.Status.VolumeSnapshotContentName
+	} else {
+		// The transfer has not started yet.
+		if source == nil {
+			return ctrl.updateTransferStatus(request, accept, crdv1alpha1.VolumeSnapshotTransferPending, "",
+				fmt.Sprintf("VolumeSnapshot %s not found", request.Spec.VolumeSnapshotName))
+		}
+		if failed, msg := ctrl.checkTransferSource(source, accept); msg != "" {
+			if failed {
+				return ctrl.failTransfer(request, accept, msg)
+			}
+			return ctrl.updateTransferStatus(request, accept, crdv1alpha1.VolumeSnapshotTransferPending, "", msg)
+		}
+		contentName = *source.Status.BoundVolumeSnapshotContentName
+		if err := ctrl.updateTransferStatus(request, accept, crdv1alpha1.VolumeSnapshotTransferPending, contentName,
+			fmt.Sprintf("Transferring VolumeSnapshotContent %s", contentName)); err != nil {
+			return err
+		}
+	}
+
+	content, err := ctrl.contentLister.Get(contentName)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			return ctrl.failTransfer(request, accept, fmt.Sprintf("VolumeSnapshotContent %s not found", contentName))
+		}
+		return err
+	}
+
+	ref := content.Spec.VolumeSnapshotRef
+	if ref.Namespace != accept.Namespace || ref.Name != accept.Spec.VolumeSnapshotName {
+		if source == nil || ref.Namespace != source.Namespace || ref.Name != source.Name || ref.UID != source.UID {
+			return ctrl.failTransfer(request, accept, fmt.Sprintf("VolumeSnapshotContent %s is no longer bound to VolumeSnapshot %s", contentName, request.Spec.VolumeSnapshotName))
+		}
+		if err := ctrl.rebindContent(content, source, accept); err != nil {
+			return err
+		}
+	}
+
+	if msg, err := ctrl.ensureTargetSnapshot(request, accept, content); err != nil || msg != "" {
+		if msg != "" {
+			return ctrl.failTransfer(request, accept, msg)
+		}
+		return err
+	}
+
+	if source != nil && source.DeletionTimestamp == nil && utils.IsBoundVolumeSnapshotContentNameSet(source) && *source.Status.BoundVolumeSnapshotContentName == contentName {
+		klog.V(5).Infof("syncRequest: deleting transferred VolumeSnapshot %s", utils.SnapshotKey(source))
+		uid := source.UID
+		err := ctrl.clientset.SnapshotV1().VolumeSnapshots(source.Namespace).Delete(context.TODO(), source.Name, metav1.DeleteOptions{
+			Preconditions: &metav1.Preconditions{UID: &uid},
+		})
+		if err != nil && !apierrs.IsNotFound(err) {
+			return fmt.Errorf("failed to delete transferred VolumeSnapshot %s: %v", utils.SnapshotKey(source), err)
+		}
+	}
+
+	if err := ctrl.updateTransferStatus(request, accept, crdv1alpha1.VolumeSnapshotTransferCompleted, contentName, ""); err != nil {
+		return err
+	}
+	msg := fmt.Sprintf("Transferred VolumeSnapshotContent %s from VolumeSnapshot %s/%s to VolumeSnapshot %s/%s",
+		contentName, request.Namespace, request.Spec.VolumeSnapshotName, accept.Namespace, accept.Spec.VolumeSnapshotName)
+	ctrl.eventRecorder.Event(request, v1.EventTypeNormal, "VolumeSnapshotTransferred", msg)
+	ctrl.eventRecorder.Event(accept, v1.EventTypeNormal, "VolumeSnapshotTransferred", msg)
+	return nil
+}
+
+// getAccept returns the oldest VolumeSnapshotTransferAccept in the target
+// namespace that accepts the request, or nil if there is none.
+func (ctrl *snapshotTransferController) getAccept(request *crdv1alpha1.VolumeSnapshotTransferRequest) (*crdv1alpha1.VolumeSnapshotTransferAccept, error) {
+	accepts, err := ctrl.acceptLister.VolumeSnapshotTransferAccepts(request.Spec.TargetNamespace).List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list VolumeSnapshotTransferAccepts in namespace %s: %v", request.Spec.TargetNamespace, err)
+	}
+	var matching []*crdv1alpha1.VolumeSnapshotTransferAccept
+	for _, accept := range accepts {
+		if accept.Spec.SourceNamespace == request.Namespace && accept.Spec.TransferRequestName == request.Name && accept.DeletionTimestamp == nil {
+			matching = append(matching, accept)
+		}
+	}
+	if len(matching) == 0 {
+		return nil, nil
+	}
+	sort.Slice(matching, func(i, j int) bool {
+		ti, tj := matching[i].CreationTimestamp, matching[j].CreationTimestamp
+		if !ti.Equal(&tj) {
+			return ti.Before(&tj)
+		}
+		return matching[i].Name < matching[j].Name
+	})
+	return matching[0].DeepCopy(), nil
+}
+
+// checkTransferSource returns a message if the source snapshot cannot be
+// transferred yet, and whether the transfer can never take place.
+func (ctrl *snapshotTransferController) checkTransferSource(source *crdv1.VolumeSnapshot, accept *crdv1alpha1.VolumeSnapshotTransferAccept) (bool, string) {
+	if source.DeletionTimestamp != nil {
+		return true, fmt.Sprintf("VolumeSnapshot %s is being deleted", source.Name)
+	}
+	if utils.IsVolumeGroupSnapshotMember(source) ||
+		(source.Status != nil && source.Status.VolumeGroupSnapshotName != nil && *source.Status.VolumeGroupSnapshotName != "") {
+		return true, fmt.Sprintf("VolumeSnapshot %s is a member of a VolumeGroupSnapshot", source.Name)
+	}
+	if !utils.IsSnapshotReady(source) || !utils.IsBoundVolumeSnapshotContentNameSet(source) {
+		return false, fmt.Sprintf("Waiting for VolumeSnapshot %s to be ready to use", source.Name)
+	}
+	if volumeBeingCreatedFromSnapshot(ctrl.pvcLister, source) {
+		return false, fmt.Sprintf("VolumeSnapshot %s is being used to restore a PersistentVolumeClaim", source.Name)
+	}
+	if _, err := ctrl.snapshotLister.VolumeSnapshots(accept.Namespace).Get(accept.Spec.VolumeSnapshotName); err == nil {
+		return false, fmt.Sprintf("VolumeSnapshot %s/%s already exists", accept.Namespace, accept.Spec.VolumeSnapshotName)
+	}
+	return false, ""
+}
+
+// rebindContent points the content to the target snapshot. The patch only
+// applies if the content is still bound to the source snapshot.
+func (ctrl *snapshotTransferController) rebindContent(content *crdv1.VolumeSnapshotContent, source *crdv1.VolumeSnapshot, accept *crdv1alpha1.VolumeSnapshotTransferAccept) error {
+	klog.V(5).Infof("rebindContent: binding VolumeSnapshotContent %s to VolumeSnapshot %s/%s", content.Name, accept.Namespace, accept.Spec.VolumeSnapshotName)
+	patches := []utils.PatchOp{
+		{
+			Op:    "test",
+			Path:  "/spec/volumeSnapshotRef/uid",
+			Value: string(source.UID),
+		},
+		{
+			Op:   "replace",
+			Path: "/spec/volumeSnapshotRef",
+			Value: v1.ObjectReference{
+				Kind:       content.Spec.VolumeSnapshotRef.Kind,
+				APIVersion: content.Spec.VolumeSnapshotRef.APIVersion,
+				Namespace:  accept.Namespace,
+				Name:       accept.Spec.VolumeSnapshotName,
+			},
+		},
+	}
+	if content.Annotations == nil {
+		patches = append(patches, utils.PatchOp{
+			Op:    "add",
+			Path:  "/metadata/annotations",
+			Value: map[string]string{utils.AnnVolumeSnapshotTransferredFrom: utils.SnapshotKey(source)},
+		})
+	} else {
+		patches = append(patches, utils.PatchOp{
+			Op:    "add",
+			Path:  "/metadata/annotations/" + strings.ReplaceAll(utils.AnnVolumeSnapshotTransferredFrom, "/", "~1"),
+			Value: utils.SnapshotKey(source),
+		})
+	}
+	if _, err := utils.PatchVolumeSnapshotContent(content, patches, ctrl.clientset); err != nil {
+		return fmt.Errorf("failed to bind VolumeSnapshotContent %s to VolumeSnapshot %s/%s: %v", content.Name, accept.Namespace, accept.Spec.VolumeSnapshotName, err)
+	}
+	return nil
+}
+
+// ensureTargetSnapshot creates the VolumeSnapshot in the target namespace
+// that is bound to the transferred content. It returns a message if a
+// different VolumeSnapshot of the same name exists.
+func (ctrl *snapshotTransferController) ensureTargetSnapshot(request *crdv1alpha1.VolumeSnapshotTransferRequest, accept *crdv1alpha1.VolumeSnapshotTransferAccept, content *crdv1.VolumeSnapshotContent) (string, error) {
+	target, err := ctrl.clientset.SnapshotV1().VolumeSnapshots(accept.Namespace).Get(context.TODO(), accept.Spec.VolumeSnapshotName, metav1.GetOptions{})
+	if err == nil {
+		if target.Spec.Source.VolumeSnapshotContentName == nil || *target.Spec.Source.VolumeSnapshotContentName != content.Name {
+			return fmt.Sprintf("VolumeSnapshot %s/%s already exists and does not use VolumeSnapshotContent %s", accept.Namespace, accept.Spec.VolumeSnapshotName, content.Name), nil
+		}
+		return "", nil
+	}
+	if !apierrs.IsNotFound(err) {
+		return "", err
+	}
+
+	contentName := content.Name
+	target = &crdv1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      accept.Spec.VolumeSnapshotName,
+			Namespace: accept.Namespace,
+			Annotations: map[string]string{
+				utils.AnnVolumeSnapshotTransferredFrom: request.Namespace + "/" + request.Spec.VolumeSnapshotName,
+			},
+		},
+		Spec: crdv1.VolumeSnapshotSpec{
+			Source: crdv1.VolumeSnapshotSource{
+				VolumeSnapshotContentName: &contentName,
+			},
+			VolumeSnapshotClassName: content.Spec.VolumeSnapshotClassName,
+		},
+	}
+	klog.V(5).Infof("ensureTargetSnapshot: creating VolumeSnapshot %s", utils.SnapshotKey(target))
+	if _, err := ctrl.clientset.SnapshotV1().VolumeSnapshots(target.Namespace).Create(context.TODO(), target, metav1.CreateOptions{}); err != nil && !apierrs.IsAlreadyExists(err) {
+		return "", fmt.Errorf("failed to create VolumeSnapshot %s: %v", utils.SnapshotKey(target), err)
+	}
+	return "", nil
+}
+
+// failTransfer marks the request and the accept as failed and emits a
+// warning event on both of them.
+func (ctrl *snapshotTransferController) failTransfer(request *crdv1alpha1.VolumeSnapshotTransferRequest, accept *crdv1alpha1.VolumeSnapshotTransferAccept, message string) error {
+	klog.V(4).Infof("VolumeSnapshotTransferRequest[%s/%s]: %s", request.Namespace, request.Name, message)
+	ctrl.eventRecorder.Event(request, v1.EventTypeWarning, "VolumeSnapshotTransferFailed", message)
+	if accept != nil {
+		ctrl.eventRecorder.Event(accept, v1.EventTypeWarning, "VolumeSnapshotTransferFailed", message)
+	}
+	contentName := ""
+	if request.Status != nil && request.Status.VolumeSnapshotContentName != nil {
+		contentName = *request.Status.VolumeSnapshotContentName
+	}
+	return ctrl.updateTransferStatus(request, accept, crdv1alpha1.VolumeSnapshotTransferFailed, contentName, message)
+}
+
+// updateTransferStatus saves the status of the transfer in the request and,
+// if not nil, in the accept. The request is updated in place.
+func (ctrl *snapshotTransferController) updateTransferStatus(request *crdv1alpha1.VolumeSnapshotTransferRequest, accept *crdv1alpha1.VolumeSnapshotTransferAccept, phase crdv1alpha1.VolumeSnapshotTransferPhase, contentName, message string) error {
+	newRequestStatus := ctrl.newTransferStatus(request.Status, phase, contentName, message)
+	if !equality.Semantic.DeepEqual(request.Status, newRequestStatus) {
+		requestClone := request.DeepCopy()
+		requestClone.Status = newRequestStatus
+		newRequest, err := ctrl.clientset.SnapshotV1alpha1().VolumeSnapshotTransferRequests(request.Namespace).UpdateStatus(context.TODO(), requestClone, metav1.UpdateOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to update status of VolumeSnapshotTransferRequest %s/%s: %v", request.Namespace, request.Name, err)
+		}
+		*request = *newRequest
+	}
+
+	if accept == nil {
+		return nil
+	}
+	newAcceptStatus := ctrl.newTransferStatus(accept.Status, phase, contentName, message)
+	if equality.Semantic.DeepEqual(accept.Status, newAcceptStatus) {
+		return nil
+	}
+	acceptClone := accept.DeepCopy()
+	acceptClone.Status = newAcceptStatus
+	newAccept, err := ctrl.clientset.SnapshotV1alpha1().VolumeSnapshotTransferAccepts(accept.Namespace).UpdateStatus(context.TODO(), acceptClone, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to update status of VolumeSnapshotTransferAccept %s/%s: %v", accept.Namespace, accept.Name, err)
+	}
+	*accept = *newAccept
+	return nil
+}
+
+// newTransferStatus returns the transfer status for the given phase. The
+// completion time of a status that is already completed is kept.
+func (ctrl *snapshotTransferController) newTransferStatus(status *crdv1alpha1.VolumeSnapshotTransferStatus, phase crdv1alpha1.VolumeSnapshotTransferPhase, contentName, message string) *crdv1alpha1.VolumeSnapshotTransferStatus {
+	newStatus := &crdv1alpha1.VolumeSnapshotTransferStatus{Phase: phase}
+	if contentName != "" {
+		newStatus.VolumeSnapshotContentName = &contentName
+	}
+	if message != "" {
+		newStatus.Message = &message
+	}
+	if phase == crdv1alpha1.VolumeSnapshotTransferCompleted {
+		if status != nil && status.CompletionTime != nil {
+			newStatus.CompletionTime = status.CompletionTime
+		} else {
+			newStatus.CompletionTime = &metav1.Time{Time: ctrl.now()}
+		}
+	}
+	return newStatus
+}
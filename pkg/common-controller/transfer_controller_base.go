@@ -0,0 +1,243 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common_controller
+
+import (
+	"time"
+
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	crdv1alpha1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1alpha1"
+	clientset "github.com/kubernetes-csi/external-snapshotter/client/v8/clientset/versioned"
+	snapshotinformers "github.com/kubernetes-csi/external-snapshotter/client/v8/informers/externalversions/volumesnapshot/v1"
+	transferinformers "github.com/kubernetes-csi/external-snapshotter/client/v8/informers/externalversions/volumesnapshot/v1alpha1"
+	snapshotlisters "github.com/kubernetes-csi/external-snapshotter/client/v8/listers/volumesnapshot/v1"
+	transferlisters "github.com/kubernetes-csi/external-snapshotter/client/v8/listers/volumesnapshot/v1alpha1"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	klog "k8s.io/klog/v2"
+)
+
+// snapshotTransferController moves VolumeSnapshotContents between namespaces
+// once a VolumeSnapshotTransferRequest has been accepted by a
+// VolumeSnapshotTransferAccept. The new VolumeSnapshot in the target
+// namespace is bound to the content by csiSnapshotCommonController like any
+// other VolumeSnapshot with a VolumeSnapshotContentName source.
+type snapshotTransferController struct {
+	clientset     clientset.Interface
+	client        kubernetes.Interface
+	eventRecorder record.EventRecorder
+	transferQueue workqueue.TypedRateLimitingInterface[string]
+
+	requestLister        transferlisters.VolumeSnapshotTransferRequestLister
+	requestListerSynced  cache.InformerSynced
+	acceptLister         transferlisters.VolumeSnapshotTransferAcceptLister
+	acceptListerSynced   cache.InformerSynced
+	snapshotLister       snapshotlisters.VolumeSnapshotLister
+	snapshotListerSynced cache.InformerSynced
+	contentLister        snapshotlisters.VolumeSnapshotContentLister
+	contentListerSynced  cache.InformerSynced
+	pvcLister            corelisters.PersistentVolumeClaimLister
+	pvcListerSynced      cache.InformerSynced
+
+	resyncPeriod time.Duration
+
+	// now returns the current time. It is replaced in unit tests.
+	now func() time.Time
+}
+
+// NewSnapshotTransferController returns a new *snapshotTransferController
+func NewSnapshotTransferController(
+	clientset clientset.Interface,
+	client kubernetes.Interface,
+	volumeSnapshotTransferRequestInformer transferinformers.VolumeSnapshotTransferRequestInformer,
+	volumeSnapshotTransferAcceptInformer transferinformers.VolumeSnapshotTransferAcceptInformer,
+	volumeSnapshotInformer snapshotinformers.VolumeSnapshotInformer,
+	volumeSnapshotContentInformer snapshotinformers.VolumeSnapshotContentInformer,
+	pvcInformer coreinformers.PersistentVolumeClaimInformer,
+	resyncPeriod time.Duration,
+	transferRateLimiter workqueue.TypedRateLimiter[string],
+) *snapshotTransferController {
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartLogging(klog.Infof)
+	broadcaster.StartRecordingToSink(&corev1.EventSinkImpl{Interface: client.CoreV1().Events(v1.NamespaceAll)})
+	eventRecorder := broadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: "snapshot-controller"})
+
+	ctrl := &snapshotTransferController{
+		clientset:     clientset,
+		client:        client,
+		eventRecorder: eventRecorder,
+		resyncPeriod:  resyncPeriod,
+		transferQueue: workqueue.NewTypedRateLimitingQueueWithConfig(transferRateLimiter,
+			workqueue.TypedRateLimitingQueueConfig[string]{
+				Name: "snapshot-controller-transfer"}),
+		now: time.Now,
+	}
+
+	volumeSnapshotTransferRequestInformer.Informer().AddEventHandlerWithResyncPeriod(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { ctrl.enqueueRequestWork(obj) },
+			UpdateFunc: func(oldObj, newObj interface{}) { ctrl.enqueueRequestWork(newObj) },
+		},
+		ctrl.resyncPeriod,
+	)
+	ctrl.requestLister = volumeSnapshotTransferRequestInformer.Lister()
+	ctrl.requestListerSynced = volumeSnapshotTransferRequestInformer.Informer().HasSynced
+
+	volumeSnapshotTransferAcceptInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { ctrl.enqueueRequestForAccept(obj) },
+			UpdateFunc: func(oldObj, newObj interface{}) { ctrl.enqueueRequestForAccept(newObj) },
+		},
+	)
+	ctrl.acceptLister = volumeSnapshotTransferAcceptInformer.Lister()
+	ctrl.acceptListerSynced = volumeSnapshotTransferAcceptInformer.Informer().HasSynced
+
+	volumeSnapshotInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			UpdateFunc: func(oldObj, newObj interface{}) { ctrl.enqueueRequestsForSnapshot(newObj) },
+		},
+	)
+	ctrl.snapshotLister = volumeSnapshotInformer.Lister()
+	ctrl.snapshotListerSynced = volumeSnapshotInformer.Informer().HasSynced
+
+	ctrl.contentLister = volumeSnapshotContentInformer.Lister()
+	ctrl.contentListerSynced = volumeSnapshotContentInformer.Informer().HasSynced
+
+	ctrl.pvcLister = pvcInformer.Lister()
+	ctrl.pvcListerSynced = pvcInformer.Informer().HasSynced
+
+	return ctrl
+}
+
+func (ctrl *snapshotTransferController) Run(workers int, stopCh <-chan struct{}) {
+	defer ctrl.transferQueue.ShutDown()
+
+	klog.Infof("Starting snapshot transfer controller")
+	defer klog.Infof("Shutting snapshot transfer controller")
+
+	if !cache.WaitForCacheSync(stopCh, ctrl.requestListerSynced, ctrl.acceptListerSynced, ctrl.snapshotListerSynced, ctrl.contentListerSynced, ctrl.pvcListerSynced) {
+		klog.Errorf("Cannot sync caches")
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.Until(ctrl.transferWorker, 0, stopCh)
+	}
+
+	<-stopCh
+}
+
+// enqueueRequestWork adds a transfer request to the work queue.
+func (ctrl *snapshotTransferController) enqueueRequestWork(obj interface{}) {
+	if request, ok := obj.(*crdv1alpha1.VolumeSnapshotTransferRequest); ok {
+		objName, err := cache.MetaNamespaceKeyFunc(request)
+		if err != nil {
+			klog.Errorf("failed to get key from object: %v, %v", err, request)
+			return
+		}
+		klog.V(5).Infof("enqueued %q for sync", objName)
+		ctrl.transferQueue.Add(objName)
+	}
+}
+
+// enqueueRequestForAccept adds the transfer request accepted by the given
+// accept to the work queue.
+func (ctrl *snapshotTransferController) enqueueRequestForAccept(obj interface{}) {
+	accept, ok := obj.(*crdv1alpha1.VolumeSnapshotTransferAccept)
+	if !ok {
+		return
+	}
+	key := accept.Spec.SourceNamespace + "/" + accept.Spec.TransferRequestName
+	klog.V(5).Infof("enqueued %q for sync on change of accept %s/%s", key, accept.Namespace, accept.Name)
+	ctrl.transferQueue.Add(key)
+}
+
+// enqueueRequestsForSnapshot adds all transfer requests of the given
+// snapshot to the work queue, so that pending transfers start once the
+// snapshot is ready.
+func (ctrl *snapshotTransferController) enqueueRequestsForSnapshot(obj interface{}) {
+	snapshot, ok := obj.(*crdv1.VolumeSnapshot)
+	if !ok {
+		return
+	}
+	requests, err := ctrl.requestLister.VolumeSnapshotTransferRequests(snapshot.Namespace).List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list transfer requests in namespace %s: %v", snapshot.Namespace, err)
+		return
+	}
+	for _, request := range requests {
+		if request.Spec.VolumeSnapshotName != snapshot.Name {
+			continue
+		}
+		key := request.Namespace + "/" + request.Name
+		klog.V(5).Infof("enqueued %q for sync on change of snapshot %s", key, utils.SnapshotKey(snapshot))
+		ctrl.transferQueue.Add(key)
+	}
+}
+
+// transferWorker is the main worker for VolumeSnapshotTransferRequests.
+func (ctrl *snapshotTransferController) transferWorker() {
+	key, quit := ctrl.transferQueue.Get()
+	if quit {
+		return
+	}
+	defer ctrl.transferQueue.Done(key)
+
+	if err := ctrl.syncRequestByKey(key); err != nil {
+		// Rather than wait for a full resync, re-add the key to the
+		// queue to be processed.
+		ctrl.transferQueue.AddRateLimited(key)
+		klog.V(4).Infof("Failed to sync transfer request %q, will retry again: %v", key, err)
+		return
+	}
+	// Finally, if no error occurs we Forget this item so it does not
+	// get queued again until another change happens.
+	ctrl.transferQueue.Forget(key)
+}
+
+// syncRequestByKey processes a VolumeSnapshotTransferRequest.
+func (ctrl *snapshotTransferController) syncRequestByKey(key string) error {
+	klog.V(5).Infof("syncRequestByKey[%s]", key)
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		klog.Errorf("error getting namespace & name of transfer request %q to get request from informer: %v", key, err)
+		return nil
+	}
+	request, err := ctrl.requestLister.VolumeSnapshotTransferRequests(namespace).Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			klog.V(4).Infof("transfer request %q has been deleted", key)
+			return nil
+		}
+		klog.V(2).Infof("error getting transfer request %q from informer: %v", key, err)
+		return err
+	}
+	return ctrl.syncRequest(request.DeepCopy())
+}
@@ -0,0 +1,419 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common_controller
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	crdv1alpha1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1alpha1"
+	"github.com/kubernetes-csi/external-snapshotter/client/v8/clientset/versioned/fake"
+	informers "github.com/kubernetes-csi/external-snapshotter/client/v8/informers/externalversions"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	coreinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	testTransferSourceNamespace = "ci"
+	testTransferTargetNamespace = "team"
+)
+
+type transferTest struct {
+	name      string
+	request   *crdv1alpha1.VolumeSnapshotTransferRequest
+	accepts   []*crdv1alpha1.VolumeSnapshotTransferAccept
+	snapshots []*crdv1.VolumeSnapshot
+	contents  []*crdv1.VolumeSnapshotContent
+	pvcs      []*v1.PersistentVolumeClaim
+
+	expectedPhase crdv1alpha1.VolumeSnapshotTransferPhase
+	// expectedSnapshots are the "<namespace>/<name>" keys of the snapshots
+	// left after the sync.
+	expectedSnapshots []string
+	// expectedContentRef is the "<namespace>/<name>" key of the snapshot
+	// the content is bound to after the sync.
+	expectedContentRef string
+	expectedEvents     []string
+}
+
+func newTransferRequest(name, snapshotName, targetNamespace string) *crdv1alpha1.VolumeSnapshotTransferRequest {
+	return &crdv1alpha1.VolumeSnapshotTransferRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testTransferSourceNamespace},
+		Spec: crdv1alpha1.VolumeSnapshotTransferRequestSpec{
+			VolumeSnapshotName: snapshotName,
+			TargetNamespace:    targetNamespace,
+		},
+	}
+}
+
+func withTransferContentName(request *crdv1alpha1.VolumeSnapshotTransferRequest, contentName string) *crdv1alpha1.VolumeSnapshotTransferRequest {
+	request.Status = &crdv1alpha1.VolumeSnapshotTransferStatus{
+		Phase:                     crdv1alpha1.VolumeSnapshotTransferPending,
+		VolumeSnapshotContentName: &contentName,
+	}
+	return request
+}
+
+func newTransferAccept(name, requestName, snapshotName string) *crdv1alpha1.VolumeSnapshotTransferAccept {
+	return &crdv1alpha1.VolumeSnapshotTransferAccept{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testTransferTargetNamespace},
+		Spec: crdv1alpha1.VolumeSnapshotTransferAcceptSpec{
+			SourceNamespace:     testTransferSourceNamespace,
+			TransferRequestName: requestName,
+			VolumeSnapshotName:  snapshotName,
+		},
+	}
+}
+
+func newTransferSnapshot(namespace, name, contentName string, ready bool) *crdv1.VolumeSnapshot {
+	pvcName := "golden"
+	className := classGold
+	snapshot := &crdv1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			UID:       types.UID("uid-" + name),
+		},
+		Spec: crdv1.VolumeSnapshotSpec{
+			Source:                  crdv1.VolumeSnapshotSource{PersistentVolumeClaimName: &pvcName},
+			VolumeSnapshotClassName: &className,
+		},
+		Status: &crdv1.VolumeSnapshotStatus{
+			BoundVolumeSnapshotContentName: &contentName,
+			ReadyToUse:                     &ready,
+		},
+	}
+	return snapshot
+}
+
+func newTransferContent(name string, snapshot *crdv1.VolumeSnapshot) *crdv1.VolumeSnapshotContent {
+	volumeHandle := "pv-handle-golden"
+	className := classGold
+	return &crdv1.VolumeSnapshotContent{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: crdv1.VolumeSnapshotContentSpec{
+			Driver:                  mockDriverName,
+			DeletionPolicy:          crdv1.VolumeSnapshotContentDelete,
+			Source:                  crdv1.VolumeSnapshotContentSource{VolumeHandle: &volumeHandle},
+			VolumeSnapshotClassName: &className,
+			VolumeSnapshotRef: v1.ObjectReference{
+				Kind:       "VolumeSnapshot",
+				APIVersion: "snapshot.storage.k8s.io/v1",
+				Namespace:  snapshot.Namespace,
+				Name:       snapshot.Name,
+				UID:        snapshot.UID,
+			},
+		},
+	}
+}
+
+func TestSyncTransferRequest(t *testing.T) {
+	golden := newTransferSnapshot(testTransferSourceNamespace, "golden", "snapcontent-golden", true)
+	goldenContent := newTransferContent("snapcontent-golden", golden)
+
+	tests := []transferTest{
+		{
+			name:               "7-1 - request waits for an accept",
+			request:            newTransferRequest("share", "golden", testTransferTargetNamespace),
+			snapshots:          []*crdv1.VolumeSnapshot{golden},
+			contents:           []*crdv1.VolumeSnapshotContent{goldenContent},
+			expectedPhase:      crdv1alpha1.VolumeSnapshotTransferPending,
+			expectedSnapshots:  []string{"ci/golden"},
+			expectedContentRef: "ci/golden",
+		},
+		{
+			name:               "7-2 - accept of another request is ignored",
+			request:            newTransferRequest("share", "golden", testTransferTargetNamespace),
+			accepts:            []*crdv1alpha1.VolumeSnapshotTransferAccept{newTransferAccept("take", "other", "restored")},
+			snapshots:          []*crdv1.VolumeSnapshot{golden},
+			contents:           []*crdv1.VolumeSnapshotContent{goldenContent},
+			expectedPhase:      crdv1alpha1.VolumeSnapshotTransferPending,
+			expectedSnapshots:  []string{"ci/golden"},
+			expectedContentRef: "ci/golden",
+		},
+		{
+			name:               "7-3 - accepted request transfers the content",
+			request:            newTransferRequest("share", "golden", testTransferTargetNamespace),
+			accepts:            []*crdv1alpha1.VolumeSnapshotTransferAccept{newTransferAccept("take", "share", "restored")},
+			snapshots:          []*crdv1.VolumeSnapshot{golden},
+			contents:           []*crdv1.VolumeSnapshotContent{goldenContent},
+			expectedPhase:      crdv1alpha1.VolumeSnapshotTransferCompleted,
+			expectedSnapshots:  []string{"team/restored"},
+			expectedContentRef: "team/restored",
+			expectedEvents:     []string{"Normal VolumeSnapshotTransferred", "Normal VolumeSnapshotTransferred"},
+		},
+		{
+			name:               "7-4 - snapshot that is not ready is not transferred",
+			request:            newTransferRequest("share", "golden", testTransferTargetNamespace),
+			accepts:            []*crdv1alpha1.VolumeSnapshotTransferAccept{newTransferAccept("take", "share", "restored")},
+			snapshots:          []*crdv1.VolumeSnapshot{newTransferSnapshot(testTransferSourceNamespace, "golden", "snapcontent-golden", false)},
+			contents:           []*crdv1.VolumeSnapshotContent{goldenContent},
+			expectedPhase:      crdv1alpha1.VolumeSnapshotTransferPending,
+			expectedSnapshots:  []string{"ci/golden"},
+			expectedContentRef: "ci/golden",
+		},
+		{
+			name:               "7-5 - snapshot used to restore a PVC is not transferred",
+			request:            newTransferRequest("share", "golden", testTransferTargetNamespace),
+			accepts:            []*crdv1alpha1.VolumeSnapshotTransferAccept{newTransferAccept("take", "share", "restored")},
+			snapshots:          []*crdv1.VolumeSnapshot{golden},
+			contents:           []*crdv1.VolumeSnapshotContent{goldenContent},
+			pvcs:               []*v1.PersistentVolumeClaim{withClaimNamespace(newRestoringClaim("restore", "golden"), testTransferSourceNamespace)},
+			expectedPhase:      crdv1alpha1.VolumeSnapshotTransferPending,
+			expectedSnapshots:  []string{"ci/golden"},
+			expectedContentRef: "ci/golden",
+		},
+		{
+			name:    "7-6 - existing target snapshot blocks the transfer",
+			request: newTransferRequest("share", "golden", testTransferTargetNamespace),
+			accepts: []*crdv1alpha1.VolumeSnapshotTransferAccept{newTransferAccept("take", "share", "restored")},
+			snapshots: []*crdv1.VolumeSnapshot{
+				golden,
+				newTransferSnapshot(testTransferTargetNamespace, "restored", "snapcontent-other", true),
+			},
+			contents:           []*crdv1.VolumeSnapshotContent{goldenContent},
+			expectedPhase:      crdv1alpha1.VolumeSnapshotTransferPending,
+			expectedSnapshots:  []string{"ci/golden", "team/restored"},
+			expectedContentRef: "ci/golden",
+		},
+		{
+			name:    "7-7 - group snapshot members cannot be transferred",
+			request: newTransferRequest("share", "golden", testTransferTargetNamespace),
+			accepts: []*crdv1alpha1.VolumeSnapshotTransferAccept{newTransferAccept("take", "share", "restored")},
+			snapshots: []*crdv1.VolumeSnapshot{
+				withGroupSnapshotName(newTransferSnapshot(testTransferSourceNamespace, "golden", "snapcontent-golden", true), "group"),
+			},
+			contents:           []*crdv1.VolumeSnapshotContent{goldenContent},
+			expectedPhase:      crdv1alpha1.VolumeSnapshotTransferFailed,
+			expectedSnapshots:  []string{"ci/golden"},
+			expectedContentRef: "ci/golden",
+			expectedEvents:     []string{"Warning VolumeSnapshotTransferFailed", "Warning VolumeSnapshotTransferFailed"},
+		},
+		{
+			name:               "7-8 - interrupted transfer is resumed after the content was rebound",
+			request:            withTransferContentName(newTransferRequest("share", "golden", testTransferTargetNamespace), "snapcontent-golden"),
+			accepts:            []*crdv1alpha1.VolumeSnapshotTransferAccept{newTransferAccept("take", "share", "restored")},
+			contents:           []*crdv1.VolumeSnapshotContent{newTransferContent("snapcontent-golden", &crdv1.VolumeSnapshot{ObjectMeta: metav1.ObjectMeta{Namespace: testTransferTargetNamespace, Name: "restored"}})},
+			expectedPhase:      crdv1alpha1.VolumeSnapshotTransferCompleted,
+			expectedSnapshots:  []string{"team/restored"},
+			expectedContentRef: "team/restored",
+			expectedEvents:     []string{"Normal VolumeSnapshotTransferred", "Normal VolumeSnapshotTransferred"},
+		},
+		{
+			name:               "7-9 - content bound to another snapshot fails the transfer",
+			request:            withTransferContentName(newTransferRequest("share", "golden", testTransferTargetNamespace), "snapcontent-golden"),
+			accepts:            []*crdv1alpha1.VolumeSnapshotTransferAccept{newTransferAccept("take", "share", "restored")},
+			contents:           []*crdv1.VolumeSnapshotContent{newTransferContent("snapcontent-golden", newTransferSnapshot("elsewhere", "other", "snapcontent-golden", true))},
+			expectedPhase:      crdv1alpha1.VolumeSnapshotTransferFailed,
+			expectedContentRef: "elsewhere/other",
+			expectedEvents:     []string{"Warning VolumeSnapshotTransferFailed", "Warning VolumeSnapshotTransferFailed"},
+		},
+		{
+			name:               "7-10 - transfer to the same namespace fails",
+			request:            newTransferRequest("share", "golden", testTransferSourceNamespace),
+			snapshots:          []*crdv1.VolumeSnapshot{golden},
+			contents:           []*crdv1.VolumeSnapshotContent{goldenContent},
+			expectedPhase:      crdv1alpha1.VolumeSnapshotTransferFailed,
+			expectedSnapshots:  []string{"ci/golden"},
+			expectedContentRef: "ci/golden",
+			expectedEvents:     []string{"Warning VolumeSnapshotTransferFailed"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			runTransferTest(t, test)
+		})
+	}
+}
+
+func withClaimNamespace(pvc *v1.PersistentVolumeClaim, namespace string) *v1.PersistentVolumeClaim {
+	pvc.Namespace = namespace
+	return pvc
+}
+
+func runTransferTest(t *testing.T, test transferTest) {
+	objs := []runtime.Object{test.request}
+	for _, accept := range test.accepts {
+		objs = append(objs, accept)
+	}
+	for _, snapshot := range test.snapshots {
+		objs = append(objs, snapshot.DeepCopy())
+	}
+	for _, content := range test.contents {
+		objs = append(objs, content.DeepCopy())
+	}
+	client := fake.NewSimpleClientset(objs...)
+	kubeObjs := []runtime.Object{}
+	for _, pvc := range test.pvcs {
+		kubeObjs = append(kubeObjs, pvc)
+	}
+	kubeClient := kubefake.NewSimpleClientset(kubeObjs...)
+
+	factory := informers.NewSharedInformerFactory(client, 0)
+	coreFactory := coreinformers.NewSharedInformerFactory(kubeClient, 0)
+	ctrl := NewSnapshotTransferController(
+		client,
+		kubeClient,
+		factory.Snapshot().V1alpha1().VolumeSnapshotTransferRequests(),
+		factory.Snapshot().V1alpha1().VolumeSnapshotTransferAccepts(),
+		factory.Snapshot().V1().VolumeSnapshots(),
+		factory.Snapshot().V1().VolumeSnapshotContents(),
+		coreFactory.Core().V1().PersistentVolumeClaims(),
+		0,
+		workqueue.DefaultTypedControllerRateLimiter[string](),
+	)
+	fakeRecorder := record.NewFakeRecorder(100)
+	ctrl.eventRecorder = fakeRecorder
+	ctrl.now = func() time.Time { return mustParseTime("2024-01-01T10:00:00Z") }
+
+	for _, pvc := range test.pvcs {
+		coreFactory.Core().V1().PersistentVolumeClaims().Informer().GetIndexer().Add(pvc)
+	}
+	for _, accept := range test.accepts {
+		factory.Snapshot().V1alpha1().VolumeSnapshotTransferAccepts().Informer().GetIndexer().Add(accept)
+	}
+	for _, snapshot := range test.snapshots {
+		factory.Snapshot().V1().VolumeSnapshots().Informer().GetIndexer().Add(snapshot)
+	}
+	for _, content := range test.contents {
+		factory.Snapshot().V1().VolumeSnapshotContents().Informer().GetIndexer().Add(content)
+	}
+
+	if err := ctrl.syncRequest(test.request.DeepCopy()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	request, err := client.SnapshotV1alpha1().VolumeSnapshotTransferRequests(testTransferSourceNamespace).Get(context.TODO(), test.request.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get request: %v", err)
+	}
+	var phase crdv1alpha1.VolumeSnapshotTransferPhase
+	if request.Status != nil {
+		phase = request.Status.Phase
+	}
+	if phase != test.expectedPhase {
+		t.Errorf("expected phase %q, got %q (%+v)", test.expectedPhase, phase, request.Status)
+	}
+	if phase == crdv1alpha1.VolumeSnapshotTransferCompleted && request.Status.CompletionTime == nil {
+		t.Errorf("expected completion time to be set")
+	}
+	for _, expected := range test.accepts {
+		if expected.Spec.TransferRequestName != test.request.Name {
+			continue
+		}
+		accept, err := client.SnapshotV1alpha1().VolumeSnapshotTransferAccepts(testTransferTargetNamespace).Get(context.TODO(), expected.Name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("failed to get accept: %v", err)
+		}
+		if accept.Status == nil || accept.Status.Phase != test.expectedPhase {
+			t.Errorf("expected accept phase %q, got %+v", test.expectedPhase, accept.Status)
+		}
+	}
+
+	snapshots, err := client.SnapshotV1().VolumeSnapshots("").List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to list snapshots: %v", err)
+	}
+	keys := []string{}
+	for i := range snapshots.Items {
+		snapshot := &snapshots.Items[i]
+		keys = append(keys, utils.SnapshotKey(snapshot))
+		if snapshot.Namespace == testTransferTargetNamespace && snapshot.Annotations[utils.AnnVolumeSnapshotTransferredFrom] != "" {
+			if snapshot.Spec.Source.VolumeSnapshotContentName == nil || *snapshot.Spec.Source.VolumeSnapshotContentName != "snapcontent-golden" {
+				t.Errorf("expected target snapshot to use the transferred content, got %+v", snapshot.Spec.Source)
+			}
+		}
+	}
+	sort.Strings(keys)
+	if strings.Join(keys, ",") != strings.Join(test.expectedSnapshots, ",") {
+		t.Errorf("expected snapshots %v, got %v", test.expectedSnapshots, keys)
+	}
+
+	for _, expected := range test.contents {
+		content, err := client.SnapshotV1().VolumeSnapshotContents().Get(context.TODO(), expected.Name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("failed to get content: %v", err)
+		}
+		ref := content.Spec.VolumeSnapshotRef.Namespace + "/" + content.Spec.VolumeSnapshotRef.Name
+		if ref != test.expectedContentRef {
+			t.Errorf("expected content to be bound to %s, got %s", test.expectedContentRef, ref)
+		}
+		if ref != utils.SnapshotRefKey(&expected.Spec.VolumeSnapshotRef) {
+			if content.Spec.VolumeSnapshotRef.UID != "" {
+				t.Errorf("expected the UID of the rebound reference to be cleared, got %q", content.Spec.VolumeSnapshotRef.UID)
+			}
+			if content.Annotations[utils.AnnVolumeSnapshotTransferredFrom] != "ci/golden" {
+				t.Errorf("expected transferred-from annotation, got %v", content.Annotations)
+			}
+		}
+	}
+
+	close(fakeRecorder.Events)
+	events := []string{}
+	for event := range fakeRecorder.Events {
+		events = append(events, event)
+	}
+	if len(events) != len(test.expectedEvents) {
+		t.Fatalf("expected events %v, got %v", test.expectedEvents, events)
+	}
+	for i, expected := range test.expectedEvents {
+		if !strings.HasPrefix(events[i], expected) {
+			t.Errorf("expected event %q, got %q", expected, events[i])
+		}
+	}
+}
+
+// Test binding of a VolumeSnapshot to a dynamically provisioned content
+// transferred from another namespace.
+func TestSyncTransferredSnapshot(t *testing.T) {
+	transferred := map[string]string{utils.AnnVolumeSnapshotTransferredFrom: "ci/golden"}
+	tests := []controllerTest{
+		{
+			name:              "7-11 - transferred dynamic content is bound like a pre-provisioned one",
+			initialContents:   withContentAnnotations(newContentArray("snapcontent-7-11", "", "snap7-11", "sid7-11", validSecretClass, "", "pv-handle7-11", deletionPolicy, nil, nil, false), transferred),
+			expectedContents:  withContentAnnotations(newContentArray("snapcontent-7-11", "snapuid7-11", "snap7-11", "sid7-11", validSecretClass, "", "pv-handle7-11", deletionPolicy, nil, nil, false), transferred),
+			initialSnapshots:  newSnapshotArray("snap7-11", "snapuid7-11", "", "snapcontent-7-11", validSecretClass, "", &False, nil, nil, nil, false, true, nil),
+			expectedSnapshots: newSnapshotArray("snap7-11", "snapuid7-11", "", "snapcontent-7-11", validSecretClass, "snapcontent-7-11", &True, nil, nil, nil, false, true, nil),
+			errors:            noerrors,
+			test:              testSyncSnapshot,
+		},
+		{
+			name:              "7-12 - dynamic content without transfer annotation is not bound",
+			initialContents:   newContentArray("snapcontent-7-12", "", "snap7-12", "sid7-12", validSecretClass, "", "pv-handle7-12", deletionPolicy, nil, nil, false),
+			expectedContents:  newContentArray("snapcontent-7-12", "", "snap7-12", "sid7-12", validSecretClass, "", "pv-handle7-12", deletionPolicy, nil, nil, false),
+			initialSnapshots:  newSnapshotArray("snap7-12", "snapuid7-12", "", "snapcontent-7-12", validSecretClass, "", &False, nil, nil, nil, false, true, nil),
+			expectedSnapshots: newSnapshotArray("snap7-12", "snapuid7-12", "", "snapcontent-7-12", validSecretClass, "", &False, nil, nil, newVolumeError("VolumeSnapshotContent is dynamically provisioned while expecting a pre-provisioned one", "SnapshotContentMismatch"), false, true, nil),
+			expectedEvents:    []string{"Warning SnapshotContentMismatch"},
+			errors:            noerrors,
+			test:              testSyncSnapshotError,
+		},
+	}
+
+	runSyncTests(t, tests, snapshotClasses, nil)
+}
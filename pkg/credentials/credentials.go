@@ -0,0 +1,231 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package credentials reads the credentials passed to the CSI calls of the
+// sidecar from the sources a utils.CredentialsReference identifies: Secrets
+// referenced by name or selected by labels, files mounted into the sidecar and
+// exec plugins.
+package credentials
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	klog "k8s.io/klog/v2"
+
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+)
+
+// Provider returns the credentials identified by a reference.
+type Provider interface {
+	GetCredentials(ctx context.Context, ref *utils.CredentialsReference) (map[string]string, error)
+}
+
+// Options configures the providers returned by NewProvider.
+type Options struct {
+	// Dir is the directory holding the credential directories of
+	// CredentialsReference.File. File credentials are disabled if empty.
+	Dir string
+	// ExecPluginDir is the directory holding the executables of
+	// CredentialsReference.Exec. Exec plugins are disabled if empty.
+	ExecPluginDir string
+	// ExecTimeout is the maximum run time of an exec plugin.
+	ExecTimeout time.Duration
+	// CacheTTL is the time the credentials are cached for before they are
+	// read again. Credentials are not cached if it is 0.
+	CacheTTL time.Duration
+}
+
+// NewProvider returns a Provider reading the credentials of the CSI driver
+// driverName from any source, as allowed by options.
+func NewProvider(client kubernetes.Interface, driverName string, options Options) Provider {
+	var provider Provider = &sourceProvider{
+		secret:         &secretProvider{client: client},
+		secretSelector: &secretSelectorProvider{client: client},
+		file:           &fileProvider{dir: options.Dir},
+		exec:           &execProvider{dir: options.ExecPluginDir, timeout: options.ExecTimeout, driverName: driverName},
+	}
+	if options.CacheTTL > 0 {
+		provider = newCachingProvider(provider, options.CacheTTL)
+	}
+	return provider
+}
+
+// sourceProvider dispatches a reference to the provider of its source.
+type sourceProvider struct {
+	secret, secretSelector, file, exec Provider
+}
+
+func (p *sourceProvider) GetCredentials(ctx context.Context, ref *utils.CredentialsReference) (map[string]string, error) {
+	switch {
+	case ref == nil:
+		return nil, nil
+	case ref.Secret != nil:
+		return p.secret.GetCredentials(ctx, ref)
+	case ref.SecretSelector != nil:
+		return p.secretSelector.GetCredentials(ctx, ref)
+	case ref.File != "":
+		return p.file.GetCredentials(ctx, ref)
+	case ref.Exec != "":
+		return p.exec.GetCredentials(ctx, ref)
+	}
+	return nil, fmt.Errorf("empty credentials reference")
+}
+
+// secretProvider reads the credentials from a Secret referenced by name.
+type secretProvider struct {
+	client kubernetes.Interface
+}
+
+func (p *secretProvider) GetCredentials(ctx context.Context, ref *utils.CredentialsReference) (map[string]string, error) {
+	secret, err := p.client.CoreV1().Secrets(ref.Secret.Namespace).Get(ctx, ref.Secret.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting secret %s in namespace %s: %v", ref.Secret.Name, ref.Secret.Namespace, err)
+	}
+	credentials := map[string]string{}
+	for key, value := range secret.Data {
+		credentials[key] = string(value)
+	}
+	return credentials, nil
+}
+
+// secretSelectorProvider reads the credentials from the only Secret matching
+// a label selector.
+type secretSelectorProvider struct {
+	client kubernetes.Interface
+}
+
+func (p *secretSelectorProvider) GetCredentials(ctx context.Context, ref *utils.CredentialsReference) (map[string]string, error) {
+	selector := ref.SecretSelector
+	secrets, err := p.client.CoreV1().Secrets(selector.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.Selector})
+	if err != nil {
+		return nil, fmt.Errorf("error listing secrets matching %q in namespace %s: %v", selector.Selector, selector.Namespace, err)
+	}
+	if len(secrets.Items) != 1 {
+		return nil, fmt.Errorf("found %d secrets matching %q in namespace %s, expected exactly one", len(secrets.Items), selector.Selector, selector.Namespace)
+	}
+	credentials := map[string]string{}
+	for key, value := range secrets.Items[0].Data {
+		credentials[key] = string(value)
+	}
+	return credentials, nil
+}
+
+// fileProvider reads the credentials from a directory holding one file per
+// credential, named after its key, such as a projected volume or a volume of
+// the Secrets Store CSI driver. Hidden files are skipped, which skips the
+// "..data" link of atomically updated volumes.
+type fileProvider struct {
+	dir string
+}
+
+func (p *fileProvider) GetCredentials(_ context.Context, ref *utils.CredentialsReference) (map[string]string, error) {
+	if p.dir == "" {
+		return nil, fmt.Errorf("cannot read credentials file %q, file credentials are not enabled", ref.File)
+	}
+	dir := filepath.Join(p.dir, ref.File)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading credentials directory %s: %v", dir, err)
+	}
+	credentials := map[string]string{}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		// Follow the links of atomically updated volumes.
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading credentials file %s: %v", path, err)
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		value, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading credentials file %s: %v", path, err)
+		}
+		credentials[entry.Name()] = string(value)
+	}
+	if len(credentials) == 0 {
+		return nil, fmt.Errorf("no credentials found in directory %s", dir)
+	}
+	return credentials, nil
+}
+
+type cacheEntry struct {
+	credentials map[string]string
+	expiration  time.Time
+}
+
+// cachingProvider caches the credentials of another provider for a fixed
+// time. Failures are not cached.
+type cachingProvider struct {
+	provider Provider
+	ttl      time.Duration
+	now      func() time.Time
+
+	lock    sync.Mutex
+	entries map[string]cacheEntry
+}
+
+func newCachingProvider(provider Provider, ttl time.Duration) *cachingProvider {
+	return &cachingProvider{
+		provider: provider,
+		ttl:      ttl,
+		now:      time.Now,
+		entries:  map[string]cacheEntry{},
+	}
+}
+
+func (p *cachingProvider) GetCredentials(ctx context.Context, ref *utils.CredentialsReference) (map[string]string, error) {
+	if ref == nil {
+		return nil, nil
+	}
+	key := ref.String()
+	p.lock.Lock()
+	entry, found := p.entries[key]
+	p.lock.Unlock()
+	if found && p.now().Before(entry.expiration) {
+		return maps.Clone(entry.credentials), nil
+	}
+
+	credentials, err := p.provider.GetCredentials(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	klog.V(5).Infof("caching credentials of %s for %v", key, p.ttl)
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	now := p.now()
+	for k, e := range p.entries {
+		if !now.Before(e.expiration) {
+			delete(p.entries, k)
+		}
+	}
+	p.entries[key] = cacheEntry{credentials: credentials, expiration: now.Add(p.ttl)}
+	return maps.Clone(credentials), nil
+}
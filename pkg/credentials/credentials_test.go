@@ -0,0 +1,214 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentials
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newSecret(name, namespace string, labels map[string]string, data map[string]string) *v1.Secret {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Data: map[string][]byte{},
+	}
+	for key, value := range data {
+		secret.Data[key] = []byte(value)
+	}
+	return secret
+}
+
+func TestGetCredentials(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		newSecret("secret", "ns", map[string]string{"app": "csi", "snapshot": "snap-1"}, map[string]string{"user": "one"}),
+		newSecret("other", "ns", map[string]string{"app": "csi", "snapshot": "snap-2"}, map[string]string{"user": "two"}),
+	)
+
+	// A directory laid out like an atomically updated volume.
+	dir := t.TempDir()
+	volume := filepath.Join(dir, "volume")
+	data := filepath.Join(volume, "..2024_01_01")
+	if err := os.MkdirAll(data, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(data, "user"), []byte("file"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("..2024_01_01", filepath.Join(volume, "..data")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("..data/user", filepath.Join(volume, "user")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "empty"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	pluginDir := t.TempDir()
+	plugins := map[string]string{
+		"echo-driver": "#!/bin/sh\nread request\necho \"{\\\"credentials\\\":$request}\"\n",
+		"fail":        "#!/bin/sh\necho denied >&2\nexit 1\n",
+		"empty":       "#!/bin/sh\necho '{}'\n",
+	}
+	for name, script := range plugins {
+		if err := os.WriteFile(filepath.Join(pluginDir, name), []byte(script), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testcases := map[string]struct {
+		options           Options
+		ref               *utils.CredentialsReference
+		expectCredentials map[string]string
+		expectErr         bool
+	}{
+		"nil reference": {
+			ref:               nil,
+			expectCredentials: nil,
+		},
+		"secret": {
+			ref:               &utils.CredentialsReference{Secret: &v1.SecretReference{Name: "secret", Namespace: "ns"}},
+			expectCredentials: map[string]string{"user": "one"},
+		},
+		"missing secret": {
+			ref:       &utils.CredentialsReference{Secret: &v1.SecretReference{Name: "missing", Namespace: "ns"}},
+			expectErr: true,
+		},
+		"secret selector": {
+			ref:               &utils.CredentialsReference{SecretSelector: &utils.SecretSelector{Namespace: "ns", Selector: "snapshot=snap-2"}},
+			expectCredentials: map[string]string{"user": "two"},
+		},
+		"secret selector without match": {
+			ref:       &utils.CredentialsReference{SecretSelector: &utils.SecretSelector{Namespace: "ns", Selector: "snapshot=snap-3"}},
+			expectErr: true,
+		},
+		"secret selector with several matches": {
+			ref:       &utils.CredentialsReference{SecretSelector: &utils.SecretSelector{Namespace: "ns", Selector: "app=csi"}},
+			expectErr: true,
+		},
+		"file": {
+			options:           Options{Dir: dir},
+			ref:               &utils.CredentialsReference{File: "volume"},
+			expectCredentials: map[string]string{"user": "file"},
+		},
+		"empty file directory": {
+			options:   Options{Dir: dir},
+			ref:       &utils.CredentialsReference{File: "empty"},
+			expectErr: true,
+		},
+		"file disabled": {
+			ref:       &utils.CredentialsReference{File: "volume"},
+			expectErr: true,
+		},
+		"exec": {
+			options:           Options{ExecPluginDir: pluginDir},
+			ref:               &utils.CredentialsReference{Exec: "echo-driver"},
+			expectCredentials: map[string]string{"driver": "test.csi.k8s.io"},
+		},
+		"failing exec": {
+			options:   Options{ExecPluginDir: pluginDir},
+			ref:       &utils.CredentialsReference{Exec: "fail"},
+			expectErr: true,
+		},
+		"exec without credentials": {
+			options:   Options{ExecPluginDir: pluginDir},
+			ref:       &utils.CredentialsReference{Exec: "empty"},
+			expectErr: true,
+		},
+		"exec disabled": {
+			ref:       &utils.CredentialsReference{Exec: "echo-driver"},
+			expectErr: true,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			provider := NewProvider(client, "test.csi.k8s.io", tc.options)
+			credentials, err := provider.GetCredentials(context.TODO(), tc.ref)
+			if err != nil {
+				if tc.expectErr {
+					return
+				}
+				t.Fatalf("Did not expect error but got: %v", err)
+			}
+			if tc.expectErr {
+				t.Fatalf("Expected error but got none")
+			}
+			if !reflect.DeepEqual(credentials, tc.expectCredentials) {
+				t.Errorf("Expected %v, got %v", tc.expectCredentials, credentials)
+			}
+		})
+	}
+}
+
+type countingProvider struct {
+	calls int
+}
+
+func (p *countingProvider) GetCredentials(_ context.Context, _ *utils.CredentialsReference) (map[string]string, error) {
+	p.calls++
+	return map[string]string{"user": "one"}, nil
+}
+
+func TestCachingProvider(t *testing.T) {
+	now := time.Now()
+	source := &countingProvider{}
+	provider := newCachingProvider(source, time.Minute)
+	provider.now = func() time.Time { return now }
+	ref := &utils.CredentialsReference{Exec: "vault"}
+
+	get := func(expectCalls int) {
+		t.Helper()
+		credentials, err := provider.GetCredentials(context.TODO(), ref)
+		if err != nil {
+			t.Fatalf("Did not expect error but got: %v", err)
+		}
+		if credentials["user"] != "one" {
+			t.Errorf("Expected credentials of user one, got %v", credentials)
+		}
+		// Callers must not change the cached credentials.
+		credentials["user"] = "changed"
+		if source.calls != expectCalls {
+			t.Errorf("Expected %d calls of the source, got %d", expectCalls, source.calls)
+		}
+	}
+
+	get(1)
+	now = now.Add(30 * time.Second)
+	get(1)
+	now = now.Add(30 * time.Second)
+	get(2)
+	if _, err := provider.GetCredentials(context.TODO(), &utils.CredentialsReference{File: "other"}); err != nil {
+		t.Fatalf("Did not expect error but got: %v", err)
+	}
+	if len(provider.entries) != 2 {
+		t.Errorf("Expected 2 cache entries, got %d", len(provider.entries))
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentials
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+)
+
+// defaultExecTimeout is the maximum run time of an exec plugin, if not set in
+// the options.
+const defaultExecTimeout = 10 * time.Second
+
+// ExecRequest is written as JSON to the standard input of exec plugins.
+type ExecRequest struct {
+	// Driver is the name of the CSI driver the credentials are passed to.
+	Driver string `json:"driver"`
+}
+
+// ExecResponse is read as JSON from the standard output of exec plugins.
+type ExecResponse struct {
+	// Credentials are the credentials passed to the CSI driver.
+	Credentials map[string]string `json:"credentials"`
+}
+
+// execProvider reads the credentials from the output of an executable.
+type execProvider struct {
+	dir        string
+	timeout    time.Duration
+	driverName string
+}
+
+func (p *execProvider) GetCredentials(ctx context.Context, ref *utils.CredentialsReference) (map[string]string, error) {
+	if p.dir == "" {
+		return nil, fmt.Errorf("cannot run credentials exec plugin %q, exec plugins are not enabled", ref.Exec)
+	}
+	timeout := p.timeout
+	if timeout <= 0 {
+		timeout = defaultExecTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	request, err := json.Marshal(ExecRequest{Driver: p.driverName})
+	if err != nil {
+		return nil, err
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, filepath.Join(p.dir, ref.Exec))
+	cmd.Stdin = bytes.NewReader(request)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("credentials exec plugin %q failed: %v: %s", ref.Exec, err, strings.TrimSpace(stderr.String()))
+	}
+
+	response := ExecResponse{}
+	if err := json.Unmarshal(stdout.Bytes(), &response); err != nil {
+		return nil, fmt.Errorf("credentials exec plugin %q returned an invalid response: %v", ref.Exec, err)
+	}
+	if len(response.Credentials) == 0 {
+		return nil, fmt.Errorf("credentials exec plugin %q returned no credentials", ref.Exec)
+	}
+	return response.Credentials, nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"1-2-3 * * * *",
+		"*-5 * * * *",
+		"@every 5m",
+		"foo * * * *",
+	}
+	for _, spec := range tests {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q): expected error, got none", spec)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		spec     string
+		from     string
+		expected string
+	}{
+		{"* * * * *", "2024-01-01T10:00:00Z", "2024-01-01T10:01:00Z"},
+		{"* * * * *", "2024-01-01T10:00:30Z", "2024-01-01T10:01:00Z"},
+		{"*/15 * * * *", "2024-01-01T10:07:00Z", "2024-01-01T10:15:00Z"},
+		{"5/20 * * * *", "2024-01-01T10:26:00Z", "2024-01-01T10:45:00Z"},
+		{"0 */6 * * *", "2024-01-01T07:00:00Z", "2024-01-01T12:00:00Z"},
+		{"30 2 * * *", "2024-01-01T03:00:00Z", "2024-01-02T02:30:00Z"},
+		{"0 0 1 * *", "2024-01-15T00:00:00Z", "2024-02-01T00:00:00Z"},
+		{"0 0 29 2 *", "2024-03-01T00:00:00Z", "2028-02-29T00:00:00Z"},
+		{"0 0 * * mon-fri", "2024-01-05T12:00:00Z", "2024-01-08T00:00:00Z"},
+		{"0 0 * * 7", "2024-01-01T00:00:00Z", "2024-01-07T00:00:00Z"},
+		{"0 0 1,15 * *", "2024-01-02T00:00:00Z", "2024-01-15T00:00:00Z"},
+		// Both day fields restricted: either one matches.
+		{"0 0 13 * fri", "2024-01-01T00:00:00Z", "2024-01-05T00:00:00Z"},
+		{"0 0 * jan-mar *", "2024-03-31T23:59:00Z", "2025-01-01T00:00:00Z"},
+		{"@hourly", "2024-01-01T10:59:59Z", "2024-01-01T11:00:00Z"},
+		{"@daily", "2024-01-01T00:00:00Z", "2024-01-02T00:00:00Z"},
+		{"@weekly", "2024-01-01T00:00:00Z", "2024-01-07T00:00:00Z"},
+		{"@YEARLY", "2024-06-01T00:00:00Z", "2025-01-01T00:00:00Z"},
+		// Never matches.
+		{"0 0 30 2 *", "2024-01-01T00:00:00Z", ""},
+	}
+	for _, test := range tests {
+		s, err := Parse(test.spec)
+		if err != nil {
+			t.Errorf("Parse(%q): unexpected error: %v", test.spec, err)
+			continue
+		}
+		from, _ := time.Parse(time.RFC3339, test.from)
+		var expected time.Time
+		if test.expected != "" {
+			expected, _ = time.Parse(time.RFC3339, test.expected)
+		}
+		if got := s.Next(from); !got.Equal(expected) {
+			t.Errorf("Next(%q, %s): expected %s, got %s", test.spec, test.from, expected, got)
+		}
+	}
+}
+
+func TestNextNonUTC(t *testing.T) {
+	s, err := Parse("0 12 * * *")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	from := time.Date(2024, 1, 1, 12, 30, 0, 0, time.FixedZone("CET", 3600))
+	expected := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	if got := s.Next(from); !got.Equal(expected) {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
@@ -0,0 +1,231 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package debug serves read-only JSON endpoints exposing the state of the
+// controllers, e.g. the depth of their work queues and the result of the
+// last sync of their objects.
+package debug
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	klog "k8s.io/klog/v2"
+)
+
+// PathPrefix is the HTTP path under which the debug endpoints are served.
+const PathPrefix = "/debug/state/"
+
+// Server registers debug endpoints on a mux. The index of the endpoints is
+// served at PathPrefix.
+type Server struct {
+	mux *http.ServeMux
+
+	mu    sync.Mutex
+	names []string
+}
+
+// NewServer returns a new *Server registering its endpoints on mux.
+func NewServer(mux *http.ServeMux) *Server {
+	s := &Server{mux: mux}
+	mux.Handle(PathPrefix, handler(func() interface{} {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		paths := make([]string, 0, len(s.names))
+		for _, name := range s.names {
+			paths = append(paths, PathPrefix+name)
+		}
+		sort.Strings(paths)
+		return paths
+	}))
+	return s
+}
+
+// Register serves the JSON encoding of the value returned by state at
+// PathPrefix followed by name.
+func (s *Server) Register(name string, state func() interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.names = append(s.names, name)
+	s.mux.Handle(PathPrefix+name, handler(state))
+}
+
+func handler(state func() interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(state()); err != nil {
+			klog.Errorf("failed to encode debug state of %s: %v", r.URL.Path, err)
+		}
+	}
+}
+
+// KeyGetter is implemented by the stores of the informers.
+type KeyGetter interface {
+	GetByKey(key string) (item interface{}, exists bool, err error)
+}
+
+// RateLimitingQueue is implemented by the work queues of the controllers.
+type RateLimitingQueue interface {
+	Len() int
+	NumRequeues(item string) int
+}
+
+// SyncResult is the result of the last sync of an object.
+type SyncResult struct {
+	Key  string    `json:"key"`
+	Time time.Time `json:"time"`
+	// Error is the error of the last sync, empty if it succeeded.
+	Error string `json:"error,omitempty"`
+	// Failures is the number of consecutive failed syncs.
+	Failures int `json:"failures,omitempty"`
+}
+
+// QueueState is the state of a work queue.
+type QueueState struct {
+	Name string `json:"name"`
+	// Depth is the number of objects waiting to be synced.
+	Depth int `json:"depth"`
+	// Retries is the number of times the objects whose last sync failed
+	// were requeued, by key.
+	Retries map[string]int `json:"retries,omitempty"`
+}
+
+// SyncResults records the result of the last sync of the objects of a work
+// queue. The objects that are not in the store of their informer anymore
+// are forgotten when they are synced.
+type SyncResults struct {
+	name  string
+	store KeyGetter
+
+	mu      sync.Mutex
+	results map[string]SyncResult
+
+	// now returns the current time. It is replaced in unit tests.
+	now func() time.Time
+}
+
+// NewSyncResults returns a new *SyncResults for the work queue with the
+// given name, whose objects are in store.
+func NewSyncResults(name string, store KeyGetter) *SyncResults {
+	return &SyncResults{
+		name:    name,
+		store:   store,
+		results: map[string]SyncResult{},
+		now:     time.Now,
+	}
+}
+
+// Record records the result of a sync of the object with the given key.
+func (r *SyncResults) Record(key string, err error) {
+	if r == nil {
+		return
+	}
+	_, exists, _ := r.store.GetByKey(key)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !exists {
+		delete(r.results, key)
+		return
+	}
+	result := SyncResult{Key: key, Time: r.now()}
+	if err != nil {
+		result.Error = err.Error()
+		result.Failures = r.results[key].Failures + 1
+	}
+	r.results[key] = result
+}
+
+// List returns the results of the objects, sorted by key.
+func (r *SyncResults) List() []SyncResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	results := make([]SyncResult, 0, len(r.results))
+	for _, result := range r.results {
+		results = append(results, result)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Key < results[j].Key
+	})
+	return results
+}
+
+// QueueState returns the state of the work queue of the objects.
+func (r *SyncResults) QueueState(queue RateLimitingQueue) QueueState {
+	state := QueueState{Name: r.name, Depth: queue.Len()}
+	for _, result := range r.List() {
+		if result.Failures == 0 {
+			continue
+		}
+		if requeues := queue.NumRequeues(result.Key); requeues > 0 {
+			if state.Retries == nil {
+				state.Retries = map[string]int{}
+			}
+			state.Retries[result.Key] = requeues
+		}
+	}
+	return state
+}
+
+// LeaderState is the leader election state of a process.
+type LeaderState struct {
+	// LeaderElection is true when leader election is enabled.
+	LeaderElection bool   `json:"leaderElection"`
+	LockName       string `json:"lockName,omitempty"`
+	// Leader is true when the controllers of the process are running, i.e.
+	// when it is the leader or leader election is disabled.
+	Leader      bool       `json:"leader"`
+	LeaderSince *time.Time `json:"leaderSince,omitempty"`
+}
+
+// Leader tracks the leader election state of a process.
+type Leader struct {
+	mu    sync.Mutex
+	state LeaderState
+}
+
+// NewLeader returns a new *Leader. lockName is the name of the leader
+// election lock, if leader election is enabled.
+func NewLeader(leaderElection bool, lockName string) *Leader {
+	return &Leader{state: LeaderState{LeaderElection: leaderElection, LockName: lockName}}
+}
+
+// Started records that the process became the leader and started its
+// controllers. A process that loses the leadership exits.
+func (l *Leader) Started() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	l.state.Leader = true
+	l.state.LeaderSince = &now
+}
+
+// State returns the leader election state.
+func (l *Leader) State() LeaderState {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.state
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+func newTestStore(keys ...string) cache.Store {
+	store := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	for _, key := range keys {
+		namespace, name, _ := cache.SplitMetaNamespaceKey(key)
+		store.Add(&metav1.ObjectMeta{Namespace: namespace, Name: name})
+	}
+	return store
+}
+
+func TestSyncResults(t *testing.T) {
+	store := newTestStore("ns/a", "ns/b")
+	results := NewSyncResults("test-queue", store)
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	results.now = func() time.Time { return now }
+
+	results.Record("ns/a", errors.New("boom"))
+	results.Record("ns/a", errors.New("boom again"))
+	results.Record("ns/b", errors.New("boom"))
+	results.Record("ns/b", nil)
+	// objects that are not in the store are not recorded
+	results.Record("ns/c", errors.New("boom"))
+
+	expected := []SyncResult{
+		{Key: "ns/a", Time: now, Error: "boom again", Failures: 2},
+		{Key: "ns/b", Time: now},
+	}
+	if got := results.List(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+
+	// deleted objects are forgotten on their next sync
+	store.Delete(&metav1.ObjectMeta{Namespace: "ns", Name: "a"})
+	results.Record("ns/a", nil)
+	expected = expected[1:]
+	if got := results.List(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+}
+
+func TestQueueState(t *testing.T) {
+	results := NewSyncResults("test-queue", newTestStore("ns/a", "ns/b"))
+	queue := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[string]())
+	defer queue.ShutDown()
+
+	queue.Add("ns/b")
+	queue.AddRateLimited("ns/a")
+	queue.AddRateLimited("ns/a")
+	results.Record("ns/a", errors.New("boom"))
+	results.Record("ns/b", nil)
+
+	expected := QueueState{Name: "test-queue", Depth: 1, Retries: map[string]int{"ns/a": 2}}
+	if got := results.QueueState(queue); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+}
+
+func TestServer(t *testing.T) {
+	mux := http.NewServeMux()
+	server := NewServer(mux)
+	leader := NewLeader(true, "test-lock")
+	server.Register("leader", func() interface{} { return leader.State() })
+	server.Register("queues", func() interface{} { return []QueueState{{Name: "test-queue", Depth: 3}} })
+
+	get := func(path string, value interface{}) {
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("expected status %d for %s, got %d", http.StatusOK, path, recorder.Code)
+		}
+		if contentType := recorder.Header().Get("Content-Type"); contentType != "application/json" {
+			t.Errorf("expected JSON for %s, got %q", path, contentType)
+		}
+		if err := json.Unmarshal(recorder.Body.Bytes(), value); err != nil {
+			t.Fatalf("failed to decode %s: %v", path, err)
+		}
+	}
+
+	var index []string
+	get(PathPrefix, &index)
+	if expected := []string{PathPrefix + "leader", PathPrefix + "queues"}; !reflect.DeepEqual(index, expected) {
+		t.Errorf("expected index %v, got %v", expected, index)
+	}
+
+	var queues []QueueState
+	get(PathPrefix+"queues", &queues)
+	if expected := []QueueState{{Name: "test-queue", Depth: 3}}; !reflect.DeepEqual(queues, expected) {
+		t.Errorf("expected queues %+v, got %+v", expected, queues)
+	}
+
+	var state LeaderState
+	get(PathPrefix+"leader", &state)
+	if !state.LeaderElection || state.Leader || state.LockName != "test-lock" || state.LeaderSince != nil {
+		t.Errorf("unexpected leader state before the leadership is acquired: %+v", state)
+	}
+	leader.Started()
+	get(PathPrefix+"leader", &state)
+	if !state.Leader || state.LeaderSince == nil {
+		t.Errorf("unexpected leader state after the leadership is acquired: %+v", state)
+	}
+
+	// the endpoints are read-only
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, PathPrefix+"queues", nil))
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status %d for POST, got %d", http.StatusMethodNotAllowed, recorder.Code)
+	}
+}
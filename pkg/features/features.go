@@ -24,6 +24,18 @@ import (
 const (
 	// Enable usage of volume group snapshot
 	VolumeGroupSnapshot featuregate.Feature = "CSIVolumeGroupSnapshot"
+
+	// Enable creation of volume snapshots from VolumeSnapshotSchedule objects
+	VolumeSnapshotSchedule featuregate.Feature = "VolumeSnapshotSchedule"
+
+	// Enable deletion of volume snapshots according to VolumeSnapshotRetentionPolicy objects
+	VolumeSnapshotRetentionPolicy featuregate.Feature = "VolumeSnapshotRetentionPolicy"
+
+	// Enable pre- and post-snapshot hooks from VolumeSnapshotHook objects
+	VolumeSnapshotHooks featuregate.Feature = "VolumeSnapshotHooks"
+
+	// Enable transfer of volume snapshots between namespaces
+	VolumeSnapshotTransfer featuregate.Feature = "VolumeSnapshotTransfer"
 )
 
 func init() {
@@ -33,5 +45,9 @@ func init() {
 // defaultKubernetesFeatureGates consists of all known feature keys specific to external-snapshotter.
 // To add a new feature, define a key for it above and add it here.
 var defaultKubernetesFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
-	VolumeGroupSnapshot: {Default: false, PreRelease: featuregate.Beta},
+	VolumeGroupSnapshot:           {Default: false, PreRelease: featuregate.Beta},
+	VolumeSnapshotSchedule:        {Default: false, PreRelease: featuregate.Alpha},
+	VolumeSnapshotRetentionPolicy: {Default: false, PreRelease: featuregate.Alpha},
+	VolumeSnapshotHooks:           {Default: false, PreRelease: featuregate.Alpha},
+	VolumeSnapshotTransfer:        {Default: false, PreRelease: featuregate.Alpha},
 }
@@ -0,0 +1,235 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package group_snapshotter
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	klog "k8s.io/klog/v2"
+
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/snapshotter"
+)
+
+// emulatedGroupSnapshot implements group snapshots with individual snapshots
+// for CSI drivers that do not implement the GroupController service.
+type emulatedGroupSnapshot struct {
+	snapshotter snapshotter.Snapshotter
+	window      time.Duration
+}
+
+// NewEmulatedGroupSnapshotter returns a GroupSnapshotter that takes a group
+// snapshot with one CreateSnapshot call per volume, issued in parallel under
+// the limiter of the context, see WithCreateSnapshotLimiter. All the calls
+// must complete, and all the snapshots must be cut, within window. Otherwise
+// the snapshots that were taken are deleted and the group snapshot fails, so
+// that the next attempt takes all of them again. The group snapshot handle is
+// the group snapshot name.
+//
+// The workloads using the volumes are not quiesced, emulated group snapshots
+// are only crash consistent.
+func NewEmulatedGroupSnapshotter(snapshotter snapshotter.Snapshotter, window time.Duration) GroupSnapshotter {
+	return &emulatedGroupSnapshot{
+		snapshotter: snapshotter,
+		window:      window,
+	}
+}
+
+// IsEmulated returns true if the group snapshots of gs are emulated with
+// individual snapshots.
+func IsEmulated(gs GroupSnapshotter) bool {
+	_, ok := gs.(*emulatedGroupSnapshot)
+	return ok
+}
+
+type createSnapshotLimiterKey struct{}
+
+// CreateSnapshotLimiter waits for a slot for a CreateSnapshot call until ctx
+// is done and returns the function that releases it.
+type CreateSnapshotLimiter func(ctx context.Context) (func(), error)
+
+// WithCreateSnapshotLimiter returns a copy of ctx that makes emulated group
+// snapshots wait for a slot of limiter before each per-volume CreateSnapshot
+// call.
+func WithCreateSnapshotLimiter(ctx context.Context, limiter CreateSnapshotLimiter) context.Context {
+	return context.WithValue(ctx, createSnapshotLimiterKey{}, limiter)
+}
+
+// createSnapshot takes the snapshot of a volume of an emulated group snapshot
+// once the limiter of ctx, if any, gives it a slot.
+func (gs *emulatedGroupSnapshot) createSnapshot(ctx context.Context, snapshotName string, volumeID string, parameters map[string]string, snapshotterCredentials map[string]string) (string, string, time.Time, int64, bool, error) {
+	if limiter, ok := ctx.Value(createSnapshotLimiterKey{}).(CreateSnapshotLimiter); ok && limiter != nil {
+		release, err := limiter(ctx)
+		if err != nil {
+			return "", "", time.Time{}, 0, false, err
+		}
+		defer release()
+	}
+	return gs.snapshotter.CreateSnapshot(ctx, snapshotName, volumeID, parameters, snapshotterCredentials)
+}
+
+// emulatedSnapshotName returns the name of the snapshot of a volume in an
+// emulated group snapshot. It is stable, so that CreateSnapshot is idempotent
+// across retries, and no longer than the group snapshot name plus 13
+// characters.
+func emulatedSnapshotName(groupSnapshotName, volumeID string) string {
+	return fmt.Sprintf("%s-%x", groupSnapshotName, sha256.Sum256([]byte(volumeID)))[:len(groupSnapshotName)+13]
+}
+
+type emulatedSnapshotResult struct {
+	driverName   string
+	snapshotID   string
+	creationTime time.Time
+	size         int64
+	readyToUse   bool
+	err          error
+}
+
+func (gs *emulatedGroupSnapshot) CreateGroupSnapshot(ctx context.Context, groupSnapshotName string, volumeIDs []string, parameters map[string]string, snapshotterCredentials map[string]string) (string, string, []*csi.Snapshot, time.Time, bool, error) {
+	klog.V(5).Infof("Emulated CreateGroupSnapshot: %s with %d volumes within %v", groupSnapshotName, len(volumeIDs), gs.window)
+	if len(volumeIDs) == 0 {
+		return "", "", nil, time.Time{}, false, status.Error(codes.InvalidArgument, "no source volumes")
+	}
+
+	windowCtx, cancel := context.WithTimeout(ctx, gs.window)
+	defer cancel()
+	results := make([]emulatedSnapshotResult, len(volumeIDs))
+	var wg sync.WaitGroup
+	for i := range volumeIDs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			r := &results[i]
+			r.driverName, r.snapshotID, r.creationTime, r.size, r.readyToUse, r.err = gs.createSnapshot(windowCtx, emulatedSnapshotName(groupSnapshotName, volumeIDs[i]), volumeIDs[i], parameters, snapshotterCredentials)
+		}(i)
+	}
+	wg.Wait()
+
+	var err error
+	for i, r := range results {
+		if r.err == nil {
+			continue
+		}
+		// Keep the code of the first failure, it tells the caller
+		// whether the snapshot may still be in progress.
+		st, _ := status.FromError(r.err)
+		if windowCtx.Err() == context.DeadlineExceeded {
+			st = status.New(codes.DeadlineExceeded, st.Message())
+		}
+		err = status.Errorf(st.Code(), "failed to snapshot volume %s within %v: %s", volumeIDs[i], gs.window, st.Message())
+		break
+	}
+	if err == nil {
+		if spread := creationTimeSpread(results); spread > gs.window {
+			err = status.Errorf(codes.Aborted, "the snapshots were cut %v apart, more than the %v window", spread, gs.window)
+		}
+	}
+	if err != nil {
+		gs.deleteSnapshots(ctx, groupSnapshotName, results, snapshotterCredentials)
+		return "", "", nil, time.Time{}, false, err
+	}
+
+	var creationTime time.Time
+	readyToUse := true
+	snapshots := make([]*csi.Snapshot, 0, len(results))
+	for i, r := range results {
+		snapshots = append(snapshots, &csi.Snapshot{
+			SizeBytes:       r.size,
+			SnapshotId:      r.snapshotID,
+			SourceVolumeId:  volumeIDs[i],
+			CreationTime:    timestamppb.New(r.creationTime),
+			ReadyToUse:      r.readyToUse,
+			GroupSnapshotId: groupSnapshotName,
+		})
+		if r.creationTime.After(creationTime) {
+			creationTime = r.creationTime
+		}
+		readyToUse = readyToUse && r.readyToUse
+	}
+	klog.V(5).Infof("Emulated CreateGroupSnapshot: %s snapshots [%v] readyToUse [%v]", groupSnapshotName, snapshots, readyToUse)
+	return results[0].driverName, groupSnapshotName, snapshots, creationTime, readyToUse, nil
+}
+
+// creationTimeSpread returns the time between the first and the last
+// snapshot, or zero if the driver did not report all creation times.
+func creationTimeSpread(results []emulatedSnapshotResult) time.Duration {
+	var first, last time.Time
+	for _, r := range results {
+		if r.creationTime.IsZero() {
+			return 0
+		}
+		if first.IsZero() || r.creationTime.Before(first) {
+			first = r.creationTime
+		}
+		if r.creationTime.After(last) {
+			last = r.creationTime
+		}
+	}
+	return last.Sub(first)
+}
+
+// deleteSnapshots deletes the snapshots of a failed emulated group snapshot.
+// Snapshots that could not be deleted are taken over by the next attempt,
+// which creates them with the same names.
+func (gs *emulatedGroupSnapshot) deleteSnapshots(ctx context.Context, groupSnapshotName string, results []emulatedSnapshotResult, snapshotterCredentials map[string]string) {
+	for _, r := range results {
+		if r.err != nil || r.snapshotID == "" {
+			continue
+		}
+		if err := gs.snapshotter.DeleteSnapshot(ctx, r.snapshotID, snapshotterCredentials); err != nil {
+			klog.Errorf("failed to delete snapshot %s of failed emulated group snapshot %s: %v", r.snapshotID, groupSnapshotName, err)
+		}
+	}
+}
+
+func (gs *emulatedGroupSnapshot) DeleteGroupSnapshot(ctx context.Context, groupSnapshotID string, snapshotIDs []string, snapshotterCredentials map[string]string) error {
+	klog.V(5).Infof("Emulated DeleteGroupSnapshot: %s", groupSnapshotID)
+	var firstErr error
+	for _, snapshotID := range snapshotIDs {
+		if err := gs.snapshotter.DeleteSnapshot(ctx, snapshotID, snapshotterCredentials); err != nil {
+			klog.Errorf("failed to delete snapshot %s of emulated group snapshot %s: %v", snapshotID, groupSnapshotID, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+func (gs *emulatedGroupSnapshot) GetGroupSnapshotStatus(ctx context.Context, groupSnapshotID string, snapshotIDs []string, snapshotterCredentials map[string]string) (bool, time.Time, error) {
+	klog.V(5).Infof("Emulated GetGroupSnapshotStatus: %s", groupSnapshotID)
+	var creationTime time.Time
+	readyToUse := true
+	for _, snapshotID := range snapshotIDs {
+		ready, timestamp, _, _, err := gs.snapshotter.GetSnapshotStatus(ctx, snapshotID, snapshotterCredentials)
+		if err != nil {
+			return false, time.Time{}, err
+		}
+		if timestamp.After(creationTime) {
+			creationTime = timestamp
+		}
+		readyToUse = readyToUse && ready
+	}
+	return readyToUse, creationTime, nil
+}
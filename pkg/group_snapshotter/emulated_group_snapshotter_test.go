@@ -0,0 +1,290 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package group_snapshotter
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/snapshotter"
+)
+
+// fakeSnapshotter keeps snapshots in memory. Snapshots of the volumes in
+// errors fail, those of the volumes in latencies are delayed and those of
+// the volumes in creationTimes are cut at the given time.
+type fakeSnapshotter struct {
+	snapshotter.Snapshotter
+
+	mutex         sync.Mutex
+	snapshots     map[string]string
+	ready         map[string]bool
+	errors        map[string]error
+	latencies     map[string]time.Duration
+	creationTimes map[string]time.Time
+	deleted       []string
+}
+
+func newFakeSnapshotter() *fakeSnapshotter {
+	return &fakeSnapshotter{
+		snapshots:     map[string]string{},
+		ready:         map[string]bool{},
+		errors:        map[string]error{},
+		latencies:     map[string]time.Duration{},
+		creationTimes: map[string]time.Time{},
+	}
+}
+
+func (f *fakeSnapshotter) CreateSnapshot(ctx context.Context, snapshotName string, volumeHandle string, parameters map[string]string, snapshotterCredentials map[string]string) (string, string, time.Time, int64, bool, error) {
+	f.mutex.Lock()
+	err, latency, creationTime := f.errors[volumeHandle], f.latencies[volumeHandle], f.creationTimes[volumeHandle]
+	f.mutex.Unlock()
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-ctx.Done():
+			return "", "", time.Time{}, 0, false, status.FromContextError(ctx.Err()).Err()
+		}
+	}
+	if err != nil {
+		return "", "", time.Time{}, 0, false, err
+	}
+	if creationTime.IsZero() {
+		creationTime = time.Now()
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	id := "id-" + snapshotName
+	f.snapshots[id] = volumeHandle
+	return "driver", id, creationTime, 1, f.ready[volumeHandle], nil
+}
+
+func (f *fakeSnapshotter) DeleteSnapshot(ctx context.Context, snapshotID string, snapshotterCredentials map[string]string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if err := f.errors[snapshotID]; err != nil {
+		return err
+	}
+	delete(f.snapshots, snapshotID)
+	f.deleted = append(f.deleted, snapshotID)
+	return nil
+}
+
+func (f *fakeSnapshotter) GetSnapshotStatus(ctx context.Context, snapshotID string, snapshotterListCredentials map[string]string) (bool, time.Time, int64, string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	volumeHandle, found := f.snapshots[snapshotID]
+	if !found {
+		return false, time.Time{}, 0, "", status.Error(codes.NotFound, "not found")
+	}
+	return f.ready[volumeHandle], time.Unix(100, 0), 1, "", nil
+}
+
+func TestEmulatedCreateGroupSnapshot(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name          string
+		errors        map[string]error
+		latencies     map[string]time.Duration
+		creationTimes map[string]time.Time
+		expectCode    codes.Code
+		expectDeleted int
+	}{
+		{
+			name: "12-1 - all volumes are snapshotted",
+		},
+		{
+			name:          "12-2 - a failed snapshot fails the group and deletes the others",
+			errors:        map[string]error{"vol-2": status.Error(codes.InvalidArgument, "invalid")},
+			expectCode:    codes.InvalidArgument,
+			expectDeleted: 2,
+		},
+		{
+			name:          "12-3 - a snapshot outside the window fails the group",
+			latencies:     map[string]time.Duration{"vol-3": time.Second},
+			expectCode:    codes.DeadlineExceeded,
+			expectDeleted: 2,
+		},
+		{
+			name:          "12-4 - snapshots cut too far apart fail the group",
+			creationTimes: map[string]time.Time{"vol-1": now.Add(-time.Hour), "vol-2": now, "vol-3": now},
+			expectCode:    codes.Aborted,
+			expectDeleted: 3,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := newFakeSnapshotter()
+			for volume, err := range test.errors {
+				fake.errors[volume] = err
+			}
+			for volume, latency := range test.latencies {
+				fake.latencies[volume] = latency
+			}
+			for volume, creationTime := range test.creationTimes {
+				fake.creationTimes[volume] = creationTime
+			}
+			gs := NewEmulatedGroupSnapshotter(fake, 100*time.Millisecond)
+			if !IsEmulated(gs) {
+				t.Errorf("expected an emulated group snapshotter")
+			}
+
+			driverName, groupSnapshotID, snapshots, _, readyToUse, err := gs.CreateGroupSnapshot(context.Background(), "groupsnapshot-1", []string{"vol-1", "vol-2", "vol-3"}, nil, nil)
+			if test.expectCode != codes.OK {
+				if status.Code(err) != test.expectCode {
+					t.Errorf("expected error code %s, got %v", test.expectCode, err)
+				}
+				if len(fake.deleted) != test.expectDeleted || len(fake.snapshots) != 0 {
+					t.Errorf("expected %d deleted snapshots and none left, got %v and %v", test.expectDeleted, fake.deleted, fake.snapshots)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if driverName != "driver" || groupSnapshotID != "groupsnapshot-1" || readyToUse {
+				t.Errorf("unexpected result %s %s %v", driverName, groupSnapshotID, readyToUse)
+			}
+			var volumes []string
+			for _, snapshot := range snapshots {
+				volumes = append(volumes, snapshot.SourceVolumeId)
+				if snapshot.GroupSnapshotId != groupSnapshotID || fake.snapshots[snapshot.SnapshotId] != snapshot.SourceVolumeId {
+					t.Errorf("unexpected snapshot %+v", snapshot)
+				}
+			}
+			sort.Strings(volumes)
+			if len(volumes) != 3 || volumes[0] != "vol-1" || volumes[2] != "vol-3" {
+				t.Errorf("expected a snapshot of each volume, got %v", volumes)
+			}
+
+			// A retry takes the same snapshots.
+			_, _, again, _, _, err := gs.CreateGroupSnapshot(context.Background(), "groupsnapshot-1", []string{"vol-1", "vol-2", "vol-3"}, nil, nil)
+			if err != nil || len(fake.snapshots) != 3 || !sameSnapshotIDs(snapshots, again) {
+				t.Errorf("expected an idempotent retry, got %v %v", again, err)
+			}
+		})
+	}
+}
+
+func TestEmulatedCreateGroupSnapshotLimiter(t *testing.T) {
+	fake := newFakeSnapshotter()
+	volumes := []string{"vol-1", "vol-2", "vol-3", "vol-4"}
+	for _, volume := range volumes {
+		fake.latencies[volume] = 10 * time.Millisecond
+	}
+	gs := NewEmulatedGroupSnapshotter(fake, time.Second)
+
+	// A limiter with a single slot serializes the per-volume calls.
+	slot := make(chan struct{}, 1)
+	var mutex sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	ctx := WithCreateSnapshotLimiter(context.Background(), func(ctx context.Context) (func(), error) {
+		select {
+		case slot <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		mutex.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mutex.Unlock()
+		return func() {
+			mutex.Lock()
+			inFlight--
+			mutex.Unlock()
+			<-slot
+		}, nil
+	})
+	if _, _, snapshots, _, _, err := gs.CreateGroupSnapshot(ctx, "groupsnapshot-1", volumes, nil, nil); err != nil || len(snapshots) != len(volumes) {
+		t.Fatalf("expected %d snapshots, got %v %v", len(volumes), snapshots, err)
+	}
+	if maxInFlight != 1 {
+		t.Errorf("expected at most 1 CreateSnapshot call in flight, got %d", maxInFlight)
+	}
+
+	// Calls that cannot get a slot within the window fail the group.
+	slot <- struct{}{}
+	_, _, _, _, _, err := NewEmulatedGroupSnapshotter(fake, 50*time.Millisecond).CreateGroupSnapshot(ctx, "groupsnapshot-2", volumes, nil, nil)
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("expected error code %s, got %v", codes.DeadlineExceeded, err)
+	}
+}
+
+func TestEmulatedGroupSnapshotStatusAndDeletion(t *testing.T) {
+	fake := newFakeSnapshotter()
+	fake.ready["vol-1"] = true
+	gs := NewEmulatedGroupSnapshotter(fake, time.Second)
+	_, groupSnapshotID, snapshots, _, _, err := gs.CreateGroupSnapshot(context.Background(), "groupsnapshot-1", []string{"vol-1", "vol-2"}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, snapshot := range snapshots {
+		ids = append(ids, snapshot.SnapshotId)
+	}
+
+	readyToUse, creationTime, err := gs.GetGroupSnapshotStatus(context.Background(), groupSnapshotID, ids, nil)
+	if err != nil || readyToUse || !creationTime.Equal(time.Unix(100, 0)) {
+		t.Errorf("expected a group that is not ready, got %v %v %v", readyToUse, creationTime, err)
+	}
+	fake.ready["vol-2"] = true
+	if readyToUse, _, err := gs.GetGroupSnapshotStatus(context.Background(), groupSnapshotID, ids, nil); err != nil || !readyToUse {
+		t.Errorf("expected a ready group, got %v %v", readyToUse, err)
+	}
+
+	fake.errors[ids[0]] = status.Error(codes.Internal, "internal")
+	if err := gs.DeleteGroupSnapshot(context.Background(), groupSnapshotID, ids, nil); status.Code(err) != codes.Internal {
+		t.Errorf("expected the first error, got %v", err)
+	}
+	if len(fake.snapshots) != 1 {
+		t.Errorf("expected the other snapshot to be deleted, got %v", fake.snapshots)
+	}
+	delete(fake.errors, ids[0])
+	if err := gs.DeleteGroupSnapshot(context.Background(), groupSnapshotID, ids, nil); err != nil || len(fake.snapshots) != 0 {
+		t.Errorf("expected all snapshots to be deleted, got %v %v", fake.snapshots, err)
+	}
+}
+
+func TestEmulatedSnapshotName(t *testing.T) {
+	name := emulatedSnapshotName("groupsnapshot-1", "vol-1")
+	if len(name) != len("groupsnapshot-1")+13 || name != emulatedSnapshotName("groupsnapshot-1", "vol-1") {
+		t.Errorf("unexpected name %s", name)
+	}
+	if name == emulatedSnapshotName("groupsnapshot-1", "vol-2") {
+		t.Errorf("expected different names for different volumes")
+	}
+}
+
+func sameSnapshotIDs(a, b []*csi.Snapshot) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].SnapshotId != b[i].SnapshotId {
+			return false
+		}
+	}
+	return true
+}
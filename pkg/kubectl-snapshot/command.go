@@ -0,0 +1,169 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package kubectlsnapshot implements kubectl-snapshot, a kubectl plugin to inspect and
+// operate on VolumeSnapshots, VolumeGroupSnapshots and their contents.
+package kubectlsnapshot
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	clientset "github.com/kubernetes-csi/external-snapshotter/client/v8/clientset/versioned"
+)
+
+const (
+	kindVolumeSnapshot             = "VolumeSnapshot"
+	kindVolumeSnapshotContent      = "VolumeSnapshotContent"
+	kindVolumeGroupSnapshot        = "VolumeGroupSnapshot"
+	kindVolumeGroupSnapshotContent = "VolumeGroupSnapshotContent"
+	kindPersistentVolumeClaim      = "PersistentVolumeClaim"
+	kindPersistentVolume           = "PersistentVolume"
+	kindVolumeSnapshotClass        = "VolumeSnapshotClass"
+	kindVolumeGroupSnapshotClass   = "VolumeGroupSnapshotClass"
+
+	notFound = "not found"
+)
+
+// kindAliases maps the names accepted on the command line to the kinds of the objects.
+var kindAliases = map[string]string{
+	"volumesnapshot":              kindVolumeSnapshot,
+	"volumesnapshots":             kindVolumeSnapshot,
+	"vs":                          kindVolumeSnapshot,
+	"volumesnapshotcontent":       kindVolumeSnapshotContent,
+	"volumesnapshotcontents":      kindVolumeSnapshotContent,
+	"vsc":                         kindVolumeSnapshotContent,
+	"volumegroupsnapshot":         kindVolumeGroupSnapshot,
+	"volumegroupsnapshots":        kindVolumeGroupSnapshot,
+	"vgs":                         kindVolumeGroupSnapshot,
+	"volumegroupsnapshotcontent":  kindVolumeGroupSnapshotContent,
+	"volumegroupsnapshotcontents": kindVolumeGroupSnapshotContent,
+	"vgsc":                        kindVolumeGroupSnapshotContent,
+}
+
+// Options holds the clients and the streams shared by the commands.
+type Options struct {
+	KubeClient     kubernetes.Interface
+	SnapshotClient clientset.Interface
+	// Namespace is the namespace of the VolumeSnapshots, VolumeGroupSnapshots
+	// and PersistentVolumeClaims given on the command line.
+	Namespace string
+
+	Out    io.Writer
+	ErrOut io.Writer
+}
+
+// NewCommand returns the root command of the plugin. The clients of the options
+// are created from the kubeconfig flags unless they are already set.
+func NewCommand(o *Options) *cobra.Command {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	overrides := &clientcmd.ConfigOverrides{}
+
+	cmd := &cobra.Command{
+		Use:   "kubectl-snapshot",
+		Short: "Inspect and operate on volume snapshots",
+		Annotations: map[string]string{
+			cobra.CommandDisplayNameAnnotation: "kubectl snapshot",
+		},
+		SilenceUsage: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return o.complete(clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides))
+		},
+	}
+	cmd.SetOut(o.Out)
+	cmd.SetErr(o.ErrOut)
+	cmd.PersistentFlags().StringVar(&loadingRules.ExplicitPath, "kubeconfig", "", "Path to the kubeconfig file.")
+	clientcmd.BindOverrideFlags(overrides, cmd.PersistentFlags(), clientcmd.RecommendedConfigOverrideFlags(""))
+
+	cmd.AddCommand(
+		newTreeCommand(o),
+		newDescribeCommand(o),
+		newRestoreCommand(o),
+		newWaitCommand(o),
+		newDoctorCommand(o),
+		newMigrateDriverCommand(o),
+		newExportCommand(o),
+		newImportCommand(o),
+	)
+	return cmd
+}
+
+// complete creates the clients and reads the namespace from the client configuration.
+func (o *Options) complete(config clientcmd.ClientConfig) error {
+	if o.KubeClient != nil && o.SnapshotClient != nil {
+		return nil
+	}
+	namespace, _, err := config.Namespace()
+	if err != nil {
+		return err
+	}
+	restConfig, err := config.ClientConfig()
+	if err != nil {
+		return err
+	}
+	if o.KubeClient, err = kubernetes.NewForConfig(restConfig); err != nil {
+		return fmt.Errorf("error building kubernetes clientset: %w", err)
+	}
+	if o.SnapshotClient, err = clientset.NewForConfig(restConfig); err != nil {
+		return fmt.Errorf("error building snapshot clientset: %w", err)
+	}
+	o.Namespace = namespace
+	return nil
+}
+
+// parseObject parses an object given as "KIND/NAME" on the command line. A bare
+// "NAME" is an object of defaultKind, unless defaultKind is empty.
+func parseObject(arg, defaultKind string) (string, string, error) {
+	if kind, name, found := strings.Cut(arg, "/"); found {
+		k, err := parseKind(kind)
+		return k, name, err
+	}
+	if defaultKind == "" {
+		return "", "", fmt.Errorf("expected KIND/NAME, got %q", arg)
+	}
+	return defaultKind, arg, nil
+}
+
+// parseKind returns the kind of the objects named kind on the command line.
+func parseKind(kind string) (string, error) {
+	if k, ok := kindAliases[strings.ToLower(kind)]; ok {
+		return k, nil
+	}
+	return "", fmt.Errorf("unknown object type %q, expected one of volumesnapshot (vs), volumesnapshotcontent (vsc), volumegroupsnapshot (vgs) or volumegroupsnapshotcontent (vgsc)", kind)
+}
+
+// objectStatus summarizes the status of a snapshot or content in a few words.
+func objectStatus(deletionTimestamp *metav1.Time, readyToUse *bool, snapshotError *crdv1.VolumeSnapshotError) string {
+	switch {
+	case deletionTimestamp != nil:
+		return "deleting"
+	case readyToUse != nil && *readyToUse:
+		return "ready"
+	case snapshotError != nil && snapshotError.Message != nil:
+		return "error: " + *snapshotError.Message
+	case snapshotError != nil:
+		return "error"
+	default:
+		return "not ready"
+	}
+}
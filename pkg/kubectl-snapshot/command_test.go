@@ -0,0 +1,178 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectlsnapshot
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	crdv1beta1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumegroupsnapshot/v1beta1"
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	"github.com/kubernetes-csi/external-snapshotter/client/v8/clientset/versioned/fake"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+)
+
+const testNamespace = "default"
+
+var (
+	True  = true
+	False = false
+)
+
+// runCommand runs the plugin with the given arguments against fake clients holding the objects.
+func runCommand(t *testing.T, kubeObjects, snapshotObjects []runtime.Object, args ...string) (string, string, error) {
+	t.Helper()
+	var out, errOut bytes.Buffer
+	o := &Options{
+		KubeClient:     kubefake.NewSimpleClientset(kubeObjects...),
+		SnapshotClient: fake.NewSimpleClientset(snapshotObjects...),
+		Namespace:      testNamespace,
+		Out:            &out,
+		ErrOut:         &errOut,
+	}
+	cmd := NewCommand(o)
+	cmd.SetArgs(args)
+	err := cmd.ExecuteContext(context.Background())
+	return out.String(), errOut.String(), err
+}
+
+func newSnapshot(name, claimName, contentName string, ready *bool, restoreSize string) *crdv1.VolumeSnapshot {
+	snapshot := &crdv1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace, UID: types.UID("uid-" + name)},
+		Spec: crdv1.VolumeSnapshotSpec{
+			Source: crdv1.VolumeSnapshotSource{PersistentVolumeClaimName: &claimName},
+		},
+		Status: &crdv1.VolumeSnapshotStatus{ReadyToUse: ready},
+	}
+	if contentName != "" {
+		snapshot.Status.BoundVolumeSnapshotContentName = &contentName
+	}
+	if restoreSize != "" {
+		size := resource.MustParse(restoreSize)
+		snapshot.Status.RestoreSize = &size
+	}
+	return snapshot
+}
+
+func newContent(name, snapshotName, snapshotHandle string, ready *bool) *crdv1.VolumeSnapshotContent {
+	return &crdv1.VolumeSnapshotContent{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: crdv1.VolumeSnapshotContentSpec{
+			VolumeSnapshotRef: v1.ObjectReference{Kind: kindVolumeSnapshot, Namespace: testNamespace, Name: snapshotName, UID: types.UID("uid-" + snapshotName)},
+			DeletionPolicy:    crdv1.VolumeSnapshotContentDelete,
+			Driver:            "csi-mock-plugin",
+		},
+		Status: &crdv1.VolumeSnapshotContentStatus{SnapshotHandle: &snapshotHandle, ReadyToUse: ready},
+	}
+}
+
+func newClaim(name, volumeName string) *v1.PersistentVolumeClaim {
+	storageClassName := "gold"
+	volumeMode := v1.PersistentVolumeFilesystem
+	return &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes:      []v1.PersistentVolumeAccessMode{v1.ReadWriteMany},
+			StorageClassName: &storageClassName,
+			VolumeMode:       &volumeMode,
+			VolumeName:       volumeName,
+		},
+		Status: v1.PersistentVolumeClaimStatus{Phase: v1.ClaimBound},
+	}
+}
+
+func newVolume(name, volumeHandle string) *v1.PersistentVolume {
+	return &v1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v1.PersistentVolumeSpec{
+			PersistentVolumeSource: v1.PersistentVolumeSource{
+				CSI: &v1.CSIPersistentVolumeSource{Driver: "csi-mock-plugin", VolumeHandle: volumeHandle},
+			},
+		},
+	}
+}
+
+func newGroupSnapshot(name, contentName string, ready *bool) *crdv1beta1.VolumeGroupSnapshot {
+	return &crdv1beta1.VolumeGroupSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace, UID: types.UID("uid-" + name)},
+		Status: &crdv1beta1.VolumeGroupSnapshotStatus{
+			BoundVolumeGroupSnapshotContentName: &contentName,
+			ReadyToUse:                          ready,
+		},
+	}
+}
+
+func newGroupSnapshotContent(name, groupSnapshotHandle string, ready *bool) *crdv1beta1.VolumeGroupSnapshotContent {
+	return &crdv1beta1.VolumeGroupSnapshotContent{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: crdv1beta1.VolumeGroupSnapshotContentSpec{
+			DeletionPolicy: crdv1.VolumeSnapshotContentDelete,
+			Driver:         "csi-mock-plugin",
+		},
+		Status: &crdv1beta1.VolumeGroupSnapshotContentStatus{
+			VolumeGroupSnapshotHandle: &groupSnapshotHandle,
+			ReadyToUse:                ready,
+		},
+	}
+}
+
+// withGroupSnapshotOwner makes the snapshot a member of the group snapshot.
+func withGroupSnapshotOwner(snapshot *crdv1.VolumeSnapshot, groupSnapshot *crdv1beta1.VolumeGroupSnapshot) *crdv1.VolumeSnapshot {
+	snapshot.OwnerReferences = append(snapshot.OwnerReferences, utils.BuildVolumeGroupSnapshotOwnerReference(groupSnapshot))
+	snapshot.Finalizers = append(snapshot.Finalizers, utils.VolumeSnapshotInGroupFinalizer)
+	return snapshot
+}
+
+func TestParseObject(t *testing.T) {
+	tests := []struct {
+		arg          string
+		defaultKind  string
+		expectedKind string
+		expectedName string
+		expectError  bool
+	}{
+		{arg: "snap", defaultKind: kindVolumeSnapshot, expectedKind: kindVolumeSnapshot, expectedName: "snap"},
+		{arg: "vgs/group", defaultKind: kindVolumeSnapshot, expectedKind: kindVolumeGroupSnapshot, expectedName: "group"},
+		{arg: "VolumeSnapshotContent/content", expectedKind: kindVolumeSnapshotContent, expectedName: "content"},
+		{arg: "snap", expectError: true},
+		{arg: "pvc/claim", defaultKind: kindVolumeSnapshot, expectError: true},
+	}
+	for _, test := range tests {
+		kind, name, err := parseObject(test.arg, test.defaultKind)
+		if test.expectError {
+			if err == nil {
+				t.Errorf("%s: expected error, got none", test.arg)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.arg, err)
+			continue
+		}
+		if kind != test.expectedKind || name != test.expectedName {
+			t.Errorf("%s: expected %s %s, got %s %s", test.arg, test.expectedKind, test.expectedName, kind, name)
+		}
+	}
+}
@@ -0,0 +1,329 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectlsnapshot
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+)
+
+// finalizerDescriptions explains the finalizers set by the snapshot controllers.
+var finalizerDescriptions = map[string]string{
+	utils.VolumeSnapshotContentFinalizer:      "The content is bound to a VolumeSnapshot. It is removed once the snapshot on the storage system is deleted, or retained per the deletion policy.",
+	utils.VolumeSnapshotBoundFinalizer:        "The snapshot is bound to a VolumeSnapshotContent. It is removed once the content is marked for deletion.",
+	utils.VolumeSnapshotAsSourceFinalizer:     "The snapshot is the data source of a PersistentVolumeClaim being provisioned. It is removed once the claim is bound.",
+	utils.VolumeSnapshotInGroupFinalizer:      "The snapshot is a member of a VolumeGroupSnapshot. It is removed when the group snapshot is deleted.",
+	utils.VolumeGroupSnapshotContentFinalizer: "The content is bound to a VolumeGroupSnapshot. It is removed once the group snapshot on the storage system is deleted, or retained per the deletion policy.",
+	utils.VolumeGroupSnapshotBoundFinalizer:   "The group snapshot is bound to a VolumeGroupSnapshotContent. It is removed once its member snapshots are deleted and the content is marked for deletion.",
+}
+
+// annotationDescriptions explains the annotations set by the snapshot controllers and by users.
+var annotationDescriptions = map[string]string{
+	utils.AnnVolumeSnapshotBeingCreated:       "The CSI driver was asked to create the snapshot and has not answered with a success or a final error yet. The content cannot be deleted until it does.",
+	utils.AnnVolumeSnapshotBeingDeleted:       "The VolumeSnapshot is being deleted. The sidecar deletes the snapshot on the storage system if the deletion policy is Delete, then removes the finalizer of the content.",
+	utils.AnnVolumeSnapshotContentAbandon:     "The finalizer of the content is removed once it is being deleted, without the snapshot being deleted on the storage system.",
+	utils.AnnVolumeGroupSnapshotBeingCreated:  "The CSI driver was asked to create the group snapshot and has not answered with a success or a final error yet. The content cannot be deleted until it does.",
+	utils.AnnVolumeGroupSnapshotBeingDeleted:  "The VolumeGroupSnapshot is being deleted. The sidecar deletes the group snapshot on the storage system if the deletion policy is Delete, then removes the finalizer of the content.",
+	utils.AnnDeletionSecretRefName:            "Name of the secret passed to the CSI driver to delete the snapshot.",
+	utils.AnnDeletionSecretRefNamespace:       "Namespace of the secret passed to the CSI driver to delete the snapshot.",
+	utils.AnnDeletionCredentials:              "Credentials provider passed to the CSI driver to delete the snapshot.",
+	utils.AnnDeletionGroupSecretRefName:       "Name of the secret passed to the CSI driver to delete the group snapshot.",
+	utils.AnnDeletionGroupSecretRefNamespace:  "Namespace of the secret passed to the CSI driver to delete the group snapshot.",
+	utils.AnnDeletionGroupCredentials:         "Credentials provider passed to the CSI driver to delete the group snapshot.",
+	utils.VolumeGroupSnapshotHandleAnnotation: "The content is a member of the group snapshot with this handle. The snapshot is cut by the CSI driver with the group, not on its own.",
+	utils.AnnTraceParent:                      "Trace context of the creation of the snapshot.",
+	utils.AnnVolumeSnapshotScheduledTime:      "Scheduled time of the VolumeSnapshotSchedule run that created the snapshot.",
+	utils.AnnVolumeSnapshotExpiresAt:          "The snapshot is deleted at this time.",
+	utils.AnnVolumeSnapshotTTL:                "The snapshot is deleted once this duration has passed since it was cut.",
+	utils.AnnVolumeSnapshotHook:               "VolumeSnapshotHook run before and after the snapshot is taken.",
+	utils.AnnVolumeSnapshotTransferredFrom:    "The content was transferred from this VolumeSnapshot of another namespace.",
+	utils.AnnImportedFromVolume:               "The snapshot was imported from the storage system. The value is the ID of its source volume.",
+}
+
+func newDescribeCommand(o *Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "describe (KIND NAME | KIND/NAME)",
+		Short: "Show the details of a snapshot or content, explaining its finalizers and annotations",
+		Long: "Show the details of a VolumeSnapshot (vs), VolumeSnapshotContent (vsc), VolumeGroupSnapshot (vgs) or " +
+			"VolumeGroupSnapshotContent (vgsc), explaining the finalizers and annotations set by the snapshot controllers.",
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var kind, name string
+			var err error
+			if len(args) == 2 {
+				kind, err = parseKind(args[0])
+				name = args[1]
+			} else {
+				kind, name, err = parseObject(args[0], "")
+			}
+			if err != nil {
+				return err
+			}
+			return o.runDescribe(cmd.Context(), kind, name)
+		},
+	}
+}
+
+// describeWriter writes aligned "Field: value" lines.
+type describeWriter struct {
+	w *tabwriter.Writer
+}
+
+func (d *describeWriter) field(name string, value interface{}) {
+	fmt.Fprintf(d.w, "%s:\t%v\n", name, value)
+}
+
+func (d *describeWriter) optional(name string, value interface{}) {
+	switch v := value.(type) {
+	case *string:
+		if v != nil {
+			d.field(name, *v)
+		}
+	case *bool:
+		if v != nil {
+			d.field(name, *v)
+		}
+	case *int64:
+		if v != nil {
+			d.field(name, *v)
+		}
+	case *metav1.Time:
+		if v != nil {
+			d.field(name, v.Format(time.RFC3339))
+		}
+	default:
+		d.field(name, v)
+	}
+}
+
+func (d *describeWriter) objectMeta(meta *metav1.ObjectMeta) {
+	d.field("Name", meta.Name)
+	if meta.Namespace != "" {
+		d.field("Namespace", meta.Namespace)
+	}
+	if meta.DeletionTimestamp != nil {
+		d.field("Deletion Requested", meta.DeletionTimestamp.Format(time.RFC3339))
+	}
+}
+
+func (d *describeWriter) snapshotError(snapshotError *crdv1.VolumeSnapshotError) {
+	if snapshotError == nil {
+		return
+	}
+	var message string
+	if snapshotError.Message != nil {
+		message = *snapshotError.Message
+	}
+	if snapshotError.Time != nil {
+		message = fmt.Sprintf("%s (%s)", message, snapshotError.Time.Format(time.RFC3339))
+	}
+	d.field("Error", message)
+}
+
+// finalizersAndAnnotations lists the finalizers and the annotations of the object,
+// followed by their explanation when they are known.
+func (d *describeWriter) finalizersAndAnnotations(meta *metav1.ObjectMeta) {
+	w := d.w
+	fmt.Fprintln(w, "Finalizers:")
+	for _, finalizer := range meta.Finalizers {
+		fmt.Fprintf(w, "  %s\n", finalizer)
+		if description, ok := finalizerDescriptions[finalizer]; ok {
+			fmt.Fprintf(w, "    %s\n", description)
+		}
+	}
+	fmt.Fprintln(w, "Annotations:")
+	keys := make([]string, 0, len(meta.Annotations))
+	for key := range meta.Annotations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(w, "  %s=%s\n", key, meta.Annotations[key])
+		if description, ok := annotationDescriptions[key]; ok {
+			fmt.Fprintf(w, "    %s\n", description)
+		}
+	}
+}
+
+func (d *describeWriter) conditions(conditions []metav1.Condition) {
+	if len(conditions) == 0 {
+		return
+	}
+	fmt.Fprintln(d.w, "Conditions:")
+	fmt.Fprintln(d.w, "  Type\tStatus\tReason\tMessage")
+	for _, condition := range conditions {
+		fmt.Fprintf(d.w, "  %s\t%s\t%s\t%s\n", condition.Type, condition.Status, condition.Reason, condition.Message)
+	}
+}
+
+func (o *Options) runDescribe(ctx context.Context, kind, name string) error {
+	d := &describeWriter{w: tabwriter.NewWriter(o.Out, 0, 8, 2, ' ', 0)}
+	var err error
+	switch kind {
+	case kindVolumeSnapshot:
+		err = o.describeSnapshot(ctx, d, name)
+	case kindVolumeSnapshotContent:
+		err = o.describeContent(ctx, d, name)
+	case kindVolumeGroupSnapshot:
+		err = o.describeGroupSnapshot(ctx, d, name)
+	case kindVolumeGroupSnapshotContent:
+		err = o.describeGroupSnapshotContent(ctx, d, name)
+	}
+	if err != nil {
+		return err
+	}
+	return d.w.Flush()
+}
+
+func (o *Options) describeSnapshot(ctx context.Context, d *describeWriter, name string) error {
+	snapshot, err := o.SnapshotClient.SnapshotV1().VolumeSnapshots(o.Namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get volume snapshot %s: %w", name, err)
+	}
+	d.objectMeta(&snapshot.ObjectMeta)
+	d.optional("Source PersistentVolumeClaim", snapshot.Spec.Source.PersistentVolumeClaimName)
+	d.optional("Source VolumeSnapshotContent", snapshot.Spec.Source.VolumeSnapshotContentName)
+	d.optional("VolumeSnapshotClass", snapshot.Spec.VolumeSnapshotClassName)
+	if group := groupSnapshotName(snapshot); group != "" {
+		d.field(kindVolumeGroupSnapshot, group)
+	}
+	status := snapshot.Status
+	if status == nil {
+		status = &crdv1.VolumeSnapshotStatus{}
+	}
+	d.optional("Bound VolumeSnapshotContent", status.BoundVolumeSnapshotContentName)
+	d.optional("Creation Time", status.CreationTime)
+	d.field("Ready To Use", status.ReadyToUse != nil && *status.ReadyToUse)
+	if status.RestoreSize != nil {
+		d.field("Restore Size", status.RestoreSize.String())
+	}
+	d.snapshotError(status.Error)
+	d.finalizersAndAnnotations(&snapshot.ObjectMeta)
+	d.conditions(status.Conditions)
+	return nil
+}
+
+func (o *Options) describeContent(ctx context.Context, d *describeWriter, name string) error {
+	content, err := o.SnapshotClient.SnapshotV1().VolumeSnapshotContents().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get volume snapshot content %s: %w", name, err)
+	}
+	d.objectMeta(&content.ObjectMeta)
+	d.field("Driver", content.Spec.Driver)
+	d.field("Deletion Policy", content.Spec.DeletionPolicy)
+	d.field("VolumeSnapshot", fmt.Sprintf("%s/%s", content.Spec.VolumeSnapshotRef.Namespace, content.Spec.VolumeSnapshotRef.Name))
+	if content.Spec.VolumeSnapshotRef.UID != "" {
+		d.field("VolumeSnapshot UID", content.Spec.VolumeSnapshotRef.UID)
+	}
+	d.optional("VolumeSnapshotClass", content.Spec.VolumeSnapshotClassName)
+	d.optional("Source Volume Handle", content.Spec.Source.VolumeHandle)
+	d.optional("Source Snapshot Handle", content.Spec.Source.SnapshotHandle)
+	if content.Spec.SourceVolumeMode != nil {
+		d.field("Source Volume Mode", *content.Spec.SourceVolumeMode)
+	}
+	status := content.Status
+	if status == nil {
+		status = &crdv1.VolumeSnapshotContentStatus{}
+	}
+	d.optional("Snapshot Handle", status.SnapshotHandle)
+	if status.CreationTime != nil {
+		d.field("Creation Time", time.Unix(0, *status.CreationTime).UTC().Format(time.RFC3339))
+	}
+	d.field("Ready To Use", status.ReadyToUse != nil && *status.ReadyToUse)
+	d.optional("Restore Size", status.RestoreSize)
+	d.optional("VolumeGroupSnapshot Handle", status.VolumeGroupSnapshotHandle)
+	d.snapshotError(status.Error)
+	d.finalizersAndAnnotations(&content.ObjectMeta)
+	d.conditions(status.Conditions)
+	return nil
+}
+
+func (o *Options) describeGroupSnapshot(ctx context.Context, d *describeWriter, name string) error {
+	groupSnapshot, err := o.SnapshotClient.GroupsnapshotV1beta1().VolumeGroupSnapshots(o.Namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get volume group snapshot %s: %w", name, err)
+	}
+	d.objectMeta(&groupSnapshot.ObjectMeta)
+	if groupSnapshot.Spec.Source.Selector != nil {
+		d.field("Selector", metav1.FormatLabelSelector(groupSnapshot.Spec.Source.Selector))
+	}
+	d.optional("Source VolumeGroupSnapshotContent", groupSnapshot.Spec.Source.VolumeGroupSnapshotContentName)
+	d.optional("VolumeGroupSnapshotClass", groupSnapshot.Spec.VolumeGroupSnapshotClassName)
+	if groupSnapshot.Status != nil {
+		status := groupSnapshot.Status
+		d.optional("Bound VolumeGroupSnapshotContent", status.BoundVolumeGroupSnapshotContentName)
+		d.optional("Creation Time", status.CreationTime)
+		d.field("Ready To Use", status.ReadyToUse != nil && *status.ReadyToUse)
+		d.snapshotError(status.Error)
+	} else {
+		d.field("Ready To Use", false)
+	}
+	d.finalizersAndAnnotations(&groupSnapshot.ObjectMeta)
+	if groupSnapshot.Status != nil {
+		d.conditions(groupSnapshot.Status.Conditions)
+	}
+	return nil
+}
+
+func (o *Options) describeGroupSnapshotContent(ctx context.Context, d *describeWriter, name string) error {
+	content, err := o.SnapshotClient.GroupsnapshotV1beta1().VolumeGroupSnapshotContents().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get volume group snapshot content %s: %w", name, err)
+	}
+	d.objectMeta(&content.ObjectMeta)
+	d.field("Driver", content.Spec.Driver)
+	d.field("Deletion Policy", content.Spec.DeletionPolicy)
+	d.field(kindVolumeGroupSnapshot, fmt.Sprintf("%s/%s", content.Spec.VolumeGroupSnapshotRef.Namespace, content.Spec.VolumeGroupSnapshotRef.Name))
+	if content.Spec.VolumeGroupSnapshotRef.UID != "" {
+		d.field("VolumeGroupSnapshot UID", content.Spec.VolumeGroupSnapshotRef.UID)
+	}
+	d.optional("VolumeGroupSnapshotClass", content.Spec.VolumeGroupSnapshotClassName)
+	if len(content.Spec.Source.VolumeHandles) > 0 {
+		d.field("Source Volume Handles", strings.Join(content.Spec.Source.VolumeHandles, ", "))
+	}
+	if handles := content.Spec.Source.GroupSnapshotHandles; handles != nil {
+		d.field("Source Group Snapshot Handle", handles.VolumeGroupSnapshotHandle)
+		d.field("Source Snapshot Handles", strings.Join(handles.VolumeSnapshotHandles, ", "))
+	}
+	if content.Status != nil {
+		status := content.Status
+		d.optional("Group Snapshot Handle", status.VolumeGroupSnapshotHandle)
+		d.optional("Creation Time", status.CreationTime)
+		d.field("Ready To Use", status.ReadyToUse != nil && *status.ReadyToUse)
+		for _, pair := range status.VolumeSnapshotHandlePairList {
+			d.field("Member Snapshot Handle", fmt.Sprintf("%s (volume %s)", pair.SnapshotHandle, pair.VolumeHandle))
+		}
+		d.snapshotError(status.Error)
+	} else {
+		d.field("Ready To Use", false)
+	}
+	d.finalizersAndAnnotations(&content.ObjectMeta)
+	if content.Status != nil {
+		d.conditions(content.Status.Conditions)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectlsnapshot
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+)
+
+func TestDescribe(t *testing.T) {
+	content := newContent("content-a", "snap-a", "handle-a", &False)
+	content.Finalizers = []string{utils.VolumeSnapshotContentFinalizer}
+	content.Annotations = map[string]string{
+		utils.AnnVolumeSnapshotBeingCreated: "yes",
+		"example.com/custom":                "value",
+	}
+	snapshot := newSnapshot("snap-a", "claim-a", "content-a", &False, "")
+	snapshot.Finalizers = []string{utils.VolumeSnapshotAsSourceFinalizer, utils.VolumeSnapshotBoundFinalizer}
+	snapshot.Status.Conditions = []metav1.Condition{{Type: "Ready", Status: metav1.ConditionFalse, Reason: "WaitingForReadyToUse"}}
+	groupSnapshot := newGroupSnapshot("group", "groupcontent", &True)
+	objects := []runtime.Object{content, snapshot, groupSnapshot, newGroupSnapshotContent("groupcontent", "group-handle", &True)}
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string
+	}{
+		{
+			name: "content",
+			args: []string{"describe", "vsc", "content-a"},
+			expected: []string{
+				"Name:",
+				"VolumeSnapshot:      default/snap-a",
+				"Snapshot Handle:     handle-a",
+				"  " + utils.VolumeSnapshotContentFinalizer + "\n    " + finalizerDescriptions[utils.VolumeSnapshotContentFinalizer],
+				"  " + utils.AnnVolumeSnapshotBeingCreated + "=yes\n    " + annotationDescriptions[utils.AnnVolumeSnapshotBeingCreated],
+				"  example.com/custom=value\n",
+			},
+		},
+		{
+			name: "snapshot",
+			args: []string{"describe", "volumesnapshot/snap-a"},
+			expected: []string{
+				"Source PersistentVolumeClaim:",
+				"Ready To Use:",
+				"  " + utils.VolumeSnapshotAsSourceFinalizer + "\n    " + finalizerDescriptions[utils.VolumeSnapshotAsSourceFinalizer],
+				"  " + utils.VolumeSnapshotBoundFinalizer + "\n    " + finalizerDescriptions[utils.VolumeSnapshotBoundFinalizer],
+				"Conditions:",
+				"WaitingForReadyToUse",
+			},
+		},
+		{
+			name:     "group snapshot",
+			args:     []string{"describe", "vgs", "group"},
+			expected: []string{"Bound VolumeGroupSnapshotContent:  groupcontent"},
+		},
+		{
+			name:     "group snapshot content",
+			args:     []string{"describe", "vgsc/groupcontent"},
+			expected: []string{"Group Snapshot Handle:", "group-handle"},
+		},
+	}
+	for _, test := range tests {
+		out, _, err := runCommand(t, nil, objects, test.args...)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		for _, expected := range test.expected {
+			if !strings.Contains(out, expected) {
+				t.Errorf("%s: expected output to contain %q, got:\n%s", test.name, expected, out)
+			}
+		}
+	}
+
+	for _, args := range [][]string{{"describe", "snap-a"}, {"describe", "pvc", "claim-a"}, {"describe", "vs", "missing"}} {
+		if _, _, err := runCommand(t, nil, objects, args...); err == nil {
+			t.Errorf("%v: expected error, got none", args)
+		}
+	}
+}
@@ -0,0 +1,627 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectlsnapshot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+
+	crdv1beta1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumegroupsnapshot/v1beta1"
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+)
+
+// Checks run by the doctor command.
+const (
+	checkBoundContentMissing              = "BoundContentMissing"
+	checkContentNotBoundToSnapshot        = "ContentNotBoundToSnapshot"
+	checkSnapshotFinalizerMissing         = "SnapshotFinalizerMissing"
+	checkSnapshotMissing                  = "SnapshotMissing"
+	checkSnapshotBoundToOtherContent      = "SnapshotBoundToOtherContent"
+	checkContentFinalizerMissing          = "ContentFinalizerMissing"
+	checkGroupSnapshotMissing             = "GroupSnapshotMissing"
+	checkGroupOwnershipMissing            = "GroupOwnershipMissing"
+	checkInGroupFinalizerMissing          = "InGroupFinalizerMissing"
+	checkGroupMembersMismatch             = "GroupMembersMismatch"
+	checkBoundGroupContentMissing         = "BoundGroupContentMissing"
+	checkGroupContentNotBoundToGroup      = "GroupContentNotBoundToGroup"
+	checkGroupSnapshotFinalizerMissing    = "GroupSnapshotFinalizerMissing"
+	checkGroupContentFinalizerMissing     = "GroupContentFinalizerMissing"
+	checkGroupSnapshotBoundToOtherContent = "GroupSnapshotBoundToOtherContent"
+	checkStalePVCFinalizer                = "StalePVCFinalizer"
+)
+
+// Finding is a broken invariant found by the doctor command.
+type Finding struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Check     string `json:"check"`
+	Message   string `json:"message"`
+	// Fixable is true when --fix knows a safe repair.
+	Fixable  bool   `json:"fixable"`
+	Fixed    bool   `json:"fixed,omitempty"`
+	FixError string `json:"fixError,omitempty"`
+
+	fix func(ctx context.Context) error
+}
+
+// DoctorReport is the output of the doctor command.
+type DoctorReport struct {
+	VolumeSnapshots             int       `json:"volumeSnapshots"`
+	VolumeSnapshotContents      int       `json:"volumeSnapshotContents"`
+	VolumeGroupSnapshots        int       `json:"volumeGroupSnapshots"`
+	VolumeGroupSnapshotContents int       `json:"volumeGroupSnapshotContents"`
+	PersistentVolumeClaims      int       `json:"persistentVolumeClaims"`
+	Findings                    []Finding `json:"findings"`
+}
+
+// doctorOptions are the flags of the doctor command.
+type doctorOptions struct {
+	allNamespaces bool
+	fix           bool
+	output        string
+}
+
+func newDoctorCommand(o *Options) *cobra.Command {
+	do := &doctorOptions{}
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the bindings and finalizers of the snapshot objects",
+		Long: "List the VolumeSnapshots, VolumeSnapshotContents, VolumeGroupSnapshots, VolumeGroupSnapshotContents and " +
+			"PersistentVolumeClaims and check the invariants the snapshot controllers rely on: the bindings between " +
+			"snapshots and contents, the finalizers, and the members of the group snapshots. The command fails when a " +
+			"problem is found.\n\n" +
+			"With --fix, the problems with a known safe repair are fixed: missing finalizers and group snapshot owner " +
+			"references are added, and the finalizer of PersistentVolumeClaims no snapshot is being created from is " +
+			"removed. Each object is fetched and checked again before it is updated, and the update fails if the object " +
+			"changed in the meantime.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.runDoctor(cmd.Context(), do)
+		},
+	}
+	cmd.Flags().BoolVarP(&do.allNamespaces, "all-namespaces", "A", false, "Check the objects of all the namespaces.")
+	cmd.Flags().BoolVar(&do.fix, "fix", false, "Repair the problems with a known safe fix.")
+	cmd.Flags().StringVarP(&do.output, "output", "o", "text", "Format of the report, text or json.")
+	return cmd
+}
+
+func (o *Options) runDoctor(ctx context.Context, do *doctorOptions) error {
+	if do.output != "text" && do.output != "json" {
+		return fmt.Errorf("unsupported output format %q, expected text or json", do.output)
+	}
+	namespace := o.Namespace
+	if do.allNamespaces {
+		namespace = metav1.NamespaceAll
+	}
+	report, err := o.diagnose(ctx, namespace)
+	if err != nil {
+		return err
+	}
+
+	if do.fix {
+		for i := range report.Findings {
+			finding := &report.Findings[i]
+			if finding.fix == nil {
+				continue
+			}
+			if err := finding.fix(ctx); err != nil {
+				finding.FixError = err.Error()
+			} else {
+				finding.Fixed = true
+			}
+		}
+	}
+
+	if do.output == "json" {
+		data, err := json.MarshalIndent(report, "", "    ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(o.Out, string(data))
+	} else {
+		printDoctorReport(o, report, do.fix)
+	}
+
+	var remaining int
+	for _, finding := range report.Findings {
+		if !finding.Fixed {
+			remaining++
+		}
+	}
+	if remaining > 0 {
+		return fmt.Errorf("found %d problems", remaining)
+	}
+	return nil
+}
+
+func printDoctorReport(o *Options, report *DoctorReport, fix bool) {
+	fmt.Fprintf(o.Out, "Checked %d VolumeSnapshots, %d VolumeSnapshotContents, %d VolumeGroupSnapshots, %d VolumeGroupSnapshotContents and %d PersistentVolumeClaims.\n",
+		report.VolumeSnapshots, report.VolumeSnapshotContents, report.VolumeGroupSnapshots, report.VolumeGroupSnapshotContents, report.PersistentVolumeClaims)
+	if len(report.Findings) == 0 {
+		fmt.Fprintln(o.Out, "No problems found.")
+		return
+	}
+
+	w := tabwriter.NewWriter(o.Out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "KIND\tNAME\tCHECK\tFIX\tMESSAGE")
+	var fixable int
+	for _, finding := range report.Findings {
+		name := finding.Name
+		if finding.Namespace != "" {
+			name = finding.Namespace + "/" + finding.Name
+		}
+		status := "-"
+		switch {
+		case finding.Fixed:
+			status = "fixed"
+		case finding.FixError != "":
+			status = "failed: " + finding.FixError
+		case finding.Fixable:
+			status = "fixable"
+			fixable++
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", finding.Kind, name, finding.Check, status, finding.Message)
+	}
+	w.Flush()
+	if !fix && fixable > 0 {
+		fmt.Fprintf(o.Out, "%d of the %d problems can be repaired with --fix.\n", fixable, len(report.Findings))
+	}
+}
+
+// doctor holds the objects checked by the doctor command.
+type doctor struct {
+	o                    *Options
+	snapshots            []crdv1.VolumeSnapshot
+	snapshotIndexer      cache.Indexer
+	contents             map[string]*crdv1.VolumeSnapshotContent
+	groupSnapshots       map[types.NamespacedName]*crdv1beta1.VolumeGroupSnapshot
+	groupSnapshotContent map[string]*crdv1beta1.VolumeGroupSnapshotContent
+	claims               []v1.PersistentVolumeClaim
+	report               *DoctorReport
+}
+
+// diagnose lists the objects of the namespace, or of all the namespaces, and checks them.
+func (o *Options) diagnose(ctx context.Context, namespace string) (*DoctorReport, error) {
+	d := &doctor{
+		o:        o,
+		contents: map[string]*crdv1.VolumeSnapshotContent{},
+		// The same index as the common snapshot controller maps the group
+		// snapshots to their members.
+		snapshotIndexer: cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{
+			utils.VolumeSnapshotParentGroupIndex: func(obj interface{}) ([]string, error) {
+				if key := utils.VolumeSnapshotParentGroupKeyFunc(obj.(*crdv1.VolumeSnapshot)); key != "" {
+					return []string{key}, nil
+				}
+				return nil, nil
+			},
+		}),
+		groupSnapshots:       map[types.NamespacedName]*crdv1beta1.VolumeGroupSnapshot{},
+		groupSnapshotContent: map[string]*crdv1beta1.VolumeGroupSnapshotContent{},
+		report:               &DoctorReport{Findings: []Finding{}},
+	}
+
+	snapshots, err := o.SnapshotClient.SnapshotV1().VolumeSnapshots(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list volume snapshots: %w", err)
+	}
+	d.snapshots = snapshots.Items
+	for i := range d.snapshots {
+		if err := d.snapshotIndexer.Add(&d.snapshots[i]); err != nil {
+			return nil, err
+		}
+	}
+	contents, err := o.SnapshotClient.SnapshotV1().VolumeSnapshotContents().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list volume snapshot contents: %w", err)
+	}
+	for i := range contents.Items {
+		d.contents[contents.Items[i].Name] = &contents.Items[i]
+	}
+	// The group snapshot CRDs are optional.
+	groupSnapshots, err := o.SnapshotClient.GroupsnapshotV1beta1().VolumeGroupSnapshots(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil && !apierrs.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to list volume group snapshots: %w", err)
+	}
+	if err == nil {
+		for i := range groupSnapshots.Items {
+			groupSnapshot := &groupSnapshots.Items[i]
+			d.groupSnapshots[types.NamespacedName{Namespace: groupSnapshot.Namespace, Name: groupSnapshot.Name}] = groupSnapshot
+		}
+		groupSnapshotContents, err := o.SnapshotClient.GroupsnapshotV1beta1().VolumeGroupSnapshotContents().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list volume group snapshot contents: %w", err)
+		}
+		for i := range groupSnapshotContents.Items {
+			d.groupSnapshotContent[groupSnapshotContents.Items[i].Name] = &groupSnapshotContents.Items[i]
+		}
+	}
+	claims, err := o.KubeClient.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list persistent volume claims: %w", err)
+	}
+	d.claims = claims.Items
+
+	for i := range d.snapshots {
+		d.checkSnapshot(&d.snapshots[i])
+		d.report.VolumeSnapshots++
+	}
+	for _, content := range sortedValues(d.contents) {
+		if namespace == metav1.NamespaceAll || content.Spec.VolumeSnapshotRef.Namespace == namespace {
+			d.checkContent(content)
+			d.report.VolumeSnapshotContents++
+		}
+	}
+	for _, key := range sortedKeys(d.groupSnapshots) {
+		d.checkGroupSnapshot(d.groupSnapshots[key])
+		d.report.VolumeGroupSnapshots++
+	}
+	for _, content := range sortedValues(d.groupSnapshotContent) {
+		if namespace == metav1.NamespaceAll || content.Spec.VolumeGroupSnapshotRef.Namespace == namespace {
+			d.checkGroupSnapshotContent(content)
+			d.report.VolumeGroupSnapshotContents++
+		}
+	}
+	for i := range d.claims {
+		d.checkClaim(&d.claims[i])
+		d.report.PersistentVolumeClaims++
+	}
+	return d.report, nil
+}
+
+func sortedValues[T any](objects map[string]*T) []*T {
+	names := make([]string, 0, len(objects))
+	for name := range objects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	values := make([]*T, 0, len(objects))
+	for _, name := range names {
+		values = append(values, objects[name])
+	}
+	return values
+}
+
+func sortedKeys[T any](objects map[types.NamespacedName]T) []types.NamespacedName {
+	keys := make([]types.NamespacedName, 0, len(objects))
+	for key := range objects {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+	return keys
+}
+
+func (d *doctor) add(kind string, meta *metav1.ObjectMeta, check string, fix func(ctx context.Context) error, format string, args ...interface{}) {
+	d.report.Findings = append(d.report.Findings, Finding{
+		Kind:      kind,
+		Namespace: meta.Namespace,
+		Name:      meta.Name,
+		Check:     check,
+		Message:   fmt.Sprintf(format, args...),
+		Fixable:   fix != nil,
+		fix:       fix,
+	})
+}
+
+func (d *doctor) checkSnapshot(snapshot *crdv1.VolumeSnapshot) {
+	var content *crdv1.VolumeSnapshotContent
+	if utils.IsBoundVolumeSnapshotContentNameSet(snapshot) {
+		contentName := *snapshot.Status.BoundVolumeSnapshotContentName
+		content = d.contents[contentName]
+		switch {
+		case content == nil:
+			d.add(kindVolumeSnapshot, &snapshot.ObjectMeta, checkBoundContentMissing, nil,
+				"bound to VolumeSnapshotContent %s, which does not exist", contentName)
+		case !utils.IsVolumeSnapshotRefSet(snapshot, content):
+			ref := content.Spec.VolumeSnapshotRef
+			d.add(kindVolumeSnapshot, &snapshot.ObjectMeta, checkContentNotBoundToSnapshot, nil,
+				"bound to VolumeSnapshotContent %s, which references VolumeSnapshot %s/%s with UID %q instead of %q",
+				contentName, ref.Namespace, ref.Name, ref.UID, snapshot.UID)
+			content = nil
+		}
+	}
+
+	// The common snapshot controller always adds the source finalizer, and adds the
+	// bound finalizer when the bound content has the Delete deletion policy.
+	addSourceFinalizer := utils.NeedToAddSnapshotAsSourceFinalizer(snapshot)
+	addBoundFinalizer := content != nil && utils.NeedToAddSnapshotBoundFinalizer(snapshot) && content.Spec.DeletionPolicy == crdv1.VolumeSnapshotContentDelete
+	if addBoundFinalizer || (addSourceFinalizer && utils.IsBoundVolumeSnapshotContentNameSet(snapshot)) {
+		var missing []string
+		if addSourceFinalizer {
+			missing = append(missing, utils.VolumeSnapshotAsSourceFinalizer)
+		}
+		if addBoundFinalizer {
+			missing = append(missing, utils.VolumeSnapshotBoundFinalizer)
+		}
+		d.add(kindVolumeSnapshot, &snapshot.ObjectMeta, checkSnapshotFinalizerMissing, d.snapshotFix(snapshot, func(s *crdv1.VolumeSnapshot) bool {
+			changed := false
+			if utils.NeedToAddSnapshotAsSourceFinalizer(s) {
+				s.Finalizers = append(s.Finalizers, utils.VolumeSnapshotAsSourceFinalizer)
+				changed = true
+			}
+			if addBoundFinalizer && utils.NeedToAddSnapshotBoundFinalizer(s) {
+				s.Finalizers = append(s.Finalizers, utils.VolumeSnapshotBoundFinalizer)
+				changed = true
+			}
+			return changed
+		}), "finalizers %v are missing", missing)
+	}
+
+	// Group membership
+	if utils.IsVolumeGroupSnapshotMember(snapshot) {
+		groupName := groupSnapshotName(snapshot)
+		if _, ok := d.groupSnapshots[types.NamespacedName{Namespace: snapshot.Namespace, Name: groupName}]; !ok {
+			d.add(kindVolumeSnapshot, &snapshot.ObjectMeta, checkGroupSnapshotMissing, nil,
+				"member of VolumeGroupSnapshot %s, which does not exist", groupName)
+		}
+		if snapshot.DeletionTimestamp == nil && !slices.Contains(snapshot.Finalizers, utils.VolumeSnapshotInGroupFinalizer) {
+			d.add(kindVolumeSnapshot, &snapshot.ObjectMeta, checkInGroupFinalizerMissing, d.snapshotFix(snapshot, func(s *crdv1.VolumeSnapshot) bool {
+				if s.DeletionTimestamp != nil || slices.Contains(s.Finalizers, utils.VolumeSnapshotInGroupFinalizer) {
+					return false
+				}
+				s.Finalizers = append(s.Finalizers, utils.VolumeSnapshotInGroupFinalizer)
+				return true
+			}), "member of VolumeGroupSnapshot %s without the %s finalizer", groupName, utils.VolumeSnapshotInGroupFinalizer)
+		}
+	} else if utils.NeedToAddVolumeGroupSnapshotOwnership(snapshot) {
+		groupName := *snapshot.Status.VolumeGroupSnapshotName
+		groupSnapshot, ok := d.groupSnapshots[types.NamespacedName{Namespace: snapshot.Namespace, Name: groupName}]
+		if !ok {
+			d.add(kindVolumeSnapshot, &snapshot.ObjectMeta, checkGroupSnapshotMissing, nil,
+				"member of VolumeGroupSnapshot %s, which does not exist", groupName)
+		} else {
+			d.add(kindVolumeSnapshot, &snapshot.ObjectMeta, checkGroupOwnershipMissing, d.snapshotFix(snapshot, func(s *crdv1.VolumeSnapshot) bool {
+				if !utils.NeedToAddVolumeGroupSnapshotOwnership(s) {
+					return false
+				}
+				s.OwnerReferences = append(s.OwnerReferences, utils.BuildVolumeGroupSnapshotOwnerReference(groupSnapshot))
+				return true
+			}), "member of VolumeGroupSnapshot %s without an owner reference to it", groupName)
+		}
+	}
+}
+
+func (d *doctor) checkContent(content *crdv1.VolumeSnapshotContent) {
+	ref := content.Spec.VolumeSnapshotRef
+	// Contents pre-bound to a snapshot are bound by the snapshot controller.
+	if ref.UID == "" {
+		return
+	}
+	if utils.NeedToAddContentFinalizer(content) {
+		d.add(kindVolumeSnapshotContent, &content.ObjectMeta, checkContentFinalizerMissing, d.contentFix(content, func(c *crdv1.VolumeSnapshotContent) bool {
+			if !utils.NeedToAddContentFinalizer(c) {
+				return false
+			}
+			c.Finalizers = append(c.Finalizers, utils.VolumeSnapshotContentFinalizer)
+			return true
+		}), "the %s finalizer is missing", utils.VolumeSnapshotContentFinalizer)
+	}
+
+	var snapshot *crdv1.VolumeSnapshot
+	if obj, exists, _ := d.snapshotIndexer.GetByKey(ref.Namespace + "/" + ref.Name); exists {
+		snapshot = obj.(*crdv1.VolumeSnapshot)
+	}
+	switch {
+	case snapshot == nil || snapshot.UID != ref.UID:
+		// A released content with the Retain deletion policy is expected to stay.
+		if content.Spec.DeletionPolicy == crdv1.VolumeSnapshotContentDelete && content.DeletionTimestamp == nil {
+			d.add(kindVolumeSnapshotContent, &content.ObjectMeta, checkSnapshotMissing, nil,
+				"bound to VolumeSnapshot %s/%s with UID %q, which does not exist, but not deleted despite its Delete deletion policy",
+				ref.Namespace, ref.Name, ref.UID)
+		}
+	case utils.IsBoundVolumeSnapshotContentNameSet(snapshot) && *snapshot.Status.BoundVolumeSnapshotContentName != content.Name:
+		d.add(kindVolumeSnapshotContent, &content.ObjectMeta, checkSnapshotBoundToOtherContent, nil,
+			"bound to VolumeSnapshot %s/%s, which is bound to VolumeSnapshotContent %s",
+			ref.Namespace, ref.Name, *snapshot.Status.BoundVolumeSnapshotContentName)
+	}
+}
+
+func (d *doctor) checkGroupSnapshot(groupSnapshot *crdv1beta1.VolumeGroupSnapshot) {
+	var content *crdv1beta1.VolumeGroupSnapshotContent
+	if utils.IsBoundVolumeGroupSnapshotContentNameSet(groupSnapshot) {
+		contentName := *groupSnapshot.Status.BoundVolumeGroupSnapshotContentName
+		content = d.groupSnapshotContent[contentName]
+		switch {
+		case content == nil:
+			d.add(kindVolumeGroupSnapshot, &groupSnapshot.ObjectMeta, checkBoundGroupContentMissing, nil,
+				"bound to VolumeGroupSnapshotContent %s, which does not exist", contentName)
+		case !utils.IsVolumeGroupSnapshotRefSet(groupSnapshot, content):
+			ref := content.Spec.VolumeGroupSnapshotRef
+			d.add(kindVolumeGroupSnapshot, &groupSnapshot.ObjectMeta, checkGroupContentNotBoundToGroup, nil,
+				"bound to VolumeGroupSnapshotContent %s, which references VolumeGroupSnapshot %s/%s with UID %q instead of %q",
+				contentName, ref.Namespace, ref.Name, ref.UID, groupSnapshot.UID)
+			content = nil
+		}
+	}
+
+	if content != nil && utils.NeedToAddGroupSnapshotBoundFinalizer(groupSnapshot) {
+		d.add(kindVolumeGroupSnapshot, &groupSnapshot.ObjectMeta, checkGroupSnapshotFinalizerMissing, d.groupSnapshotFix(groupSnapshot, func(g *crdv1beta1.VolumeGroupSnapshot) bool {
+			if !utils.NeedToAddGroupSnapshotBoundFinalizer(g) {
+				return false
+			}
+			g.Finalizers = append(g.Finalizers, utils.VolumeGroupSnapshotBoundFinalizer)
+			return true
+		}), "the %s finalizer is missing", utils.VolumeGroupSnapshotBoundFinalizer)
+	}
+
+	// Once the group snapshot is ready, it has a member snapshot per snapshot of the group.
+	if content != nil && content.Status != nil && content.Status.ReadyToUse != nil && *content.Status.ReadyToUse {
+		members, err := d.snapshotIndexer.ByIndex(utils.VolumeSnapshotParentGroupIndex,
+			utils.VolumeSnapshotParentGroupKeyFuncByComponents(types.NamespacedName{Namespace: groupSnapshot.Namespace, Name: groupSnapshot.Name}))
+		if err == nil && len(members) != len(content.Status.VolumeSnapshotHandlePairList) {
+			d.add(kindVolumeGroupSnapshot, &groupSnapshot.ObjectMeta, checkGroupMembersMismatch, nil,
+				"has %d member VolumeSnapshots, but VolumeGroupSnapshotContent %s has %d snapshots",
+				len(members), content.Name, len(content.Status.VolumeSnapshotHandlePairList))
+		}
+	}
+}
+
+func (d *doctor) checkGroupSnapshotContent(content *crdv1beta1.VolumeGroupSnapshotContent) {
+	ref := content.Spec.VolumeGroupSnapshotRef
+	// Contents pre-bound to a group snapshot are bound by the snapshot controller.
+	if ref.UID == "" {
+		return
+	}
+	if utils.NeedToAddGroupSnapshotContentFinalizer(content) {
+		d.add(kindVolumeGroupSnapshotContent, &content.ObjectMeta, checkGroupContentFinalizerMissing, d.groupSnapshotContentFix(content, func(c *crdv1beta1.VolumeGroupSnapshotContent) bool {
+			if !utils.NeedToAddGroupSnapshotContentFinalizer(c) {
+				return false
+			}
+			c.Finalizers = append(c.Finalizers, utils.VolumeGroupSnapshotContentFinalizer)
+			return true
+		}), "the %s finalizer is missing", utils.VolumeGroupSnapshotContentFinalizer)
+	}
+
+	groupSnapshot := d.groupSnapshots[types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}]
+	switch {
+	case groupSnapshot == nil || groupSnapshot.UID != ref.UID:
+		if content.Spec.DeletionPolicy == crdv1.VolumeSnapshotContentDelete && content.DeletionTimestamp == nil {
+			d.add(kindVolumeGroupSnapshotContent, &content.ObjectMeta, checkGroupSnapshotMissing, nil,
+				"bound to VolumeGroupSnapshot %s/%s with UID %q, which does not exist, but not deleted despite its Delete deletion policy",
+				ref.Namespace, ref.Name, ref.UID)
+		}
+	case utils.IsBoundVolumeGroupSnapshotContentNameSet(groupSnapshot) && *groupSnapshot.Status.BoundVolumeGroupSnapshotContentName != content.Name:
+		d.add(kindVolumeGroupSnapshotContent, &content.ObjectMeta, checkGroupSnapshotBoundToOtherContent, nil,
+			"bound to VolumeGroupSnapshot %s/%s, which is bound to VolumeGroupSnapshotContent %s",
+			ref.Namespace, ref.Name, *groupSnapshot.Status.BoundVolumeGroupSnapshotContentName)
+	}
+}
+
+func (d *doctor) checkClaim(claim *v1.PersistentVolumeClaim) {
+	if !slices.Contains(claim.Finalizers, utils.PVCFinalizer) || isClaimBeingSnapshotted(claim, d.snapshots) {
+		return
+	}
+	d.add(kindPersistentVolumeClaim, &claim.ObjectMeta, checkStalePVCFinalizer, func(ctx context.Context) error {
+		// Check again with the current snapshots, a snapshot may have been created since the check.
+		snapshots, err := d.o.SnapshotClient.SnapshotV1().VolumeSnapshots(claim.Namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		current, err := d.o.KubeClient.CoreV1().PersistentVolumeClaims(claim.Namespace).Get(ctx, claim.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if isClaimBeingSnapshotted(current, snapshots.Items) || !slices.Contains(current.Finalizers, utils.PVCFinalizer) {
+			return nil
+		}
+		current.Finalizers = slices.DeleteFunc(current.Finalizers, func(f string) bool { return f == utils.PVCFinalizer })
+		_, err = d.o.KubeClient.CoreV1().PersistentVolumeClaims(claim.Namespace).Update(ctx, current, metav1.UpdateOptions{})
+		return err
+	}, "the %s finalizer is set, but no VolumeSnapshot is being created from the claim", utils.PVCFinalizer)
+}
+
+// isClaimBeingSnapshotted mirrors the check of the common snapshot controller
+// before it removes the finalizer of a claim: a snapshot that is not ready to use
+// is still being created from the claim.
+func isClaimBeingSnapshotted(claim *v1.PersistentVolumeClaim, snapshots []crdv1.VolumeSnapshot) bool {
+	for i := range snapshots {
+		snapshot := &snapshots[i]
+		if snapshot.Namespace != claim.Namespace {
+			continue
+		}
+		if snapshot.Spec.Source.PersistentVolumeClaimName != nil && *snapshot.Spec.Source.PersistentVolumeClaimName == claim.Name && !utils.IsSnapshotReady(snapshot) {
+			return true
+		}
+	}
+	return false
+}
+
+// snapshotFix returns a fix fetching the snapshot again and updating it if update,
+// which checks the finding again, changes it.
+func (d *doctor) snapshotFix(snapshot *crdv1.VolumeSnapshot, update func(*crdv1.VolumeSnapshot) bool) func(context.Context) error {
+	return func(ctx context.Context) error {
+		client := d.o.SnapshotClient.SnapshotV1().VolumeSnapshots(snapshot.Namespace)
+		current, err := client.Get(ctx, snapshot.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if current.UID != snapshot.UID {
+			return fmt.Errorf("the VolumeSnapshot was recreated")
+		}
+		if !update(current) {
+			return nil
+		}
+		_, err = client.Update(ctx, current, metav1.UpdateOptions{})
+		return err
+	}
+}
+
+func (d *doctor) contentFix(content *crdv1.VolumeSnapshotContent, update func(*crdv1.VolumeSnapshotContent) bool) func(context.Context) error {
+	return func(ctx context.Context) error {
+		client := d.o.SnapshotClient.SnapshotV1().VolumeSnapshotContents()
+		current, err := client.Get(ctx, content.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if current.UID != content.UID {
+			return fmt.Errorf("the VolumeSnapshotContent was recreated")
+		}
+		if !update(current) {
+			return nil
+		}
+		_, err = client.Update(ctx, current, metav1.UpdateOptions{})
+		return err
+	}
+}
+
+func (d *doctor) groupSnapshotFix(groupSnapshot *crdv1beta1.VolumeGroupSnapshot, update func(*crdv1beta1.VolumeGroupSnapshot) bool) func(context.Context) error {
+	return func(ctx context.Context) error {
+		client := d.o.SnapshotClient.GroupsnapshotV1beta1().VolumeGroupSnapshots(groupSnapshot.Namespace)
+		current, err := client.Get(ctx, groupSnapshot.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if current.UID != groupSnapshot.UID {
+			return fmt.Errorf("the VolumeGroupSnapshot was recreated")
+		}
+		if !update(current) {
+			return nil
+		}
+		_, err = client.Update(ctx, current, metav1.UpdateOptions{})
+		return err
+	}
+}
+
+func (d *doctor) groupSnapshotContentFix(content *crdv1beta1.VolumeGroupSnapshotContent, update func(*crdv1beta1.VolumeGroupSnapshotContent) bool) func(context.Context) error {
+	return func(ctx context.Context) error {
+		client := d.o.SnapshotClient.GroupsnapshotV1beta1().VolumeGroupSnapshotContents()
+		current, err := client.Get(ctx, content.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if current.UID != content.UID {
+			return fmt.Errorf("the VolumeGroupSnapshotContent was recreated")
+		}
+		if !update(current) {
+			return nil
+		}
+		_, err = client.Update(ctx, current, metav1.UpdateOptions{})
+		return err
+	}
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectlsnapshot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	crdv1beta1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumegroupsnapshot/v1beta1"
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	"github.com/kubernetes-csi/external-snapshotter/client/v8/clientset/versioned/fake"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+)
+
+// withFinalizers sets the finalizers of the object.
+func withFinalizers[T metav1.Object](obj T, finalizers ...string) T {
+	obj.SetFinalizers(finalizers)
+	return obj
+}
+
+func newDoctorObjects() ([]runtime.Object, []runtime.Object) {
+	groupSnapshot := withFinalizers(newGroupSnapshot("group", "groupcontent", &True), utils.VolumeGroupSnapshotBoundFinalizer)
+	groupContent := withFinalizers(newGroupSnapshotContent("groupcontent", "group-handle", &True), utils.VolumeGroupSnapshotContentFinalizer)
+	groupContent.Spec.VolumeGroupSnapshotRef = v1.ObjectReference{Kind: kindVolumeGroupSnapshot, Namespace: testNamespace, Name: groupSnapshot.Name, UID: groupSnapshot.UID}
+	groupContent.Status.VolumeSnapshotHandlePairList = []crdv1beta1.VolumeSnapshotHandlePair{
+		{VolumeHandle: "volume-a", SnapshotHandle: "handle-a"},
+		{VolumeHandle: "volume-b", SnapshotHandle: "handle-b"},
+	}
+
+	healthyMember := withGroupSnapshotOwner(newSnapshot("member-a", "claim-a", "content-a", &True, "1Gi"), groupSnapshot)
+	healthyMember.Finalizers = append(healthyMember.Finalizers, utils.VolumeSnapshotAsSourceFinalizer, utils.VolumeSnapshotBoundFinalizer)
+
+	// member-b is not owned by its group snapshot yet
+	unownedMember := withFinalizers(newSnapshot("member-b", "claim-a", "content-b", &True, "1Gi"),
+		utils.VolumeSnapshotAsSourceFinalizer, utils.VolumeSnapshotBoundFinalizer, utils.VolumeSnapshotInGroupFinalizer)
+	groupName := "group"
+	unownedMember.Status.VolumeGroupSnapshotName = &groupName
+
+	// snap-c misses its finalizers, and so does its content
+	unprotected := newSnapshot("snap-c", "claim-c", "content-c", &True, "1Gi")
+	unprotectedContent := newContent("content-c", "snap-c", "handle-c", &True)
+
+	// snap-d is bound to a missing content, and content-e to a missing snapshot
+	dangling := withFinalizers(newSnapshot("snap-d", "claim-c", "content-d", &True, "1Gi"), utils.VolumeSnapshotAsSourceFinalizer)
+	released := withFinalizers(newContent("content-e", "snap-e", "handle-e", &True), utils.VolumeSnapshotContentFinalizer)
+	retained := withFinalizers(newContent("content-f", "snap-f", "handle-f", &True), utils.VolumeSnapshotContentFinalizer)
+	retained.Spec.DeletionPolicy = crdv1.VolumeSnapshotContentRetain
+
+	// snap-g is bound to a content referencing another incarnation of the snapshot
+	mismatched := withFinalizers(newSnapshot("snap-g", "claim-c", "content-g", &True, "1Gi"), utils.VolumeSnapshotAsSourceFinalizer)
+	mismatchedContent := withFinalizers(newContent("content-g", "snap-g", "handle-g", &True), utils.VolumeSnapshotContentFinalizer)
+	mismatchedContent.Spec.VolumeSnapshotRef.UID = "old-uid"
+
+	// claim-a is protected, but all its snapshots are ready; claim-c is being snapshotted
+	staleClaim := withFinalizers(newClaim("claim-a", "pv-a"), utils.PVCFinalizer)
+	protectedClaim := withFinalizers(newClaim("claim-c", "pv-c"), utils.PVCFinalizer)
+	inProgress := withFinalizers(newSnapshot("snap-h", "claim-c", "", &False, ""), utils.VolumeSnapshotAsSourceFinalizer)
+
+	return []runtime.Object{staleClaim, protectedClaim}, []runtime.Object{
+		groupSnapshot, groupContent,
+		healthyMember, withFinalizers(newContent("content-a", "member-a", "handle-a", &True), utils.VolumeSnapshotContentFinalizer),
+		unownedMember, withFinalizers(newContent("content-b", "member-b", "handle-b", &True), utils.VolumeSnapshotContentFinalizer),
+		unprotected, unprotectedContent,
+		dangling, released, retained,
+		mismatched, mismatchedContent,
+		inProgress,
+	}
+}
+
+func TestDoctor(t *testing.T) {
+	kubeObjects, snapshotObjects := newDoctorObjects()
+	out, _, err := runCommand(t, kubeObjects, snapshotObjects, "doctor", "-o", "json")
+	if err == nil || err.Error() != "found 9 problems" {
+		t.Errorf("expected error %q, got %v", "found 9 problems", err)
+	}
+	report := &DoctorReport{}
+	if err := json.Unmarshal([]byte(out), report); err != nil {
+		t.Fatalf("failed to decode the report: %v\n%s", err, out)
+	}
+	if report.VolumeSnapshots != 6 || report.VolumeSnapshotContents != 6 || report.VolumeGroupSnapshots != 1 ||
+		report.VolumeGroupSnapshotContents != 1 || report.PersistentVolumeClaims != 2 {
+		t.Errorf("unexpected counts in report %+v", report)
+	}
+
+	var findings []string
+	for _, finding := range report.Findings {
+		findings = append(findings, strings.Join([]string{finding.Kind, finding.Name, finding.Check, map[bool]string{true: "fixable", false: "-"}[finding.Fixable]}, " "))
+	}
+	sort.Strings(findings)
+	expected := []string{
+		"PersistentVolumeClaim claim-a StalePVCFinalizer fixable",
+		"VolumeGroupSnapshot group GroupMembersMismatch -",
+		"VolumeSnapshot member-b GroupOwnershipMissing fixable",
+		"VolumeSnapshot snap-c SnapshotFinalizerMissing fixable",
+		"VolumeSnapshot snap-d BoundContentMissing -",
+		"VolumeSnapshot snap-g ContentNotBoundToSnapshot -",
+		"VolumeSnapshotContent content-c ContentFinalizerMissing fixable",
+		"VolumeSnapshotContent content-e SnapshotMissing -",
+		"VolumeSnapshotContent content-g SnapshotMissing -",
+	}
+	if !reflect.DeepEqual(findings, expected) {
+		t.Errorf("expected findings:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(findings, "\n"))
+	}
+}
+
+func TestDoctorFix(t *testing.T) {
+	kubeObjects, snapshotObjects := newDoctorObjects()
+	var out, errOut bytes.Buffer
+	o := &Options{
+		KubeClient:     kubefake.NewSimpleClientset(kubeObjects...),
+		SnapshotClient: fake.NewSimpleClientset(snapshotObjects...),
+		Namespace:      testNamespace,
+		Out:            &out,
+		ErrOut:         &errOut,
+	}
+	run := func(args ...string) error {
+		out.Reset()
+		cmd := NewCommand(o)
+		cmd.SetArgs(args)
+		return cmd.ExecuteContext(context.Background())
+	}
+
+	if err := run("doctor"); err == nil {
+		t.Fatalf("expected problems to be found")
+	}
+	if !strings.Contains(out.String(), "4 of the 9 problems can be repaired with --fix.") {
+		t.Errorf("expected the fixable problems to be counted, got:\n%s", out.String())
+	}
+
+	if err := run("doctor", "--fix"); err == nil || err.Error() != "found 5 problems" {
+		t.Errorf("expected error %q, got %v", "found 5 problems", err)
+	}
+	if strings.Count(out.String(), " fixed ") != 4 {
+		t.Errorf("expected 4 fixed problems, got:\n%s", out.String())
+	}
+
+	ctx := context.Background()
+	snapshot, _ := o.SnapshotClient.SnapshotV1().VolumeSnapshots(testNamespace).Get(ctx, "snap-c", metav1.GetOptions{})
+	if expected := []string{utils.VolumeSnapshotAsSourceFinalizer, utils.VolumeSnapshotBoundFinalizer}; !reflect.DeepEqual(snapshot.Finalizers, expected) {
+		t.Errorf("expected finalizers %v on snap-c, got %v", expected, snapshot.Finalizers)
+	}
+	content, _ := o.SnapshotClient.SnapshotV1().VolumeSnapshotContents().Get(ctx, "content-c", metav1.GetOptions{})
+	if expected := []string{utils.VolumeSnapshotContentFinalizer}; !reflect.DeepEqual(content.Finalizers, expected) {
+		t.Errorf("expected finalizers %v on content-c, got %v", expected, content.Finalizers)
+	}
+	member, _ := o.SnapshotClient.SnapshotV1().VolumeSnapshots(testNamespace).Get(ctx, "member-b", metav1.GetOptions{})
+	if !utils.IsVolumeGroupSnapshotMember(member) {
+		t.Errorf("expected member-b to be owned by its group snapshot, got %v", member.OwnerReferences)
+	}
+	claim, _ := o.KubeClient.CoreV1().PersistentVolumeClaims(testNamespace).Get(ctx, "claim-a", metav1.GetOptions{})
+	if len(claim.Finalizers) != 0 {
+		t.Errorf("expected the finalizer of claim-a to be removed, got %v", claim.Finalizers)
+	}
+	claim, _ = o.KubeClient.CoreV1().PersistentVolumeClaims(testNamespace).Get(ctx, "claim-c", metav1.GetOptions{})
+	if len(claim.Finalizers) != 1 {
+		t.Errorf("expected the finalizer of claim-c to be kept, got %v", claim.Finalizers)
+	}
+
+	// Only the problems without a known fix are left, the members of the group
+	// snapshot match its content now that member-b is owned by it.
+	if err := run("doctor"); err == nil || err.Error() != "found 4 problems" {
+		t.Errorf("expected error %q, got %v", "found 4 problems", err)
+	}
+	if strings.Contains(out.String(), "fixable") {
+		t.Errorf("expected no fixable problems, got:\n%s", out.String())
+	}
+}
+
+func TestDoctorHealthy(t *testing.T) {
+	content := withFinalizers(newContent("content-a", "snap-a", "handle-a", &True), utils.VolumeSnapshotContentFinalizer)
+	snapshot := withFinalizers(newSnapshot("snap-a", "claim-a", "content-a", &True, "1Gi"), utils.VolumeSnapshotAsSourceFinalizer, utils.VolumeSnapshotBoundFinalizer)
+	out, _, err := runCommand(t, []runtime.Object{newClaim("claim-a", "pv-a")}, []runtime.Object{snapshot, content}, "doctor")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	expected := "Checked 1 VolumeSnapshots, 1 VolumeSnapshotContents, 0 VolumeGroupSnapshots, 0 VolumeGroupSnapshotContents and 1 PersistentVolumeClaims.\nNo problems found.\n"
+	if out != expected {
+		t.Errorf("expected %q, got %q", expected, out)
+	}
+}
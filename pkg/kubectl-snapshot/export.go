@@ -0,0 +1,219 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectlsnapshot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	crdv1beta1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumegroupsnapshot/v1beta1"
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+)
+
+// bundleVersion is the version of the format of the bundles written by the
+// export command.
+const bundleVersion = "v1"
+
+// Bundle holds the snapshot objects exported from a cluster, with their
+// status, to be imported in another cluster using the same storage system.
+type Bundle struct {
+	Version                     string                                  `json:"version"`
+	VolumeSnapshotContents      []crdv1.VolumeSnapshotContent           `json:"volumeSnapshotContents"`
+	VolumeSnapshots             []crdv1.VolumeSnapshot                  `json:"volumeSnapshots"`
+	VolumeGroupSnapshotContents []crdv1beta1.VolumeGroupSnapshotContent `json:"volumeGroupSnapshotContents"`
+	VolumeGroupSnapshots        []crdv1beta1.VolumeGroupSnapshot        `json:"volumeGroupSnapshots"`
+}
+
+// transientAnnotations are the annotations that only make sense in the
+// exporting cluster, such as the ones of the operations in progress, which
+// are not exported.
+var transientAnnotations = []string{
+	utils.AnnVolumeSnapshotBeingCreated,
+	utils.AnnVolumeSnapshotBeingDeleted,
+	utils.AnnVolumeGroupSnapshotBeingCreated,
+	utils.AnnVolumeGroupSnapshotBeingDeleted,
+	v1.LastAppliedConfigAnnotation,
+}
+
+// exportOptions are the flags of the export command.
+type exportOptions struct {
+	allNamespaces bool
+	output        string
+}
+
+func newExportCommand(o *Options) *cobra.Command {
+	eo := &exportOptions{}
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Write the snapshots of the namespace to a bundle",
+		Long: "Write the VolumeSnapshotContents and VolumeGroupSnapshotContents bound to the snapshots of the namespace, " +
+			"or of all the namespaces, and the VolumeSnapshots and VolumeGroupSnapshots bound to them, to a bundle that " +
+			"can be imported in another cluster using the same storage system. The bundle keeps the status of the " +
+			"objects, with the snapshot handles and restore sizes, and drops their cluster specific metadata.\n\n" +
+			"Contents whose snapshot has not been cut yet are skipped. The members of a group snapshot are not " +
+			"exported on their own, since the snapshot controller creates them from the snapshot handles of the " +
+			"VolumeGroupSnapshotContent.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.runExport(cmd.Context(), eo)
+		},
+	}
+	cmd.Flags().BoolVarP(&eo.allNamespaces, "all-namespaces", "A", false, "Export the snapshots of all the namespaces.")
+	cmd.Flags().StringVarP(&eo.output, "output", "o", "yaml", "Format of the bundle, yaml or json.")
+	return cmd
+}
+
+func (o *Options) runExport(ctx context.Context, eo *exportOptions) error {
+	if eo.output != "yaml" && eo.output != "json" {
+		return fmt.Errorf("unsupported output format %q, expected yaml or json", eo.output)
+	}
+	inScope := func(namespace string) bool {
+		return eo.allNamespaces || namespace == o.Namespace
+	}
+
+	bundle, err := o.exportBundle(ctx, inScope)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	if eo.output == "json" {
+		data, err = json.MarshalIndent(bundle, "", "    ")
+	} else {
+		data, err = yaml.Marshal(bundle)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(o.Out, string(data))
+	return nil
+}
+
+// exportBundle collects the contents bound to snapshots of the namespaces in
+// scope, and the snapshots bound to them.
+func (o *Options) exportBundle(ctx context.Context, inScope func(namespace string) bool) (*Bundle, error) {
+	bundle := &Bundle{
+		Version:                     bundleVersion,
+		VolumeSnapshotContents:      []crdv1.VolumeSnapshotContent{},
+		VolumeSnapshots:             []crdv1.VolumeSnapshot{},
+		VolumeGroupSnapshotContents: []crdv1beta1.VolumeGroupSnapshotContent{},
+		VolumeGroupSnapshots:        []crdv1beta1.VolumeGroupSnapshot{},
+	}
+
+	contents, err := o.SnapshotClient.SnapshotV1().VolumeSnapshotContents().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list volume snapshot contents: %w", err)
+	}
+	for _, content := range contents.Items {
+		ref := content.Spec.VolumeSnapshotRef
+		if !inScope(ref.Namespace) || metav1.HasAnnotation(content.ObjectMeta, utils.VolumeGroupSnapshotHandleAnnotation) {
+			continue
+		}
+		if contentSnapshotHandle(&content) == "" {
+			fmt.Fprintf(o.ErrOut, "Warning: skipping volume snapshot content %s, its snapshot has not been cut yet\n", content.Name)
+			continue
+		}
+		content.ObjectMeta = exportedObjectMeta(content.ObjectMeta)
+		bundle.VolumeSnapshotContents = append(bundle.VolumeSnapshotContents, content)
+
+		snapshot, err := o.SnapshotClient.SnapshotV1().VolumeSnapshots(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if apierrs.IsNotFound(err) || err == nil && snapshot.UID != ref.UID {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get volume snapshot %s/%s: %w", ref.Namespace, ref.Name, err)
+		}
+		snapshot.ObjectMeta = exportedObjectMeta(snapshot.ObjectMeta)
+		bundle.VolumeSnapshots = append(bundle.VolumeSnapshots, *snapshot)
+	}
+
+	groupContents, err := o.SnapshotClient.GroupsnapshotV1beta1().VolumeGroupSnapshotContents().List(ctx, metav1.ListOptions{})
+	// The group snapshot CRDs are optional.
+	if apierrs.IsNotFound(err) {
+		return bundle, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list volume group snapshot contents: %w", err)
+	}
+	for _, content := range groupContents.Items {
+		ref := content.Spec.VolumeGroupSnapshotRef
+		if !inScope(ref.Namespace) {
+			continue
+		}
+		if groupContentSnapshotHandles(&content) == nil {
+			fmt.Fprintf(o.ErrOut, "Warning: skipping volume group snapshot content %s, its group snapshot has not been cut yet\n", content.Name)
+			continue
+		}
+		content.ObjectMeta = exportedObjectMeta(content.ObjectMeta)
+		bundle.VolumeGroupSnapshotContents = append(bundle.VolumeGroupSnapshotContents, content)
+
+		groupSnapshot, err := o.SnapshotClient.GroupsnapshotV1beta1().VolumeGroupSnapshots(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if apierrs.IsNotFound(err) || err == nil && groupSnapshot.UID != ref.UID {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get volume group snapshot %s/%s: %w", ref.Namespace, ref.Name, err)
+		}
+		groupSnapshot.ObjectMeta = exportedObjectMeta(groupSnapshot.ObjectMeta)
+		bundle.VolumeGroupSnapshots = append(bundle.VolumeGroupSnapshots, *groupSnapshot)
+	}
+	return bundle, nil
+}
+
+// exportedObjectMeta keeps the name, namespace, labels and annotations of an
+// object, without the transient annotations.
+func exportedObjectMeta(meta metav1.ObjectMeta) metav1.ObjectMeta {
+	exported := metav1.ObjectMeta{
+		Name:      meta.Name,
+		Namespace: meta.Namespace,
+		Labels:    meta.Labels,
+	}
+	for key, value := range meta.Annotations {
+		if slices.Contains(transientAnnotations, key) {
+			continue
+		}
+		if exported.Annotations == nil {
+			exported.Annotations = map[string]string{}
+		}
+		exported.Annotations[key] = value
+	}
+	return exported
+}
+
+// groupContentSnapshotHandles returns the handles of the group snapshot and of
+// its members on the storage system, or nil if it has not been cut yet.
+func groupContentSnapshotHandles(content *crdv1beta1.VolumeGroupSnapshotContent) *crdv1beta1.GroupSnapshotHandles {
+	if content.Status != nil && content.Status.VolumeGroupSnapshotHandle != nil {
+		handles := &crdv1beta1.GroupSnapshotHandles{VolumeGroupSnapshotHandle: *content.Status.VolumeGroupSnapshotHandle}
+		for _, pair := range content.Status.VolumeSnapshotHandlePairList {
+			handles.VolumeSnapshotHandles = append(handles.VolumeSnapshotHandles, pair.SnapshotHandle)
+		}
+		if len(handles.VolumeSnapshotHandles) > 0 {
+			return handles
+		}
+	}
+	return content.Spec.Source.GroupSnapshotHandles
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectlsnapshot
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	crdv1beta1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumegroupsnapshot/v1beta1"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+)
+
+func newExportObjects() []runtime.Object {
+	content := withFinalizers(newContent("content-a", "snap-a", "handle-a", &True), utils.VolumeSnapshotContentFinalizer)
+	content.Annotations = map[string]string{
+		utils.AnnDeletionSecretRefName:      "secret",
+		utils.AnnDeletionSecretRefNamespace: testNamespace,
+		utils.AnnVolumeSnapshotBeingDeleted: "yes",
+	}
+	restoreSize := int64(1 << 30)
+	content.Status.RestoreSize = &restoreSize
+	snapshot := withFinalizers(newSnapshot("snap-a", "claim-a", "content-a", &True, "1Gi"), utils.VolumeSnapshotBoundFinalizer)
+
+	// content-b is bound to a snapshot of another namespace
+	otherNamespace := newContent("content-b", "snap-b", "handle-b", &True)
+	otherNamespace.Spec.VolumeSnapshotRef.Namespace = "other"
+
+	// content-c has not been cut yet
+	uncut := newContent("content-c", "snap-c", "", &False)
+	uncut.Status.SnapshotHandle = nil
+
+	groupSnapshot := newGroupSnapshot("group", "groupcontent", &True)
+	groupContent := newGroupSnapshotContent("groupcontent", "group-handle", &True)
+	groupContent.Spec.VolumeGroupSnapshotRef = v1.ObjectReference{Kind: kindVolumeGroupSnapshot, Namespace: testNamespace, Name: "group", UID: groupSnapshot.UID}
+	groupContent.Status.VolumeSnapshotHandlePairList = []crdv1beta1.VolumeSnapshotHandlePair{
+		{VolumeHandle: "volume-m", SnapshotHandle: "handle-m"},
+	}
+	member := withGroupSnapshotOwner(newSnapshot("member", "claim-m", "content-m", &True, "1Gi"), groupSnapshot)
+	memberContent := newContent("content-m", "member", "handle-m", &True)
+	memberContent.Annotations = map[string]string{utils.VolumeGroupSnapshotHandleAnnotation: "group-handle"}
+
+	return []runtime.Object{
+		snapshot, content, otherNamespace, uncut,
+		groupSnapshot, groupContent, member, memberContent,
+	}
+}
+
+func TestExport(t *testing.T) {
+	out, errOut, err := runCommand(t, nil, newExportObjects(), "export")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(errOut, "skipping volume snapshot content content-c") {
+		t.Errorf("expected a warning about content-c, got %q", errOut)
+	}
+	bundle := &Bundle{}
+	if err := yaml.UnmarshalStrict([]byte(out), bundle); err != nil {
+		t.Fatalf("failed to decode the bundle: %v\n%s", err, out)
+	}
+	if bundle.Version != bundleVersion {
+		t.Errorf("expected version %s, got %s", bundleVersion, bundle.Version)
+	}
+
+	if len(bundle.VolumeSnapshotContents) != 1 || len(bundle.VolumeSnapshots) != 1 {
+		t.Fatalf("expected content-a and snap-a to be exported, got:\n%s", out)
+	}
+	content := bundle.VolumeSnapshotContents[0]
+	if content.Name != "content-a" || len(content.Finalizers) != 0 || content.UID != "" {
+		t.Errorf("expected the metadata of content-a to be sanitized, got %+v", content.ObjectMeta)
+	}
+	expectedAnnotations := map[string]string{utils.AnnDeletionSecretRefName: "secret", utils.AnnDeletionSecretRefNamespace: testNamespace}
+	if !reflect.DeepEqual(content.Annotations, expectedAnnotations) {
+		t.Errorf("expected annotations %v, got %v", expectedAnnotations, content.Annotations)
+	}
+	if *content.Status.SnapshotHandle != "handle-a" || *content.Status.RestoreSize != 1<<30 {
+		t.Errorf("expected the status of content-a to be exported, got %+v", content.Status)
+	}
+	snapshot := bundle.VolumeSnapshots[0]
+	if snapshot.Name != "snap-a" || snapshot.Namespace != testNamespace || len(snapshot.Finalizers) != 0 || snapshot.Status.RestoreSize.String() != "1Gi" {
+		t.Errorf("unexpected exported snapshot %+v", snapshot)
+	}
+
+	if len(bundle.VolumeGroupSnapshotContents) != 1 || len(bundle.VolumeGroupSnapshots) != 1 {
+		t.Fatalf("expected groupcontent and group to be exported, got:\n%s", out)
+	}
+	if pairs := bundle.VolumeGroupSnapshotContents[0].Status.VolumeSnapshotHandlePairList; len(pairs) != 1 || pairs[0].SnapshotHandle != "handle-m" {
+		t.Errorf("expected the handles of the members to be exported, got %+v", pairs)
+	}
+}
+
+func TestExportAllNamespaces(t *testing.T) {
+	out, _, err := runCommand(t, nil, newExportObjects(), "export", "-A", "-o", "json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	bundle := &Bundle{}
+	if err := yaml.UnmarshalStrict([]byte(out), bundle); err != nil {
+		t.Fatalf("failed to decode the bundle: %v\n%s", err, out)
+	}
+	var names []string
+	for _, content := range bundle.VolumeSnapshotContents {
+		names = append(names, content.Name)
+	}
+	// content-b is exported without its missing snapshot
+	if expected := []string{"content-a", "content-b"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected contents %v, got %v", expected, names)
+	}
+	if len(bundle.VolumeSnapshots) != 1 {
+		t.Errorf("expected 1 snapshot, got %d", len(bundle.VolumeSnapshots))
+	}
+}
@@ -0,0 +1,294 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectlsnapshot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	crdv1beta1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumegroupsnapshot/v1beta1"
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+)
+
+// importOptions are the flags of the import command.
+type importOptions struct {
+	filename         string
+	namespaceMapping map[string]string
+	dryRun           bool
+	in               io.Reader
+}
+
+func newImportCommand(o *Options) *cobra.Command {
+	imo := &importOptions{}
+	cmd := &cobra.Command{
+		Use:   "import -f BUNDLE",
+		Short: "Create the snapshots of a bundle as pre-provisioned snapshots",
+		Long: "Create the VolumeSnapshotContents, VolumeSnapshots, VolumeGroupSnapshotContents and VolumeGroupSnapshots " +
+			"of a bundle written by the export command, for instance to rebuild the snapshots in a new cluster using " +
+			"the same storage system after a disaster.\n\n" +
+			"The contents are created as pre-provisioned contents from their snapshot handles, or from the handles of " +
+			"the group snapshot and of its members, with the Retain deletion policy, so that deleting the imported " +
+			"objects never deletes the snapshots still used by the exporting cluster. The snapshots are created from " +
+			"their content, and their status is filled again by the snapshot controllers. The namespaces of the " +
+			"snapshots, and of the deletion secrets of the contents, are changed according to --namespace-mapping. " +
+			"Objects that already exist are left unchanged.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			imo.in = cmd.InOrStdin()
+			return o.runImport(cmd.Context(), imo)
+		},
+	}
+	cmd.Flags().StringVarP(&imo.filename, "filename", "f", "", "Bundle to import, or - to read it from the standard input.")
+	cmd.Flags().StringToStringVar(&imo.namespaceMapping, "namespace-mapping", nil, "Namespaces of the bundle to create the snapshots in another namespace, as OLD=NEW pairs.")
+	cmd.Flags().BoolVar(&imo.dryRun, "dry-run", false, "Only print the objects that would be created.")
+	return cmd
+}
+
+func (o *Options) runImport(ctx context.Context, imo *importOptions) error {
+	if imo.filename == "" {
+		return fmt.Errorf("--filename is required")
+	}
+	bundle, err := readBundle(imo)
+	if err != nil {
+		return err
+	}
+
+	im := &importer{o: o, imo: imo}
+	for i := range bundle.VolumeGroupSnapshotContents {
+		im.importGroupSnapshotContent(ctx, &bundle.VolumeGroupSnapshotContents[i])
+	}
+	for i := range bundle.VolumeGroupSnapshots {
+		im.importGroupSnapshot(ctx, &bundle.VolumeGroupSnapshots[i])
+	}
+	for i := range bundle.VolumeSnapshotContents {
+		im.importSnapshotContent(ctx, &bundle.VolumeSnapshotContents[i])
+	}
+	for i := range bundle.VolumeSnapshots {
+		im.importSnapshot(ctx, &bundle.VolumeSnapshots[i])
+	}
+
+	if im.failed > 0 {
+		return fmt.Errorf("failed to import %d objects", im.failed)
+	}
+	return nil
+}
+
+// readBundle reads and decodes the bundle of the --filename flag.
+func readBundle(imo *importOptions) (*Bundle, error) {
+	var data []byte
+	var err error
+	if imo.filename == "-" {
+		data, err = io.ReadAll(imo.in)
+	} else {
+		data, err = os.ReadFile(imo.filename)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle: %w", err)
+	}
+	bundle := &Bundle{}
+	if err := yaml.UnmarshalStrict(data, bundle); err != nil {
+		return nil, fmt.Errorf("failed to decode bundle: %w", err)
+	}
+	if bundle.Version != bundleVersion {
+		return nil, fmt.Errorf("unsupported bundle version %q, expected %s", bundle.Version, bundleVersion)
+	}
+	return bundle, nil
+}
+
+// importer creates the objects of a bundle.
+type importer struct {
+	o      *Options
+	imo    *importOptions
+	failed int
+}
+
+// namespace returns the namespace in which the objects of the namespace of
+// the bundle are created.
+func (im *importer) namespace(namespace string) string {
+	if mapped, ok := im.imo.namespaceMapping[namespace]; ok {
+		return mapped
+	}
+	return namespace
+}
+
+// importedObjectMeta returns the metadata of an object of the bundle in the
+// importing cluster.
+func (im *importer) importedObjectMeta(meta metav1.ObjectMeta) metav1.ObjectMeta {
+	imported := metav1.ObjectMeta{
+		Name:   meta.Name,
+		Labels: meta.Labels,
+	}
+	if meta.Namespace != "" {
+		imported.Namespace = im.namespace(meta.Namespace)
+	}
+	for key, value := range meta.Annotations {
+		if imported.Annotations == nil {
+			imported.Annotations = map[string]string{}
+		}
+		if key == utils.AnnDeletionSecretRefNamespace || key == utils.AnnDeletionGroupSecretRefNamespace {
+			value = im.namespace(value)
+		}
+		imported.Annotations[key] = value
+	}
+	return imported
+}
+
+// importedRef returns the reference to a snapshot of the bundle in the
+// importing cluster, which is bound when the snapshot is created.
+func (im *importer) importedRef(ref v1.ObjectReference) v1.ObjectReference {
+	return v1.ObjectReference{
+		Kind:       ref.Kind,
+		APIVersion: ref.APIVersion,
+		Namespace:  im.namespace(ref.Namespace),
+		Name:       ref.Name,
+	}
+}
+
+// create runs the creation of an object and prints its outcome.
+func (im *importer) create(kind string, meta metav1.ObjectMeta, create func() error) {
+	name := strings.ToLower(kind) + "/" + meta.Name
+	if meta.Namespace != "" {
+		name += " in namespace " + meta.Namespace
+	}
+	if im.imo.dryRun {
+		fmt.Fprintf(im.o.Out, "%s created (dry run)\n", name)
+		return
+	}
+	err := create()
+	switch {
+	case apierrs.IsAlreadyExists(err):
+		fmt.Fprintf(im.o.Out, "%s unchanged, it already exists\n", name)
+	case err != nil:
+		fmt.Fprintf(im.o.ErrOut, "Error: failed to create %s: %v\n", name, err)
+		im.failed++
+	default:
+		fmt.Fprintf(im.o.Out, "%s created\n", name)
+	}
+}
+
+// fail reports an object of the bundle that cannot be imported.
+func (im *importer) fail(kind, name, reason string) {
+	fmt.Fprintf(im.o.ErrOut, "Error: cannot import %s/%s, %s\n", strings.ToLower(kind), name, reason)
+	im.failed++
+}
+
+func (im *importer) importSnapshotContent(ctx context.Context, content *crdv1.VolumeSnapshotContent) {
+	snapshotHandle := contentSnapshotHandle(content)
+	if snapshotHandle == "" {
+		im.fail(kindVolumeSnapshotContent, content.Name, "it has no snapshot handle")
+		return
+	}
+	imported := &crdv1.VolumeSnapshotContent{
+		ObjectMeta: im.importedObjectMeta(content.ObjectMeta),
+		Spec: crdv1.VolumeSnapshotContentSpec{
+			VolumeSnapshotRef:       im.importedRef(content.Spec.VolumeSnapshotRef),
+			DeletionPolicy:          crdv1.VolumeSnapshotContentRetain,
+			Driver:                  content.Spec.Driver,
+			VolumeSnapshotClassName: content.Spec.VolumeSnapshotClassName,
+			Source: crdv1.VolumeSnapshotContentSource{
+				SnapshotHandle: &snapshotHandle,
+			},
+			SourceVolumeMode: content.Spec.SourceVolumeMode,
+		},
+	}
+	im.create(kindVolumeSnapshotContent, imported.ObjectMeta, func() error {
+		_, err := im.o.SnapshotClient.SnapshotV1().VolumeSnapshotContents().Create(ctx, imported, metav1.CreateOptions{})
+		return err
+	})
+}
+
+func (im *importer) importSnapshot(ctx context.Context, snapshot *crdv1.VolumeSnapshot) {
+	contentName := snapshot.Spec.Source.VolumeSnapshotContentName
+	if snapshot.Status != nil && snapshot.Status.BoundVolumeSnapshotContentName != nil {
+		contentName = snapshot.Status.BoundVolumeSnapshotContentName
+	}
+	if contentName == nil {
+		im.fail(kindVolumeSnapshot, snapshot.Name, "it is not bound to a content")
+		return
+	}
+	imported := &crdv1.VolumeSnapshot{
+		ObjectMeta: im.importedObjectMeta(snapshot.ObjectMeta),
+		Spec: crdv1.VolumeSnapshotSpec{
+			Source: crdv1.VolumeSnapshotSource{
+				VolumeSnapshotContentName: contentName,
+			},
+			VolumeSnapshotClassName: snapshot.Spec.VolumeSnapshotClassName,
+		},
+	}
+	im.create(kindVolumeSnapshot, imported.ObjectMeta, func() error {
+		_, err := im.o.SnapshotClient.SnapshotV1().VolumeSnapshots(imported.Namespace).Create(ctx, imported, metav1.CreateOptions{})
+		return err
+	})
+}
+
+func (im *importer) importGroupSnapshotContent(ctx context.Context, content *crdv1beta1.VolumeGroupSnapshotContent) {
+	handles := groupContentSnapshotHandles(content)
+	if handles == nil {
+		im.fail(kindVolumeGroupSnapshotContent, content.Name, "it has no group snapshot handle")
+		return
+	}
+	imported := &crdv1beta1.VolumeGroupSnapshotContent{
+		ObjectMeta: im.importedObjectMeta(content.ObjectMeta),
+		Spec: crdv1beta1.VolumeGroupSnapshotContentSpec{
+			VolumeGroupSnapshotRef:       im.importedRef(content.Spec.VolumeGroupSnapshotRef),
+			DeletionPolicy:               crdv1.VolumeSnapshotContentRetain,
+			Driver:                       content.Spec.Driver,
+			VolumeGroupSnapshotClassName: content.Spec.VolumeGroupSnapshotClassName,
+			Source: crdv1beta1.VolumeGroupSnapshotContentSource{
+				GroupSnapshotHandles: handles,
+			},
+		},
+	}
+	im.create(kindVolumeGroupSnapshotContent, imported.ObjectMeta, func() error {
+		_, err := im.o.SnapshotClient.GroupsnapshotV1beta1().VolumeGroupSnapshotContents().Create(ctx, imported, metav1.CreateOptions{})
+		return err
+	})
+}
+
+func (im *importer) importGroupSnapshot(ctx context.Context, groupSnapshot *crdv1beta1.VolumeGroupSnapshot) {
+	contentName := groupSnapshot.Spec.Source.VolumeGroupSnapshotContentName
+	if groupSnapshot.Status != nil && groupSnapshot.Status.BoundVolumeGroupSnapshotContentName != nil {
+		contentName = groupSnapshot.Status.BoundVolumeGroupSnapshotContentName
+	}
+	if contentName == nil {
+		im.fail(kindVolumeGroupSnapshot, groupSnapshot.Name, "it is not bound to a content")
+		return
+	}
+	imported := &crdv1beta1.VolumeGroupSnapshot{
+		ObjectMeta: im.importedObjectMeta(groupSnapshot.ObjectMeta),
+		Spec: crdv1beta1.VolumeGroupSnapshotSpec{
+			Source: crdv1beta1.VolumeGroupSnapshotSource{
+				VolumeGroupSnapshotContentName: contentName,
+			},
+			VolumeGroupSnapshotClassName: groupSnapshot.Spec.VolumeGroupSnapshotClassName,
+		},
+	}
+	im.create(kindVolumeGroupSnapshot, imported.ObjectMeta, func() error {
+		_, err := im.o.SnapshotClient.GroupsnapshotV1beta1().VolumeGroupSnapshots(imported.Namespace).Create(ctx, imported, metav1.CreateOptions{})
+		return err
+	})
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectlsnapshot
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	"github.com/kubernetes-csi/external-snapshotter/client/v8/clientset/versioned/fake"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+)
+
+// exportToFile exports the objects of newExportObjects to a bundle file.
+func exportToFile(t *testing.T) string {
+	t.Helper()
+	out, _, err := runCommand(t, nil, newExportObjects(), "export")
+	if err != nil {
+		t.Fatalf("failed to export: %v", err)
+	}
+	filename := filepath.Join(t.TempDir(), "bundle.yaml")
+	if err := os.WriteFile(filename, []byte(out), 0o600); err != nil {
+		t.Fatalf("failed to write bundle: %v", err)
+	}
+	return filename
+}
+
+func TestImport(t *testing.T) {
+	filename := exportToFile(t)
+	var out, errOut bytes.Buffer
+	o := &Options{
+		KubeClient:     kubefake.NewSimpleClientset(),
+		SnapshotClient: fake.NewSimpleClientset(),
+		Namespace:      testNamespace,
+		Out:            &out,
+		ErrOut:         &errOut,
+	}
+	run := func(args ...string) error {
+		out.Reset()
+		cmd := NewCommand(o)
+		cmd.SetArgs(args)
+		return cmd.ExecuteContext(context.Background())
+	}
+
+	if err := run("import", "-f", filename, "--namespace-mapping", testNamespace+"=dr"); err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, errOut.String())
+	}
+	expected := "volumegroupsnapshotcontent/groupcontent created\n" +
+		"volumegroupsnapshot/group in namespace dr created\n" +
+		"volumesnapshotcontent/content-a created\n" +
+		"volumesnapshot/snap-a in namespace dr created\n"
+	if out.String() != expected {
+		t.Errorf("expected output:\n%s\ngot:\n%s", expected, out.String())
+	}
+
+	ctx := context.Background()
+	content, err := o.SnapshotClient.SnapshotV1().VolumeSnapshotContents().Get(ctx, "content-a", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get content: %v", err)
+	}
+	if content.Spec.DeletionPolicy != crdv1.VolumeSnapshotContentRetain || content.Spec.Source.SnapshotHandle == nil || *content.Spec.Source.SnapshotHandle != "handle-a" {
+		t.Errorf("expected a retained pre-provisioned content, got %+v", content.Spec)
+	}
+	ref := content.Spec.VolumeSnapshotRef
+	if ref.Namespace != "dr" || ref.Name != "snap-a" || ref.UID != "" {
+		t.Errorf("expected content to reference dr/snap-a, got %+v", ref)
+	}
+	if content.Annotations[utils.AnnDeletionSecretRefNamespace] != "dr" || content.Status != nil {
+		t.Errorf("unexpected imported content %+v", content)
+	}
+	snapshot, err := o.SnapshotClient.SnapshotV1().VolumeSnapshots("dr").Get(ctx, "snap-a", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get snapshot: %v", err)
+	}
+	if snapshot.Spec.Source.VolumeSnapshotContentName == nil || *snapshot.Spec.Source.VolumeSnapshotContentName != "content-a" || snapshot.Spec.Source.PersistentVolumeClaimName != nil {
+		t.Errorf("expected snapshot to be pre-provisioned from content-a, got %+v", snapshot.Spec)
+	}
+
+	groupContent, err := o.SnapshotClient.GroupsnapshotV1beta1().VolumeGroupSnapshotContents().Get(ctx, "groupcontent", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get group content: %v", err)
+	}
+	handles := groupContent.Spec.Source.GroupSnapshotHandles
+	if groupContent.Spec.DeletionPolicy != crdv1.VolumeSnapshotContentRetain || handles == nil ||
+		handles.VolumeGroupSnapshotHandle != "group-handle" || strings.Join(handles.VolumeSnapshotHandles, ",") != "handle-m" {
+		t.Errorf("expected a retained pre-provisioned group content, got %+v", groupContent.Spec)
+	}
+	groupSnapshot, err := o.SnapshotClient.GroupsnapshotV1beta1().VolumeGroupSnapshots("dr").Get(ctx, "group", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get group snapshot: %v", err)
+	}
+	if name := groupSnapshot.Spec.Source.VolumeGroupSnapshotContentName; name == nil || *name != "groupcontent" {
+		t.Errorf("expected group snapshot to be pre-provisioned from groupcontent, got %+v", groupSnapshot.Spec)
+	}
+
+	// Importing the bundle again leaves the objects unchanged.
+	if err := run("import", "-f", filename, "--namespace-mapping", testNamespace+"=dr"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Count(out.String(), "unchanged, it already exists") != 4 {
+		t.Errorf("expected the objects to be unchanged, got:\n%s", out.String())
+	}
+}
+
+func TestImportDryRun(t *testing.T) {
+	filename := exportToFile(t)
+	var out, errOut bytes.Buffer
+	client := fake.NewSimpleClientset()
+	o := &Options{
+		KubeClient:     kubefake.NewSimpleClientset(),
+		SnapshotClient: client,
+		Namespace:      testNamespace,
+		Out:            &out,
+		ErrOut:         &errOut,
+	}
+	cmd := NewCommand(o)
+	cmd.SetArgs([]string{"import", "-f", filename, "--dry-run"})
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Count(out.String(), "created (dry run)") != 4 {
+		t.Errorf("expected 4 objects to be reported, got:\n%s", out.String())
+	}
+	if len(client.Actions()) != 0 {
+		t.Errorf("expected no API calls in a dry run, got %v", client.Actions())
+	}
+}
+
+func TestImportInvalidBundle(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "bundle.yaml")
+	if err := os.WriteFile(filename, []byte("version: v2\n"), 0o600); err != nil {
+		t.Fatalf("failed to write bundle: %v", err)
+	}
+	_, _, err := runCommand(t, nil, nil, "import", "-f", filename)
+	if err == nil || !strings.Contains(err.Error(), "unsupported bundle version") {
+		t.Errorf("expected an unsupported version error, got %v", err)
+	}
+}
@@ -0,0 +1,564 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectlsnapshot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+)
+
+// Actions reported by the migrate-driver command.
+const (
+	actionRecreate = "recreate"
+	actionRebind   = "rebind"
+	actionSkip     = "skip"
+)
+
+// migrationPollInterval is how often the deletion of the old contents is checked.
+var migrationPollInterval = time.Second
+
+// MigrationResult is the outcome of the migration of one object.
+type MigrationResult struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Action    string `json:"action"`
+	Message   string `json:"message"`
+	Done      bool   `json:"done,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// MigrationReport is the output of the migrate-driver command.
+type MigrationReport struct {
+	From    string            `json:"from"`
+	To      string            `json:"to"`
+	DryRun  bool              `json:"dryRun"`
+	Results []MigrationResult `json:"results"`
+}
+
+// migrateOptions are the flags of the migrate-driver command.
+type migrateOptions struct {
+	from    string
+	to      string
+	dryRun  bool
+	timeout time.Duration
+	output  string
+}
+
+func newMigrateDriverCommand(o *Options) *cobra.Command {
+	mo := &migrateOptions{}
+	cmd := &cobra.Command{
+		Use:   "migrate-driver --from OLD --to NEW",
+		Short: "Move the snapshots of a CSI driver to its new name",
+		Long: "Move the VolumeSnapshotClasses and VolumeSnapshotContents of the CSI driver named --from to the driver " +
+			"named --to, for instance when the driver was renamed.\n\n" +
+			"The driver of a VolumeSnapshotClass cannot be changed while the class is in use, so each class is deleted " +
+			"and created again with the same name. The driver and the source of a VolumeSnapshotContent are immutable, " +
+			"so each content is recreated with the same name, bound to the same VolumeSnapshot, and with its snapshot " +
+			"handle as a pre-provisioned source, so that the VolumeSnapshots bound to the contents need no change. " +
+			"During the swap the deletion policy of the content is Retain, so the snapshot on the storage system is " +
+			"never deleted, and the finalizer of the old content is removed since no sidecar handles its driver. The " +
+			"original deletion policy is restored on the new content.\n\n" +
+			"Contents that are being created or deleted, and group snapshots, are skipped. With --dry-run, nothing is " +
+			"changed and the report shows what would be done. The command fails when an object cannot be migrated.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.runMigrateDriver(cmd.Context(), mo)
+		},
+	}
+	cmd.Flags().StringVar(&mo.from, "from", "", "Name of the CSI driver to migrate from.")
+	cmd.Flags().StringVar(&mo.to, "to", "", "Name of the CSI driver to migrate to.")
+	cmd.Flags().BoolVar(&mo.dryRun, "dry-run", false, "Only report what would be migrated.")
+	cmd.Flags().DurationVar(&mo.timeout, "timeout", time.Minute, "How long to wait for the deletion of each old content and of each dynamically provisioned VolumeSnapshot.")
+	cmd.Flags().StringVarP(&mo.output, "output", "o", "text", "Format of the report, text or json.")
+	return cmd
+}
+
+func (o *Options) runMigrateDriver(ctx context.Context, mo *migrateOptions) error {
+	if mo.from == "" || mo.to == "" {
+		return fmt.Errorf("both --from and --to are required")
+	}
+	if mo.from == mo.to {
+		return fmt.Errorf("--from and --to are the same driver %s", mo.from)
+	}
+	if mo.output != "text" && mo.output != "json" {
+		return fmt.Errorf("unsupported output format %q, expected text or json", mo.output)
+	}
+
+	m := &migration{o: o, mo: mo, report: &MigrationReport{From: mo.from, To: mo.to, DryRun: mo.dryRun}}
+	if err := m.migrateClasses(ctx); err != nil {
+		return err
+	}
+	if err := m.migrateContents(ctx); err != nil {
+		return err
+	}
+	if err := m.reportGroupSnapshots(ctx); err != nil {
+		return err
+	}
+
+	if mo.output == "json" {
+		data, err := json.MarshalIndent(m.report, "", "    ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(o.Out, string(data))
+	} else {
+		printMigrationReport(o, m.report)
+	}
+
+	var failed int
+	for _, result := range m.report.Results {
+		if result.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to migrate %d objects", failed)
+	}
+	return nil
+}
+
+func printMigrationReport(o *Options, report *MigrationReport) {
+	if report.DryRun {
+		fmt.Fprintf(o.Out, "Migration from driver %s to %s (dry run).\n", report.From, report.To)
+	} else {
+		fmt.Fprintf(o.Out, "Migration from driver %s to %s.\n", report.From, report.To)
+	}
+	if len(report.Results) == 0 {
+		fmt.Fprintln(o.Out, "No objects use the driver.")
+		return
+	}
+
+	w := tabwriter.NewWriter(o.Out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "KIND\tNAME\tACTION\tRESULT\tMESSAGE")
+	for _, result := range report.Results {
+		name := result.Name
+		if result.Namespace != "" {
+			name = result.Namespace + "/" + result.Name
+		}
+		status := "-"
+		switch {
+		case result.Error != "":
+			status = "failed: " + result.Error
+		case result.Done:
+			status = "done"
+		case result.Action != actionSkip && report.DryRun:
+			status = "dry run"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", result.Kind, name, result.Action, status, result.Message)
+	}
+	w.Flush()
+}
+
+// migration holds the state of the migrate-driver command.
+type migration struct {
+	o      *Options
+	mo     *migrateOptions
+	report *MigrationReport
+}
+
+// record adds the result of an object to the report, running the migration
+// step unless it is a dry run.
+func (m *migration) record(result MigrationResult, step func() error) {
+	if step != nil && !m.mo.dryRun {
+		if err := step(); err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Done = true
+		}
+	}
+	m.report.Results = append(m.report.Results, result)
+}
+
+// migrateClasses recreates the VolumeSnapshotClasses of the old driver with
+// the new one.
+func (m *migration) migrateClasses(ctx context.Context) error {
+	classes, err := m.o.SnapshotClient.SnapshotV1().VolumeSnapshotClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list volume snapshot classes: %w", err)
+	}
+	for i := range classes.Items {
+		class := &classes.Items[i]
+		if class.Driver != m.mo.from {
+			continue
+		}
+		result := MigrationResult{
+			Kind:    kindVolumeSnapshotClass,
+			Name:    class.Name,
+			Action:  actionRecreate,
+			Message: fmt.Sprintf("deletion policy %s", class.DeletionPolicy),
+		}
+		m.record(result, func() error { return m.recreateClass(ctx, class) })
+	}
+	return nil
+}
+
+func (m *migration) recreateClass(ctx context.Context, class *crdv1.VolumeSnapshotClass) error {
+	newClass := &crdv1.VolumeSnapshotClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        class.Name,
+			Labels:      class.Labels,
+			Annotations: class.Annotations,
+		},
+		Driver:         m.mo.to,
+		Parameters:     class.Parameters,
+		DeletionPolicy: class.DeletionPolicy,
+	}
+	err := m.o.SnapshotClient.SnapshotV1().VolumeSnapshotClasses().Delete(ctx, class.Name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{UID: &class.UID},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete volume snapshot class: %w", err)
+	}
+	if _, err := m.o.SnapshotClient.SnapshotV1().VolumeSnapshotClasses().Create(ctx, newClass, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create volume snapshot class after deleting it: %w", err)
+	}
+	return nil
+}
+
+// migrateContents recreates the VolumeSnapshotContents of the old driver
+// with the new one, and checks that their VolumeSnapshots are bound to the
+// new contents.
+func (m *migration) migrateContents(ctx context.Context) error {
+	contents, err := m.o.SnapshotClient.SnapshotV1().VolumeSnapshotContents().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list volume snapshot contents: %w", err)
+	}
+	for i := range contents.Items {
+		content := &contents.Items[i]
+		if content.Spec.Driver != m.mo.from {
+			continue
+		}
+		result := MigrationResult{
+			Kind: kindVolumeSnapshotContent,
+			Name: content.Name,
+		}
+		snapshotHandle := contentSnapshotHandle(content)
+		switch {
+		case content.DeletionTimestamp != nil:
+			result.Action, result.Message = actionSkip, "the content is being deleted"
+		case metav1.HasAnnotation(content.ObjectMeta, utils.AnnVolumeSnapshotBeingCreated):
+			result.Action, result.Message = actionSkip, "the snapshot is being created"
+		case metav1.HasAnnotation(content.ObjectMeta, utils.VolumeGroupSnapshotHandleAnnotation):
+			result.Action, result.Message = actionSkip, "the content is a member of a group snapshot, group snapshots are not migrated"
+		case snapshotHandle == "":
+			result.Action, result.Message = actionSkip, "the snapshot has not been cut yet"
+		}
+		if result.Action == actionSkip {
+			m.record(result, nil)
+			continue
+		}
+
+		result.Action = actionRecreate
+		result.Message = fmt.Sprintf("snapshot handle %s, deletion policy %s", snapshotHandle, content.Spec.DeletionPolicy)
+		snapshot, err := m.dynamicSnapshot(ctx, content)
+		if err != nil {
+			result.Error = err.Error()
+			m.record(result, nil)
+			continue
+		}
+		var recreated *crdv1.VolumeSnapshot
+		m.record(result, func() (err error) {
+			recreated, err = m.recreateContent(ctx, content, snapshotHandle, snapshot)
+			return err
+		})
+		if m.report.Results[len(m.report.Results)-1].Error != "" {
+			continue
+		}
+
+		ref := content.Spec.VolumeSnapshotRef
+		if ref.UID == "" {
+			continue
+		}
+		if snapshot != nil {
+			m.record(MigrationResult{
+				Kind:      kindVolumeSnapshot,
+				Namespace: ref.Namespace,
+				Name:      ref.Name,
+				Action:    actionRecreate,
+				Message:   fmt.Sprintf("dynamically provisioned, recreated from the new content %s", content.Name),
+			}, func() error { return m.checkSnapshotBinding(ctx, content, recreated.UID) })
+			continue
+		}
+		m.record(MigrationResult{
+			Kind:      kindVolumeSnapshot,
+			Namespace: ref.Namespace,
+			Name:      ref.Name,
+			Action:    actionRebind,
+			Message:   fmt.Sprintf("bound to the new content %s", content.Name),
+		}, func() error { return m.checkSnapshotBinding(ctx, content, ref.UID) })
+	}
+	return nil
+}
+
+// dynamicSnapshot returns the VolumeSnapshot the content was dynamically
+// provisioned for, or nil if the content is not bound to one. A dynamically
+// provisioned VolumeSnapshot cannot be bound to a pre-provisioned content, so
+// it is recreated from the new content.
+func (m *migration) dynamicSnapshot(ctx context.Context, content *crdv1.VolumeSnapshotContent) (*crdv1.VolumeSnapshot, error) {
+	ref := content.Spec.VolumeSnapshotRef
+	if ref.UID == "" {
+		return nil, nil
+	}
+	snapshot, err := m.o.SnapshotClient.SnapshotV1().VolumeSnapshots(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if apierrs.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get volume snapshot %s/%s: %w", ref.Namespace, ref.Name, err)
+	}
+	if snapshot.UID != ref.UID || snapshot.Spec.Source.PersistentVolumeClaimName == nil {
+		return nil, nil
+	}
+	return snapshot, nil
+}
+
+// contentSnapshotHandle returns the handle of the snapshot on the storage
+// system, or an empty string if it has not been cut yet.
+func contentSnapshotHandle(content *crdv1.VolumeSnapshotContent) string {
+	if content.Status != nil && content.Status.SnapshotHandle != nil {
+		return *content.Status.SnapshotHandle
+	}
+	if content.Spec.Source.SnapshotHandle != nil {
+		return *content.Spec.Source.SnapshotHandle
+	}
+	return ""
+}
+
+// recreateContent swaps a content of the old driver for a pre-provisioned
+// content of the new driver with the same name and snapshot handle. The
+// dynamically provisioned VolumeSnapshot of the content, if any, is swapped
+// for a pre-provisioned VolumeSnapshot with the same name, which is returned.
+func (m *migration) recreateContent(ctx context.Context, content *crdv1.VolumeSnapshotContent, snapshotHandle string, snapshot *crdv1.VolumeSnapshot) (*crdv1.VolumeSnapshot, error) {
+	client := m.o.SnapshotClient.SnapshotV1().VolumeSnapshotContents()
+
+	// Retain the snapshot on the storage system while the content and its
+	// VolumeSnapshot are deleted.
+	if content.Spec.DeletionPolicy != crdv1.VolumeSnapshotContentRetain {
+		if err := m.setContentDeletionPolicy(ctx, content, crdv1.VolumeSnapshotContentRetain); err != nil {
+			return nil, fmt.Errorf("failed to set the deletion policy to Retain: %w", err)
+		}
+	}
+	if snapshot != nil {
+		if err := m.deleteSnapshot(ctx, snapshot); err != nil {
+			return nil, fmt.Errorf("%w, snapshot handle %s is retained", err, snapshotHandle)
+		}
+	}
+	err := client.Delete(ctx, content.Name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{UID: &content.UID},
+	})
+	if err != nil && !apierrs.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to delete the content: %w", err)
+	}
+
+	// No sidecar handles the old driver any more to remove the finalizer.
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current, err := client.Get(ctx, content.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if current.UID != content.UID || !slices.Contains(current.Finalizers, utils.VolumeSnapshotContentFinalizer) {
+			return nil
+		}
+		current.Finalizers = utils.RemoveString(current.Finalizers, utils.VolumeSnapshotContentFinalizer)
+		_, err = client.Update(ctx, current, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil && !apierrs.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to remove the finalizer of the content, snapshot handle %s is retained: %w", snapshotHandle, err)
+	}
+
+	err = wait.PollUntilContextTimeout(ctx, migrationPollInterval, m.mo.timeout, true, func(ctx context.Context) (bool, error) {
+		current, err := client.Get(ctx, content.Name, metav1.GetOptions{})
+		if apierrs.IsNotFound(err) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return current.UID != content.UID, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed waiting for the deletion of the content, snapshot handle %s is retained: %w", snapshotHandle, err)
+	}
+
+	annotations := map[string]string{}
+	for key, value := range content.Annotations {
+		if key != utils.AnnVolumeSnapshotBeingDeleted {
+			annotations[key] = value
+		}
+	}
+	// The recreated VolumeSnapshot binds to the new content with its own UID.
+	ref := content.Spec.VolumeSnapshotRef
+	if snapshot != nil {
+		ref.UID, ref.ResourceVersion = "", ""
+	}
+	newContent := &crdv1.VolumeSnapshotContent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        content.Name,
+			Labels:      content.Labels,
+			Annotations: annotations,
+		},
+		Spec: crdv1.VolumeSnapshotContentSpec{
+			VolumeSnapshotRef:       ref,
+			DeletionPolicy:          crdv1.VolumeSnapshotContentRetain,
+			Driver:                  m.mo.to,
+			VolumeSnapshotClassName: content.Spec.VolumeSnapshotClassName,
+			Source: crdv1.VolumeSnapshotContentSource{
+				SnapshotHandle: &snapshotHandle,
+			},
+			SourceVolumeMode: content.Spec.SourceVolumeMode,
+		},
+	}
+	if _, err := client.Create(ctx, newContent, metav1.CreateOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to create the new content, snapshot handle %s is retained: %w", snapshotHandle, err)
+	}
+
+	var recreated *crdv1.VolumeSnapshot
+	if snapshot != nil {
+		newSnapshot := &crdv1.VolumeSnapshot{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        snapshot.Name,
+				Namespace:   snapshot.Namespace,
+				Labels:      snapshot.Labels,
+				Annotations: snapshot.Annotations,
+			},
+			Spec: crdv1.VolumeSnapshotSpec{
+				Source: crdv1.VolumeSnapshotSource{
+					VolumeSnapshotContentName: &content.Name,
+				},
+				VolumeSnapshotClassName: snapshot.Spec.VolumeSnapshotClassName,
+			},
+		}
+		recreated, err = m.o.SnapshotClient.SnapshotV1().VolumeSnapshots(snapshot.Namespace).Create(ctx, newSnapshot, metav1.CreateOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the volume snapshot from the new content, snapshot handle %s is retained: %w", snapshotHandle, err)
+		}
+	}
+
+	if content.Spec.DeletionPolicy != crdv1.VolumeSnapshotContentRetain {
+		if err := m.setContentDeletionPolicy(ctx, newContent, content.Spec.DeletionPolicy); err != nil {
+			return nil, fmt.Errorf("failed to restore the deletion policy %s: %w", content.Spec.DeletionPolicy, err)
+		}
+	}
+	return recreated, nil
+}
+
+// setContentDeletionPolicy sets the deletion policy of the content with the
+// name of content. The content is read again on conflicts, the controllers
+// update it concurrently.
+func (m *migration) setContentDeletionPolicy(ctx context.Context, content *crdv1.VolumeSnapshotContent, policy crdv1.DeletionPolicy) error {
+	client := m.o.SnapshotClient.SnapshotV1().VolumeSnapshotContents()
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current, err := client.Get(ctx, content.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if current.Spec.DeletionPolicy == policy {
+			return nil
+		}
+		current.Spec.DeletionPolicy = policy
+		_, err = client.Update(ctx, current, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// deleteSnapshot deletes a VolumeSnapshot and waits until the snapshot
+// controller has removed its finalizers.
+func (m *migration) deleteSnapshot(ctx context.Context, snapshot *crdv1.VolumeSnapshot) error {
+	client := m.o.SnapshotClient.SnapshotV1().VolumeSnapshots(snapshot.Namespace)
+	err := client.Delete(ctx, snapshot.Name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{UID: &snapshot.UID},
+	})
+	if err != nil && !apierrs.IsNotFound(err) {
+		return fmt.Errorf("failed to delete the volume snapshot: %w", err)
+	}
+	err = wait.PollUntilContextTimeout(ctx, migrationPollInterval, m.mo.timeout, true, func(ctx context.Context) (bool, error) {
+		current, err := client.Get(ctx, snapshot.Name, metav1.GetOptions{})
+		if apierrs.IsNotFound(err) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return current.UID != snapshot.UID, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed waiting for the deletion of the volume snapshot: %w", err)
+	}
+	return nil
+}
+
+// checkSnapshotBinding checks that the VolumeSnapshot the content was bound to
+// still exists and is bound to the new content with the same name.
+func (m *migration) checkSnapshotBinding(ctx context.Context, content *crdv1.VolumeSnapshotContent, uid types.UID) error {
+	ref := content.Spec.VolumeSnapshotRef
+	snapshot, err := m.o.SnapshotClient.SnapshotV1().VolumeSnapshots(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get volume snapshot: %w", err)
+	}
+	if snapshot.UID != uid {
+		return fmt.Errorf("the volume snapshot was recreated, its UID is %s instead of %s", snapshot.UID, uid)
+	}
+	if snapshot.Status != nil && snapshot.Status.BoundVolumeSnapshotContentName != nil && *snapshot.Status.BoundVolumeSnapshotContentName != content.Name {
+		return fmt.Errorf("the volume snapshot is bound to content %s", *snapshot.Status.BoundVolumeSnapshotContentName)
+	}
+	return nil
+}
+
+// reportGroupSnapshots reports the group snapshot classes and contents of the
+// old driver, which are not migrated.
+func (m *migration) reportGroupSnapshots(ctx context.Context) error {
+	classes, err := m.o.SnapshotClient.GroupsnapshotV1beta1().VolumeGroupSnapshotClasses().List(ctx, metav1.ListOptions{})
+	// The group snapshot CRDs are optional.
+	if apierrs.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to list volume group snapshot classes: %w", err)
+	}
+	for _, class := range classes.Items {
+		if class.Driver == m.mo.from {
+			m.record(MigrationResult{Kind: kindVolumeGroupSnapshotClass, Name: class.Name, Action: actionSkip, Message: "group snapshots are not migrated"}, nil)
+		}
+	}
+
+	contents, err := m.o.SnapshotClient.GroupsnapshotV1beta1().VolumeGroupSnapshotContents().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list volume group snapshot contents: %w", err)
+	}
+	for _, content := range contents.Items {
+		if content.Spec.Driver == m.mo.from {
+			m.record(MigrationResult{Kind: kindVolumeGroupSnapshotContent, Name: content.Name, Action: actionSkip, Message: "group snapshots are not migrated"}, nil)
+		}
+	}
+	return nil
+}
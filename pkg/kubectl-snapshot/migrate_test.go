@@ -0,0 +1,229 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectlsnapshot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	"github.com/kubernetes-csi/external-snapshotter/client/v8/clientset/versioned/fake"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+)
+
+func newMigrationObjects() []runtime.Object {
+	class := &crdv1.VolumeSnapshotClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "gold",
+			Annotations: map[string]string{utils.IsDefaultSnapshotClassAnnotation: "true"},
+		},
+		Driver:         "csi-mock-plugin",
+		Parameters:     map[string]string{"type": "incremental"},
+		DeletionPolicy: crdv1.VolumeSnapshotContentDelete,
+	}
+	otherClass := &crdv1.VolumeSnapshotClass{
+		ObjectMeta:     metav1.ObjectMeta{Name: "silver"},
+		Driver:         "other-plugin",
+		DeletionPolicy: crdv1.VolumeSnapshotContentDelete,
+	}
+
+	// content-a was dynamically provisioned for snap-a
+	className := class.Name
+	volumeHandle := "volume-a"
+	dynamic := withFinalizers(newContent("content-a", "snap-a", "handle-a", &True), utils.VolumeSnapshotContentFinalizer)
+	dynamic.Spec.Source.VolumeHandle = &volumeHandle
+	dynamic.Spec.VolumeSnapshotClassName = &className
+	dynamic.Annotations = map[string]string{utils.AnnDeletionSecretRefName: "secret", utils.AnnDeletionSecretRefNamespace: "default"}
+
+	// content-b is pre-provisioned and retained
+	retained := withFinalizers(newContent("content-b", "snap-b", "handle-b", &True), utils.VolumeSnapshotContentFinalizer)
+	handle := "handle-b"
+	retained.Spec.Source.SnapshotHandle = &handle
+	retained.Spec.DeletionPolicy = crdv1.VolumeSnapshotContentRetain
+
+	preProvisioned := newSnapshot("snap-b", "", "content-b", &True, "1Gi")
+	contentName := retained.Name
+	preProvisioned.Spec.Source = crdv1.VolumeSnapshotSource{VolumeSnapshotContentName: &contentName}
+
+	creating := newContent("content-c", "snap-c", "", &False)
+	creating.Status.SnapshotHandle = nil
+	creating.Annotations = map[string]string{utils.AnnVolumeSnapshotBeingCreated: "yes"}
+
+	other := newContent("content-d", "snap-d", "handle-d", &True)
+	other.Spec.Driver = "other-plugin"
+
+	return []runtime.Object{
+		class, otherClass,
+		newSnapshot("snap-a", "claim-a", "content-a", &True, "1Gi"), dynamic,
+		preProvisioned, retained,
+		creating, other,
+		newGroupSnapshotContent("groupcontent", "group-handle", &True),
+	}
+}
+
+func TestMigrateDriverDryRun(t *testing.T) {
+	out, _, err := runCommand(t, nil, newMigrationObjects(), "migrate-driver", "--from", "csi-mock-plugin", "--to", "new-plugin", "--dry-run", "-o", "json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	report := &MigrationReport{}
+	if err := json.Unmarshal([]byte(out), report); err != nil {
+		t.Fatalf("failed to decode the report: %v\n%s", err, out)
+	}
+	if !report.DryRun || report.From != "csi-mock-plugin" || report.To != "new-plugin" {
+		t.Errorf("unexpected report %+v", report)
+	}
+
+	var results []string
+	for _, result := range report.Results {
+		if result.Done || result.Error != "" {
+			t.Errorf("expected nothing to be done in a dry run, got %+v", result)
+		}
+		results = append(results, strings.Join([]string{result.Kind, result.Name, result.Action}, " "))
+	}
+	expected := []string{
+		"VolumeSnapshotClass gold recreate",
+		"VolumeSnapshotContent content-a recreate",
+		"VolumeSnapshot snap-a recreate",
+		"VolumeSnapshotContent content-b recreate",
+		"VolumeSnapshot snap-b rebind",
+		"VolumeSnapshotContent content-c skip",
+		"VolumeGroupSnapshotContent groupcontent skip",
+	}
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("expected results:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(results, "\n"))
+	}
+}
+
+func TestMigrateDriver(t *testing.T) {
+	var out, errOut bytes.Buffer
+	client := fake.NewSimpleClientset(newMigrationObjects()...)
+	// The first restore of the deletion policy conflicts with an update of
+	// the controllers.
+	conflicted := false
+	client.PrependReactor("update", "volumesnapshotcontents", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		content := action.(k8stesting.UpdateAction).GetObject().(*crdv1.VolumeSnapshotContent)
+		if conflicted || content.Spec.Driver != "new-plugin" {
+			return false, nil, nil
+		}
+		conflicted = true
+		return true, nil, apierrs.NewConflict(crdv1.Resource("volumesnapshotcontents"), content.Name, errors.New("object has been modified"))
+	})
+	o := &Options{
+		KubeClient:     kubefake.NewSimpleClientset(),
+		SnapshotClient: client,
+		Namespace:      testNamespace,
+		Out:            &out,
+		ErrOut:         &errOut,
+	}
+	cmd := NewCommand(o)
+	cmd.SetArgs([]string{"migrate-driver", "--from", "csi-mock-plugin", "--to", "new-plugin"})
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, out.String())
+	}
+	if strings.Count(out.String(), " done ") != 5 {
+		t.Errorf("expected 5 migrated objects, got:\n%s", out.String())
+	}
+
+	// The snapshot must be retained until the new content exists.
+	for _, action := range client.Actions() {
+		if action.GetResource().Resource != "volumesnapshotcontents" || action.GetVerb() != "create" {
+			continue
+		}
+		content := action.(k8stesting.CreateAction).GetObject().(*crdv1.VolumeSnapshotContent)
+		if content.Spec.DeletionPolicy != crdv1.VolumeSnapshotContentRetain {
+			t.Errorf("expected content %s to be created with the Retain deletion policy, got %s", content.Name, content.Spec.DeletionPolicy)
+		}
+	}
+
+	ctx := context.Background()
+	class, err := client.SnapshotV1().VolumeSnapshotClasses().Get(ctx, "gold", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get class: %v", err)
+	}
+	if class.Driver != "new-plugin" || class.Parameters["type"] != "incremental" || !utils.IsVolumeSnapshotClassDefaultAnnotation(class.ObjectMeta) {
+		t.Errorf("unexpected class after migration %+v", class)
+	}
+
+	content, err := client.SnapshotV1().VolumeSnapshotContents().Get(ctx, "content-a", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get content: %v", err)
+	}
+	if content.Spec.Driver != "new-plugin" || content.Spec.DeletionPolicy != crdv1.VolumeSnapshotContentDelete {
+		t.Errorf("unexpected content after migration %+v", content.Spec)
+	}
+	if content.Spec.Source.VolumeHandle != nil || content.Spec.Source.SnapshotHandle == nil || *content.Spec.Source.SnapshotHandle != "handle-a" {
+		t.Errorf("expected content to be pre-provisioned from handle-a, got %+v", content.Spec.Source)
+	}
+	if ref := content.Spec.VolumeSnapshotRef; ref.Name != "snap-a" || ref.UID != "" || content.Spec.VolumeSnapshotClassName == nil || *content.Spec.VolumeSnapshotClassName != "gold" {
+		t.Errorf("expected content to be bound to the recreated snap-a with class gold, got %+v", content.Spec)
+	}
+	if content.Annotations[utils.AnnDeletionSecretRefName] != "secret" || len(content.Finalizers) != 0 {
+		t.Errorf("unexpected metadata after migration %+v", content.ObjectMeta)
+	}
+
+	if !conflicted {
+		t.Errorf("expected the restore of the deletion policy to conflict")
+	}
+
+	// snap-a was dynamically provisioned, it is recreated from the new
+	// content since a dynamically provisioned snapshot cannot be bound to a
+	// pre-provisioned content.
+	snapshot, err := client.SnapshotV1().VolumeSnapshots(testNamespace).Get(ctx, "snap-a", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get snapshot: %v", err)
+	}
+	if snapshot.Spec.Source.PersistentVolumeClaimName != nil || snapshot.Spec.Source.VolumeSnapshotContentName == nil || *snapshot.Spec.Source.VolumeSnapshotContentName != "content-a" {
+		t.Errorf("expected snap-a to be recreated from content-a, got %+v", snapshot.Spec.Source)
+	}
+	snapshot, _ = client.SnapshotV1().VolumeSnapshots(testNamespace).Get(ctx, "snap-b", metav1.GetOptions{})
+	if snapshot.UID != "uid-snap-b" {
+		t.Errorf("expected pre-provisioned snap-b to be kept, got %+v", snapshot.ObjectMeta)
+	}
+
+	content, _ = client.SnapshotV1().VolumeSnapshotContents().Get(ctx, "content-b", metav1.GetOptions{})
+	if content.Spec.Driver != "new-plugin" || content.Spec.DeletionPolicy != crdv1.VolumeSnapshotContentRetain {
+		t.Errorf("unexpected content after migration %+v", content.Spec)
+	}
+	for _, name := range []string{"content-c", "content-d"} {
+		content, _ = client.SnapshotV1().VolumeSnapshotContents().Get(ctx, name, metav1.GetOptions{})
+		if content.Spec.Driver == "new-plugin" {
+			t.Errorf("expected %s not to be migrated", name)
+		}
+	}
+}
+
+func TestMigrateDriverSnapshotRecreated(t *testing.T) {
+	objects := newMigrationObjects()
+	// snap-a was deleted and created again since content-a was bound to it
+	objects[2].(*crdv1.VolumeSnapshot).UID = "new-uid"
+	_, _, err := runCommand(t, nil, objects, "migrate-driver", "--from", "csi-mock-plugin", "--to", "new-plugin")
+	if err == nil || err.Error() != "failed to migrate 1 objects" {
+		t.Errorf("expected error %q, got %v", "failed to migrate 1 objects", err)
+	}
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectlsnapshot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+)
+
+// restoreOptions are the flags of the restore command.
+type restoreOptions struct {
+	name             string
+	storageClassName string
+	accessModes      []string
+	size             string
+	create           bool
+	output           string
+}
+
+func newRestoreCommand(o *Options) *cobra.Command {
+	ro := &restoreOptions{}
+	cmd := &cobra.Command{
+		Use:   "restore VOLUMESNAPSHOT",
+		Short: "Generate a PersistentVolumeClaim restoring a VolumeSnapshot",
+		Long: "Generate a PersistentVolumeClaim whose data source is the VolumeSnapshot and whose size is the restore size " +
+			"of the snapshot. The storage class, access modes and volume mode default to the ones of the source " +
+			"PersistentVolumeClaim of the snapshot when it still exists. The claim is printed, unless --create is set.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.runRestore(cmd.Context(), ro, args[0])
+		},
+	}
+	cmd.Flags().StringVar(&ro.name, "name", "", "Name of the PersistentVolumeClaim. Defaults to the name of the VolumeSnapshot followed by \"-restore\".")
+	cmd.Flags().StringVar(&ro.storageClassName, "storage-class", "", "StorageClass of the PersistentVolumeClaim.")
+	cmd.Flags().StringSliceVar(&ro.accessModes, "access-mode", nil, "Access modes of the PersistentVolumeClaim. Defaults to ReadWriteOnce if the source claim is gone.")
+	cmd.Flags().StringVar(&ro.size, "size", "", "Requested storage of the PersistentVolumeClaim. Defaults to the restore size of the VolumeSnapshot, and cannot be smaller.")
+	cmd.Flags().BoolVar(&ro.create, "create", false, "Create the PersistentVolumeClaim instead of printing it.")
+	cmd.Flags().StringVarP(&ro.output, "output", "o", "yaml", "Format of the printed PersistentVolumeClaim, yaml or json.")
+	return cmd
+}
+
+func (o *Options) runRestore(ctx context.Context, ro *restoreOptions, snapshotName string) error {
+	if ro.output != "yaml" && ro.output != "json" {
+		return fmt.Errorf("unsupported output format %q, expected yaml or json", ro.output)
+	}
+	claim, err := o.restoredClaim(ctx, ro, snapshotName)
+	if err != nil {
+		return err
+	}
+	if ro.create {
+		created, err := o.KubeClient.CoreV1().PersistentVolumeClaims(claim.Namespace).Create(ctx, claim, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to create persistent volume claim %s: %w", claim.Name, err)
+		}
+		fmt.Fprintf(o.Out, "persistentvolumeclaim/%s created\n", created.Name)
+		return nil
+	}
+
+	var data []byte
+	if ro.output == "json" {
+		data, err = json.MarshalIndent(claim, "", "    ")
+		data = append(data, '\n')
+	} else {
+		data, err = yaml.Marshal(claim)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = o.Out.Write(data)
+	return err
+}
+
+// restoredClaim builds the PersistentVolumeClaim restoring the snapshot.
+func (o *Options) restoredClaim(ctx context.Context, ro *restoreOptions, snapshotName string) (*v1.PersistentVolumeClaim, error) {
+	snapshot, err := o.SnapshotClient.SnapshotV1().VolumeSnapshots(o.Namespace).Get(ctx, snapshotName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get volume snapshot %s: %w", snapshotName, err)
+	}
+	if !utils.IsSnapshotReady(snapshot) {
+		fmt.Fprintf(o.ErrOut, "Warning: volume snapshot %s is not ready to use, the claim will stay pending until it is\n", snapshotName)
+	}
+
+	var size resource.Quantity
+	if snapshot.Status != nil && snapshot.Status.RestoreSize != nil {
+		size = *snapshot.Status.RestoreSize
+	}
+	if ro.size != "" {
+		requested, err := resource.ParseQuantity(ro.size)
+		if err != nil {
+			return nil, fmt.Errorf("invalid size %q: %w", ro.size, err)
+		}
+		if requested.Cmp(size) < 0 {
+			return nil, fmt.Errorf("size %s is smaller than the restore size %s of volume snapshot %s", requested.String(), size.String(), snapshotName)
+		}
+		size = requested
+	}
+	if size.IsZero() {
+		return nil, fmt.Errorf("the restore size of volume snapshot %s is unknown, use --size", snapshotName)
+	}
+
+	name := ro.name
+	if name == "" {
+		name = snapshotName + "-restore"
+	}
+	apiGroup := crdv1.GroupName
+	claim := &v1.PersistentVolumeClaim{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: kindPersistentVolumeClaim},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: o.Namespace,
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			DataSource: &v1.TypedLocalObjectReference{
+				APIGroup: &apiGroup,
+				Kind:     kindVolumeSnapshot,
+				Name:     snapshotName,
+			},
+			Resources: v1.VolumeResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceStorage: size},
+			},
+		},
+	}
+
+	// Default to the settings of the source claim, when it still exists.
+	if source := snapshot.Spec.Source.PersistentVolumeClaimName; source != nil && *source != "" {
+		sourceClaim, err := o.KubeClient.CoreV1().PersistentVolumeClaims(o.Namespace).Get(ctx, *source, metav1.GetOptions{})
+		switch {
+		case apierrs.IsNotFound(err):
+		case err != nil:
+			return nil, fmt.Errorf("failed to get persistent volume claim %s: %w", *source, err)
+		default:
+			claim.Spec.StorageClassName = sourceClaim.Spec.StorageClassName
+			claim.Spec.AccessModes = sourceClaim.Spec.AccessModes
+			claim.Spec.VolumeMode = sourceClaim.Spec.VolumeMode
+		}
+	}
+	if snapshot.Status != nil && snapshot.Status.BoundVolumeSnapshotContentName != nil {
+		content, err := o.SnapshotClient.SnapshotV1().VolumeSnapshotContents().Get(ctx, *snapshot.Status.BoundVolumeSnapshotContentName, metav1.GetOptions{})
+		switch {
+		case apierrs.IsNotFound(err):
+		case err != nil:
+			return nil, fmt.Errorf("failed to get volume snapshot content %s: %w", *snapshot.Status.BoundVolumeSnapshotContentName, err)
+		case content.Spec.SourceVolumeMode != nil:
+			claim.Spec.VolumeMode = content.Spec.SourceVolumeMode
+		}
+	}
+
+	if ro.storageClassName != "" {
+		claim.Spec.StorageClassName = &ro.storageClassName
+	}
+	if len(ro.accessModes) > 0 {
+		claim.Spec.AccessModes = nil
+		for _, mode := range ro.accessModes {
+			claim.Spec.AccessModes = append(claim.Spec.AccessModes, v1.PersistentVolumeAccessMode(mode))
+		}
+	}
+	if len(claim.Spec.AccessModes) == 0 {
+		claim.Spec.AccessModes = []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce}
+	}
+	return claim, nil
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectlsnapshot
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kubernetes-csi/external-snapshotter/client/v8/clientset/versioned/fake"
+)
+
+func TestRestore(t *testing.T) {
+	blockMode := v1.PersistentVolumeBlock
+	content := newContent("content-a", "snap-a", "handle-a", &True)
+	content.Spec.SourceVolumeMode = &blockMode
+	snapshotObjects := []runtime.Object{
+		newSnapshot("snap-a", "claim-a", "content-a", &True, "2Gi"),
+		content,
+		newSnapshot("snap-b", "claim-b", "", &False, ""),
+	}
+	kubeObjects := []runtime.Object{newClaim("claim-a", "pv-a")}
+
+	tests := []struct {
+		name                string
+		args                []string
+		expectedName        string
+		expectedSize        string
+		expectedClass       string
+		expectedAccessModes []v1.PersistentVolumeAccessMode
+		expectedVolumeMode  *v1.PersistentVolumeMode
+		expectWarning       bool
+		expectError         bool
+	}{
+		{
+			name:                "defaults from the source claim and the content",
+			args:                []string{"restore", "snap-a"},
+			expectedName:        "snap-a-restore",
+			expectedSize:        "2Gi",
+			expectedClass:       "gold",
+			expectedAccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteMany},
+			expectedVolumeMode:  &blockMode,
+		},
+		{
+			name:                "overrides",
+			args:                []string{"restore", "snap-a", "--name", "claim", "--size", "3Gi", "--storage-class", "silver", "--access-mode", "ReadWriteOnce,ReadOnlyMany"},
+			expectedName:        "claim",
+			expectedSize:        "3Gi",
+			expectedClass:       "silver",
+			expectedAccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce, v1.ReadOnlyMany},
+			expectedVolumeMode:  &blockMode,
+		},
+		{
+			name:                "snapshot not ready and source claim gone",
+			args:                []string{"restore", "snap-b", "--size", "1Gi"},
+			expectedName:        "snap-b-restore",
+			expectedSize:        "1Gi",
+			expectedAccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+			expectWarning:       true,
+		},
+		{
+			name:        "size smaller than the restore size",
+			args:        []string{"restore", "snap-a", "--size", "1Gi"},
+			expectError: true,
+		},
+		{
+			name:        "unknown restore size",
+			args:        []string{"restore", "snap-b"},
+			expectError: true,
+		},
+		{
+			name:        "missing snapshot",
+			args:        []string{"restore", "missing"},
+			expectError: true,
+		},
+	}
+	for _, test := range tests {
+		out, errOut, err := runCommand(t, kubeObjects, snapshotObjects, test.args...)
+		if test.expectError {
+			if err == nil {
+				t.Errorf("%s: expected error, got none", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if (errOut != "") != test.expectWarning {
+			t.Errorf("%s: expected warning %v, got %q", test.name, test.expectWarning, errOut)
+		}
+
+		claim := &v1.PersistentVolumeClaim{}
+		if err := yaml.UnmarshalStrict([]byte(out), claim); err != nil {
+			t.Errorf("%s: failed to decode the claim: %v", test.name, err)
+			continue
+		}
+		if claim.Name != test.expectedName || claim.Namespace != testNamespace {
+			t.Errorf("%s: expected claim %s/%s, got %s/%s", test.name, testNamespace, test.expectedName, claim.Namespace, claim.Name)
+		}
+		if source := claim.Spec.DataSource; source == nil || source.APIGroup == nil || *source.APIGroup != "snapshot.storage.k8s.io" || source.Kind != "VolumeSnapshot" || source.Name != test.args[1] {
+			t.Errorf("%s: unexpected data source %+v", test.name, source)
+		}
+		if size := claim.Spec.Resources.Requests[v1.ResourceStorage]; size.Cmp(resource.MustParse(test.expectedSize)) != 0 {
+			t.Errorf("%s: expected size %s, got %s", test.name, test.expectedSize, size.String())
+		}
+		var class string
+		if claim.Spec.StorageClassName != nil {
+			class = *claim.Spec.StorageClassName
+		}
+		if class != test.expectedClass {
+			t.Errorf("%s: expected storage class %q, got %q", test.name, test.expectedClass, class)
+		}
+		if len(claim.Spec.AccessModes) != len(test.expectedAccessModes) {
+			t.Errorf("%s: expected access modes %v, got %v", test.name, test.expectedAccessModes, claim.Spec.AccessModes)
+		}
+		for i := range test.expectedAccessModes {
+			if i < len(claim.Spec.AccessModes) && claim.Spec.AccessModes[i] != test.expectedAccessModes[i] {
+				t.Errorf("%s: expected access modes %v, got %v", test.name, test.expectedAccessModes, claim.Spec.AccessModes)
+			}
+		}
+		if (claim.Spec.VolumeMode == nil) != (test.expectedVolumeMode == nil) || (claim.Spec.VolumeMode != nil && *claim.Spec.VolumeMode != *test.expectedVolumeMode) {
+			t.Errorf("%s: expected volume mode %v, got %v", test.name, test.expectedVolumeMode, claim.Spec.VolumeMode)
+		}
+	}
+}
+
+func TestRestoreCreate(t *testing.T) {
+	o := &Options{
+		KubeClient:     kubefake.NewSimpleClientset(),
+		SnapshotClient: fake.NewSimpleClientset(newSnapshot("snap-a", "claim-a", "", &True, "1Gi")),
+		Namespace:      testNamespace,
+	}
+	out := &bytes.Buffer{}
+	o.Out, o.ErrOut = out, &bytes.Buffer{}
+	cmd := NewCommand(o)
+	cmd.SetArgs([]string{"restore", "snap-a", "--create"})
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := "persistentvolumeclaim/snap-a-restore created\n"; out.String() != expected {
+		t.Errorf("expected %q, got %q", expected, out.String())
+	}
+	if _, err := o.KubeClient.CoreV1().PersistentVolumeClaims(testNamespace).Get(context.Background(), "snap-a-restore", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected the claim to be created: %v", err)
+	}
+}
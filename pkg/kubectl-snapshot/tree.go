@@ -0,0 +1,279 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectlsnapshot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	crdv1beta1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumegroupsnapshot/v1beta1"
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+)
+
+func newTreeCommand(o *Options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "tree [VOLUMEGROUPSNAPSHOT]",
+		Short: "Show snapshots with their contents and source volumes",
+		Long: "Show the VolumeGroupSnapshots of the namespace with their VolumeGroupSnapshotContent, member VolumeSnapshots, " +
+			"VolumeSnapshotContents and source PersistentVolumeClaims and PersistentVolumes, followed by the VolumeSnapshots " +
+			"that are not a member of a group. When VOLUMEGROUPSNAPSHOT is given, only this group snapshot is shown.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.runTree(cmd.Context(), args)
+		},
+	}
+}
+
+// treeNode is a line of the output of the tree command.
+type treeNode struct {
+	label    string
+	children []*treeNode
+}
+
+func (n *treeNode) add(label string) *treeNode {
+	child := &treeNode{label: label}
+	n.children = append(n.children, child)
+	return child
+}
+
+func (n *treeNode) print(w io.Writer) {
+	fmt.Fprintln(w, n.label)
+	n.printChildren(w, "")
+}
+
+func (n *treeNode) printChildren(w io.Writer, prefix string) {
+	for i, child := range n.children {
+		branch, indent := "├── ", "│   "
+		if i == len(n.children)-1 {
+			branch, indent = "└── ", "    "
+		}
+		fmt.Fprintf(w, "%s%s%s\n", prefix, branch, child.label)
+		child.printChildren(w, prefix+indent)
+	}
+}
+
+func (o *Options) runTree(ctx context.Context, args []string) error {
+	snapshots, err := o.SnapshotClient.SnapshotV1().VolumeSnapshots(o.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list volume snapshots: %w", err)
+	}
+	sort.Slice(snapshots.Items, func(i, j int) bool { return snapshots.Items[i].Name < snapshots.Items[j].Name })
+
+	var groupSnapshots []crdv1beta1.VolumeGroupSnapshot
+	if len(args) == 1 {
+		groupSnapshot, err := o.SnapshotClient.GroupsnapshotV1beta1().VolumeGroupSnapshots(o.Namespace).Get(ctx, args[0], metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get volume group snapshot %s: %w", args[0], err)
+		}
+		groupSnapshots = append(groupSnapshots, *groupSnapshot)
+	} else {
+		list, err := o.SnapshotClient.GroupsnapshotV1beta1().VolumeGroupSnapshots(o.Namespace).List(ctx, metav1.ListOptions{})
+		// The group snapshot CRDs are optional, only show the snapshots when they are missing.
+		if err != nil && !apierrs.IsNotFound(err) {
+			return fmt.Errorf("failed to list volume group snapshots: %w", err)
+		}
+		if err == nil {
+			groupSnapshots = list.Items
+		}
+		sort.Slice(groupSnapshots, func(i, j int) bool { return groupSnapshots[i].Name < groupSnapshots[j].Name })
+	}
+
+	for i := range groupSnapshots {
+		root, err := o.groupSnapshotTree(ctx, &groupSnapshots[i], snapshots.Items)
+		if err != nil {
+			return err
+		}
+		root.print(o.Out)
+	}
+	if len(args) == 1 {
+		return nil
+	}
+	for i := range snapshots.Items {
+		snapshot := &snapshots.Items[i]
+		if groupSnapshotName(snapshot) != "" {
+			continue
+		}
+		root := &treeNode{}
+		if err := o.addSnapshotTree(ctx, root, snapshot); err != nil {
+			return err
+		}
+		root.children[0].print(o.Out)
+	}
+	return nil
+}
+
+// groupSnapshotName returns the name of the VolumeGroupSnapshot the snapshot is a member of,
+// from its owner reference or, until the owner reference is set, from its status.
+func groupSnapshotName(snapshot *crdv1.VolumeSnapshot) string {
+	if key := utils.VolumeSnapshotParentGroupKeyFunc(snapshot); key != "" {
+		_, name, _ := strings.Cut(key, "^")
+		return name
+	}
+	if snapshot.Status != nil && snapshot.Status.VolumeGroupSnapshotName != nil {
+		return *snapshot.Status.VolumeGroupSnapshotName
+	}
+	return ""
+}
+
+func (o *Options) groupSnapshotTree(ctx context.Context, groupSnapshot *crdv1beta1.VolumeGroupSnapshot, snapshots []crdv1.VolumeSnapshot) (*treeNode, error) {
+	var readyToUse *bool
+	var snapshotError *crdv1.VolumeSnapshotError
+	contentName := groupSnapshot.Spec.Source.VolumeGroupSnapshotContentName
+	if groupSnapshot.Status != nil {
+		readyToUse, snapshotError = groupSnapshot.Status.ReadyToUse, groupSnapshot.Status.Error
+		if groupSnapshot.Status.BoundVolumeGroupSnapshotContentName != nil {
+			contentName = groupSnapshot.Status.BoundVolumeGroupSnapshotContentName
+		}
+	}
+	root := &treeNode{label: fmt.Sprintf("%s %s/%s [%s]", kindVolumeGroupSnapshot, groupSnapshot.Namespace, groupSnapshot.Name,
+		objectStatus(groupSnapshot.DeletionTimestamp, readyToUse, snapshotError))}
+
+	parent := root
+	if contentName != nil {
+		content, err := o.SnapshotClient.GroupsnapshotV1beta1().VolumeGroupSnapshotContents().Get(ctx, *contentName, metav1.GetOptions{})
+		switch {
+		case apierrs.IsNotFound(err):
+			parent = root.add(fmt.Sprintf("%s %s [%s]", kindVolumeGroupSnapshotContent, *contentName, notFound))
+		case err != nil:
+			return nil, fmt.Errorf("failed to get volume group snapshot content %s: %w", *contentName, err)
+		default:
+			var handle string
+			readyToUse, snapshotError = nil, nil
+			if content.Status != nil {
+				readyToUse, snapshotError = content.Status.ReadyToUse, content.Status.Error
+				if content.Status.VolumeGroupSnapshotHandle != nil {
+					handle = *content.Status.VolumeGroupSnapshotHandle
+				}
+			}
+			if handle == "" && content.Spec.Source.GroupSnapshotHandles != nil {
+				handle = content.Spec.Source.GroupSnapshotHandles.VolumeGroupSnapshotHandle
+			}
+			parent = root.add(fmt.Sprintf("%s %s%s [%s]", kindVolumeGroupSnapshotContent, content.Name, handleLabel(handle),
+				objectStatus(content.DeletionTimestamp, readyToUse, snapshotError)))
+		}
+	}
+
+	for i := range snapshots {
+		if snapshots[i].Namespace == groupSnapshot.Namespace && groupSnapshotName(&snapshots[i]) == groupSnapshot.Name {
+			if err := o.addSnapshotTree(ctx, parent, &snapshots[i]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return root, nil
+}
+
+// addSnapshotTree adds the snapshot, its content and its source volume to the parent node.
+func (o *Options) addSnapshotTree(ctx context.Context, parent *treeNode, snapshot *crdv1.VolumeSnapshot) error {
+	var readyToUse *bool
+	var snapshotError *crdv1.VolumeSnapshotError
+	var details string
+	contentName := snapshot.Spec.Source.VolumeSnapshotContentName
+	if snapshot.Status != nil {
+		readyToUse, snapshotError = snapshot.Status.ReadyToUse, snapshot.Status.Error
+		if snapshot.Status.BoundVolumeSnapshotContentName != nil {
+			contentName = snapshot.Status.BoundVolumeSnapshotContentName
+		}
+		if snapshot.Status.RestoreSize != nil {
+			details = ", restore size " + snapshot.Status.RestoreSize.String()
+		}
+	}
+	node := parent.add(fmt.Sprintf("%s %s/%s [%s%s]", kindVolumeSnapshot, snapshot.Namespace, snapshot.Name,
+		objectStatus(snapshot.DeletionTimestamp, readyToUse, snapshotError), details))
+
+	var volumeHandle string
+	if contentName != nil {
+		content, err := o.SnapshotClient.SnapshotV1().VolumeSnapshotContents().Get(ctx, *contentName, metav1.GetOptions{})
+		switch {
+		case apierrs.IsNotFound(err):
+			node = node.add(fmt.Sprintf("%s %s [%s]", kindVolumeSnapshotContent, *contentName, notFound))
+		case err != nil:
+			return fmt.Errorf("failed to get volume snapshot content %s: %w", *contentName, err)
+		default:
+			var handle string
+			readyToUse, snapshotError = nil, nil
+			if content.Status != nil {
+				readyToUse, snapshotError = content.Status.ReadyToUse, content.Status.Error
+				if content.Status.SnapshotHandle != nil {
+					handle = *content.Status.SnapshotHandle
+				}
+			}
+			if handle == "" && content.Spec.Source.SnapshotHandle != nil {
+				handle = *content.Spec.Source.SnapshotHandle
+			}
+			if content.Spec.Source.VolumeHandle != nil {
+				volumeHandle = *content.Spec.Source.VolumeHandle
+			}
+			node = node.add(fmt.Sprintf("%s %s%s [%s]", kindVolumeSnapshotContent, content.Name, handleLabel(handle),
+				objectStatus(content.DeletionTimestamp, readyToUse, snapshotError)))
+		}
+	}
+
+	if snapshot.Spec.Source.PersistentVolumeClaimName != nil && *snapshot.Spec.Source.PersistentVolumeClaimName != "" {
+		label, err := o.volumeLabel(ctx, types.NamespacedName{Namespace: snapshot.Namespace, Name: *snapshot.Spec.Source.PersistentVolumeClaimName})
+		if err != nil {
+			return err
+		}
+		node.add(label)
+	} else if volumeHandle != "" {
+		node.add("volume handle " + volumeHandle)
+	}
+	return nil
+}
+
+// volumeLabel describes the claim and the volume it is bound to.
+func (o *Options) volumeLabel(ctx context.Context, claimKey types.NamespacedName) (string, error) {
+	claim, err := o.KubeClient.CoreV1().PersistentVolumeClaims(claimKey.Namespace).Get(ctx, claimKey.Name, metav1.GetOptions{})
+	if apierrs.IsNotFound(err) {
+		return fmt.Sprintf("%s %s [%s]", kindPersistentVolumeClaim, claimKey, notFound), nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get persistent volume claim %s: %w", claimKey, err)
+	}
+	label := fmt.Sprintf("%s %s [%s]", kindPersistentVolumeClaim, claimKey, strings.ToLower(string(claim.Status.Phase)))
+	if claim.Spec.VolumeName == "" {
+		return label, nil
+	}
+	volume, err := o.KubeClient.CoreV1().PersistentVolumes().Get(ctx, claim.Spec.VolumeName, metav1.GetOptions{})
+	if apierrs.IsNotFound(err) {
+		return fmt.Sprintf("%s → %s %s [%s]", label, kindPersistentVolume, claim.Spec.VolumeName, notFound), nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get persistent volume %s: %w", claim.Spec.VolumeName, err)
+	}
+	var handle string
+	if volume.Spec.CSI != nil {
+		handle = volume.Spec.CSI.VolumeHandle
+	}
+	return fmt.Sprintf("%s → %s %s%s", label, kindPersistentVolume, volume.Name, handleLabel(handle)), nil
+}
+
+func handleLabel(handle string) string {
+	if handle == "" {
+		return ""
+	}
+	return " (" + handle + ")"
+}
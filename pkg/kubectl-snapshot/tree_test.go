@@ -0,0 +1,76 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectlsnapshot
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestTree(t *testing.T) {
+	groupSnapshot := newGroupSnapshot("group", "groupcontent", &True)
+	kubeObjects := []runtime.Object{
+		newClaim("claim-a", "pv-a"),
+		newVolume("pv-a", "volume-a"),
+		newClaim("claim-c", ""),
+	}
+	snapshotObjects := []runtime.Object{
+		groupSnapshot,
+		newGroupSnapshotContent("groupcontent", "group-handle", &True),
+		withGroupSnapshotOwner(newSnapshot("member-a", "claim-a", "content-a", &True, "1Gi"), groupSnapshot),
+		withGroupSnapshotOwner(newSnapshot("member-b", "claim-b", "content-b", &True, "1Gi"), groupSnapshot),
+		newContent("content-a", "member-a", "handle-a", &True),
+		newSnapshot("snap-c", "claim-c", "content-c", &False, ""),
+		newContent("content-c", "snap-c", "handle-c", &False),
+	}
+
+	expectedGroup := `VolumeGroupSnapshot default/group [ready]
+└── VolumeGroupSnapshotContent groupcontent (group-handle) [ready]
+    ├── VolumeSnapshot default/member-a [ready, restore size 1Gi]
+    │   └── VolumeSnapshotContent content-a (handle-a) [ready]
+    │       └── PersistentVolumeClaim default/claim-a [bound] → PersistentVolume pv-a (volume-a)
+    └── VolumeSnapshot default/member-b [ready, restore size 1Gi]
+        └── VolumeSnapshotContent content-b [not found]
+            └── PersistentVolumeClaim default/claim-b [not found]
+`
+	expectedSnapshots := `VolumeSnapshot default/snap-c [not ready]
+└── VolumeSnapshotContent content-c (handle-c) [not ready]
+    └── PersistentVolumeClaim default/claim-c [bound]
+`
+	expected := expectedGroup + expectedSnapshots
+	out, _, err := runCommand(t, kubeObjects, snapshotObjects, "tree")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, out)
+	}
+
+	// Only the group snapshot is shown when it is named.
+	out, _, err = runCommand(t, kubeObjects, snapshotObjects, "tree", "group")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != expectedGroup {
+		t.Errorf("expected:\n%s\ngot:\n%s", expectedGroup, out)
+	}
+
+	if _, _, err = runCommand(t, kubeObjects, snapshotObjects, "tree", "missing"); err == nil {
+		t.Errorf("expected error for a missing group snapshot, got none")
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectlsnapshot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+)
+
+// waitOptions are the flags of the wait command.
+type waitOptions struct {
+	timeout  time.Duration
+	interval time.Duration
+}
+
+func newWaitCommand(o *Options) *cobra.Command {
+	wo := &waitOptions{}
+	cmd := &cobra.Command{
+		Use:   "wait NAME|KIND/NAME...",
+		Short: "Wait until snapshots are ready to use",
+		Long: "Wait until the VolumeSnapshots, or the VolumeGroupSnapshots given as vgs/NAME, are ready to use. " +
+			"Errors reported in the status of the snapshots are printed while waiting, since the snapshot " +
+			"controllers keep retrying.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return o.runWait(cmd.Context(), wo, args)
+		},
+	}
+	cmd.Flags().DurationVar(&wo.timeout, "timeout", 10*time.Minute, "How long to wait for the snapshots to be ready to use.")
+	cmd.Flags().DurationVar(&wo.interval, "interval", 2*time.Second, "How often the snapshots are checked.")
+	return cmd
+}
+
+func (o *Options) runWait(ctx context.Context, wo *waitOptions, args []string) error {
+	type object struct{ kind, name string }
+	objects := make([]object, 0, len(args))
+	for _, arg := range args {
+		kind, name, err := parseObject(arg, kindVolumeSnapshot)
+		if err != nil {
+			return err
+		}
+		if kind != kindVolumeSnapshot && kind != kindVolumeGroupSnapshot {
+			return fmt.Errorf("cannot wait for %s %s, only volume snapshots and volume group snapshots can be waited for", kind, name)
+		}
+		objects = append(objects, object{kind: kind, name: name})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, wo.timeout)
+	defer cancel()
+	for _, obj := range objects {
+		var lastError string
+		err := wait.PollUntilContextCancel(ctx, wo.interval, true, func(ctx context.Context) (bool, error) {
+			ready, snapshotError, err := o.snapshotReadiness(ctx, obj.kind, obj.name)
+			if err != nil {
+				return false, err
+			}
+			if snapshotError != "" && snapshotError != lastError {
+				fmt.Fprintf(o.ErrOut, "%s/%s: %s\n", strings.ToLower(obj.kind), obj.name, snapshotError)
+			}
+			lastError = snapshotError
+			return ready, nil
+		})
+		if wait.Interrupted(err) {
+			return fmt.Errorf("timed out waiting for %s %s to be ready to use", strings.ToLower(obj.kind), obj.name)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(o.Out, "%s/%s is ready to use\n", strings.ToLower(obj.kind), obj.name)
+	}
+	return nil
+}
+
+// snapshotReadiness returns whether the snapshot is ready to use, and the message of its error.
+func (o *Options) snapshotReadiness(ctx context.Context, kind, name string) (bool, string, error) {
+	var readyToUse *bool
+	var snapshotError *crdv1.VolumeSnapshotError
+	if kind == kindVolumeGroupSnapshot {
+		groupSnapshot, err := o.SnapshotClient.GroupsnapshotV1beta1().VolumeGroupSnapshots(o.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, "", fmt.Errorf("failed to get volume group snapshot %s: %w", name, err)
+		}
+		if groupSnapshot.Status != nil {
+			readyToUse, snapshotError = groupSnapshot.Status.ReadyToUse, groupSnapshot.Status.Error
+		}
+	} else {
+		snapshot, err := o.SnapshotClient.SnapshotV1().VolumeSnapshots(o.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, "", fmt.Errorf("failed to get volume snapshot %s: %w", name, err)
+		}
+		if snapshot.Status != nil {
+			readyToUse, snapshotError = snapshot.Status.ReadyToUse, snapshot.Status.Error
+		}
+	}
+	var message string
+	if snapshotError != nil && snapshotError.Message != nil {
+		message = *snapshotError.Message
+	}
+	return readyToUse != nil && *readyToUse, message, nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubectlsnapshot
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	core "k8s.io/client-go/testing"
+
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	"github.com/kubernetes-csi/external-snapshotter/client/v8/clientset/versioned/fake"
+)
+
+func TestWait(t *testing.T) {
+	message := "failed to take snapshot: timeout"
+	snapshot := newSnapshot("snap-a", "claim-a", "content-a", &False, "")
+	snapshot.Status.Error = &crdv1.VolumeSnapshotError{Message: &message}
+	client := fake.NewSimpleClientset(snapshot, newGroupSnapshot("group", "groupcontent", &True))
+
+	// The snapshot becomes ready on the third check.
+	checks := 0
+	client.PrependReactor("get", "volumesnapshots", func(action core.Action) (bool, runtime.Object, error) {
+		checks++
+		if checks < 3 {
+			return true, snapshot.DeepCopy(), nil
+		}
+		ready := snapshot.DeepCopy()
+		ready.Status.ReadyToUse, ready.Status.Error = &True, nil
+		return true, ready, nil
+	})
+
+	var out, errOut bytes.Buffer
+	o := &Options{
+		KubeClient:     kubefake.NewSimpleClientset(),
+		SnapshotClient: client,
+		Namespace:      testNamespace,
+		Out:            &out,
+		ErrOut:         &errOut,
+	}
+	cmd := NewCommand(o)
+	cmd.SetArgs([]string{"wait", "snap-a", "vgs/group", "--interval", "1ms"})
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := "volumesnapshot/snap-a is ready to use\nvolumegroupsnapshot/group is ready to use\n"; out.String() != expected {
+		t.Errorf("expected %q, got %q", expected, out.String())
+	}
+	// The error is printed once, although it was seen twice.
+	if expected := "volumesnapshot/snap-a: " + message + "\n"; errOut.String() != expected {
+		t.Errorf("expected %q, got %q", expected, errOut.String())
+	}
+}
+
+func TestWaitFailures(t *testing.T) {
+	objects := []runtime.Object{newSnapshot("snap-a", "claim-a", "content-a", &False, "")}
+	tests := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{
+			name:          "timeout",
+			args:          []string{"wait", "snap-a", "--interval", "1ms", "--timeout", "10ms"},
+			expectedError: "timed out waiting for volumesnapshot snap-a",
+		},
+		{
+			name:          "missing snapshot",
+			args:          []string{"wait", "missing"},
+			expectedError: "failed to get volume snapshot missing",
+		},
+		{
+			name:          "content",
+			args:          []string{"wait", "vsc/content-a"},
+			expectedError: "only volume snapshots and volume group snapshots can be waited for",
+		},
+	}
+	for _, test := range tests {
+		_, _, err := runCommand(t, nil, objects, test.args...)
+		if err == nil || !strings.Contains(err.Error(), test.expectedError) {
+			t.Errorf("%s: expected error containing %q, got %v", test.name, test.expectedError, err)
+		}
+	}
+}
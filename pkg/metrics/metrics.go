@@ -113,6 +113,39 @@ type MetricsManager interface {
 	// VolumeGroupSnapshot
 	RecordVolumeGroupSnapshotMetrics(op OperationKey, status OperationStatus, driverName string)
 
+	// SetSnapshotExpiry records the time at which a VolumeSnapshot expires.
+	// It is reported by the snapshots_expiring gauge until DropSnapshotExpiry
+	// is called for the snapshot.
+	SetSnapshotExpiry(snapshotUID types.UID, expiry time.Time)
+
+	// DropSnapshotExpiry forgets the expiry time of a VolumeSnapshot.
+	// if the snapshot has no expiry time, it's an no-op.
+	DropSnapshotExpiry(snapshotUID types.UID)
+
+	// RegisterInventory registers gauges of the number of VolumeSnapshots,
+	// VolumeSnapshotContents and VolumeGroupSnapshots, of the restore size
+	// and of the age of the oldest VolumeSnapshot, by namespace, class,
+	// driver and ready state. They are computed from the listers when the
+	// metrics are scraped. Each metric has at most maxSeries series, the
+	// objects beyond are aggregated into a series whose labels are all
+	// "_other". 0 does not limit the series.
+	RegisterInventory(listers InventoryListers, maxSeries int)
+
+	// InFlightOperations returns the operations that have been started with
+	// OperationStart and not recorded or dropped yet.
+	InFlightOperations() []InFlightOperation
+
+	// RecordStuckOperations records the age of the operations in flight in
+	// the operation_age_seconds histogram, and the number of stuck operations
+	// by reason in the stuck_operations gauge. Both replace the values
+	// recorded by the previous call.
+	RecordStuckOperations(now time.Time, inFlight []InFlightOperation, stuck []StuckOperation)
+
+	// RecordAbandonedSnapshot increments the abandoned_snapshot_handles_total
+	// counter of the driver, for a VolumeSnapshotContent whose finalizer has
+	// been removed without deleting the snapshot on the storage system.
+	RecordAbandonedSnapshot(driverName string)
+
 	// GetRegistry() returns the metrics.KubeRegistry used by this metrics manager.
 	GetRegistry() k8smetrics.KubeRegistry
 }
@@ -121,9 +154,9 @@ type MetricsManager interface {
 // uniquely identify a snapshot related operation
 type OperationKey struct {
 	// Name is the name of the operation, for example: "CreateSnapshot", "DeleteSnapshot"
-	Name string
+	Name string `json:"name"`
 	// ResourceID is the resource UID to which the operation has been executed against
-	ResourceID types.UID
+	ResourceID types.UID `json:"resourceID"`
 }
 
 // OperationValue is a structure which holds operation metadata
@@ -175,12 +208,32 @@ type operationMetricsManager struct {
 
 	// opInFlight is a Gauge metric for the number of operations in flight
 	opInFlight *k8smetrics.Gauge
+
+	// expiries stores the expiry time of every VolumeSnapshot that has one,
+	// keyed by the snapshot UID.
+	expiries map[types.UID]time.Time
+
+	// mutex for protecting expiries from concurrent access
+	expiryMu sync.Mutex
+
+	// opAgeMetrics is a Histogram metric for the age of the operations in flight
+	opAgeMetrics *k8smetrics.HistogramVec
+
+	// stuckOperations is a Gauge metric for the number of stuck operations
+	stuckOperations *k8smetrics.GaugeVec
+
+	// mutex for replacing opAgeMetrics and stuckOperations atomically
+	stuckMu sync.Mutex
+
+	// abandonedSnapshots is a Counter metric for the number of abandoned snapshot handles
+	abandonedSnapshots *k8smetrics.CounterVec
 }
 
 // NewMetricsManager creates a new MetricsManager instance
 func NewMetricsManager() MetricsManager {
 	mgr := &operationMetricsManager{
-		cache: make(map[OperationKey]OperationValue),
+		cache:    make(map[OperationKey]OperationValue),
+		expiries: make(map[types.UID]time.Time),
 	}
 	mgr.init()
 	return mgr
@@ -287,6 +340,35 @@ func (opMgr *operationMetricsManager) init() {
 		},
 	)
 	opMgr.registry.MustRegister(opMgr.opInFlight)
+	opMgr.registry.CustomMustRegister(newExpiringSnapshotsCollector(opMgr))
+	opMgr.opAgeMetrics = k8smetrics.NewHistogramVec(
+		&k8smetrics.HistogramOpts{
+			Subsystem: subSystem,
+			Name:      operationAgeName,
+			Help:      operationAgeHelpMsg,
+			Buckets:   operationAgeBuckets,
+		},
+		[]string{labelDriverName, labelOperationName},
+	)
+	opMgr.registry.MustRegister(opMgr.opAgeMetrics)
+	opMgr.stuckOperations = k8smetrics.NewGaugeVec(
+		&k8smetrics.GaugeOpts{
+			Subsystem: subSystem,
+			Name:      stuckOperationsName,
+			Help:      stuckOperationsHelpMsg,
+		},
+		[]string{labelDriverName, labelOperationName, labelReason},
+	)
+	opMgr.registry.MustRegister(opMgr.stuckOperations)
+	opMgr.abandonedSnapshots = k8smetrics.NewCounterVec(
+		&k8smetrics.CounterOpts{
+			Subsystem: subSystem,
+			Name:      abandonedSnapshotsName,
+			Help:      abandonedSnapshotsHelpMsg,
+		},
+		[]string{labelDriverName},
+	)
+	opMgr.registry.MustRegister(opMgr.abandonedSnapshots)
 
 	// While we always maintain the number of operations in flight
 	// for every metrics operation start/finish, if any are leaked,
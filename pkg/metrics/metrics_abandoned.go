@@ -0,0 +1,30 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+const (
+	abandonedSnapshotsName    = "abandoned_snapshot_handles_total"
+	abandonedSnapshotsHelpMsg = "Number of VolumeSnapshotContents whose finalizer was removed by the abandon annotation without deleting the snapshot on the storage system"
+)
+
+// RecordAbandonedSnapshot counts a snapshot handle that has been abandoned.
+func (opMgr *operationMetricsManager) RecordAbandonedSnapshot(driverName string) {
+	if driverName == "" {
+		driverName = unknownDriverName
+	}
+	opMgr.abandonedSnapshots.WithLabelValues(driverName).Inc()
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	k8smetrics "k8s.io/component-base/metrics"
+)
+
+const (
+	labelWithin              = "within"
+	snapshotsExpiringName    = "snapshots_expiring"
+	snapshotsExpiringHelpMsg = "Number of VolumeSnapshots with an expiry annotation that expire within the given duration"
+)
+
+// expiringWindows are the durations for which snapshotsExpiringName reports
+// the number of snapshots that are about to expire, keyed by label value.
+var expiringWindows = []struct {
+	label    string
+	duration time.Duration
+}{
+	{"1h", time.Hour},
+	{"24h", 24 * time.Hour},
+	{"168h", 7 * 24 * time.Hour},
+}
+
+// SetSnapshotExpiry records the time at which a snapshot expires.
+func (opMgr *operationMetricsManager) SetSnapshotExpiry(snapshotUID types.UID, expiry time.Time) {
+	opMgr.expiryMu.Lock()
+	defer opMgr.expiryMu.Unlock()
+	opMgr.expiries[snapshotUID] = expiry
+}
+
+// DropSnapshotExpiry forgets the expiry time of a snapshot.
+func (opMgr *operationMetricsManager) DropSnapshotExpiry(snapshotUID types.UID) {
+	opMgr.expiryMu.Lock()
+	defer opMgr.expiryMu.Unlock()
+	delete(opMgr.expiries, snapshotUID)
+}
+
+// expiringSnapshotsCollector computes the number of snapshots that are about
+// to expire when the metrics are scraped, so that the gauge follows the
+// passing time without being updated by the controller.
+type expiringSnapshotsCollector struct {
+	k8smetrics.BaseStableCollector
+
+	opMgr *operationMetricsManager
+	desc  *k8smetrics.Desc
+}
+
+func newExpiringSnapshotsCollector(opMgr *operationMetricsManager) *expiringSnapshotsCollector {
+	return &expiringSnapshotsCollector{
+		opMgr: opMgr,
+		desc: k8smetrics.NewDesc(
+			subSystem+"_"+snapshotsExpiringName,
+			snapshotsExpiringHelpMsg,
+			[]string{labelWithin},
+			nil,
+			k8smetrics.ALPHA,
+			"",
+		),
+	}
+}
+
+func (c *expiringSnapshotsCollector) DescribeWithStability(ch chan<- *k8smetrics.Desc) {
+	ch <- c.desc
+}
+
+func (c *expiringSnapshotsCollector) CollectWithStability(ch chan<- k8smetrics.Metric) {
+	now := time.Now()
+	counts := make([]int, len(expiringWindows))
+
+	c.opMgr.expiryMu.Lock()
+	for _, expiry := range c.opMgr.expiries {
+		for i, window := range expiringWindows {
+			if expiry.Sub(now) <= window.duration {
+				counts[i]++
+			}
+		}
+	}
+	c.opMgr.expiryMu.Unlock()
+
+	for i, window := range expiringWindows {
+		ch <- k8smetrics.NewLazyConstMetric(c.desc, k8smetrics.GaugeValue, float64(counts[i]), window.label)
+	}
+}
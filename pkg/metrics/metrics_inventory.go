@@ -0,0 +1,319 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+	k8smetrics "k8s.io/component-base/metrics"
+
+	groupsnapshotlisters "github.com/kubernetes-csi/external-snapshotter/client/v8/listers/volumegroupsnapshot/v1beta1"
+	snapshotlisters "github.com/kubernetes-csi/external-snapshotter/client/v8/listers/volumesnapshot/v1"
+)
+
+const (
+	labelNamespace     = "namespace"
+	labelSnapshotClass = "snapshot_class"
+	labelReady         = "ready"
+
+	// inventoryOverflowLabelValue is the value of all the labels of the
+	// series that aggregates the objects beyond the cardinality cap.
+	inventoryOverflowLabelValue = "_other"
+
+	volumeSnapshotsName               = "volumesnapshots"
+	volumeSnapshotsHelpMsg            = "Number of VolumeSnapshots"
+	volumeSnapshotsRestoreSizeName    = "volumesnapshots_restore_size_bytes"
+	volumeSnapshotsRestoreSizeHelpMsg = "Sum of the restore size of the VolumeSnapshots"
+	volumeSnapshotsOldestAgeName      = "volumesnapshots_oldest_age_seconds"
+	volumeSnapshotsOldestAgeHelpMsg   = "Age of the oldest VolumeSnapshot, from the time the snapshot was cut or, if it was not cut yet, the time the VolumeSnapshot was created"
+	volumeSnapshotContentsName        = "volumesnapshotcontents"
+	volumeSnapshotContentsHelpMsg     = "Number of VolumeSnapshotContents"
+	volumeGroupSnapshotsName          = "volumegroupsnapshots"
+	volumeGroupSnapshotsHelpMsg       = "Number of VolumeGroupSnapshots"
+)
+
+// InventoryListers are the informer caches the inventory gauges are computed
+// from.
+type InventoryListers struct {
+	Snapshots snapshotlisters.VolumeSnapshotLister
+	Contents  snapshotlisters.VolumeSnapshotContentLister
+	Classes   snapshotlisters.VolumeSnapshotClassLister
+
+	// GroupSnapshots, GroupSnapshotContents and GroupSnapshotClasses are nil
+	// when volume group snapshots are disabled.
+	GroupSnapshots        groupsnapshotlisters.VolumeGroupSnapshotLister
+	GroupSnapshotContents groupsnapshotlisters.VolumeGroupSnapshotContentLister
+	GroupSnapshotClasses  groupsnapshotlisters.VolumeGroupSnapshotClassLister
+}
+
+// RegisterInventory registers the inventory gauges, computed from the
+// listers when the metrics are scraped.
+func (opMgr *operationMetricsManager) RegisterInventory(listers InventoryListers, maxSeries int) {
+	opMgr.registry.CustomMustRegister(newInventoryCollector(listers, maxSeries))
+}
+
+// inventoryKey is the set of labels of an inventory series.
+type inventoryKey struct {
+	namespace string
+	class     string
+	driver    string
+	ready     string
+}
+
+func (k inventoryKey) values() []string {
+	return []string{k.namespace, k.class, k.driver, k.ready}
+}
+
+// inventoryValue aggregates the objects of an inventory series.
+type inventoryValue struct {
+	count       int
+	restoreSize int64
+	oldest      time.Time
+}
+
+func (v *inventoryValue) add(other inventoryValue) {
+	v.count += other.count
+	v.restoreSize += other.restoreSize
+	if v.oldest.IsZero() || (!other.oldest.IsZero() && other.oldest.Before(v.oldest)) {
+		v.oldest = other.oldest
+	}
+}
+
+// inventory maps the labels of the series to their aggregated objects.
+type inventory map[inventoryKey]*inventoryValue
+
+func (i inventory) add(key inventoryKey, value inventoryValue) {
+	if existing, ok := i[key]; ok {
+		existing.add(value)
+		return
+	}
+	i[key] = &value
+}
+
+// capped returns the inventory with at most maxSeries series. The series
+// with the most objects are kept, and the other ones are aggregated into a
+// single series whose labels are all inventoryOverflowLabelValue.
+func (i inventory) capped(maxSeries int) inventory {
+	if maxSeries <= 0 || len(i) <= maxSeries {
+		return i
+	}
+	keys := make([]inventoryKey, 0, len(i))
+	for key := range i {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(a, b int) bool {
+		if i[keys[a]].count != i[keys[b]].count {
+			return i[keys[a]].count > i[keys[b]].count
+		}
+		return strings.Join(keys[a].values(), "/") < strings.Join(keys[b].values(), "/")
+	})
+	capped := inventory{}
+	for _, key := range keys[:maxSeries-1] {
+		capped[key] = i[key]
+	}
+	overflow := inventoryKey{inventoryOverflowLabelValue, inventoryOverflowLabelValue, inventoryOverflowLabelValue, inventoryOverflowLabelValue}
+	for _, key := range keys[maxSeries-1:] {
+		capped.add(overflow, *i[key])
+	}
+	return capped
+}
+
+// inventoryCollector computes the number of snapshot objects from the
+// informer caches when the metrics are scraped.
+type inventoryCollector struct {
+	k8smetrics.BaseStableCollector
+
+	listers   InventoryListers
+	maxSeries int
+	now       func() time.Time
+
+	snapshotsDesc            *k8smetrics.Desc
+	snapshotsRestoreSizeDesc *k8smetrics.Desc
+	snapshotsOldestAgeDesc   *k8smetrics.Desc
+	contentsDesc             *k8smetrics.Desc
+	groupSnapshotsDesc       *k8smetrics.Desc
+}
+
+func newInventoryDesc(name, help string) *k8smetrics.Desc {
+	return k8smetrics.NewDesc(
+		subSystem+"_"+name,
+		help,
+		[]string{labelNamespace, labelSnapshotClass, labelDriverName, labelReady},
+		nil,
+		k8smetrics.ALPHA,
+		"",
+	)
+}
+
+func newInventoryCollector(listers InventoryListers, maxSeries int) *inventoryCollector {
+	return &inventoryCollector{
+		listers:                  listers,
+		maxSeries:                maxSeries,
+		now:                      time.Now,
+		snapshotsDesc:            newInventoryDesc(volumeSnapshotsName, volumeSnapshotsHelpMsg),
+		snapshotsRestoreSizeDesc: newInventoryDesc(volumeSnapshotsRestoreSizeName, volumeSnapshotsRestoreSizeHelpMsg),
+		snapshotsOldestAgeDesc:   newInventoryDesc(volumeSnapshotsOldestAgeName, volumeSnapshotsOldestAgeHelpMsg),
+		contentsDesc:             newInventoryDesc(volumeSnapshotContentsName, volumeSnapshotContentsHelpMsg),
+		groupSnapshotsDesc:       newInventoryDesc(volumeGroupSnapshotsName, volumeGroupSnapshotsHelpMsg),
+	}
+}
+
+func (c *inventoryCollector) DescribeWithStability(ch chan<- *k8smetrics.Desc) {
+	ch <- c.snapshotsDesc
+	ch <- c.snapshotsRestoreSizeDesc
+	ch <- c.snapshotsOldestAgeDesc
+	ch <- c.contentsDesc
+	ch <- c.groupSnapshotsDesc
+}
+
+func (c *inventoryCollector) CollectWithStability(ch chan<- k8smetrics.Metric) {
+	now := c.now()
+	for key, value := range c.snapshots().capped(c.maxSeries) {
+		ch <- k8smetrics.NewLazyConstMetric(c.snapshotsDesc, k8smetrics.GaugeValue, float64(value.count), key.values()...)
+		ch <- k8smetrics.NewLazyConstMetric(c.snapshotsRestoreSizeDesc, k8smetrics.GaugeValue, float64(value.restoreSize), key.values()...)
+		ch <- k8smetrics.NewLazyConstMetric(c.snapshotsOldestAgeDesc, k8smetrics.GaugeValue, now.Sub(value.oldest).Seconds(), key.values()...)
+	}
+	for key, value := range c.contents().capped(c.maxSeries) {
+		ch <- k8smetrics.NewLazyConstMetric(c.contentsDesc, k8smetrics.GaugeValue, float64(value.count), key.values()...)
+	}
+	for key, value := range c.groupSnapshots().capped(c.maxSeries) {
+		ch <- k8smetrics.NewLazyConstMetric(c.groupSnapshotsDesc, k8smetrics.GaugeValue, float64(value.count), key.values()...)
+	}
+}
+
+func readyLabel(ready *bool) string {
+	return strconv.FormatBool(ready != nil && *ready)
+}
+
+// snapshotDriver returns the driver of the content bound to a snapshot or,
+// if it is not bound yet, of its class.
+func (c *inventoryCollector) snapshotDriver(contentName, className *string) string {
+	if contentName != nil && c.listers.Contents != nil {
+		if content, err := c.listers.Contents.Get(*contentName); err == nil {
+			return content.Spec.Driver
+		}
+	}
+	if className != nil && c.listers.Classes != nil {
+		if class, err := c.listers.Classes.Get(*className); err == nil {
+			return class.Driver
+		}
+	}
+	return unknownDriverName
+}
+
+// groupSnapshotDriver returns the driver of the content bound to a group
+// snapshot or, if it is not bound yet, of its class.
+func (c *inventoryCollector) groupSnapshotDriver(contentName, className *string) string {
+	if contentName != nil && c.listers.GroupSnapshotContents != nil {
+		if content, err := c.listers.GroupSnapshotContents.Get(*contentName); err == nil {
+			return content.Spec.Driver
+		}
+	}
+	if className != nil && c.listers.GroupSnapshotClasses != nil {
+		if class, err := c.listers.GroupSnapshotClasses.Get(*className); err == nil {
+			return class.Driver
+		}
+	}
+	return unknownDriverName
+}
+
+func (c *inventoryCollector) snapshots() inventory {
+	snapshots := inventory{}
+	if c.listers.Snapshots == nil {
+		return snapshots
+	}
+	list, err := c.listers.Snapshots.List(labels.Everything())
+	if err != nil {
+		return snapshots
+	}
+	for _, snapshot := range list {
+		key := inventoryKey{namespace: snapshot.Namespace, ready: readyLabel(nil)}
+		if snapshot.Spec.VolumeSnapshotClassName != nil {
+			key.class = *snapshot.Spec.VolumeSnapshotClassName
+		}
+		value := inventoryValue{count: 1, oldest: snapshot.CreationTimestamp.Time}
+		var contentName *string
+		if snapshot.Status != nil {
+			contentName = snapshot.Status.BoundVolumeSnapshotContentName
+			key.ready = readyLabel(snapshot.Status.ReadyToUse)
+			if snapshot.Status.RestoreSize != nil {
+				value.restoreSize = snapshot.Status.RestoreSize.Value()
+			}
+			if snapshot.Status.CreationTime != nil {
+				value.oldest = snapshot.Status.CreationTime.Time
+			}
+		}
+		key.driver = c.snapshotDriver(contentName, snapshot.Spec.VolumeSnapshotClassName)
+		snapshots.add(key, value)
+	}
+	return snapshots
+}
+
+func (c *inventoryCollector) contents() inventory {
+	contents := inventory{}
+	if c.listers.Contents == nil {
+		return contents
+	}
+	list, err := c.listers.Contents.List(labels.Everything())
+	if err != nil {
+		return contents
+	}
+	for _, content := range list {
+		key := inventoryKey{
+			namespace: content.Spec.VolumeSnapshotRef.Namespace,
+			driver:    content.Spec.Driver,
+			ready:     readyLabel(nil),
+		}
+		if content.Spec.VolumeSnapshotClassName != nil {
+			key.class = *content.Spec.VolumeSnapshotClassName
+		}
+		if content.Status != nil {
+			key.ready = readyLabel(content.Status.ReadyToUse)
+		}
+		contents.add(key, inventoryValue{count: 1})
+	}
+	return contents
+}
+
+func (c *inventoryCollector) groupSnapshots() inventory {
+	groupSnapshots := inventory{}
+	if c.listers.GroupSnapshots == nil {
+		return groupSnapshots
+	}
+	list, err := c.listers.GroupSnapshots.List(labels.Everything())
+	if err != nil {
+		return groupSnapshots
+	}
+	for _, groupSnapshot := range list {
+		key := inventoryKey{namespace: groupSnapshot.Namespace, ready: readyLabel(nil)}
+		if groupSnapshot.Spec.VolumeGroupSnapshotClassName != nil {
+			key.class = *groupSnapshot.Spec.VolumeGroupSnapshotClassName
+		}
+		var contentName *string
+		if groupSnapshot.Status != nil {
+			contentName = groupSnapshot.Status.BoundVolumeGroupSnapshotContentName
+			key.ready = readyLabel(groupSnapshot.Status.ReadyToUse)
+		}
+		key.driver = c.groupSnapshotDriver(contentName, groupSnapshot.Spec.VolumeGroupSnapshotClassName)
+		groupSnapshots.add(key, inventoryValue{count: 1})
+	}
+	return groupSnapshots
+}
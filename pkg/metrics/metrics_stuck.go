@@ -0,0 +1,84 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"time"
+)
+
+const (
+	labelReason            = "reason"
+	stuckOperationsName    = "stuck_operations"
+	stuckOperationsHelpMsg = "Number of operations in flight for longer than their threshold, by the reason they are blocked"
+	operationAgeName       = "operation_age_seconds"
+	operationAgeHelpMsg    = "Age of the operations in flight when they were last checked by the stuck operation watchdog"
+)
+
+var operationAgeBuckets = []float64{60, 300, 600, 1800, 3600, 7200, 21600, 86400}
+
+// InFlightOperation is an operation that has been started and not recorded
+// or dropped yet.
+type InFlightOperation struct {
+	Key OperationKey `json:"key"`
+	// Driver is the driver name which executes the operation
+	Driver string `json:"driver"`
+	// SnapshotType represents the snapshot type, for example: "dynamic", "pre-provisioned"
+	SnapshotType string `json:"snapshotType"`
+	// StartTime is the time when the operation first started
+	StartTime time.Time `json:"startTime"`
+}
+
+// StuckOperation is an operation in flight for longer than its threshold.
+type StuckOperation struct {
+	InFlightOperation
+	// Reason is the reason why the operation is blocked, for example:
+	// "WaitingForContent", "SecretNotFound"
+	Reason string
+}
+
+// InFlightOperations returns the operations in flight.
+func (opMgr *operationMetricsManager) InFlightOperations() []InFlightOperation {
+	opMgr.mu.Lock()
+	defer opMgr.mu.Unlock()
+	ops := make([]InFlightOperation, 0, len(opMgr.cache))
+	for key, val := range opMgr.cache {
+		ops = append(ops, InFlightOperation{
+			Key:          key,
+			Driver:       val.Driver,
+			SnapshotType: val.SnapshotType,
+			StartTime:    val.startTime,
+		})
+	}
+	return ops
+}
+
+// RecordStuckOperations replaces the operation ages and the stuck operations
+// reported by the previous call.
+func (opMgr *operationMetricsManager) RecordStuckOperations(now time.Time, inFlight []InFlightOperation, stuck []StuckOperation) {
+	opMgr.stuckMu.Lock()
+	defer opMgr.stuckMu.Unlock()
+
+	opMgr.opAgeMetrics.Reset()
+	for _, op := range inFlight {
+		opMgr.opAgeMetrics.WithLabelValues(op.Driver, op.Key.Name).Observe(now.Sub(op.StartTime).Seconds())
+	}
+
+	opMgr.stuckOperations.Reset()
+	for _, op := range stuck {
+		opMgr.stuckOperations.WithLabelValues(op.Driver, op.Key.Name, op.Reason).Inc()
+	}
+}
@@ -32,7 +32,18 @@ import (
 
 	cmg "github.com/prometheus/client_model/go"
 	"github.com/prometheus/common/expfmt"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	k8smetrics "k8s.io/component-base/metrics"
+	"k8s.io/utils/ptr"
+
+	crdv1beta1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumegroupsnapshot/v1beta1"
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	groupsnapshotlisters "github.com/kubernetes-csi/external-snapshotter/client/v8/listers/volumegroupsnapshot/v1beta1"
+	snapshotlisters "github.com/kubernetes-csi/external-snapshotter/client/v8/listers/volumesnapshot/v1"
 )
 
 var (
@@ -878,3 +889,290 @@ snapshot_controller_operation_total_seconds_count{driver_name="driver2",operatio
 		t.Errorf("failed testing [%v]", err)
 	}
 }
+
+func TestSnapshotsExpiringMetric(t *testing.T) {
+	mgr, srv := initMgr()
+	defer shutdown(srv)
+
+	now := time.Now()
+	mgr.SetSnapshotExpiry(types.UID("uid1"), now.Add(-time.Minute))
+	mgr.SetSnapshotExpiry(types.UID("uid2"), now.Add(30*time.Minute))
+	mgr.SetSnapshotExpiry(types.UID("uid3"), now.Add(12*time.Hour))
+	mgr.SetSnapshotExpiry(types.UID("uid4"), now.Add(30*24*time.Hour))
+	mgr.SetSnapshotExpiry(types.UID("uid5"), now.Add(3*24*time.Hour))
+	mgr.DropSnapshotExpiry(types.UID("uid5"))
+	// dropping an unknown snapshot is a no-op
+	mgr.DropSnapshotExpiry(types.UID("unknown"))
+
+	metricsFamilies, err := mgr.GetRegistry().Gather()
+	if err != nil {
+		t.Fatalf("Error fetching metrics: %v", err)
+	}
+	expected := map[string]float64{"1h": 2, "24h": 3, "168h": 3}
+	for _, metricsFamily := range metricsFamilies {
+		if metricsFamily.GetName() != "snapshot_controller_snapshots_expiring" {
+			continue
+		}
+		got := map[string]float64{}
+		for _, m := range metricsFamily.GetMetric() {
+			for _, label := range m.GetLabel() {
+				if label.GetName() == labelWithin {
+					got[label.GetValue()] = m.GetGauge().GetValue()
+				}
+			}
+		}
+		if !reflect.DeepEqual(got, expected) {
+			t.Fatalf("expected %v, got %v", expected, got)
+		}
+		return
+	}
+	t.Fatalf("Metrics does not contain snapshot_controller_snapshots_expiring. Scraped content: %v", metricsFamilies)
+}
+
+func TestInventoryMetrics(t *testing.T) {
+	now := time.Now()
+	gold := "gold"
+	silver := "silver"
+	snapshotIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	contentIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	classIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	groupSnapshotIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	classIndexer.Add(&crdv1.VolumeSnapshotClass{ObjectMeta: metav1.ObjectMeta{Name: gold}, Driver: "driver-a"})
+	contentIndexer.Add(&crdv1.VolumeSnapshotContent{
+		ObjectMeta: metav1.ObjectMeta{Name: "content-1"},
+		Spec: crdv1.VolumeSnapshotContentSpec{
+			Driver:                  "driver-b",
+			VolumeSnapshotClassName: &silver,
+			VolumeSnapshotRef:       v1.ObjectReference{Namespace: "ns1", Name: "snap-1"},
+		},
+		Status: &crdv1.VolumeSnapshotContentStatus{ReadyToUse: ptr.To(true)},
+	})
+	snapshotIndexer.Add(&crdv1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "snap-1", CreationTimestamp: metav1.NewTime(now.Add(-time.Minute))},
+		Spec:       crdv1.VolumeSnapshotSpec{VolumeSnapshotClassName: &silver},
+		Status: &crdv1.VolumeSnapshotStatus{
+			BoundVolumeSnapshotContentName: ptr.To("content-1"),
+			CreationTime:                   &metav1.Time{Time: now.Add(-time.Hour)},
+			ReadyToUse:                     ptr.To(true),
+			RestoreSize:                    resource.NewQuantity(100, resource.BinarySI),
+		},
+	})
+	snapshotIndexer.Add(&crdv1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "snap-2", CreationTimestamp: metav1.NewTime(now.Add(-2 * time.Hour))},
+		Spec:       crdv1.VolumeSnapshotSpec{VolumeSnapshotClassName: &gold},
+		Status: &crdv1.VolumeSnapshotStatus{
+			CreationTime: &metav1.Time{Time: now.Add(-3 * time.Hour)},
+			ReadyToUse:   ptr.To(true),
+			RestoreSize:  resource.NewQuantity(200, resource.BinarySI),
+		},
+	})
+	snapshotIndexer.Add(&crdv1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "snap-3", CreationTimestamp: metav1.NewTime(now.Add(-30 * time.Minute))},
+		Spec:       crdv1.VolumeSnapshotSpec{VolumeSnapshotClassName: &gold},
+		Status: &crdv1.VolumeSnapshotStatus{
+			CreationTime: &metav1.Time{Time: now.Add(-time.Hour)},
+			ReadyToUse:   ptr.To(true),
+			RestoreSize:  resource.NewQuantity(300, resource.BinarySI),
+		},
+	})
+	snapshotIndexer.Add(&crdv1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns2", Name: "snap-4", CreationTimestamp: metav1.NewTime(now.Add(-10 * time.Minute))},
+		Spec:       crdv1.VolumeSnapshotSpec{VolumeSnapshotClassName: &gold},
+	})
+	groupSnapshotIndexer.Add(&crdv1beta1.VolumeGroupSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns2", Name: "group-1"},
+	})
+	listers := InventoryListers{
+		Snapshots:      snapshotlisters.NewVolumeSnapshotLister(snapshotIndexer),
+		Contents:       snapshotlisters.NewVolumeSnapshotContentLister(contentIndexer),
+		Classes:        snapshotlisters.NewVolumeSnapshotClassLister(classIndexer),
+		GroupSnapshots: groupsnapshotlisters.NewVolumeGroupSnapshotLister(groupSnapshotIndexer),
+	}
+
+	tests := []struct {
+		name      string
+		maxSeries int
+		expected  map[string]map[string]float64
+	}{
+		{
+			name:      "unlimited series",
+			maxSeries: 0,
+			expected: map[string]map[string]float64{
+				"snapshot_controller_volumesnapshots": {
+					"ns1/silver/driver-b/true": 1,
+					"ns1/gold/driver-a/true":   2,
+					"ns2/gold/driver-a/false":  1,
+				},
+				"snapshot_controller_volumesnapshots_restore_size_bytes": {
+					"ns1/silver/driver-b/true": 100,
+					"ns1/gold/driver-a/true":   500,
+					"ns2/gold/driver-a/false":  0,
+				},
+				"snapshot_controller_volumesnapshots_oldest_age_seconds": {
+					"ns1/silver/driver-b/true": 3600,
+					"ns1/gold/driver-a/true":   3 * 3600,
+					"ns2/gold/driver-a/false":  600,
+				},
+				"snapshot_controller_volumesnapshotcontents": {
+					"ns1/silver/driver-b/true": 1,
+				},
+				"snapshot_controller_volumegroupsnapshots": {
+					"ns2//unknown/false": 1,
+				},
+			},
+		},
+		{
+			name:      "capped series",
+			maxSeries: 2,
+			expected: map[string]map[string]float64{
+				"snapshot_controller_volumesnapshots": {
+					"ns1/gold/driver-a/true":      2,
+					"_other/_other/_other/_other": 2,
+				},
+				"snapshot_controller_volumesnapshots_restore_size_bytes": {
+					"ns1/gold/driver-a/true":      500,
+					"_other/_other/_other/_other": 100,
+				},
+				"snapshot_controller_volumesnapshots_oldest_age_seconds": {
+					"ns1/gold/driver-a/true":      3 * 3600,
+					"_other/_other/_other/_other": 3600,
+				},
+				"snapshot_controller_volumesnapshotcontents": {
+					"ns1/silver/driver-b/true": 1,
+				},
+				"snapshot_controller_volumegroupsnapshots": {
+					"ns2//unknown/false": 1,
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			collector := newInventoryCollector(listers, test.maxSeries)
+			collector.now = func() time.Time { return now }
+			registry := k8smetrics.NewKubeRegistry()
+			registry.CustomMustRegister(collector)
+
+			metricsFamilies, err := registry.Gather()
+			if err != nil {
+				t.Fatalf("Error fetching metrics: %v", err)
+			}
+			got := map[string]map[string]float64{}
+			for _, metricsFamily := range metricsFamilies {
+				series := map[string]float64{}
+				for _, m := range metricsFamily.GetMetric() {
+					labels := map[string]string{}
+					for _, label := range m.GetLabel() {
+						labels[label.GetName()] = label.GetValue()
+					}
+					key := strings.Join([]string{labels[labelNamespace], labels[labelSnapshotClass], labels[labelDriverName], labels[labelReady]}, "/")
+					series[key] = m.GetGauge().GetValue()
+				}
+				got[metricsFamily.GetName()] = series
+			}
+			if !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestStuckOperationsMetrics(t *testing.T) {
+	mgr, srv := initMgr()
+	defer shutdown(srv)
+
+	createKey := NewOperationKey(CreateSnapshotOperationName, types.UID("uid1"))
+	deleteKey := NewOperationKey(DeleteGroupSnapshotOperationName, types.UID("uid2"))
+	mgr.OperationStart(createKey, NewOperationValue("driver1", DynamicSnapshotType))
+	mgr.OperationStart(deleteKey, NewOperationValue("", DynamicGroupSnapshotType))
+	inFlight := mgr.InFlightOperations()
+	if len(inFlight) != 2 {
+		t.Fatalf("expected 2 operations in flight, got %+v", inFlight)
+	}
+	var createOp InFlightOperation
+	for _, op := range inFlight {
+		if op.StartTime.IsZero() {
+			t.Errorf("expected the start time of %+v to be set", op)
+		}
+		if op.Key == createKey {
+			createOp = op
+		}
+	}
+	if createOp.Driver != "driver1" || createOp.SnapshotType != string(DynamicSnapshotType) {
+		t.Fatalf("unexpected operation %+v", createOp)
+	}
+
+	now := createOp.StartTime.Add(2 * time.Hour)
+	mgr.RecordStuckOperations(now, inFlight, []StuckOperation{{InFlightOperation: createOp, Reason: "SecretNotFound"}})
+	mgr.RecordStuckOperations(now, inFlight, []StuckOperation{{InFlightOperation: createOp, Reason: "WaitingForContent"}})
+
+	metricsFamilies, err := mgr.GetRegistry().Gather()
+	if err != nil {
+		t.Fatalf("Error fetching metrics: %v", err)
+	}
+	stuck := map[string]float64{}
+	ages := map[string]uint64{}
+	for _, metricsFamily := range metricsFamilies {
+		switch metricsFamily.GetName() {
+		case "snapshot_controller_stuck_operations":
+			for _, m := range metricsFamily.GetMetric() {
+				labels := map[string]string{}
+				for _, label := range m.GetLabel() {
+					labels[label.GetName()] = label.GetValue()
+				}
+				stuck[labels[labelDriverName]+"/"+labels[labelOperationName]+"/"+labels[labelReason]] = m.GetGauge().GetValue()
+			}
+		case "snapshot_controller_operation_age_seconds":
+			for _, m := range metricsFamily.GetMetric() {
+				for _, label := range m.GetLabel() {
+					if label.GetName() == labelOperationName {
+						ages[label.GetValue()] = m.GetHistogram().GetSampleCount()
+					}
+				}
+				if m.GetHistogram().GetSampleSum() < 3600 {
+					t.Errorf("expected operations to be more than 1h old, got %v", m.GetHistogram().GetSampleSum())
+				}
+			}
+		}
+	}
+	// the second call replaces the stuck operations of the first one
+	expectedStuck := map[string]float64{"driver1/CreateSnapshot/WaitingForContent": 1}
+	if !reflect.DeepEqual(stuck, expectedStuck) {
+		t.Errorf("expected stuck operations %v, got %v", expectedStuck, stuck)
+	}
+	expectedCounts := map[string]uint64{CreateSnapshotOperationName: 1, DeleteGroupSnapshotOperationName: 1}
+	if !reflect.DeepEqual(ages, expectedCounts) {
+		t.Errorf("expected operation ages %v, got %v", expectedCounts, ages)
+	}
+}
+
+func TestAbandonedSnapshotsMetrics(t *testing.T) {
+	mgr, srv := initMgr()
+	defer shutdown(srv)
+
+	mgr.RecordAbandonedSnapshot("driver1")
+	mgr.RecordAbandonedSnapshot("driver1")
+	mgr.RecordAbandonedSnapshot("")
+
+	metricsFamilies, err := mgr.GetRegistry().Gather()
+	if err != nil {
+		t.Fatalf("Error fetching metrics: %v", err)
+	}
+	abandoned := map[string]float64{}
+	for _, metricsFamily := range metricsFamilies {
+		if metricsFamily.GetName() != "snapshot_controller_abandoned_snapshot_handles_total" {
+			continue
+		}
+		for _, m := range metricsFamily.GetMetric() {
+			for _, label := range m.GetLabel() {
+				if label.GetName() == labelDriverName {
+					abandoned[label.GetValue()] = m.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	expected := map[string]float64{"driver1": 2, unknownDriverName: 1}
+	if !reflect.DeepEqual(abandoned, expected) {
+		t.Errorf("expected abandoned snapshots %v, got %v", expected, abandoned)
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockcsidriver
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (d *Driver) ControllerGetCapabilities(ctx context.Context, req *csi.ControllerGetCapabilitiesRequest) (*csi.ControllerGetCapabilitiesResponse, error) {
+	types := []csi.ControllerServiceCapability_RPC_Type{csi.ControllerServiceCapability_RPC_CREATE_DELETE_SNAPSHOT}
+	if !d.options.DisableListSnapshots {
+		types = append(types, csi.ControllerServiceCapability_RPC_LIST_SNAPSHOTS)
+	}
+	capabilities := make([]*csi.ControllerServiceCapability, 0, len(types))
+	for _, t := range types {
+		capabilities = append(capabilities, &csi.ControllerServiceCapability{
+			Type: &csi.ControllerServiceCapability_Rpc{
+				Rpc: &csi.ControllerServiceCapability_RPC{Type: t},
+			},
+		})
+	}
+	return &csi.ControllerGetCapabilitiesResponse{Capabilities: capabilities}, nil
+}
+
+// CreateSnapshot is idempotent by name, as required by the CSI spec.
+func (d *Driver) CreateSnapshot(ctx context.Context, req *csi.CreateSnapshotRequest) (*csi.CreateSnapshotResponse, error) {
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "snapshot name is missing")
+	}
+	if req.SourceVolumeId == "" {
+		return nil, status.Error(codes.InvalidArgument, "source volume ID is missing")
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if id, ok := d.snapshotNames[req.Name]; ok {
+		snapshot := d.snapshots[id]
+		if snapshot.SourceVolumeID != req.SourceVolumeId {
+			return nil, status.Errorf(codes.AlreadyExists, "snapshot %s already exists for volume %s", req.Name, snapshot.SourceVolumeID)
+		}
+		d.observe(snapshot)
+		return &csi.CreateSnapshotResponse{Snapshot: d.csiSnapshot(snapshot)}, nil
+	}
+
+	snapshot := &Snapshot{
+		ID:             d.newID("snapshot"),
+		Name:           req.Name,
+		SourceVolumeID: req.SourceVolumeId,
+		CreationTime:   time.Now(),
+		Parameters:     req.Parameters,
+		Secrets:        req.Secrets,
+	}
+	d.snapshots[snapshot.ID] = snapshot
+	d.snapshotNames[snapshot.Name] = snapshot.ID
+	d.observe(snapshot)
+	return &csi.CreateSnapshotResponse{Snapshot: d.csiSnapshot(snapshot)}, nil
+}
+
+// DeleteSnapshot succeeds for unknown snapshots, as required by the CSI spec.
+func (d *Driver) DeleteSnapshot(ctx context.Context, req *csi.DeleteSnapshotRequest) (*csi.DeleteSnapshotResponse, error) {
+	if req.SnapshotId == "" {
+		return nil, status.Error(codes.InvalidArgument, "snapshot ID is missing")
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	snapshot, ok := d.snapshots[req.SnapshotId]
+	if !ok {
+		return &csi.DeleteSnapshotResponse{}, nil
+	}
+	if snapshot.GroupSnapshotID != "" {
+		if _, ok := d.groupSnapshots[snapshot.GroupSnapshotID]; ok {
+			return nil, status.Errorf(codes.FailedPrecondition, "snapshot %s is a member of group snapshot %s", snapshot.ID, snapshot.GroupSnapshotID)
+		}
+	}
+	delete(d.snapshots, snapshot.ID)
+	delete(d.snapshotNames, snapshot.Name)
+	return &csi.DeleteSnapshotResponse{}, nil
+}
+
+// ListSnapshots returns the snapshots sorted by ID. The starting token is
+// the index of the first entry of the page.
+func (d *Driver) ListSnapshots(ctx context.Context, req *csi.ListSnapshotsRequest) (*csi.ListSnapshotsResponse, error) {
+	if d.options.DisableListSnapshots {
+		return nil, status.Error(codes.Unimplemented, "ListSnapshots is not supported")
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	var matching []*Snapshot
+	for _, snapshot := range d.snapshots {
+		if req.SnapshotId != "" && snapshot.ID != req.SnapshotId {
+			continue
+		}
+		if req.SourceVolumeId != "" && snapshot.SourceVolumeID != req.SourceVolumeId {
+			continue
+		}
+		matching = append(matching, snapshot)
+	}
+	sort.Slice(matching, func(i, j int) bool { return matching[i].ID < matching[j].ID })
+
+	start := 0
+	if req.StartingToken != "" {
+		var err error
+		start, err = strconv.Atoi(req.StartingToken)
+		if err != nil || start < 0 || start > len(matching) {
+			return nil, status.Errorf(codes.Aborted, "invalid starting token %q", req.StartingToken)
+		}
+	}
+	pageSize := int(req.MaxEntries)
+	if d.options.MaxPageSize > 0 && (pageSize == 0 || pageSize > int(d.options.MaxPageSize)) {
+		pageSize = int(d.options.MaxPageSize)
+	}
+	end := len(matching)
+	if pageSize > 0 && start+pageSize < end {
+		end = start + pageSize
+	}
+
+	rsp := &csi.ListSnapshotsResponse{}
+	for _, snapshot := range matching[start:end] {
+		d.observe(snapshot)
+		rsp.Entries = append(rsp.Entries, &csi.ListSnapshotsResponse_Entry{Snapshot: d.csiSnapshot(snapshot)})
+	}
+	if end < len(matching) {
+		rsp.NextToken = strconv.Itoa(end)
+	}
+	return rsp, nil
+}
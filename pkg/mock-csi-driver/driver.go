@@ -0,0 +1,344 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package mockcsidriver provides an in-memory CSI driver with the Identity,
+// Controller and GroupController services, served over a unix socket, to
+// exercise the snapshot controllers and their gRPC clients end to end. Its
+// behavior can be scripted with latencies, injected errors, ReadyToUse
+// transitions and a maximum ListSnapshots page size.
+package mockcsidriver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// DefaultName is the name of the driver when Options.Name is empty.
+	DefaultName = "mock.csi.k8s.io"
+	// DefaultSnapshotSize is the size of the snapshots when Options.SnapshotSize is zero.
+	DefaultSnapshotSize = int64(1024 * 1024 * 1024)
+)
+
+// Options configures a Driver.
+type Options struct {
+	// Name returned by GetPluginInfo.
+	Name string
+	// DisableListSnapshots removes the LIST_SNAPSHOTS capability.
+	DisableListSnapshots bool
+	// DisableGroupSnapshots removes the GroupController service capabilities.
+	DisableGroupSnapshots bool
+	// MaxPageSize caps the number of ListSnapshots entries per page. 0 means no cap.
+	MaxPageSize int32
+	// ReadyAfter is the number of times a snapshot is observed through
+	// CreateSnapshot, ListSnapshots, CreateVolumeGroupSnapshot or
+	// GetVolumeGroupSnapshot, including its creation, before it becomes ready
+	// to use. 0 and 1 make snapshots ready when they are created.
+	ReadyAfter int
+	// SnapshotSize is the size of every snapshot.
+	SnapshotSize int64
+}
+
+// Snapshot is the state of a snapshot stored by the driver.
+type Snapshot struct {
+	ID              string
+	Name            string
+	SourceVolumeID  string
+	GroupSnapshotID string
+	CreationTime    time.Time
+	ReadyToUse      bool
+	Parameters      map[string]string
+	Secrets         map[string]string
+
+	observations int
+}
+
+// GroupSnapshot is the state of a group snapshot stored by the driver.
+type GroupSnapshot struct {
+	ID           string
+	Name         string
+	SnapshotIDs  []string
+	CreationTime time.Time
+}
+
+// fault is an error injected in the next calls of a method.
+type fault struct {
+	code    codes.Code
+	message string
+	// remaining is the number of calls that fail, or -1 for all of them.
+	remaining int
+}
+
+// Driver is an in-memory CSI driver. All its methods are safe for
+// concurrent use.
+type Driver struct {
+	csi.UnimplementedIdentityServer
+	csi.UnimplementedControllerServer
+	csi.UnimplementedGroupControllerServer
+
+	options Options
+
+	mutex          sync.Mutex
+	snapshots      map[string]*Snapshot
+	snapshotNames  map[string]string
+	groupSnapshots map[string]*GroupSnapshot
+	groupNames     map[string]string
+	nextID         int
+	faults         map[string]*fault
+	latencies      map[string]time.Duration
+	calls          []string
+
+	server   *grpc.Server
+	listener net.Listener
+	dir      string
+}
+
+// New returns a driver that is not serving yet.
+func New(options Options) *Driver {
+	if options.Name == "" {
+		options.Name = DefaultName
+	}
+	if options.SnapshotSize == 0 {
+		options.SnapshotSize = DefaultSnapshotSize
+	}
+	return &Driver{
+		options:        options,
+		snapshots:      map[string]*Snapshot{},
+		snapshotNames:  map[string]string{},
+		groupSnapshots: map[string]*GroupSnapshot{},
+		groupNames:     map[string]string{},
+		faults:         map[string]*fault{},
+		latencies:      map[string]time.Duration{},
+	}
+}
+
+// Start serves the driver on a unix socket in a new temporary directory.
+func (d *Driver) Start() error {
+	dir, err := os.MkdirTemp("", "csi-mock")
+	if err != nil {
+		return err
+	}
+	listener, err := net.Listen("unix", filepath.Join(dir, "csi.sock"))
+	if err != nil {
+		os.RemoveAll(dir)
+		return err
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.dir = dir
+	d.listener = listener
+	d.server = grpc.NewServer(grpc.UnaryInterceptor(d.interceptor))
+	csi.RegisterIdentityServer(d.server, d)
+	csi.RegisterControllerServer(d.server, d)
+	csi.RegisterGroupControllerServer(d.server, d)
+	go d.server.Serve(listener)
+	return nil
+}
+
+// Stop stops serving and removes the socket.
+func (d *Driver) Stop() {
+	d.mutex.Lock()
+	server, dir := d.server, d.dir
+	d.server, d.dir = nil, ""
+	d.mutex.Unlock()
+	if server != nil {
+		server.Stop()
+	}
+	if dir != "" {
+		os.RemoveAll(dir)
+	}
+}
+
+// Address returns the unix:// address the driver is served on.
+func (d *Driver) Address() string {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return "unix://" + d.listener.Addr().String()
+}
+
+// Name returns the name of the driver.
+func (d *Driver) Name() string {
+	return d.options.Name
+}
+
+// InjectError makes the next times calls of method, e.g. "CreateSnapshot",
+// fail with the given code. A negative times makes all the calls fail until
+// ClearErrors is called.
+func (d *Driver) InjectError(method string, code codes.Code, times int) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if times < 0 {
+		times = -1
+	}
+	d.faults[method] = &fault{
+		code:      code,
+		message:   fmt.Sprintf("injected %s error", code),
+		remaining: times,
+	}
+}
+
+// ClearErrors removes all the injected errors.
+func (d *Driver) ClearErrors() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.faults = map[string]*fault{}
+}
+
+// SetLatency delays every call of method by latency.
+func (d *Driver) SetLatency(method string, latency time.Duration) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.latencies[method] = latency
+}
+
+// SetReadyToUse sets the ReadyToUse status of a snapshot.
+func (d *Driver) SetReadyToUse(snapshotID string, ready bool) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	snapshot, ok := d.snapshots[snapshotID]
+	if !ok {
+		return fmt.Errorf("snapshot %s not found", snapshotID)
+	}
+	snapshot.ReadyToUse = ready
+	return nil
+}
+
+// AddSnapshot stores a snapshot as if it was created out of band, e.g. by
+// another cluster. A missing ID is generated and returned.
+func (d *Driver) AddSnapshot(snapshot Snapshot) string {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if snapshot.ID == "" {
+		snapshot.ID = d.newID("snapshot")
+	}
+	if snapshot.CreationTime.IsZero() {
+		snapshot.CreationTime = time.Now()
+	}
+	d.snapshots[snapshot.ID] = &snapshot
+	if snapshot.Name != "" {
+		d.snapshotNames[snapshot.Name] = snapshot.ID
+	}
+	return snapshot.ID
+}
+
+// Snapshots returns a copy of the stored snapshots keyed by ID.
+func (d *Driver) Snapshots() map[string]Snapshot {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	snapshots := make(map[string]Snapshot, len(d.snapshots))
+	for id, snapshot := range d.snapshots {
+		snapshots[id] = *snapshot
+	}
+	return snapshots
+}
+
+// GroupSnapshots returns a copy of the stored group snapshots keyed by ID.
+func (d *Driver) GroupSnapshots() map[string]GroupSnapshot {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	groups := make(map[string]GroupSnapshot, len(d.groupSnapshots))
+	for id, group := range d.groupSnapshots {
+		groups[id] = *group
+	}
+	return groups
+}
+
+// Calls returns the names of the methods called so far, in order.
+func (d *Driver) Calls() []string {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return append([]string(nil), d.calls...)
+}
+
+// CallCount returns the number of calls of method so far.
+func (d *Driver) CallCount(method string) int {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	count := 0
+	for _, call := range d.calls {
+		if call == method {
+			count++
+		}
+	}
+	return count
+}
+
+// interceptor records the calls and applies the scripted latencies and errors.
+func (d *Driver) interceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	method := filepath.Base(info.FullMethod)
+
+	d.mutex.Lock()
+	d.calls = append(d.calls, method)
+	latency := d.latencies[method]
+	var err error
+	if f, ok := d.faults[method]; ok && f.remaining != 0 {
+		err = status.Error(f.code, f.message)
+		if f.remaining > 0 {
+			f.remaining--
+		}
+	}
+	d.mutex.Unlock()
+
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-ctx.Done():
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// newID returns a new unique ID. The caller must hold the mutex.
+func (d *Driver) newID(prefix string) string {
+	d.nextID++
+	return fmt.Sprintf("%s-%d", prefix, d.nextID)
+}
+
+// observe counts an observation of the snapshot and makes it ready once it
+// has been observed options.ReadyAfter times. The caller must hold the mutex.
+func (d *Driver) observe(snapshot *Snapshot) {
+	snapshot.observations++
+	if snapshot.observations >= d.options.ReadyAfter {
+		snapshot.ReadyToUse = true
+	}
+}
+
+func (d *Driver) csiSnapshot(snapshot *Snapshot) *csi.Snapshot {
+	return &csi.Snapshot{
+		SnapshotId:      snapshot.ID,
+		SourceVolumeId:  snapshot.SourceVolumeID,
+		GroupSnapshotId: snapshot.GroupSnapshotID,
+		CreationTime:    timestamppb.New(snapshot.CreationTime),
+		ReadyToUse:      snapshot.ReadyToUse,
+		SizeBytes:       d.options.SnapshotSize,
+	}
+}
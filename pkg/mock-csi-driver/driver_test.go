@@ -0,0 +1,166 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockcsidriver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kubernetes-csi/csi-lib-utils/connection"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/group_snapshotter"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/snapshotter"
+)
+
+func startDriver(t *testing.T, options Options) (*Driver, *grpc.ClientConn) {
+	driver := New(options)
+	if err := driver.Start(); err != nil {
+		t.Fatalf("failed to start driver: %v", err)
+	}
+	t.Cleanup(driver.Stop)
+	conn, err := connection.Connect(context.Background(), driver.Address(), nil)
+	if err != nil {
+		t.Fatalf("failed to connect to driver: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return driver, conn
+}
+
+func TestSnapshots(t *testing.T) {
+	driver, conn := startDriver(t, Options{ReadyAfter: 3})
+	s := snapshotter.NewSnapshotter(conn)
+	ctx := context.Background()
+
+	driverName, id, _, size, ready, err := s.CreateSnapshot(ctx, "snap-1", "vol-1", nil, nil)
+	if err != nil {
+		t.Fatalf("CreateSnapshot failed: %v", err)
+	}
+	if driverName != DefaultName || size != DefaultSnapshotSize || ready {
+		t.Errorf("unexpected CreateSnapshot result: %s %d %v", driverName, size, ready)
+	}
+	_, again, _, _, ready, err := s.CreateSnapshot(ctx, "snap-1", "vol-1", nil, nil)
+	if err != nil || again != id || ready {
+		t.Errorf("expected idempotent CreateSnapshot of %s, not ready, got %s %v %v", id, again, ready, err)
+	}
+	if _, _, _, _, _, err := s.CreateSnapshot(ctx, "snap-1", "vol-2", nil, nil); status.Code(err) != codes.AlreadyExists {
+		t.Errorf("expected AlreadyExists for another volume, got %v", err)
+	}
+	ready, _, _, _, err = s.GetSnapshotStatus(ctx, id, nil)
+	if err != nil || !ready {
+		t.Errorf("expected snapshot to be ready after 3 observations, got %v %v", ready, err)
+	}
+
+	if err := driver.SetReadyToUse(id, false); err != nil {
+		t.Fatal(err)
+	}
+	if snapshots := driver.Snapshots(); snapshots[id].ReadyToUse {
+		t.Errorf("expected SetReadyToUse to update the snapshot")
+	}
+
+	if err := s.DeleteSnapshot(ctx, id, nil); err != nil {
+		t.Errorf("DeleteSnapshot failed: %v", err)
+	}
+	if err := s.DeleteSnapshot(ctx, id, nil); err != nil {
+		t.Errorf("expected idempotent DeleteSnapshot, got %v", err)
+	}
+	if len(driver.Snapshots()) != 0 {
+		t.Errorf("expected no snapshots, got %v", driver.Snapshots())
+	}
+}
+
+func TestListSnapshotsPagination(t *testing.T) {
+	driver, conn := startDriver(t, Options{MaxPageSize: 2})
+	for i := 0; i < 5; i++ {
+		driver.AddSnapshot(Snapshot{SourceVolumeID: "vol-1", ReadyToUse: true})
+	}
+	s := snapshotter.NewSnapshotter(conn)
+
+	snapshots, supported, err := s.GetSnapshotStatuses(context.Background(), 10, nil)
+	if err != nil || !supported || len(snapshots) != 5 {
+		t.Errorf("expected 5 snapshots, got %d %v %v", len(snapshots), supported, err)
+	}
+	if count := driver.CallCount("ListSnapshots"); count != 3 {
+		t.Errorf("expected 3 pages, got %d", count)
+	}
+	if _, _, err := s.ListSnapshots(context.Background(), "invalid", 0, nil); status.Code(err) != codes.Aborted {
+		t.Errorf("expected Aborted for an invalid token, got %v", err)
+	}
+}
+
+func TestInjectedErrorsAndLatency(t *testing.T) {
+	driver, conn := startDriver(t, Options{})
+	s := snapshotter.NewSnapshotter(conn)
+
+	driver.InjectError("CreateSnapshot", codes.ResourceExhausted, 2)
+	for i := 0; i < 2; i++ {
+		if _, _, _, _, _, err := s.CreateSnapshot(context.Background(), "snap-1", "vol-1", nil, nil); status.Code(err) != codes.ResourceExhausted {
+			t.Errorf("expected injected error, got %v", err)
+		}
+	}
+	if _, _, _, _, _, err := s.CreateSnapshot(context.Background(), "snap-1", "vol-1", nil, nil); err != nil {
+		t.Errorf("expected the injected error to be exhausted, got %v", err)
+	}
+
+	driver.InjectError("DeleteSnapshot", codes.Internal, -1)
+	for i := 0; i < 3; i++ {
+		if err := s.DeleteSnapshot(context.Background(), "snapshot-1", nil); status.Code(err) != codes.Internal {
+			t.Errorf("expected injected error, got %v", err)
+		}
+	}
+	driver.ClearErrors()
+	if err := s.DeleteSnapshot(context.Background(), "snapshot-1", nil); err != nil {
+		t.Errorf("expected no error after ClearErrors, got %v", err)
+	}
+
+	driver.SetLatency("CreateSnapshot", time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, _, _, _, _, err := s.CreateSnapshot(ctx, "snap-2", "vol-1", nil, nil); status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("expected DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestGroupSnapshots(t *testing.T) {
+	driver, conn := startDriver(t, Options{})
+	gs := group_snapshotter.NewGroupSnapshotter(conn)
+	ctx := context.Background()
+
+	_, groupID, snapshots, _, ready, err := gs.CreateGroupSnapshot(ctx, "group-1", []string{"vol-1", "vol-2"}, nil, nil)
+	if err != nil || len(snapshots) != 2 || !ready {
+		t.Fatalf("unexpected CreateGroupSnapshot result: %v %v %v", snapshots, ready, err)
+	}
+	ids := []string{snapshots[0].SnapshotId, snapshots[1].SnapshotId}
+	for _, snapshot := range snapshots {
+		if snapshot.GroupSnapshotId != groupID {
+			t.Errorf("expected member of %s, got %+v", groupID, snapshot)
+		}
+	}
+	ready, _, err = gs.GetGroupSnapshotStatus(ctx, groupID, ids, nil)
+	if err != nil || !ready {
+		t.Errorf("unexpected GetGroupSnapshotStatus result: %v %v", ready, err)
+	}
+	if err := gs.DeleteGroupSnapshot(ctx, groupID, ids, nil); err != nil {
+		t.Errorf("DeleteGroupSnapshot failed: %v", err)
+	}
+	if len(driver.Snapshots()) != 0 || len(driver.GroupSnapshots()) != 0 {
+		t.Errorf("expected the group and its members to be deleted, got %v %v", driver.Snapshots(), driver.GroupSnapshots())
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockcsidriver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (d *Driver) GroupControllerGetCapabilities(ctx context.Context, req *csi.GroupControllerGetCapabilitiesRequest) (*csi.GroupControllerGetCapabilitiesResponse, error) {
+	if d.options.DisableGroupSnapshots {
+		return &csi.GroupControllerGetCapabilitiesResponse{}, nil
+	}
+	return &csi.GroupControllerGetCapabilitiesResponse{
+		Capabilities: []*csi.GroupControllerServiceCapability{
+			{
+				Type: &csi.GroupControllerServiceCapability_Rpc{
+					Rpc: &csi.GroupControllerServiceCapability_RPC{
+						Type: csi.GroupControllerServiceCapability_RPC_CREATE_DELETE_GET_VOLUME_GROUP_SNAPSHOT,
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// CreateVolumeGroupSnapshot is idempotent by name, as required by the CSI spec.
+func (d *Driver) CreateVolumeGroupSnapshot(ctx context.Context, req *csi.CreateVolumeGroupSnapshotRequest) (*csi.CreateVolumeGroupSnapshotResponse, error) {
+	if d.options.DisableGroupSnapshots {
+		return nil, status.Error(codes.Unimplemented, "group snapshots are not supported")
+	}
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "group snapshot name is missing")
+	}
+	if len(req.SourceVolumeIds) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "source volume IDs are missing")
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if id, ok := d.groupNames[req.Name]; ok {
+		group := d.groupSnapshots[id]
+		return &csi.CreateVolumeGroupSnapshotResponse{GroupSnapshot: d.csiGroupSnapshot(group)}, nil
+	}
+
+	group := &GroupSnapshot{
+		ID:           d.newID("groupsnapshot"),
+		Name:         req.Name,
+		CreationTime: time.Now(),
+	}
+	for i, volumeID := range req.SourceVolumeIds {
+		snapshot := &Snapshot{
+			ID:              d.newID("snapshot"),
+			Name:            fmt.Sprintf("%s-%d", req.Name, i),
+			SourceVolumeID:  volumeID,
+			GroupSnapshotID: group.ID,
+			CreationTime:    group.CreationTime,
+			Parameters:      req.Parameters,
+			Secrets:         req.Secrets,
+		}
+		d.snapshots[snapshot.ID] = snapshot
+		d.snapshotNames[snapshot.Name] = snapshot.ID
+		group.SnapshotIDs = append(group.SnapshotIDs, snapshot.ID)
+	}
+	d.groupSnapshots[group.ID] = group
+	d.groupNames[group.Name] = group.ID
+	return &csi.CreateVolumeGroupSnapshotResponse{GroupSnapshot: d.csiGroupSnapshot(group)}, nil
+}
+
+// DeleteVolumeGroupSnapshot deletes the group and its members. It succeeds
+// for unknown groups, as required by the CSI spec.
+func (d *Driver) DeleteVolumeGroupSnapshot(ctx context.Context, req *csi.DeleteVolumeGroupSnapshotRequest) (*csi.DeleteVolumeGroupSnapshotResponse, error) {
+	if req.GroupSnapshotId == "" {
+		return nil, status.Error(codes.InvalidArgument, "group snapshot ID is missing")
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	group, ok := d.groupSnapshots[req.GroupSnapshotId]
+	if !ok {
+		return &csi.DeleteVolumeGroupSnapshotResponse{}, nil
+	}
+	for _, id := range group.SnapshotIDs {
+		if snapshot, ok := d.snapshots[id]; ok {
+			delete(d.snapshotNames, snapshot.Name)
+			delete(d.snapshots, id)
+		}
+	}
+	delete(d.groupSnapshots, group.ID)
+	delete(d.groupNames, group.Name)
+	return &csi.DeleteVolumeGroupSnapshotResponse{}, nil
+}
+
+func (d *Driver) GetVolumeGroupSnapshot(ctx context.Context, req *csi.GetVolumeGroupSnapshotRequest) (*csi.GetVolumeGroupSnapshotResponse, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	group, ok := d.groupSnapshots[req.GroupSnapshotId]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "group snapshot %s not found", req.GroupSnapshotId)
+	}
+	return &csi.GetVolumeGroupSnapshotResponse{GroupSnapshot: d.csiGroupSnapshot(group)}, nil
+}
+
+// csiGroupSnapshot observes the members of the group snapshot and returns
+// it. The group is ready when all its members are. The caller must hold the
+// mutex.
+func (d *Driver) csiGroupSnapshot(group *GroupSnapshot) *csi.VolumeGroupSnapshot {
+	rsp := &csi.VolumeGroupSnapshot{
+		GroupSnapshotId: group.ID,
+		CreationTime:    timestamppb.New(group.CreationTime),
+		ReadyToUse:      true,
+	}
+	for _, id := range group.SnapshotIDs {
+		snapshot, ok := d.snapshots[id]
+		if !ok {
+			continue
+		}
+		d.observe(snapshot)
+		rsp.Snapshots = append(rsp.Snapshots, d.csiSnapshot(snapshot))
+		rsp.ReadyToUse = rsp.ReadyToUse && snapshot.ReadyToUse
+	}
+	return rsp
+}
@@ -0,0 +1,490 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package harness runs the snapshot-controller and the csi-snapshotter
+// sidecar together against fake clientsets and the in-memory mock CSI
+// driver, so that the whole snapshot lifecycle can be tested in process.
+package harness
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/kubernetes-csi/csi-lib-utils/connection"
+	crdv1beta1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumegroupsnapshot/v1beta1"
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	snapshotfake "github.com/kubernetes-csi/external-snapshotter/client/v8/clientset/versioned/fake"
+	snapshotscheme "github.com/kubernetes-csi/external-snapshotter/client/v8/clientset/versioned/scheme"
+	informers "github.com/kubernetes-csi/external-snapshotter/client/v8/informers/externalversions"
+	common_controller "github.com/kubernetes-csi/external-snapshotter/v8/pkg/common-controller"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/credentials"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/group_snapshotter"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/metrics"
+	mockcsidriver "github.com/kubernetes-csi/external-snapshotter/v8/pkg/mock-csi-driver"
+	sidecar_controller "github.com/kubernetes-csi/external-snapshotter/v8/pkg/sidecar-controller"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/snapshotter"
+)
+
+const (
+	// DefaultTimeout is the default timeout of CSI calls and of the Wait helpers.
+	DefaultTimeout = 10 * time.Second
+
+	pollInterval = 10 * time.Millisecond
+)
+
+// Options configures a Harness.
+type Options struct {
+	// Driver configures the mock CSI driver.
+	Driver mockcsidriver.Options
+	// EnableVolumeGroupSnapshots enables the group snapshot controllers.
+	EnableVolumeGroupSnapshots bool
+	// EmulateVolumeGroupSnapshots makes the sidecar emulate group snapshots
+	// with individual snapshots cut within GroupSnapshotEmulationWindow,
+	// which defaults to DefaultTimeout.
+	EmulateVolumeGroupSnapshots  bool
+	GroupSnapshotEmulationWindow time.Duration
+	// Timeout of the CSI calls made by the sidecar. Defaults to DefaultTimeout.
+	Timeout time.Duration
+	// RetryIntervalStart and RetryIntervalMax configure the rate limiters
+	// of all work queues. They default to 10ms and 1s.
+	RetryIntervalStart time.Duration
+	RetryIntervalMax   time.Duration
+	// SnapshotStatusCacheTTL and ListSnapshotsPageSize are passed to the sidecar.
+	SnapshotStatusCacheTTL time.Duration
+	ListSnapshotsPageSize  int32
+	// RPCLimits are the limits of the CSI calls of the sidecar.
+	RPCLimits sidecar_controller.RPCLimits
+	// Credentials configures the credential providers of the sidecar.
+	Credentials credentials.Options
+}
+
+type controller interface {
+	Run(workers int, stopCh <-chan struct{})
+}
+
+// Harness is a running snapshot-controller and csi-snapshotter pair
+// connected to a mock CSI driver.
+type Harness struct {
+	Driver         *mockcsidriver.Driver
+	SnapshotClient *snapshotfake.Clientset
+	KubeClient     *kubefake.Clientset
+
+	conn   *grpc.ClientConn
+	stopCh chan struct{}
+}
+
+// Start starts the mock CSI driver and both controllers. Stop must be
+// called to release them.
+func Start(options Options) (*Harness, error) {
+	if options.Driver.Name == "" {
+		options.Driver.Name = mockcsidriver.DefaultName
+	}
+	if options.Timeout == 0 {
+		options.Timeout = DefaultTimeout
+	}
+	if options.RetryIntervalStart == 0 {
+		options.RetryIntervalStart = 10 * time.Millisecond
+	}
+	if options.RetryIntervalMax == 0 {
+		options.RetryIntervalMax = time.Second
+	}
+	if err := snapshotscheme.AddToScheme(scheme.Scheme); err != nil {
+		return nil, err
+	}
+
+	driver := mockcsidriver.New(options.Driver)
+	if err := driver.Start(); err != nil {
+		return nil, err
+	}
+	conn, err := connection.Connect(context.Background(), driver.Address(), nil)
+	if err != nil {
+		driver.Stop()
+		return nil, fmt.Errorf("failed to connect to the mock CSI driver: %v", err)
+	}
+
+	h := &Harness{
+		Driver:         driver,
+		SnapshotClient: snapshotfake.NewSimpleClientset(),
+		KubeClient:     kubefake.NewSimpleClientset(),
+		conn:           conn,
+		stopCh:         make(chan struct{}),
+	}
+	// The fake clientsets assign neither UIDs, which both controllers use
+	// to name the objects they create, nor resource versions, which their
+	// caches use to discard stale objects, and they ignore finalizers.
+	h.SnapshotClient.PrependReactor("*", "*", newAPIServerTracker(h.SnapshotClient.Tracker()).react)
+	h.KubeClient.PrependReactor("*", "*", newAPIServerTracker(h.KubeClient.Tracker()).react)
+
+	// Like the two deployments, each controller has its own informers:
+	// both modify the objects in their caches.
+	factory := informers.NewSharedInformerFactory(h.SnapshotClient, 0)
+	sidecarFactory := informers.NewSharedInformerFactory(h.SnapshotClient, 0)
+	coreFactory := coreinformers.NewSharedInformerFactory(h.KubeClient, 0)
+	rateLimiter := func() workqueue.TypedRateLimiter[string] {
+		return workqueue.NewTypedItemExponentialFailureRateLimiter[string](options.RetryIntervalStart, options.RetryIntervalMax)
+	}
+
+	commonController := common_controller.NewCSISnapshotCommonController(
+		h.SnapshotClient,
+		h.KubeClient,
+		factory.Snapshot().V1().VolumeSnapshots(),
+		factory.Snapshot().V1().VolumeSnapshotContents(),
+		factory.Snapshot().V1().VolumeSnapshotClasses(),
+		factory.Groupsnapshot().V1beta1().VolumeGroupSnapshots(),
+		factory.Groupsnapshot().V1beta1().VolumeGroupSnapshotContents(),
+		factory.Groupsnapshot().V1beta1().VolumeGroupSnapshotClasses(),
+		coreFactory.Core().V1().PersistentVolumeClaims(),
+		coreFactory.Core().V1().PersistentVolumes(),
+		nil,
+		nil,
+		nil,
+		metrics.NewMetricsManager(),
+		0,
+		rateLimiter(),
+		rateLimiter(),
+		rateLimiter(),
+		rateLimiter(),
+		false,
+		false,
+		options.EnableVolumeGroupSnapshots,
+		nil,
+	)
+
+	var groupSnapshotter group_snapshotter.GroupSnapshotter
+	switch {
+	case options.EnableVolumeGroupSnapshots && options.EmulateVolumeGroupSnapshots:
+		if options.GroupSnapshotEmulationWindow == 0 {
+			options.GroupSnapshotEmulationWindow = DefaultTimeout
+		}
+		groupSnapshotter = group_snapshotter.NewEmulatedGroupSnapshotter(snapshotter.NewSnapshotter(conn), options.GroupSnapshotEmulationWindow)
+	case options.EnableVolumeGroupSnapshots:
+		groupSnapshotter = group_snapshotter.NewGroupSnapshotter(conn)
+	}
+	sidecarController := sidecar_controller.NewCSISnapshotSideCarController(
+		h.SnapshotClient,
+		h.KubeClient,
+		options.Driver.Name,
+		sidecarFactory.Snapshot().V1().VolumeSnapshotContents(),
+		sidecarFactory.Snapshot().V1().VolumeSnapshotClasses(),
+		snapshotter.NewSnapshotter(conn),
+		groupSnapshotter,
+		options.Timeout,
+		0,
+		"snapshot",
+		-1,
+		"groupsnapshot",
+		-1,
+		false,
+		rateLimiter(),
+		options.EnableVolumeGroupSnapshots,
+		sidecarFactory.Groupsnapshot().V1beta1().VolumeGroupSnapshotContents(),
+		sidecarFactory.Groupsnapshot().V1beta1().VolumeGroupSnapshotClasses(),
+		rateLimiter(),
+		options.SnapshotStatusCacheTTL,
+		options.ListSnapshotsPageSize,
+		options.RPCLimits,
+		options.Credentials,
+	)
+
+	factory.Start(h.stopCh)
+	sidecarFactory.Start(h.stopCh)
+	coreFactory.Start(h.stopCh)
+	for _, ctrl := range []controller{commonController, sidecarController} {
+		go ctrl.Run(1, h.stopCh)
+	}
+	return h, nil
+}
+
+// Stop stops both controllers and the mock CSI driver.
+func (h *Harness) Stop() {
+	close(h.stopCh)
+	h.conn.Close()
+	h.Driver.Stop()
+}
+
+// apiServerTracker is an object tracker that behaves like the API server:
+// it assigns a UID to created objects and a new resource version to every
+// created, updated or patched object, and it only removes objects with
+// finalizers once the last finalizer is removed.
+type apiServerTracker struct {
+	k8stesting.ObjectTracker
+
+	reaction k8stesting.ReactionFunc
+	mutex    sync.Mutex
+	version  int64
+}
+
+func newAPIServerTracker(tracker k8stesting.ObjectTracker) *apiServerTracker {
+	t := &apiServerTracker{ObjectTracker: tracker}
+	t.reaction = k8stesting.ObjectReaction(t)
+	return t
+}
+
+func (t *apiServerTracker) react(action k8stesting.Action) (bool, runtime.Object, error) {
+	handled, obj, err := t.reaction(action)
+	if err != nil || obj == nil || (action.GetVerb() != "update" && action.GetVerb() != "patch") {
+		return handled, obj, err
+	}
+	object, err := meta.Accessor(obj)
+	if err != nil {
+		return handled, obj, err
+	}
+	if object.GetDeletionTimestamp() != nil && len(object.GetFinalizers()) == 0 {
+		err = t.ObjectTracker.Delete(action.GetResource(), action.GetNamespace(), object.GetName())
+	}
+	return handled, obj, err
+}
+
+func (t *apiServerTracker) Create(gvr schema.GroupVersionResource, obj runtime.Object, ns string, opts ...metav1.CreateOptions) error {
+	object, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	if object.GetUID() == "" {
+		object.SetUID(uuid.NewUUID())
+	}
+	t.bump(object)
+	return t.ObjectTracker.Create(gvr, obj, ns, opts...)
+}
+
+func (t *apiServerTracker) Update(gvr schema.GroupVersionResource, obj runtime.Object, ns string, opts ...metav1.UpdateOptions) error {
+	changed, err := t.prepareWrite(gvr, obj, ns)
+	if err != nil || !changed {
+		return err
+	}
+	return t.ObjectTracker.Update(gvr, obj, ns, opts...)
+}
+
+func (t *apiServerTracker) Patch(gvr schema.GroupVersionResource, obj runtime.Object, ns string, opts ...metav1.PatchOptions) error {
+	changed, err := t.prepareWrite(gvr, obj, ns)
+	if err != nil || !changed {
+		return err
+	}
+	return t.ObjectTracker.Patch(gvr, obj, ns, opts...)
+}
+
+// prepareWrite assigns a new resource version to obj, unless it is equal to
+// the stored object, in which case the write is skipped and no watch event
+// is sent, as the API server does for no-op updates.
+func (t *apiServerTracker) prepareWrite(gvr schema.GroupVersionResource, obj runtime.Object, ns string) (bool, error) {
+	object, err := meta.Accessor(obj)
+	if err != nil {
+		return false, err
+	}
+	if stored, err := t.ObjectTracker.Get(gvr, ns, object.GetName()); err == nil {
+		if storedObject, err := meta.Accessor(stored); err == nil {
+			object.SetResourceVersion(storedObject.GetResourceVersion())
+			if equality.Semantic.DeepEqual(stored, obj) {
+				return false, nil
+			}
+		}
+	}
+	t.bump(object)
+	return true, nil
+}
+
+func (t *apiServerTracker) Delete(gvr schema.GroupVersionResource, ns, name string, opts ...metav1.DeleteOptions) error {
+	obj, err := t.ObjectTracker.Get(gvr, ns, name)
+	if err != nil {
+		return err
+	}
+	object, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	if len(object.GetFinalizers()) == 0 {
+		return t.ObjectTracker.Delete(gvr, ns, name, opts...)
+	}
+	if object.GetDeletionTimestamp() != nil {
+		return nil
+	}
+	now := metav1.Now()
+	object.SetDeletionTimestamp(&now)
+	t.bump(object)
+	return t.ObjectTracker.Update(gvr, obj, ns)
+}
+
+func (t *apiServerTracker) bump(object metav1.Object) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.version++
+	object.SetResourceVersion(strconv.FormatInt(t.version, 10))
+}
+
+// CreateVolume creates a PersistentVolume of the mock driver with the given
+// volume handle and a PersistentVolumeClaim with the given labels bound to it.
+func (h *Harness) CreateVolume(namespace, claimName, volumeHandle string, labels map[string]string) (*v1.PersistentVolumeClaim, error) {
+	ctx := context.Background()
+	claim, err := h.KubeClient.CoreV1().PersistentVolumeClaims(namespace).Create(ctx, &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: claimName, Namespace: namespace, Labels: labels},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+			Resources: v1.VolumeResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceStorage: resource.MustParse("1Gi")},
+			},
+			VolumeName: "pv-" + claimName,
+		},
+		Status: v1.PersistentVolumeClaimStatus{Phase: v1.ClaimBound},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	_, err = h.KubeClient.CoreV1().PersistentVolumes().Create(ctx, &v1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-" + claimName},
+		Spec: v1.PersistentVolumeSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+			Capacity:    v1.ResourceList{v1.ResourceStorage: resource.MustParse("1Gi")},
+			PersistentVolumeSource: v1.PersistentVolumeSource{
+				CSI: &v1.CSIPersistentVolumeSource{Driver: h.Driver.Name(), VolumeHandle: volumeHandle},
+			},
+			ClaimRef: &v1.ObjectReference{
+				Kind:      "PersistentVolumeClaim",
+				Namespace: namespace,
+				Name:      claimName,
+				UID:       claim.UID,
+			},
+		},
+		Status: v1.PersistentVolumeStatus{Phase: v1.VolumeBound},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return claim, nil
+}
+
+// CreateSnapshotClass creates a VolumeSnapshotClass of the mock driver.
+func (h *Harness) CreateSnapshotClass(name string, deletionPolicy crdv1.DeletionPolicy) (*crdv1.VolumeSnapshotClass, error) {
+	return h.SnapshotClient.SnapshotV1().VolumeSnapshotClasses().Create(context.Background(), &crdv1.VolumeSnapshotClass{
+		ObjectMeta:     metav1.ObjectMeta{Name: name},
+		Driver:         h.Driver.Name(),
+		DeletionPolicy: deletionPolicy,
+	}, metav1.CreateOptions{})
+}
+
+// CreateSnapshot creates a dynamically provisioned VolumeSnapshot of the
+// given claim.
+func (h *Harness) CreateSnapshot(namespace, name, claimName, className string) (*crdv1.VolumeSnapshot, error) {
+	return h.SnapshotClient.SnapshotV1().VolumeSnapshots(namespace).Create(context.Background(), &crdv1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: crdv1.VolumeSnapshotSpec{
+			Source:                  crdv1.VolumeSnapshotSource{PersistentVolumeClaimName: &claimName},
+			VolumeSnapshotClassName: &className,
+		},
+	}, metav1.CreateOptions{})
+}
+
+// DeleteSnapshot deletes a VolumeSnapshot.
+func (h *Harness) DeleteSnapshot(namespace, name string) error {
+	return h.SnapshotClient.SnapshotV1().VolumeSnapshots(namespace).Delete(context.Background(), name, metav1.DeleteOptions{})
+}
+
+// CreateGroupSnapshotClass creates a VolumeGroupSnapshotClass of the mock driver.
+func (h *Harness) CreateGroupSnapshotClass(name string, deletionPolicy crdv1.DeletionPolicy) (*crdv1beta1.VolumeGroupSnapshotClass, error) {
+	return h.SnapshotClient.GroupsnapshotV1beta1().VolumeGroupSnapshotClasses().Create(context.Background(), &crdv1beta1.VolumeGroupSnapshotClass{
+		ObjectMeta:     metav1.ObjectMeta{Name: name},
+		Driver:         h.Driver.Name(),
+		DeletionPolicy: deletionPolicy,
+	}, metav1.CreateOptions{})
+}
+
+// CreateGroupSnapshot creates a dynamically provisioned VolumeGroupSnapshot
+// of the claims with the given labels.
+func (h *Harness) CreateGroupSnapshot(namespace, name string, labels map[string]string, className string) (*crdv1beta1.VolumeGroupSnapshot, error) {
+	return h.SnapshotClient.GroupsnapshotV1beta1().VolumeGroupSnapshots(namespace).Create(context.Background(), &crdv1beta1.VolumeGroupSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: crdv1beta1.VolumeGroupSnapshotSpec{
+			Source: crdv1beta1.VolumeGroupSnapshotSource{
+				Selector: &metav1.LabelSelector{MatchLabels: labels},
+			},
+			VolumeGroupSnapshotClassName: &className,
+		},
+	}, metav1.CreateOptions{})
+}
+
+// DeleteGroupSnapshot deletes a VolumeGroupSnapshot.
+func (h *Harness) DeleteGroupSnapshot(namespace, name string) error {
+	return h.SnapshotClient.GroupsnapshotV1beta1().VolumeGroupSnapshots(namespace).Delete(context.Background(), name, metav1.DeleteOptions{})
+}
+
+// Wait polls condition until it returns true or an error, or until the
+// timeout expires.
+func (h *Harness) Wait(timeout time.Duration, condition func() (bool, error)) error {
+	return wait.PollUntilContextTimeout(context.Background(), pollInterval, timeout, true, func(context.Context) (bool, error) {
+		return condition()
+	})
+}
+
+// WaitForSnapshot waits until the given VolumeSnapshot satisfies condition
+// and returns it.
+func (h *Harness) WaitForSnapshot(namespace, name string, timeout time.Duration, condition func(*crdv1.VolumeSnapshot) bool) (*crdv1.VolumeSnapshot, error) {
+	var snapshot *crdv1.VolumeSnapshot
+	err := h.Wait(timeout, func() (bool, error) {
+		var err error
+		snapshot, err = h.SnapshotClient.SnapshotV1().VolumeSnapshots(namespace).Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			return false, nil
+		}
+		return condition(snapshot), nil
+	})
+	if err != nil {
+		return snapshot, fmt.Errorf("timed out waiting for VolumeSnapshot %s/%s: %v", namespace, name, err)
+	}
+	return snapshot, nil
+}
+
+// WaitForSnapshotReady waits until the given VolumeSnapshot is ready to use.
+func (h *Harness) WaitForSnapshotReady(namespace, name string, timeout time.Duration) (*crdv1.VolumeSnapshot, error) {
+	return h.WaitForSnapshot(namespace, name, timeout, func(snapshot *crdv1.VolumeSnapshot) bool {
+		return snapshot.Status != nil && snapshot.Status.ReadyToUse != nil && *snapshot.Status.ReadyToUse
+	})
+}
+
+// WaitForGroupSnapshotReady waits until the given VolumeGroupSnapshot is
+// ready to use and returns it.
+func (h *Harness) WaitForGroupSnapshotReady(namespace, name string, timeout time.Duration) (*crdv1beta1.VolumeGroupSnapshot, error) {
+	var groupSnapshot *crdv1beta1.VolumeGroupSnapshot
+	err := h.Wait(timeout, func() (bool, error) {
+		var err error
+		groupSnapshot, err = h.SnapshotClient.GroupsnapshotV1beta1().VolumeGroupSnapshots(namespace).Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			return false, nil
+		}
+		return groupSnapshot.Status != nil && groupSnapshot.Status.ReadyToUse != nil && *groupSnapshot.Status.ReadyToUse, nil
+	})
+	if err != nil {
+		return groupSnapshot, fmt.Errorf("timed out waiting for VolumeGroupSnapshot %s/%s: %v", namespace, name, err)
+	}
+	return groupSnapshot, nil
+}
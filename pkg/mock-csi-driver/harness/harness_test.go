@@ -0,0 +1,162 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package harness
+
+import (
+	"testing"
+
+	"google.golang.org/grpc/codes"
+
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	mockcsidriver "github.com/kubernetes-csi/external-snapshotter/v8/pkg/mock-csi-driver"
+)
+
+const testNamespace = "default"
+
+func startHarness(t *testing.T, options Options) *Harness {
+	h, err := Start(options)
+	if err != nil {
+		t.Fatalf("failed to start harness: %v", err)
+	}
+	t.Cleanup(h.Stop)
+	if _, err := h.CreateSnapshotClass("class", crdv1.VolumeSnapshotContentDelete); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.CreateVolume(testNamespace, "claim", "vol-1", nil); err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func TestSnapshotLifecycle(t *testing.T) {
+	h := startHarness(t, Options{})
+
+	if _, err := h.CreateSnapshot(testNamespace, "snap", "claim", "class"); err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err := h.WaitForSnapshotReady(testNamespace, "snap", DefaultTimeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Status.BoundVolumeSnapshotContentName == nil || snapshot.Status.RestoreSize == nil {
+		t.Errorf("expected a bound snapshot with a restore size, got %+v", snapshot.Status)
+	}
+	snapshots := h.Driver.Snapshots()
+	if len(snapshots) != 1 {
+		t.Fatalf("expected 1 backend snapshot, got %v", snapshots)
+	}
+	for _, backend := range snapshots {
+		if backend.SourceVolumeID != "vol-1" {
+			t.Errorf("expected a snapshot of vol-1, got %+v", backend)
+		}
+	}
+
+	if err := h.DeleteSnapshot(testNamespace, "snap"); err != nil {
+		t.Fatal(err)
+	}
+	err = h.Wait(DefaultTimeout, func() (bool, error) {
+		return len(h.Driver.Snapshots()) == 0, nil
+	})
+	if err != nil {
+		t.Errorf("expected the backend snapshot to be deleted: %v", err)
+	}
+}
+
+func TestSnapshotRetriesInjectedErrors(t *testing.T) {
+	h := startHarness(t, Options{})
+	h.Driver.InjectError("CreateSnapshot", codes.Unavailable, 3)
+
+	if _, err := h.CreateSnapshot(testNamespace, "snap", "claim", "class"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := h.WaitForSnapshotReady(testNamespace, "snap", DefaultTimeout); err != nil {
+		t.Fatal(err)
+	}
+	if count := h.Driver.CallCount("CreateSnapshot"); count < 4 {
+		t.Errorf("expected CreateSnapshot to be retried, got %d calls", count)
+	}
+}
+
+func TestSnapshotBecomesReady(t *testing.T) {
+	h := startHarness(t, Options{Driver: mockcsidriver.Options{ReadyAfter: 3}})
+
+	if _, err := h.CreateSnapshot(testNamespace, "snap", "claim", "class"); err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err := h.WaitForSnapshot(testNamespace, "snap", DefaultTimeout, func(snapshot *crdv1.VolumeSnapshot) bool {
+		return snapshot.Status != nil && snapshot.Status.BoundVolumeSnapshotContentName != nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Status.ReadyToUse != nil && *snapshot.Status.ReadyToUse {
+		t.Errorf("expected the snapshot not to be ready before the driver reports it")
+	}
+	if _, err := h.WaitForSnapshotReady(testNamespace, "snap", DefaultTimeout); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEmulatedGroupSnapshot(t *testing.T) {
+	h := startHarness(t, Options{
+		Driver:                      mockcsidriver.Options{DisableGroupSnapshots: true},
+		EnableVolumeGroupSnapshots:  true,
+		EmulateVolumeGroupSnapshots: true,
+	})
+	labels := map[string]string{"app": "db"}
+	for _, volume := range []string{"data", "log"} {
+		if _, err := h.CreateVolume(testNamespace, volume, "vol-"+volume, labels); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := h.CreateGroupSnapshotClass("group-class", crdv1.VolumeSnapshotContentDelete); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := h.CreateGroupSnapshot(testNamespace, "group", labels, "group-class"); err != nil {
+		t.Fatal(err)
+	}
+	groupSnapshot, err := h.WaitForGroupSnapshotReady(testNamespace, "group", DefaultTimeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if groupSnapshot.Status.Emulated == nil || !*groupSnapshot.Status.Emulated {
+		t.Errorf("expected the group snapshot to be marked as emulated, got %+v", groupSnapshot.Status)
+	}
+	if count := h.Driver.CallCount("CreateVolumeGroupSnapshot"); count != 0 {
+		t.Errorf("expected no CreateVolumeGroupSnapshot call, got %d", count)
+	}
+	snapshots := h.Driver.Snapshots()
+	if len(snapshots) != 2 {
+		t.Fatalf("expected 2 backend snapshots, got %v", snapshots)
+	}
+	for _, snapshot := range snapshots {
+		if snapshot.SourceVolumeID != "vol-data" && snapshot.SourceVolumeID != "vol-log" {
+			t.Errorf("unexpected backend snapshot %+v", snapshot)
+		}
+	}
+
+	if err := h.DeleteGroupSnapshot(testNamespace, "group"); err != nil {
+		t.Fatal(err)
+	}
+	err = h.Wait(DefaultTimeout, func() (bool, error) {
+		return len(h.Driver.Snapshots()) == 0, nil
+	})
+	if err != nil {
+		t.Errorf("expected the backend snapshots to be deleted: %v", err)
+	}
+}
@@ -23,6 +23,8 @@ import (
 
 	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
 	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	v1 "k8s.io/api/core/v1"
 )
 
@@ -95,7 +97,7 @@ func TestSyncContent(t *testing.T) {
 					SnapshotHandle: nil,
 					RestoreSize:    nil,
 					ReadyToUse:     &False,
-					Error:          newSnapshotError("Failed to check and update snapshot content: failed to get input parameters to create snapshot for content content1-3: \"cannot retrieve secrets for snapshot content \\\"content1-3\\\", err: secret name or namespace not specified\""),
+					Error:          newSnapshotError("Failed to check and update snapshot content: failed to get input parameters to create snapshot for content content1-3: \"cannot retrieve secrets for snapshot content \\\"content1-3\\\", err: secret name or namespace not specified\"", crdv1.VolumeSnapshotErrorReasonSecretError),
 				}), map[string]string{
 				utils.AnnDeletionSecretRefName:      "",
 				utils.AnnDeletionSecretRefNamespace: "",
@@ -160,7 +162,7 @@ func TestSyncContent(t *testing.T) {
 					SnapshotHandle: nil,
 					RestoreSize:    nil,
 					ReadyToUse:     &False,
-					Error:          newSnapshotError(`Failed to check and update snapshot content: failed to get input parameters to create snapshot for content content1-5: "cannot get credentials for snapshot content \"content1-5\""`),
+					Error:          newSnapshotError(`Failed to check and update snapshot content: failed to get input parameters to create snapshot for content content1-5: "cannot get credentials for snapshot content \"content1-5\""`, crdv1.VolumeSnapshotErrorReasonSecretError),
 				}), map[string]string{
 				utils.AnnDeletionSecretRefName:      "secret",
 				utils.AnnDeletionSecretRefNamespace: "default",
@@ -181,7 +183,7 @@ func TestSyncContent(t *testing.T) {
 					SnapshotHandle: toStringPointer("sid1-6"),
 					RestoreSize:    &defaultSize,
 					ReadyToUse:     &False,
-					Error:          newSnapshotError("Failed to check and update snapshot content: failed to get input parameters to create snapshot for content content1-6: \"volumesnapshotclass.snapshot.storage.k8s.io \\\"bad-class\\\" not found\""),
+					Error:          newSnapshotError("Failed to check and update snapshot content: failed to get input parameters to create snapshot for content content1-6: \"volumesnapshotclass.snapshot.storage.k8s.io \\\"bad-class\\\" not found\"", crdv1.VolumeSnapshotErrorReasonClassError),
 				}),
 			expectedEvents: []string{"Warning SnapshotContentCheckandUpdateFailed"},
 			expectedCreateCalls: []createCall{
@@ -290,6 +292,71 @@ func TestSyncContent(t *testing.T) {
 			expectSuccess: true,
 			test:          testSyncContent,
 		},
+		{
+			name: "1-10: Basic sync content create snapshot with final CSI error",
+			initialContents: withContentStatus(newContentArray("content1-10", "snapuid1-10", "snap1-10", "sid1-10", defaultClass, "", "volume-handle-1-10", retainPolicy, nil, &defaultSize, true),
+				nil),
+			expectedContents: withContentStatus(newContentArray("content1-10", "snapuid1-10", "snap1-10", "sid1-10", defaultClass, "", "volume-handle-1-10", retainPolicy, nil, &defaultSize, true),
+				&crdv1.VolumeSnapshotContentStatus{
+					ReadyToUse: &False,
+					Error:      newCSISnapshotError(`Failed to create snapshot: failed to take snapshot of the volume volume-handle-1-10: "rpc error: code = InvalidArgument desc = mock create error"`, "InvalidArgument", true),
+				}),
+			expectedEvents: []string{"Warning SnapshotCreationFailed"},
+			expectedCreateCalls: []createCall{
+				{
+					volumeHandle: "volume-handle-1-10",
+					snapshotName: "snapshot-snapuid1-10",
+					parameters: map[string]string{
+						utils.PrefixedVolumeSnapshotNameKey:        "snap1-10",
+						utils.PrefixedVolumeSnapshotNamespaceKey:   "default",
+						utils.PrefixedVolumeSnapshotContentNameKey: "content1-10",
+					},
+					err: status.Error(codes.InvalidArgument, "mock create error"),
+				},
+			},
+			errors: noerrors,
+			test:   testSyncContent,
+		},
+		{
+			name: "1-11: Basic sync content create snapshot with secret selected by labels",
+			initialContents: withContentAnnotations(withContentStatus(newContentArray("content1-11", "snapuid1-11", "snap1-11", "sid1-11", defaultClass, "", "volume-handle-1-11", retainPolicy, nil, &defaultSize, true),
+				nil), map[string]string{
+				utils.AnnDeletionCredentials: `{"secretSelector":{"namespace":"default","selector":"snapshotter=true"}}`,
+			}),
+			expectedContents: withContentAnnotations(withContentStatus(newContentArray("content1-11", "snapuid1-11", "snap1-11", "sid1-11", defaultClass, "", "volume-handle-1-11", retainPolicy, nil, &defaultSize, true),
+				&crdv1.VolumeSnapshotContentStatus{
+					SnapshotHandle: toStringPointer("snapuid1-11"),
+					RestoreSize:    &defaultSize,
+					ReadyToUse:     &True,
+				}), map[string]string{
+				utils.AnnDeletionCredentials: `{"secretSelector":{"namespace":"default","selector":"snapshotter=true"}}`,
+			}),
+			expectedCreateCalls: []createCall{
+				{
+					volumeHandle: "volume-handle-1-11",
+					snapshotName: "snapshot-snapuid1-11",
+					parameters: map[string]string{
+						utils.PrefixedVolumeSnapshotNameKey:        "snap1-11",
+						utils.PrefixedVolumeSnapshotNamespaceKey:   "default",
+						utils.PrefixedVolumeSnapshotContentNameKey: "content1-11",
+					},
+					secrets: map[string]string{
+						"foo": "bar",
+					},
+					driverName:   mockDriverName,
+					snapshotId:   "snapuid1-11",
+					creationTime: timeNow,
+					readyToUse:   true,
+					size:         defaultSize,
+				},
+			},
+			expectedListCalls: []listCall{{"sid1-11", map[string]string{}, true, time.Now(), 1, nil, ""}},
+			expectSuccess:     true,
+			initialSecrets:    []*v1.Secret{labeledSecret()},
+			expectedEvents:    noevents,
+			errors:            noerrors,
+			test:              testSyncContent,
+		},
 	}
 
 	runSyncContentTests(t, tests, snapshotClasses)
@@ -25,9 +25,17 @@ import (
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	crdv1beta1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumegroupsnapshot/v1beta1"
 	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/group_snapshotter"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	klog "k8s.io/klog/v2"
 
 	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	groupsnapshotlisters "github.com/kubernetes-csi/external-snapshotter/client/v8/listers/volumegroupsnapshot/v1beta1"
+	snapshotlisters "github.com/kubernetes-csi/external-snapshotter/client/v8/listers/volumesnapshot/v1"
 	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/snapshotter"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/tracing"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
 )
 
 // Handler is responsible for handling VolumeSnapshot events from informer.
@@ -49,6 +57,10 @@ type csiHandler struct {
 	snapshotNameUUIDLength      int
 	groupSnapshotNamePrefix     string
 	groupSnapshotNameUUIDLength int
+	statusCache                 *snapshotStatusCache
+	limiters                    *rpcLimiters
+	classLister                 snapshotlisters.VolumeSnapshotClassLister
+	groupSnapshotClassLister    groupsnapshotlisters.VolumeGroupSnapshotClassLister
 }
 
 // NewCSIHandler returns a handler which includes the csi connection and Snapshot name details
@@ -60,7 +72,16 @@ func NewCSIHandler(
 	snapshotNameUUIDLength int,
 	groupSnapshotNamePrefix string,
 	groupSnapshotNameUUIDLength int,
+	snapshotStatusCacheTTL time.Duration,
+	listSnapshotsPageSize int32,
+	limiters *rpcLimiters,
+	classLister snapshotlisters.VolumeSnapshotClassLister,
+	groupSnapshotClassLister groupsnapshotlisters.VolumeGroupSnapshotClassLister,
 ) Handler {
+	var statusCache *snapshotStatusCache
+	if snapshotStatusCacheTTL > 0 {
+		statusCache = newSnapshotStatusCache(snapshotter, snapshotStatusCacheTTL, listSnapshotsPageSize, timeout, limiters)
+	}
 	return &csiHandler{
 		snapshotter:                 snapshotter,
 		groupSnapshotter:            groupSnapshotter,
@@ -69,13 +90,14 @@ func NewCSIHandler(
 		snapshotNameUUIDLength:      snapshotNameUUIDLength,
 		groupSnapshotNamePrefix:     groupSnapshotNamePrefix,
 		groupSnapshotNameUUIDLength: groupSnapshotNameUUIDLength,
+		statusCache:                 statusCache,
+		limiters:                    limiters,
+		classLister:                 classLister,
+		groupSnapshotClassLister:    groupSnapshotClassLister,
 	}
 }
 
 func (handler *csiHandler) CreateSnapshot(content *crdv1.VolumeSnapshotContent, parameters map[string]string, snapshotterCredentials map[string]string) (string, string, time.Time, int64, bool, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), handler.timeout)
-	defer cancel()
-
 	if content.Spec.VolumeSnapshotRef.UID == "" {
 		return "", "", time.Time{}, 0, false, fmt.Errorf("cannot create snapshot. Snapshot content %s not bound to a snapshot", content.Name)
 	}
@@ -88,13 +110,30 @@ func (handler *csiHandler) CreateSnapshot(content *crdv1.VolumeSnapshotContent,
 	if err != nil {
 		return "", "", time.Time{}, 0, false, err
 	}
-	return handler.snapshotter.CreateSnapshot(ctx, snapshotName, *content.Spec.Source.VolumeHandle, parameters, snapshotterCredentials)
-}
 
-func (handler *csiHandler) DeleteSnapshot(content *crdv1.VolumeSnapshotContent, snapshotterCredentials map[string]string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), handler.timeout)
+	// Continue the trace of the VolumeSnapshot, if any, into the CSI call.
+	ctx, span := tracing.Start(tracing.ExtractAnnotation(context.Background(), content.Annotations, utils.AnnTraceParent), "CreateSnapshot", trace.WithAttributes(
+		attribute.String("volumesnapshotcontent.name", content.Name),
+		attribute.String("csi.snapshot.name", snapshotName),
+	))
+	defer span.End()
+
+	release, err := handler.waitForSnapshotRPC(ctx, utils.RPCCreateSnapshot, content)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return "", "", time.Time{}, 0, false, err
+	}
+	defer release()
+	ctx, cancel := context.WithTimeout(ctx, handler.timeout)
 	defer cancel()
+	driverName, snapshotID, creationTime, size, readyToUse, err := handler.snapshotter.CreateSnapshot(ctx, snapshotName, *content.Spec.Source.VolumeHandle, parameters, snapshotterCredentials)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return driverName, snapshotID, creationTime, size, readyToUse, err
+}
 
+func (handler *csiHandler) DeleteSnapshot(content *crdv1.VolumeSnapshotContent, snapshotterCredentials map[string]string) error {
 	var snapshotHandle string
 	var err error
 	if content.Status != nil && content.Status.SnapshotHandle != nil {
@@ -105,6 +144,13 @@ func (handler *csiHandler) DeleteSnapshot(content *crdv1.VolumeSnapshotContent,
 		return fmt.Errorf("failed to delete snapshot content %s: snapshotHandle is missing", content.Name)
 	}
 
+	release, err := handler.waitForSnapshotRPC(context.Background(), utils.RPCDeleteSnapshot, content)
+	if err != nil {
+		return fmt.Errorf("failed to delete snapshot content %s: %q", content.Name, err)
+	}
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), handler.timeout)
+	defer cancel()
 	err = handler.snapshotter.DeleteSnapshot(ctx, snapshotHandle, snapshotterCredentials)
 	if err != nil {
 		return fmt.Errorf("failed to delete snapshot content %s: %q", content.Name, err)
@@ -114,9 +160,6 @@ func (handler *csiHandler) DeleteSnapshot(content *crdv1.VolumeSnapshotContent,
 }
 
 func (handler *csiHandler) GetSnapshotStatus(content *crdv1.VolumeSnapshotContent, snapshotterListCredentials map[string]string) (bool, time.Time, int64, string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), handler.timeout)
-	defer cancel()
-
 	var snapshotHandle string
 	var err error
 	if content.Status != nil && content.Status.SnapshotHandle != nil {
@@ -127,10 +170,37 @@ func (handler *csiHandler) GetSnapshotStatus(content *crdv1.VolumeSnapshotConten
 		return false, time.Time{}, 0, "", fmt.Errorf("failed to list snapshot for content %s: snapshotHandle is missing", content.Name)
 	}
 
-	csiSnapshotStatus, timestamp, size, groupSnapshotID, err := handler.snapshotter.GetSnapshotStatus(ctx, snapshotHandle, snapshotterListCredentials)
+	if handler.statusCache != nil {
+		snapshot, found, listSnapshotsSupported, err := handler.statusCache.get(snapshotHandle, snapshotterListCredentials)
+		if err != nil {
+			return false, time.Time{}, 0, "", utils.NewCSIStatusError(err, fmt.Errorf("failed to list snapshots for content %s: %q", content.Name, err))
+		}
+		if !listSnapshotsSupported {
+			// Same as GetSnapshotStatus: assume the snapshot ID is valid.
+			return true, time.Time{}, 0, "", nil
+		}
+		if found {
+			var creationTime time.Time
+			if snapshot.CreationTime != nil {
+				creationTime = snapshot.CreationTime.AsTime()
+			}
+			return snapshot.ReadyToUse, creationTime, snapshot.SizeBytes, snapshot.GroupSnapshotId, nil
+		}
+		// The snapshot may have been created after the listing.
+		klog.V(5).Infof("GetSnapshotStatus: snapshot %s of content %s not found in the cached listing", snapshotHandle, content.Name)
+	}
+
+	release, err := handler.waitForSnapshotRPC(context.Background(), utils.RPCListSnapshots, content)
 	if err != nil {
 		return false, time.Time{}, 0, "", fmt.Errorf("failed to list snapshot for content %s: %q", content.Name, err)
 	}
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), handler.timeout)
+	defer cancel()
+	csiSnapshotStatus, timestamp, size, groupSnapshotID, err := handler.snapshotter.GetSnapshotStatus(ctx, snapshotHandle, snapshotterListCredentials)
+	if err != nil {
+		return false, time.Time{}, 0, "", utils.NewCSIStatusError(err, fmt.Errorf("failed to list snapshot for content %s: %q", content.Name, err))
+	}
 
 	return csiSnapshotStatus, timestamp, size, groupSnapshotID, nil
 }
@@ -149,9 +219,6 @@ func makeSnapshotName(prefix, snapshotUID string, snapshotNameUUIDLength int) (s
 }
 
 func (handler *csiHandler) CreateGroupSnapshot(content *crdv1beta1.VolumeGroupSnapshotContent, parameters map[string]string, snapshotterCredentials map[string]string) (string, string, []*csi.Snapshot, time.Time, bool, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), handler.timeout)
-	defer cancel()
-
 	if content.Spec.VolumeGroupSnapshotRef.UID == "" {
 		return "", "", nil, time.Time{}, false, fmt.Errorf("cannot create group snapshot. Group snapshot content %s not bound to a group snapshot", content.Name)
 	}
@@ -164,13 +231,26 @@ func (handler *csiHandler) CreateGroupSnapshot(content *crdv1beta1.VolumeGroupSn
 	if err != nil {
 		return "", "", nil, time.Time{}, false, err
 	}
-	return handler.groupSnapshotter.CreateGroupSnapshot(ctx, groupSnapshotName, content.Spec.Source.VolumeHandles, parameters, snapshotterCredentials)
-}
 
-func (handler *csiHandler) DeleteGroupSnapshot(content *crdv1beta1.VolumeGroupSnapshotContent, snapshotIDs []string, snapshotterCredentials map[string]string) error {
+	release, err := handler.waitForGroupSnapshotRPC(context.Background(), utils.RPCCreateGroupSnapshot, content)
+	if err != nil {
+		return "", "", nil, time.Time{}, false, err
+	}
+	defer release()
 	ctx, cancel := context.WithTimeout(context.Background(), handler.timeout)
 	defer cancel()
+	if group_snapshotter.IsEmulated(handler.groupSnapshotter) {
+		// The per-volume CreateSnapshot calls of an emulated group
+		// snapshot count against the CreateSnapshot limits of the driver
+		// and of the VolumeGroupSnapshotClass.
+		ctx = group_snapshotter.WithCreateSnapshotLimiter(ctx, func(ctx context.Context) (func(), error) {
+			return handler.waitForGroupSnapshotRPC(ctx, utils.RPCCreateSnapshot, content)
+		})
+	}
+	return handler.groupSnapshotter.CreateGroupSnapshot(ctx, groupSnapshotName, content.Spec.Source.VolumeHandles, parameters, snapshotterCredentials)
+}
 
+func (handler *csiHandler) DeleteGroupSnapshot(content *crdv1beta1.VolumeGroupSnapshotContent, snapshotIDs []string, snapshotterCredentials map[string]string) error {
 	// NOTE: snapshotIDs are required for DeleteGroupSnapshot
 	if len(snapshotIDs) == 0 {
 		return fmt.Errorf("cannot delete group snapshot content %s. No snapshots found in the group snapshot", content.Name)
@@ -185,13 +265,17 @@ func (handler *csiHandler) DeleteGroupSnapshot(content *crdv1beta1.VolumeGroupSn
 		return fmt.Errorf("failed to delete group snapshot content %s: groupsnapshotHandle is missing", content.Name)
 	}
 
+	release, err := handler.waitForGroupSnapshotRPC(context.Background(), utils.RPCDeleteGroupSnapshot, content)
+	if err != nil {
+		return fmt.Errorf("failed to delete group snapshot content %s: %q", content.Name, err)
+	}
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), handler.timeout)
+	defer cancel()
 	return handler.groupSnapshotter.DeleteGroupSnapshot(ctx, groupSnapshotHandle, snapshotIDs, snapshotterCredentials)
 }
 
 func (handler *csiHandler) GetGroupSnapshotStatus(content *crdv1beta1.VolumeGroupSnapshotContent, snapshotIDs []string, snapshotterCredentials map[string]string) (bool, time.Time, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), handler.timeout)
-	defer cancel()
-
 	// NOTE: snapshotIDs are required for GetGroupSnapshotStatus
 	if len(snapshotIDs) == 0 {
 		return false, time.Time{}, fmt.Errorf("cannot list group snapshot %s. No snapshots found in the group snapshot content", content.Name)
@@ -206,10 +290,17 @@ func (handler *csiHandler) GetGroupSnapshotStatus(content *crdv1beta1.VolumeGrou
 		return false, time.Time{}, fmt.Errorf("failed to list group snapshot for group snapshot content %s: groupSnapshotHandle is missing", content.Name)
 	}
 
-	csiSnapshotStatus, timestamp, err := handler.groupSnapshotter.GetGroupSnapshotStatus(ctx, groupSnapshotHandle, snapshotIDs, snapshotterCredentials)
+	release, err := handler.waitForGroupSnapshotRPC(context.Background(), utils.RPCGetGroupSnapshot, content)
 	if err != nil {
 		return false, time.Time{}, fmt.Errorf("failed to list group snapshot for group snapshot content %s: %q", content.Name, err)
 	}
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), handler.timeout)
+	defer cancel()
+	csiSnapshotStatus, timestamp, err := handler.groupSnapshotter.GetGroupSnapshotStatus(ctx, groupSnapshotHandle, snapshotIDs, snapshotterCredentials)
+	if err != nil {
+		return false, time.Time{}, utils.NewCSIStatusError(err, fmt.Errorf("failed to list group snapshot for group snapshot content %s: %q", content.Name, err))
+	}
 
 	return csiSnapshotStatus, timestamp, nil
 }
@@ -224,3 +315,33 @@ func (handler *csiHandler) makeGroupSnapshotName(groupSnapshotUID string) (strin
 
 	return fmt.Sprintf("%s-%s", handler.groupSnapshotNamePrefix, strings.Replace(groupSnapshotUID, "-", "", -1)[0:handler.groupSnapshotNameUUIDLength]), nil
 }
+
+// waitForSnapshotRPC waits for a slot of the RPC type under the limits of the
+// driver and of the VolumeSnapshotClass of the content, until ctx is done or
+// the controller stops. The timeout of the call starts once it has a slot.
+func (handler *csiHandler) waitForSnapshotRPC(ctx context.Context, rpcType string, content *crdv1.VolumeSnapshotContent) (func(), error) {
+	var classKey string
+	var classParameters map[string]string
+	if className := content.Spec.VolumeSnapshotClassName; className != nil && *className != "" && handler.classLister != nil {
+		classKey = snapshotClassLimiterKey(*className)
+		if class, err := handler.classLister.Get(*className); err == nil {
+			classParameters = class.Parameters
+		}
+	}
+	return handler.limiters.wait(ctx, rpcType, classKey, classParameters)
+}
+
+// waitForGroupSnapshotRPC waits for a slot of the RPC type under the limits
+// of the driver and of the VolumeGroupSnapshotClass of the content, until ctx
+// is done or the controller stops.
+func (handler *csiHandler) waitForGroupSnapshotRPC(ctx context.Context, rpcType string, content *crdv1beta1.VolumeGroupSnapshotContent) (func(), error) {
+	var classKey string
+	var classParameters map[string]string
+	if className := content.Spec.VolumeGroupSnapshotClassName; className != nil && *className != "" && handler.groupSnapshotClassLister != nil {
+		classKey = groupSnapshotClassLimiterKey(*className)
+		if class, err := handler.groupSnapshotClassLister.Get(*className); err == nil {
+			classParameters = class.Parameters
+		}
+	}
+	return handler.limiters.wait(ctx, rpcType, classKey, classParameters)
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecar_controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+)
+
+// tracingSnapshotter records the span context of the CreateSnapshot calls.
+type tracingSnapshotter struct {
+	fakeSnapshotter
+	spanContext trace.SpanContext
+}
+
+func (f *tracingSnapshotter) CreateSnapshot(ctx context.Context, snapshotName string, volumeHandle string, parameters map[string]string, snapshotterCredentials map[string]string) (string, string, time.Time, int64, bool, error) {
+	f.spanContext = trace.SpanContextFromContext(ctx)
+	return mockDriverName, "sid-" + snapshotName, time.Now(), 1, true, nil
+}
+
+func TestCreateSnapshotTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	// The default global provider cannot be restored, drop the spans of the
+	// following tests instead.
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	tests := []struct {
+		name          string
+		annotations   map[string]string
+		expectTraceID string
+		expectParent  string
+	}{
+		{
+			name:          "14-1 - CreateSnapshot continues the trace of the content",
+			annotations:   map[string]string{utils.AnnTraceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+			expectTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+			expectParent:  "00f067aa0ba902b7",
+		},
+		{
+			name:         "14-2 - CreateSnapshot starts a trace for a content without trace context",
+			expectParent: "0000000000000000",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			exporter.Reset()
+			backend := &tracingSnapshotter{fakeSnapshotter: fakeSnapshotter{t: t}}
+			handler := NewCSIHandler(backend, nil, time.Minute, "snapshot", -1, "groupsnapshot", -1, 0, 0, newRPCLimiters(nil), nil, nil)
+			content := newContent("content14", "snapuid14", "snap", "", classGold, "", "volume-handle", deletionPolicy, nil, nil, false, nil)
+			content.Annotations = test.annotations
+			if _, _, _, _, _, err := handler.CreateSnapshot(content, nil, nil); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			spans := exporter.GetSpans()
+			if len(spans) != 1 || spans[0].Name != "CreateSnapshot" {
+				t.Fatalf("expected span CreateSnapshot, got %v", spans)
+			}
+			span := spans[0]
+			if test.expectTraceID != "" && span.SpanContext.TraceID().String() != test.expectTraceID {
+				t.Errorf("expected trace ID %s, got %s", test.expectTraceID, span.SpanContext.TraceID())
+			}
+			if span.Parent.SpanID().String() != test.expectParent {
+				t.Errorf("expected parent span ID %s, got %s", test.expectParent, span.Parent.SpanID())
+			}
+			if backend.spanContext.SpanID() != span.SpanContext.SpanID() {
+				t.Errorf("expected the CSI call to be made in span %s, got %s", span.SpanContext.SpanID(), backend.spanContext.SpanID())
+			}
+		})
+	}
+}
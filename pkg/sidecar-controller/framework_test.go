@@ -26,6 +26,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/container-storage-interface/spec/lib/go/csi"
 	jsonpatch "github.com/evanphx/json-patch"
 	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
 	clientset "github.com/kubernetes-csi/external-snapshotter/client/v8/clientset/versioned"
@@ -33,10 +34,12 @@ import (
 	snapshotscheme "github.com/kubernetes-csi/external-snapshotter/client/v8/clientset/versioned/scheme"
 	informers "github.com/kubernetes-csi/external-snapshotter/client/v8/informers/externalversions"
 	storagelisters "github.com/kubernetes-csi/external-snapshotter/client/v8/listers/volumesnapshot/v1"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/credentials"
 	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/diff"
@@ -294,6 +297,17 @@ func (r *snapshotReactor) React(action core.Action) (handled bool, ret runtime.O
 		klog.V(4).Infof("GetSecret: secret %s not found", name)
 		return true, nil, fmt.Errorf("cannot find secret %s", name)
 
+	case action.Matches("list", "secrets"):
+		selector := action.(core.ListAction).GetListRestrictions().Labels
+		secrets := &v1.SecretList{}
+		for _, secret := range r.secrets {
+			if selector.Matches(labels.Set(secret.Labels)) {
+				secrets.Items = append(secrets.Items, *secret)
+			}
+		}
+		klog.V(4).Infof("ListSecrets: found %d secrets matching %s", len(secrets.Items), selector)
+		return true, secrets, nil
+
 	}
 
 	return false, nil, nil
@@ -339,16 +353,25 @@ func (r *snapshotReactor) checkContents(expectedContents []*crdv1.VolumeSnapshot
 		if v.Status.Error != nil {
 			v.Status.Error.Time = &metav1.Time{}
 		}
+		if v.Status != nil {
+			v.Status.Conditions = nil
+		}
 		expectedMap[v.Name] = v
 	}
 	for _, v := range r.contents {
 		// We must clone the content because of golang race check - it was
 		// written by the controller without any locks on it.
 		v := v.DeepCopy()
+		// The conditions are not part of the fixtures, but the conditions
+		// written by the controller must match the rest of the status.
+		if v.Status != nil && len(v.Status.Conditions) > 0 && utils.SetVolumeSnapshotContentConditions(v.DeepCopy()) {
+			return fmt.Errorf("content %s has conditions out of date with its status: %+v", v.Name, v.Status)
+		}
 		v.ResourceVersion = ""
 		v.Spec.VolumeSnapshotRef.ResourceVersion = ""
 		if v.Status != nil {
 			v.Status.CreationTime = nil
+			v.Status.Conditions = nil
 			if v.Status.Error != nil {
 				v.Status.Error.Time = &metav1.Time{}
 			}
@@ -540,6 +563,7 @@ func newSnapshotReactor(kubeClient *kubefake.Clientset, client *fake.Clientset,
 	client.AddReactor("get", "volumesnapshotcontents", reactor.React)
 	client.AddReactor("delete", "volumesnapshotcontents", reactor.React)
 	kubeClient.AddReactor("get", "secrets", reactor.React)
+	kubeClient.AddReactor("list", "secrets", reactor.React)
 
 	return reactor
 }
@@ -580,6 +604,10 @@ func newTestController(kubeClient kubernetes.Interface, clientset clientset.Inte
 		informerFactory.Groupsnapshot().V1beta1().VolumeGroupSnapshotContents(),
 		informerFactory.Groupsnapshot().V1beta1().VolumeGroupSnapshotClasses(),
 		workqueue.NewTypedItemExponentialFailureRateLimiter[string](1*time.Millisecond, 1*time.Minute),
+		0,
+		0,
+		nil,
+		credentials.Options{},
 	)
 
 	ctrl.eventRecorder = record.NewFakeRecorder(1000)
@@ -865,6 +893,13 @@ func secret() *v1.Secret {
 	}
 }
 
+func labeledSecret() *v1.Secret {
+	secret := secret()
+	secret.Name = "labeled-secret"
+	secret.Labels = map[string]string{"snapshotter": "true"}
+	return secret
+}
+
 func secretAnnotations() map[string]string {
 	return map[string]string{
 		utils.AnnDeletionSecretRefName:      "secret",
@@ -1018,11 +1053,35 @@ func (f *fakeSnapshotter) GetSnapshotStatus(ctx context.Context, snapshotID stri
 	return call.readyToUse, call.createTime, call.size, call.groupSnapshotID, call.err
 }
 
-func newSnapshotError(message string) *crdv1.VolumeSnapshotError {
+func newSnapshotError(message, reason string) *crdv1.VolumeSnapshotError {
+	final := false
+	return &crdv1.VolumeSnapshotError{
+		Time:    &metav1.Time{},
+		Message: &message,
+		Reason:  &reason,
+		Final:   &final,
+	}
+}
+
+func newCSISnapshotError(message, code string, final bool) *crdv1.VolumeSnapshotError {
+	reason := crdv1.VolumeSnapshotErrorReasonCSIDriverError
 	return &crdv1.VolumeSnapshotError{
 		Time:    &metav1.Time{},
 		Message: &message,
+		Reason:  &reason,
+		Code:    &code,
+		Final:   &final,
 	}
 }
 
 func toStringPointer(str string) *string { return &str }
+
+func (f *fakeSnapshotter) GetSnapshotStatuses(ctx context.Context, maxEntries int32, snapshotterListCredentials map[string]string) (map[string]*csi.Snapshot, bool, error) {
+	f.t.Errorf("Unexpected CSI GetSnapshotStatuses call")
+	return nil, false, fmt.Errorf("unexpected call")
+}
+
+func (f *fakeSnapshotter) ListSnapshots(ctx context.Context, startingToken string, maxEntries int32, snapshotterListCredentials map[string]string) ([]*csi.Snapshot, string, error) {
+	f.t.Errorf("Unexpected CSI ListSnapshots call: startingToken=%s", startingToken)
+	return nil, "", fmt.Errorf("unexpected call")
+}
@@ -72,7 +72,9 @@ func (ctrl *csiSnapshotSideCarController) groupSnapshotContentWorker() {
 	}
 	defer ctrl.groupSnapshotContentQueue.Done(key)
 
-	if err := ctrl.syncGroupSnapshotContentByKey(key); err != nil {
+	err := ctrl.syncGroupSnapshotContentByKey(key)
+	ctrl.groupSnapshotContentSyncResults.Record(key, err)
+	if err != nil {
 		// Rather than wait for a full resync, re-add the key to the
 		// queue to be processed.
 		ctrl.groupSnapshotContentQueue.AddRateLimited(key)
@@ -298,6 +300,7 @@ func (ctrl *csiSnapshotSideCarController) clearGroupSnapshotContentStatus(
 		groupSnapshotContent.Status.ReadyToUse = nil
 		groupSnapshotContent.Status.CreationTime = nil
 		groupSnapshotContent.Status.Error = nil
+		utils.SetVolumeGroupSnapshotContentConditions(groupSnapshotContent)
 	}
 	newContent, err := ctrl.clientset.GroupsnapshotV1beta1().VolumeGroupSnapshotContents().UpdateStatus(context.TODO(), groupSnapshotContent, metav1.UpdateOptions{})
 	if err != nil {
@@ -310,6 +313,7 @@ func (ctrl *csiSnapshotSideCarController) GetCredentialsFromAnnotationForGroupSn
 	// get secrets if VolumeGroupSnapshotClass specifies it
 	var snapshotterCredentials map[string]string
 	var err error
+	var snapshotterCredentialsRef *utils.CredentialsReference
 
 	// Check if annotation exists
 	if metav1.HasAnnotation(groupSnapshotContent.ObjectMeta, utils.AnnDeletionGroupSecretRefName) && metav1.HasAnnotation(groupSnapshotContent.ObjectMeta, utils.AnnDeletionGroupSecretRefNamespace) {
@@ -324,8 +328,16 @@ func (ctrl *csiSnapshotSideCarController) GetCredentialsFromAnnotationForGroupSn
 
 		snapshotterSecretRef.Name = annDeletionSecretName
 		snapshotterSecretRef.Namespace = annDeletionSecretNamespace
+		snapshotterCredentialsRef = &utils.CredentialsReference{Secret: snapshotterSecretRef}
+	} else if metav1.HasAnnotation(groupSnapshotContent.ObjectMeta, utils.AnnDeletionGroupCredentials) {
+		snapshotterCredentialsRef, err = utils.ParseCredentialsReference(groupSnapshotContent.Annotations[utils.AnnDeletionGroupCredentials])
+		if err != nil {
+			return nil, fmt.Errorf("cannot retrieve credentials for group snapshot content %#v, err: %v", groupSnapshotContent.Name, err)
+		}
+	}
 
-		snapshotterCredentials, err = utils.GetCredentials(ctrl.client, snapshotterSecretRef)
+	if snapshotterCredentialsRef != nil {
+		snapshotterCredentials, err = ctrl.credentials.GetCredentials(context.TODO(), snapshotterCredentialsRef)
 		if err != nil {
 			// Continue with deletion, as the secret may have already been deleted.
 			klog.Errorf("Failed to get credentials for group snapshot content %s: %s", groupSnapshotContent.Name, err.Error())
@@ -371,7 +383,7 @@ func (ctrl *csiSnapshotSideCarController) createGroupSnapshot(groupSnapshotConte
 	klog.V(5).Infof("createGroupSnapshot for group snapshot content [%s]: started", groupSnapshotContent.Name)
 	groupSnapshotContentObj, err := ctrl.createGroupSnapshotWrapper(groupSnapshotContent)
 	if err != nil {
-		ctrl.updateGroupSnapshotContentErrorStatusWithEvent(groupSnapshotContentObj, v1.EventTypeWarning, "GroupSnapshotCreationFailed", fmt.Sprintf("Failed to create group snapshot: %v", err))
+		ctrl.updateGroupSnapshotContentErrorStatusWithEvent(groupSnapshotContentObj, v1.EventTypeWarning, "GroupSnapshotCreationFailed", fmt.Sprintf("Failed to create group snapshot: %v", err), err)
 		klog.Errorf("createGroupSnapshot for groupSnapshotContent [%s]: error occurred in createGroupSnapshotWrapper: %v", groupSnapshotContent.Name, err)
 		return err
 	}
@@ -391,7 +403,7 @@ func (ctrl *csiSnapshotSideCarController) createGroupSnapshotWrapper(groupSnapsh
 
 	class, snapshotterCredentials, err := ctrl.getCSIGroupSnapshotInput(groupSnapshotContent)
 	if err != nil {
-		return groupSnapshotContent, fmt.Errorf("failed to get input parameters to create group snapshot for group snapshot content %s: %q", groupSnapshotContent.Name, err)
+		return groupSnapshotContent, utils.WrapStatusError(err, fmt.Errorf("failed to get input parameters to create group snapshot for group snapshot content %s: %q", groupSnapshotContent.Name, err))
 	}
 
 	// NOTE(xyang): handle create timeout
@@ -403,12 +415,12 @@ func (ctrl *csiSnapshotSideCarController) createGroupSnapshotWrapper(groupSnapsh
 	// the storage system.
 	groupSnapshotContent, err = ctrl.setAnnVolumeGroupSnapshotBeingCreated(groupSnapshotContent)
 	if err != nil {
-		return groupSnapshotContent, fmt.Errorf("failed to add VolumeGroupSnapshotBeingCreated annotation on the group snapshot content %s: %q", groupSnapshotContent.Name, err)
+		return groupSnapshotContent, utils.NewStatusError(crdv1.VolumeSnapshotErrorReasonAPIServerError, fmt.Errorf("failed to add VolumeGroupSnapshotBeingCreated annotation on the group snapshot content %s: %q", groupSnapshotContent.Name, err))
 	}
 
 	parameters, err := utils.RemovePrefixedParameters(class.Parameters)
 	if err != nil {
-		return groupSnapshotContent, fmt.Errorf("failed to remove CSI Parameters of prefixed keys: %v", err)
+		return groupSnapshotContent, utils.NewStatusError(crdv1.VolumeSnapshotErrorReasonClassError, fmt.Errorf("failed to remove CSI Parameters of prefixed keys: %v", err))
 	}
 	if ctrl.extraCreateMetadata {
 		parameters[utils.PrefixedVolumeGroupSnapshotNameKey] = groupSnapshotContent.Spec.VolumeGroupSnapshotRef.Name
@@ -422,14 +434,14 @@ func (ctrl *csiSnapshotSideCarController) createGroupSnapshotWrapper(groupSnapsh
 		// If it is a final error, remove annotation to indicate
 		// storage system has responded with an error
 		klog.Infof("createGroupSnapshotWrapper: CreateGroupSnapshot for groupSnapshotContent %s returned error: %v", groupSnapshotContent.Name, err)
-		if isCSIFinalError(err) {
+		if utils.IsCSIFinalError(err) {
 			var removeAnnotationErr error
 			if groupSnapshotContent, removeAnnotationErr = ctrl.removeAnnVolumeGroupSnapshotBeingCreated(groupSnapshotContent); removeAnnotationErr != nil {
-				return groupSnapshotContent, fmt.Errorf("failed to remove VolumeGroupSnapshotBeingCreated annotation from the group snapshot content %s: %s", groupSnapshotContent.Name, removeAnnotationErr)
+				return groupSnapshotContent, utils.NewStatusError(crdv1.VolumeSnapshotErrorReasonAPIServerError, fmt.Errorf("failed to remove VolumeGroupSnapshotBeingCreated annotation from the group snapshot content %s: %s", groupSnapshotContent.Name, removeAnnotationErr))
 			}
 		}
 
-		return groupSnapshotContent, fmt.Errorf("failed to take group snapshot of the volumes %s: %q", groupSnapshotContent.Spec.Source.VolumeHandles, err)
+		return groupSnapshotContent, utils.NewCSIStatusError(err, fmt.Errorf("failed to take group snapshot of the volumes %s: %q", groupSnapshotContent.Spec.Source.VolumeHandles, err))
 	}
 
 	klog.V(5).Infof("Created group snapshot: driver %s, groupSnapshotId %s, creationTime %v, readyToUse %t", driverName, groupSnapshotID, creationTime, readyToUse)
@@ -450,7 +462,7 @@ func (ctrl *csiSnapshotSideCarController) createGroupSnapshotWrapper(groupSnapsh
 	newGroupSnapshotContent, err := ctrl.updateGroupSnapshotContentStatus(groupSnapshotContent, groupSnapshotID, readyToUse, metav1.NewTime(creationTime), snapshotContentLinks)
 	if err != nil {
 		klog.Errorf("error updating status for volume group snapshot content %s: %v.", groupSnapshotContent.Name, err)
-		return groupSnapshotContent, fmt.Errorf("error updating status for volume group snapshot content %s: %v", groupSnapshotContent.Name, err)
+		return groupSnapshotContent, utils.NewStatusError(crdv1.VolumeSnapshotErrorReasonAPIServerError, fmt.Errorf("error updating status for volume group snapshot content %s: %v", groupSnapshotContent.Name, err))
 	}
 	groupSnapshotContent = newGroupSnapshotContent
 
@@ -459,7 +471,7 @@ func (ctrl *csiSnapshotSideCarController) createGroupSnapshotWrapper(groupSnapsh
 	// cut the group snapshot
 	groupSnapshotContent, err = ctrl.removeAnnVolumeGroupSnapshotBeingCreated(groupSnapshotContent)
 	if err != nil {
-		return groupSnapshotContent, fmt.Errorf("failed to remove VolumeGroupSnapshotBeingCreated annotation on the groupSnapshotContent %s: %q", groupSnapshotContent.Name, err)
+		return groupSnapshotContent, utils.NewStatusError(crdv1.VolumeSnapshotErrorReasonAPIServerError, fmt.Errorf("failed to remove VolumeGroupSnapshotBeingCreated annotation on the groupSnapshotContent %s: %q", groupSnapshotContent.Name, err))
 	}
 	return groupSnapshotContent, nil
 }
@@ -473,13 +485,13 @@ func (ctrl *csiSnapshotSideCarController) getCSIGroupSnapshotInput(groupSnapshot
 		class, err = ctrl.getGroupSnapshotClass(*className)
 		if err != nil {
 			klog.Errorf("getCSISnapshotInput failed to getClassFromVolumeGroupSnapshot %s", err)
-			return nil, nil, err
+			return nil, nil, utils.NewStatusError(crdv1.VolumeSnapshotErrorReasonClassError, err)
 		}
 	} else {
 		// If dynamic provisioning, return failure if no group snapshot class
 		if len(groupSnapshotContent.Spec.Source.VolumeHandles) != 0 {
 			klog.Errorf("failed to getCSISnapshotInput %s without a group snapshot class", groupSnapshotContent.Name)
-			return nil, nil, fmt.Errorf("failed to take group snapshot %s without a group snapshot class", groupSnapshotContent.Name)
+			return nil, nil, utils.NewStatusError(crdv1.VolumeSnapshotErrorReasonClassError, fmt.Errorf("failed to take group snapshot %s without a group snapshot class", groupSnapshotContent.Name))
 		}
 		// For pre-provisioned group snapshot, group snapshot class is not required
 		klog.V(5).Infof("getCSISnapshotInput for groupSnapshotContent [%s]: no VolumeGroupSnapshotClassName provided for pre-provisioned group snapshot", groupSnapshotContent.Name)
@@ -488,7 +500,7 @@ func (ctrl *csiSnapshotSideCarController) getCSIGroupSnapshotInput(groupSnapshot
 	// Resolve snapshotting secret credentials.
 	snapshotterCredentials, err := ctrl.GetCredentialsFromAnnotationForGroupSnapshot(groupSnapshotContent)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, utils.NewStatusError(crdv1.VolumeSnapshotErrorReasonSecretError, err)
 	}
 
 	return class, snapshotterCredentials, nil
@@ -600,6 +612,10 @@ func (ctrl *csiSnapshotSideCarController) updateGroupSnapshotContentStatus(
 			ReadyToUse:                &readyToUse,
 			CreationTime:              &createdAt,
 		}
+		if ctrl.emulatedGroupSnapshots {
+			emulated := true
+			newStatus.Emulated = &emulated
+		}
 		for _, snapshotContentLink := range snapshotContentLinks {
 
 			newStatus.VolumeSnapshotHandlePairList = append(newStatus.VolumeSnapshotHandlePairList, crdv1beta1.VolumeSnapshotHandlePair{
@@ -626,6 +642,11 @@ func (ctrl *csiSnapshotSideCarController) updateGroupSnapshotContentStatus(
 			newStatus.CreationTime = &createdAt
 			updated = true
 		}
+		if ctrl.emulatedGroupSnapshots && newStatus.Emulated == nil {
+			emulated := true
+			newStatus.Emulated = &emulated
+			updated = true
+		}
 		if len(newStatus.VolumeSnapshotHandlePairList) == 0 {
 			for _, snapshotContentLink := range snapshotContentLinks {
 				newStatus.VolumeSnapshotHandlePairList = append(newStatus.VolumeSnapshotHandlePairList, crdv1beta1.VolumeSnapshotHandlePair{
@@ -637,9 +658,13 @@ func (ctrl *csiSnapshotSideCarController) updateGroupSnapshotContentStatus(
 		}
 	}
 
+	groupSnapshotContentClone := groupSnapshotContentObj.DeepCopy()
+	groupSnapshotContentClone.Status = newStatus
+	if utils.SetVolumeGroupSnapshotContentConditions(groupSnapshotContentClone) {
+		updated = true
+	}
+
 	if updated {
-		groupSnapshotContentClone := groupSnapshotContentObj.DeepCopy()
-		groupSnapshotContentClone.Status = newStatus
 		newContent, err := ctrl.clientset.GroupsnapshotV1beta1().VolumeGroupSnapshotContents().UpdateStatus(context.TODO(), groupSnapshotContentClone, metav1.UpdateOptions{})
 		if err != nil {
 			return groupSnapshotContentObj, newControllerUpdateError(groupSnapshotContent.Name, err.Error())
@@ -657,7 +682,8 @@ func (ctrl *csiSnapshotSideCarController) updateGroupSnapshotContentStatus(
 //
 // * groupSnapshotContent - group snapshot content to update
 // * eventtype, reason, message - event to send, see EventRecorder.Event()
-func (ctrl *csiSnapshotSideCarController) updateGroupSnapshotContentErrorStatusWithEvent(groupSnapshotContent *crdv1beta1.VolumeGroupSnapshotContent, eventtype, reason, message string) error {
+// * err - error reported by the status, whose reason defaults to the reason of the event
+func (ctrl *csiSnapshotSideCarController) updateGroupSnapshotContentErrorStatusWithEvent(groupSnapshotContent *crdv1beta1.VolumeGroupSnapshotContent, eventtype, reason, message string, err error) error {
 	klog.V(5).Infof("updateGroupSnapshotContentErrorStatusWithEvent[%s]", groupSnapshotContent.Name)
 
 	if groupSnapshotContent.Status != nil && groupSnapshotContent.Status.Error != nil && *groupSnapshotContent.Status.Error.Message == message {
@@ -667,12 +693,7 @@ func (ctrl *csiSnapshotSideCarController) updateGroupSnapshotContentErrorStatusW
 
 	var patches []utils.PatchOp
 	ready := false
-	groupSnapshotContentStatusError := &crdv1.VolumeSnapshotError{
-		Time: &metav1.Time{
-			Time: time.Now(),
-		},
-		Message: &message,
-	}
+	groupSnapshotContentStatusError := utils.NewVolumeSnapshotError(message, reason, err)
 	if groupSnapshotContent.Status == nil {
 		// Initialize status if nil
 		patches = append(patches, utils.PatchOp{
@@ -697,6 +718,19 @@ func (ctrl *csiSnapshotSideCarController) updateGroupSnapshotContentErrorStatusW
 		})
 
 	}
+	// Patch the conditions computed from the patched status
+	groupSnapshotContentClone := groupSnapshotContent.DeepCopy()
+	if groupSnapshotContentClone.Status == nil {
+		groupSnapshotContentClone.Status = &crdv1beta1.VolumeGroupSnapshotContentStatus{}
+	}
+	groupSnapshotContentClone.Status.Error = groupSnapshotContentStatusError
+	groupSnapshotContentClone.Status.ReadyToUse = &ready
+	utils.SetVolumeGroupSnapshotContentConditions(groupSnapshotContentClone)
+	patches = append(patches, utils.PatchOp{
+		Op:    "add",
+		Path:  "/status/conditions",
+		Value: groupSnapshotContentClone.Status.Conditions,
+	})
 
 	newContent, err := utils.PatchVolumeGroupSnapshotContent(groupSnapshotContent, patches, ctrl.clientset, "status")
 
@@ -732,7 +766,7 @@ func (ctrl *csiSnapshotSideCarController) checkandUpdateGroupSnapshotContentStat
 	klog.V(5).Infof("checkandUpdateGroupSnapshotContentStatus[%s] started", groupSnapshotContent.Name)
 	groupSnapshotContentObj, err := ctrl.checkandUpdateGroupSnapshotContentStatusOperation(groupSnapshotContent)
 	if err != nil {
-		ctrl.updateGroupSnapshotContentErrorStatusWithEvent(groupSnapshotContentObj, v1.EventTypeWarning, "GroupSnapshotContentCheckandUpdateFailed", fmt.Sprintf("Failed to check and update group snapshot content: %v", err))
+		ctrl.updateGroupSnapshotContentErrorStatusWithEvent(groupSnapshotContentObj, v1.EventTypeWarning, "GroupSnapshotContentCheckandUpdateFailed", fmt.Sprintf("Failed to check and update group snapshot content: %v", err), err)
 		klog.Errorf("checkandUpdateGroupSnapshotContentStatus [%s]: error occurred %v", groupSnapshotContent.Name, err)
 		return err
 	}
@@ -760,20 +794,20 @@ func (ctrl *csiSnapshotSideCarController) checkandUpdateGroupSnapshotContentStat
 			class, err := ctrl.getGroupSnapshotClass(*groupSnapshotContent.Spec.VolumeGroupSnapshotClassName)
 			if err != nil {
 				klog.Errorf("Failed to get group snapshot class %s for group snapshot content %s: %v", *groupSnapshotContent.Spec.VolumeGroupSnapshotClassName, groupSnapshotContent.Name, err)
-				return groupSnapshotContent, fmt.Errorf("failed to get group snapshot class %s for group snapshot content %s: %v", *groupSnapshotContent.Spec.VolumeGroupSnapshotClassName, groupSnapshotContent.Name, err)
+				return groupSnapshotContent, utils.NewStatusError(crdv1.VolumeSnapshotErrorReasonClassError, fmt.Errorf("failed to get group snapshot class %s for group snapshot content %s: %v", *groupSnapshotContent.Spec.VolumeGroupSnapshotClassName, groupSnapshotContent.Name, err))
 			}
 
-			groupSnapshotSecretRef, err := utils.GetGroupSnapshotSecretReference(utils.GroupSnapshotterGetSecretParams, class.Parameters, groupSnapshotContent.GetObjectMeta().GetName(), nil)
+			groupSnapshotCredentialsRef, err := utils.GetGroupSnapshotCredentialsReference(utils.GroupSnapshotterGetSecretParams, class.Parameters, groupSnapshotContent.GetObjectMeta().GetName(), nil)
 			if err != nil {
 				klog.Errorf("Failed to get secret reference for group snapshot content %s: %v", groupSnapshotContent.Name, err)
-				return groupSnapshotContent, fmt.Errorf("failed to get secret reference for group snapshot content %s: %v", groupSnapshotContent.Name, err)
+				return groupSnapshotContent, utils.NewStatusError(crdv1.VolumeSnapshotErrorReasonSecretError, fmt.Errorf("failed to get secret reference for group snapshot content %s: %v", groupSnapshotContent.Name, err))
 			}
 
-			groupSnapshotCredentials, err = utils.GetCredentials(ctrl.client, groupSnapshotSecretRef)
+			groupSnapshotCredentials, err = ctrl.credentials.GetCredentials(context.TODO(), groupSnapshotCredentialsRef)
 			if err != nil {
 				// Continue with deletion, as the secret may have already been deleted.
 				klog.Errorf("Failed to get credentials for group snapshot content %s: %v", groupSnapshotContent.Name, err)
-				return groupSnapshotContent, fmt.Errorf("failed to get credentials for group snapshot content %s: %v", groupSnapshotContent.Name, err)
+				return groupSnapshotContent, utils.NewStatusError(crdv1.VolumeSnapshotErrorReasonSecretError, fmt.Errorf("failed to get credentials for group snapshot content %s: %v", groupSnapshotContent.Name, err))
 			}
 		}
 
@@ -795,7 +829,7 @@ func (ctrl *csiSnapshotSideCarController) checkandUpdateGroupSnapshotContentStat
 		// TODO: Get a reference to snapshot contents for this volume group snapshot
 		updatedContent, err := ctrl.updateGroupSnapshotContentStatus(groupSnapshotContent, groupSnapshotID, readyToUse, metav1.NewTime(creationTime), []snapshotContentNameVolumeHandlePair{})
 		if err != nil {
-			return groupSnapshotContent, err
+			return groupSnapshotContent, utils.NewStatusError(crdv1.VolumeSnapshotErrorReasonAPIServerError, err)
 		}
 		return updatedContent, nil
 	}
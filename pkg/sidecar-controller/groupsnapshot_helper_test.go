@@ -2,10 +2,13 @@ package sidecar_controller
 
 import (
 	"testing"
+	"time"
 
 	crdv1beta1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumegroupsnapshot/v1beta1"
+	"github.com/kubernetes-csi/external-snapshotter/client/v8/clientset/versioned/fake"
 
 	v1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/tools/record"
 )
@@ -51,3 +54,21 @@ func TestDeleteCSIGroupSnapshotOperation(t *testing.T) {
 		t.Errorf("expected deleteCSIGroupSnapshotOperation to return error when groupsnapshotContent is empty: %v", err)
 	}
 }
+
+func TestUpdateGroupSnapshotContentStatusEmulated(t *testing.T) {
+	for _, emulated := range []bool{false, true} {
+		content := &crdv1beta1.VolumeGroupSnapshotContent{ObjectMeta: metav1.ObjectMeta{Name: "groupsnapcontent-1"}}
+		ctrl := &csiSnapshotSideCarController{
+			clientset:              fake.NewSimpleClientset(content),
+			emulatedGroupSnapshots: emulated,
+		}
+		pairs := []snapshotContentNameVolumeHandlePair{{snapshotHandle: "snapshot-1", volumeHandle: "volume-1"}}
+		newContent, err := ctrl.updateGroupSnapshotContentStatus(content, "groupsnapshot-1", true, metav1.NewTime(time.Now()), pairs)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := newContent.Status.Emulated != nil && *newContent.Status.Emulated; got != emulated {
+			t.Errorf("expected emulated %v, got %v", emulated, newContent.Status.Emulated)
+		}
+	}
+}
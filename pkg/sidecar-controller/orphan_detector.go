@@ -0,0 +1,389 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecar_controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	k8smetrics "k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	klog "k8s.io/klog/v2"
+
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	clientset "github.com/kubernetes-csi/external-snapshotter/client/v8/clientset/versioned"
+	snapshotinformers "github.com/kubernetes-csi/external-snapshotter/client/v8/informers/externalversions/volumesnapshot/v1"
+	snapshotlisters "github.com/kubernetes-csi/external-snapshotter/client/v8/listers/volumesnapshot/v1"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/snapshotter"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+)
+
+// Design:
+//
+// A snapshot leaks on the storage backend when its VolumeSnapshotContent is
+// removed without the sidecar deleting it, e.g. when the finalizer of the
+// content is removed by hand, or when the sidecar crashes between
+// CreateSnapshot and the update of the content status. The orphan detector
+// periodically pages through ListSnapshots of the driver and compares the
+// returned snapshot IDs with the snapshot handles of the contents of the
+// driver. Snapshots that are not referenced by any content are reported as
+// orphans through metrics, logs and an event on the CSIDriver object.
+//
+// A content that is being created has no snapshot handle yet, so backend
+// snapshots of its source volume are never reported while it is in flight.
+// Members of a group snapshot are skipped, because they have to be deleted
+// together with their group.
+//
+// When enabled, orphans older than a minimum age are deleted with
+// DeleteSnapshot. The snapshot handles are listed again from the API server
+// right before the deletion, so that a stale informer cache cannot cause
+// the deletion of a snapshot that is in use. Snapshots retained by a content
+// with the Retain deletion policy that was deleted afterwards are orphans too,
+// so the deletion must only be enabled when no backend snapshot is expected
+// to outlive its content.
+
+const (
+	// maxReportedOrphans is the maximum number of snapshot IDs listed in
+	// the event reporting the orphans of a pass.
+	maxReportedOrphans = 10
+
+	orphanedSnapshotsName             = "orphaned_snapshots"
+	orphanedSnapshotsHelpMsg          = "Number of snapshots on the storage backend that are not referenced by any VolumeSnapshotContent, as of the last orphan detection pass"
+	orphanedSnapshotsDeletedName      = "orphaned_snapshots_deleted_total"
+	orphanedSnapshotsDeletedHelpMsg   = "Number of orphaned snapshots deleted from the storage backend"
+	orphanDetectionPassesName         = "orphan_detection_passes_total"
+	orphanDetectionPassesHelpMsg      = "Number of orphan detection passes, by result"
+	orphanedSnapshotsMetricsSubsystem = "csi_snapshotter"
+)
+
+var (
+	orphanedSnapshots = k8smetrics.NewGauge(&k8smetrics.GaugeOpts{
+		Subsystem:      orphanedSnapshotsMetricsSubsystem,
+		Name:           orphanedSnapshotsName,
+		Help:           orphanedSnapshotsHelpMsg,
+		StabilityLevel: k8smetrics.ALPHA,
+	})
+	orphanedSnapshotsDeleted = k8smetrics.NewCounter(&k8smetrics.CounterOpts{
+		Subsystem:      orphanedSnapshotsMetricsSubsystem,
+		Name:           orphanedSnapshotsDeletedName,
+		Help:           orphanedSnapshotsDeletedHelpMsg,
+		StabilityLevel: k8smetrics.ALPHA,
+	})
+	orphanDetectionPasses = k8smetrics.NewCounterVec(&k8smetrics.CounterOpts{
+		Subsystem:      orphanedSnapshotsMetricsSubsystem,
+		Name:           orphanDetectionPassesName,
+		Help:           orphanDetectionPassesHelpMsg,
+		StabilityLevel: k8smetrics.ALPHA,
+	}, []string{"result"})
+
+	registerOrphanMetrics sync.Once
+)
+
+// OrphanDetectorOptions configures an orphan detector.
+type OrphanDetectorOptions struct {
+	// Interval between two detection passes.
+	Interval time.Duration
+	// PageSize is the maximum number of entries requested per ListSnapshots
+	// call. Zero lets the driver choose.
+	PageSize int32
+	// Timeout of every CSI call.
+	Timeout time.Duration
+	// Delete enables the deletion of orphans older than MinAge.
+	Delete bool
+	// MinAge is the minimum age of an orphan before it is deleted.
+	MinAge time.Duration
+	// Secret is the secret passed to ListSnapshots and DeleteSnapshot, if any.
+	Secret *v1.SecretReference
+}
+
+// OrphanReport is the outcome of a detection pass.
+type OrphanReport struct {
+	// Orphans are the snapshots that are not referenced by any content.
+	Orphans []*csi.Snapshot
+	// Deleted are the IDs of the orphans deleted in this pass.
+	Deleted []string
+}
+
+type orphanDetector struct {
+	clientset     clientset.Interface
+	client        kubernetes.Interface
+	driverName    string
+	snapshotter   snapshotter.Snapshotter
+	eventRecorder record.EventRecorder
+	options       OrphanDetectorOptions
+
+	contentLister       snapshotlisters.VolumeSnapshotContentLister
+	contentListerSynced cache.InformerSynced
+
+	now func() time.Time
+}
+
+// NewOrphanDetector returns a detector of the snapshots of the driver that are
+// not referenced by any VolumeSnapshotContent.
+func NewOrphanDetector(
+	clientset clientset.Interface,
+	client kubernetes.Interface,
+	driverName string,
+	volumeSnapshotContentInformer snapshotinformers.VolumeSnapshotContentInformer,
+	snapshotter snapshotter.Snapshotter,
+	options OrphanDetectorOptions,
+) *orphanDetector {
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartLogging(klog.Infof)
+	broadcaster.StartRecordingToSink(&corev1.EventSinkImpl{Interface: client.CoreV1().Events(v1.NamespaceAll)})
+	eventRecorder := broadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: fmt.Sprintf("csi-snapshotter %s", driverName)})
+
+	registerOrphanMetrics.Do(func() {
+		legacyregistry.MustRegister(orphanedSnapshots, orphanedSnapshotsDeleted, orphanDetectionPasses)
+	})
+
+	return &orphanDetector{
+		clientset:           clientset,
+		client:              client,
+		driverName:          driverName,
+		snapshotter:         snapshotter,
+		eventRecorder:       eventRecorder,
+		options:             options,
+		contentLister:       volumeSnapshotContentInformer.Lister(),
+		contentListerSynced: volumeSnapshotContentInformer.Informer().HasSynced,
+		now:                 time.Now,
+	}
+}
+
+// Run runs a detection pass every interval until stopCh is closed.
+func (d *orphanDetector) Run(stopCh <-chan struct{}) {
+	klog.Infof("Starting orphaned snapshot detector")
+	defer klog.Infof("Shutting down orphaned snapshot detector")
+
+	if !cache.WaitForCacheSync(stopCh, d.contentListerSynced) {
+		klog.Errorf("Cannot sync caches")
+		return
+	}
+
+	wait.Until(func() {
+		if _, err := d.detect(); err != nil {
+			orphanDetectionPasses.WithLabelValues("error").Inc()
+			klog.Errorf("orphaned snapshot detection failed: %v", err)
+			return
+		}
+		orphanDetectionPasses.WithLabelValues("success").Inc()
+	}, d.options.Interval, stopCh)
+}
+
+// detect runs a single detection pass.
+func (d *orphanDetector) detect() (*OrphanReport, error) {
+	credentials, err := d.credentials()
+	if err != nil {
+		return nil, err
+	}
+
+	// The contents are listed before the backend snapshots, so that a
+	// snapshot created in between is either found through its content or
+	// through the volume of a content in flight.
+	contents, err := d.contentLister.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list VolumeSnapshotContents: %v", err)
+	}
+	known, inFlight := knownSnapshots(d.driverName, contents)
+
+	snapshots, err := d.listSnapshots(credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &OrphanReport{}
+	for _, snapshot := range snapshots {
+		if isOrphan(snapshot, known, inFlight) {
+			report.Orphans = append(report.Orphans, snapshot)
+		}
+	}
+	sort.Slice(report.Orphans, func(i, j int) bool {
+		return report.Orphans[i].SnapshotId < report.Orphans[j].SnapshotId
+	})
+
+	orphanedSnapshots.Set(float64(len(report.Orphans)))
+	klog.V(4).Infof("orphaned snapshot detection: %d snapshots on the backend, %d orphans", len(snapshots), len(report.Orphans))
+	if len(report.Orphans) == 0 {
+		return report, nil
+	}
+	for _, orphan := range report.Orphans {
+		klog.Warningf("snapshot %s of volume %s is not referenced by any VolumeSnapshotContent", orphan.SnapshotId, orphan.SourceVolumeId)
+	}
+	d.eventRecorder.Event(d.driverReference(), v1.EventTypeWarning, "OrphanedSnapshotsDetected", orphansMessage(report.Orphans))
+
+	if d.options.Delete {
+		report.Deleted, err = d.deleteOrphans(report.Orphans, credentials)
+	}
+	return report, err
+}
+
+// knownSnapshots returns the snapshot handles referenced by the contents of
+// the driver, and the source volumes of the contents that have no handle yet.
+func knownSnapshots(driverName string, contents []*crdv1.VolumeSnapshotContent) (map[string]bool, map[string]bool) {
+	known := map[string]bool{}
+	inFlight := map[string]bool{}
+	for _, content := range contents {
+		if content.Spec.Driver != driverName {
+			continue
+		}
+		if content.Spec.Source.SnapshotHandle != nil {
+			known[*content.Spec.Source.SnapshotHandle] = true
+		}
+		if content.Status != nil && content.Status.SnapshotHandle != nil {
+			known[*content.Status.SnapshotHandle] = true
+			continue
+		}
+		if content.Spec.Source.VolumeHandle != nil {
+			inFlight[*content.Spec.Source.VolumeHandle] = true
+		}
+	}
+	return known, inFlight
+}
+
+func isOrphan(snapshot *csi.Snapshot, known, inFlight map[string]bool) bool {
+	if known[snapshot.SnapshotId] {
+		return false
+	}
+	if snapshot.GroupSnapshotId != "" {
+		klog.V(5).Infof("skipping snapshot %s, it is a member of group snapshot %s", snapshot.SnapshotId, snapshot.GroupSnapshotId)
+		return false
+	}
+	if inFlight[snapshot.SourceVolumeId] {
+		klog.V(5).Infof("skipping snapshot %s, a snapshot of volume %s is being created", snapshot.SnapshotId, snapshot.SourceVolumeId)
+		return false
+	}
+	return true
+}
+
+// listSnapshots pages through ListSnapshots.
+func (d *orphanDetector) listSnapshots(credentials map[string]string) ([]*csi.Snapshot, error) {
+	var snapshots []*csi.Snapshot
+	token := ""
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), d.options.Timeout)
+		page, next, err := d.snapshotter.ListSnapshots(ctx, token, d.options.PageSize, credentials)
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list snapshots of driver %s: %v", d.driverName, err)
+		}
+		snapshots = append(snapshots, page...)
+		if next == "" {
+			return snapshots, nil
+		}
+		if next == token {
+			return nil, fmt.Errorf("driver %s returned the same ListSnapshots token %q twice", d.driverName, next)
+		}
+		token = next
+	}
+}
+
+// deleteOrphans deletes the orphans that are older than the minimum age and
+// are still not referenced by any content on the API server.
+func (d *orphanDetector) deleteOrphans(orphans []*csi.Snapshot, credentials map[string]string) ([]string, error) {
+	var candidates []*csi.Snapshot
+	for _, orphan := range orphans {
+		if orphan.CreationTime == nil {
+			klog.V(4).Infof("not deleting orphaned snapshot %s, its creation time is unknown", orphan.SnapshotId)
+			continue
+		}
+		if d.now().Sub(orphan.CreationTime.AsTime()) < d.options.MinAge {
+			klog.V(4).Infof("not deleting orphaned snapshot %s, it is younger than %s", orphan.SnapshotId, d.options.MinAge)
+			continue
+		}
+		candidates = append(candidates, orphan)
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	list, err := d.clientset.SnapshotV1().VolumeSnapshotContents().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list VolumeSnapshotContents before deleting orphaned snapshots: %v", err)
+	}
+	contents := make([]*crdv1.VolumeSnapshotContent, 0, len(list.Items))
+	for i := range list.Items {
+		contents = append(contents, &list.Items[i])
+	}
+	known, inFlight := knownSnapshots(d.driverName, contents)
+
+	var deleted []string
+	var errs []string
+	for _, orphan := range candidates {
+		if !isOrphan(orphan, known, inFlight) {
+			klog.V(4).Infof("not deleting snapshot %s, it is referenced by a VolumeSnapshotContent", orphan.SnapshotId)
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), d.options.Timeout)
+		err := d.snapshotter.DeleteSnapshot(ctx, orphan.SnapshotId, credentials)
+		cancel()
+		if err != nil {
+			d.eventRecorder.Event(d.driverReference(), v1.EventTypeWarning, "OrphanedSnapshotDeleteFailed", fmt.Sprintf("Failed to delete orphaned snapshot %s: %v", orphan.SnapshotId, err))
+			errs = append(errs, fmt.Sprintf("%s: %v", orphan.SnapshotId, err))
+			continue
+		}
+		klog.Infof("deleted orphaned snapshot %s of volume %s", orphan.SnapshotId, orphan.SourceVolumeId)
+		d.eventRecorder.Event(d.driverReference(), v1.EventTypeNormal, "OrphanedSnapshotDeleted", fmt.Sprintf("Deleted orphaned snapshot %s of volume %s", orphan.SnapshotId, orphan.SourceVolumeId))
+		orphanedSnapshotsDeleted.Inc()
+		deleted = append(deleted, orphan.SnapshotId)
+	}
+	if len(errs) > 0 {
+		return deleted, fmt.Errorf("failed to delete orphaned snapshots: %s", strings.Join(errs, "; "))
+	}
+	return deleted, nil
+}
+
+func (d *orphanDetector) credentials() (map[string]string, error) {
+	if d.options.Secret == nil {
+		return nil, nil
+	}
+	return utils.GetCredentials(d.client, d.options.Secret)
+}
+
+// driverReference returns the CSIDriver object the events are reported on.
+func (d *orphanDetector) driverReference() *v1.ObjectReference {
+	return &v1.ObjectReference{
+		Kind:       "CSIDriver",
+		APIVersion: "storage.k8s.io/v1",
+		Name:       d.driverName,
+	}
+}
+
+func orphansMessage(orphans []*csi.Snapshot) string {
+	ids := []string{}
+	for i, orphan := range orphans {
+		if i == maxReportedOrphans {
+			ids = append(ids, "...")
+			break
+		}
+		ids = append(ids, orphan.SnapshotId)
+	}
+	return fmt.Sprintf("Found %d snapshots not referenced by any VolumeSnapshotContent: %s", len(orphans), strings.Join(ids, ", "))
+}
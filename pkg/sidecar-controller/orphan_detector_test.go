@@ -0,0 +1,246 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecar_controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	"github.com/kubernetes-csi/external-snapshotter/client/v8/clientset/versioned/fake"
+	informers "github.com/kubernetes-csi/external-snapshotter/client/v8/informers/externalversions"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+)
+
+// orphanSnapshotter serves ListSnapshots from a fixed list of snapshots in
+// pages of pageSize entries and records the DeleteSnapshot calls.
+type orphanSnapshotter struct {
+	fakeSnapshotter
+	snapshots []*csi.Snapshot
+	pageSize  int
+	listErr   error
+	deleted   []string
+}
+
+func (f *orphanSnapshotter) ListSnapshots(ctx context.Context, startingToken string, maxEntries int32, snapshotterListCredentials map[string]string) ([]*csi.Snapshot, string, error) {
+	if f.listErr != nil {
+		return nil, "", f.listErr
+	}
+	start := 0
+	if startingToken != "" {
+		fmt.Sscanf(startingToken, "%d", &start)
+	}
+	end := start + f.pageSize
+	if end >= len(f.snapshots) {
+		return f.snapshots[start:], "", nil
+	}
+	return f.snapshots[start:end], fmt.Sprintf("%d", end), nil
+}
+
+func (f *orphanSnapshotter) DeleteSnapshot(ctx context.Context, snapshotID string, snapshotterCredentials map[string]string) error {
+	f.deleted = append(f.deleted, snapshotID)
+	return nil
+}
+
+func newBackendSnapshot(id, volumeID string, created time.Time) *csi.Snapshot {
+	return &csi.Snapshot{
+		SnapshotId:     id,
+		SourceVolumeId: volumeID,
+		CreationTime:   timestamppb.New(created),
+		ReadyToUse:     true,
+	}
+}
+
+func withGroupSnapshotID(snapshot *csi.Snapshot, groupID string) *csi.Snapshot {
+	snapshot.GroupSnapshotId = groupID
+	return snapshot
+}
+
+func TestOrphanDetector(t *testing.T) {
+	now := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	old := now.Add(-48 * time.Hour)
+	recent := now.Add(-time.Hour)
+
+	// A dynamically provisioned content, a pre-provisioned content and a
+	// content of another driver.
+	dynamic := newContent("content8-1", "snapuid8-1", "snap8-1", "sid8-1", classGold, "", "volume8-1", deletionPolicy, nil, nil, true, nil)
+	preProvisioned := newContent("content8-2", "snapuid8-2", "snap8-2", "", classGold, "sid8-2", "", deletionPolicy, nil, nil, true, nil)
+	preProvisioned.Status = nil
+	otherDriver := newContent("content8-3", "snapuid8-3", "snap8-3", "sid8-3", classGold, "", "volume8-3", deletionPolicy, nil, nil, true, nil)
+	otherDriver.Spec.Driver = "other.csi.k8s.io"
+	// A content whose snapshot is being created.
+	inFlight := newContent("content8-4", "snapuid8-4", "snap8-4", "", classGold, "", "volume8-4", deletionPolicy, nil, nil, true, nil)
+
+	tests := []struct {
+		name            string
+		contents        []*crdv1.VolumeSnapshotContent
+		apiContents     []*crdv1.VolumeSnapshotContent
+		snapshots       []*csi.Snapshot
+		delete          bool
+		listErr         error
+		expectedOrphans []string
+		expectedDeleted []string
+		expectedEvents  []string
+		expectErr       bool
+	}{
+		{
+			name:     "8-1 - no orphans",
+			contents: []*crdv1.VolumeSnapshotContent{dynamic, preProvisioned},
+			snapshots: []*csi.Snapshot{
+				newBackendSnapshot("sid8-1", "volume8-1", old),
+				newBackendSnapshot("sid8-2", "volume8-2", old),
+			},
+		},
+		{
+			name:     "8-2 - orphans are reported across pages",
+			contents: []*crdv1.VolumeSnapshotContent{dynamic, preProvisioned, otherDriver},
+			snapshots: []*csi.Snapshot{
+				newBackendSnapshot("sid8-1", "volume8-1", old),
+				newBackendSnapshot("sid8-2", "volume8-2", old),
+				newBackendSnapshot("sid8-3", "volume8-3", old),
+				newBackendSnapshot("leaked-1", "volume8-1", old),
+				newBackendSnapshot("leaked-2", "volume8-9", recent),
+			},
+			expectedOrphans: []string{"leaked-1", "leaked-2", "sid8-3"},
+			expectedEvents:  []string{"Warning OrphanedSnapshotsDetected Found 3 snapshots not referenced by any VolumeSnapshotContent: leaked-1, leaked-2, sid8-3"},
+		},
+		{
+			name:     "8-3 - snapshots of contents in flight and group members are not orphans",
+			contents: []*crdv1.VolumeSnapshotContent{inFlight},
+			snapshots: []*csi.Snapshot{
+				newBackendSnapshot("sid8-4", "volume8-4", old),
+				withGroupSnapshotID(newBackendSnapshot("member", "volume8-9", old), "group"),
+			},
+		},
+		{
+			name:        "8-4 - only old orphans are deleted",
+			contents:    []*crdv1.VolumeSnapshotContent{dynamic},
+			apiContents: []*crdv1.VolumeSnapshotContent{dynamic},
+			snapshots: []*csi.Snapshot{
+				newBackendSnapshot("sid8-1", "volume8-1", old),
+				newBackendSnapshot("leaked-1", "volume8-1", old),
+				newBackendSnapshot("leaked-2", "volume8-9", recent),
+			},
+			delete:          true,
+			expectedOrphans: []string{"leaked-1", "leaked-2"},
+			expectedDeleted: []string{"leaked-1"},
+			expectedEvents: []string{
+				"Warning OrphanedSnapshotsDetected Found 2 snapshots not referenced by any VolumeSnapshotContent: leaked-1, leaked-2",
+				"Normal OrphanedSnapshotDeleted Deleted orphaned snapshot leaked-1 of volume volume8-1",
+			},
+		},
+		{
+			name:     "8-5 - orphans referenced by a content missing from the cache are not deleted",
+			contents: []*crdv1.VolumeSnapshotContent{},
+			apiContents: []*crdv1.VolumeSnapshotContent{
+				newContent("content8-5", "snapuid8-5", "snap8-5", "leaked-1", classGold, "", "volume8-5", deletionPolicy, nil, nil, true, nil),
+			},
+			snapshots:       []*csi.Snapshot{newBackendSnapshot("leaked-1", "volume8-5", old)},
+			delete:          true,
+			expectedOrphans: []string{"leaked-1"},
+			expectedEvents:  []string{"Warning OrphanedSnapshotsDetected Found 1 snapshots not referenced by any VolumeSnapshotContent: leaked-1"},
+		},
+		{
+			name:      "8-6 - ListSnapshots error",
+			contents:  []*crdv1.VolumeSnapshotContent{dynamic},
+			listErr:   fmt.Errorf("mock error"),
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			objs := []runtime.Object{}
+			for _, content := range test.apiContents {
+				objs = append(objs, content)
+			}
+			client := fake.NewSimpleClientset(objs...)
+			kubeClient := kubefake.NewSimpleClientset()
+			factory := informers.NewSharedInformerFactory(client, 0)
+			contentInformer := factory.Snapshot().V1().VolumeSnapshotContents()
+			for _, content := range test.contents {
+				contentInformer.Informer().GetIndexer().Add(content)
+			}
+
+			backend := &orphanSnapshotter{
+				fakeSnapshotter: fakeSnapshotter{t: t},
+				snapshots:       test.snapshots,
+				pageSize:        2,
+				listErr:         test.listErr,
+			}
+			detector := NewOrphanDetector(client, kubeClient, mockDriverName, contentInformer, backend, OrphanDetectorOptions{
+				Interval: time.Hour,
+				PageSize: 2,
+				Timeout:  time.Minute,
+				Delete:   test.delete,
+				MinAge:   24 * time.Hour,
+			})
+			fakeRecorder := record.NewFakeRecorder(100)
+			detector.eventRecorder = fakeRecorder
+			detector.now = func() time.Time { return now }
+
+			report, err := detector.detect()
+			if test.expectErr {
+				if err == nil {
+					t.Errorf("expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			orphans := []string{}
+			for _, orphan := range report.Orphans {
+				orphans = append(orphans, orphan.SnapshotId)
+			}
+			if !reflect.DeepEqual(orphans, nonNil(test.expectedOrphans)) {
+				t.Errorf("expected orphans %v, got %v", test.expectedOrphans, orphans)
+			}
+			if !reflect.DeepEqual(nonNil(report.Deleted), nonNil(test.expectedDeleted)) {
+				t.Errorf("expected deleted snapshots %v, got %v", test.expectedDeleted, report.Deleted)
+			}
+			if !reflect.DeepEqual(nonNil(backend.deleted), nonNil(test.expectedDeleted)) {
+				t.Errorf("expected DeleteSnapshot calls for %v, got %v", test.expectedDeleted, backend.deleted)
+			}
+
+			close(fakeRecorder.Events)
+			events := []string{}
+			for event := range fakeRecorder.Events {
+				events = append(events, event)
+			}
+			if strings.Join(events, "\n") != strings.Join(test.expectedEvents, "\n") {
+				t.Errorf("expected events %v, got %v", test.expectedEvents, events)
+			}
+		})
+	}
+}
+
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,350 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecar_controller
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/tools/cache"
+	k8smetrics "k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+)
+
+// Design:
+//
+// Every CSI call of the handler waits for a slot of its RPC type before it is
+// issued. A slot is limited by a maximum number of calls in flight and by a
+// token bucket, both per RPC type. The limits of the driver are set with
+// command line flags. A VolumeSnapshotClass or a VolumeGroupSnapshotClass can
+// limit the calls made for its contents further with parameters, in which
+// case a call waits for a slot of its class first and then for a slot of the
+// driver. The limiter of a class is dropped when the class is deleted or its
+// parameters change, and rebuilt by the next call.
+//
+// A call waits for its slots for as long as the controller runs, and its
+// timeout only starts once it is issued, so that calls queued behind slow
+// ones do not expire before reaching the driver. The time spent waiting is
+// reported in the rpc_queue_wait_seconds metric.
+// ListSnapshots calls made to fill the snapshot status cache are shared by all
+// classes, so they are only subject to the limits of the driver.
+
+const (
+	rpcQueueWaitName             = "rpc_queue_wait_seconds"
+	rpcQueueWaitHelpMsg          = "Time spent by CSI calls waiting for the concurrency and rate limits of their RPC type, by RPC type"
+	rpcLimiterMetricsSubsystem   = "csi_snapshotter"
+	rpcLimiterMetricsRPCLabel    = "rpc"
+	rpcLimitsParameterSeparator  = ","
+	rpcLimitsParameterAssignment = "="
+)
+
+var (
+	rpcQueueWait = k8smetrics.NewHistogramVec(&k8smetrics.HistogramOpts{
+		Subsystem:      rpcLimiterMetricsSubsystem,
+		Name:           rpcQueueWaitName,
+		Help:           rpcQueueWaitHelpMsg,
+		Buckets:        []float64{0.001, 0.01, 0.1, 0.5, 1, 5, 10, 30, 60, 300},
+		StabilityLevel: k8smetrics.ALPHA,
+	}, []string{rpcLimiterMetricsRPCLabel})
+
+	registerRPCLimiterMetrics sync.Once
+)
+
+// RPCLimit limits the CSI calls of one RPC type. Zero values mean no limit.
+type RPCLimit struct {
+	// MaxInFlight is the maximum number of concurrent calls.
+	MaxInFlight int
+	// QPS is the rate at which the token bucket of the calls is refilled.
+	QPS float64
+	// Burst is the size of the token bucket. It defaults to QPS, rounded up.
+	Burst int
+}
+
+// RPCLimits are the limits of the CSI calls, by RPC type.
+type RPCLimits map[string]RPCLimit
+
+// ParseRPCLimits returns the limits set by parameters, whose keys are made of
+// an RPC type followed by a limit suffix, e.g. "create-snapshot-qps". RPC
+// types without parameters get the defaults.
+func ParseRPCLimits(defaults RPCLimit, parameters map[string]string) (RPCLimits, error) {
+	limits := RPCLimits{}
+	for _, rpcType := range utils.RPCLimitTypes {
+		limits[rpcType] = defaults
+	}
+	for key, value := range parameters {
+		rpcType, suffix, ok := utils.ParseRPCLimitKey(key)
+		if !ok {
+			return nil, fmt.Errorf("unknown RPC limit %q", key)
+		}
+		limit := limits[rpcType]
+		switch suffix {
+		case utils.RPCLimitMaxInFlightSuffix:
+			maxInFlight, err := strconv.Atoi(value)
+			if err != nil || maxInFlight < 0 {
+				return nil, fmt.Errorf("invalid value %q of RPC limit %q: must be a non-negative integer", value, key)
+			}
+			limit.MaxInFlight = maxInFlight
+		case utils.RPCLimitQPSSuffix:
+			qps, err := strconv.ParseFloat(value, 64)
+			if err != nil || qps < 0 || math.IsInf(qps, 0) || math.IsNaN(qps) {
+				return nil, fmt.Errorf("invalid value %q of RPC limit %q: must be a non-negative number", value, key)
+			}
+			limit.QPS = qps
+		case utils.RPCLimitBurstSuffix:
+			burst, err := strconv.Atoi(value)
+			if err != nil || burst < 0 {
+				return nil, fmt.Errorf("invalid value %q of RPC limit %q: must be a non-negative integer", value, key)
+			}
+			limit.Burst = burst
+		}
+		limits[rpcType] = limit
+	}
+	return limits, nil
+}
+
+// ParseRPCLimitsFlag returns the limits set by a comma separated list of
+// key=value pairs, e.g. "create-snapshot-qps=2,create-snapshot-burst=4". RPC
+// types without a limit in the list get the defaults.
+func ParseRPCLimitsFlag(defaults RPCLimit, flag string) (RPCLimits, error) {
+	parameters := map[string]string{}
+	for _, pair := range strings.Split(flag, rpcLimitsParameterSeparator) {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, value, found := strings.Cut(pair, rpcLimitsParameterAssignment)
+		if !found {
+			return nil, fmt.Errorf("invalid RPC limit %q: must be a key=value pair", pair)
+		}
+		parameters[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return ParseRPCLimits(defaults, parameters)
+}
+
+// rpcLimiter enforces RPCLimits.
+type rpcLimiter struct {
+	slots   map[string]chan struct{}
+	buckets map[string]*rate.Limiter
+}
+
+func newRPCLimiter(limits RPCLimits) *rpcLimiter {
+	limiter := &rpcLimiter{
+		slots:   map[string]chan struct{}{},
+		buckets: map[string]*rate.Limiter{},
+	}
+	for rpcType, limit := range limits {
+		if limit.MaxInFlight > 0 {
+			limiter.slots[rpcType] = make(chan struct{}, limit.MaxInFlight)
+		}
+		if limit.QPS > 0 {
+			burst := limit.Burst
+			if burst == 0 {
+				burst = int(math.Ceil(limit.QPS))
+			}
+			limiter.buckets[rpcType] = rate.NewLimiter(rate.Limit(limit.QPS), burst)
+		}
+	}
+	return limiter
+}
+
+// wait blocks until a call of the RPC type may be issued and returns the
+// function releasing the slot of the call.
+func (l *rpcLimiter) wait(ctx context.Context, rpcType string) (func(), error) {
+	release := func() {}
+	if slots, ok := l.slots[rpcType]; ok {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		release = func() { <-slots }
+	}
+	if bucket, ok := l.buckets[rpcType]; ok {
+		if err := bucket.Wait(ctx); err != nil {
+			release()
+			return nil, err
+		}
+	}
+	return release, nil
+}
+
+// classRPCLimiter is the limiter of a class, along with the parameters it was
+// built from.
+type classRPCLimiter struct {
+	parameters string
+	limiter    *rpcLimiter
+}
+
+// rpcLimiters holds the limiter of the driver and the limiters of the classes
+// with RPC limits.
+type rpcLimiters struct {
+	driver *rpcLimiter
+
+	// ctx is done once the controller stops, which ends the waits.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mutex   sync.Mutex
+	classes map[string]*classRPCLimiter
+}
+
+func newRPCLimiters(limits RPCLimits) *rpcLimiters {
+	registerRPCLimiterMetrics.Do(func() {
+		legacyregistry.MustRegister(rpcQueueWait)
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	return &rpcLimiters{
+		driver:  newRPCLimiter(limits),
+		ctx:     ctx,
+		cancel:  cancel,
+		classes: map[string]*classRPCLimiter{},
+	}
+}
+
+// stop ends the waits in progress and makes the next ones fail.
+func (l *rpcLimiters) stop() {
+	if l != nil {
+		l.cancel()
+	}
+}
+
+// wait blocks until a call of the RPC type may be issued under the limits of
+// the driver and the limits set by classParameters, the parameters of the
+// class identified by classKey, if any, until ctx is done or the limiters are
+// stopped. It returns the function releasing the slots of the call.
+func (l *rpcLimiters) wait(ctx context.Context, rpcType string, classKey string, classParameters map[string]string) (func(), error) {
+	start := time.Now()
+	defer func() {
+		rpcQueueWait.WithLabelValues(rpcType).Observe(time.Since(start).Seconds())
+	}()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	defer context.AfterFunc(l.ctx, cancel)()
+
+	classLimiter, err := l.classLimiter(classKey, classParameters)
+	if err != nil {
+		return nil, err
+	}
+	releaseClass := func() {}
+	if classLimiter != nil {
+		if releaseClass, err = classLimiter.wait(ctx, rpcType); err != nil {
+			return nil, err
+		}
+	}
+	releaseDriver, err := l.driver.wait(ctx, rpcType)
+	if err != nil {
+		releaseClass()
+		return nil, err
+	}
+	return func() {
+		releaseDriver()
+		releaseClass()
+	}, nil
+}
+
+// forgetClass drops the limiter of a class. Calls in flight release their
+// slots in the dropped limiter.
+func (l *rpcLimiters) forgetClass(classKey string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	delete(l.classes, classKey)
+}
+
+// snapshotClassLimiterKey returns the key of the limiter of a
+// VolumeSnapshotClass.
+func snapshotClassLimiterKey(className string) string {
+	return "VolumeSnapshotClass " + className
+}
+
+// groupSnapshotClassLimiterKey returns the key of the limiter of a
+// VolumeGroupSnapshotClass.
+func groupSnapshotClassLimiterKey(className string) string {
+	return "VolumeGroupSnapshotClass " + className
+}
+
+// classRPCLimitsEventHandler returns the handler of the events of a class
+// informer that drops the limiter of a class when the class is deleted or its
+// parameters change. limiterKey returns the key of the limiter of a class and
+// parameters returns the parameters of a class.
+func classRPCLimitsEventHandler(l *rpcLimiters, limiterKey func(className string) string, parameters func(obj interface{}) map[string]string) cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if reflect.DeepEqual(parameters(oldObj), parameters(newObj)) {
+				return
+			}
+			if className, err := cache.MetaNamespaceKeyFunc(newObj); err == nil {
+				l.forgetClass(limiterKey(className))
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if className, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj); err == nil {
+				l.forgetClass(limiterKey(className))
+			}
+		},
+	}
+}
+
+// classLimiter returns the limiter of a class, or nil if the class does not
+// set any RPC limit.
+func (l *rpcLimiters) classLimiter(classKey string, classParameters map[string]string) (*rpcLimiter, error) {
+	parameters := utils.GetRPCLimitParameters(classParameters)
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if len(parameters) == 0 {
+		delete(l.classes, classKey)
+		return nil, nil
+	}
+	key := rpcLimitParametersKey(parameters)
+	if class, ok := l.classes[classKey]; ok && class.parameters == key {
+		return class.limiter, nil
+	}
+	limits, err := ParseRPCLimits(RPCLimit{}, parameters)
+	if err != nil {
+		return nil, fmt.Errorf("invalid RPC limits in %s: %v", classKey, err)
+	}
+	limiter := newRPCLimiter(limits)
+	l.classes[classKey] = &classRPCLimiter{parameters: key, limiter: limiter}
+	return limiter, nil
+}
+
+// rpcLimitParametersKey returns a key identifying a set of RPC limits.
+func rpcLimitParametersKey(parameters map[string]string) string {
+	keys := make([]string, 0, len(parameters))
+	for key := range parameters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, key := range keys {
+		b.WriteString(key)
+		b.WriteString(rpcLimitsParameterAssignment)
+		b.WriteString(parameters[key])
+		b.WriteString(rpcLimitsParameterSeparator)
+	}
+	return b.String()
+}
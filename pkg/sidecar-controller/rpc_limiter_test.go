@@ -0,0 +1,328 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecar_controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	snapshotlisters "github.com/kubernetes-csi/external-snapshotter/client/v8/listers/volumesnapshot/v1"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+)
+
+// concurrentSnapshotter blocks CreateSnapshot calls until release is closed
+// and records the highest number of concurrent calls, and the calls issued
+// with an expired context.
+type concurrentSnapshotter struct {
+	fakeSnapshotter
+	release chan struct{}
+
+	mutex       sync.Mutex
+	inFlight    int
+	maxInFlight int
+	calls       int
+	expired     int
+}
+
+func (f *concurrentSnapshotter) CreateSnapshot(ctx context.Context, snapshotName string, volumeHandle string, parameters map[string]string, snapshotterCredentials map[string]string) (string, string, time.Time, int64, bool, error) {
+	f.mutex.Lock()
+	f.inFlight++
+	f.calls++
+	if ctx.Err() != nil {
+		f.expired++
+	}
+	if f.inFlight > f.maxInFlight {
+		f.maxInFlight = f.inFlight
+	}
+	f.mutex.Unlock()
+
+	<-f.release
+
+	f.mutex.Lock()
+	f.inFlight--
+	f.mutex.Unlock()
+	return mockDriverName, "sid-" + snapshotName, time.Now(), 1, true, nil
+}
+
+func (f *concurrentSnapshotter) stats() (calls, maxInFlight int) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.calls, f.maxInFlight
+}
+
+func TestParseRPCLimitsFlag(t *testing.T) {
+	defaults := RPCLimit{MaxInFlight: 4, QPS: 10}
+	limits, err := ParseRPCLimitsFlag(defaults, " create-snapshot-max-inflight=1, create-snapshot-qps=0.5,list-snapshots-burst=20,")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := RPCLimits{
+		utils.RPCCreateSnapshot:      {MaxInFlight: 1, QPS: 0.5},
+		utils.RPCDeleteSnapshot:      defaults,
+		utils.RPCListSnapshots:       {MaxInFlight: 4, QPS: 10, Burst: 20},
+		utils.RPCCreateGroupSnapshot: defaults,
+		utils.RPCDeleteGroupSnapshot: defaults,
+		utils.RPCGetGroupSnapshot:    defaults,
+	}
+	if !reflect.DeepEqual(limits, expected) {
+		t.Errorf("expected %+v, got %+v", expected, limits)
+	}
+
+	for _, flag := range []string{
+		"create-snapshot-max-inflight",
+		"create-snapshot-max-inflight=-1",
+		"create-snapshot-qps=fast",
+		"create-snapshot-burst=1.5",
+		"get-snapshot-qps=1",
+	} {
+		if _, err := ParseRPCLimitsFlag(defaults, flag); err == nil {
+			t.Errorf("expected an error for %q", flag)
+		}
+	}
+}
+
+func TestRPCLimiterQPS(t *testing.T) {
+	limiters := newRPCLimiters(RPCLimits{utils.RPCDeleteSnapshot: {QPS: 20, Burst: 1}})
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		release, err := limiters.wait(context.Background(), utils.RPCDeleteSnapshot, "", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		release()
+	}
+	// The first call takes the only token, the other ones wait 50ms each.
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("expected 5 calls at 20 QPS to take at least 150ms, took %v", elapsed)
+	}
+
+	start = time.Now()
+	for i := 0; i < 5; i++ {
+		release, err := limiters.wait(context.Background(), utils.RPCListSnapshots, "", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		release()
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("expected unlimited calls to not wait, took %v", elapsed)
+	}
+}
+
+func TestRPCLimiterMaxInFlight(t *testing.T) {
+	gold := &crdv1.VolumeSnapshotClass{
+		ObjectMeta: metav1.ObjectMeta{Name: classGold},
+		Driver:     mockDriverName,
+		Parameters: map[string]string{"csi.storage.k8s.io/create-snapshot-max-inflight": "1"},
+	}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if err := indexer.Add(gold); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	classLister := snapshotlisters.NewVolumeSnapshotClassLister(indexer)
+
+	tests := []struct {
+		name                string
+		className           string
+		expectedMaxInFlight int
+	}{
+		{
+			name:                "13-1 - calls are limited by the driver limit",
+			expectedMaxInFlight: 3,
+		},
+		{
+			name:                "13-2 - calls are limited by the limit of their class",
+			className:           classGold,
+			expectedMaxInFlight: 1,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			backend := &concurrentSnapshotter{fakeSnapshotter: fakeSnapshotter{t: t}, release: make(chan struct{})}
+			limits := RPCLimits{utils.RPCCreateSnapshot: {MaxInFlight: 3}}
+			handler := NewCSIHandler(backend, nil, time.Minute, "snapshot", -1, "groupsnapshot", -1, 0, 0, newRPCLimiters(limits), classLister, nil)
+
+			var wg sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				content := newContent(fmt.Sprintf("content13-%d", i), fmt.Sprintf("snapuid13-%d", i), "snap", "", test.className, "", "volume-handle", deletionPolicy, nil, nil, false, nil)
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if _, _, _, _, _, err := handler.CreateSnapshot(content, nil, nil); err != nil {
+						t.Errorf("unexpected error: %v", err)
+					}
+				}()
+			}
+
+			// Wait for the calls that may be in flight, then check that no
+			// other call got through.
+			err := wait.PollUntilContextTimeout(context.Background(), 5*time.Millisecond, 5*time.Second, true, func(context.Context) (bool, error) {
+				calls, _ := backend.stats()
+				return calls == test.expectedMaxInFlight, nil
+			})
+			if err != nil {
+				t.Fatalf("expected %d calls in flight: %v", test.expectedMaxInFlight, err)
+			}
+			time.Sleep(50 * time.Millisecond)
+			if calls, _ := backend.stats(); calls != test.expectedMaxInFlight {
+				t.Errorf("expected %d calls in flight, got %d", test.expectedMaxInFlight, calls)
+			}
+
+			close(backend.release)
+			wg.Wait()
+			calls, maxInFlight := backend.stats()
+			if calls != 10 || maxInFlight != test.expectedMaxInFlight {
+				t.Errorf("expected 10 calls with at most %d in flight, got %d calls with %d in flight", test.expectedMaxInFlight, calls, maxInFlight)
+			}
+		})
+	}
+}
+
+func TestRPCLimiterWaitOutsideTimeout(t *testing.T) {
+	backend := &concurrentSnapshotter{fakeSnapshotter: fakeSnapshotter{t: t}, release: make(chan struct{})}
+	limits := RPCLimits{utils.RPCCreateSnapshot: {MaxInFlight: 1}}
+	handler := NewCSIHandler(backend, nil, 20*time.Millisecond, "snapshot", -1, "groupsnapshot", -1, 0, 0, newRPCLimiters(limits), nil, nil)
+
+	// The calls queued behind the first one wait for longer than the
+	// timeout, which only starts once they are issued.
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		content := newContent(fmt.Sprintf("content13-%d", i), fmt.Sprintf("snapuid13-%d", i), "snap", "", "", "", "volume-handle", deletionPolicy, nil, nil, false, nil)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, _, _, _, _, err := handler.CreateSnapshot(content, nil, nil); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	time.Sleep(100 * time.Millisecond)
+	close(backend.release)
+	wg.Wait()
+
+	backend.mutex.Lock()
+	defer backend.mutex.Unlock()
+	if backend.calls != 3 || backend.expired != 0 {
+		t.Errorf("expected 3 calls issued before their timeout, got %d calls of which %d expired", backend.calls, backend.expired)
+	}
+}
+
+func TestRPCLimiterStop(t *testing.T) {
+	limiters := newRPCLimiters(RPCLimits{utils.RPCDeleteSnapshot: {MaxInFlight: 1}})
+	release, err := limiters.wait(context.Background(), utils.RPCDeleteSnapshot, "", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer release()
+
+	// A call waiting for the only slot gives up once the controller stops.
+	errCh := make(chan error)
+	go func() {
+		_, err := limiters.wait(context.Background(), utils.RPCDeleteSnapshot, "", nil)
+		errCh <- err
+	}()
+	limiters.stop()
+	select {
+	case err := <-errCh:
+		if err == nil {
+			t.Errorf("expected the wait to fail once the limiters are stopped")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("the wait did not end once the limiters were stopped")
+	}
+}
+
+func TestRPCLimiterClassParameters(t *testing.T) {
+	limiters := newRPCLimiters(nil)
+	const classKey = "VolumeSnapshotClass gold"
+
+	release, err := limiters.wait(context.Background(), utils.RPCDeleteSnapshot, classKey, map[string]string{"csi.storage.k8s.io/delete-snapshot-max-inflight": "1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer release()
+
+	// The only slot of the class is taken.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := limiters.wait(ctx, utils.RPCDeleteSnapshot, classKey, map[string]string{"csi.storage.k8s.io/delete-snapshot-max-inflight": "1"}); err == nil {
+		t.Errorf("expected the call to wait for the slot of the class")
+	}
+
+	// Changed parameters replace the limiter of the class.
+	second, err := limiters.wait(context.Background(), utils.RPCDeleteSnapshot, classKey, map[string]string{"csi.storage.k8s.io/delete-snapshot-max-inflight": "2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second()
+
+	if _, err := limiters.wait(context.Background(), utils.RPCDeleteSnapshot, classKey, map[string]string{"csi.storage.k8s.io/delete-snapshot-qps": "-1"}); err == nil {
+		t.Errorf("expected an error for invalid class parameters")
+	}
+}
+
+func TestRPCLimiterClassEvents(t *testing.T) {
+	limiters := newRPCLimiters(nil)
+	handler := classRPCLimitsEventHandler(limiters, snapshotClassLimiterKey, func(obj interface{}) map[string]string {
+		return obj.(*crdv1.VolumeSnapshotClass).Parameters
+	})
+	newClass := func(maxInFlight string) *crdv1.VolumeSnapshotClass {
+		return &crdv1.VolumeSnapshotClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "gold"},
+			Parameters: map[string]string{"csi.storage.k8s.io/delete-snapshot-max-inflight": maxInFlight},
+		}
+	}
+	classKey := snapshotClassLimiterKey("gold")
+	hasLimiter := func() bool {
+		limiters.mutex.Lock()
+		defer limiters.mutex.Unlock()
+		_, ok := limiters.classes[classKey]
+		return ok
+	}
+	wait := func(class *crdv1.VolumeSnapshotClass) {
+		release, err := limiters.wait(context.Background(), utils.RPCDeleteSnapshot, classKey, class.Parameters)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		release()
+	}
+
+	wait(newClass("1"))
+	handler.OnUpdate(newClass("1"), newClass("1"))
+	if !hasLimiter() {
+		t.Errorf("expected the limiter of the class to be kept on resync")
+	}
+	handler.OnUpdate(newClass("1"), newClass("2"))
+	if hasLimiter() {
+		t.Errorf("expected the limiter of the class to be dropped when its parameters change")
+	}
+	wait(newClass("2"))
+	handler.OnDelete(cache.DeletedFinalStateUnknown{Key: "gold", Obj: newClass("2")})
+	if hasLimiter() {
+		t.Errorf("expected the limiter of the class to be dropped when the class is deleted")
+	}
+}
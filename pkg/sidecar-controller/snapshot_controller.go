@@ -23,8 +23,6 @@ import (
 	"strings"
 	"time"
 
-	codes "google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	klog "k8s.io/klog/v2"
@@ -124,7 +122,7 @@ func (ctrl *csiSnapshotSideCarController) createSnapshot(content *crdv1.VolumeSn
 	klog.V(5).Infof("createSnapshot for content [%s]: started", content.Name)
 	contentObj, err := ctrl.createSnapshotWrapper(content)
 	if err != nil {
-		ctrl.updateContentErrorStatusWithEvent(contentObj, v1.EventTypeWarning, "SnapshotCreationFailed", fmt.Sprintf("Failed to create snapshot: %v", err))
+		ctrl.updateContentErrorStatusWithEvent(contentObj, v1.EventTypeWarning, "SnapshotCreationFailed", fmt.Sprintf("Failed to create snapshot: %v", err), err)
 		klog.Errorf("createSnapshot for content [%s]: error occurred in createSnapshotWrapper: %v", content.Name, err)
 		return true, err
 	}
@@ -144,7 +142,7 @@ func (ctrl *csiSnapshotSideCarController) checkandUpdateContentStatus(content *c
 	klog.V(5).Infof("checkandUpdateContentStatus[%s] started", content.Name)
 	contentObj, err := ctrl.checkandUpdateContentStatusOperation(content)
 	if err != nil {
-		ctrl.updateContentErrorStatusWithEvent(contentObj, v1.EventTypeWarning, "SnapshotContentCheckandUpdateFailed", fmt.Sprintf("Failed to check and update snapshot content: %v", err))
+		ctrl.updateContentErrorStatusWithEvent(contentObj, v1.EventTypeWarning, "SnapshotContentCheckandUpdateFailed", fmt.Sprintf("Failed to check and update snapshot content: %v", err), err)
 		klog.Errorf("checkandUpdateContentStatus [%s]: error occurred %v", content.Name, err)
 		return true, err
 	}
@@ -163,7 +161,8 @@ func (ctrl *csiSnapshotSideCarController) checkandUpdateContentStatus(content *c
 //
 // * content - content to update
 // * eventtype, reason, message - event to send, see EventRecorder.Event()
-func (ctrl *csiSnapshotSideCarController) updateContentErrorStatusWithEvent(content *crdv1.VolumeSnapshotContent, eventtype, reason, message string) error {
+// * err - error reported by the status, whose reason defaults to the reason of the event
+func (ctrl *csiSnapshotSideCarController) updateContentErrorStatusWithEvent(content *crdv1.VolumeSnapshotContent, eventtype, reason, message string, err error) error {
 	klog.V(5).Infof("updateContentStatusWithEvent[%s]", content.Name)
 
 	if content.Status != nil && content.Status.Error != nil && *content.Status.Error.Message == message {
@@ -173,12 +172,7 @@ func (ctrl *csiSnapshotSideCarController) updateContentErrorStatusWithEvent(cont
 
 	var patches []utils.PatchOp
 	ready := false
-	contentStatusError := &crdv1.VolumeSnapshotError{
-		Time: &metav1.Time{
-			Time: time.Now(),
-		},
-		Message: &message,
-	}
+	contentStatusError := utils.NewVolumeSnapshotError(message, reason, err)
 	if content.Status == nil {
 		// Initialize status if nil
 		patches = append(patches, utils.PatchOp{
@@ -203,6 +197,19 @@ func (ctrl *csiSnapshotSideCarController) updateContentErrorStatusWithEvent(cont
 		})
 
 	}
+	// Patch the conditions computed from the patched status
+	contentClone := content.DeepCopy()
+	if contentClone.Status == nil {
+		contentClone.Status = &crdv1.VolumeSnapshotContentStatus{}
+	}
+	contentClone.Status.Error = contentStatusError
+	contentClone.Status.ReadyToUse = &ready
+	utils.SetVolumeSnapshotContentConditions(contentClone)
+	patches = append(patches, utils.PatchOp{
+		Op:    "add",
+		Path:  "/status/conditions",
+		Value: contentClone.Status.Conditions,
+	})
 
 	newContent, err := utils.PatchVolumeSnapshotContent(content, patches, ctrl.clientset, "status")
 
@@ -232,14 +239,14 @@ func (ctrl *csiSnapshotSideCarController) getCSISnapshotInput(content *crdv1.Vol
 		class, err = ctrl.getSnapshotClass(*className)
 		if err != nil {
 			klog.Errorf("getCSISnapshotInput failed to getClassFromVolumeSnapshot %s", err)
-			return nil, nil, err
+			return nil, nil, utils.NewStatusError(crdv1.VolumeSnapshotErrorReasonClassError, err)
 		}
 	} else {
 		// If dynamic provisioning for an independent snapshot, return failure if no snapshot class
 		_, groupSnapshotMember := content.Annotations[utils.VolumeGroupSnapshotHandleAnnotation]
 		if content.Spec.Source.VolumeHandle != nil && !groupSnapshotMember {
 			klog.Errorf("failed to getCSISnapshotInput %s without a snapshot class", content.Name)
-			return nil, nil, fmt.Errorf("failed to take snapshot %s without a snapshot class", content.Name)
+			return nil, nil, utils.NewStatusError(crdv1.VolumeSnapshotErrorReasonClassError, fmt.Errorf("failed to take snapshot %s without a snapshot class", content.Name))
 		}
 		// For pre-provisioned snapshot or an individual snapshot in a dynamically provisioned
 		// volume group snapshot, snapshot class is not required
@@ -249,7 +256,7 @@ func (ctrl *csiSnapshotSideCarController) getCSISnapshotInput(content *crdv1.Vol
 	// Resolve snapshotting secret credentials.
 	snapshotterCredentials, err := ctrl.GetCredentialsFromAnnotation(content)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, utils.NewStatusError(crdv1.VolumeSnapshotErrorReasonSecretError, err)
 	}
 
 	return class, snapshotterCredentials, nil
@@ -273,20 +280,20 @@ func (ctrl *csiSnapshotSideCarController) checkandUpdateContentStatusOperation(c
 			class, err := ctrl.getSnapshotClass(*content.Spec.VolumeSnapshotClassName)
 			if err != nil {
 				klog.Errorf("Failed to get snapshot class %s for snapshot content %s: %v", *content.Spec.VolumeSnapshotClassName, content.Name, err)
-				return content, fmt.Errorf("failed to get snapshot class %s for snapshot content %s: %v", *content.Spec.VolumeSnapshotClassName, content.Name, err)
+				return content, utils.NewStatusError(crdv1.VolumeSnapshotErrorReasonClassError, fmt.Errorf("failed to get snapshot class %s for snapshot content %s: %v", *content.Spec.VolumeSnapshotClassName, content.Name, err))
 			}
 
-			snapshotterListSecretRef, err := utils.GetSecretReference(utils.SnapshotterListSecretParams, class.Parameters, content.GetObjectMeta().GetName(), nil)
+			snapshotterListCredentialsRef, err := utils.GetCredentialsReference(utils.SnapshotterListSecretParams, class.Parameters, content.GetObjectMeta().GetName(), nil)
 			if err != nil {
 				klog.Errorf("Failed to get secret reference for snapshot content %s: %v", content.Name, err)
-				return content, fmt.Errorf("failed to get secret reference for snapshot content %s: %v", content.Name, err)
+				return content, utils.NewStatusError(crdv1.VolumeSnapshotErrorReasonSecretError, fmt.Errorf("failed to get secret reference for snapshot content %s: %v", content.Name, err))
 			}
 
-			snapshotterListCredentials, err = utils.GetCredentials(ctrl.client, snapshotterListSecretRef)
+			snapshotterListCredentials, err = ctrl.credentials.GetCredentials(context.TODO(), snapshotterListCredentialsRef)
 			if err != nil {
 				// Continue with deletion, as the secret may have already been deleted.
 				klog.Errorf("Failed to get credentials for snapshot content %s: %v", content.Name, err)
-				return content, fmt.Errorf("failed to get credentials for snapshot content %s: %v", content.Name, err)
+				return content, utils.NewStatusError(crdv1.VolumeSnapshotErrorReasonSecretError, fmt.Errorf("failed to get credentials for snapshot content %s: %v", content.Name, err))
 			}
 		}
 
@@ -312,7 +319,7 @@ func (ctrl *csiSnapshotSideCarController) checkandUpdateContentStatusOperation(c
 
 		updatedContent, err := ctrl.updateSnapshotContentStatus(content, snapshotID, readyToUse, creationTime.UnixNano(), size, groupSnapshotID)
 		if err != nil {
-			return content, err
+			return content, utils.NewStatusError(crdv1.VolumeSnapshotErrorReasonAPIServerError, err)
 		}
 		return updatedContent, nil
 	}
@@ -331,7 +338,7 @@ func (ctrl *csiSnapshotSideCarController) createSnapshotWrapper(content *crdv1.V
 
 	class, snapshotterCredentials, err := ctrl.getCSISnapshotInput(content)
 	if err != nil {
-		return content, fmt.Errorf("failed to get input parameters to create snapshot for content %s: %q", content.Name, err)
+		return content, utils.WrapStatusError(err, fmt.Errorf("failed to get input parameters to create snapshot for content %s: %q", content.Name, err))
 	}
 
 	// NOTE(xyang): handle create timeout
@@ -343,12 +350,12 @@ func (ctrl *csiSnapshotSideCarController) createSnapshotWrapper(content *crdv1.V
 	// the storage system.
 	content, err = ctrl.setAnnVolumeSnapshotBeingCreated(content)
 	if err != nil {
-		return content, fmt.Errorf("failed to add VolumeSnapshotBeingCreated annotation on the content %s: %q", content.Name, err)
+		return content, utils.NewStatusError(crdv1.VolumeSnapshotErrorReasonAPIServerError, fmt.Errorf("failed to add VolumeSnapshotBeingCreated annotation on the content %s: %q", content.Name, err))
 	}
 
 	parameters, err := utils.RemovePrefixedParameters(class.Parameters)
 	if err != nil {
-		return content, fmt.Errorf("failed to remove CSI Parameters of prefixed keys: %v", err)
+		return content, utils.NewStatusError(crdv1.VolumeSnapshotErrorReasonClassError, fmt.Errorf("failed to remove CSI Parameters of prefixed keys: %v", err))
 	}
 	if ctrl.extraCreateMetadata {
 		parameters[utils.PrefixedVolumeSnapshotNameKey] = content.Spec.VolumeSnapshotRef.Name
@@ -362,14 +369,14 @@ func (ctrl *csiSnapshotSideCarController) createSnapshotWrapper(content *crdv1.V
 		// If it is a final error, remove annotation to indicate
 		// storage system has responded with an error
 		klog.Infof("createSnapshotWrapper: CreateSnapshot for content %s returned error: %v", content.Name, err)
-		if isCSIFinalError(err) {
+		if utils.IsCSIFinalError(err) {
 			var removeAnnotationErr error
 			if content, removeAnnotationErr = ctrl.removeAnnVolumeSnapshotBeingCreated(content); removeAnnotationErr != nil {
-				return content, fmt.Errorf("failed to remove VolumeSnapshotBeingCreated annotation from the content %s: %s", content.Name, removeAnnotationErr)
+				return content, utils.NewStatusError(crdv1.VolumeSnapshotErrorReasonAPIServerError, fmt.Errorf("failed to remove VolumeSnapshotBeingCreated annotation from the content %s: %s", content.Name, removeAnnotationErr))
 			}
 		}
 
-		return content, fmt.Errorf("failed to take snapshot of the volume %s: %q", *content.Spec.Source.VolumeHandle, err)
+		return content, utils.NewCSIStatusError(err, fmt.Errorf("failed to take snapshot of the volume %s: %q", *content.Spec.Source.VolumeHandle, err))
 	}
 
 	klog.V(5).Infof("Created snapshot: driver %s, snapshotId %s, creationTime %v, size %d, readyToUse %t", driverName, snapshotID, creationTime, size, readyToUse)
@@ -381,7 +388,7 @@ func (ctrl *csiSnapshotSideCarController) createSnapshotWrapper(content *crdv1.V
 	newContent, err := ctrl.updateSnapshotContentStatus(content, snapshotID, readyToUse, creationTime.UnixNano(), size, "")
 	if err != nil {
 		klog.Errorf("error updating status for volume snapshot content %s: %v.", content.Name, err)
-		return content, fmt.Errorf("error updating status for volume snapshot content %s: %v", content.Name, err)
+		return content, utils.NewStatusError(crdv1.VolumeSnapshotErrorReasonAPIServerError, fmt.Errorf("error updating status for volume snapshot content %s: %v", content.Name, err))
 	}
 	content = newContent
 
@@ -390,7 +397,7 @@ func (ctrl *csiSnapshotSideCarController) createSnapshotWrapper(content *crdv1.V
 	// cut the snapshot
 	content, err = ctrl.removeAnnVolumeSnapshotBeingCreated(content)
 	if err != nil {
-		return content, fmt.Errorf("failed to remove VolumeSnapshotBeingCreated annotation on the content %s: %q", content.Name, err)
+		return content, utils.NewStatusError(crdv1.VolumeSnapshotErrorReasonAPIServerError, fmt.Errorf("failed to remove VolumeSnapshotBeingCreated annotation on the content %s: %q", content.Name, err))
 	}
 
 	return content, nil
@@ -440,6 +447,7 @@ func (ctrl *csiSnapshotSideCarController) clearVolumeContentStatus(
 		content.Status.ReadyToUse = nil
 		content.Status.CreationTime = nil
 		content.Status.RestoreSize = nil
+		utils.SetVolumeSnapshotContentConditions(content)
 	}
 	newContent, err := ctrl.clientset.SnapshotV1().VolumeSnapshotContents().UpdateStatus(context.TODO(), content, metav1.UpdateOptions{})
 	if err != nil {
@@ -504,14 +512,18 @@ func (ctrl *csiSnapshotSideCarController) updateSnapshotContentStatus(
 		}
 	}
 
-	if updated {
-		contentClone := contentObj.DeepCopy()
+	contentClone := contentObj.DeepCopy()
+	contentClone.Status = newStatus
+	if utils.SetVolumeSnapshotContentConditions(contentClone) {
+		updated = true
+	}
 
+	if updated {
 		patches := []utils.PatchOp{
 			{
 				Op:    "replace",
 				Path:  "/status",
-				Value: newStatus,
+				Value: contentClone.Status,
 			},
 		}
 
@@ -567,6 +579,7 @@ func (ctrl *csiSnapshotSideCarController) GetCredentialsFromAnnotation(content *
 	// get secrets if VolumeSnapshotClass specifies it
 	var snapshotterCredentials map[string]string
 	var err error
+	var snapshotterCredentialsRef *utils.CredentialsReference
 
 	// Check if annotation exists
 	if metav1.HasAnnotation(content.ObjectMeta, utils.AnnDeletionSecretRefName) && metav1.HasAnnotation(content.ObjectMeta, utils.AnnDeletionSecretRefNamespace) {
@@ -581,8 +594,16 @@ func (ctrl *csiSnapshotSideCarController) GetCredentialsFromAnnotation(content *
 
 		snapshotterSecretRef.Name = annDeletionSecretName
 		snapshotterSecretRef.Namespace = annDeletionSecretNamespace
+		snapshotterCredentialsRef = &utils.CredentialsReference{Secret: snapshotterSecretRef}
+	} else if metav1.HasAnnotation(content.ObjectMeta, utils.AnnDeletionCredentials) {
+		snapshotterCredentialsRef, err = utils.ParseCredentialsReference(content.Annotations[utils.AnnDeletionCredentials])
+		if err != nil {
+			return nil, fmt.Errorf("cannot retrieve credentials for snapshot content %#v, err: %v", content.Name, err)
+		}
+	}
 
-		snapshotterCredentials, err = utils.GetCredentials(ctrl.client, snapshotterSecretRef)
+	if snapshotterCredentialsRef != nil {
+		snapshotterCredentials, err = ctrl.credentials.GetCredentials(context.TODO(), snapshotterCredentialsRef)
 		if err != nil {
 			// Continue with deletion, as the secret may have already been deleted.
 			klog.Errorf("Failed to get credentials for snapshot %s: %s", content.Name, err.Error())
@@ -723,31 +744,6 @@ func (ctrl csiSnapshotSideCarController) removeAnnVolumeSnapshotBeingCreated(con
 	return updatedContent, nil
 }
 
-// This function checks if the error is final
-func isCSIFinalError(err error) bool {
-	// Sources:
-	// https://github.com/grpc/grpc/blob/master/doc/statuscodes.md
-	// https://github.com/container-storage-interface/spec/blob/master/spec.md
-	st, ok := status.FromError(err)
-	if !ok {
-		// This is not gRPC error. The operation must have failed before gRPC
-		// method was called, otherwise we would get gRPC error.
-		// We don't know if any previous CreateSnapshot is in progress, be on the safe side.
-		return false
-	}
-	switch st.Code() {
-	case codes.Canceled, // gRPC: Client Application cancelled the request
-		codes.DeadlineExceeded,  // gRPC: Timeout
-		codes.Unavailable,       // gRPC: Server shutting down, TCP connection broken - previous CreateSnapshot() may be still in progress.
-		codes.ResourceExhausted, // gRPC: Server temporarily out of resources - previous CreateSnapshot() may be still in progress.
-		codes.Aborted:           // CSI: Operation pending for Snapshot
-		return false
-	}
-	// All other errors mean that creating snapshot either did not
-	// even start or failed. It is for sure not in progress.
-	return true
-}
-
 func contentIsReady(content *crdv1.VolumeSnapshotContent) bool {
 	return content.Status != nil && content.Status.ReadyToUse != nil && *content.Status.ReadyToUse
 }
@@ -41,6 +41,8 @@ import (
 	snapshotinformers "github.com/kubernetes-csi/external-snapshotter/client/v8/informers/externalversions/volumesnapshot/v1"
 	groupsnapshotlisters "github.com/kubernetes-csi/external-snapshotter/client/v8/listers/volumegroupsnapshot/v1beta1"
 	snapshotlisters "github.com/kubernetes-csi/external-snapshotter/client/v8/listers/volumesnapshot/v1"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/credentials"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/debug"
 	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/snapshotter"
 	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
 )
@@ -60,7 +62,17 @@ type csiSnapshotSideCarController struct {
 
 	contentStore cache.Store
 
+	// The results of the last sync of the objects of each queue, exposed by
+	// the debug endpoints.
+	contentSyncResults              *debug.SyncResults
+	groupSnapshotContentSyncResults *debug.SyncResults
+
 	handler Handler
+	// limiters hold the CSI calls of the handler until they may be issued.
+	limiters *rpcLimiters
+
+	// credentials reads the credentials passed to the CSI calls.
+	credentials credentials.Provider
 
 	resyncPeriod time.Duration
 
@@ -71,6 +83,9 @@ type csiSnapshotSideCarController struct {
 	groupSnapshotClassLister         groupsnapshotlisters.VolumeGroupSnapshotClassLister
 	groupSnapshotClassListerSynced   cache.InformerSynced
 	groupSnapshotContentStore        cache.Store
+	// emulatedGroupSnapshots is true if group snapshots are emulated with
+	// individual snapshots.
+	emulatedGroupSnapshots bool
 }
 
 // NewCSISnapshotSideCarController returns a new *csiSnapshotSideCarController
@@ -94,6 +109,10 @@ func NewCSISnapshotSideCarController(
 	volumeGroupSnapshotContentInformer groupsnapshotinformers.VolumeGroupSnapshotContentInformer,
 	volumeGroupSnapshotClassInformer groupsnapshotinformers.VolumeGroupSnapshotClassInformer,
 	groupSnapshotContentRateLimiter workqueue.TypedRateLimiter[string],
+	snapshotStatusCacheTTL time.Duration,
+	listSnapshotsPageSize int32,
+	rpcLimits RPCLimits,
+	credentialsOptions credentials.Options,
 ) *csiSnapshotSideCarController {
 	broadcaster := record.NewBroadcaster()
 	broadcaster.StartLogging(klog.Infof)
@@ -101,12 +120,30 @@ func NewCSISnapshotSideCarController(
 	var eventRecorder record.EventRecorder
 	eventRecorder = broadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: fmt.Sprintf("csi-snapshotter %s", driverName)})
 
+	var groupSnapshotClassLister groupsnapshotlisters.VolumeGroupSnapshotClassLister
+	if enableVolumeGroupSnapshots {
+		groupSnapshotClassLister = volumeGroupSnapshotClassInformer.Lister()
+	}
+	limiters := newRPCLimiters(rpcLimits)
+	handler := NewCSIHandler(snapshotter, groupSnapshotter, timeout, snapshotNamePrefix, snapshotNameUUIDLength, groupSnapshotNamePrefix, groupSnapshotNameUUIDLength,
+		snapshotStatusCacheTTL, listSnapshotsPageSize, limiters, volumeSnapshotClassInformer.Lister(), groupSnapshotClassLister)
+	volumeSnapshotClassInformer.Informer().AddEventHandler(classRPCLimitsEventHandler(limiters, snapshotClassLimiterKey, func(obj interface{}) map[string]string {
+		return obj.(*crdv1.VolumeSnapshotClass).Parameters
+	}))
+	if enableVolumeGroupSnapshots {
+		volumeGroupSnapshotClassInformer.Informer().AddEventHandler(classRPCLimitsEventHandler(limiters, groupSnapshotClassLimiterKey, func(obj interface{}) map[string]string {
+			return obj.(*crdv1beta1.VolumeGroupSnapshotClass).Parameters
+		}))
+	}
+
 	ctrl := &csiSnapshotSideCarController{
 		clientset:     clientset,
 		client:        client,
 		driverName:    driverName,
 		eventRecorder: eventRecorder,
-		handler:       NewCSIHandler(snapshotter, groupSnapshotter, timeout, snapshotNamePrefix, snapshotNameUUIDLength, groupSnapshotNamePrefix, groupSnapshotNameUUIDLength),
+		handler:       handler,
+		limiters:      limiters,
+		credentials:   credentials.NewProvider(client, driverName, credentialsOptions),
 		resyncPeriod:  resyncPeriod,
 		contentStore:  cache.NewStore(cache.DeletionHandlingMetaNamespaceKeyFunc),
 		contentQueue: workqueue.NewTypedRateLimitingQueueWithConfig(
@@ -131,12 +168,14 @@ func NewCSISnapshotSideCarController(
 	)
 	ctrl.contentLister = volumeSnapshotContentInformer.Lister()
 	ctrl.contentListerSynced = volumeSnapshotContentInformer.Informer().HasSynced
+	ctrl.contentSyncResults = debug.NewSyncResults("csi-snapshotter-content", volumeSnapshotContentInformer.Informer().GetStore())
 
 	ctrl.classLister = volumeSnapshotClassInformer.Lister()
 	ctrl.classListerSynced = volumeSnapshotClassInformer.Informer().HasSynced
 
 	ctrl.enableVolumeGroupSnapshots = enableVolumeGroupSnapshots
 	if enableVolumeGroupSnapshots {
+		ctrl.emulatedGroupSnapshots = group_snapshotter.IsEmulated(groupSnapshotter)
 		ctrl.groupSnapshotContentStore = cache.NewStore(cache.DeletionHandlingMetaNamespaceKeyFunc)
 		ctrl.groupSnapshotContentQueue = workqueue.NewTypedRateLimitingQueueWithConfig(
 			groupSnapshotContentRateLimiter, workqueue.TypedRateLimitingQueueConfig[string]{
@@ -158,6 +197,7 @@ func NewCSISnapshotSideCarController(
 
 		ctrl.groupSnapshotContentLister = volumeGroupSnapshotContentInformer.Lister()
 		ctrl.groupSnapshotContentListerSynced = volumeGroupSnapshotContentInformer.Informer().HasSynced
+		ctrl.groupSnapshotContentSyncResults = debug.NewSyncResults("csi-snapshotter-groupsnapshotcontent", volumeGroupSnapshotContentInformer.Informer().GetStore())
 
 		ctrl.groupSnapshotClassLister = volumeGroupSnapshotClassInformer.Lister()
 		ctrl.groupSnapshotClassListerSynced = volumeGroupSnapshotClassInformer.Informer().HasSynced
@@ -169,6 +209,7 @@ func NewCSISnapshotSideCarController(
 
 func (ctrl *csiSnapshotSideCarController) Run(workers int, stopCh <-chan struct{}) {
 	defer ctrl.contentQueue.ShutDown()
+	defer ctrl.limiters.stop()
 
 	klog.Infof("Starting CSI snapshotter")
 	defer klog.Infof("Shutting CSI snapshotter")
@@ -195,6 +236,28 @@ func (ctrl *csiSnapshotSideCarController) Run(workers int, stopCh <-chan struct{
 	<-stopCh
 }
 
+// RegisterDebugEndpoints registers the endpoints exposing the state of the
+// work queues of the controller and the result of the last sync of their
+// objects.
+func (ctrl *csiSnapshotSideCarController) RegisterDebugEndpoints(server *debug.Server) {
+	server.Register("queues", func() interface{} {
+		queues := []debug.QueueState{ctrl.contentSyncResults.QueueState(ctrl.contentQueue)}
+		if ctrl.enableVolumeGroupSnapshots {
+			queues = append(queues, ctrl.groupSnapshotContentSyncResults.QueueState(ctrl.groupSnapshotContentQueue))
+		}
+		return queues
+	})
+	server.Register("syncs", func() interface{} {
+		syncs := map[string][]debug.SyncResult{
+			"contents": ctrl.contentSyncResults.List(),
+		}
+		if ctrl.enableVolumeGroupSnapshots {
+			syncs["groupSnapshotContents"] = ctrl.groupSnapshotContentSyncResults.List()
+		}
+		return syncs
+	})
+}
+
 // enqueueContentWork adds snapshot content to given work queue.
 func (ctrl *csiSnapshotSideCarController) enqueueContentWork(obj interface{}) {
 	// Beware of "xxx deleted" events
@@ -228,6 +291,7 @@ func (ctrl *csiSnapshotSideCarController) processNextItem() bool {
 	defer ctrl.contentQueue.Done(key)
 
 	requeue, err := ctrl.syncContentByKey(key)
+	ctrl.contentSyncResults.Record(key, err)
 	if err != nil {
 		klog.V(4).Infof("Failed to sync content %q, will retry again: %v", key, err)
 		// Always requeue on error to be able to call functions like "return false, doSomething()" where doSomething
@@ -0,0 +1,367 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecar_controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	v1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	klog "k8s.io/klog/v2"
+
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	clientset "github.com/kubernetes-csi/external-snapshotter/client/v8/clientset/versioned"
+	snapshotinformers "github.com/kubernetes-csi/external-snapshotter/client/v8/informers/externalversions/volumesnapshot/v1"
+	snapshotlisters "github.com/kubernetes-csi/external-snapshotter/client/v8/listers/volumesnapshot/v1"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/snapshotter"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+)
+
+// Design:
+//
+// The importer turns snapshots that already exist on the storage backend into
+// pre-provisioned VolumeSnapshotContents, as if an administrator wrote one
+// with spec.source.snapshotHandle for each of them. It lists all the
+// snapshots of the driver, keeps the ones matching the filters and not
+// referenced by any content yet, and creates a content with the Retain
+// deletion policy for each, so that deleting an imported object never
+// deletes data that was not created through Kubernetes.
+//
+// The source volume of a snapshot is mapped back to its PersistentVolume with
+// the CSIDriverHandleIndexName index. The VolumeSnapshot the content refers
+// to is placed in the namespace of the claim of the volume, or in the default
+// namespace of the import when the volume is unknown, and is optionally
+// created as well. The names of both objects are derived from the snapshot
+// ID, so that running the import again skips the snapshots imported before.
+// Members of group snapshots are skipped, since they are imported with
+// their group.
+
+const (
+	importedContentPrefix  = "snapcontent-imported-"
+	importedSnapshotSuffix = "-imported-"
+	importedNameHashLength = 16
+	maxSnapshotNameLength  = 253
+)
+
+// ImportOptions configures a snapshot import.
+type ImportOptions struct {
+	// SourceVolumeIDs restricts the import to the snapshots of these volumes.
+	SourceVolumeIDs []string
+	// SnapshotIDPrefix restricts the import to the snapshots whose ID has this prefix.
+	SnapshotIDPrefix string
+	// Namespace of the VolumeSnapshots of volumes without a bound PersistentVolume.
+	// These snapshots are skipped when empty.
+	Namespace string
+	// SnapshotClassName is set on the imported objects, if not empty.
+	SnapshotClassName string
+	// CreateVolumeSnapshots enables the creation of the VolumeSnapshots the
+	// contents refer to.
+	CreateVolumeSnapshots bool
+	// DryRun reports what would be imported without creating anything.
+	DryRun bool
+	// PageSize is the maximum number of entries requested per ListSnapshots call.
+	PageSize int32
+	// Timeout of the listing.
+	Timeout time.Duration
+	// Secret is the secret passed to ListSnapshots, if any.
+	Secret *v1.SecretReference
+}
+
+// ImportResult describes the import of a single backend snapshot.
+type ImportResult struct {
+	SnapshotID        string
+	SourceVolumeID    string
+	ContentName       string
+	SnapshotNamespace string
+	SnapshotName      string
+	// Skipped is the reason why the snapshot was not imported, if any.
+	Skipped string
+}
+
+type snapshotImporter struct {
+	clientset   clientset.Interface
+	client      kubernetes.Interface
+	driverName  string
+	snapshotter snapshotter.Snapshotter
+	options     ImportOptions
+
+	contentLister       snapshotlisters.VolumeSnapshotContentLister
+	contentListerSynced cache.InformerSynced
+	pvIndexer           cache.Indexer
+	pvListerSynced      cache.InformerSynced
+}
+
+// NewSnapshotImporter returns an importer of the backend snapshots of the
+// driver. The PersistentVolume informer must be indexed by
+// CSIDriverHandleIndexName.
+func NewSnapshotImporter(
+	clientset clientset.Interface,
+	client kubernetes.Interface,
+	driverName string,
+	volumeSnapshotContentInformer snapshotinformers.VolumeSnapshotContentInformer,
+	pvInformer coreinformers.PersistentVolumeInformer,
+	snapshotter snapshotter.Snapshotter,
+	options ImportOptions,
+) *snapshotImporter {
+	return &snapshotImporter{
+		clientset:           clientset,
+		client:              client,
+		driverName:          driverName,
+		snapshotter:         snapshotter,
+		options:             options,
+		contentLister:       volumeSnapshotContentInformer.Lister(),
+		contentListerSynced: volumeSnapshotContentInformer.Informer().HasSynced,
+		pvIndexer:           pvInformer.Informer().GetIndexer(),
+		pvListerSynced:      pvInformer.Informer().HasSynced,
+	}
+}
+
+// Import imports the matching backend snapshots once the caches are synced.
+// The results are sorted by snapshot ID.
+func (i *snapshotImporter) Import(stopCh <-chan struct{}) ([]ImportResult, error) {
+	if !cache.WaitForCacheSync(stopCh, i.contentListerSynced, i.pvListerSynced) {
+		return nil, fmt.Errorf("cannot sync caches")
+	}
+
+	credentials, err := i.credentials()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), i.options.Timeout)
+	snapshots, listSnapshotsSupported, err := i.snapshotter.GetSnapshotStatuses(ctx, i.options.PageSize, credentials)
+	cancel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots of driver %s: %v", i.driverName, err)
+	}
+	if !listSnapshotsSupported {
+		return nil, fmt.Errorf("CSI driver %s does not support ListSnapshots", i.driverName)
+	}
+
+	contents, err := i.contentLister.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list VolumeSnapshotContents: %v", err)
+	}
+	known, _ := knownSnapshots(i.driverName, contents)
+
+	ids := make([]string, 0, len(snapshots))
+	for id := range snapshots {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	results := []ImportResult{}
+	var errs []string
+	for _, id := range ids {
+		snapshot := snapshots[id]
+		if !i.matches(snapshot) {
+			continue
+		}
+		result := ImportResult{
+			SnapshotID:     snapshot.SnapshotId,
+			SourceVolumeID: snapshot.SourceVolumeId,
+		}
+		switch {
+		case known[snapshot.SnapshotId]:
+			result.Skipped = "already referenced by a VolumeSnapshotContent"
+		case snapshot.GroupSnapshotId != "":
+			result.Skipped = fmt.Sprintf("member of group snapshot %s", snapshot.GroupSnapshotId)
+		default:
+			if err := i.importSnapshot(snapshot, &result); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", snapshot.SnapshotId, err))
+				result.Skipped = err.Error()
+			}
+		}
+		if result.Skipped != "" {
+			klog.V(2).Infof("not importing snapshot %s: %s", result.SnapshotID, result.Skipped)
+		}
+		results = append(results, result)
+	}
+	if len(errs) > 0 {
+		return results, fmt.Errorf("failed to import snapshots: %s", strings.Join(errs, "; "))
+	}
+	return results, nil
+}
+
+func (i *snapshotImporter) matches(snapshot *csi.Snapshot) bool {
+	if !strings.HasPrefix(snapshot.SnapshotId, i.options.SnapshotIDPrefix) {
+		return false
+	}
+	if len(i.options.SourceVolumeIDs) == 0 {
+		return true
+	}
+	for _, volumeID := range i.options.SourceVolumeIDs {
+		if snapshot.SourceVolumeId == volumeID {
+			return true
+		}
+	}
+	return false
+}
+
+// importSnapshot creates the content, and optionally the snapshot, of a
+// backend snapshot and fills in the result.
+func (i *snapshotImporter) importSnapshot(snapshot *csi.Snapshot, result *ImportResult) error {
+	namespace, claimName, err := i.claimOf(snapshot.SourceVolumeId)
+	if err != nil {
+		return err
+	}
+	if namespace == "" {
+		if i.options.Namespace == "" {
+			result.Skipped = fmt.Sprintf("no PersistentVolumeClaim found for volume %s and no import namespace set", snapshot.SourceVolumeId)
+			return nil
+		}
+		namespace = i.options.Namespace
+	}
+
+	result.ContentName = importedContentName(snapshot.SnapshotId)
+	result.SnapshotNamespace = namespace
+	result.SnapshotName = importedSnapshotName(claimName, snapshot.SnapshotId)
+
+	content := i.newImportedContent(snapshot, result)
+	if i.options.DryRun {
+		klog.Infof("would import snapshot %s as VolumeSnapshotContent %s bound to VolumeSnapshot %s/%s", snapshot.SnapshotId, result.ContentName, namespace, result.SnapshotName)
+		return nil
+	}
+
+	_, err = i.clientset.SnapshotV1().VolumeSnapshotContents().Create(context.TODO(), content, metav1.CreateOptions{})
+	if err != nil && !apierrs.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create VolumeSnapshotContent %s: %v", result.ContentName, err)
+	}
+	klog.Infof("imported snapshot %s as VolumeSnapshotContent %s", snapshot.SnapshotId, result.ContentName)
+
+	if !i.options.CreateVolumeSnapshots {
+		return nil
+	}
+	_, err = i.clientset.SnapshotV1().VolumeSnapshots(namespace).Create(context.TODO(), i.newImportedSnapshot(snapshot, result), metav1.CreateOptions{})
+	if err != nil && !apierrs.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create VolumeSnapshot %s/%s: %v", namespace, result.SnapshotName, err)
+	}
+	klog.Infof("created VolumeSnapshot %s/%s for snapshot %s", namespace, result.SnapshotName, snapshot.SnapshotId)
+	return nil
+}
+
+// claimOf returns the claim bound to the PersistentVolume of the given
+// volume, if any.
+func (i *snapshotImporter) claimOf(volumeID string) (string, string, error) {
+	objs, err := i.pvIndexer.ByIndex(utils.CSIDriverHandleIndexName, utils.PersistentVolumeKeyFuncByCSIDriverHandle(i.driverName, volumeID))
+	if err != nil {
+		return "", "", err
+	}
+	if len(objs) > 1 {
+		return "", "", fmt.Errorf("multiple PersistentVolumes found for volume %s", volumeID)
+	}
+	if len(objs) == 0 {
+		return "", "", nil
+	}
+	pv, ok := objs[0].(*v1.PersistentVolume)
+	if !ok || pv.Spec.ClaimRef == nil {
+		return "", "", nil
+	}
+	return pv.Spec.ClaimRef.Namespace, pv.Spec.ClaimRef.Name, nil
+}
+
+func (i *snapshotImporter) newImportedContent(snapshot *csi.Snapshot, result *ImportResult) *crdv1.VolumeSnapshotContent {
+	snapshotHandle := snapshot.SnapshotId
+	content := &crdv1.VolumeSnapshotContent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        result.ContentName,
+			Annotations: map[string]string{utils.AnnImportedFromVolume: snapshot.SourceVolumeId},
+		},
+		Spec: crdv1.VolumeSnapshotContentSpec{
+			VolumeSnapshotRef: v1.ObjectReference{
+				Kind:       "VolumeSnapshot",
+				APIVersion: crdv1.SchemeGroupVersion.String(),
+				Namespace:  result.SnapshotNamespace,
+				Name:       result.SnapshotName,
+			},
+			DeletionPolicy: crdv1.VolumeSnapshotContentRetain,
+			Driver:         i.driverName,
+			Source: crdv1.VolumeSnapshotContentSource{
+				SnapshotHandle: &snapshotHandle,
+			},
+		},
+	}
+	if i.options.SnapshotClassName != "" {
+		className := i.options.SnapshotClassName
+		content.Spec.VolumeSnapshotClassName = &className
+	}
+	return content
+}
+
+func (i *snapshotImporter) newImportedSnapshot(snapshot *csi.Snapshot, result *ImportResult) *crdv1.VolumeSnapshot {
+	contentName := result.ContentName
+	volumeSnapshot := &crdv1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        result.SnapshotName,
+			Namespace:   result.SnapshotNamespace,
+			Annotations: map[string]string{utils.AnnImportedFromVolume: snapshot.SourceVolumeId},
+		},
+		Spec: crdv1.VolumeSnapshotSpec{
+			Source: crdv1.VolumeSnapshotSource{
+				VolumeSnapshotContentName: &contentName,
+			},
+		},
+	}
+	if i.options.SnapshotClassName != "" {
+		className := i.options.SnapshotClassName
+		volumeSnapshot.Spec.VolumeSnapshotClassName = &className
+	}
+	return volumeSnapshot
+}
+
+func (i *snapshotImporter) credentials() (map[string]string, error) {
+	if i.options.Secret == nil {
+		return nil, nil
+	}
+	return utils.GetCredentials(i.client, i.options.Secret)
+}
+
+// importedNameHash returns a stable suffix derived from the snapshot ID,
+// which may contain characters that are not valid in object names.
+func importedNameHash(snapshotID string) string {
+	sum := sha256.Sum256([]byte(snapshotID))
+	return hex.EncodeToString(sum[:])[:importedNameHashLength]
+}
+
+func importedContentName(snapshotID string) string {
+	return importedContentPrefix + importedNameHash(snapshotID)
+}
+
+// importedSnapshotName returns "<claim>-imported-<hash>", or
+// "imported-<hash>" when the claim is unknown.
+func importedSnapshotName(claimName, snapshotID string) string {
+	suffix := importedNameHash(snapshotID)
+	if claimName == "" {
+		return strings.TrimPrefix(importedSnapshotSuffix, "-") + suffix
+	}
+	maxClaimLength := maxSnapshotNameLength - len(importedSnapshotSuffix) - len(suffix)
+	if len(claimName) > maxClaimLength {
+		claimName = claimName[:maxClaimLength]
+	}
+	return claimName + importedSnapshotSuffix + suffix
+}
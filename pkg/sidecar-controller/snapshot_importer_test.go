@@ -0,0 +1,241 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecar_controller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	"github.com/kubernetes-csi/external-snapshotter/client/v8/clientset/versioned/fake"
+	informers "github.com/kubernetes-csi/external-snapshotter/client/v8/informers/externalversions"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	coreinformers "k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+func newImportVolume(name, volumeHandle, claimNamespace, claimName string) *v1.PersistentVolume {
+	pv := &v1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v1.PersistentVolumeSpec{
+			PersistentVolumeSource: v1.PersistentVolumeSource{
+				CSI: &v1.CSIPersistentVolumeSource{Driver: mockDriverName, VolumeHandle: volumeHandle},
+			},
+		},
+	}
+	if claimName != "" {
+		pv.Spec.ClaimRef = &v1.ObjectReference{Kind: "PersistentVolumeClaim", Namespace: claimNamespace, Name: claimName}
+	}
+	return pv
+}
+
+func TestSnapshotImporter(t *testing.T) {
+	backendSnapshots := map[string]*csi.Snapshot{
+		"sid10-1":   {SnapshotId: "sid10-1", SourceVolumeId: "volume10-1"},
+		"sid10-2":   {SnapshotId: "sid10-2", SourceVolumeId: "volume10-2"},
+		"sid10-3":   {SnapshotId: "sid10-3", SourceVolumeId: "volume10-3"},
+		"sid10-4":   {SnapshotId: "sid10-4", SourceVolumeId: "volume10-1", GroupSnapshotId: "group10"},
+		"other10-5": {SnapshotId: "other10-5", SourceVolumeId: "volume10-1"},
+	}
+	volumes := []*v1.PersistentVolume{
+		newImportVolume("pv10-1", "volume10-1", "apps", "data"),
+		newImportVolume("pv10-2", "volume10-2", "", ""),
+	}
+	existing := newContent("content10-2", "snapuid10-2", "snap10-2", "", classGold, "sid10-2", "", deletionPolicy, nil, nil, false, nil)
+
+	result := func(id, volumeID, namespace, snapshotName string) ImportResult {
+		return ImportResult{
+			SnapshotID:        id,
+			SourceVolumeID:    volumeID,
+			ContentName:       importedContentName(id),
+			SnapshotNamespace: namespace,
+			SnapshotName:      snapshotName,
+		}
+	}
+	skipped := func(id, volumeID, reason string) ImportResult {
+		return ImportResult{SnapshotID: id, SourceVolumeID: volumeID, Skipped: reason}
+	}
+
+	tests := []struct {
+		name              string
+		options           ImportOptions
+		contents          []*crdv1.VolumeSnapshotContent
+		expectedResults   []ImportResult
+		expectedContents  []string
+		expectedSnapshots []string
+	}{
+		{
+			name:     "10-1 - snapshots are imported in the namespace of the claim of their volume",
+			options:  ImportOptions{SnapshotIDPrefix: "sid", Namespace: "imported"},
+			contents: []*crdv1.VolumeSnapshotContent{existing},
+			expectedResults: []ImportResult{
+				result("sid10-1", "volume10-1", "apps", "data-imported-"+importedNameHash("sid10-1")),
+				skipped("sid10-2", "volume10-2", "already referenced by a VolumeSnapshotContent"),
+				result("sid10-3", "volume10-3", "imported", "imported-"+importedNameHash("sid10-3")),
+				skipped("sid10-4", "volume10-1", "member of group snapshot group10"),
+			},
+			expectedContents: []string{"content10-2", importedContentName("sid10-1"), importedContentName("sid10-3")},
+		},
+		{
+			name:    "10-2 - volume filter and VolumeSnapshot creation",
+			options: ImportOptions{SourceVolumeIDs: []string{"volume10-1"}, CreateVolumeSnapshots: true, SnapshotClassName: classGold},
+			expectedResults: []ImportResult{
+				result("other10-5", "volume10-1", "apps", "data-imported-"+importedNameHash("other10-5")),
+				result("sid10-1", "volume10-1", "apps", "data-imported-"+importedNameHash("sid10-1")),
+				skipped("sid10-4", "volume10-1", "member of group snapshot group10"),
+			},
+			expectedContents: []string{importedContentName("other10-5"), importedContentName("sid10-1")},
+			expectedSnapshots: []string{
+				"apps/data-imported-" + importedNameHash("other10-5"),
+				"apps/data-imported-" + importedNameHash("sid10-1"),
+			},
+		},
+		{
+			name:    "10-3 - snapshots of volumes without claim are skipped without import namespace",
+			options: ImportOptions{SourceVolumeIDs: []string{"volume10-2", "volume10-3"}},
+			expectedResults: []ImportResult{
+				{SnapshotID: "sid10-2", SourceVolumeID: "volume10-2", Skipped: "no PersistentVolumeClaim found for volume volume10-2 and no import namespace set"},
+				{SnapshotID: "sid10-3", SourceVolumeID: "volume10-3", Skipped: "no PersistentVolumeClaim found for volume volume10-3 and no import namespace set"},
+			},
+			expectedContents: []string{},
+		},
+		{
+			name:    "10-4 - dry run creates nothing",
+			options: ImportOptions{SnapshotIDPrefix: "sid10-1", CreateVolumeSnapshots: true, DryRun: true},
+			expectedResults: []ImportResult{
+				result("sid10-1", "volume10-1", "apps", "data-imported-"+importedNameHash("sid10-1")),
+			},
+			expectedContents: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			objs := []runtime.Object{}
+			for _, content := range test.contents {
+				objs = append(objs, content)
+			}
+			client := fake.NewSimpleClientset(objs...)
+			kubeClient := kubefake.NewSimpleClientset()
+			factory := informers.NewSharedInformerFactory(client, 0)
+			coreFactory := coreinformers.NewSharedInformerFactory(kubeClient, 0)
+			contentInformer := factory.Snapshot().V1().VolumeSnapshotContents()
+			pvInformer := coreFactory.Core().V1().PersistentVolumes()
+			pvInformer.Informer().AddIndexers(cache.Indexers{utils.CSIDriverHandleIndexName: utils.PersistentVolumeByCSIDriverHandleIndexFunc})
+			for _, content := range test.contents {
+				contentInformer.Informer().GetIndexer().Add(content)
+			}
+			for _, pv := range volumes {
+				pvInformer.Informer().GetIndexer().Add(pv)
+			}
+
+			backend := &listingSnapshotter{
+				fakeSnapshotter:        fakeSnapshotter{t: t},
+				snapshots:              backendSnapshots,
+				listSnapshotsSupported: true,
+			}
+			test.options.Timeout = time.Minute
+			importer := NewSnapshotImporter(client, kubeClient, mockDriverName, contentInformer, pvInformer, backend, test.options)
+			importer.contentListerSynced = alwaysReady
+			importer.pvListerSynced = alwaysReady
+
+			results, err := importer.Import(make(chan struct{}))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(results, test.expectedResults) {
+				t.Errorf("expected results:\n%+v\ngot:\n%+v", test.expectedResults, results)
+			}
+
+			contents, err := client.SnapshotV1().VolumeSnapshotContents().List(context.TODO(), metav1.ListOptions{})
+			if err != nil {
+				t.Fatalf("failed to list contents: %v", err)
+			}
+			contentNames := []string{}
+			for _, content := range contents.Items {
+				contentNames = append(contentNames, content.Name)
+				if content.Name == "content10-2" {
+					continue
+				}
+				if content.Spec.DeletionPolicy != crdv1.VolumeSnapshotContentRetain {
+					t.Errorf("expected content %s to be retained, got %s", content.Name, content.Spec.DeletionPolicy)
+				}
+				if content.Spec.Source.SnapshotHandle == nil || importedContentName(*content.Spec.Source.SnapshotHandle) != content.Name {
+					t.Errorf("unexpected snapshot handle of content %s: %v", content.Name, content.Spec.Source.SnapshotHandle)
+				}
+				if content.Annotations[utils.AnnImportedFromVolume] == "" {
+					t.Errorf("expected content %s to be annotated with its source volume", content.Name)
+				}
+			}
+			if !sameStrings(contentNames, test.expectedContents) {
+				t.Errorf("expected contents %v, got %v", test.expectedContents, contentNames)
+			}
+
+			snapshots, err := client.SnapshotV1().VolumeSnapshots("").List(context.TODO(), metav1.ListOptions{})
+			if err != nil {
+				t.Fatalf("failed to list snapshots: %v", err)
+			}
+			snapshotKeys := []string{}
+			for i := range snapshots.Items {
+				snapshot := &snapshots.Items[i]
+				snapshotKeys = append(snapshotKeys, utils.SnapshotKey(snapshot))
+				if snapshot.Spec.Source.VolumeSnapshotContentName == nil || snapshot.Spec.VolumeSnapshotClassName == nil || *snapshot.Spec.VolumeSnapshotClassName != classGold {
+					t.Errorf("unexpected spec of snapshot %s: %+v", snapshot.Name, snapshot.Spec)
+				}
+			}
+			if !sameStrings(snapshotKeys, test.expectedSnapshots) {
+				t.Errorf("expected snapshots %v, got %v", test.expectedSnapshots, snapshotKeys)
+			}
+		})
+	}
+}
+
+func TestImportedSnapshotName(t *testing.T) {
+	long := make([]byte, 300)
+	for i := range long {
+		long[i] = 'a'
+	}
+	if name := importedSnapshotName(string(long), "sid"); len(name) != maxSnapshotNameLength {
+		t.Errorf("expected name of %d characters, got %d", maxSnapshotNameLength, len(name))
+	}
+	if importedContentName("a/b") == importedContentName("a-b") {
+		t.Errorf("expected different content names for different snapshot IDs")
+	}
+}
+
+func sameStrings(a, b []string) bool {
+	set := map[string]int{}
+	for _, s := range a {
+		set[s]++
+	}
+	for _, s := range b {
+		set[s]--
+	}
+	for _, count := range set {
+		if count != 0 {
+			return false
+		}
+	}
+	return true
+}
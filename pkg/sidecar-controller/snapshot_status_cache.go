@@ -0,0 +1,139 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecar_controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	klog "k8s.io/klog/v2"
+
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/snapshotter"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+)
+
+// snapshotStatusCache serves the status of snapshots from a listing of all
+// the snapshots of the driver, so that the status of N contents costs
+// N / pageSize ListSnapshots calls instead of N ControllerGetCapabilities and
+// N ListSnapshots calls. Listings are made per set of ListSnapshots
+// credentials, since the credentials may restrict the snapshots a driver
+// returns, and are reused for ttl.
+type snapshotStatusCache struct {
+	snapshotter snapshotter.Snapshotter
+	ttl         time.Duration
+	pageSize    int32
+	timeout     time.Duration
+	limiters    *rpcLimiters
+	now         func() time.Time
+
+	mutex    sync.Mutex
+	listings map[string]*snapshotListing
+}
+
+// snapshotListing is the listing of the snapshots visible with one set of
+// credentials. Its mutex is held while the listing is refreshed, so that
+// concurrent workers wait for a single listing instead of issuing their own.
+type snapshotListing struct {
+	mutex                  sync.Mutex
+	listedAt               time.Time
+	listSnapshotsSupported bool
+	snapshots              map[string]*csi.Snapshot
+}
+
+func newSnapshotStatusCache(snapshotter snapshotter.Snapshotter, ttl time.Duration, pageSize int32, timeout time.Duration, limiters *rpcLimiters) *snapshotStatusCache {
+	return &snapshotStatusCache{
+		snapshotter: snapshotter,
+		ttl:         ttl,
+		pageSize:    pageSize,
+		timeout:     timeout,
+		limiters:    limiters,
+		now:         time.Now,
+		listings:    map[string]*snapshotListing{},
+	}
+}
+
+// get returns the cached snapshot with the given ID. found is false when the
+// snapshot is not part of the listing, which may be older than the snapshot,
+// so the caller should ask the driver for it directly. listSnapshotsSupported
+// is false when the driver does not support ListSnapshots. The listing is
+// made under the ListSnapshots limits of the driver.
+func (c *snapshotStatusCache) get(snapshotID string, snapshotterListCredentials map[string]string) (snapshot *csi.Snapshot, found bool, listSnapshotsSupported bool, err error) {
+	listing := c.listing(credentialsKey(snapshotterListCredentials))
+
+	listing.mutex.Lock()
+	defer listing.mutex.Unlock()
+	if listing.listedAt.IsZero() || c.now().Sub(listing.listedAt) >= c.ttl {
+		snapshots, supported, err := c.list(snapshotterListCredentials)
+		if err != nil {
+			return nil, false, supported, err
+		}
+		klog.V(4).Infof("snapshotStatusCache: listed %d snapshots", len(snapshots))
+		listing.listedAt = c.now()
+		listing.listSnapshotsSupported = supported
+		listing.snapshots = snapshots
+	}
+	if !listing.listSnapshotsSupported {
+		return nil, false, false, nil
+	}
+	snapshot, found = listing.snapshots[snapshotID]
+	return snapshot, found, true, nil
+}
+
+func (c *snapshotStatusCache) list(snapshotterListCredentials map[string]string) (map[string]*csi.Snapshot, bool, error) {
+	release, err := c.limiters.wait(context.Background(), utils.RPCListSnapshots, "", nil)
+	if err != nil {
+		return nil, false, err
+	}
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	return c.snapshotter.GetSnapshotStatuses(ctx, c.pageSize, snapshotterListCredentials)
+}
+
+func (c *snapshotStatusCache) listing(key string) *snapshotListing {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	listing, ok := c.listings[key]
+	if !ok {
+		listing = &snapshotListing{}
+		c.listings[key] = listing
+	}
+	return listing
+}
+
+// credentialsKey returns a key identifying a set of credentials without
+// keeping the secrets in memory.
+func credentialsKey(credentials map[string]string) string {
+	keys := make([]string, 0, len(credentials))
+	for key := range credentials {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	hash := sha256.New()
+	for _, key := range keys {
+		hash.Write([]byte(key))
+		hash.Write([]byte{0})
+		hash.Write([]byte(credentials[key]))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecar_controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// listingSnapshotter serves GetSnapshotStatuses from a fixed list of
+// snapshots and counts the calls of both status paths.
+type listingSnapshotter struct {
+	fakeSnapshotter
+	snapshots              map[string]*csi.Snapshot
+	listSnapshotsSupported bool
+	listingCalls           int
+	listingSecrets         []map[string]string
+	statusCalls            []string
+}
+
+func (f *listingSnapshotter) GetSnapshotStatuses(ctx context.Context, maxEntries int32, snapshotterListCredentials map[string]string) (map[string]*csi.Snapshot, bool, error) {
+	f.listingCalls++
+	f.listingSecrets = append(f.listingSecrets, snapshotterListCredentials)
+	if !f.listSnapshotsSupported {
+		return nil, false, nil
+	}
+	snapshots := map[string]*csi.Snapshot{}
+	for id, snapshot := range f.snapshots {
+		snapshots[id] = snapshot
+	}
+	return snapshots, true, nil
+}
+
+func (f *listingSnapshotter) GetSnapshotStatus(ctx context.Context, snapshotID string, snapshotterListCredentials map[string]string) (bool, time.Time, int64, string, error) {
+	f.statusCalls = append(f.statusCalls, snapshotID)
+	snapshot, ok := f.snapshots[snapshotID]
+	if !ok {
+		return false, time.Time{}, 0, "", fmt.Errorf("can not find snapshot for snapshotID %s", snapshotID)
+	}
+	return snapshot.ReadyToUse, snapshot.CreationTime.AsTime(), snapshot.SizeBytes, snapshot.GroupSnapshotId, nil
+}
+
+func TestSnapshotStatusCache(t *testing.T) {
+	created := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	secret := map[string]string{"foo": "bar"}
+
+	newBackend := func() *listingSnapshotter {
+		return &listingSnapshotter{
+			fakeSnapshotter: fakeSnapshotter{t: t},
+			snapshots: map[string]*csi.Snapshot{
+				"sid9-1": {SnapshotId: "sid9-1", SizeBytes: 10, CreationTime: timestamppb.New(created), ReadyToUse: true},
+				"sid9-2": {SnapshotId: "sid9-2", SizeBytes: 20, CreationTime: timestamppb.New(created), ReadyToUse: false, GroupSnapshotId: "group9"},
+			},
+			listSnapshotsSupported: true,
+		}
+	}
+	newHandler := func(backend *listingSnapshotter, now *time.Time) *csiHandler {
+		handler := NewCSIHandler(backend, nil, time.Minute, "snapshot", -1, "groupsnapshot", -1, 10*time.Second, 100, newRPCLimiters(nil), nil, nil).(*csiHandler)
+		handler.statusCache.now = func() time.Time { return *now }
+		return handler
+	}
+	content := func(name, handle string) *crdv1.VolumeSnapshotContent {
+		return newContent(name, "snapuid-"+name, "snap-"+name, "", classGold, handle, "", deletionPolicy, nil, nil, false, nil)
+	}
+
+	t.Run("9-1 - statuses of several contents are served by a single listing", func(t *testing.T) {
+		now := created
+		backend := newBackend()
+		handler := newHandler(backend, &now)
+
+		ready, creationTime, size, groupID, err := handler.GetSnapshotStatus(content("content9-1", "sid9-1"), nil)
+		if err != nil || !ready || !creationTime.Equal(created) || size != 10 || groupID != "" {
+			t.Errorf("unexpected status of sid9-1: %v %v %d %q %v", ready, creationTime, size, groupID, err)
+		}
+		ready, _, size, groupID, err = handler.GetSnapshotStatus(content("content9-2", "sid9-2"), nil)
+		if err != nil || ready || size != 20 || groupID != "group9" {
+			t.Errorf("unexpected status of sid9-2: %v %d %q %v", ready, size, groupID, err)
+		}
+		if backend.listingCalls != 1 || len(backend.statusCalls) != 0 {
+			t.Errorf("expected 1 listing and no per-snapshot calls, got %d and %v", backend.listingCalls, backend.statusCalls)
+		}
+	})
+
+	t.Run("9-2 - listing is refreshed after the TTL", func(t *testing.T) {
+		now := created
+		backend := newBackend()
+		handler := newHandler(backend, &now)
+
+		if _, _, _, _, err := handler.GetSnapshotStatus(content("content9-1", "sid9-1"), nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		backend.snapshots["sid9-2"] = &csi.Snapshot{SnapshotId: "sid9-2", SizeBytes: 20, CreationTime: timestamppb.New(created), ReadyToUse: true, GroupSnapshotId: "group9"}
+		now = now.Add(5 * time.Second)
+		ready, _, _, _, _ := handler.GetSnapshotStatus(content("content9-2", "sid9-2"), nil)
+		if ready || backend.listingCalls != 1 {
+			t.Errorf("expected cached status within the TTL, got ready %v after %d listings", ready, backend.listingCalls)
+		}
+		now = now.Add(5 * time.Second)
+		ready, _, _, _, _ = handler.GetSnapshotStatus(content("content9-2", "sid9-2"), nil)
+		if !ready || backend.listingCalls != 2 {
+			t.Errorf("expected refreshed status after the TTL, got ready %v after %d listings", ready, backend.listingCalls)
+		}
+	})
+
+	t.Run("9-3 - snapshot missing from the listing is looked up directly", func(t *testing.T) {
+		now := created
+		backend := newBackend()
+		handler := newHandler(backend, &now)
+
+		if _, _, _, _, err := handler.GetSnapshotStatus(content("content9-1", "sid9-1"), nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		backend.snapshots["sid9-3"] = &csi.Snapshot{SnapshotId: "sid9-3", CreationTime: timestamppb.New(created), ReadyToUse: true}
+		ready, _, _, _, err := handler.GetSnapshotStatus(content("content9-3", "sid9-3"), nil)
+		if err != nil || !ready {
+			t.Errorf("unexpected status of sid9-3: %v %v", ready, err)
+		}
+		if _, _, _, _, err := handler.GetSnapshotStatus(content("content9-4", "sid9-4"), nil); err == nil {
+			t.Errorf("expected error for unknown snapshot, got none")
+		}
+		if backend.listingCalls != 1 || len(backend.statusCalls) != 2 {
+			t.Errorf("expected 1 listing and 2 per-snapshot calls, got %d and %v", backend.listingCalls, backend.statusCalls)
+		}
+	})
+
+	t.Run("9-4 - listings are kept per credentials", func(t *testing.T) {
+		now := created
+		backend := newBackend()
+		handler := newHandler(backend, &now)
+
+		handler.GetSnapshotStatus(content("content9-1", "sid9-1"), nil)
+		handler.GetSnapshotStatus(content("content9-1", "sid9-1"), secret)
+		handler.GetSnapshotStatus(content("content9-2", "sid9-2"), secret)
+		if backend.listingCalls != 2 {
+			t.Errorf("expected 2 listings, got %d", backend.listingCalls)
+		}
+		if len(backend.listingSecrets) != 2 || backend.listingSecrets[1]["foo"] != "bar" {
+			t.Errorf("expected the second listing to use the secret, got %v", backend.listingSecrets)
+		}
+	})
+
+	t.Run("9-5 - snapshots are assumed ready when ListSnapshots is not supported", func(t *testing.T) {
+		now := created
+		backend := newBackend()
+		backend.listSnapshotsSupported = false
+		handler := newHandler(backend, &now)
+
+		ready, _, _, _, err := handler.GetSnapshotStatus(content("content9-1", "sid9-1"), nil)
+		if err != nil || !ready {
+			t.Errorf("unexpected status: %v %v", ready, err)
+		}
+		handler.GetSnapshotStatus(content("content9-2", "sid9-2"), nil)
+		if backend.listingCalls != 1 || len(backend.statusCalls) != 0 {
+			t.Errorf("expected 1 listing and no per-snapshot calls, got %d and %v", backend.listingCalls, backend.statusCalls)
+		}
+	})
+}
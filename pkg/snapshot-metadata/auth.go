@@ -0,0 +1,113 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshotmetadata
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// AuthorizationMetadataKey is the gRPC metadata key of the service
+	// account token of the caller, in the form "Bearer <token>". The token
+	// must be issued for the audience of the SnapshotMetadataService.
+	AuthorizationMetadataKey = "authorization"
+	// NamespaceMetadataKey is the gRPC metadata key of the namespace of the
+	// VolumeSnapshots named in the request.
+	NamespaceMetadataKey = "namespace"
+
+	bearerPrefix = "Bearer "
+)
+
+// authorize authenticates the caller of a request with a TokenReview and
+// checks with a SubjectAccessReview that it may get VolumeSnapshots in the
+// namespace of the request. It returns the namespace.
+func (s *Server) authorize(ctx context.Context) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	token := firstValue(md, AuthorizationMetadataKey)
+	if !strings.HasPrefix(token, bearerPrefix) || len(token) == len(bearerPrefix) {
+		return "", status.Errorf(codes.Unauthenticated, "missing bearer token in %q metadata", AuthorizationMetadataKey)
+	}
+	token = strings.TrimPrefix(token, bearerPrefix)
+	namespace := firstValue(md, NamespaceMetadataKey)
+	if namespace == "" {
+		return "", status.Errorf(codes.InvalidArgument, "missing %q metadata", NamespaceMetadataKey)
+	}
+
+	review, err := s.client.AuthenticationV1().TokenReviews().Create(ctx, &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{
+			Token:     token,
+			Audiences: []string{s.audience},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", status.Errorf(codes.Unavailable, "failed to review token: %v", err)
+	}
+	if !review.Status.Authenticated {
+		klog.V(4).Infof("rejected token: %s", review.Status.Error)
+		return "", status.Error(codes.Unauthenticated, "token is not valid for the snapshot metadata service")
+	}
+	if !slices.Contains(review.Status.Audiences, s.audience) {
+		return "", status.Errorf(codes.Unauthenticated, "token is not issued for audience %q", s.audience)
+	}
+	user := review.Status.User
+
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for key, value := range user.Extra {
+		extra[key] = authorizationv1.ExtraValue(value)
+	}
+	sar, err := s.client.AuthorizationV1().SubjectAccessReviews().Create(ctx, &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      "get",
+				Group:     crdv1.GroupName,
+				Resource:  "volumesnapshots",
+			},
+			User:   user.Username,
+			Groups: user.Groups,
+			Extra:  extra,
+			UID:    user.UID,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", status.Errorf(codes.Unavailable, "failed to review access: %v", err)
+	}
+	if !sar.Status.Allowed {
+		return "", status.Errorf(codes.PermissionDenied, "user %q cannot get VolumeSnapshots in namespace %q", user.Username, namespace)
+	}
+	klog.V(5).Infof("authorized user %q for namespace %q", user.Username, namespace)
+	return namespace, nil
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
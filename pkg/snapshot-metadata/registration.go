@@ -0,0 +1,61 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshotmetadata
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	crdv1alpha1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1alpha1"
+	clientset "github.com/kubernetes-csi/external-snapshotter/client/v8/clientset/versioned"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klog "k8s.io/klog/v2"
+)
+
+// RegisterService creates or updates the SnapshotMetadataService of the
+// driver, so that clients can find the endpoint of the server.
+func RegisterService(ctx context.Context, client clientset.Interface, driverName string, spec crdv1alpha1.SnapshotMetadataServiceSpec) error {
+	services := client.SnapshotV1alpha1().SnapshotMetadataServices()
+	service, err := services.Get(ctx, driverName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = services.Create(ctx, &crdv1alpha1.SnapshotMetadataService{
+			ObjectMeta: metav1.ObjectMeta{Name: driverName},
+			Spec:       spec,
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to create SnapshotMetadataService %s: %v", driverName, err)
+		}
+		klog.Infof("Created SnapshotMetadataService %s", driverName)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get SnapshotMetadataService %s: %v", driverName, err)
+	}
+	if reflect.DeepEqual(service.Spec, spec) {
+		return nil
+	}
+	service = service.DeepCopy()
+	service.Spec = spec
+	if _, err := services.Update(ctx, service, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update SnapshotMetadataService %s: %v", driverName, err)
+	}
+	klog.Infof("Updated SnapshotMetadataService %s", driverName)
+	return nil
+}
@@ -0,0 +1,288 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package snapshotmetadata implements a proxy for the CSI SnapshotMetadata
+// service of a CSI driver, which returns the allocated blocks of a snapshot
+// and the blocks that changed between two snapshots.
+//
+// Clients call the csi.v1.SnapshotMetadata service of the proxy with the
+// names of VolumeSnapshots instead of CSI snapshot handles, and send their
+// service account token and the namespace of the VolumeSnapshots as gRPC
+// metadata. The proxy authenticates and authorizes the caller, resolves the
+// VolumeSnapshots to the snapshot handles of their bound
+// VolumeSnapshotContents and streams the responses of the driver back.
+package snapshotmetadata
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	snapshotinformers "github.com/kubernetes-csi/external-snapshotter/client/v8/informers/externalversions/volumesnapshot/v1"
+	snapshotlisters "github.com/kubernetes-csi/external-snapshotter/client/v8/listers/volumesnapshot/v1"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/credentials"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	klog "k8s.io/klog/v2"
+)
+
+// Server serves the csi.v1.SnapshotMetadata service on behalf of a CSI
+// driver.
+type Server struct {
+	csi.UnimplementedSnapshotMetadataServer
+
+	client         kubernetes.Interface
+	metadataClient csi.SnapshotMetadataClient
+	driverName     string
+	audience       string
+	// credentials resolves the snapshotter credentials of the snapshot
+	// classes.
+	credentials credentials.Provider
+
+	snapshotLister       snapshotlisters.VolumeSnapshotLister
+	snapshotListerSynced cache.InformerSynced
+	contentLister        snapshotlisters.VolumeSnapshotContentLister
+	contentListerSynced  cache.InformerSynced
+	classLister          snapshotlisters.VolumeSnapshotClassLister
+	classListerSynced    cache.InformerSynced
+}
+
+// NewServer returns a new *Server that forwards requests to the CSI driver
+// behind conn.
+func NewServer(
+	client kubernetes.Interface,
+	conn grpc.ClientConnInterface,
+	driverName string,
+	audience string,
+	volumeSnapshotInformer snapshotinformers.VolumeSnapshotInformer,
+	volumeSnapshotContentInformer snapshotinformers.VolumeSnapshotContentInformer,
+	volumeSnapshotClassInformer snapshotinformers.VolumeSnapshotClassInformer,
+	credentialsOptions credentials.Options,
+) *Server {
+	return &Server{
+		client:               client,
+		metadataClient:       csi.NewSnapshotMetadataClient(conn),
+		driverName:           driverName,
+		audience:             audience,
+		credentials:          credentials.NewProvider(client, driverName, credentialsOptions),
+		snapshotLister:       volumeSnapshotInformer.Lister(),
+		snapshotListerSynced: volumeSnapshotInformer.Informer().HasSynced,
+		contentLister:        volumeSnapshotContentInformer.Lister(),
+		contentListerSynced:  volumeSnapshotContentInformer.Informer().HasSynced,
+		classLister:          volumeSnapshotClassInformer.Lister(),
+		classListerSynced:    volumeSnapshotClassInformer.Informer().HasSynced,
+	}
+}
+
+// WaitForCacheSync waits until the informers used by the server are synced.
+// It returns false if stopCh was closed before.
+func (s *Server) WaitForCacheSync(stopCh <-chan struct{}) bool {
+	return cache.WaitForCacheSync(stopCh, s.snapshotListerSynced, s.contentListerSynced, s.classListerSynced)
+}
+
+// GetMetadataAllocated streams the allocated blocks of the VolumeSnapshot
+// named by request.SnapshotId.
+func (s *Server) GetMetadataAllocated(request *csi.GetMetadataAllocatedRequest, stream csi.SnapshotMetadata_GetMetadataAllocatedServer) error {
+	ctx := stream.Context()
+	if request.SnapshotId == "" {
+		return status.Error(codes.InvalidArgument, "snapshot_id must be the name of a VolumeSnapshot")
+	}
+	if request.StartingOffset < 0 {
+		return status.Error(codes.InvalidArgument, "starting_offset must not be negative")
+	}
+	namespace, err := s.authorize(ctx)
+	if err != nil {
+		return err
+	}
+	snapshot, err := s.resolveSnapshot(namespace, request.SnapshotId)
+	if err != nil {
+		return err
+	}
+	secrets, err := s.getSecrets(ctx, snapshot)
+	if err != nil {
+		return err
+	}
+
+	klog.V(4).Infof("GetMetadataAllocated: snapshot %s/%s, handle %s, starting offset %d", namespace, request.SnapshotId, snapshot.handle, request.StartingOffset)
+	driverStream, err := s.metadataClient.GetMetadataAllocated(ctx, &csi.GetMetadataAllocatedRequest{
+		SnapshotId:     snapshot.handle,
+		StartingOffset: request.StartingOffset,
+		MaxResults:     request.MaxResults,
+		Secrets:        secrets,
+	})
+	if err != nil {
+		return err
+	}
+	return forward(driverStream.Recv, stream.Send)
+}
+
+// GetMetadataDelta streams the blocks that changed between the
+// VolumeSnapshots named by request.BaseSnapshotId and
+// request.TargetSnapshotId.
+func (s *Server) GetMetadataDelta(request *csi.GetMetadataDeltaRequest, stream csi.SnapshotMetadata_GetMetadataDeltaServer) error {
+	ctx := stream.Context()
+	if request.BaseSnapshotId == "" || request.TargetSnapshotId == "" {
+		return status.Error(codes.InvalidArgument, "base_snapshot_id and target_snapshot_id must be names of VolumeSnapshots")
+	}
+	if request.StartingOffset < 0 {
+		return status.Error(codes.InvalidArgument, "starting_offset must not be negative")
+	}
+	namespace, err := s.authorize(ctx)
+	if err != nil {
+		return err
+	}
+	base, err := s.resolveSnapshot(namespace, request.BaseSnapshotId)
+	if err != nil {
+		return err
+	}
+	target, err := s.resolveSnapshot(namespace, request.TargetSnapshotId)
+	if err != nil {
+		return err
+	}
+	if !sameSource(base, target) {
+		return status.Errorf(codes.InvalidArgument, "VolumeSnapshots %s and %s are not snapshots of the same volume", request.BaseSnapshotId, request.TargetSnapshotId)
+	}
+	secrets, err := s.getSecrets(ctx, target)
+	if err != nil {
+		return err
+	}
+
+	klog.V(4).Infof("GetMetadataDelta: snapshots %s/%s and %s/%s, handles %s and %s, starting offset %d", namespace, request.BaseSnapshotId, namespace, request.TargetSnapshotId, base.handle, target.handle, request.StartingOffset)
+	driverStream, err := s.metadataClient.GetMetadataDelta(ctx, &csi.GetMetadataDeltaRequest{
+		BaseSnapshotId:   base.handle,
+		TargetSnapshotId: target.handle,
+		StartingOffset:   request.StartingOffset,
+		MaxResults:       request.MaxResults,
+		Secrets:          secrets,
+	})
+	if err != nil {
+		return err
+	}
+	return forward(driverStream.Recv, stream.Send)
+}
+
+// forward sends all messages received from the driver to the client. Errors
+// of the driver are returned unchanged, so that the client sees their gRPC
+// status.
+func forward[T any](recv func() (T, error), send func(T) error) error {
+	for {
+		response, err := recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := send(response); err != nil {
+			return err
+		}
+	}
+}
+
+// resolvedSnapshot is a VolumeSnapshot together with its bound content.
+type resolvedSnapshot struct {
+	snapshot *crdv1.VolumeSnapshot
+	content  *crdv1.VolumeSnapshotContent
+	handle   string
+}
+
+// resolveSnapshot looks up the snapshot handle of the named VolumeSnapshot.
+// The snapshot must be ready to use and taken by the driver of the server.
+func (s *Server) resolveSnapshot(namespace, name string) (*resolvedSnapshot, error) {
+	snapshot, err := s.snapshotLister.VolumeSnapshots(namespace).Get(name)
+	if apierrors.IsNotFound(err) {
+		return nil, status.Errorf(codes.NotFound, "VolumeSnapshot %s/%s not found", namespace, name)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get VolumeSnapshot %s/%s: %v", namespace, name, err)
+	}
+	if !utils.IsSnapshotReady(snapshot) || snapshot.Status.BoundVolumeSnapshotContentName == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "VolumeSnapshot %s/%s is not ready to use", namespace, name)
+	}
+
+	contentName := *snapshot.Status.BoundVolumeSnapshotContentName
+	content, err := s.contentLister.Get(contentName)
+	if apierrors.IsNotFound(err) {
+		return nil, status.Errorf(codes.NotFound, "VolumeSnapshotContent %s of VolumeSnapshot %s/%s not found", contentName, namespace, name)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get VolumeSnapshotContent %s: %v", contentName, err)
+	}
+	// The status of the snapshot can be changed by its owner, the content
+	// must confirm the binding.
+	ref := content.Spec.VolumeSnapshotRef
+	if ref.Namespace != namespace || ref.Name != name || ref.UID != snapshot.UID {
+		return nil, status.Errorf(codes.FailedPrecondition, "VolumeSnapshotContent %s is not bound to VolumeSnapshot %s/%s", contentName, namespace, name)
+	}
+	if content.Spec.Driver != s.driverName {
+		return nil, status.Errorf(codes.InvalidArgument, "VolumeSnapshot %s/%s was taken by driver %s, not %s", namespace, name, content.Spec.Driver, s.driverName)
+	}
+	if content.Status == nil || content.Status.SnapshotHandle == nil || *content.Status.SnapshotHandle == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "VolumeSnapshotContent %s has no snapshot handle", contentName)
+	}
+	return &resolvedSnapshot{
+		snapshot: snapshot,
+		content:  content,
+		handle:   *content.Status.SnapshotHandle,
+	}, nil
+}
+
+// sameSource returns false if the snapshots are known to be taken from
+// different volumes.
+func sameSource(base, target *resolvedSnapshot) bool {
+	basePVC, targetPVC := base.snapshot.Spec.Source.PersistentVolumeClaimName, target.snapshot.Spec.Source.PersistentVolumeClaimName
+	if basePVC != nil && targetPVC != nil && *basePVC != *targetPVC {
+		return false
+	}
+	baseVolume, targetVolume := base.content.Spec.Source.VolumeHandle, target.content.Spec.Source.VolumeHandle
+	if baseVolume != nil && targetVolume != nil && *baseVolume != *targetVolume {
+		return false
+	}
+	return true
+}
+
+// getSecrets returns the snapshotter secrets of the VolumeSnapshotClass of
+// the snapshot, if any.
+func (s *Server) getSecrets(ctx context.Context, snapshot *resolvedSnapshot) (map[string]string, error) {
+	className := snapshot.content.Spec.VolumeSnapshotClassName
+	if className == nil || *className == "" {
+		return nil, nil
+	}
+	class, err := s.classLister.Get(*className)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get VolumeSnapshotClass %s: %v", *className, err)
+	}
+	ref, err := utils.GetCredentialsReference(utils.SnapshotterSecretParams, class.Parameters, snapshot.content.Name, snapshot.snapshot)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get snapshotter secret reference of VolumeSnapshotClass %s: %v", *className, err)
+	}
+	if ref == nil {
+		return nil, nil
+	}
+	secrets, err := s.credentials.GetCredentials(ctx, ref)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get snapshotter credentials: %v", err)
+	}
+	return secrets, nil
+}
@@ -0,0 +1,419 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshotmetadata
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	crdv1alpha1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1alpha1"
+	"github.com/kubernetes-csi/external-snapshotter/client/v8/clientset/versioned/fake"
+	informers "github.com/kubernetes-csi/external-snapshotter/client/v8/informers/externalversions"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/credentials"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	core "k8s.io/client-go/testing"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	testDriver    = "hostpath.csi.k8s.io"
+	testAudience  = "snapshot-metadata"
+	testNamespace = "default"
+	validToken    = "valid-token"
+	allowedUser   = "system:serviceaccount:default:backup"
+)
+
+// fakeMetadataClient records the requests sent to the driver and returns
+// the configured responses.
+type fakeMetadataClient struct {
+	allocatedRequest *csi.GetMetadataAllocatedRequest
+	deltaRequest     *csi.GetMetadataDeltaRequest
+	responses        int
+	err              error
+}
+
+type fakeClientStream[T any] struct {
+	grpc.ClientStream
+	responses []*T
+	err       error
+}
+
+func (s *fakeClientStream[T]) Recv() (*T, error) {
+	if len(s.responses) == 0 {
+		if s.err != nil {
+			return nil, s.err
+		}
+		return nil, io.EOF
+	}
+	response := s.responses[0]
+	s.responses = s.responses[1:]
+	return response, nil
+}
+
+func (c *fakeMetadataClient) GetMetadataAllocated(ctx context.Context, in *csi.GetMetadataAllocatedRequest, opts ...grpc.CallOption) (csi.SnapshotMetadata_GetMetadataAllocatedClient, error) {
+	c.allocatedRequest = in
+	stream := &fakeClientStream[csi.GetMetadataAllocatedResponse]{err: c.err}
+	for i := 0; i < c.responses; i++ {
+		stream.responses = append(stream.responses, &csi.GetMetadataAllocatedResponse{VolumeCapacityBytes: int64(i)})
+	}
+	return stream, nil
+}
+
+func (c *fakeMetadataClient) GetMetadataDelta(ctx context.Context, in *csi.GetMetadataDeltaRequest, opts ...grpc.CallOption) (csi.SnapshotMetadata_GetMetadataDeltaClient, error) {
+	c.deltaRequest = in
+	stream := &fakeClientStream[csi.GetMetadataDeltaResponse]{err: c.err}
+	for i := 0; i < c.responses; i++ {
+		stream.responses = append(stream.responses, &csi.GetMetadataDeltaResponse{VolumeCapacityBytes: int64(i)})
+	}
+	return stream, nil
+}
+
+// fakeServerStream collects the responses sent to the client.
+type fakeServerStream[T any] struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent []*T
+}
+
+func (s *fakeServerStream[T]) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeServerStream[T]) Send(response *T) error {
+	s.sent = append(s.sent, response)
+	return nil
+}
+
+func newReadySnapshot(name, contentName, pvcName string) *crdv1.VolumeSnapshot {
+	return &crdv1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace, UID: types.UID("uid-" + name)},
+		Spec: crdv1.VolumeSnapshotSpec{
+			Source: crdv1.VolumeSnapshotSource{PersistentVolumeClaimName: &pvcName},
+		},
+		Status: &crdv1.VolumeSnapshotStatus{
+			BoundVolumeSnapshotContentName: &contentName,
+			ReadyToUse:                     ptr.To(true),
+		},
+	}
+}
+
+func newBoundContent(name, snapshotName, driver, handle, className string) *crdv1.VolumeSnapshotContent {
+	content := &crdv1.VolumeSnapshotContent{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: crdv1.VolumeSnapshotContentSpec{
+			VolumeSnapshotRef: v1.ObjectReference{Namespace: testNamespace, Name: snapshotName, UID: types.UID("uid-" + snapshotName)},
+			Driver:            driver,
+		},
+		Status: &crdv1.VolumeSnapshotContentStatus{SnapshotHandle: &handle},
+	}
+	if className != "" {
+		content.Spec.VolumeSnapshotClassName = &className
+	}
+	return content
+}
+
+func newTestServer(t *testing.T, metadataClient csi.SnapshotMetadataClient) *Server {
+	t.Helper()
+	class := &crdv1.VolumeSnapshotClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "class-secret"},
+		Driver:     testDriver,
+		Parameters: map[string]string{
+			utils.PrefixedSnapshotterSecretNameKey:      "snapshotter-secret",
+			utils.PrefixedSnapshotterSecretNamespaceKey: "kube-system",
+		},
+	}
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "snapshotter-secret", Namespace: "kube-system"},
+		Data:       map[string][]byte{"password": []byte("secret")},
+	}
+	kubeClient := kubefake.NewSimpleClientset(secret)
+	kubeClient.PrependReactor("create", "tokenreviews", func(action core.Action) (bool, runtime.Object, error) {
+		review := action.(core.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		if review.Spec.Token == validToken {
+			review.Status = authenticationv1.TokenReviewStatus{
+				Authenticated: true,
+				Audiences:     review.Spec.Audiences,
+				User:          authenticationv1.UserInfo{Username: allowedUser},
+			}
+		} else {
+			review.Status = authenticationv1.TokenReviewStatus{Error: "invalid token"}
+		}
+		return true, review, nil
+	})
+	kubeClient.PrependReactor("create", "subjectaccessreviews", func(action core.Action) (bool, runtime.Object, error) {
+		review := action.(core.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		attrs := review.Spec.ResourceAttributes
+		review.Status.Allowed = review.Spec.User == allowedUser && attrs.Namespace == testNamespace &&
+			attrs.Verb == "get" && attrs.Group == crdv1.GroupName && attrs.Resource == "volumesnapshots"
+		return true, review, nil
+	})
+
+	factory := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+	s := NewServer(kubeClient, nil, testDriver, testAudience,
+		factory.Snapshot().V1().VolumeSnapshots(),
+		factory.Snapshot().V1().VolumeSnapshotContents(),
+		factory.Snapshot().V1().VolumeSnapshotClasses(),
+		credentials.Options{})
+	s.metadataClient = metadataClient
+
+	for _, snapshot := range []*crdv1.VolumeSnapshot{
+		newReadySnapshot("snap-1", "content-1", "pvc-1"),
+		newReadySnapshot("snap-2", "content-2", "pvc-1"),
+		newReadySnapshot("snap-other-pvc", "content-other-pvc", "pvc-2"),
+		newReadySnapshot("snap-other-driver", "content-other-driver", "pvc-1"),
+		newReadySnapshot("snap-unbound", "content-unbound", "pvc-1"),
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "snap-not-ready", Namespace: testNamespace},
+			Status:     &crdv1.VolumeSnapshotStatus{ReadyToUse: ptr.To(false)},
+		},
+	} {
+		factory.Snapshot().V1().VolumeSnapshots().Informer().GetIndexer().Add(snapshot)
+	}
+	for _, content := range []*crdv1.VolumeSnapshotContent{
+		newBoundContent("content-1", "snap-1", testDriver, "handle-1", "class-secret"),
+		newBoundContent("content-2", "snap-2", testDriver, "handle-2", ""),
+		newBoundContent("content-other-pvc", "snap-other-pvc", testDriver, "handle-3", ""),
+		newBoundContent("content-other-driver", "snap-other-driver", "other.csi.k8s.io", "handle-4", ""),
+		newBoundContent("content-unbound", "snap-1", testDriver, "handle-5", ""),
+	} {
+		factory.Snapshot().V1().VolumeSnapshotContents().Informer().GetIndexer().Add(content)
+	}
+	factory.Snapshot().V1().VolumeSnapshotClasses().Informer().GetIndexer().Add(class)
+	return s
+}
+
+func newContext(token, namespace string) context.Context {
+	md := metadata.MD{}
+	if token != "" {
+		md.Set(AuthorizationMetadataKey, "Bearer "+token)
+	}
+	if namespace != "" {
+		md.Set(NamespaceMetadataKey, namespace)
+	}
+	return metadata.NewIncomingContext(context.Background(), md)
+}
+
+func TestGetMetadataAllocated(t *testing.T) {
+	tests := []struct {
+		name            string
+		ctx             context.Context
+		snapshotName    string
+		driverErr       error
+		expectCode      codes.Code
+		expectHandle    string
+		expectSecrets   map[string]string
+		expectResponses int
+	}{
+		{
+			name:            "allocated blocks of a ready snapshot",
+			ctx:             newContext(validToken, testNamespace),
+			snapshotName:    "snap-1",
+			expectCode:      codes.OK,
+			expectHandle:    "handle-1",
+			expectSecrets:   map[string]string{"password": "secret"},
+			expectResponses: 3,
+		},
+		{
+			name:         "missing token",
+			ctx:          newContext("", testNamespace),
+			snapshotName: "snap-1",
+			expectCode:   codes.Unauthenticated,
+		},
+		{
+			name:         "invalid token",
+			ctx:          newContext("invalid-token", testNamespace),
+			snapshotName: "snap-1",
+			expectCode:   codes.Unauthenticated,
+		},
+		{
+			name:         "missing namespace",
+			ctx:          newContext(validToken, ""),
+			snapshotName: "snap-1",
+			expectCode:   codes.InvalidArgument,
+		},
+		{
+			name:         "no access to namespace",
+			ctx:          newContext(validToken, "other"),
+			snapshotName: "snap-1",
+			expectCode:   codes.PermissionDenied,
+		},
+		{
+			name:         "unknown snapshot",
+			ctx:          newContext(validToken, testNamespace),
+			snapshotName: "snap-unknown",
+			expectCode:   codes.NotFound,
+		},
+		{
+			name:         "snapshot not ready",
+			ctx:          newContext(validToken, testNamespace),
+			snapshotName: "snap-not-ready",
+			expectCode:   codes.FailedPrecondition,
+		},
+		{
+			name:         "content bound to another snapshot",
+			ctx:          newContext(validToken, testNamespace),
+			snapshotName: "snap-unbound",
+			expectCode:   codes.FailedPrecondition,
+		},
+		{
+			name:         "snapshot of another driver",
+			ctx:          newContext(validToken, testNamespace),
+			snapshotName: "snap-other-driver",
+			expectCode:   codes.InvalidArgument,
+		},
+		{
+			name:            "driver error is returned after the streamed responses",
+			ctx:             newContext(validToken, testNamespace),
+			snapshotName:    "snap-2",
+			driverErr:       status.Error(codes.Aborted, "interrupted"),
+			expectCode:      codes.Aborted,
+			expectHandle:    "handle-2",
+			expectResponses: 3,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			metadataClient := &fakeMetadataClient{responses: 3, err: test.driverErr}
+			s := newTestServer(t, metadataClient)
+			stream := &fakeServerStream[csi.GetMetadataAllocatedResponse]{ctx: test.ctx}
+			err := s.GetMetadataAllocated(&csi.GetMetadataAllocatedRequest{SnapshotId: test.snapshotName, StartingOffset: 1024}, stream)
+			if code := status.Code(err); code != test.expectCode {
+				t.Fatalf("expected code %v, got %v: %v", test.expectCode, code, err)
+			}
+			if len(stream.sent) != test.expectResponses {
+				t.Errorf("expected %d responses, got %d", test.expectResponses, len(stream.sent))
+			}
+			if test.expectHandle == "" {
+				if metadataClient.allocatedRequest != nil {
+					t.Errorf("expected no request to the driver, got %+v", metadataClient.allocatedRequest)
+				}
+				return
+			}
+			request := metadataClient.allocatedRequest
+			if request == nil {
+				t.Fatalf("expected a request to the driver")
+			}
+			if request.SnapshotId != test.expectHandle || request.StartingOffset != 1024 {
+				t.Errorf("expected handle %s at offset 1024, got %s at %d", test.expectHandle, request.SnapshotId, request.StartingOffset)
+			}
+			if !reflect.DeepEqual(request.Secrets, test.expectSecrets) {
+				t.Errorf("expected secrets %v, got %v", test.expectSecrets, request.Secrets)
+			}
+		})
+	}
+}
+
+func TestGetMetadataDelta(t *testing.T) {
+	tests := []struct {
+		name         string
+		base         string
+		target       string
+		expectCode   codes.Code
+		expectBase   string
+		expectTarget string
+	}{
+		{
+			name:         "changed blocks between snapshots of the same volume",
+			base:         "snap-1",
+			target:       "snap-2",
+			expectCode:   codes.OK,
+			expectBase:   "handle-1",
+			expectTarget: "handle-2",
+		},
+		{
+			name:       "snapshots of different volumes",
+			base:       "snap-1",
+			target:     "snap-other-pvc",
+			expectCode: codes.InvalidArgument,
+		},
+		{
+			name:       "missing target",
+			base:       "snap-1",
+			expectCode: codes.InvalidArgument,
+		},
+		{
+			name:       "unknown base",
+			base:       "snap-unknown",
+			target:     "snap-2",
+			expectCode: codes.NotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			metadataClient := &fakeMetadataClient{responses: 2}
+			s := newTestServer(t, metadataClient)
+			stream := &fakeServerStream[csi.GetMetadataDeltaResponse]{ctx: newContext(validToken, testNamespace)}
+			err := s.GetMetadataDelta(&csi.GetMetadataDeltaRequest{BaseSnapshotId: test.base, TargetSnapshotId: test.target}, stream)
+			if code := status.Code(err); code != test.expectCode {
+				t.Fatalf("expected code %v, got %v: %v", test.expectCode, code, err)
+			}
+			if test.expectCode != codes.OK {
+				if metadataClient.deltaRequest != nil {
+					t.Errorf("expected no request to the driver, got %+v", metadataClient.deltaRequest)
+				}
+				return
+			}
+			request := metadataClient.deltaRequest
+			if request.BaseSnapshotId != test.expectBase || request.TargetSnapshotId != test.expectTarget {
+				t.Errorf("expected handles %s and %s, got %s and %s", test.expectBase, test.expectTarget, request.BaseSnapshotId, request.TargetSnapshotId)
+			}
+			if len(stream.sent) != 2 {
+				t.Errorf("expected 2 responses, got %d", len(stream.sent))
+			}
+		})
+	}
+}
+
+func TestRegisterService(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	ctx := context.Background()
+	spec := crdv1alpha1.SnapshotMetadataServiceSpec{
+		Address:  "snapshot-metadata.kube-system.svc:6443",
+		CACert:   []byte("ca"),
+		Audience: testAudience,
+	}
+	if err := RegisterService(ctx, client, testDriver, spec); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	spec.Address = "snapshot-metadata.storage.svc:6443"
+	if err := RegisterService(ctx, client, testDriver, spec); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	service, err := client.SnapshotV1alpha1().SnapshotMetadataServices().Get(ctx, testDriver, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get SnapshotMetadataService: %v", err)
+	}
+	if !reflect.DeepEqual(service.Spec, spec) {
+		t.Errorf("expected spec %+v, got %+v", spec, service.Spec)
+	}
+}
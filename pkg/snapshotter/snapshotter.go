@@ -39,6 +39,15 @@ type Snapshotter interface {
 
 	// GetSnapshotStatus returns if a snapshot is ready to use, creation time, and restore size.
 	GetSnapshotStatus(ctx context.Context, snapshotID string, snapshotterListCredentials map[string]string) (bool, time.Time, int64, string, error)
+
+	// ListSnapshots returns one page of the snapshots known to the driver, starting
+	// at startingToken, and the token of the next page. The token is empty on the last page.
+	ListSnapshots(ctx context.Context, startingToken string, maxEntries int32, snapshotterListCredentials map[string]string) (snapshots []*csi.Snapshot, nextToken string, err error)
+
+	// GetSnapshotStatuses returns all the snapshots known to the driver keyed by snapshot ID, paging
+	// through ListSnapshots with at most maxEntries entries per call. It returns false if the driver
+	// does not support ListSnapshots.
+	GetSnapshotStatuses(ctx context.Context, maxEntries int32, snapshotterListCredentials map[string]string) (snapshots map[string]*csi.Snapshot, listSnapshotsSupported bool, err error)
 }
 
 type snapshot struct {
@@ -138,3 +147,57 @@ func (s *snapshot) GetSnapshotStatus(ctx context.Context, snapshotID string, sna
 	creationTime := rsp.Entries[0].Snapshot.CreationTime.AsTime()
 	return rsp.Entries[0].Snapshot.ReadyToUse, creationTime, rsp.Entries[0].Snapshot.SizeBytes, rsp.Entries[0].Snapshot.GroupSnapshotId, nil
 }
+
+func (s *snapshot) ListSnapshots(ctx context.Context, startingToken string, maxEntries int32, snapshotterListCredentials map[string]string) ([]*csi.Snapshot, string, error) {
+	klog.V(5).Infof("CSI ListSnapshots: starting token [%s] max entries [%d]", startingToken, maxEntries)
+
+	client := csi.NewControllerClient(s.conn)
+	req := csi.ListSnapshotsRequest{
+		StartingToken: startingToken,
+		MaxEntries:    maxEntries,
+		Secrets:       snapshotterListCredentials,
+	}
+	rsp, err := client.ListSnapshots(ctx, &req)
+	if err != nil {
+		return nil, "", err
+	}
+
+	snapshots := make([]*csi.Snapshot, 0, len(rsp.Entries))
+	for _, entry := range rsp.Entries {
+		if entry.Snapshot != nil {
+			snapshots = append(snapshots, entry.Snapshot)
+		}
+	}
+	return snapshots, rsp.NextToken, nil
+}
+
+func (s *snapshot) GetSnapshotStatuses(ctx context.Context, maxEntries int32, snapshotterListCredentials map[string]string) (map[string]*csi.Snapshot, bool, error) {
+	klog.V(5).Infof("GetSnapshotStatuses: max entries [%d]", maxEntries)
+
+	listSnapshotsSupported, err := s.isListSnapshotsSupported(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to check if ListSnapshots is supported: %s", err.Error())
+	}
+	if !listSnapshotsSupported {
+		return nil, false, nil
+	}
+
+	snapshots := map[string]*csi.Snapshot{}
+	token := ""
+	for {
+		page, next, err := s.ListSnapshots(ctx, token, maxEntries, snapshotterListCredentials)
+		if err != nil {
+			return nil, true, err
+		}
+		for _, snapshot := range page {
+			snapshots[snapshot.SnapshotId] = snapshot
+		}
+		if next == "" {
+			return snapshots, true, nil
+		}
+		if next == token {
+			return nil, true, fmt.Errorf("ListSnapshots returned the same starting token %q twice", next)
+		}
+		token = next
+	}
+}
@@ -488,6 +488,191 @@ func TestGetSnapshotStatus(t *testing.T) {
 	}
 }
 
+func TestListSnapshots(t *testing.T) {
+	secret := map[string]string{"foo": "bar"}
+	page := &csi.ListSnapshotsResponse{
+		Entries: []*csi.ListSnapshotsResponse_Entry{
+			{Snapshot: &csi.Snapshot{SnapshotId: "snap-1", SourceVolumeId: "vol-1"}},
+			{Snapshot: &csi.Snapshot{SnapshotId: "snap-2", SourceVolumeId: "vol-2"}},
+		},
+		NextToken: "2",
+	}
+
+	tests := []struct {
+		name          string
+		startingToken string
+		maxEntries    int32
+		secrets       map[string]string
+		input         *csi.ListSnapshotsRequest
+		output        *csi.ListSnapshotsResponse
+		injectError   codes.Code
+		expectError   bool
+		expectIDs     []string
+		expectToken   string
+	}{
+		{
+			name:        "first page",
+			maxEntries:  2,
+			input:       &csi.ListSnapshotsRequest{MaxEntries: 2},
+			output:      page,
+			expectIDs:   []string{"snap-1", "snap-2"},
+			expectToken: "2",
+		},
+		{
+			name:          "last page with secrets",
+			startingToken: "2",
+			secrets:       secret,
+			input:         &csi.ListSnapshotsRequest{StartingToken: "2", Secrets: secret},
+			output:        &csi.ListSnapshotsResponse{},
+			expectIDs:     []string{},
+		},
+		{
+			name:          "gRPC error",
+			startingToken: "stale",
+			input:         &csi.ListSnapshotsRequest{StartingToken: "stale"},
+			injectError:   codes.Aborted,
+			expectError:   true,
+		},
+	}
+
+	mockController, driver, _, controllerServer, csiConn, err := createMockServer(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockController.Finish()
+	defer driver.Stop()
+	defer csiConn.Close()
+
+	for _, test := range tests {
+		var injectedErr error
+		if test.injectError != codes.OK {
+			injectedErr = status.Error(test.injectError, fmt.Sprintf("Injecting error %d", test.injectError))
+		}
+		controllerServer.EXPECT().ListSnapshots(gomock.Any(), utils.Protobuf(test.input)).Return(test.output, injectedErr).Times(1)
+
+		s := NewSnapshotter(csiConn)
+		snapshots, token, err := s.ListSnapshots(context.Background(), test.startingToken, test.maxEntries, test.secrets)
+		if test.expectError && err == nil {
+			t.Errorf("test %q: Expected error, got none", test.name)
+		}
+		if !test.expectError && err != nil {
+			t.Errorf("test %q: got error: %v", test.name, err)
+		}
+		if test.expectError {
+			continue
+		}
+		ids := []string{}
+		for _, snapshot := range snapshots {
+			ids = append(ids, snapshot.SnapshotId)
+		}
+		if !reflect.DeepEqual(ids, test.expectIDs) {
+			t.Errorf("test %q: expected snapshots %v, got %v", test.name, test.expectIDs, ids)
+		}
+		if token != test.expectToken {
+			t.Errorf("test %q: expected next token %q, got %q", test.name, test.expectToken, token)
+		}
+	}
+}
+
+func TestGetSnapshotStatuses(t *testing.T) {
+	listSnapshotsCap := &csi.ControllerServiceCapability{
+		Type: &csi.ControllerServiceCapability_Rpc{
+			Rpc: &csi.ControllerServiceCapability_RPC{
+				Type: csi.ControllerServiceCapability_RPC_LIST_SNAPSHOTS,
+			},
+		},
+	}
+	firstPage := &csi.ListSnapshotsResponse{
+		Entries: []*csi.ListSnapshotsResponse_Entry{
+			{Snapshot: &csi.Snapshot{SnapshotId: "snap-1", ReadyToUse: true}},
+			{Snapshot: &csi.Snapshot{SnapshotId: "snap-2"}},
+		},
+		NextToken: "2",
+	}
+	lastPage := &csi.ListSnapshotsResponse{
+		Entries: []*csi.ListSnapshotsResponse_Entry{
+			{Snapshot: &csi.Snapshot{SnapshotId: "snap-3", ReadyToUse: true}},
+		},
+	}
+
+	tests := []struct {
+		name                   string
+		listSnapshotsSupported bool
+		pages                  []*csi.ListSnapshotsResponse
+		injectError            codes.Code
+		expectError            bool
+		expectIDs              []string
+	}{
+		{
+			name:                   "all pages",
+			listSnapshotsSupported: true,
+			pages:                  []*csi.ListSnapshotsResponse{firstPage, lastPage},
+			expectIDs:              []string{"snap-1", "snap-2", "snap-3"},
+		},
+		{
+			name:                   "ListSnapshots not supported",
+			listSnapshotsSupported: false,
+		},
+		{
+			name:                   "gRPC error",
+			listSnapshotsSupported: true,
+			pages:                  []*csi.ListSnapshotsResponse{nil},
+			injectError:            codes.Unavailable,
+			expectError:            true,
+		},
+	}
+
+	mockController, driver, _, controllerServer, csiConn, err := createMockServer(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mockController.Finish()
+	defer driver.Stop()
+	defer csiConn.Close()
+
+	for _, test := range tests {
+		var controllerCapabilities []*csi.ControllerServiceCapability
+		if test.listSnapshotsSupported {
+			controllerCapabilities = append(controllerCapabilities, listSnapshotsCap)
+		}
+		controllerServer.EXPECT().ControllerGetCapabilities(gomock.Any(), gomock.Any()).Return(&csi.ControllerGetCapabilitiesResponse{
+			Capabilities: controllerCapabilities,
+		}, nil).Times(1)
+		token := ""
+		for _, page := range test.pages {
+			var injectedErr error
+			if test.injectError != codes.OK {
+				injectedErr = status.Error(test.injectError, fmt.Sprintf("Injecting error %d", test.injectError))
+			}
+			in := &csi.ListSnapshotsRequest{StartingToken: token, MaxEntries: 2}
+			controllerServer.EXPECT().ListSnapshots(gomock.Any(), utils.Protobuf(in)).Return(page, injectedErr).Times(1)
+			if page != nil {
+				token = page.NextToken
+			}
+		}
+
+		s := NewSnapshotter(csiConn)
+		snapshots, supported, err := s.GetSnapshotStatuses(context.Background(), 2, nil)
+		if test.expectError && err == nil {
+			t.Errorf("test %q: Expected error, got none", test.name)
+		}
+		if !test.expectError && err != nil {
+			t.Errorf("test %q: got error: %v", test.name, err)
+		}
+		if supported != test.listSnapshotsSupported {
+			t.Errorf("test %q: expected ListSnapshots support %v, got %v", test.name, test.listSnapshotsSupported, supported)
+		}
+		if len(snapshots) != len(test.expectIDs) {
+			t.Errorf("test %q: expected snapshots %v, got %v", test.name, test.expectIDs, snapshots)
+		}
+		for _, id := range test.expectIDs {
+			if _, ok := snapshots[id]; !ok {
+				t.Errorf("test %q: expected snapshot %s in %v", test.name, id, snapshots)
+			}
+		}
+	}
+}
+
 func FakeCSIVolume() *v1.PersistentVolume {
 	volume := v1.PersistentVolume{
 		ObjectMeta: metav1.ObjectMeta{
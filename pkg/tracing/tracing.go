@@ -0,0 +1,101 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing records OpenTelemetry traces of the snapshot operations.
+// The trace of a VolumeSnapshot is started once by the snapshot controller,
+// stored in an annotation on the VolumeSnapshot for its later syncs, and
+// handed over to the CSI snapshotter sidecar with an annotation on the
+// VolumeSnapshotContent, so that both binaries and the CSI driver report
+// spans of the same trace.
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// instrumentationName is the name of the tracer of the spans.
+	instrumentationName = "github.com/kubernetes-csi/external-snapshotter"
+
+	// traceParentKey is the key of the W3C trace context propagated in
+	// the annotation.
+	traceParentKey = "traceparent"
+)
+
+// propagator serializes the trace context into annotations.
+var propagator = propagation.TraceContext{}
+
+// Setup installs a tracer provider exporting the spans with OTLP over gRPC
+// and the W3C trace context propagator used by the gRPC interceptors. The
+// exporter is configured by the standard OTEL_EXPORTER_OTLP_* environment
+// variables, and the sampler by OTEL_TRACES_SAMPLER. The returned function
+// flushes the pending spans and stops the exporter.
+func Setup(ctx context.Context, serviceName string) (func(context.Context) error, error) {
+	exporter, err := otlptracegrpc.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagator)
+	return provider.Shutdown, nil
+}
+
+// Start starts a span with the tracer of the snapshotter. The tracer is
+// looked up at every call, so that spans are dropped until Setup is called.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
+
+// InjectAnnotation stores the trace context of ctx in the annotation of
+// the object. Nothing is stored if ctx has no sampled span.
+func InjectAnnotation(ctx context.Context, annotations *map[string]string, annotation string) {
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+	traceParent := carrier.Get(traceParentKey)
+	if traceParent == "" || !trace.SpanContextFromContext(ctx).IsSampled() {
+		return
+	}
+	if *annotations == nil {
+		*annotations = map[string]string{}
+	}
+	(*annotations)[annotation] = traceParent
+}
+
+// ExtractAnnotation returns a context continuing the trace stored in the
+// annotation, or ctx if the annotation is missing or invalid.
+func ExtractAnnotation(ctx context.Context, annotations map[string]string, annotation string) context.Context {
+	traceParent, ok := annotations[annotation]
+	if !ok {
+		return ctx
+	}
+	return propagator.Extract(ctx, propagation.MapCarrier{traceParentKey: traceParent})
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const testAnnotation = "snapshot.storage.kubernetes.io/traceparent"
+
+func TestAnnotationPropagation(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	otel.SetTracerProvider(provider)
+	// The default global provider cannot be restored, drop the spans of the
+	// following tests instead.
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	ctx, parent := Start(context.Background(), "parent")
+	var annotations map[string]string
+	InjectAnnotation(ctx, &annotations, testAnnotation)
+	parent.End()
+	if annotations[testAnnotation] == "" {
+		t.Fatalf("expected annotation %s to be set, got %v", testAnnotation, annotations)
+	}
+
+	_, child := Start(ExtractAnnotation(context.Background(), annotations, testAnnotation), "child")
+	child.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	if spans[1].Name != "child" || spans[1].Parent.SpanID() != spans[0].SpanContext.SpanID() || spans[1].SpanContext.TraceID() != spans[0].SpanContext.TraceID() {
+		t.Errorf("expected span child to continue the trace of span parent, got %+v", spans[1])
+	}
+	if !spans[1].Parent.IsRemote() {
+		t.Errorf("expected the parent of span child to be remote")
+	}
+}
+
+func TestInjectAnnotationWithoutSpan(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+	}{
+		{
+			name: "no span",
+			ctx:  context.Background(),
+		},
+		{
+			name: "span not sampled",
+			ctx: trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+				TraceID: trace.TraceID{1},
+				SpanID:  trace.SpanID{1},
+			})),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var annotations map[string]string
+			InjectAnnotation(test.ctx, &annotations, testAnnotation)
+			if annotations != nil {
+				t.Errorf("expected no annotation, got %v", annotations)
+			}
+		})
+	}
+}
+
+func TestExtractAnnotation(t *testing.T) {
+	tests := []struct {
+		name          string
+		annotations   map[string]string
+		expectTraceID string
+	}{
+		{
+			name:          "valid trace context",
+			annotations:   map[string]string{testAnnotation: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+			expectTraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			name:        "no annotation",
+			annotations: map[string]string{},
+		},
+		{
+			name:        "invalid trace context",
+			annotations: map[string]string{testAnnotation: "invalid"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spanContext := trace.SpanContextFromContext(ExtractAnnotation(context.Background(), test.annotations, testAnnotation))
+			if test.expectTraceID == "" {
+				if spanContext.IsValid() {
+					t.Errorf("expected no trace context, got %v", spanContext)
+				}
+				return
+			}
+			if spanContext.TraceID().String() != test.expectTraceID {
+				t.Errorf("expected trace ID %s, got %s", test.expectTraceID, spanContext.TraceID())
+			}
+		})
+	}
+}
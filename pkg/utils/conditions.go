@@ -0,0 +1,199 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	crdv1beta1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumegroupsnapshot/v1beta1"
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+)
+
+// snapshotState is the state of a (group) snapshot or of its content that
+// their conditions are derived from.
+type snapshotState struct {
+	// contentKind is the kind of the content of a (group) snapshot, and empty
+	// for contents, which have no Bound condition.
+	contentKind string
+	// boundContentName is the name of the bound content, if any.
+	boundContentName *string
+	created          bool
+	ready            bool
+	// deleted is true if the object is being deleted and its snapshot is
+	// gone from the storage system.
+	deleted bool
+	err     *crdv1.VolumeSnapshotError
+}
+
+// conditions returns the Bound, Created and Ready conditions of the state.
+func (s *snapshotState) conditions() []metav1.Condition {
+	// pending is the reason and message of the conditions of the steps that
+	// did not happen yet.
+	var pendingReason, pendingMessage string
+	switch {
+	case s.contentKind != "" && s.boundContentName == nil:
+		pendingReason, pendingMessage = crdv1.VolumeSnapshotReasonWaitingForContent, fmt.Sprintf("Waiting for the %s to be created and bound", s.contentKind)
+	case s.deleted:
+		pendingReason, pendingMessage = crdv1.VolumeSnapshotReasonDeleted, "The snapshot was deleted from the storage system"
+	case !s.created:
+		pendingReason, pendingMessage = crdv1.VolumeSnapshotReasonCreating, "The snapshot is being cut by the CSI driver"
+	default:
+		pendingReason, pendingMessage = crdv1.VolumeSnapshotReasonProcessing, "The snapshot was cut and is being processed by the storage system"
+	}
+	if s.err != nil {
+		pendingReason = crdv1.VolumeSnapshotReasonError
+		if s.err.Reason != nil && *s.err.Reason != "" {
+			pendingReason = *s.err.Reason
+		}
+		if s.err.Message != nil {
+			pendingMessage = *s.err.Message
+		}
+	}
+
+	var conditions []metav1.Condition
+	if s.contentKind != "" {
+		bound := metav1.Condition{
+			Type:    crdv1.VolumeSnapshotConditionBound,
+			Status:  metav1.ConditionFalse,
+			Reason:  crdv1.VolumeSnapshotReasonWaitingForContent,
+			Message: fmt.Sprintf("Waiting for the %s to be created and bound", s.contentKind),
+		}
+		if s.boundContentName != nil {
+			bound.Status = metav1.ConditionTrue
+			bound.Reason = crdv1.VolumeSnapshotReasonContentBound
+			bound.Message = fmt.Sprintf("Bound to %s %s", s.contentKind, *s.boundContentName)
+		}
+		conditions = append(conditions, bound)
+	}
+
+	created := metav1.Condition{
+		Type:    crdv1.VolumeSnapshotConditionCreated,
+		Status:  metav1.ConditionFalse,
+		Reason:  pendingReason,
+		Message: pendingMessage,
+	}
+	if s.created {
+		created.Status = metav1.ConditionTrue
+		created.Reason = crdv1.VolumeSnapshotReasonCreated
+		created.Message = "The snapshot was cut by the CSI driver"
+	}
+	ready := metav1.Condition{
+		Type:    crdv1.VolumeSnapshotConditionReady,
+		Status:  metav1.ConditionFalse,
+		Reason:  pendingReason,
+		Message: pendingMessage,
+	}
+	if s.ready {
+		ready.Status = metav1.ConditionTrue
+		ready.Reason = crdv1.VolumeSnapshotReasonReady
+		ready.Message = "The snapshot is ready to use"
+	}
+	return append(conditions, created, ready)
+}
+
+// setConditions sets the conditions of state in conditions, observed at
+// generation. It returns true if conditions changed.
+func setConditions(conditions *[]metav1.Condition, generation int64, state *snapshotState) bool {
+	changed := false
+	for _, condition := range state.conditions() {
+		condition.ObservedGeneration = generation
+		if meta.SetStatusCondition(conditions, condition) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// SetVolumeSnapshotConditions sets the Bound, Created and Ready conditions
+// of a VolumeSnapshot from the other fields of its status. It returns true
+// if the conditions changed.
+func SetVolumeSnapshotConditions(snapshot *crdv1.VolumeSnapshot) bool {
+	if snapshot.Status == nil {
+		return false
+	}
+	return setConditions(&snapshot.Status.Conditions, snapshot.Generation, &snapshotState{
+		contentKind:      "VolumeSnapshotContent",
+		boundContentName: snapshot.Status.BoundVolumeSnapshotContentName,
+		created:          snapshot.Status.CreationTime != nil,
+		ready:            snapshot.Status.ReadyToUse != nil && *snapshot.Status.ReadyToUse,
+		err:              snapshot.Status.Error,
+	})
+}
+
+// SetVolumeSnapshotContentConditions sets the Created and Ready conditions
+// of a VolumeSnapshotContent from the other fields of its status. It returns
+// true if the conditions changed.
+func SetVolumeSnapshotContentConditions(content *crdv1.VolumeSnapshotContent) bool {
+	if content.Status == nil {
+		return false
+	}
+	created := content.Status.CreationTime != nil
+	return setConditions(&content.Status.Conditions, content.Generation, &snapshotState{
+		created: created,
+		ready:   content.Status.ReadyToUse != nil && *content.Status.ReadyToUse,
+		deleted: !created && content.DeletionTimestamp != nil,
+		err:     content.Status.Error,
+	})
+}
+
+// SetVolumeGroupSnapshotConditions sets the Bound, Created and Ready
+// conditions of a VolumeGroupSnapshot from the other fields of its status.
+// It returns true if the conditions changed.
+func SetVolumeGroupSnapshotConditions(groupSnapshot *crdv1beta1.VolumeGroupSnapshot) bool {
+	if groupSnapshot.Status == nil {
+		return false
+	}
+	return setConditions(&groupSnapshot.Status.Conditions, groupSnapshot.Generation, &snapshotState{
+		contentKind:      "VolumeGroupSnapshotContent",
+		boundContentName: groupSnapshot.Status.BoundVolumeGroupSnapshotContentName,
+		created:          groupSnapshot.Status.CreationTime != nil,
+		ready:            groupSnapshot.Status.ReadyToUse != nil && *groupSnapshot.Status.ReadyToUse,
+		err:              groupSnapshot.Status.Error,
+	})
+}
+
+// SetVolumeGroupSnapshotContentConditions sets the Created and Ready
+// conditions of a VolumeGroupSnapshotContent from the other fields of its
+// status. It returns true if the conditions changed.
+func SetVolumeGroupSnapshotContentConditions(groupSnapshotContent *crdv1beta1.VolumeGroupSnapshotContent) bool {
+	if groupSnapshotContent.Status == nil {
+		return false
+	}
+	created := groupSnapshotContent.Status.CreationTime != nil
+	return setConditions(&groupSnapshotContent.Status.Conditions, groupSnapshotContent.Generation, &snapshotState{
+		created: created,
+		ready:   groupSnapshotContent.Status.ReadyToUse != nil && *groupSnapshotContent.Status.ReadyToUse,
+		deleted: !created && groupSnapshotContent.DeletionTimestamp != nil,
+		err:     groupSnapshotContent.Status.Error,
+	})
+}
+
+// SetDeletionBlockedCondition sets a True DeletionBlocked condition with the
+// given reason and message in conditions, observed at generation. It returns
+// true if the conditions changed.
+func SetDeletionBlockedCondition(conditions *[]metav1.Condition, generation int64, reason, message string) bool {
+	return meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               crdv1.VolumeSnapshotConditionDeletionBlocked,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
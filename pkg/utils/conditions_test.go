@@ -0,0 +1,235 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	crdv1beta1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumegroupsnapshot/v1beta1"
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+// conditionReasons returns the status and reason of the conditions by type.
+func conditionReasons(conditions []metav1.Condition) map[string]string {
+	reasons := map[string]string{}
+	for _, condition := range conditions {
+		reasons[condition.Type] = string(condition.Status) + "/" + condition.Reason
+	}
+	return reasons
+}
+
+func TestSetVolumeSnapshotConditions(t *testing.T) {
+	testcases := map[string]struct {
+		status        *crdv1.VolumeSnapshotStatus
+		expectReasons map[string]string
+	}{
+		"nil status": {
+			status:        nil,
+			expectReasons: map[string]string{},
+		},
+		"waiting for content": {
+			status: &crdv1.VolumeSnapshotStatus{},
+			expectReasons: map[string]string{
+				crdv1.VolumeSnapshotConditionBound:   "False/WaitingForContent",
+				crdv1.VolumeSnapshotConditionCreated: "False/WaitingForContent",
+				crdv1.VolumeSnapshotConditionReady:   "False/WaitingForContent",
+			},
+		},
+		"creating": {
+			status: &crdv1.VolumeSnapshotStatus{
+				BoundVolumeSnapshotContentName: ptr.To("content"),
+				ReadyToUse:                     ptr.To(false),
+			},
+			expectReasons: map[string]string{
+				crdv1.VolumeSnapshotConditionBound:   "True/ContentBound",
+				crdv1.VolumeSnapshotConditionCreated: "False/SnapshotCreating",
+				crdv1.VolumeSnapshotConditionReady:   "False/SnapshotCreating",
+			},
+		},
+		"processing": {
+			status: &crdv1.VolumeSnapshotStatus{
+				BoundVolumeSnapshotContentName: ptr.To("content"),
+				CreationTime:                   &metav1.Time{},
+				ReadyToUse:                     ptr.To(false),
+			},
+			expectReasons: map[string]string{
+				crdv1.VolumeSnapshotConditionBound:   "True/ContentBound",
+				crdv1.VolumeSnapshotConditionCreated: "True/SnapshotCreated",
+				crdv1.VolumeSnapshotConditionReady:   "False/SnapshotProcessing",
+			},
+		},
+		"ready": {
+			status: &crdv1.VolumeSnapshotStatus{
+				BoundVolumeSnapshotContentName: ptr.To("content"),
+				CreationTime:                   &metav1.Time{},
+				ReadyToUse:                     ptr.To(true),
+			},
+			expectReasons: map[string]string{
+				crdv1.VolumeSnapshotConditionBound:   "True/ContentBound",
+				crdv1.VolumeSnapshotConditionCreated: "True/SnapshotCreated",
+				crdv1.VolumeSnapshotConditionReady:   "True/SnapshotReady",
+			},
+		},
+		"error": {
+			status: &crdv1.VolumeSnapshotStatus{
+				BoundVolumeSnapshotContentName: ptr.To("content"),
+				ReadyToUse:                     ptr.To(false),
+				Error:                          NewVolumeSnapshotError("driver failure", "CreateSnapshotFailed", NewStatusError(crdv1.VolumeSnapshotErrorReasonSecretError, nil)),
+			},
+			expectReasons: map[string]string{
+				crdv1.VolumeSnapshotConditionBound:   "True/ContentBound",
+				crdv1.VolumeSnapshotConditionCreated: "False/SecretError",
+				crdv1.VolumeSnapshotConditionReady:   "False/SecretError",
+			},
+		},
+		"error without reason": {
+			status: &crdv1.VolumeSnapshotStatus{
+				Error: &crdv1.VolumeSnapshotError{Message: ptr.To("failure")},
+			},
+			expectReasons: map[string]string{
+				crdv1.VolumeSnapshotConditionBound:   "False/WaitingForContent",
+				crdv1.VolumeSnapshotConditionCreated: "False/Error",
+				crdv1.VolumeSnapshotConditionReady:   "False/Error",
+			},
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			snapshot := &crdv1.VolumeSnapshot{
+				ObjectMeta: metav1.ObjectMeta{Generation: 3},
+				Status:     tc.status,
+			}
+			changed := SetVolumeSnapshotConditions(snapshot)
+			if changed != (tc.status != nil) {
+				t.Errorf("Expected changed %v, got %v", tc.status != nil, changed)
+			}
+			if snapshot.Status == nil {
+				return
+			}
+			if reasons := conditionReasons(snapshot.Status.Conditions); !reflect.DeepEqual(reasons, tc.expectReasons) {
+				t.Errorf("Expected conditions %v, got %v", tc.expectReasons, reasons)
+			}
+			for _, condition := range snapshot.Status.Conditions {
+				if condition.ObservedGeneration != 3 {
+					t.Errorf("Expected observedGeneration 3 in condition %s, got %d", condition.Type, condition.ObservedGeneration)
+				}
+			}
+			if SetVolumeSnapshotConditions(snapshot) {
+				t.Errorf("Expected no change when setting the conditions again")
+			}
+		})
+	}
+}
+
+func TestSetVolumeSnapshotContentConditions(t *testing.T) {
+	deletionTime := metav1.Now()
+	testcases := map[string]struct {
+		content       *crdv1.VolumeSnapshotContent
+		expectReasons map[string]string
+	}{
+		"creating": {
+			content: &crdv1.VolumeSnapshotContent{
+				Status: &crdv1.VolumeSnapshotContentStatus{},
+			},
+			expectReasons: map[string]string{
+				crdv1.VolumeSnapshotConditionCreated: "False/SnapshotCreating",
+				crdv1.VolumeSnapshotConditionReady:   "False/SnapshotCreating",
+			},
+		},
+		"ready": {
+			content: &crdv1.VolumeSnapshotContent{
+				Status: &crdv1.VolumeSnapshotContentStatus{
+					SnapshotHandle: ptr.To("handle"),
+					CreationTime:   ptr.To[int64](1),
+					ReadyToUse:     ptr.To(true),
+				},
+			},
+			expectReasons: map[string]string{
+				crdv1.VolumeSnapshotConditionCreated: "True/SnapshotCreated",
+				crdv1.VolumeSnapshotConditionReady:   "True/SnapshotReady",
+			},
+		},
+		"deleted": {
+			content: &crdv1.VolumeSnapshotContent{
+				ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &deletionTime},
+				Status:     &crdv1.VolumeSnapshotContentStatus{},
+			},
+			expectReasons: map[string]string{
+				crdv1.VolumeSnapshotConditionCreated: "False/SnapshotDeleted",
+				crdv1.VolumeSnapshotConditionReady:   "False/SnapshotDeleted",
+			},
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			SetVolumeSnapshotContentConditions(tc.content)
+			if reasons := conditionReasons(tc.content.Status.Conditions); !reflect.DeepEqual(reasons, tc.expectReasons) {
+				t.Errorf("Expected conditions %v, got %v", tc.expectReasons, reasons)
+			}
+		})
+	}
+}
+
+func TestSetVolumeGroupSnapshotConditions(t *testing.T) {
+	groupSnapshot := &crdv1beta1.VolumeGroupSnapshot{
+		Status: &crdv1beta1.VolumeGroupSnapshotStatus{
+			BoundVolumeGroupSnapshotContentName: ptr.To("content"),
+			ReadyToUse:                          ptr.To(false),
+		},
+	}
+	SetVolumeGroupSnapshotConditions(groupSnapshot)
+	created := meta.FindStatusCondition(groupSnapshot.Status.Conditions, crdv1.VolumeSnapshotConditionCreated)
+	if created == nil || created.Status != metav1.ConditionFalse {
+		t.Fatalf("Expected a False Created condition, got %+v", created)
+	}
+	transitionTime := created.LastTransitionTime
+
+	// The transition time only changes with the status of the condition.
+	groupSnapshot.Status.CreationTime = &metav1.Time{}
+	created.LastTransitionTime = metav1.Time{Time: transitionTime.Add(-1)}
+	transitionTime = created.LastTransitionTime
+	if !SetVolumeGroupSnapshotConditions(groupSnapshot) {
+		t.Fatalf("Expected the conditions to change")
+	}
+	created = meta.FindStatusCondition(groupSnapshot.Status.Conditions, crdv1.VolumeSnapshotConditionCreated)
+	if created.Status != metav1.ConditionTrue || created.LastTransitionTime == transitionTime {
+		t.Errorf("Expected a True Created condition with a new transition time, got %+v", created)
+	}
+	bound := meta.FindStatusCondition(groupSnapshot.Status.Conditions, crdv1.VolumeSnapshotConditionBound)
+	if bound == nil || bound.Message != "Bound to VolumeGroupSnapshotContent content" {
+		t.Errorf("Expected a Bound condition, got %+v", bound)
+	}
+}
+
+func TestSetDeletionBlockedCondition(t *testing.T) {
+	var conditions []metav1.Condition
+	if !SetDeletionBlockedCondition(&conditions, 2, crdv1.VolumeSnapshotReasonUsedAsSource, "restoring") {
+		t.Fatalf("Expected the conditions to change")
+	}
+	if SetDeletionBlockedCondition(&conditions, 2, crdv1.VolumeSnapshotReasonUsedAsSource, "restoring") {
+		t.Errorf("Expected no change when setting the condition again")
+	}
+	if !meta.IsStatusConditionTrue(conditions, crdv1.VolumeSnapshotConditionDeletionBlocked) || conditions[0].ObservedGeneration != 2 {
+		t.Errorf("Expected a True DeletionBlocked condition observed at generation 2, got %+v", conditions)
+	}
+}
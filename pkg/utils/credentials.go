@@ -0,0 +1,240 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	crdv1beta1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumegroupsnapshot/v1beta1"
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+)
+
+// CredentialsReference identifies where the credentials passed to a CSI call
+// are read from. Exactly one of its fields is set.
+type CredentialsReference struct {
+	// Secret is a Secret referenced by name.
+	Secret *v1.SecretReference `json:"secret,omitempty"`
+	// SecretSelector selects the Secret by labels. It must match exactly one
+	// Secret.
+	SecretSelector *SecretSelector `json:"secretSelector,omitempty"`
+	// File is a directory, relative to the credentials directory of the
+	// sidecar, holding one file per credential.
+	File string `json:"file,omitempty"`
+	// Exec is the name of an exec plugin of the sidecar.
+	Exec string `json:"exec,omitempty"`
+}
+
+// SecretSelector selects a Secret by labels.
+type SecretSelector struct {
+	Namespace string `json:"namespace"`
+	Selector  string `json:"selector"`
+}
+
+// String returns the JSON encoding of the reference.
+func (r *CredentialsReference) String() string {
+	data, err := json.Marshal(r)
+	if err != nil {
+		// Not reachable, the reference only has strings.
+		return fmt.Sprintf("%#v", r)
+	}
+	return string(data)
+}
+
+// validate checks that exactly one field of the reference is set and valid.
+func (r *CredentialsReference) validate() error {
+	set := 0
+	if r.Secret != nil {
+		set++
+		if r.Secret.Name == "" || r.Secret.Namespace == "" {
+			return fmt.Errorf("secret name or namespace not specified")
+		}
+	}
+	if r.SecretSelector != nil {
+		set++
+		if err := validateSecretSelector(r.SecretSelector.Namespace, r.SecretSelector.Selector); err != nil {
+			return err
+		}
+	}
+	if r.File != "" {
+		set++
+		if err := validateCredentialsFile(r.File); err != nil {
+			return err
+		}
+	}
+	if r.Exec != "" {
+		set++
+		if err := validateCredentialsExec(r.Exec); err != nil {
+			return err
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("exactly one of secret, secretSelector, file and exec must be specified")
+	}
+	return nil
+}
+
+// ParseCredentialsReference decodes a reference encoded by String.
+func ParseCredentialsReference(value string) (*CredentialsReference, error) {
+	ref := &CredentialsReference{}
+	if err := json.Unmarshal([]byte(value), ref); err != nil {
+		return nil, fmt.Errorf("invalid credentials reference %q: %v", value, err)
+	}
+	if err := ref.validate(); err != nil {
+		return nil, fmt.Errorf("invalid credentials reference %q: %v", value, err)
+	}
+	return ref, nil
+}
+
+// GetCredentialsReference returns a reference to the credentials specified in
+// the parameters of secretParams, or nil if none is specified. Credentials
+// from a Secret referenced by name are resolved by GetSecretReference.
+//
+// The secret selector and the secret namespace support the same tokens as
+// the secret name and namespace of GetSecretReference.
+func GetCredentialsReference(secretParams secretParamsMap, snapshotClassParams map[string]string, snapContentName string, snapshot *crdv1.VolumeSnapshot) (*CredentialsReference, error) {
+	namespaceParams := map[string]string{"volumesnapshotcontent.name": snapContentName}
+	selectorParams := map[string]string{"volumesnapshotcontent.name": snapContentName}
+	if snapshot != nil {
+		namespaceParams["volumesnapshot.namespace"] = snapshot.Namespace
+		selectorParams["volumesnapshot.name"] = snapshot.Name
+		selectorParams["volumesnapshot.namespace"] = snapshot.Namespace
+	}
+	return getCredentialsReference(secretParams, snapshotClassParams, namespaceParams, selectorParams, func() (*v1.SecretReference, error) {
+		return GetSecretReference(secretParams, snapshotClassParams, snapContentName, snapshot)
+	})
+}
+
+// GetGroupSnapshotCredentialsReference is GetCredentialsReference for group
+// snapshots.
+func GetGroupSnapshotCredentialsReference(secretParams secretParamsMap, volumeGroupSnapshotClassParams map[string]string, groupSnapContentName string, volumeGroupSnapshot *crdv1beta1.VolumeGroupSnapshot) (*CredentialsReference, error) {
+	namespaceParams := map[string]string{"volumegroupsnapshotcontent.name": groupSnapContentName}
+	selectorParams := map[string]string{"volumegroupsnapshotcontent.name": groupSnapContentName}
+	if volumeGroupSnapshot != nil {
+		namespaceParams["volumegroupsnapshot.namespace"] = volumeGroupSnapshot.Namespace
+		selectorParams["volumegroupsnapshot.name"] = volumeGroupSnapshot.Name
+		selectorParams["volumegroupsnapshot.namespace"] = volumeGroupSnapshot.Namespace
+	}
+	return getCredentialsReference(secretParams, volumeGroupSnapshotClassParams, namespaceParams, selectorParams, func() (*v1.SecretReference, error) {
+		return GetGroupSnapshotSecretReference(secretParams, volumeGroupSnapshotClassParams, groupSnapContentName, volumeGroupSnapshot)
+	})
+}
+
+func getCredentialsReference(secretParams secretParamsMap, classParams map[string]string, namespaceParams, selectorParams map[string]string, getSecretReference func() (*v1.SecretReference, error)) (*CredentialsReference, error) {
+	_, hasName := classParams[secretParams.secretNameKey]
+	namespaceTemplate, hasNamespace := classParams[secretParams.secretNamespaceKey]
+	selectorTemplate, hasSelector := classParams[secretParams.secretSelectorKey]
+	file, hasFile := classParams[secretParams.credentialsFileKey]
+	plugin, hasExec := classParams[secretParams.credentialsExecKey]
+
+	set := 0
+	for _, has := range []bool{hasName, hasSelector, hasFile, hasExec} {
+		if has {
+			set++
+		}
+	}
+	if set > 1 {
+		return nil, fmt.Errorf("only one of %s, %s, %s and %s may be specified", secretParams.secretNameKey, secretParams.secretSelectorKey, secretParams.credentialsFileKey, secretParams.credentialsExecKey)
+	}
+	if (hasFile || hasExec) && hasNamespace {
+		return nil, fmt.Errorf("%s must not be specified with %s or %s", secretParams.secretNamespaceKey, secretParams.credentialsFileKey, secretParams.credentialsExecKey)
+	}
+
+	switch {
+	case hasSelector:
+		if !hasNamespace || namespaceTemplate == "" {
+			return nil, fmt.Errorf("%s requires %s", secretParams.secretSelectorKey, secretParams.secretNamespaceKey)
+		}
+		namespace, err := resolveTemplate(namespaceTemplate, namespaceParams)
+		if err != nil {
+			return nil, fmt.Errorf("error resolving value %q: %v", namespaceTemplate, err)
+		}
+		selector, err := resolveTemplate(selectorTemplate, selectorParams)
+		if err != nil {
+			return nil, fmt.Errorf("error resolving value %q: %v", selectorTemplate, err)
+		}
+		if err := validateSecretSelector(namespace, selector); err != nil {
+			return nil, err
+		}
+		return &CredentialsReference{SecretSelector: &SecretSelector{Namespace: namespace, Selector: selector}}, nil
+	case hasFile:
+		if err := validateCredentialsFile(file); err != nil {
+			return nil, err
+		}
+		return &CredentialsReference{File: file}, nil
+	case hasExec:
+		if err := validateCredentialsExec(plugin); err != nil {
+			return nil, err
+		}
+		return &CredentialsReference{Exec: plugin}, nil
+	}
+
+	ref, err := getSecretReference()
+	if err != nil || ref == nil {
+		return nil, err
+	}
+	return &CredentialsReference{Secret: ref}, nil
+}
+
+func validateSecretSelector(namespace, selector string) error {
+	if len(validation.IsDNS1123Label(namespace)) > 0 {
+		return fmt.Errorf("%q is not a valid namespace name", namespace)
+	}
+	if selector == "" {
+		return fmt.Errorf("secret selector is empty")
+	}
+	if _, err := labels.Parse(selector); err != nil {
+		return fmt.Errorf("%q is not a valid label selector: %v", selector, err)
+	}
+	return nil
+}
+
+func validateCredentialsFile(file string) error {
+	if file == "" || filepath.IsAbs(file) || filepath.Clean(file) != file || file == ".." || strings.HasPrefix(file, "../") {
+		return fmt.Errorf("%q is not a relative path within the credentials directory", file)
+	}
+	return nil
+}
+
+func validateCredentialsExec(plugin string) error {
+	if len(validation.IsDNS1123Subdomain(plugin)) > 0 {
+		return fmt.Errorf("%q is not a valid exec plugin name", plugin)
+	}
+	return nil
+}
+
+// SetCredentialsAnnotations records ref on an object: a Secret referenced by
+// name in the nameAnnotation and namespaceAnnotation annotations, other
+// references in the credentialsAnnotation annotation.
+func SetCredentialsAnnotations(obj *metav1.ObjectMeta, ref *CredentialsReference, nameAnnotation, namespaceAnnotation, credentialsAnnotation string) {
+	if ref == nil {
+		return
+	}
+	if ref.Secret != nil {
+		metav1.SetMetaDataAnnotation(obj, nameAnnotation, ref.Secret.Name)
+		metav1.SetMetaDataAnnotation(obj, namespaceAnnotation, ref.Secret.Namespace)
+		return
+	}
+	metav1.SetMetaDataAnnotation(obj, credentialsAnnotation, ref.String())
+}
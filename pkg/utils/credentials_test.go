@@ -0,0 +1,208 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetCredentialsReference(t *testing.T) {
+	snapshot := &crdv1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "snapshotname",
+			Namespace: "snapshotnamespace",
+		},
+	}
+	testcases := map[string]struct {
+		params    map[string]string
+		expectRef *CredentialsReference
+		expectErr bool
+	}{
+		"no params": {
+			params:    nil,
+			expectRef: nil,
+		},
+		"secret name": {
+			params:    map[string]string{PrefixedSnapshotterSecretNameKey: "name", PrefixedSnapshotterSecretNamespaceKey: "ns"},
+			expectRef: &CredentialsReference{Secret: &v1.SecretReference{Name: "name", Namespace: "ns"}},
+		},
+		"secret selector": {
+			params: map[string]string{
+				PrefixedSnapshotterSecretSelectorKey:  "app=csi,snapshot=${volumesnapshot.name}",
+				PrefixedSnapshotterSecretNamespaceKey: "${volumesnapshot.namespace}",
+			},
+			expectRef: &CredentialsReference{SecretSelector: &SecretSelector{Namespace: "snapshotnamespace", Selector: "app=csi,snapshot=snapshotname"}},
+		},
+		"secret selector without namespace": {
+			params:    map[string]string{PrefixedSnapshotterSecretSelectorKey: "app=csi"},
+			expectErr: true,
+		},
+		"invalid secret selector": {
+			params:    map[string]string{PrefixedSnapshotterSecretSelectorKey: "app in (", PrefixedSnapshotterSecretNamespaceKey: "ns"},
+			expectErr: true,
+		},
+		"file": {
+			params:    map[string]string{PrefixedSnapshotterCredentialsFileKey: "driver/snapshotter"},
+			expectRef: &CredentialsReference{File: "driver/snapshotter"},
+		},
+		"file outside of the credentials directory": {
+			params:    map[string]string{PrefixedSnapshotterCredentialsFileKey: "../etc"},
+			expectErr: true,
+		},
+		"absolute file": {
+			params:    map[string]string{PrefixedSnapshotterCredentialsFileKey: "/etc"},
+			expectErr: true,
+		},
+		"file with namespace": {
+			params:    map[string]string{PrefixedSnapshotterCredentialsFileKey: "snapshotter", PrefixedSnapshotterSecretNamespaceKey: "ns"},
+			expectErr: true,
+		},
+		"exec": {
+			params:    map[string]string{PrefixedSnapshotterCredentialsExecKey: "vault"},
+			expectRef: &CredentialsReference{Exec: "vault"},
+		},
+		"invalid exec": {
+			params:    map[string]string{PrefixedSnapshotterCredentialsExecKey: "../bin/sh"},
+			expectErr: true,
+		},
+		"several sources": {
+			params: map[string]string{
+				PrefixedSnapshotterSecretNameKey:      "name",
+				PrefixedSnapshotterSecretNamespaceKey: "ns",
+				PrefixedSnapshotterCredentialsExecKey: "vault",
+			},
+			expectErr: true,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			ref, err := GetCredentialsReference(SnapshotterSecretParams, tc.params, "snapcontentname", snapshot)
+			if err != nil {
+				if tc.expectErr {
+					return
+				}
+				t.Fatalf("Did not expect error but got: %v", err)
+			}
+			if tc.expectErr {
+				t.Fatalf("Expected error but got none")
+			}
+			if !reflect.DeepEqual(ref, tc.expectRef) {
+				t.Errorf("Expected %v, got %v", tc.expectRef, ref)
+			}
+		})
+	}
+}
+
+func TestParseCredentialsReference(t *testing.T) {
+	testcases := map[string]struct {
+		value     string
+		expectRef *CredentialsReference
+		expectErr bool
+	}{
+		"secret selector": {
+			value:     `{"secretSelector":{"namespace":"ns","selector":"app=csi"}}`,
+			expectRef: &CredentialsReference{SecretSelector: &SecretSelector{Namespace: "ns", Selector: "app=csi"}},
+		},
+		"file": {
+			value:     `{"file":"snapshotter"}`,
+			expectRef: &CredentialsReference{File: "snapshotter"},
+		},
+		"exec": {
+			value:     `{"exec":"vault"}`,
+			expectRef: &CredentialsReference{Exec: "vault"},
+		},
+		"empty": {
+			value:     `{}`,
+			expectErr: true,
+		},
+		"several sources": {
+			value:     `{"file":"snapshotter","exec":"vault"}`,
+			expectErr: true,
+		},
+		"invalid file": {
+			value:     `{"file":"../snapshotter"}`,
+			expectErr: true,
+		},
+		"not json": {
+			value:     "snapshotter",
+			expectErr: true,
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			ref, err := ParseCredentialsReference(tc.value)
+			if err != nil {
+				if tc.expectErr {
+					return
+				}
+				t.Fatalf("Did not expect error but got: %v", err)
+			}
+			if tc.expectErr {
+				t.Fatalf("Expected error but got none")
+			}
+			if !reflect.DeepEqual(ref, tc.expectRef) {
+				t.Errorf("Expected %v, got %v", tc.expectRef, ref)
+			}
+			// The annotation value round-trips.
+			if ref.String() != tc.value {
+				t.Errorf("Expected %s, got %s", tc.value, ref.String())
+			}
+		})
+	}
+}
+
+func TestSetCredentialsAnnotations(t *testing.T) {
+	testcases := map[string]struct {
+		ref               *CredentialsReference
+		expectAnnotations map[string]string
+	}{
+		"nil": {
+			ref:               nil,
+			expectAnnotations: nil,
+		},
+		"secret": {
+			ref: &CredentialsReference{Secret: &v1.SecretReference{Name: "name", Namespace: "ns"}},
+			expectAnnotations: map[string]string{
+				AnnDeletionSecretRefName:      "name",
+				AnnDeletionSecretRefNamespace: "ns",
+			},
+		},
+		"exec": {
+			ref: &CredentialsReference{Exec: "vault"},
+			expectAnnotations: map[string]string{
+				AnnDeletionCredentials: `{"exec":"vault"}`,
+			},
+		},
+	}
+
+	for k, tc := range testcases {
+		t.Run(k, func(t *testing.T) {
+			obj := metav1.ObjectMeta{}
+			SetCredentialsAnnotations(&obj, tc.ref, AnnDeletionSecretRefName, AnnDeletionSecretRefNamespace, AnnDeletionCredentials)
+			if !reflect.DeepEqual(obj.Annotations, tc.expectAnnotations) {
+				t.Errorf("Expected %v, got %v", tc.expectAnnotations, obj.Annotations)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"errors"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+)
+
+// StatusError is an error with the cause reported in the error status of a
+// snapshot object. It keeps the message of the error it wraps.
+type StatusError struct {
+	// Reason is the reason of the VolumeSnapshotError.
+	Reason string
+	// Code is the gRPC status code returned by the CSI driver, if any.
+	Code *codes.Code
+	// Final is true if the CSI driver returned a final error.
+	Final bool
+	// Err is the wrapped error.
+	Err error
+}
+
+func (e *StatusError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
+// NewStatusError returns err with the given reason.
+func NewStatusError(reason string, err error) error {
+	return &StatusError{Reason: reason, Err: err}
+}
+
+// WrapStatusError returns err, which reports cause, with the reason, code and
+// finality of the StatusError in the chain of cause, if any.
+func WrapStatusError(cause, err error) error {
+	var statusErr *StatusError
+	if !errors.As(cause, &statusErr) {
+		return err
+	}
+	wrapped := *statusErr
+	wrapped.Err = err
+	return &wrapped
+}
+
+// NewCSIStatusError returns err, which reports csiErr, the error of a CSI
+// call, with the gRPC status code of csiErr. err is returned as is when
+// csiErr is not a gRPC error, as the call failed before reaching the driver.
+func NewCSIStatusError(csiErr, err error) error {
+	st, ok := status.FromError(csiErr)
+	if !ok {
+		return err
+	}
+	code := st.Code()
+	return &StatusError{
+		Reason: crdv1.VolumeSnapshotErrorReasonCSIDriverError,
+		Code:   &code,
+		Final:  IsCSIFinalError(csiErr),
+		Err:    err,
+	}
+}
+
+// NewVolumeSnapshotError returns the error status reporting message. Its
+// reason, code and finality are taken from the StatusError in the chain of
+// err, if any, and the reason is defaultReason otherwise.
+func NewVolumeSnapshotError(message, defaultReason string, err error) *crdv1.VolumeSnapshotError {
+	reason := defaultReason
+	final := false
+	var code *string
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		reason = statusErr.Reason
+		final = statusErr.Final
+		if statusErr.Code != nil {
+			c := statusErr.Code.String()
+			code = &c
+		}
+	}
+	return &crdv1.VolumeSnapshotError{
+		Time: &metav1.Time{
+			Time: time.Now(),
+		},
+		Message: &message,
+		Reason:  &reason,
+		Code:    code,
+		Final:   &final,
+	}
+}
+
+// IsCSIFinalError returns true if err is a gRPC error showing that the
+// operation is not in progress on the storage system.
+func IsCSIFinalError(err error) bool {
+	// Sources:
+	// https://github.com/grpc/grpc/blob/master/doc/statuscodes.md
+	// https://github.com/container-storage-interface/spec/blob/master/spec.md
+	st, ok := status.FromError(err)
+	if !ok {
+		// This is not gRPC error. The operation must have failed before gRPC
+		// method was called, otherwise we would get gRPC error.
+		// We don't know if any previous CreateSnapshot is in progress, be on the safe side.
+		return false
+	}
+	switch st.Code() {
+	case codes.Canceled, // gRPC: Client Application cancelled the request
+		codes.DeadlineExceeded,  // gRPC: Timeout
+		codes.Unavailable,       // gRPC: Server shutting down, TCP connection broken - previous CreateSnapshot() may be still in progress.
+		codes.ResourceExhausted, // gRPC: Server temporarily out of resources - previous CreateSnapshot() may be still in progress.
+		codes.Aborted:           // CSI: Operation pending for Snapshot
+		return false
+	}
+	// All other errors mean that creating snapshot either did not
+	// even start or failed. It is for sure not in progress.
+	return true
+}
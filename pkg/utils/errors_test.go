@@ -0,0 +1,137 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+)
+
+func TestNewVolumeSnapshotError(t *testing.T) {
+	testcases := []struct {
+		name           string
+		err            error
+		expectedReason string
+		expectedCode   string
+		expectedFinal  bool
+	}{
+		{
+			name:           "nil error",
+			err:            nil,
+			expectedReason: "SnapshotContentMissing",
+		},
+		{
+			name:           "unclassified error",
+			err:            errors.New("mock error"),
+			expectedReason: "SnapshotContentMissing",
+		},
+		{
+			name:           "secret error",
+			err:            NewStatusError(crdv1.VolumeSnapshotErrorReasonSecretError, errors.New("mock error")),
+			expectedReason: crdv1.VolumeSnapshotErrorReasonSecretError,
+		},
+		{
+			name:           "final CSI error",
+			err:            NewCSIStatusError(status.Error(codes.InvalidArgument, "mock error"), errors.New("failed to take snapshot")),
+			expectedReason: crdv1.VolumeSnapshotErrorReasonCSIDriverError,
+			expectedCode:   "InvalidArgument",
+			expectedFinal:  true,
+		},
+		{
+			name:           "non-final CSI error",
+			err:            NewCSIStatusError(status.Error(codes.DeadlineExceeded, "mock error"), errors.New("failed to take snapshot")),
+			expectedReason: crdv1.VolumeSnapshotErrorReasonCSIDriverError,
+			expectedCode:   "DeadlineExceeded",
+		},
+		{
+			name:           "CSI call failed before reaching the driver",
+			err:            NewCSIStatusError(errors.New("mock error"), errors.New("failed to take snapshot")),
+			expectedReason: "SnapshotContentMissing",
+		},
+		{
+			name:           "wrapped CSI error",
+			err:            WrapStatusError(NewCSIStatusError(status.Error(codes.NotFound, "mock error"), errors.New("not found")), errors.New("failed to take snapshot")),
+			expectedReason: crdv1.VolumeSnapshotErrorReasonCSIDriverError,
+			expectedCode:   "NotFound",
+			expectedFinal:  true,
+		},
+		{
+			name:           "status error wrapped with %w",
+			err:            fmt.Errorf("failed to take snapshot: %w", NewStatusError(crdv1.VolumeSnapshotErrorReasonClassError, errors.New("mock error"))),
+			expectedReason: crdv1.VolumeSnapshotErrorReasonClassError,
+		},
+	}
+
+	for _, tc := range testcases {
+		snapshotErr := NewVolumeSnapshotError("mock message", "SnapshotContentMissing", tc.err)
+		if snapshotErr.Time == nil {
+			t.Errorf("%s: expected time to be set", tc.name)
+		}
+		if snapshotErr.Message == nil || *snapshotErr.Message != "mock message" {
+			t.Errorf("%s: expected message %q, got %v", tc.name, "mock message", snapshotErr.Message)
+		}
+		if snapshotErr.Reason == nil || *snapshotErr.Reason != tc.expectedReason {
+			t.Errorf("%s: expected reason %q, got %v", tc.name, tc.expectedReason, snapshotErr.Reason)
+		}
+		code := ""
+		if snapshotErr.Code != nil {
+			code = *snapshotErr.Code
+		}
+		if code != tc.expectedCode {
+			t.Errorf("%s: expected code %q, got %q", tc.name, tc.expectedCode, code)
+		}
+		if snapshotErr.Final == nil || *snapshotErr.Final != tc.expectedFinal {
+			t.Errorf("%s: expected final %v, got %v", tc.name, tc.expectedFinal, snapshotErr.Final)
+		}
+	}
+}
+
+func TestStatusErrorMessage(t *testing.T) {
+	err := errors.New("failed to take snapshot")
+	testcases := []struct {
+		name string
+		err  error
+	}{
+		{
+			name: "status error",
+			err:  NewStatusError(crdv1.VolumeSnapshotErrorReasonAPIServerError, err),
+		},
+		{
+			name: "CSI status error",
+			err:  NewCSIStatusError(status.Error(codes.Internal, "mock error"), err),
+		},
+		{
+			name: "wrapped status error",
+			err:  WrapStatusError(NewStatusError(crdv1.VolumeSnapshotErrorReasonSecretError, errors.New("mock error")), err),
+		},
+	}
+
+	for _, tc := range testcases {
+		if tc.err.Error() != err.Error() {
+			t.Errorf("%s: expected message %q, got %q", tc.name, err.Error(), tc.err.Error())
+		}
+		if !errors.Is(tc.err, err) {
+			t.Errorf("%s: expected %v to wrap %v", tc.name, tc.err, err)
+		}
+	}
+}
@@ -24,6 +24,19 @@ import (
 
 const CSIDriverHandleIndexName = "ByVolumeHandle"
 
+// PersistentVolumeByCSIDriverHandleIndexFunc indexes CSI persistent volumes
+// by the key returned by PersistentVolumeKeyFunc, to be registered as
+// CSIDriverHandleIndexName.
+func PersistentVolumeByCSIDriverHandleIndexFunc(obj interface{}) ([]string, error) {
+	if pv, ok := obj.(*v1.PersistentVolume); ok {
+		if key := PersistentVolumeKeyFunc(pv); key != "" {
+			return []string{key}, nil
+		}
+	}
+
+	return nil, nil
+}
+
 // PersistentVolumeKeyFunc maps a persistent volume to a string usable
 // as KeyFunc to recover it from the CSI driver name and the volume handle.
 // If the passed PV is not CSI-based, it will return the empty string
@@ -45,6 +45,9 @@ type secretParamsMap struct {
 	name               string
 	secretNameKey      string
 	secretNamespaceKey string
+	secretSelectorKey  string
+	credentialsFileKey string
+	credentialsExecKey string
 }
 
 const (
@@ -68,6 +71,32 @@ const (
 	PrefixedGroupSnapshotterGetSecretNameKey      = csiParameterPrefix + "group-snapshotter-get-secret-name"      // Prefixed name key for GetVolumeGroupSnapshot secret
 	PrefixedGroupSnapshotterGetSecretNamespaceKey = csiParameterPrefix + "group-snapshotter-get-secret-namespace" // Prefixed namespace key for GetVolumeGroupSnapshot secret
 
+	// Parameters selecting where the credentials of the CSI calls are read
+	// from, instead of a Secret referenced by name. At most one of the secret
+	// name, secret selector, credentials file and credentials exec keys may be
+	// set for a type of call. The secret selector requires the secret
+	// namespace key.
+	PrefixedSnapshotterSecretSelectorKey      = csiParameterPrefix + "snapshotter-secret-selector"       // Prefixed label selector key for CreateSnapshot/DeleteSnapshot secret
+	PrefixedSnapshotterCredentialsFileKey     = csiParameterPrefix + "snapshotter-credentials-file"      // Prefixed credentials directory key for CreateSnapshot/DeleteSnapshot
+	PrefixedSnapshotterCredentialsExecKey     = csiParameterPrefix + "snapshotter-credentials-exec"      // Prefixed exec plugin key for CreateSnapshot/DeleteSnapshot
+	PrefixedSnapshotterListSecretSelectorKey  = csiParameterPrefix + "snapshotter-list-secret-selector"  // Prefixed label selector key for ListSnapshots secret
+	PrefixedSnapshotterListCredentialsFileKey = csiParameterPrefix + "snapshotter-list-credentials-file" // Prefixed credentials directory key for ListSnapshots
+	PrefixedSnapshotterListCredentialsExecKey = csiParameterPrefix + "snapshotter-list-credentials-exec" // Prefixed exec plugin key for ListSnapshots
+
+	PrefixedGroupSnapshotterSecretSelectorKey     = csiParameterPrefix + "group-snapshotter-secret-selector"      // Prefixed label selector key for CreateGroupSnapshot/DeleteGroupSnapshot secret
+	PrefixedGroupSnapshotterCredentialsFileKey    = csiParameterPrefix + "group-snapshotter-credentials-file"     // Prefixed credentials directory key for CreateGroupSnapshot/DeleteGroupSnapshot
+	PrefixedGroupSnapshotterCredentialsExecKey    = csiParameterPrefix + "group-snapshotter-credentials-exec"     // Prefixed exec plugin key for CreateGroupSnapshot/DeleteGroupSnapshot
+	PrefixedGroupSnapshotterGetSecretSelectorKey  = csiParameterPrefix + "group-snapshotter-get-secret-selector"  // Prefixed label selector key for GetVolumeGroupSnapshot secret
+	PrefixedGroupSnapshotterGetCredentialsFileKey = csiParameterPrefix + "group-snapshotter-get-credentials-file" // Prefixed credentials directory key for GetVolumeGroupSnapshot
+	PrefixedGroupSnapshotterGetCredentialsExecKey = csiParameterPrefix + "group-snapshotter-get-credentials-exec" // Prefixed exec plugin key for GetVolumeGroupSnapshot
+
+	// Parameters prefixed with csiParameterPrefix and made of an RPC type of
+	// RPCLimitTypes followed by one of these suffixes limit the CSI calls of
+	// that type, e.g. "csi.storage.k8s.io/create-snapshot-max-inflight".
+	RPCLimitMaxInFlightSuffix = "-max-inflight" // Maximum number of concurrent calls
+	RPCLimitQPSSuffix         = "-qps"          // Rate of the token bucket of the calls
+	RPCLimitBurstSuffix       = "-burst"        // Size of the token bucket of the calls
+
 	PrefixedVolumeSnapshotNameKey        = csiParameterPrefix + "volumesnapshot/name"        // Prefixed VolumeSnapshot name key
 	PrefixedVolumeSnapshotNamespaceKey   = csiParameterPrefix + "volumesnapshot/namespace"   // Prefixed VolumeSnapshot namespace key
 	PrefixedVolumeSnapshotContentNameKey = csiParameterPrefix + "volumesnapshotcontent/name" // Prefixed VolumeSnapshotContent name key
@@ -102,6 +131,14 @@ const (
 	// backing the snapshot content.
 	AnnVolumeSnapshotBeingDeleted = "snapshot.storage.kubernetes.io/volumesnapshot-being-deleted"
 
+	// AnnVolumeSnapshotContentAbandon annotation applies to VolumeSnapshotContents.
+	// It is set by a cluster administrator, with the reason as its value, on
+	// a VolumeSnapshotContent being deleted whose CSI driver is no longer
+	// installed. The common snapshot controller then removes the content
+	// finalizer without the snapshot being deleted on the storage system,
+	// which may leak it.
+	AnnVolumeSnapshotContentAbandon = "snapshot.storage.kubernetes.io/abandon"
+
 	// AnnVolumeSnapshotBeingCreated annotation applies to VolumeSnapshotContents.
 	// If it is set, it indicates that the csi-snapshotter
 	// sidecar has sent the create snapshot request to the storage system and
@@ -136,16 +173,33 @@ const (
 	// backing the group snapshot content.
 	AnnVolumeGroupSnapshotBeingDeleted = "groupsnapshot.storage.kubernetes.io/volumegroupsnapshot-being-deleted"
 
+	// AnnTraceParent annotation applies to VolumeSnapshots and
+	// VolumeSnapshotContents. It holds the W3C trace context of the trace
+	// started by the common snapshot controller for the VolumeSnapshot,
+	// which its later syncs continue, and which the sidecar controller
+	// continues into the CSI calls of the snapshot.
+	AnnTraceParent = "snapshot.storage.kubernetes.io/traceparent"
+
 	// Annotation for secret name and namespace will be added to the content
 	// and used at snapshot content deletion time.
 	AnnDeletionSecretRefName      = "snapshot.storage.kubernetes.io/deletion-secret-name"
 	AnnDeletionSecretRefNamespace = "snapshot.storage.kubernetes.io/deletion-secret-namespace"
 
+	// Annotation for the JSON encoded CredentialsReference will be added to
+	// the content, instead of the secret name and namespace, when the
+	// credentials are not read from a Secret referenced by name.
+	AnnDeletionCredentials = "snapshot.storage.kubernetes.io/deletion-credentials"
+
 	// Annotation for secret name and namespace will be added to the group
 	// snapshot content and used at group snapshot content deletion time.
 	AnnDeletionGroupSecretRefName      = "groupsnapshot.storage.kubernetes.io/deletion-secret-name"
 	AnnDeletionGroupSecretRefNamespace = "groupsnapshot.storage.kubernetes.io/deletion-secret-namespace"
 
+	// Annotation for the JSON encoded CredentialsReference will be added to
+	// the group snapshot content, instead of the secret name and namespace,
+	// when the credentials are not read from a Secret referenced by name.
+	AnnDeletionGroupCredentials = "groupsnapshot.storage.kubernetes.io/deletion-credentials"
+
 	// VolumeGroupSnapshotHandleAnnotation is applied to VolumeSnapshotContents that are member
 	// of a VolumeGroupSnapshotContent, and indicates the handle of the latter.
 	//
@@ -156,30 +210,78 @@ const (
 	// VolumeSnapshotContentManagedByLabel is applied by the snapshot controller to the VolumeSnapshotContent object in case distributed snapshotting is enabled.
 	// The value contains the name of the node that handles the snapshot for the volume local to that node.
 	VolumeSnapshotContentManagedByLabel = "snapshot.storage.kubernetes.io/managed-by"
+
+	// VolumeSnapshotScheduleLabel is applied by the snapshot controller to the VolumeSnapshots
+	// it creates on behalf of a VolumeSnapshotSchedule. The value contains the name of the schedule.
+	VolumeSnapshotScheduleLabel = "snapshot.storage.kubernetes.io/volumesnapshotschedule-name"
+
+	// AnnVolumeSnapshotScheduledTime is applied by the snapshot controller to the VolumeSnapshots
+	// it creates on behalf of a VolumeSnapshotSchedule. The value contains the scheduled time of
+	// the run that created the snapshot in RFC 3339 format.
+	AnnVolumeSnapshotScheduledTime = "snapshot.storage.kubernetes.io/scheduled-time"
+
+	// AnnVolumeSnapshotExpiresAt can be set by users on a VolumeSnapshot to have it deleted by the
+	// snapshot controller at the given time. The value is a timestamp in RFC 3339 format.
+	AnnVolumeSnapshotExpiresAt = "snapshot.storage.kubernetes.io/expires-at"
+
+	// AnnVolumeSnapshotTTL can be set by users on a VolumeSnapshot to have it deleted by the
+	// snapshot controller once the given duration, e.g. "72h", has passed since the snapshot
+	// was cut, i.e. since status.creationTime.
+	AnnVolumeSnapshotTTL = "snapshot.storage.kubernetes.io/ttl"
+
+	// AnnVolumeSnapshotHook can be set by users on a VolumeSnapshot or a VolumeSnapshotClass
+	// to the name of a cluster scoped VolumeSnapshotHook. The snapshot
+	// controller runs the commands of the hook before and after the snapshot is taken.
+	// The annotation of the snapshot takes precedence over the one of the class.
+	AnnVolumeSnapshotHook = "snapshot.storage.kubernetes.io/hook"
+
+	// AnnVolumeSnapshotTransferredFrom is set by the snapshot controller on a VolumeSnapshotContent
+	// and on the VolumeSnapshot it is bound to when the content is transferred from another
+	// namespace. The value is the "<namespace>/<name>" key of the source VolumeSnapshot.
+	// A dynamically provisioned content with this annotation can be bound like a
+	// pre-provisioned one.
+	AnnVolumeSnapshotTransferredFrom = "snapshot.storage.kubernetes.io/transferred-from"
+
+	// AnnImportedFromVolume is set by the csi-snapshotter on the VolumeSnapshotContents and
+	// VolumeSnapshots it creates when importing existing snapshots of the storage backend.
+	// The value is the ID of the source volume of the snapshot.
+	AnnImportedFromVolume = "snapshot.storage.kubernetes.io/imported-from-volume"
 )
 
 var SnapshotterSecretParams = secretParamsMap{
 	name:               "Snapshotter",
 	secretNameKey:      PrefixedSnapshotterSecretNameKey,
 	secretNamespaceKey: PrefixedSnapshotterSecretNamespaceKey,
+	secretSelectorKey:  PrefixedSnapshotterSecretSelectorKey,
+	credentialsFileKey: PrefixedSnapshotterCredentialsFileKey,
+	credentialsExecKey: PrefixedSnapshotterCredentialsExecKey,
 }
 
 var GroupSnapshotterSecretParams = secretParamsMap{
 	name:               "GroupSnapshotter",
 	secretNameKey:      PrefixedGroupSnapshotterSecretNameKey,
 	secretNamespaceKey: PrefixedGroupSnapshotterSecretNamespaceKey,
+	secretSelectorKey:  PrefixedGroupSnapshotterSecretSelectorKey,
+	credentialsFileKey: PrefixedGroupSnapshotterCredentialsFileKey,
+	credentialsExecKey: PrefixedGroupSnapshotterCredentialsExecKey,
 }
 
 var SnapshotterListSecretParams = secretParamsMap{
 	name:               "SnapshotterList",
 	secretNameKey:      PrefixedSnapshotterListSecretNameKey,
 	secretNamespaceKey: PrefixedSnapshotterListSecretNamespaceKey,
+	secretSelectorKey:  PrefixedSnapshotterListSecretSelectorKey,
+	credentialsFileKey: PrefixedSnapshotterListCredentialsFileKey,
+	credentialsExecKey: PrefixedSnapshotterListCredentialsExecKey,
 }
 
 var GroupSnapshotterGetSecretParams = secretParamsMap{
 	name:               "GroupSnapshotterGet",
 	secretNameKey:      PrefixedGroupSnapshotterGetSecretNameKey,
 	secretNamespaceKey: PrefixedGroupSnapshotterGetSecretNamespaceKey,
+	secretSelectorKey:  PrefixedGroupSnapshotterGetSecretSelectorKey,
+	credentialsFileKey: PrefixedGroupSnapshotterGetCredentialsFileKey,
+	credentialsExecKey: PrefixedGroupSnapshotterGetCredentialsExecKey,
 }
 
 // Annotations on VolumeSnapshotContent objects entirely controlled by csi-snapshotter
@@ -506,6 +608,13 @@ func NeedToAddGroupSnapshotContentFinalizer(groupSnapshotContent *crdv1beta1.Vol
 	return groupSnapshotContent.ObjectMeta.DeletionTimestamp == nil && !slices.Contains(groupSnapshotContent.ObjectMeta.Finalizers, VolumeGroupSnapshotContentFinalizer)
 }
 
+// IsContentAbandoned checks if a volume snapshot content deletionTimestamp
+// is set, the abandon annotation is on the content and its finalizer needs
+// to be removed.
+func IsContentAbandoned(content *crdv1.VolumeSnapshotContent) bool {
+	return content.ObjectMeta.DeletionTimestamp != nil && metav1.HasAnnotation(content.ObjectMeta, AnnVolumeSnapshotContentAbandon) && slices.Contains(content.ObjectMeta.Finalizers, VolumeSnapshotContentFinalizer)
+}
+
 // IsSnapshotDeletionCandidate checks if a volume snapshot deletionTimestamp
 // is set and any finalizer is on the snapshot.
 func IsSnapshotDeletionCandidate(snapshot *crdv1.VolumeSnapshot) bool {
@@ -558,7 +667,22 @@ func RemovePrefixedParameters(param map[string]string) (map[string]string, error
 			case PrefixedGroupSnapshotterGetSecretNamespaceKey:
 			case PrefixedGroupSnapshotterSecretNameKey:
 			case PrefixedGroupSnapshotterSecretNamespaceKey:
+			case PrefixedSnapshotterSecretSelectorKey:
+			case PrefixedSnapshotterCredentialsFileKey:
+			case PrefixedSnapshotterCredentialsExecKey:
+			case PrefixedSnapshotterListSecretSelectorKey:
+			case PrefixedSnapshotterListCredentialsFileKey:
+			case PrefixedSnapshotterListCredentialsExecKey:
+			case PrefixedGroupSnapshotterSecretSelectorKey:
+			case PrefixedGroupSnapshotterCredentialsFileKey:
+			case PrefixedGroupSnapshotterCredentialsExecKey:
+			case PrefixedGroupSnapshotterGetSecretSelectorKey:
+			case PrefixedGroupSnapshotterGetCredentialsFileKey:
+			case PrefixedGroupSnapshotterGetCredentialsExecKey:
 			default:
+				if _, _, ok := ParseRPCLimitKey(strings.TrimPrefix(k, csiParameterPrefix)); ok {
+					continue
+				}
 				return map[string]string{}, fmt.Errorf("found unknown parameter key \"%s\" with reserved namespace %s", k, csiParameterPrefix)
 			}
 		} else {
@@ -570,6 +694,54 @@ func RemovePrefixedParameters(param map[string]string) (map[string]string, error
 	return newParam, nil
 }
 
+// The types of the CSI calls that can be limited.
+const (
+	RPCCreateSnapshot      = "create-snapshot"
+	RPCDeleteSnapshot      = "delete-snapshot"
+	RPCListSnapshots       = "list-snapshots"
+	RPCCreateGroupSnapshot = "create-group-snapshot"
+	RPCDeleteGroupSnapshot = "delete-group-snapshot"
+	RPCGetGroupSnapshot    = "get-group-snapshot"
+)
+
+// RPCLimitTypes are the types of the CSI calls that can be limited.
+var RPCLimitTypes = []string{
+	RPCCreateSnapshot,
+	RPCDeleteSnapshot,
+	RPCListSnapshots,
+	RPCCreateGroupSnapshot,
+	RPCDeleteGroupSnapshot,
+	RPCGetGroupSnapshot,
+}
+
+// ParseRPCLimitKey splits the key of an RPC limit, without csiParameterPrefix,
+// into its RPC type and its suffix. ok is false if the key is not an RPC limit.
+func ParseRPCLimitKey(key string) (rpcType, suffix string, ok bool) {
+	for _, suffix := range []string{RPCLimitMaxInFlightSuffix, RPCLimitQPSSuffix, RPCLimitBurstSuffix} {
+		rpcType, found := strings.CutSuffix(key, suffix)
+		if found && slices.Contains(RPCLimitTypes, rpcType) {
+			return rpcType, suffix, true
+		}
+	}
+	return "", "", false
+}
+
+// GetRPCLimitParameters returns the RPC limits of the parameters of a class,
+// without csiParameterPrefix.
+func GetRPCLimitParameters(param map[string]string) map[string]string {
+	limits := map[string]string{}
+	for k, v := range param {
+		key, found := strings.CutPrefix(k, csiParameterPrefix)
+		if !found {
+			continue
+		}
+		if _, _, ok := ParseRPCLimitKey(key); ok {
+			limits[key] = v
+		}
+	}
+	return limits
+}
+
 // Stateless functions
 func GetSnapshotStatusForLogging(snapshot *crdv1.VolumeSnapshot) string {
 	snapshotContentName := ""
@@ -618,6 +790,40 @@ func IsSnapshotReady(snapshot *crdv1.VolumeSnapshot) bool {
 	return true
 }
 
+// GetSnapshotExpiryTime returns the time at which the snapshot expires according to its
+// AnnVolumeSnapshotExpiresAt and AnnVolumeSnapshotTTL annotations. If both are set, the
+// earlier time is returned. The boolean is false if the snapshot does not expire, or if
+// only a TTL is set and the snapshot has not been cut yet.
+func GetSnapshotExpiryTime(snapshot *crdv1.VolumeSnapshot) (time.Time, bool, error) {
+	var expiry time.Time
+	found := false
+	if value, ok := snapshot.Annotations[AnnVolumeSnapshotExpiresAt]; ok {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid %s annotation %q: %v", AnnVolumeSnapshotExpiresAt, value, err)
+		}
+		expiry = t
+		found = true
+	}
+	if value, ok := snapshot.Annotations[AnnVolumeSnapshotTTL]; ok {
+		ttl, err := time.ParseDuration(value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid %s annotation %q: %v", AnnVolumeSnapshotTTL, value, err)
+		}
+		if ttl < 0 {
+			return time.Time{}, false, fmt.Errorf("invalid %s annotation %q: duration must not be negative", AnnVolumeSnapshotTTL, value)
+		}
+		if IsSnapshotCreated(snapshot) {
+			t := snapshot.Status.CreationTime.Add(ttl)
+			if !found || t.Before(expiry) {
+				expiry = t
+			}
+			found = true
+		}
+	}
+	return expiry, found, nil
+}
+
 // IsSnapshotCreated indicates that the snapshot has been cut on a storage system
 func IsSnapshotCreated(snapshot *crdv1.VolumeSnapshot) bool {
 	return snapshot.Status != nil && snapshot.Status.CreationTime != nil
@@ -19,6 +19,7 @@ package utils
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
 	v1 "k8s.io/api/core/v1"
@@ -169,11 +170,37 @@ func TestRemovePrefixedCSIParams(t *testing.T) {
 			},
 			expectedParams: map[string]string{},
 		},
+		{
+			name: "credential sources",
+			params: map[string]string{
+				PrefixedSnapshotterSecretSelectorKey:       "app=csi",
+				PrefixedSnapshotterCredentialsFileKey:      "snapshotter",
+				PrefixedSnapshotterListCredentialsExecKey:  "vault",
+				PrefixedGroupSnapshotterCredentialsFileKey: "group",
+				"bim": "baz",
+			},
+			expectedParams: map[string]string{"bim": "baz"},
+		},
+		{
+			name: "rpc limits",
+			params: map[string]string{
+				csiParameterPrefix + "create-snapshot-max-inflight": "2",
+				csiParameterPrefix + "list-snapshots-qps":           "0.5",
+				csiParameterPrefix + "get-group-snapshot-burst":     "3",
+				"bim": "baz",
+			},
+			expectedParams: map[string]string{"bim": "baz"},
+		},
 		{
 			name:      "unknown prefixed var",
 			params:    map[string]string{csiParameterPrefix + "bim": "baz"},
 			expectErr: true,
 		},
+		{
+			name:      "unknown rpc limit",
+			params:    map[string]string{csiParameterPrefix + "get-snapshot-qps": "1"},
+			expectErr: true,
+		},
 		{
 			name:           "empty",
 			params:         map[string]string{},
@@ -201,6 +228,22 @@ func TestRemovePrefixedCSIParams(t *testing.T) {
 	}
 }
 
+func TestGetRPCLimitParameters(t *testing.T) {
+	params := map[string]string{
+		csiParameterPrefix + "create-snapshot-max-inflight": "2",
+		csiParameterPrefix + "delete-group-snapshot-qps":    "1",
+		PrefixedSnapshotterSecretNameKey:                    "secret",
+		"create-snapshot-qps":                               "5",
+	}
+	expected := map[string]string{
+		"create-snapshot-max-inflight": "2",
+		"delete-group-snapshot-qps":    "1",
+	}
+	if limits := GetRPCLimitParameters(params); !reflect.DeepEqual(limits, expected) {
+		t.Errorf("Expected %v, got %v", expected, limits)
+	}
+}
+
 func TestIsVolumeSnapshotClassDefaultAnnotation(t *testing.T) {
 	testcases := []struct {
 		name       string
@@ -242,6 +285,76 @@ func TestIsVolumeSnapshotClassDefaultAnnotation(t *testing.T) {
 	}
 }
 
+func TestGetSnapshotExpiryTime(t *testing.T) {
+	creationTime := metav1.NewTime(time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC))
+	testcases := []struct {
+		name           string
+		annotations    map[string]string
+		status         *crdv1.VolumeSnapshotStatus
+		expectedExpiry string
+		expectedFound  bool
+		expectErr      bool
+	}{
+		{
+			name:          "no annotations",
+			status:        &crdv1.VolumeSnapshotStatus{CreationTime: &creationTime},
+			expectedFound: false,
+		},
+		{
+			name:           "absolute expiry time",
+			annotations:    map[string]string{AnnVolumeSnapshotExpiresAt: "2024-01-02T00:00:00Z"},
+			expectedExpiry: "2024-01-02T00:00:00Z",
+			expectedFound:  true,
+		},
+		{
+			name:           "ttl after creation time",
+			annotations:    map[string]string{AnnVolumeSnapshotTTL: "36h"},
+			status:         &crdv1.VolumeSnapshotStatus{CreationTime: &creationTime},
+			expectedExpiry: "2024-01-02T22:00:00Z",
+			expectedFound:  true,
+		},
+		{
+			name:          "ttl of a snapshot that has not been cut yet",
+			annotations:   map[string]string{AnnVolumeSnapshotTTL: "36h"},
+			expectedFound: false,
+		},
+		{
+			name:           "earlier of both annotations",
+			annotations:    map[string]string{AnnVolumeSnapshotTTL: "1h", AnnVolumeSnapshotExpiresAt: "2024-01-02T00:00:00Z"},
+			status:         &crdv1.VolumeSnapshotStatus{CreationTime: &creationTime},
+			expectedExpiry: "2024-01-01T11:00:00Z",
+			expectedFound:  true,
+		},
+		{
+			name:        "invalid timestamp",
+			annotations: map[string]string{AnnVolumeSnapshotExpiresAt: "tomorrow"},
+			expectErr:   true,
+		},
+		{
+			name:        "negative ttl",
+			annotations: map[string]string{AnnVolumeSnapshotTTL: "-1h"},
+			expectErr:   true,
+		},
+	}
+	for _, tc := range testcases {
+		t.Logf("test: %s", tc.name)
+		snapshot := &crdv1.VolumeSnapshot{
+			ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations},
+			Status:     tc.status,
+		}
+		expiry, found, err := GetSnapshotExpiryTime(snapshot)
+		if tc.expectErr != (err != nil) {
+			t.Fatalf("expected error %v, got %v", tc.expectErr, err)
+		}
+		if found != tc.expectedFound {
+			t.Fatalf("expected found %v, got %v", tc.expectedFound, found)
+		}
+		if found && expiry.UTC().Format(time.RFC3339) != tc.expectedExpiry {
+			t.Fatalf("expected expiry %s, got %s", tc.expectedExpiry, expiry.UTC().Format(time.RFC3339))
+		}
+	}
+}
+
 func TestIsVolumeGroupSnapshotClassDefaultAnnotation(t *testing.T) {
 	testcases := []struct {
 		name       string
@@ -0,0 +1,99 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	informers "github.com/kubernetes-csi/external-snapshotter/client/v8/informers/externalversions"
+	groupsnapshotlisters "github.com/kubernetes-csi/external-snapshotter/client/v8/listers/volumegroupsnapshot/v1beta1"
+	snapshotlisters "github.com/kubernetes-csi/external-snapshotter/client/v8/listers/volumesnapshot/v1"
+
+	"k8s.io/client-go/tools/cache"
+)
+
+// volumeSnapshotRefIndexName indexes VolumeSnapshotContents by the
+// namespace/name of the VolumeSnapshot they reference.
+const volumeSnapshotRefIndexName = "volumeSnapshotRef"
+
+// admitter validates snapshot objects against the other snapshot objects in
+// the cluster. Checks that only involve a single object are done by the CEL
+// rules of the CRDs.
+type admitter struct {
+	snapshotLister      snapshotlisters.VolumeSnapshotLister
+	contentLister       snapshotlisters.VolumeSnapshotContentLister
+	contentIndexer      cache.Indexer
+	snapshotClassLister snapshotlisters.VolumeSnapshotClassLister
+	groupSnapshotLister groupsnapshotlisters.VolumeGroupSnapshotLister
+	groupContentLister  groupsnapshotlisters.VolumeGroupSnapshotContentLister
+	groupClassLister    groupsnapshotlisters.VolumeGroupSnapshotClassLister
+	informersSynced     []cache.InformerSynced
+
+	enableVolumeGroupSnapshots bool
+}
+
+// NewAdmitter returns a new *admitter that reads objects from the informers
+// of the given factory. The factory must be started afterwards.
+func NewAdmitter(factory informers.SharedInformerFactory, enableVolumeGroupSnapshots bool) (*admitter, error) {
+	contentInformer := factory.Snapshot().V1().VolumeSnapshotContents().Informer()
+	if err := contentInformer.AddIndexers(cache.Indexers{volumeSnapshotRefIndexName: volumeSnapshotRefIndexFunc}); err != nil {
+		return nil, fmt.Errorf("failed to add VolumeSnapshotContent index: %v", err)
+	}
+
+	a := &admitter{
+		snapshotLister:             factory.Snapshot().V1().VolumeSnapshots().Lister(),
+		contentLister:              factory.Snapshot().V1().VolumeSnapshotContents().Lister(),
+		contentIndexer:             contentInformer.GetIndexer(),
+		snapshotClassLister:        factory.Snapshot().V1().VolumeSnapshotClasses().Lister(),
+		enableVolumeGroupSnapshots: enableVolumeGroupSnapshots,
+	}
+	a.informersSynced = []cache.InformerSynced{
+		factory.Snapshot().V1().VolumeSnapshots().Informer().HasSynced,
+		contentInformer.HasSynced,
+		factory.Snapshot().V1().VolumeSnapshotClasses().Informer().HasSynced,
+	}
+	if enableVolumeGroupSnapshots {
+		a.groupSnapshotLister = factory.Groupsnapshot().V1beta1().VolumeGroupSnapshots().Lister()
+		a.groupContentLister = factory.Groupsnapshot().V1beta1().VolumeGroupSnapshotContents().Lister()
+		a.groupClassLister = factory.Groupsnapshot().V1beta1().VolumeGroupSnapshotClasses().Lister()
+		a.informersSynced = append(a.informersSynced,
+			factory.Groupsnapshot().V1beta1().VolumeGroupSnapshots().Informer().HasSynced,
+			factory.Groupsnapshot().V1beta1().VolumeGroupSnapshotContents().Informer().HasSynced,
+			factory.Groupsnapshot().V1beta1().VolumeGroupSnapshotClasses().Informer().HasSynced,
+		)
+	}
+	return a, nil
+}
+
+// WaitForCacheSync waits until the informers used by the admitter are
+// synced. It returns false if stopCh was closed before.
+func (a *admitter) WaitForCacheSync(stopCh <-chan struct{}) bool {
+	return cache.WaitForCacheSync(stopCh, a.informersSynced...)
+}
+
+func volumeSnapshotRefIndexFunc(obj interface{}) ([]string, error) {
+	content, ok := obj.(*crdv1.VolumeSnapshotContent)
+	if !ok {
+		return nil, nil
+	}
+	ref := content.Spec.VolumeSnapshotRef
+	if ref.Name == "" {
+		return nil, nil
+	}
+	return []string{ref.Namespace + "/" + ref.Name}, nil
+}
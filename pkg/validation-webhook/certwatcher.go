@@ -0,0 +1,120 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	klog "k8s.io/klog/v2"
+)
+
+// DefaultCertReloadInterval is how often the certificate files are checked
+// for changes.
+const DefaultCertReloadInterval = 10 * time.Second
+
+// CertWatcher serves the webhook certificate and reloads it when the
+// certificate or key file changes, e.g. when the Secret they are mounted from
+// is rotated. The files are polled, which also works for the symlink swaps
+// done by the kubelet when it updates a mounted Secret.
+type CertWatcher struct {
+	certFile string
+	keyFile  string
+	interval time.Duration
+
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	certData []byte
+	keyData  []byte
+}
+
+// NewCertWatcher returns a CertWatcher for the given files. The key pair is
+// loaded immediately, so that an invalid configuration is reported on
+// startup.
+func NewCertWatcher(certFile, keyFile string, interval time.Duration) (*CertWatcher, error) {
+	w := &CertWatcher{
+		certFile: certFile,
+		keyFile:  keyFile,
+		interval: interval,
+	}
+	if _, err := w.reload(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Start checks the certificate files for changes until ctx is done.
+func (w *CertWatcher) Start(ctx context.Context) {
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		changed, err := w.reload()
+		if err != nil {
+			klog.Errorf("failed to reload webhook certificate, keeping the current one: %v", err)
+			return
+		}
+		if changed {
+			klog.Infof("Reloaded webhook certificate from %s and %s", w.certFile, w.keyFile)
+		}
+	}, w.interval)
+}
+
+// GetCertificate returns the current certificate. It is meant to be used as
+// tls.Config.GetCertificate.
+func (w *CertWatcher) GetCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.cert, nil
+}
+
+// reload loads the key pair if either file differs from the loaded one. It
+// returns true if the certificate was replaced.
+func (w *CertWatcher) reload() (bool, error) {
+	certData, err := os.ReadFile(w.certFile)
+	if err != nil {
+		return false, fmt.Errorf("failed to read certificate file %s: %v", w.certFile, err)
+	}
+	keyData, err := os.ReadFile(w.keyFile)
+	if err != nil {
+		return false, fmt.Errorf("failed to read key file %s: %v", w.keyFile, err)
+	}
+
+	w.mu.RLock()
+	unchanged := bytes.Equal(certData, w.certData) && bytes.Equal(keyData, w.keyData)
+	w.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	cert, err := tls.X509KeyPair(certData, keyData)
+	if err != nil {
+		// The files may be updated one after the other, the next poll
+		// picks up the complete pair.
+		return false, fmt.Errorf("failed to load key pair: %v", err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.cert = &cert
+	w.certData = certData
+	w.keyData = keyData
+	return true, nil
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeCert writes a self-signed certificate for the given common name and
+// its key to the given files.
+func writeCert(t *testing.T, certFile, keyFile, commonName string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatalf("failed to write certificate: %v", err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600); err != nil {
+		t.Fatalf("failed to write key: %v", err)
+	}
+}
+
+func commonName(t *testing.T, w *CertWatcher) string {
+	t.Helper()
+	cert, err := w.GetCertificate(nil)
+	if err != nil {
+		t.Fatalf("failed to get certificate: %v", err)
+	}
+	parsed, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+	return parsed.Subject.CommonName
+}
+
+func TestCertWatcherReload(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	writeCert(t, certFile, keyFile, "first")
+
+	w, err := NewCertWatcher(certFile, keyFile, time.Second)
+	if err != nil {
+		t.Fatalf("failed to create cert watcher: %v", err)
+	}
+	if name := commonName(t, w); name != "first" {
+		t.Errorf("expected certificate %q, got %q", "first", name)
+	}
+
+	changed, err := w.reload()
+	if err != nil || changed {
+		t.Errorf("expected unchanged certificate, got changed=%v, err=%v", changed, err)
+	}
+
+	writeCert(t, certFile, keyFile, "second")
+	changed, err = w.reload()
+	if err != nil || !changed {
+		t.Errorf("expected reloaded certificate, got changed=%v, err=%v", changed, err)
+	}
+	if name := commonName(t, w); name != "second" {
+		t.Errorf("expected certificate %q, got %q", "second", name)
+	}
+
+	// A broken key pair keeps the current certificate.
+	if err := os.WriteFile(keyFile, []byte("invalid"), 0600); err != nil {
+		t.Fatalf("failed to write key: %v", err)
+	}
+	if _, err := w.reload(); err == nil {
+		t.Errorf("expected error for invalid key")
+	}
+	if name := commonName(t, w); name != "second" {
+		t.Errorf("expected certificate %q, got %q", "second", name)
+	}
+}
+
+func TestNewCertWatcherMissingFile(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := NewCertWatcher(filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), time.Second); err == nil {
+		t.Errorf("expected error for missing files")
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+
+	crdv1beta1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumegroupsnapshot/v1beta1"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	klog "k8s.io/klog/v2"
+)
+
+var (
+	// GroupSnapshotClassV1beta1GVR is GroupVersionResource for v1beta1 VolumeGroupSnapshotClasses
+	GroupSnapshotClassV1beta1GVR = metav1.GroupVersionResource{Group: crdv1beta1.GroupName, Version: "v1beta1", Resource: "volumegroupsnapshotclasses"}
+)
+
+// admitGroupSnapshotResource validates requests for
+// groupsnapshot.storage.k8s.io resources.
+func (a *admitter) admitGroupSnapshotResource(request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	if request.Operation != admissionv1.Create && request.Operation != admissionv1.Update {
+		return allow()
+	}
+
+	switch request.Resource {
+	case GroupSnapshotClassV1beta1GVR:
+		class, oldClass := &crdv1beta1.VolumeGroupSnapshotClass{}, &crdv1beta1.VolumeGroupSnapshotClass{}
+		if err := decodeObjects(request, class, oldClass); err != nil {
+			return toError(err)
+		}
+		return a.admitGroupSnapshotClass(request.Operation, class, oldClass)
+	default:
+		klog.V(4).Infof("ignoring request for unsupported resource %v", request.Resource)
+		return allow()
+	}
+}
+
+// admitGroupSnapshotClass rejects a second default VolumeGroupSnapshotClass
+// for a driver, and a change of the driver of a class that is in use.
+func (a *admitter) admitGroupSnapshotClass(operation admissionv1.Operation, class, oldClass *crdv1beta1.VolumeGroupSnapshotClass) *admissionv1.AdmissionResponse {
+	driverChanged := operation == admissionv1.Update && class.Driver != oldClass.Driver
+	if driverChanged {
+		inUse, err := a.isGroupSnapshotClassInUse(class.Name)
+		if err != nil {
+			return toError(err)
+		}
+		if inUse != "" {
+			return deny("driver of VolumeGroupSnapshotClass %s cannot be changed from %s to %s because it is used by %s", class.Name, oldClass.Driver, class.Driver, inUse)
+		}
+	}
+
+	becameDefault := utils.IsVolumeGroupSnapshotClassDefaultAnnotation(class.ObjectMeta) &&
+		(operation == admissionv1.Create || !utils.IsVolumeGroupSnapshotClassDefaultAnnotation(oldClass.ObjectMeta) || driverChanged)
+	if !becameDefault {
+		return allow()
+	}
+	classes, err := a.groupClassLister.List(labels.Everything())
+	if err != nil {
+		return toError(fmt.Errorf("failed to list VolumeGroupSnapshotClasses: %v", err))
+	}
+	for _, other := range classes {
+		if other.Name != class.Name && other.Driver == class.Driver && utils.IsVolumeGroupSnapshotClassDefaultAnnotation(other.ObjectMeta) {
+			return deny("default VolumeGroupSnapshotClass %s already exists for driver %s", other.Name, class.Driver)
+		}
+	}
+	return allow()
+}
+
+// isGroupSnapshotClassInUse returns a description of an object that uses the
+// VolumeGroupSnapshotClass, or an empty string if it is not in use.
+func (a *admitter) isGroupSnapshotClassInUse(className string) (string, error) {
+	contents, err := a.groupContentLister.List(labels.Everything())
+	if err != nil {
+		return "", fmt.Errorf("failed to list VolumeGroupSnapshotContents: %v", err)
+	}
+	for _, content := range contents {
+		if content.Spec.VolumeGroupSnapshotClassName != nil && *content.Spec.VolumeGroupSnapshotClassName == className {
+			return "VolumeGroupSnapshotContent " + content.Name, nil
+		}
+	}
+	groupSnapshots, err := a.groupSnapshotLister.List(labels.Everything())
+	if err != nil {
+		return "", fmt.Errorf("failed to list VolumeGroupSnapshots: %v", err)
+	}
+	for _, groupSnapshot := range groupSnapshots {
+		if groupSnapshot.Spec.VolumeGroupSnapshotClassName != nil && *groupSnapshot.Spec.VolumeGroupSnapshotClassName == className {
+			return "VolumeGroupSnapshot " + utils.GroupSnapshotKey(groupSnapshot), nil
+		}
+	}
+	return "", nil
+}
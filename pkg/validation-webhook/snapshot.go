@@ -0,0 +1,182 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	klog "k8s.io/klog/v2"
+)
+
+var (
+	// SnapshotClassV1GVR is GroupVersionResource for v1 VolumeSnapshotClasses
+	SnapshotClassV1GVR = metav1.GroupVersionResource{Group: crdv1.GroupName, Version: "v1", Resource: "volumesnapshotclasses"}
+	// SnapshotContentV1GVR is GroupVersionResource for v1 VolumeSnapshotContents
+	SnapshotContentV1GVR = metav1.GroupVersionResource{Group: crdv1.GroupName, Version: "v1", Resource: "volumesnapshotcontents"}
+)
+
+// admitSnapshotResource validates requests for snapshot.storage.k8s.io
+// resources.
+func (a *admitter) admitSnapshotResource(request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	if request.Operation != admissionv1.Create && request.Operation != admissionv1.Update {
+		return allow()
+	}
+
+	switch request.Resource {
+	case SnapshotClassV1GVR:
+		class, oldClass := &crdv1.VolumeSnapshotClass{}, &crdv1.VolumeSnapshotClass{}
+		if err := decodeObjects(request, class, oldClass); err != nil {
+			return toError(err)
+		}
+		return a.admitSnapshotClass(request.Operation, class, oldClass)
+	case SnapshotContentV1GVR:
+		content, oldContent := &crdv1.VolumeSnapshotContent{}, &crdv1.VolumeSnapshotContent{}
+		if err := decodeObjects(request, content, oldContent); err != nil {
+			return toError(err)
+		}
+		return a.admitSnapshotContent(request.Operation, content, oldContent)
+	default:
+		klog.V(4).Infof("ignoring request for unsupported resource %v", request.Resource)
+		return allow()
+	}
+}
+
+// decodeObjects decodes the object of a request and, for updates, the old
+// object.
+func decodeObjects(request *admissionv1.AdmissionRequest, obj, oldObj interface{}) error {
+	if err := json.Unmarshal(request.Object.Raw, obj); err != nil {
+		return fmt.Errorf("failed to decode %s: %v", request.Resource.Resource, err)
+	}
+	if request.Operation == admissionv1.Update {
+		if err := json.Unmarshal(request.OldObject.Raw, oldObj); err != nil {
+			return fmt.Errorf("failed to decode old %s: %v", request.Resource.Resource, err)
+		}
+	}
+	return nil
+}
+
+// admitSnapshotClass rejects a second default VolumeSnapshotClass for a
+// driver, and a change of the driver of a class that is in use.
+func (a *admitter) admitSnapshotClass(operation admissionv1.Operation, class, oldClass *crdv1.VolumeSnapshotClass) *admissionv1.AdmissionResponse {
+	driverChanged := operation == admissionv1.Update && class.Driver != oldClass.Driver
+	if driverChanged {
+		inUse, err := a.isSnapshotClassInUse(class.Name)
+		if err != nil {
+			return toError(err)
+		}
+		if inUse != "" {
+			return deny("driver of VolumeSnapshotClass %s cannot be changed from %s to %s because it is used by %s", class.Name, oldClass.Driver, class.Driver, inUse)
+		}
+	}
+
+	// Only check the default annotation when it is added or the driver
+	// changes, so that objects created before the webhook was deployed can
+	// still be updated.
+	becameDefault := utils.IsVolumeSnapshotClassDefaultAnnotation(class.ObjectMeta) &&
+		(operation == admissionv1.Create || !utils.IsVolumeSnapshotClassDefaultAnnotation(oldClass.ObjectMeta) || driverChanged)
+	if !becameDefault {
+		return allow()
+	}
+	classes, err := a.snapshotClassLister.List(labels.Everything())
+	if err != nil {
+		return toError(fmt.Errorf("failed to list VolumeSnapshotClasses: %v", err))
+	}
+	for _, other := range classes {
+		if other.Name != class.Name && other.Driver == class.Driver && utils.IsVolumeSnapshotClassDefaultAnnotation(other.ObjectMeta) {
+			return deny("default VolumeSnapshotClass %s already exists for driver %s", other.Name, class.Driver)
+		}
+	}
+	return allow()
+}
+
+// isSnapshotClassInUse returns a description of an object that uses the
+// VolumeSnapshotClass, or an empty string if it is not in use.
+func (a *admitter) isSnapshotClassInUse(className string) (string, error) {
+	contents, err := a.contentLister.List(labels.Everything())
+	if err != nil {
+		return "", fmt.Errorf("failed to list VolumeSnapshotContents: %v", err)
+	}
+	for _, content := range contents {
+		if content.Spec.VolumeSnapshotClassName != nil && *content.Spec.VolumeSnapshotClassName == className {
+			return "VolumeSnapshotContent " + content.Name, nil
+		}
+	}
+	snapshots, err := a.snapshotLister.List(labels.Everything())
+	if err != nil {
+		return "", fmt.Errorf("failed to list VolumeSnapshots: %v", err)
+	}
+	for _, snapshot := range snapshots {
+		if snapshot.Spec.VolumeSnapshotClassName != nil && *snapshot.Spec.VolumeSnapshotClassName == className {
+			return "VolumeSnapshot " + utils.SnapshotKey(snapshot), nil
+		}
+	}
+	return "", nil
+}
+
+// admitSnapshotContent rejects a VolumeSnapshotContent that references a
+// VolumeSnapshot that is already bound to, or referenced by, another content.
+func (a *admitter) admitSnapshotContent(operation admissionv1.Operation, content, oldContent *crdv1.VolumeSnapshotContent) *admissionv1.AdmissionResponse {
+	ref := content.Spec.VolumeSnapshotRef
+	if operation == admissionv1.Update {
+		oldRef := oldContent.Spec.VolumeSnapshotRef
+		if ref.Namespace == oldRef.Namespace && ref.Name == oldRef.Name {
+			// The snapshot controller sets the UID of the reference
+			// when it binds a pre-provisioned content.
+			return allow()
+		}
+	}
+	if ref.Name == "" || ref.Namespace == "" {
+		return allow()
+	}
+	snapshotKey := ref.Namespace + "/" + ref.Name
+
+	snapshot, err := a.snapshotLister.VolumeSnapshots(ref.Namespace).Get(ref.Name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return toError(fmt.Errorf("failed to get VolumeSnapshot %s: %v", snapshotKey, err))
+	}
+	if err == nil && (ref.UID == "" || ref.UID == snapshot.UID) && snapshot.Status != nil &&
+		snapshot.Status.BoundVolumeSnapshotContentName != nil && *snapshot.Status.BoundVolumeSnapshotContentName != content.Name {
+		return deny("VolumeSnapshot %s is already bound to VolumeSnapshotContent %s", snapshotKey, *snapshot.Status.BoundVolumeSnapshotContentName)
+	}
+
+	others, err := a.contentIndexer.ByIndex(volumeSnapshotRefIndexName, snapshotKey)
+	if err != nil {
+		return toError(fmt.Errorf("failed to list VolumeSnapshotContents of VolumeSnapshot %s: %v", snapshotKey, err))
+	}
+	for _, obj := range others {
+		other, ok := obj.(*crdv1.VolumeSnapshotContent)
+		if !ok || other.Name == content.Name || other.DeletionTimestamp != nil {
+			continue
+		}
+		// A content that references a previous snapshot with the same
+		// name is not bound to this one.
+		otherUID := other.Spec.VolumeSnapshotRef.UID
+		if ref.UID != "" && otherUID != "" && ref.UID != otherUID {
+			continue
+		}
+		return deny("VolumeSnapshot %s is already referenced by VolumeSnapshotContent %s", snapshotKey, other.Name)
+	}
+	return allow()
+}
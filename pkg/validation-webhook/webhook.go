@@ -0,0 +1,175 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// VolumeSnapshotPath is the path at which requests for the
+	// snapshot.storage.k8s.io resources are served.
+	VolumeSnapshotPath = "/volumesnapshot"
+	// VolumeGroupSnapshotPath is the path at which requests for the
+	// groupsnapshot.storage.k8s.io resources are served.
+	VolumeGroupSnapshotPath = "/volumegroupsnapshot"
+
+	// maxRequestSize limits the size of the AdmissionReviews read by the
+	// server. The API server sends at most 3MiB objects, twice for updates.
+	maxRequestSize = 7 * 1024 * 1024
+)
+
+// admitFunc validates a single admission request.
+type admitFunc func(request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse
+
+// NewServeMux returns the handler for all paths served by the webhook.
+func NewServeMux(a *admitter) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc(VolumeSnapshotPath, func(w http.ResponseWriter, r *http.Request) {
+		serve(w, r, a.admitSnapshotResource)
+	})
+	if a.enableVolumeGroupSnapshots {
+		mux.HandleFunc(VolumeGroupSnapshotPath, func(w http.ResponseWriter, r *http.Request) {
+			serve(w, r, a.admitGroupSnapshotResource)
+		})
+	}
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	return mux
+}
+
+// StartServer serves the webhook over TLS on the given port until ctx is
+// done.
+func StartServer(ctx context.Context, port int, certWatcher *CertWatcher, a *admitter) error {
+	srv := &http.Server{
+		Addr:    fmt.Sprintf(":%d", port),
+		Handler: NewServeMux(a),
+		TLSConfig: &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: certWatcher.GetCertificate,
+		},
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go certWatcher.Start(ctx)
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			klog.Errorf("failed to shut down webhook server: %v", err)
+		}
+	}()
+
+	klog.Infof("Starting webhook server on port %d", port)
+	// The certificate is provided by the TLSConfig.
+	if err := srv.ListenAndServeTLS("", ""); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// serve handles an AdmissionReview request and writes the review with the
+// response returned by admit.
+func serve(w http.ResponseWriter, r *http.Request, admit admitFunc) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	if contentType := r.Header.Get("Content-Type"); contentType != "application/json" {
+		http.Error(w, fmt.Sprintf("unsupported content type %q, expected application/json", contentType), http.StatusUnsupportedMediaType)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestSize))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	review := admissionv1.AdmissionReview{}
+	if err := json.Unmarshal(body, &review); err != nil {
+		http.Error(w, fmt.Sprintf("failed to decode AdmissionReview: %v", err), http.StatusBadRequest)
+		return
+	}
+	if review.Request == nil {
+		http.Error(w, "AdmissionReview does not contain a request", http.StatusBadRequest)
+		return
+	}
+	klog.V(5).Infof("handling %s of %s %s/%s", review.Request.Operation, review.Request.Resource.Resource, review.Request.Namespace, review.Request.Name)
+
+	response := admit(review.Request)
+	response.UID = review.Request.UID
+	responseReview := admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: admissionv1.SchemeGroupVersion.String(),
+			Kind:       "AdmissionReview",
+		},
+		Response: response,
+	}
+	data, err := json.Marshal(responseReview)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to encode AdmissionReview: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(data); err != nil {
+		klog.Errorf("failed to write AdmissionReview response: %v", err)
+	}
+}
+
+// allow returns a response that admits the request.
+func allow() *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{Allowed: true}
+}
+
+// deny returns a response that rejects the request with the given message.
+func deny(format string, args ...interface{}) *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{
+		Allowed: false,
+		Result: &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Reason:  metav1.StatusReasonForbidden,
+			Code:    http.StatusForbidden,
+			Message: fmt.Sprintf(format, args...),
+		},
+	}
+}
+
+// toError returns a response that rejects the request because it could not
+// be validated.
+func toError(err error) *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{
+		Allowed: false,
+		Result: &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Reason:  metav1.StatusReasonInternalError,
+			Code:    http.StatusInternalServerError,
+			Message: err.Error(),
+		},
+	}
+}
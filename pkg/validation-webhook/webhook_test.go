@@ -0,0 +1,366 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	crdv1beta1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumegroupsnapshot/v1beta1"
+	crdv1 "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
+	"github.com/kubernetes-csi/external-snapshotter/client/v8/clientset/versioned/fake"
+	informers "github.com/kubernetes-csi/external-snapshotter/client/v8/informers/externalversions"
+	"github.com/kubernetes-csi/external-snapshotter/v8/pkg/utils"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	testDriver    = "hostpath.csi.k8s.io"
+	testNamespace = "default"
+)
+
+type webhookTest struct {
+	name    string
+	path    string
+	fixture string
+	// Objects that exist in the cluster.
+	classes        []*crdv1.VolumeSnapshotClass
+	contents       []*crdv1.VolumeSnapshotContent
+	snapshots      []*crdv1.VolumeSnapshot
+	groupClasses   []*crdv1beta1.VolumeGroupSnapshotClass
+	groupContents  []*crdv1beta1.VolumeGroupSnapshotContent
+	groupSnapshots []*crdv1beta1.VolumeGroupSnapshot
+	// Expected response.
+	expectAllowed bool
+	expectMessage string
+}
+
+func newClass(name, driver string, isDefault bool) *crdv1.VolumeSnapshotClass {
+	class := &crdv1.VolumeSnapshotClass{
+		ObjectMeta:     metav1.ObjectMeta{Name: name},
+		Driver:         driver,
+		DeletionPolicy: crdv1.VolumeSnapshotContentDelete,
+	}
+	if isDefault {
+		class.Annotations = map[string]string{utils.IsDefaultSnapshotClassAnnotation: "true"}
+	}
+	return class
+}
+
+func newContent(name, className, snapshotName string, snapshotUID types.UID) *crdv1.VolumeSnapshotContent {
+	content := &crdv1.VolumeSnapshotContent{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: crdv1.VolumeSnapshotContentSpec{
+			VolumeSnapshotRef: v1.ObjectReference{
+				Namespace: testNamespace,
+				Name:      snapshotName,
+				UID:       snapshotUID,
+			},
+			DeletionPolicy: crdv1.VolumeSnapshotContentRetain,
+			Driver:         testDriver,
+		},
+	}
+	if className != "" {
+		content.Spec.VolumeSnapshotClassName = &className
+	}
+	return content
+}
+
+func newSnapshot(name string, uid types.UID, className, boundContentName string) *crdv1.VolumeSnapshot {
+	snapshot := &crdv1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace, UID: uid},
+	}
+	if className != "" {
+		snapshot.Spec.VolumeSnapshotClassName = &className
+	}
+	if boundContentName != "" {
+		snapshot.Status = &crdv1.VolumeSnapshotStatus{BoundVolumeSnapshotContentName: &boundContentName}
+	}
+	return snapshot
+}
+
+func newGroupClass(name, driver string, isDefault bool) *crdv1beta1.VolumeGroupSnapshotClass {
+	class := &crdv1beta1.VolumeGroupSnapshotClass{
+		ObjectMeta:     metav1.ObjectMeta{Name: name},
+		Driver:         driver,
+		DeletionPolicy: crdv1.VolumeSnapshotContentDelete,
+	}
+	if isDefault {
+		class.Annotations = map[string]string{utils.IsDefaultGroupSnapshotClassAnnotation: "true"}
+	}
+	return class
+}
+
+func newGroupContent(name, className string) *crdv1beta1.VolumeGroupSnapshotContent {
+	return &crdv1beta1.VolumeGroupSnapshotContent{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: crdv1beta1.VolumeGroupSnapshotContentSpec{
+			VolumeGroupSnapshotClassName: &className,
+			Driver:                       testDriver,
+		},
+	}
+}
+
+func newGroupSnapshot(name, className string) *crdv1beta1.VolumeGroupSnapshot {
+	return &crdv1beta1.VolumeGroupSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		Spec: crdv1beta1.VolumeGroupSnapshotSpec{
+			VolumeGroupSnapshotClassName: &className,
+		},
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	tests := []webhookTest{
+		{
+			name:          "4-1 - first default snapshot class of a driver is allowed",
+			path:          VolumeSnapshotPath,
+			fixture:       "snapshotclass-create-default.json",
+			classes:       []*crdv1.VolumeSnapshotClass{newClass("class-1", testDriver, false), newClass("class-3", "other.csi.k8s.io", true)},
+			expectAllowed: true,
+		},
+		{
+			name:          "4-2 - second default snapshot class of a driver is denied",
+			path:          VolumeSnapshotPath,
+			fixture:       "snapshotclass-create-default.json",
+			classes:       []*crdv1.VolumeSnapshotClass{newClass("class-1", testDriver, true)},
+			expectAllowed: false,
+			expectMessage: "default VolumeSnapshotClass class-1 already exists for driver hostpath.csi.k8s.io",
+		},
+		{
+			name:          "4-3 - driver of an unused snapshot class can be changed",
+			path:          VolumeSnapshotPath,
+			fixture:       "snapshotclass-update-driver.json",
+			classes:       []*crdv1.VolumeSnapshotClass{newClass("class-1", testDriver, false)},
+			contents:      []*crdv1.VolumeSnapshotContent{newContent("content-1", "class-2", "snap-1", "")},
+			snapshots:     []*crdv1.VolumeSnapshot{newSnapshot("snap-1", "uid-1", "class-2", "content-1")},
+			expectAllowed: true,
+		},
+		{
+			name:          "4-4 - driver of a snapshot class used by a content cannot be changed",
+			path:          VolumeSnapshotPath,
+			fixture:       "snapshotclass-update-driver.json",
+			classes:       []*crdv1.VolumeSnapshotClass{newClass("class-1", testDriver, false)},
+			contents:      []*crdv1.VolumeSnapshotContent{newContent("content-1", "class-1", "snap-1", "")},
+			expectAllowed: false,
+			expectMessage: "because it is used by VolumeSnapshotContent content-1",
+		},
+		{
+			name:          "4-5 - driver of a snapshot class used by a snapshot cannot be changed",
+			path:          VolumeSnapshotPath,
+			fixture:       "snapshotclass-update-driver.json",
+			classes:       []*crdv1.VolumeSnapshotClass{newClass("class-1", testDriver, false)},
+			snapshots:     []*crdv1.VolumeSnapshot{newSnapshot("snap-1", "uid-1", "class-1", "")},
+			expectAllowed: false,
+			expectMessage: "because it is used by VolumeSnapshot default/snap-1",
+		},
+		{
+			name:          "4-6 - content for an unbound snapshot is allowed",
+			path:          VolumeSnapshotPath,
+			fixture:       "snapshotcontent-create.json",
+			snapshots:     []*crdv1.VolumeSnapshot{newSnapshot("snap-1", "uid-1", "", "")},
+			expectAllowed: true,
+		},
+		{
+			name:          "4-7 - content for a snapshot bound to another content is denied",
+			path:          VolumeSnapshotPath,
+			fixture:       "snapshotcontent-create.json",
+			snapshots:     []*crdv1.VolumeSnapshot{newSnapshot("snap-1", "uid-1", "", "content-1")},
+			expectAllowed: false,
+			expectMessage: "VolumeSnapshot default/snap-1 is already bound to VolumeSnapshotContent content-1",
+		},
+		{
+			name:          "4-8 - content for a snapshot referenced by another content is denied",
+			path:          VolumeSnapshotPath,
+			fixture:       "snapshotcontent-create.json",
+			contents:      []*crdv1.VolumeSnapshotContent{newContent("content-1", "", "snap-1", "uid-1")},
+			expectAllowed: false,
+			expectMessage: "VolumeSnapshot default/snap-1 is already referenced by VolumeSnapshotContent content-1",
+		},
+		{
+			name:    "4-9 - content referencing the same snapshot name as a deleted content is allowed",
+			path:    VolumeSnapshotPath,
+			fixture: "snapshotcontent-create.json",
+			contents: func() []*crdv1.VolumeSnapshotContent {
+				content := newContent("content-1", "", "snap-1", "uid-1")
+				content.DeletionTimestamp = &metav1.Time{}
+				return []*crdv1.VolumeSnapshotContent{content}
+			}(),
+			expectAllowed: true,
+		},
+		{
+			name:          "4-10 - delete is always allowed",
+			path:          VolumeSnapshotPath,
+			fixture:       "snapshotcontent-delete.json",
+			contents:      []*crdv1.VolumeSnapshotContent{newContent("content-1", "", "snap-1", "uid-1")},
+			snapshots:     []*crdv1.VolumeSnapshot{newSnapshot("snap-1", "uid-1", "", "content-1")},
+			expectAllowed: true,
+		},
+		{
+			name:          "4-11 - first default group snapshot class of a driver is allowed",
+			path:          VolumeGroupSnapshotPath,
+			fixture:       "groupsnapshotclass-create-default.json",
+			groupClasses:  []*crdv1beta1.VolumeGroupSnapshotClass{newGroupClass("group-class-1", testDriver, false)},
+			expectAllowed: true,
+		},
+		{
+			name:          "4-12 - second default group snapshot class of a driver is denied",
+			path:          VolumeGroupSnapshotPath,
+			fixture:       "groupsnapshotclass-create-default.json",
+			groupClasses:  []*crdv1beta1.VolumeGroupSnapshotClass{newGroupClass("group-class-1", testDriver, true)},
+			expectAllowed: false,
+			expectMessage: "default VolumeGroupSnapshotClass group-class-1 already exists for driver hostpath.csi.k8s.io",
+		},
+		{
+			name:          "4-13 - driver of a group snapshot class used by a group content cannot be changed",
+			path:          VolumeGroupSnapshotPath,
+			fixture:       "groupsnapshotclass-update-driver.json",
+			groupClasses:  []*crdv1beta1.VolumeGroupSnapshotClass{newGroupClass("group-class-1", testDriver, false)},
+			groupContents: []*crdv1beta1.VolumeGroupSnapshotContent{newGroupContent("group-content-1", "group-class-1")},
+			expectAllowed: false,
+			expectMessage: "because it is used by VolumeGroupSnapshotContent group-content-1",
+		},
+		{
+			name:           "4-14 - driver of a group snapshot class used by a group snapshot cannot be changed",
+			path:           VolumeGroupSnapshotPath,
+			fixture:        "groupsnapshotclass-update-driver.json",
+			groupClasses:   []*crdv1beta1.VolumeGroupSnapshotClass{newGroupClass("group-class-1", testDriver, false)},
+			groupSnapshots: []*crdv1beta1.VolumeGroupSnapshot{newGroupSnapshot("group-snap-1", "group-class-1")},
+			expectAllowed:  false,
+			expectMessage:  "because it is used by VolumeGroupSnapshot default/group-snap-1",
+		},
+		{
+			name:          "4-15 - driver of an unused group snapshot class can be changed",
+			path:          VolumeGroupSnapshotPath,
+			fixture:       "groupsnapshotclass-update-driver.json",
+			groupClasses:  []*crdv1beta1.VolumeGroupSnapshotClass{newGroupClass("group-class-1", testDriver, false)},
+			groupContents: []*crdv1beta1.VolumeGroupSnapshotContent{newGroupContent("group-content-1", "group-class-2")},
+			expectAllowed: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			runWebhookTest(t, test)
+		})
+	}
+}
+
+func runWebhookTest(t *testing.T, test webhookTest) {
+	factory := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+	a, err := NewAdmitter(factory, true)
+	if err != nil {
+		t.Fatalf("failed to create admitter: %v", err)
+	}
+	for _, class := range test.classes {
+		factory.Snapshot().V1().VolumeSnapshotClasses().Informer().GetIndexer().Add(class)
+	}
+	for _, content := range test.contents {
+		factory.Snapshot().V1().VolumeSnapshotContents().Informer().GetIndexer().Add(content)
+	}
+	for _, snapshot := range test.snapshots {
+		factory.Snapshot().V1().VolumeSnapshots().Informer().GetIndexer().Add(snapshot)
+	}
+	for _, class := range test.groupClasses {
+		factory.Groupsnapshot().V1beta1().VolumeGroupSnapshotClasses().Informer().GetIndexer().Add(class)
+	}
+	for _, content := range test.groupContents {
+		factory.Groupsnapshot().V1beta1().VolumeGroupSnapshotContents().Informer().GetIndexer().Add(content)
+	}
+	for _, groupSnapshot := range test.groupSnapshots {
+		factory.Groupsnapshot().V1beta1().VolumeGroupSnapshots().Informer().GetIndexer().Add(groupSnapshot)
+	}
+
+	body, err := os.ReadFile(filepath.Join("testdata", test.fixture))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	request := httptest.NewRequest(http.MethodPost, test.path, bytes.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	NewServeMux(a).ServeHTTP(recorder, request)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, recorder.Code, recorder.Body.String())
+	}
+	requestReview := admissionv1.AdmissionReview{}
+	if err := json.Unmarshal(body, &requestReview); err != nil {
+		t.Fatalf("failed to decode fixture: %v", err)
+	}
+	review := admissionv1.AdmissionReview{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &review); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if review.Response == nil {
+		t.Fatalf("response does not contain an AdmissionResponse")
+	}
+	if review.Response.UID != requestReview.Request.UID {
+		t.Errorf("expected UID %q, got %q", requestReview.Request.UID, review.Response.UID)
+	}
+	if review.Response.Allowed != test.expectAllowed {
+		t.Errorf("expected allowed=%v, got %v: %+v", test.expectAllowed, review.Response.Allowed, review.Response.Result)
+	}
+	if test.expectMessage != "" {
+		if review.Response.Result == nil || !strings.Contains(review.Response.Result.Message, test.expectMessage) {
+			t.Errorf("expected message containing %q, got %+v", test.expectMessage, review.Response.Result)
+		}
+	}
+}
+
+func TestServeRejectsInvalidRequests(t *testing.T) {
+	a, err := NewAdmitter(informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0), false)
+	if err != nil {
+		t.Fatalf("failed to create admitter: %v", err)
+	}
+	mux := NewServeMux(a)
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		contentType  string
+		body         string
+		expectStatus int
+	}{
+		{"GET is not allowed", http.MethodGet, VolumeSnapshotPath, "application/json", "", http.StatusMethodNotAllowed},
+		{"wrong content type", http.MethodPost, VolumeSnapshotPath, "text/plain", "{}", http.StatusUnsupportedMediaType},
+		{"invalid JSON", http.MethodPost, VolumeSnapshotPath, "application/json", "{", http.StatusBadRequest},
+		{"missing request", http.MethodPost, VolumeSnapshotPath, "application/json", "{}", http.StatusBadRequest},
+		{"group snapshots disabled", http.MethodPost, VolumeGroupSnapshotPath, "application/json", "{}", http.StatusNotFound},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest(test.method, test.path, strings.NewReader(test.body))
+			request.Header.Set("Content-Type", test.contentType)
+			recorder := httptest.NewRecorder()
+			mux.ServeHTTP(recorder, request)
+			if recorder.Code != test.expectStatus {
+				t.Errorf("expected status %d, got %d", test.expectStatus, recorder.Code)
+			}
+		})
+	}
+}